@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/kris-nova/logger"
 	"github.com/spf13/cobra"
@@ -10,6 +11,7 @@ import (
 	"github.com/weaveworks/eksctl/pkg/ctl/register"
 
 	"github.com/weaveworks/eksctl/pkg/actions/anywhere"
+	"github.com/weaveworks/eksctl/pkg/ctl/apply"
 	"github.com/weaveworks/eksctl/pkg/ctl/associate"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/ctl/completion"
@@ -19,12 +21,16 @@ import (
 	"github.com/weaveworks/eksctl/pkg/ctl/drain"
 	"github.com/weaveworks/eksctl/pkg/ctl/enable"
 	"github.com/weaveworks/eksctl/pkg/ctl/get"
+	"github.com/weaveworks/eksctl/pkg/ctl/replace"
 	"github.com/weaveworks/eksctl/pkg/ctl/scale"
 	"github.com/weaveworks/eksctl/pkg/ctl/set"
 	"github.com/weaveworks/eksctl/pkg/ctl/unset"
 	"github.com/weaveworks/eksctl/pkg/ctl/update"
 	"github.com/weaveworks/eksctl/pkg/ctl/upgrade"
 	"github.com/weaveworks/eksctl/pkg/ctl/utils"
+	"github.com/weaveworks/eksctl/pkg/utils/progress"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
 )
 
 func addCommands(rootCmd *cobra.Command, flagGrouping *cmdutils.FlagGrouping) {
@@ -38,6 +44,7 @@ func addCommands(rootCmd *cobra.Command, flagGrouping *cmdutils.FlagGrouping) {
 	rootCmd.AddCommand(set.Command(flagGrouping))
 	rootCmd.AddCommand(unset.Command(flagGrouping))
 	rootCmd.AddCommand(scale.Command(flagGrouping))
+	rootCmd.AddCommand(replace.Command(flagGrouping))
 	rootCmd.AddCommand(drain.Command(flagGrouping))
 	rootCmd.AddCommand(enable.Command(flagGrouping))
 	rootCmd.AddCommand(register.Command(flagGrouping))
@@ -47,6 +54,7 @@ func addCommands(rootCmd *cobra.Command, flagGrouping *cmdutils.FlagGrouping) {
 	//Ensures "eksctl --help" presents eksctl anywhere as a command, but adds no subcommands since we invoke the binary.
 	rootCmd.AddCommand(cmdutils.NewVerbCmd("anywhere", "EKS anywhere", ""))
 
+	cmdutils.AddResourceCmd(flagGrouping, rootCmd, apply.Command)
 	cmdutils.AddResourceCmd(flagGrouping, rootCmd, infoCmd)
 	cmdutils.AddResourceCmd(flagGrouping, rootCmd, versionCmd)
 }
@@ -87,18 +95,52 @@ func main() {
 
 	loggerLevel := rootCmd.PersistentFlags().IntP("verbose", "v", 3, "set log level, use 0 to silence, 4 for debugging and 5 for debugging with AWS debug logging")
 	colorValue := rootCmd.PersistentFlags().StringP("color", "C", "true", "toggle colorized logs (valid options: true, false, fabulous)")
+	eventsJSON := rootCmd.PersistentFlags().String("events-json", "", "write machine-readable progress events as JSON lines to the given file, or to stdout with '-' (logs are then written to stderr)")
+
+	taskRetryTimeout := rootCmd.PersistentFlags().Duration("task-retry-timeout", tasks.DefaultRetryTimeout, "maximum time spent retrying a task that failed with a transient AWS or Kubernetes error, use 0 to disable retries")
 
 	cobra.OnInitialize(func() {
 		initLogger(*loggerLevel, *colorValue)
+		initProgressEvents(*eventsJSON)
+		initTaskRetries(*taskRetryTimeout)
 	})
 
 	rootCmd.SetUsageFunc(flagGrouping.Usage)
 
-	if err := rootCmd.Execute(); err != nil {
+	err = rootCmd.Execute()
+	progress.EmitResult(err)
+	if closeErr := progress.Close(); closeErr != nil {
+		logger.Warning("closing events file: %v", closeErr)
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+func initProgressEvents(path string) {
+	if path == "" {
+		return
+	}
+	if path == "-" {
+		logger.Writer = os.Stderr
+	}
+	if err := progress.Open(path); err != nil {
+		logger.Critical(err.Error())
 		os.Exit(1)
 	}
 }
 
+func initTaskRetries(timeout time.Duration) {
+	if timeout < 0 {
+		logger.Critical("--task-retry-timeout must not be negative")
+		os.Exit(1)
+	}
+	tasks.SetDefaultRetryPolicy(&retry.TimingOutExponentialBackoff{
+		Timeout:  timeout,
+		TimeUnit: time.Second,
+	})
+}
+
 func checkCommand(rootCmd *cobra.Command) {
 	for _, cmd := range rootCmd.Commands() {
 		// just a precaution as the verb command didn't have runE
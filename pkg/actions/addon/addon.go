@@ -51,7 +51,7 @@ func New(clusterConfig *api.ClusterConfig, eksAPI eksiface.EKSAPI, stackManager
 	}, nil
 }
 
-func (a *Manager) waitForAddonToBeActive(addon *api.Addon) error {
+func (a *Manager) waitForAddonToBeActive(ctx context.Context, addon *api.Addon) error {
 	var out *awseks.DescribeAddonOutput
 	operation := func() (bool, error) {
 		var err error
@@ -75,7 +75,9 @@ func (a *Manager) waitForAddonToBeActive(addon *api.Addon) error {
 		},
 	}
 
-	err := w.WaitWithTimeout(a.timeout)
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+	err := w.Wait(ctx)
 	if err != nil {
 		if err == context.DeadlineExceeded {
 			return errors.Errorf("timed out waiting for addon %q to become active, status: %q", addon.Name, *out.Addon.Status)
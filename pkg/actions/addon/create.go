@@ -3,6 +3,7 @@ package addon
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -11,6 +12,8 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
 
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/kris-nova/logger"
@@ -26,7 +29,7 @@ const (
 	ebsCSIDriverName    = "aws-ebs-csi-driver"
 )
 
-func (a *Manager) Create(addon *api.Addon, wait bool) error {
+func (a *Manager) Create(ctx context.Context, addon *api.Addon, wait bool) error {
 	version := addon.Version
 	if version != "" {
 		var err error
@@ -62,8 +65,8 @@ func (a *Manager) Create(addon *api.Addon, wait bool) error {
 		if addon.ServiceAccountRoleARN != "" {
 			logger.Info("using provided ServiceAccountRoleARN %q", addon.ServiceAccountRoleARN)
 			createAddonInput.ServiceAccountRoleArn = &addon.ServiceAccountRoleARN
-		} else if hasPoliciesSet(addon) {
-			outputRole, err := a.createRole(addon, namespace, serviceAccount)
+		} else if HasPoliciesSet(addon) {
+			outputRole, err := a.createRole(ctx, addon, namespace, serviceAccount)
 			if err != nil {
 				return err
 			}
@@ -79,7 +82,7 @@ func (a *Manager) Create(addon *api.Addon, wait bool) error {
 				addon.WellKnownPolicies = *wellKnownPolicies
 			}
 
-			outputRole, err := a.createRole(addon, namespace, serviceAccount)
+			outputRole, err := a.createRole(ctx, addon, namespace, serviceAccount)
 			if err != nil {
 				return err
 			}
@@ -89,19 +92,19 @@ func (a *Manager) Create(addon *api.Addon, wait bool) error {
 		}
 	} else {
 		//if any sort of policy is set or could be set, log a warning
-		if addon.ServiceAccountRoleARN != "" || hasPoliciesSet(addon) || a.hasRecommendedPolicies(addon) {
+		if addon.ServiceAccountRoleARN != "" || HasPoliciesSet(addon) || a.hasRecommendedPolicies(addon) {
 			logger.Warning("OIDC is disabled but policies are required/specified for this addon. Users are responsible for attaching the policies to all nodegroup roles")
 		}
 	}
 
 	if addon.CanonicalName() == vpcCNIName {
 		logger.Debug("patching AWS node")
-		err := a.patchAWSNodeSA()
+		err := a.patchAWSNodeSA(ctx)
 		if err != nil {
 			return err
 		}
 
-		err = a.patchAWSNodeDaemonSet()
+		err = a.patchAWSNodeDaemonSet(ctx)
 		if err != nil {
 			return err
 		}
@@ -118,15 +121,36 @@ func (a *Manager) Create(addon *api.Addon, wait bool) error {
 	}
 
 	if wait {
-		return a.waitForAddonToBeActive(addon)
+		return a.waitForAddonToBeActive(ctx, addon)
 	}
 	logger.Info("successfully created addon")
 	return nil
 }
 
-func (a *Manager) patchAWSNodeSA() error {
+// CreateIfMissing creates the addon unless it exists already, e.g. because it was created by an
+// earlier attempt that failed afterwards; when wait is set, it waits for an existing addon to be active
+func (a *Manager) CreateIfMissing(ctx context.Context, addon *api.Addon, wait bool) error {
+	_, err := a.eksAPI.DescribeAddon(&eks.DescribeAddonInput{
+		ClusterName: &a.clusterConfig.Metadata.Name,
+		AddonName:   &addon.Name,
+	})
+	if err != nil {
+		if awsError, ok := err.(awserr.Error); ok && awsError.Code() == eks.ErrCodeResourceNotFoundException {
+			return a.Create(ctx, addon, wait)
+		}
+		return fmt.Errorf("failed to get addon %q: %w", addon.Name, err)
+	}
+
+	logger.Info("addon %q already exists", addon.Name)
+	if wait {
+		return a.waitForAddonToBeActive(ctx, addon)
+	}
+	return nil
+}
+
+func (a *Manager) patchAWSNodeSA(ctx context.Context) error {
 	serviceaccounts := a.clientSet.CoreV1().ServiceAccounts("kube-system")
-	sa, err := serviceaccounts.Get(context.TODO(), "aws-node", metav1.GetOptions{})
+	sa, err := serviceaccounts.Get(ctx, "aws-node", metav1.GetOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			logger.Debug("could not find aws-node SA, skipping patching")
@@ -145,7 +169,7 @@ func (a *Manager) patchAWSNodeSA() error {
 		return nil
 	}
 
-	_, err = serviceaccounts.Patch(context.TODO(), "aws-node", types.JSONPatchType, []byte(fmt.Sprintf(`[{"op": "remove", "path": "/metadata/managedFields/%d"}]`, managerIndex)), metav1.PatchOptions{})
+	_, err = serviceaccounts.Patch(ctx, "aws-node", types.JSONPatchType, []byte(fmt.Sprintf(`[{"op": "remove", "path": "/metadata/managedFields/%d"}]`, managerIndex)), metav1.PatchOptions{})
 	if err != nil {
 		return errors.Wrap(err, "failed to patch sa")
 	}
@@ -153,9 +177,9 @@ func (a *Manager) patchAWSNodeSA() error {
 	return nil
 }
 
-func (a *Manager) patchAWSNodeDaemonSet() error {
+func (a *Manager) patchAWSNodeDaemonSet(ctx context.Context) error {
 	daemonsets := a.clientSet.AppsV1().DaemonSets(kubeSystemNamespace)
-	sa, err := daemonsets.Get(context.TODO(), "aws-node", metav1.GetOptions{})
+	sa, err := daemonsets.Get(ctx, "aws-node", metav1.GetOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			logger.Debug("could not find aws-node daemon set, skipping patching")
@@ -174,7 +198,7 @@ func (a *Manager) patchAWSNodeDaemonSet() error {
 		return nil
 	}
 
-	_, err = daemonsets.Patch(context.TODO(), "aws-node", types.JSONPatchType, []byte(fmt.Sprintf(`[{"op": "remove", "path": "/metadata/managedFields/%d"}]`, managerIndex)), metav1.PatchOptions{})
+	_, err = daemonsets.Patch(ctx, "aws-node", types.JSONPatchType, []byte(fmt.Sprintf(`[{"op": "remove", "path": "/metadata/managedFields/%d"}]`, managerIndex)), metav1.PatchOptions{})
 	if err != nil {
 		return errors.Wrap(err, "failed to patch daemon set")
 	}
@@ -211,18 +235,30 @@ func (a *Manager) getKnownServiceAccountLocation(addon *api.Addon) (string, stri
 	}
 }
 
-func hasPoliciesSet(addon *api.Addon) bool {
+// HasPoliciesSet returns true when the addon has policies set for the IAM role eksctl creates for it
+func HasPoliciesSet(addon *api.Addon) bool {
 	return len(addon.AttachPolicyARNs) != 0 || addon.WellKnownPolicies.HasPolicy() || addon.AttachPolicy != nil
 }
 
-func (a *Manager) createRole(addon *api.Addon, namespace, serviceAccount string) (string, error) {
+func (a *Manager) createRole(ctx context.Context, addon *api.Addon, namespace, serviceAccount string) (string, error) {
+	// reuse the role of a stack created by an earlier attempt
+	stackName := a.makeAddonName(addon.Name)
+	existingStacks, err := a.stackManager.ListStacksMatching(fmt.Sprintf("^%s$", regexp.QuoteMeta(stackName)), cloudformation.StackStatusCreateComplete)
+	if err != nil {
+		return "", err
+	}
+	if len(existingStacks) > 0 && len(existingStacks[0].Outputs) > 0 {
+		logger.Info("using the IAM role of existing stack %q", stackName)
+		return *existingStacks[0].Outputs[0].OutputValue, nil
+	}
+
 	resourceSet, err := a.createRoleResourceSet(addon, namespace, serviceAccount)
 
 	if err != nil {
 		return "", err
 	}
 
-	err = a.createStack(resourceSet, addon)
+	err = a.createStack(ctx, resourceSet, addon)
 	if err != nil {
 		return "", err
 	}
@@ -244,14 +280,14 @@ func (a *Manager) createRoleResourceSet(addon *api.Addon, namespace, serviceAcco
 	return resourceSet, resourceSet.AddAllResources()
 }
 
-func (a *Manager) createStack(resourceSet builder.ResourceSet, addon *api.Addon) error {
+func (a *Manager) createStack(ctx context.Context, resourceSet builder.ResourceSet, addon *api.Addon) error {
 	errChan := make(chan error)
 
 	tags := map[string]string{
 		api.AddonNameTag: addon.Name,
 	}
 
-	err := a.stackManager.CreateStack(a.makeAddonName(addon.Name), resourceSet, tags, nil, errChan)
+	err := a.stackManager.CreateStack(ctx, a.makeAddonName(addon.Name), resourceSet, tags, nil, errChan)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package addon_test
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -11,6 +12,8 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -41,7 +44,7 @@ var _ = Describe("Create", func() {
 		mockProvider = mockprovider.NewMockProvider()
 		createStackReturnValue = nil
 
-		fakeStackManager.CreateStackStub = func(_ string, rs builder.ResourceSet, _ map[string]string, _ map[string]string, errs chan error) error {
+		fakeStackManager.CreateStackStub = func(_ context.Context, _ string, rs builder.ResourceSet, _ map[string]string, _ map[string]string, errs chan error) error {
 			go func() {
 				errs <- nil
 			}()
@@ -124,7 +127,7 @@ var _ = Describe("Create", func() {
 			returnedErr = fmt.Errorf("foo")
 		})
 		It("returns an error", func() {
-			err := manager.Create(&api.Addon{
+			err := manager.Create(context.Background(), &api.Addon{
 				Name:    "my-addon",
 				Version: "v1.0.0-eksbuild.1",
 			}, false)
@@ -138,7 +141,7 @@ var _ = Describe("Create", func() {
 			withOIDC = false
 		})
 		It("creates the addons but not the policies", func() {
-			err := manager.Create(&api.Addon{
+			err := manager.Create(context.Background(), &api.Addon{
 				Name:             "my-addon",
 				Version:          "v1.0.0-eksbuild.1",
 				AttachPolicyARNs: []string{"arn-1"},
@@ -161,7 +164,7 @@ var _ = Describe("Create", func() {
 
 			When("version is set to a numeric value", func() {
 				It("discovers and uses the latest available version", func() {
-					err := manager.Create(&api.Addon{
+					err := manager.Create(context.Background(), &api.Addon{
 						Name:             "my-addon",
 						Version:          "1.7.5",
 						AttachPolicyARNs: []string{"arn-1"},
@@ -178,7 +181,7 @@ var _ = Describe("Create", func() {
 
 			When("version is set to an alphanumeric value", func() {
 				It("discovers and uses the latest available version", func() {
-					err := manager.Create(&api.Addon{
+					err := manager.Create(context.Background(), &api.Addon{
 						Name:             "my-addon",
 						Version:          "1.7.5-eksbuild",
 						AttachPolicyARNs: []string{"arn-1"},
@@ -195,7 +198,7 @@ var _ = Describe("Create", func() {
 
 			When("version is set to latest", func() {
 				It("discovers and uses the latest available version", func() {
-					err := manager.Create(&api.Addon{
+					err := manager.Create(context.Background(), &api.Addon{
 						Name:             "my-addon",
 						Version:          "latest",
 						AttachPolicyARNs: []string{"arn-1"},
@@ -212,7 +215,7 @@ var _ = Describe("Create", func() {
 
 			When("the version is set to a version that does not exist", func() {
 				It("returns an error", func() {
-					err := manager.Create(&api.Addon{
+					err := manager.Create(context.Background(), &api.Addon{
 						Name:             "my-addon",
 						Version:          "1.7.8",
 						AttachPolicyARNs: []string{"arn-1"},
@@ -253,7 +256,7 @@ var _ = Describe("Create", func() {
 			})
 
 			It("returns an error", func() {
-				err := manager.Create(&api.Addon{
+				err := manager.Create(context.Background(), &api.Addon{
 					Name:             "my-addon",
 					Version:          "latest",
 					AttachPolicyARNs: []string{"arn-1"},
@@ -281,7 +284,7 @@ var _ = Describe("Create", func() {
 			})
 
 			It("returns an error", func() {
-				err := manager.Create(&api.Addon{
+				err := manager.Create(context.Background(), &api.Addon{
 					Name:             "my-addon",
 					Version:          "latest",
 					AttachPolicyARNs: []string{"arn-1"},
@@ -297,7 +300,7 @@ var _ = Describe("Create", func() {
 		})
 
 		It("creates the addons but not the policies", func() {
-			err := manager.Create(&api.Addon{
+			err := manager.Create(context.Background(), &api.Addon{
 				Name:             "my-addon",
 				Version:          "v1.0.0-eksbuild.1",
 				AttachPolicyARNs: []string{"arn-1"},
@@ -328,7 +331,7 @@ var _ = Describe("Create", func() {
 			})
 
 			It("creates the addon and waits for it to be active", func() {
-				err := manager.Create(&api.Addon{
+				err := manager.Create(context.Background(), &api.Addon{
 					Name:    "my-addon",
 					Version: "v1.0.0-eksbuild.1",
 				}, true)
@@ -354,7 +357,7 @@ var _ = Describe("Create", func() {
 			})
 
 			It("returns an error", func() {
-				err := manager.Create(&api.Addon{
+				err := manager.Create(context.Background(), &api.Addon{
 					Name:    "my-addon",
 					Version: "v1.0.0-eksbuild.1",
 				}, true)
@@ -366,7 +369,7 @@ var _ = Describe("Create", func() {
 	When("No policy/role is specified", func() {
 		When("we don't know the recommended policies for the specified addon", func() {
 			It("does not provide a role", func() {
-				err := manager.Create(&api.Addon{
+				err := manager.Create(context.Background(), &api.Addon{
 					Name:    "my-addon",
 					Version: "v1.0.0-eksbuild.1",
 				}, false)
@@ -382,7 +385,7 @@ var _ = Describe("Create", func() {
 
 		When("we know the recommended policies for the specified addon", func() {
 			BeforeEach(func() {
-				fakeStackManager.CreateStackStub = func(_ string, rs builder.ResourceSet, _ map[string]string, _ map[string]string, errs chan error) error {
+				fakeStackManager.CreateStackStub = func(_ context.Context, _ string, rs builder.ResourceSet, _ map[string]string, _ map[string]string, errs chan error) error {
 					go func() {
 						errs <- nil
 					}()
@@ -395,14 +398,14 @@ var _ = Describe("Create", func() {
 
 			When("its the vpc-cni addon", func() {
 				It("creates a role with the recommended policies and attaches it to the addon", func() {
-					err := manager.Create(&api.Addon{
+					err := manager.Create(context.Background(), &api.Addon{
 						Name:    "vpc-cni",
 						Version: "v1.0.0-eksbuild.1",
 					}, false)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(fakeStackManager.CreateStackCallCount()).To(Equal(1))
-					name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
+					_, name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
 					Expect(name).To(Equal("eksctl-my-cluster-addon-vpc-cni"))
 					Expect(resourceSet).NotTo(BeNil())
 					Expect(tags).To(Equal(map[string]string{
@@ -421,14 +424,14 @@ var _ = Describe("Create", func() {
 
 			When("its the aws-ebs-csi-driver addon", func() {
 				It("creates a role with the recommended policies and attaches it to the addon", func() {
-					err := manager.Create(&api.Addon{
+					err := manager.Create(context.Background(), &api.Addon{
 						Name:    "aws-ebs-csi-driver",
 						Version: "v1.0.0-eksbuild.1",
 					}, false)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(fakeStackManager.CreateStackCallCount()).To(Equal(1))
-					name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
+					_, name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
 					Expect(name).To(Equal("eksctl-my-cluster-addon-aws-ebs-csi-driver"))
 					Expect(resourceSet).NotTo(BeNil())
 					Expect(tags).To(Equal(map[string]string{
@@ -448,7 +451,7 @@ var _ = Describe("Create", func() {
 
 	When("attachPolicyARNs is configured", func() {
 		It("uses AttachPolicyARNS to create a role to attach to the addon", func() {
-			err := manager.Create(&api.Addon{
+			err := manager.Create(context.Background(), &api.Addon{
 				Name:             "my-addon",
 				Version:          "v1.0.0-eksbuild.1",
 				AttachPolicyARNs: []string{"arn-1"},
@@ -456,7 +459,7 @@ var _ = Describe("Create", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeStackManager.CreateStackCallCount()).To(Equal(1))
-			name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
+			_, name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
 			Expect(name).To(Equal("eksctl-my-cluster-addon-my-addon"))
 			Expect(resourceSet).NotTo(BeNil())
 			Expect(tags).To(Equal(map[string]string{
@@ -470,7 +473,7 @@ var _ = Describe("Create", func() {
 
 	When("wellKnownPolicies is configured", func() {
 		It("uses wellKnownPolicies to create a role to attach to the addon", func() {
-			err := manager.Create(&api.Addon{
+			err := manager.Create(context.Background(), &api.Addon{
 				Name:    "my-addon",
 				Version: "v1.0.0-eksbuild.1",
 				WellKnownPolicies: api.WellKnownPolicies{
@@ -480,7 +483,7 @@ var _ = Describe("Create", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeStackManager.CreateStackCallCount()).To(Equal(1))
-			name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
+			_, name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
 			Expect(name).To(Equal("eksctl-my-cluster-addon-my-addon"))
 			Expect(resourceSet).NotTo(BeNil())
 			Expect(tags).To(Equal(map[string]string{
@@ -494,7 +497,7 @@ var _ = Describe("Create", func() {
 
 	When("AttachPolicy is configured", func() {
 		It("uses AttachPolicy to create a role to attach to the addon", func() {
-			err := manager.Create(&api.Addon{
+			err := manager.Create(context.Background(), &api.Addon{
 				Name:    "my-addon",
 				Version: "v1.0.0-eksbuild.1",
 				AttachPolicy: api.InlineDocument{
@@ -504,7 +507,7 @@ var _ = Describe("Create", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeStackManager.CreateStackCallCount()).To(Equal(1))
-			name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
+			_, name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
 			Expect(name).To(Equal("eksctl-my-cluster-addon-my-addon"))
 			Expect(resourceSet).NotTo(BeNil())
 			Expect(tags).To(Equal(map[string]string{
@@ -518,7 +521,7 @@ var _ = Describe("Create", func() {
 
 	When("serviceAccountRoleARN is configured", func() {
 		It("uses the serviceAccountRoleARN to create the addon", func() {
-			err := manager.Create(&api.Addon{
+			err := manager.Create(context.Background(), &api.Addon{
 				Name:                  "my-addon",
 				Version:               "v1.0.0-eksbuild.1",
 				ServiceAccountRoleARN: "foo",
@@ -534,7 +537,7 @@ var _ = Describe("Create", func() {
 
 	When("tags are configured", func() {
 		It("uses the Tags to create the addon", func() {
-			err := manager.Create(&api.Addon{
+			err := manager.Create(context.Background(), &api.Addon{
 				Name:    "my-addon",
 				Version: "v1.0.0-eksbuild.1",
 				Tags:    map[string]string{"foo": "bar", "fox": "brown"},
@@ -548,4 +551,78 @@ var _ = Describe("Create", func() {
 			Expect(*createAddonInput.Tags["fox"]).To(Equal("brown"))
 		})
 	})
+
+	When("the IAM role stack of the addon exists already", func() {
+		BeforeEach(func() {
+			fakeStackManager.ListStacksMatchingReturns([]*cloudformation.Stack{
+				{
+					StackName: aws.String("eksctl-my-cluster-addon-my-addon"),
+					Outputs: []*cloudformation.Output{
+						{
+							OutputKey:   aws.String("Role1"),
+							OutputValue: aws.String("existing-role-arn"),
+						},
+					},
+				},
+			}, nil)
+		})
+
+		It("uses the role of the existing stack", func() {
+			err := manager.Create(context.Background(), &api.Addon{
+				Name:             "my-addon",
+				Version:          "v1.0.0-eksbuild.1",
+				AttachPolicyARNs: []string{"arn-1"},
+			}, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStackManager.CreateStackCallCount()).To(Equal(0))
+			nameRegex, statusFilters := fakeStackManager.ListStacksMatchingArgsForCall(0)
+			Expect(nameRegex).To(Equal("^eksctl-my-cluster-addon-my-addon$"))
+			Expect(statusFilters).To(ConsistOf(cloudformation.StackStatusCreateComplete))
+			Expect(*createAddonInput.ServiceAccountRoleArn).To(Equal("existing-role-arn"))
+		})
+	})
+
+	Describe("CreateIfMissing", func() {
+		BeforeEach(func() {
+			withOIDC = false
+		})
+
+		When("the addon does not exist", func() {
+			BeforeEach(func() {
+				mockProvider.MockEKS().On("DescribeAddon", mock.Anything).
+					Return(nil, awserr.New(awseks.ErrCodeResourceNotFoundException, "", nil))
+			})
+
+			It("creates the addon", func() {
+				err := manager.CreateIfMissing(context.Background(), &api.Addon{
+					Name:    "my-addon",
+					Version: "v1.0.0-eksbuild.1",
+				}, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mockProvider.MockEKS().AssertNumberOfCalls(GinkgoT(), "CreateAddon", 1)).To(BeTrue())
+			})
+		})
+
+		When("the addon exists already", func() {
+			BeforeEach(func() {
+				mockProvider.MockEKS().On("DescribeAddon", mock.Anything).
+					Return(&awseks.DescribeAddonOutput{
+						Addon: &awseks.Addon{
+							AddonName: aws.String("my-addon"),
+							Status:    aws.String("ACTIVE"),
+						},
+					}, nil)
+			})
+
+			It("waits for the addon without creating it again", func() {
+				err := manager.CreateIfMissing(context.Background(), &api.Addon{
+					Name:    "my-addon",
+					Version: "v1.0.0-eksbuild.1",
+				}, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mockProvider.MockEKS().AssertNumberOfCalls(GinkgoT(), "CreateAddon", 0)).To(BeTrue())
+			})
+		})
+	})
 })
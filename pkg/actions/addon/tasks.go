@@ -1,6 +1,8 @@
 package addon
 
 import (
+	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -60,48 +62,57 @@ type createAddonTask struct {
 
 func (t *createAddonTask) Describe() string { return t.info }
 
-func (t *createAddonTask) Do(errorCh chan error) error {
+// Do creates each addon, retrying it on transient errors; addons and IAM role stacks created
+// by an earlier attempt are not created again
+func (t *createAddonTask) Do(ctx context.Context, errorCh chan error) error {
+	var addonManager *Manager
+	if err := tasks.DoWithRetries(ctx, t.info, func() error {
+		var err error
+		addonManager, err = t.newManager()
+		return err
+	}); err != nil {
+		return err
+	}
+
+	for _, a := range t.addons {
+		if t.forceAll {
+			a.Force = true
+		}
+		addon := a
+		if err := tasks.DoWithRetries(ctx, fmt.Sprintf("create addon %q", addon.Name), func() error {
+			return addonManager.CreateIfMissing(ctx, addon, t.wait)
+		}); err != nil {
+			return err
+		}
+	}
+
+	go func() {
+		errorCh <- nil
+	}()
+	return nil
+}
+
+func (t *createAddonTask) newManager() (*Manager, error) {
 	clientSet, err := t.clusterProvider.NewStdClientSet(t.cfg)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := t.clusterProvider.WaitForControlPlane(t.cfg.Metadata, clientSet); err != nil {
-		return errors.Wrap(err, "failed to wait for control plane")
+		return nil, errors.Wrap(err, "failed to wait for control plane")
 	}
 
 	oidc, err := t.clusterProvider.NewOpenIDConnectManager(t.cfg)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	oidcProviderExists, err := oidc.CheckProviderExists()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	stackManager := t.clusterProvider.NewStackManager(t.cfg)
 
-	addonManager, err := New(t.cfg, t.clusterProvider.Provider.EKS(), stackManager, oidcProviderExists, oidc, clientSet, t.timeout)
-	if err != nil {
-		return err
-	}
-
-	for _, a := range t.addons {
-		if t.forceAll {
-			a.Force = true
-		}
-		err := addonManager.Create(a, t.wait)
-		if err != nil {
-			go func() {
-				errorCh <- err
-			}()
-			return err
-		}
-	}
-
-	go func() {
-		errorCh <- nil
-	}()
-	return nil
+	return New(t.cfg, t.clusterProvider.Provider.EKS(), stackManager, oidcProviderExists, oidc, clientSet, t.timeout)
 }
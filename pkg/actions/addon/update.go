@@ -1,6 +1,7 @@
 package addon
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -12,7 +13,17 @@ import (
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 )
 
-func (a *Manager) Update(addon *api.Addon, wait bool) error {
+func (a *Manager) Update(ctx context.Context, addon *api.Addon, wait bool) error {
+	return a.update(ctx, addon, wait, false)
+}
+
+// PlanUpdate previews an addon update; changes to the addon's IAM role stack are shown
+// as a ChangeSet, and the addon itself is left untouched
+func (a *Manager) PlanUpdate(ctx context.Context, addon *api.Addon) error {
+	return a.update(ctx, addon, false, true)
+}
+
+func (a *Manager) update(ctx context.Context, addon *api.Addon, wait, plan bool) error {
 	logger.Debug("addon: %v", addon)
 
 	updateAddonInput := &eks.UpdateAddonInput{
@@ -54,8 +65,8 @@ func (a *Manager) Update(addon *api.Addon, wait bool) error {
 	//check if we have been provided a different set of policies/role
 	if addon.ServiceAccountRoleARN != "" {
 		updateAddonInput.ServiceAccountRoleArn = &addon.ServiceAccountRoleARN
-	} else if hasPoliciesSet(addon) {
-		serviceAccountRoleARN, err := a.updateWithNewPolicies(addon)
+	} else if HasPoliciesSet(addon) {
+		serviceAccountRoleARN, err := a.updateWithNewPolicies(ctx, addon, plan)
 		if err != nil {
 			return err
 		}
@@ -67,6 +78,11 @@ func (a *Manager) Update(addon *api.Addon, wait bool) error {
 		}
 	}
 
+	if plan {
+		logger.Info("(plan) would update addon %q: %s", addon.Name, updateAddonInput.String())
+		return nil
+	}
+
 	logger.Info("updating addon")
 	logger.Debug(updateAddonInput.String())
 
@@ -78,12 +94,12 @@ func (a *Manager) Update(addon *api.Addon, wait bool) error {
 		logger.Debug(output.String())
 	}
 	if wait {
-		return a.waitForAddonToBeActive(addon)
+		return a.waitForAddonToBeActive(ctx, addon)
 	}
 	return nil
 }
 
-func (a *Manager) updateWithNewPolicies(addon *api.Addon) (string, error) {
+func (a *Manager) updateWithNewPolicies(ctx context.Context, addon *api.Addon, plan bool) (string, error) {
 	stackName := a.makeAddonName(addon.Name)
 	existingStacks, err := a.stackManager.ListStacksMatching(stackName)
 	if err != nil {
@@ -93,7 +109,11 @@ func (a *Manager) updateWithNewPolicies(addon *api.Addon) (string, error) {
 	namespace, serviceAccount := a.getKnownServiceAccountLocation(addon)
 
 	if len(existingStacks) == 0 {
-		return a.createRole(addon, namespace, serviceAccount)
+		if plan {
+			logger.Info("(plan) would create IAM role stack %q for addon %q", stackName, addon.Name)
+			return "", nil
+		}
+		return a.createRole(ctx, addon, namespace, serviceAccount)
 	}
 
 	createNewTemplate, err := a.createNewTemplate(addon, namespace, serviceAccount)
@@ -101,12 +121,13 @@ func (a *Manager) updateWithNewPolicies(addon *api.Addon) (string, error) {
 		return "", err
 	}
 	var templateBody manager.TemplateBody = createNewTemplate
-	err = a.stackManager.UpdateStack(manager.UpdateStackOptions{
+	err = a.stackManager.UpdateStack(ctx, manager.UpdateStackOptions{
 		StackName:     stackName,
 		ChangeSetName: fmt.Sprintf("updating-policy-%s", uuid.NewString()),
 		Description:   "updating policies",
 		TemplateData:  templateBody,
 		Wait:          true,
+		Plan:          plan,
 	})
 	if err != nil {
 		return "", err
@@ -120,6 +141,12 @@ func (a *Manager) updateWithNewPolicies(addon *api.Addon) (string, error) {
 	return *existingStacks[0].Outputs[0].OutputValue, nil
 }
 
+// RoleTemplate renders the template of the IAM role stack of an addon from the policies set in the config
+func (a *Manager) RoleTemplate(addon *api.Addon) ([]byte, error) {
+	namespace, serviceAccount := a.getKnownServiceAccountLocation(addon)
+	return a.createNewTemplate(addon, namespace, serviceAccount)
+}
+
 func (a *Manager) createNewTemplate(addon *api.Addon, namespace, serviceAccount string) ([]byte, error) {
 	resourceSet, err := a.createRoleResourceSet(addon, namespace, serviceAccount)
 	if err != nil {
@@ -1,6 +1,7 @@
 package addon_test
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -34,7 +35,7 @@ var _ = Describe("Update", func() {
 		mockProvider = mockprovider.NewMockProvider()
 		fakeStackManager = new(fakes.FakeStackManager)
 
-		fakeStackManager.CreateStackStub = func(_ string, rs builder.ResourceSet, _ map[string]string, _ map[string]string, errs chan error) error {
+		fakeStackManager.CreateStackStub = func(_ context.Context, _ string, rs builder.ResourceSet, _ map[string]string, _ map[string]string, errs chan error) error {
 			go func() {
 				errs <- nil
 			}()
@@ -109,7 +110,7 @@ var _ = Describe("Update", func() {
 
 		When("updating the version", func() {
 			It("updates the addon and preserves the existing role", func() {
-				err := addonManager.Update(&api.Addon{
+				err := addonManager.Update(context.Background(), &api.Addon{
 					Name:    "my-addon",
 					Version: "v1.0.0-eksbuild.2",
 					Force:   true,
@@ -127,7 +128,7 @@ var _ = Describe("Update", func() {
 
 			When("the version is not set", func() {
 				It("preserves the existing addon version", func() {
-					err := addonManager.Update(&api.Addon{
+					err := addonManager.Update(context.Background(), &api.Addon{
 						Name:    "my-addon",
 						Version: "",
 					}, false)
@@ -144,7 +145,7 @@ var _ = Describe("Update", func() {
 
 			When("the version is set to a numeric version", func() {
 				It("discovers and uses the latest available version", func() {
-					err := addonManager.Update(&api.Addon{
+					err := addonManager.Update(context.Background(), &api.Addon{
 						Name:    "my-addon",
 						Version: "1.7.5",
 					}, false)
@@ -161,7 +162,7 @@ var _ = Describe("Update", func() {
 
 			When("the version is set to latest", func() {
 				It("discovers and uses the latest available version", func() {
-					err := addonManager.Update(&api.Addon{
+					err := addonManager.Update(context.Background(), &api.Addon{
 						Name:    "my-addon",
 						Version: "latest",
 					}, false)
@@ -178,7 +179,7 @@ var _ = Describe("Update", func() {
 
 			When("the version is set to a version that does not exist", func() {
 				It("returns an error", func() {
-					err := addonManager.Update(&api.Addon{
+					err := addonManager.Update(context.Background(), &api.Addon{
 						Name:             "my-addon",
 						Version:          "1.7.8",
 						AttachPolicyARNs: []string{"arn-1"},
@@ -202,7 +203,7 @@ var _ = Describe("Update", func() {
 				})
 
 				It("creates the addon and waits for it to be running", func() {
-					err := addonManager.Update(&api.Addon{
+					err := addonManager.Update(context.Background(), &api.Addon{
 						Name:    "my-addon",
 						Version: "v1.0.0-eksbuild.2",
 						Force:   true,
@@ -230,7 +231,7 @@ var _ = Describe("Update", func() {
 				})
 
 				It("returns an error", func() {
-					err := addonManager.Update(&api.Addon{
+					err := addonManager.Update(context.Background(), &api.Addon{
 						Name:    "my-addon",
 						Version: "v1.0.0-eksbuild.2",
 						Force:   true,
@@ -243,7 +244,7 @@ var _ = Describe("Update", func() {
 		When("updating the policy", func() {
 			When("specifying a new serviceAccountRoleARN", func() {
 				It("updates the addon", func() {
-					err := addonManager.Update(&api.Addon{
+					err := addonManager.Update(context.Background(), &api.Addon{
 						Name:                  "my-addon",
 						Version:               "v1.0.0-eksbuild.2",
 						ServiceAccountRoleARN: "new-arn",
@@ -274,7 +275,7 @@ var _ = Describe("Update", func() {
 							},
 						}, nil)
 
-						err := addonManager.Update(&api.Addon{
+						err := addonManager.Update(context.Background(), &api.Addon{
 							Name:             "vpc-cni",
 							Version:          "v1.0.0-eksbuild.2",
 							AttachPolicyARNs: []string{"arn-1"},
@@ -283,7 +284,7 @@ var _ = Describe("Update", func() {
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(fakeStackManager.UpdateStackCallCount()).To(Equal(1))
-						options := fakeStackManager.UpdateStackArgsForCall(0)
+						_, options := fakeStackManager.UpdateStackArgsForCall(0)
 						Expect(options.StackName).To(Equal("eksctl-my-cluster-addon-vpc-cni"))
 						Expect(options.ChangeSetName).To(ContainSubstring("updating-policy"))
 						Expect(options.Description).To(Equal("updating policies"))
@@ -301,7 +302,7 @@ var _ = Describe("Update", func() {
 
 				When("its a new set of ARNs", func() {
 					It("creates a role with the ARNs", func() {
-						err := addonManager.Update(&api.Addon{
+						err := addonManager.Update(context.Background(), &api.Addon{
 							Name:             "my-addon",
 							Version:          "v1.0.0-eksbuild.2",
 							AttachPolicyARNs: []string{"arn-1"},
@@ -310,7 +311,7 @@ var _ = Describe("Update", func() {
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(fakeStackManager.CreateStackCallCount()).To(Equal(1))
-						name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
+						_, name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
 						Expect(name).To(Equal("eksctl-my-cluster-addon-my-addon"))
 						Expect(resourceSet).NotTo(BeNil())
 						Expect(tags).To(Equal(map[string]string{
@@ -343,7 +344,7 @@ var _ = Describe("Update", func() {
 							},
 						}, nil)
 
-						err := addonManager.Update(&api.Addon{
+						err := addonManager.Update(context.Background(), &api.Addon{
 							Name:    "vpc-cni",
 							Version: "v1.0.0-eksbuild.2",
 							AttachPolicy: api.InlineDocument{
@@ -354,7 +355,7 @@ var _ = Describe("Update", func() {
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(fakeStackManager.UpdateStackCallCount()).To(Equal(1))
-						options := fakeStackManager.UpdateStackArgsForCall(0)
+						_, options := fakeStackManager.UpdateStackArgsForCall(0)
 						Expect(options.StackName).To(Equal("eksctl-my-cluster-addon-vpc-cni"))
 						Expect(options.ChangeSetName).To(ContainSubstring("updating-policy"))
 						Expect(options.Description).To(Equal("updating policies"))
@@ -371,7 +372,7 @@ var _ = Describe("Update", func() {
 
 				When("its a new set of policies", func() {
 					It("creates a role with the policies", func() {
-						err := addonManager.Update(&api.Addon{
+						err := addonManager.Update(context.Background(), &api.Addon{
 							Name:    "my-addon",
 							Version: "v1.0.0-eksbuild.2",
 							AttachPolicy: api.InlineDocument{
@@ -382,7 +383,7 @@ var _ = Describe("Update", func() {
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(fakeStackManager.CreateStackCallCount()).To(Equal(1))
-						name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
+						_, name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
 						Expect(name).To(Equal("eksctl-my-cluster-addon-my-addon"))
 						Expect(resourceSet).NotTo(BeNil())
 						Expect(tags).To(Equal(map[string]string{
@@ -415,7 +416,7 @@ var _ = Describe("Update", func() {
 							},
 						}, nil)
 
-						err := addonManager.Update(&api.Addon{
+						err := addonManager.Update(context.Background(), &api.Addon{
 							Name:    "vpc-cni",
 							Version: "v1.0.0-eksbuild.2",
 							WellKnownPolicies: api.WellKnownPolicies{
@@ -426,7 +427,7 @@ var _ = Describe("Update", func() {
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(fakeStackManager.UpdateStackCallCount()).To(Equal(1))
-						options := fakeStackManager.UpdateStackArgsForCall(0)
+						_, options := fakeStackManager.UpdateStackArgsForCall(0)
 						Expect(options.StackName).To(Equal("eksctl-my-cluster-addon-vpc-cni"))
 						Expect(options.ChangeSetName).To(ContainSubstring("updating-policy"))
 						Expect(options.Description).To(Equal("updating policies"))
@@ -443,7 +444,7 @@ var _ = Describe("Update", func() {
 
 				When("its a new set of well known policies", func() {
 					It("creates a role with the well known policies", func() {
-						err := addonManager.Update(&api.Addon{
+						err := addonManager.Update(context.Background(), &api.Addon{
 							Name:    "my-addon",
 							Version: "v1.0.0-eksbuild.2",
 							WellKnownPolicies: api.WellKnownPolicies{
@@ -454,7 +455,7 @@ var _ = Describe("Update", func() {
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(fakeStackManager.CreateStackCallCount()).To(Equal(1))
-						name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
+						_, name, resourceSet, tags, _, _ := fakeStackManager.CreateStackArgsForCall(0)
 						Expect(name).To(Equal("eksctl-my-cluster-addon-my-addon"))
 						Expect(resourceSet).NotTo(BeNil())
 						Expect(tags).To(Equal(map[string]string{
@@ -474,6 +475,46 @@ var _ = Describe("Update", func() {
 		})
 	})
 
+	When("planning an update", func() {
+		It("previews the IAM role stack changes without updating the addon", func() {
+			fakeStackManager.ListStacksMatchingReturns([]*manager.Stack{
+				{
+					StackName: aws.String("eksctl-my-cluster-addon-vpc-cni"),
+					Outputs: []*cloudformation.Output{
+						{
+							OutputValue: aws.String("new-service-account-role-arn"),
+							OutputKey:   aws.String("Role1"),
+						},
+					},
+				},
+			}, nil)
+
+			err := addonManager.PlanUpdate(context.Background(), &api.Addon{
+				Name:             "vpc-cni",
+				Version:          "v1.0.0-eksbuild.2",
+				AttachPolicyARNs: []string{"arn-1"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStackManager.UpdateStackCallCount()).To(Equal(1))
+			_, options := fakeStackManager.UpdateStackArgsForCall(0)
+			Expect(options.StackName).To(Equal("eksctl-my-cluster-addon-vpc-cni"))
+			Expect(options.Plan).To(BeTrue())
+			mockProvider.MockEKS().AssertNotCalled(GinkgoT(), "UpdateAddon", mock.Anything)
+		})
+
+		It("does not create a new IAM role stack", func() {
+			err := addonManager.PlanUpdate(context.Background(), &api.Addon{
+				Name:             "my-addon",
+				Version:          "v1.0.0-eksbuild.2",
+				AttachPolicyARNs: []string{"arn-1"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeStackManager.CreateStackCallCount()).To(Equal(0))
+			mockProvider.MockEKS().AssertNotCalled(GinkgoT(), "UpdateAddon", mock.Anything)
+		})
+	})
+
 	When("EKS fails to return an UpdateAddonOutput", func() {
 		It("returns an error", func() {
 			mockProvider.MockEKS().On("UpdateAddon", mock.Anything).Run(func(args mock.Arguments) {
@@ -482,7 +523,7 @@ var _ = Describe("Update", func() {
 				updateAddonInput = args[0].(*awseks.UpdateAddonInput)
 			}).Return(nil, fmt.Errorf("foo"))
 
-			err := addonManager.Update(&api.Addon{
+			err := addonManager.Update(context.Background(), &api.Addon{
 				Name: "my-addon",
 			}, false)
 			Expect(err).To(MatchError(`failed to update addon "my-addon": foo`))
@@ -0,0 +1,533 @@
+package adopt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+)
+
+// emptyTemplate is the template of a cluster stack that doesn't exist yet
+const emptyTemplate = `{"Resources": {}}`
+
+// Adopter imports existing resources described in a ClusterConfig into the stacks of the cluster,
+// so that they are managed like the resources created by eksctl
+type Adopter struct {
+	cfg          *api.ClusterConfig
+	stackManager manager.StackManager
+	ec2API       ec2iface.EC2API
+	iamAPI       iamiface.IAMAPI
+	asgAPI       autoscalingiface.AutoScalingAPI
+}
+
+// New creates a new Adopter
+func New(cfg *api.ClusterConfig, stackManager manager.StackManager, ec2API ec2iface.EC2API, iamAPI iamiface.IAMAPI, asgAPI autoscalingiface.AutoScalingAPI) *Adopter {
+	return &Adopter{
+		cfg:          cfg,
+		stackManager: stackManager,
+		ec2API:       ec2API,
+		iamAPI:       iamAPI,
+		asgAPI:       asgAPI,
+	}
+}
+
+// Adopt imports the VPC, subnets, control plane security group and IAM roles of the cluster into the
+// cluster stack, and the auto scaling groups of nodegroups that have no stack into new nodegroup stacks;
+// the cluster stack is created with the imported resources if the cluster has none
+func (a *Adopter) Adopt(ctx context.Context, plan bool) error {
+	clusterStack, err := a.stackManager.DescribeClusterStack()
+	if err != nil {
+		return err
+	}
+
+	adopted := 0
+	if clusterStack == nil {
+		if adopted, err = a.createClusterStack(ctx, plan); err != nil {
+			return err
+		}
+	} else {
+		template, err := a.stackManager.GetStackTemplate(*clusterStack.StackName)
+		if err != nil {
+			return errors.Wrapf(err, "error getting stack template %s", *clusterStack.StackName)
+		}
+		clusterResources, err := a.clusterResources(template)
+		if err != nil {
+			return err
+		}
+		if len(clusterResources) > 0 {
+			if err := a.stackManager.ImportResources(ctx, manager.ImportResourcesOptions{
+				StackName:     *clusterStack.StackName,
+				ChangeSetName: a.stackManager.MakeChangeSetName("adopt-cluster"),
+				Description:   fmt.Sprintf("importing %s into stack %q", describeResources(clusterResources), *clusterStack.StackName),
+				Resources:     clusterResources,
+				Plan:          plan,
+			}); err != nil {
+				return err
+			}
+			adopted += len(clusterResources)
+		}
+	}
+
+	nodeGroupStacks, err := a.stackManager.ListNodeGroupStacks()
+	if err != nil {
+		return err
+	}
+	hasStack := map[string]bool{}
+	for _, s := range nodeGroupStacks {
+		hasStack[s.NodeGroupName] = true
+	}
+
+	for _, ng := range a.cfg.NodeGroups {
+		if hasStack[ng.Name] {
+			continue
+		}
+		asg, err := a.autoScalingGroup(ng.Name)
+		if err != nil {
+			return err
+		}
+		if asg == nil {
+			logger.Warning("nodegroup %q has no stack and no auto scaling group named %q was found, skipping", ng.Name, ng.Name)
+			continue
+		}
+		resource, err := autoScalingGroupResource(asg)
+		if err != nil {
+			return err
+		}
+		nodeGroupOutputs, err := a.nodeGroupOutputs(ng, asg)
+		if err != nil {
+			return err
+		}
+		stackName := fmt.Sprintf("eksctl-%s-nodegroup-%s", a.cfg.Metadata.Name, ng.Name)
+		if err := a.stackManager.ImportResources(ctx, manager.ImportResourcesOptions{
+			StackName:     stackName,
+			ChangeSetName: a.stackManager.MakeChangeSetName("adopt-nodegroup"),
+			Description:   fmt.Sprintf("creating stack %q with the existing auto scaling group %q", stackName, ng.Name),
+			Resources:     []manager.ResourceToImport{*resource},
+			NewStack:      true,
+			Tags: map[string]string{
+				api.NodeGroupNameTag:    ng.Name,
+				api.OldNodeGroupNameTag: ng.Name,
+				api.NodeGroupTypeTag:    string(api.NodeGroupTypeUnmanaged),
+			},
+			Outputs: nodeGroupOutputs,
+			Plan:    plan,
+		}); err != nil {
+			return err
+		}
+		adopted++
+	}
+
+	if adopted == 0 {
+		logger.Success("no resources to adopt, all resources described in the config are already part of the stacks of cluster %q", a.cfg.Metadata.Name)
+		return nil
+	}
+	if plan {
+		logger.Warning("no changes were applied, run again with '--approve' to apply the changes")
+		return nil
+	}
+	logger.Success("adopted %d resource(s) into the stacks of cluster %q", adopted, a.cfg.Metadata.Name)
+	return nil
+}
+
+// createClusterStack creates the cluster stack of a cluster that was not created by eksctl with the
+// resources described in the config, and the outputs that eksctl reads from the cluster stack; the
+// control plane itself is not part of the stack; it returns the number of resources imported
+func (a *Adopter) createClusterStack(ctx context.Context, plan bool) (int, error) {
+	vpc := a.cfg.VPC
+	if vpc == nil || vpc.ID == "" || vpc.SecurityGroup == "" {
+		return 0, fmt.Errorf("no eksctl-managed CloudFormation stack found for cluster %q; vpc.id and vpc.securityGroup must be set to create one", a.cfg.Metadata.Name)
+	}
+	clusterResources, err := a.clusterResources(emptyTemplate)
+	if err != nil {
+		return 0, err
+	}
+
+	stackName := a.stackManager.MakeClusterStackName()
+	clusterOutputs := map[string]string{
+		outputs.ClusterStackName:     stackName,
+		outputs.ClusterVPC:           vpc.ID,
+		outputs.ClusterSecurityGroup: vpc.SecurityGroup,
+	}
+	if vpc.SharedNodeSecurityGroup != "" {
+		clusterOutputs[outputs.ClusterSharedNodeSecurityGroup] = vpc.SharedNodeSecurityGroup
+	}
+	if vpc.Subnets != nil {
+		if ids := subnetIDs(vpc.Subnets.Public); len(ids) > 0 {
+			clusterOutputs[outputs.ClusterSubnetsPublic] = strings.Join(ids, ",")
+		}
+		if ids := subnetIDs(vpc.Subnets.Private); len(ids) > 0 {
+			clusterOutputs[outputs.ClusterSubnetsPrivate] = strings.Join(ids, ",")
+		}
+	}
+	if iamConfig := a.cfg.IAM; iamConfig != nil {
+		if api.IsSetAndNonEmptyString(iamConfig.ServiceRoleARN) {
+			clusterOutputs[outputs.ClusterServiceRoleARN] = *iamConfig.ServiceRoleARN
+		}
+		if api.IsSetAndNonEmptyString(iamConfig.FargatePodExecutionRoleARN) {
+			clusterOutputs[outputs.FargatePodExecutionRoleARN] = *iamConfig.FargatePodExecutionRoleARN
+		}
+	}
+
+	if err := a.stackManager.ImportResources(ctx, manager.ImportResourcesOptions{
+		StackName:     stackName,
+		ChangeSetName: a.stackManager.MakeChangeSetName("adopt-cluster"),
+		Description:   fmt.Sprintf("creating stack %q with %s", stackName, describeResources(clusterResources)),
+		Resources:     clusterResources,
+		NewStack:      true,
+		Outputs:       clusterOutputs,
+		Plan:          plan,
+	}); err != nil {
+		return 0, err
+	}
+	return len(clusterResources), nil
+}
+
+// clusterResources returns the resources described in the config that are not part of the given
+// cluster stack template; they are given the logical IDs that eksctl uses when it creates them
+func (a *Adopter) clusterResources(template string) ([]manager.ResourceToImport, error) {
+	inStack := func(logicalID string) bool {
+		return gjson.Get(template, "Resources."+logicalID).Exists()
+	}
+
+	var resources []manager.ResourceToImport
+	vpc := a.cfg.VPC
+	if vpc != nil && vpc.ID != "" && !inStack("VPC") {
+		resource, err := a.vpc(vpc.ID)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, *resource)
+	}
+
+	if vpc != nil && vpc.Subnets != nil {
+		subnets, err := a.subnets(inStack)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, subnets...)
+	}
+
+	if vpc != nil && vpc.SecurityGroup != "" && !inStack("ControlPlaneSecurityGroup") {
+		resource, err := a.securityGroup("ControlPlaneSecurityGroup", vpc.SecurityGroup)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, *resource)
+	}
+
+	if iamConfig := a.cfg.IAM; iamConfig != nil {
+		roles := []struct {
+			logicalID string
+			roleARN   *string
+		}{
+			{"ServiceRole", iamConfig.ServiceRoleARN},
+			{"FargatePodExecutionRole", iamConfig.FargatePodExecutionRoleARN},
+		}
+		for _, r := range roles {
+			if !api.IsSetAndNonEmptyString(r.roleARN) || inStack(r.logicalID) {
+				continue
+			}
+			resource, err := a.role(r.logicalID, *r.roleARN)
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, *resource)
+		}
+	}
+	return resources, nil
+}
+
+func (a *Adopter) vpc(id string) (*manager.ResourceToImport, error) {
+	output, err := a.ec2API.DescribeVpcs(&ec2.DescribeVpcsInput{
+		VpcIds: aws.StringSlice([]string{id}),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing VPC %q", id)
+	}
+	if len(output.Vpcs) != 1 {
+		return nil, fmt.Errorf("VPC %q not found", id)
+	}
+	return &manager.ResourceToImport{
+		LogicalID:    "VPC",
+		ResourceType: "AWS::EC2::VPC",
+		Identifier:   map[string]string{"VpcId": id},
+		Properties: map[string]interface{}{
+			"CidrBlock": aws.StringValue(output.Vpcs[0].CidrBlock),
+		},
+	}, nil
+}
+
+func (a *Adopter) subnets(inStack func(string) bool) ([]manager.ResourceToImport, error) {
+	logicalIDs := map[string]string{}
+	var ids []string
+	mappings := map[api.SubnetTopology]api.AZSubnetMapping{
+		api.SubnetTopologyPublic:  a.cfg.VPC.Subnets.Public,
+		api.SubnetTopologyPrivate: a.cfg.VPC.Subnets.Private,
+	}
+	for topology, subnets := range mappings {
+		for name, subnet := range subnets {
+			if subnet.ID != "" {
+				logicalIDs[subnet.ID] = subnetLogicalID(topology, name)
+			}
+		}
+	}
+	for id, logicalID := range logicalIDs {
+		if !inStack(logicalID) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	sort.Strings(ids)
+
+	output, err := a.ec2API.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(ids),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "describing subnets")
+	}
+	var resources []manager.ResourceToImport
+	for _, subnet := range output.Subnets {
+		id := aws.StringValue(subnet.SubnetId)
+		resources = append(resources, manager.ResourceToImport{
+			LogicalID:    logicalIDs[id],
+			ResourceType: "AWS::EC2::Subnet",
+			Identifier:   map[string]string{"SubnetId": id},
+			Properties: map[string]interface{}{
+				"AvailabilityZone": aws.StringValue(subnet.AvailabilityZone),
+				"CidrBlock":        aws.StringValue(subnet.CidrBlock),
+				"VpcId":            aws.StringValue(subnet.VpcId),
+			},
+		})
+	}
+	if len(resources) != len(ids) {
+		return nil, fmt.Errorf("only %d of subnets %v were found", len(resources), ids)
+	}
+	return resources, nil
+}
+
+func subnetIDs(subnets api.AZSubnetMapping) []string {
+	var ids []string
+	for _, subnet := range subnets {
+		if subnet.ID != "" {
+			ids = append(ids, subnet.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// subnetLogicalID returns the logical ID that eksctl gives to a subnet it creates
+func subnetLogicalID(topology api.SubnetTopology, name string) string {
+	return "Subnet" + string(topology) + strings.ToUpper(strings.Join(strings.Split(name, "-"), ""))
+}
+
+func (a *Adopter) securityGroup(logicalID, id string) (*manager.ResourceToImport, error) {
+	output, err := a.ec2API.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		GroupIds: aws.StringSlice([]string{id}),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing security group %q", id)
+	}
+	if len(output.SecurityGroups) != 1 {
+		return nil, fmt.Errorf("security group %q not found", id)
+	}
+	sg := output.SecurityGroups[0]
+	return &manager.ResourceToImport{
+		LogicalID:    logicalID,
+		ResourceType: "AWS::EC2::SecurityGroup",
+		Identifier:   map[string]string{"Id": id},
+		Properties: map[string]interface{}{
+			"GroupDescription": aws.StringValue(sg.Description),
+			"GroupName":        aws.StringValue(sg.GroupName),
+			"VpcId":            aws.StringValue(sg.VpcId),
+		},
+	}, nil
+}
+
+func (a *Adopter) role(logicalID, roleARN string) (*manager.ResourceToImport, error) {
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing role ARN %q", roleARN)
+	}
+	// the resource of a role ARN is role/<path>/<name>
+	roleName := parsed.Resource[strings.LastIndex(parsed.Resource, "/")+1:]
+
+	output, err := a.iamAPI.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting role %q", roleName)
+	}
+	// the policy document is returned URL-encoded
+	policyDocument, err := url.QueryUnescape(aws.StringValue(output.Role.AssumeRolePolicyDocument))
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding assume role policy document of role %q", roleName)
+	}
+	var assumeRolePolicy interface{}
+	if err := json.Unmarshal([]byte(policyDocument), &assumeRolePolicy); err != nil {
+		return nil, errors.Wrapf(err, "parsing assume role policy document of role %q", roleName)
+	}
+	return &manager.ResourceToImport{
+		LogicalID:    logicalID,
+		ResourceType: "AWS::IAM::Role",
+		Identifier:   map[string]string{"RoleName": roleName},
+		Properties: map[string]interface{}{
+			"AssumeRolePolicyDocument": assumeRolePolicy,
+			"Path":                     aws.StringValue(output.Role.Path),
+			"RoleName":                 roleName,
+		},
+	}, nil
+}
+
+// autoScalingGroup returns the auto scaling group with the given name, or nil if it doesn't exist
+func (a *Adopter) autoScalingGroup(name string) (*autoscaling.Group, error) {
+	output, err := a.asgAPI.DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: aws.StringSlice([]string{name}),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing auto scaling group %q", name)
+	}
+	if len(output.AutoScalingGroups) == 0 {
+		return nil, nil
+	}
+	return output.AutoScalingGroups[0], nil
+}
+
+// autoScalingGroupResource returns the auto scaling group with the logical ID eksctl gives to the
+// auto scaling group of a nodegroup it creates
+func autoScalingGroupResource(asg *autoscaling.Group) (*manager.ResourceToImport, error) {
+	name := aws.StringValue(asg.AutoScalingGroupName)
+	properties := map[string]interface{}{
+		"AutoScalingGroupName": name,
+		"MinSize":              strconv.FormatInt(aws.Int64Value(asg.MinSize), 10),
+		"MaxSize":              strconv.FormatInt(aws.Int64Value(asg.MaxSize), 10),
+		"DesiredCapacity":      strconv.FormatInt(aws.Int64Value(asg.DesiredCapacity), 10),
+	}
+	if subnets := aws.StringValue(asg.VPCZoneIdentifier); subnets != "" {
+		properties["VPCZoneIdentifier"] = strings.Split(subnets, ",")
+	}
+	switch {
+	case asg.LaunchTemplate != nil:
+		properties["LaunchTemplate"] = launchTemplateProperty(asg.LaunchTemplate)
+	case asg.LaunchConfigurationName != nil:
+		properties["LaunchConfigurationName"] = *asg.LaunchConfigurationName
+	default:
+		return nil, fmt.Errorf("auto scaling group %q uses a mixed instances policy, which cannot be adopted", name)
+	}
+
+	return &manager.ResourceToImport{
+		LogicalID:    "NodeGroup",
+		ResourceType: "AWS::AutoScaling::AutoScalingGroup",
+		Identifier:   map[string]string{"AutoScalingGroupName": name},
+		Properties:   properties,
+	}, nil
+}
+
+func launchTemplateProperty(lt *autoscaling.LaunchTemplateSpecification) map[string]interface{} {
+	property := map[string]interface{}{
+		"Version": aws.StringValue(lt.Version),
+	}
+	if lt.LaunchTemplateId != nil {
+		property["LaunchTemplateId"] = *lt.LaunchTemplateId
+	} else {
+		property["LaunchTemplateName"] = aws.StringValue(lt.LaunchTemplateName)
+	}
+	return property
+}
+
+// nodeGroupOutputs returns the outputs of the stacks of nodegroups created by eksctl that are read
+// when the nodegroup is listed, scaled, drained or deleted; the instance role outputs are
+// taken from the instance profile of the auto scaling group's instances, if any
+func (a *Adopter) nodeGroupOutputs(ng *api.NodeGroup, asg *autoscaling.Group) (map[string]string, error) {
+	nodeGroupOutputs := map[string]string{
+		outputs.NodeGroupFeaturePrivateNetworking:   strconv.FormatBool(ng.PrivateNetworking),
+		outputs.NodeGroupFeatureSharedSecurityGroup: strconv.FormatBool(ng.SecurityGroups == nil || !api.IsDisabled(ng.SecurityGroups.WithShared)),
+		// the security group eksctl creates for each nodegroup doesn't exist for an adopted auto scaling group
+		outputs.NodeGroupFeatureLocalSecurityGroup: "false",
+	}
+
+	profile, err := a.instanceProfile(asg)
+	if err != nil {
+		return nil, err
+	}
+	if profile == "" {
+		logger.Warning("the instances of auto scaling group %q have no instance profile, the instance role of nodegroup %q is unknown", aws.StringValue(asg.AutoScalingGroupName), ng.Name)
+		return nodeGroupOutputs, nil
+	}
+	// the instance profile of a launch configuration is either a name or an ARN
+	profileName := profile[strings.LastIndex(profile, "/")+1:]
+	output, err := a.iamAPI.GetInstanceProfile(&iam.GetInstanceProfileInput{
+		InstanceProfileName: aws.String(profileName),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting instance profile %q", profileName)
+	}
+	nodeGroupOutputs[outputs.NodeGroupInstanceProfileARN] = aws.StringValue(output.InstanceProfile.Arn)
+	if roles := output.InstanceProfile.Roles; len(roles) > 0 {
+		nodeGroupOutputs[outputs.NodeGroupInstanceRoleARN] = aws.StringValue(roles[0].Arn)
+	}
+	return nodeGroupOutputs, nil
+}
+
+// instanceProfile returns the name or ARN of the instance profile set in the launch template or
+// launch configuration of the auto scaling group
+func (a *Adopter) instanceProfile(asg *autoscaling.Group) (string, error) {
+	if lt := asg.LaunchTemplate; lt != nil {
+		output, err := a.ec2API.DescribeLaunchTemplateVersions(&ec2.DescribeLaunchTemplateVersionsInput{
+			LaunchTemplateId:   lt.LaunchTemplateId,
+			LaunchTemplateName: lt.LaunchTemplateName,
+			Versions:           []*string{lt.Version},
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "describing launch template of auto scaling group %q", aws.StringValue(asg.AutoScalingGroupName))
+		}
+		if len(output.LaunchTemplateVersions) == 0 || output.LaunchTemplateVersions[0].LaunchTemplateData.IamInstanceProfile == nil {
+			return "", nil
+		}
+		profile := output.LaunchTemplateVersions[0].LaunchTemplateData.IamInstanceProfile
+		if profile.Arn != nil {
+			return *profile.Arn, nil
+		}
+		return aws.StringValue(profile.Name), nil
+	}
+
+	output, err := a.asgAPI.DescribeLaunchConfigurations(&autoscaling.DescribeLaunchConfigurationsInput{
+		LaunchConfigurationNames: []*string{asg.LaunchConfigurationName},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "describing launch configuration of auto scaling group %q", aws.StringValue(asg.AutoScalingGroupName))
+	}
+	if len(output.LaunchConfigurations) == 0 {
+		return "", nil
+	}
+	return aws.StringValue(output.LaunchConfigurations[0].IamInstanceProfile), nil
+}
+
+func describeResources(resources []manager.ResourceToImport) string {
+	var logicalIDs []string
+	for _, r := range resources {
+		logicalIDs = append(logicalIDs, r.LogicalID)
+	}
+	return fmt.Sprintf("%d existing resource(s) %v", len(resources), logicalIDs)
+}
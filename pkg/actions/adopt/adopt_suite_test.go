@@ -0,0 +1,11 @@
+package adopt_test
+
+import (
+	"testing"
+
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+func TestAdopt(t *testing.T) {
+	testutils.RegisterAndRun(t)
+}
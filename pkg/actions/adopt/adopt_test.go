@@ -0,0 +1,201 @@
+package adopt_test
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/weaveworks/eksctl/pkg/actions/adopt"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Adopt", func() {
+	var (
+		cfg          *api.ClusterConfig
+		p            *mockprovider.MockProvider
+		stackManager *fakes.FakeStackManager
+		adopter      *adopt.Adopter
+	)
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		cfg.VPC.ID = "vpc-1"
+		cfg.VPC.Subnets = &api.ClusterSubnets{
+			Public: api.AZSubnetMapping{
+				"us-west-2a": api.AZSubnetSpec{ID: "subnet-1"},
+			},
+			Private: api.AZSubnetMapping{
+				"us-west-2a": api.AZSubnetSpec{ID: "subnet-2"},
+			},
+		}
+		cfg.IAM.ServiceRoleARN = aws.String("arn:aws:iam::123456789012:role/custom/eks-service-role")
+
+		p = mockprovider.NewMockProvider()
+		stackManager = new(fakes.FakeStackManager)
+		stackManager.DescribeClusterStackReturns(&manager.Stack{StackName: aws.String("eksctl-test-cluster")}, nil)
+		stackManager.GetStackTemplateReturns(`{"Resources": {"ControlPlane": {"Type": "AWS::EKS::Cluster"}}}`, nil)
+		stackManager.MakeChangeSetNameReturns("eksctl-adopt")
+
+		p.MockEC2().On("DescribeVpcs", mock.Anything).Return(&ec2.DescribeVpcsOutput{
+			Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-1"), CidrBlock: aws.String("10.0.0.0/16")}},
+		}, nil)
+		p.MockEC2().On("DescribeSubnets", mock.MatchedBy(func(input *ec2.DescribeSubnetsInput) bool {
+			return len(input.SubnetIds) == 2
+		})).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{
+				{SubnetId: aws.String("subnet-1"), VpcId: aws.String("vpc-1"), CidrBlock: aws.String("10.0.0.0/19"), AvailabilityZone: aws.String("us-west-2a")},
+				{SubnetId: aws.String("subnet-2"), VpcId: aws.String("vpc-1"), CidrBlock: aws.String("10.0.32.0/19"), AvailabilityZone: aws.String("us-west-2a")},
+			},
+		}, nil)
+		p.MockIAM().On("GetRole", mock.MatchedBy(func(input *iam.GetRoleInput) bool {
+			return *input.RoleName == "eks-service-role"
+		})).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{
+				RoleName:                 aws.String("eks-service-role"),
+				Path:                     aws.String("/custom/"),
+				AssumeRolePolicyDocument: aws.String("%7B%22Version%22%3A%222012-10-17%22%7D"),
+			},
+		}, nil)
+
+		adopter = adopt.New(cfg, stackManager, p.EC2(), p.IAM(), p.ASG())
+	})
+
+	It("imports the resources described in the config into the cluster stack", func() {
+		Expect(adopter.Adopt(context.Background(), false)).To(Succeed())
+
+		Expect(stackManager.ImportResourcesCallCount()).To(Equal(1))
+		_, options := stackManager.ImportResourcesArgsForCall(0)
+		Expect(options.StackName).To(Equal("eksctl-test-cluster"))
+		Expect(options.NewStack).To(BeFalse())
+		Expect(options.Plan).To(BeFalse())
+
+		logicalIDs := map[string]manager.ResourceToImport{}
+		for _, r := range options.Resources {
+			logicalIDs[r.LogicalID] = r
+		}
+		Expect(logicalIDs).To(HaveLen(4))
+		Expect(logicalIDs["VPC"].Identifier).To(Equal(map[string]string{"VpcId": "vpc-1"}))
+		Expect(logicalIDs["SubnetPublicUSWEST2A"].Identifier).To(Equal(map[string]string{"SubnetId": "subnet-1"}))
+		Expect(logicalIDs["SubnetPrivateUSWEST2A"].Properties).To(HaveKeyWithValue("CidrBlock", "10.0.32.0/19"))
+		Expect(logicalIDs["ServiceRole"].Identifier).To(Equal(map[string]string{"RoleName": "eks-service-role"}))
+		Expect(logicalIDs["ServiceRole"].Properties).To(HaveKeyWithValue("AssumeRolePolicyDocument", map[string]interface{}{"Version": "2012-10-17"}))
+	})
+
+	It("skips resources that are already part of the cluster stack", func() {
+		cfg.VPC.Subnets = nil
+		cfg.IAM.ServiceRoleARN = nil
+		stackManager.GetStackTemplateReturns(`{"Resources": {"VPC": {"Type": "AWS::EC2::VPC"}}}`, nil)
+
+		Expect(adopter.Adopt(context.Background(), true)).To(Succeed())
+		Expect(stackManager.ImportResourcesCallCount()).To(Equal(0))
+	})
+
+	It("creates nodegroup stacks for the auto scaling groups of nodegroups without a stack", func() {
+		cfg.VPC.ID = ""
+		cfg.VPC.Subnets = nil
+		cfg.IAM.ServiceRoleARN = nil
+		cfg.NodeGroups = []*api.NodeGroup{{NodeGroupBase: &api.NodeGroupBase{Name: "ng-1"}}, {NodeGroupBase: &api.NodeGroupBase{Name: "ng-2"}}}
+		stackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: "ng-2"}}, nil)
+		p.MockASG().On("DescribeAutoScalingGroups", mock.MatchedBy(func(input *autoscaling.DescribeAutoScalingGroupsInput) bool {
+			return *input.AutoScalingGroupNames[0] == "ng-1"
+		})).Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []*autoscaling.Group{{
+				AutoScalingGroupName: aws.String("ng-1"),
+				MinSize:              aws.Int64(1),
+				MaxSize:              aws.Int64(3),
+				DesiredCapacity:      aws.Int64(2),
+				VPCZoneIdentifier:    aws.String("subnet-1,subnet-2"),
+				LaunchTemplate: &autoscaling.LaunchTemplateSpecification{
+					LaunchTemplateId: aws.String("lt-1"),
+					Version:          aws.String("$Latest"),
+				},
+			}},
+		}, nil)
+		p.MockEC2().On("DescribeLaunchTemplateVersions", mock.MatchedBy(func(input *ec2.DescribeLaunchTemplateVersionsInput) bool {
+			return *input.LaunchTemplateId == "lt-1" && *input.Versions[0] == "$Latest"
+		})).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{{
+				LaunchTemplateData: &ec2.ResponseLaunchTemplateData{
+					IamInstanceProfile: &ec2.LaunchTemplateIamInstanceProfileSpecification{
+						Arn: aws.String("arn:aws:iam::123456789012:instance-profile/ng-1-profile"),
+					},
+				},
+			}},
+		}, nil)
+		p.MockIAM().On("GetInstanceProfile", mock.MatchedBy(func(input *iam.GetInstanceProfileInput) bool {
+			return *input.InstanceProfileName == "ng-1-profile"
+		})).Return(&iam.GetInstanceProfileOutput{
+			InstanceProfile: &iam.InstanceProfile{
+				Arn:   aws.String("arn:aws:iam::123456789012:instance-profile/ng-1-profile"),
+				Roles: []*iam.Role{{Arn: aws.String("arn:aws:iam::123456789012:role/ng-1-role")}},
+			},
+		}, nil)
+
+		Expect(adopter.Adopt(context.Background(), true)).To(Succeed())
+
+		Expect(stackManager.ImportResourcesCallCount()).To(Equal(1))
+		_, options := stackManager.ImportResourcesArgsForCall(0)
+		Expect(options.StackName).To(Equal("eksctl-test-nodegroup-ng-1"))
+		Expect(options.NewStack).To(BeTrue())
+		Expect(options.Plan).To(BeTrue())
+		Expect(options.Tags).To(HaveKeyWithValue(api.NodeGroupNameTag, "ng-1"))
+		Expect(options.Tags).To(HaveKeyWithValue(api.OldNodeGroupNameTag, "ng-1"))
+		Expect(options.Tags).To(HaveKeyWithValue(api.NodeGroupTypeTag, "unmanaged"))
+		Expect(options.Outputs).To(Equal(map[string]string{
+			outputs.NodeGroupInstanceRoleARN:            "arn:aws:iam::123456789012:role/ng-1-role",
+			outputs.NodeGroupInstanceProfileARN:         "arn:aws:iam::123456789012:instance-profile/ng-1-profile",
+			outputs.NodeGroupFeaturePrivateNetworking:   "false",
+			outputs.NodeGroupFeatureSharedSecurityGroup: "true",
+			outputs.NodeGroupFeatureLocalSecurityGroup:  "false",
+		}))
+		Expect(options.Resources).To(HaveLen(1))
+		Expect(options.Resources[0].LogicalID).To(Equal("NodeGroup"))
+		Expect(options.Resources[0].Properties).To(HaveKeyWithValue("VPCZoneIdentifier", []string{"subnet-1", "subnet-2"}))
+		Expect(options.Resources[0].Properties).To(HaveKeyWithValue("DesiredCapacity", "2"))
+		Expect(options.Resources[0].Properties).To(HaveKeyWithValue("LaunchTemplate", map[string]interface{}{"LaunchTemplateId": "lt-1", "Version": "$Latest"}))
+	})
+
+	It("creates the cluster stack with the resources described in the config when the cluster has no stack", func() {
+		cfg.VPC.SecurityGroup = "sg-1"
+		stackManager.DescribeClusterStackReturns(nil, nil)
+		stackManager.MakeClusterStackNameReturns("eksctl-test-cluster")
+		p.MockEC2().On("DescribeSecurityGroups", mock.Anything).Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*ec2.SecurityGroup{{GroupId: aws.String("sg-1"), GroupName: aws.String("control-plane"), Description: aws.String("control plane"), VpcId: aws.String("vpc-1")}},
+		}, nil)
+
+		Expect(adopter.Adopt(context.Background(), true)).To(Succeed())
+
+		Expect(stackManager.GetStackTemplateCallCount()).To(Equal(0))
+		Expect(stackManager.ImportResourcesCallCount()).To(Equal(1))
+		_, options := stackManager.ImportResourcesArgsForCall(0)
+		Expect(options.StackName).To(Equal("eksctl-test-cluster"))
+		Expect(options.NewStack).To(BeTrue())
+		Expect(options.Plan).To(BeTrue())
+		Expect(options.Resources).To(HaveLen(5))
+		Expect(options.Outputs).To(Equal(map[string]string{
+			outputs.ClusterStackName:      "eksctl-test-cluster",
+			outputs.ClusterVPC:            "vpc-1",
+			outputs.ClusterSecurityGroup:  "sg-1",
+			outputs.ClusterSubnetsPublic:  "subnet-1",
+			outputs.ClusterSubnetsPrivate: "subnet-2",
+			outputs.ClusterServiceRoleARN: "arn:aws:iam::123456789012:role/custom/eks-service-role",
+		}))
+	})
+
+	It("fails to create the cluster stack when the control plane security group is not set", func() {
+		stackManager.DescribeClusterStackReturns(nil, nil)
+		Expect(adopter.Adopt(context.Background(), false)).To(MatchError(ContainSubstring("vpc.id and vpc.securityGroup must be set")))
+		Expect(stackManager.ImportResourcesCallCount()).To(Equal(0))
+	})
+})
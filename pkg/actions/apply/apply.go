@@ -0,0 +1,173 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+
+	actionsaddon "github.com/weaveworks/eksctl/pkg/actions/addon"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
+)
+
+// Options controls how apply reconciles the cluster
+type Options struct {
+	// Plan only prints the changes that would be made
+	Plan bool
+	// Prune deletes resources that exist but are not declared in the config
+	Prune bool
+}
+
+// Manager reconciles the live state of a cluster with its ClusterConfig
+type Manager struct {
+	cfg          *api.ClusterConfig
+	ctl          *eks.ClusterProvider
+	stackManager manager.StackManager
+	clientSet    kubernetes.Interface
+}
+
+// New creates a new apply manager
+func New(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, clientSet kubernetes.Interface) *Manager {
+	return &Manager{
+		cfg:          cfg,
+		ctl:          ctl,
+		stackManager: ctl.NewStackManager(cfg),
+		clientSet:    clientSet,
+	}
+}
+
+// Apply diffs the ClusterConfig against the live cluster and runs the tasks needed to reconcile them
+func (m *Manager) Apply(ctx context.Context, options Options) error {
+	state, err := m.getLiveState()
+	if err != nil {
+		return errors.Wrapf(err, "getting current state of cluster %q", m.cfg.Metadata.Name)
+	}
+
+	var oidc *iamoidc.OpenIDConnectManager
+	if len(m.cfg.IAM.ServiceAccounts) > 0 {
+		oidc, err = m.ctl.NewOpenIDConnectManager(m.cfg)
+		if err != nil {
+			return err
+		}
+		providerExists, err := oidc.CheckProviderExists()
+		if err != nil {
+			return err
+		}
+		if !providerExists {
+			logger.Warning("no IAM OIDC provider associated with cluster, try 'eksctl utils associate-iam-oidc-provider --region=%s --cluster=%s'", m.cfg.Metadata.Region, m.cfg.Metadata.Name)
+			return errors.New("unable to reconcile iamserviceaccount(s) without IAM OIDC provider enabled")
+		}
+	}
+
+	serviceAccountTemplates, err := m.renderServiceAccountTemplates(state, oidc)
+	if err != nil {
+		return err
+	}
+	addonTemplates, err := m.renderAddonTemplates(state)
+	if err != nil {
+		return err
+	}
+
+	plan := Diff(m.cfg, state, DesiredTemplates{
+		ServiceAccounts: serviceAccountTemplates,
+		Addons:          addonTemplates,
+	}, options.Prune)
+	if plan.IsEmpty() {
+		logger.Info("cluster %q is up to date, no changes required", m.cfg.Metadata.Name)
+		return nil
+	}
+
+	for _, change := range plan.Changes() {
+		if change.Action == ActionUnsupported {
+			logger.Warning("%s", change)
+			continue
+		}
+		logger.Info("%s", change)
+	}
+	unsupported := len(plan.Unsupported())
+	if unsupported > 0 {
+		logger.Warning("%d difference(s) marked with '!' cannot be reconciled by apply, as they require replacing the nodegroup; "+
+			"create a new nodegroup and delete the old one, or use `eksctl upgrade nodegroup` or `eksctl set labels` to change them", unsupported)
+	}
+	toApply := len(plan.Changes()) - unsupported
+	if toApply == 0 {
+		return nil
+	}
+
+	taskTree, err := m.newTasks(ctx, plan, oidc, state)
+	if err != nil {
+		return err
+	}
+	taskTree.PlanMode = options.Plan
+
+	logger.Info(taskTree.Describe())
+	if errs := taskTree.DoAllSyncWithContext(ctx); len(errs) > 0 {
+		logger.Info("%d error(s) occurred while applying changes to cluster %q", len(errs), m.cfg.Metadata.Name)
+		for _, err := range errs {
+			logger.Critical("%s\n", err.Error())
+		}
+		return fmt.Errorf("failed to apply config to cluster %q", m.cfg.Metadata.Name)
+	}
+
+	if options.Plan {
+		logger.Warning("no changes were applied, run again without '--plan' to apply the changes")
+		return nil
+	}
+	logger.Success("applied %d change(s) to cluster %q", toApply, m.cfg.Metadata.Name)
+	return nil
+}
+
+// renderServiceAccountTemplates renders the templates of the declared iamserviceaccounts whose stack
+// already exists, so that they can be compared with the templates of their stack
+func (m *Manager) renderServiceAccountTemplates(state *LiveState, oidc *iamoidc.OpenIDConnectManager) (map[string]string, error) {
+	templates := map[string]string{}
+	for _, sa := range m.cfg.IAM.ServiceAccounts {
+		if sa.AttachRoleARN != "" || !state.ServiceAccounts.Has(sa.NameString()) {
+			continue
+		}
+		rs := builder.NewIAMRoleResourceSetForServiceAccount(sa, oidc)
+		if err := rs.AddAllResources(); err != nil {
+			return nil, err
+		}
+		template, err := rs.RenderJSON()
+		if err != nil {
+			return nil, errors.Wrapf(err, "rendering template of iamserviceaccount %q", sa.NameString())
+		}
+		templates[sa.NameString()] = string(template)
+	}
+	return templates, nil
+}
+
+// renderAddonTemplates renders the templates of the IAM roles of the declared addons with policies set
+// whose stack already exists, so that they can be compared with the templates of their stack
+func (m *Manager) renderAddonTemplates(state *LiveState) (map[string]string, error) {
+	var toRender []*api.Addon
+	for _, addon := range m.cfg.Addons {
+		if _, ok := state.AddonTemplates[addon.Name]; ok && addon.ServiceAccountRoleARN == "" && actionsaddon.HasPoliciesSet(addon) {
+			toRender = append(toRender, addon)
+		}
+	}
+	if len(toRender) == 0 {
+		return nil, nil
+	}
+
+	addonManager, err := m.newAddonManager()
+	if err != nil {
+		return nil, err
+	}
+	templates := map[string]string{}
+	for _, addon := range toRender {
+		template, err := addonManager.RoleTemplate(addon)
+		if err != nil {
+			return nil, errors.Wrapf(err, "rendering template of addon %q", addon.Name)
+		}
+		templates[addon.Name] = string(template)
+	}
+	return templates, nil
+}
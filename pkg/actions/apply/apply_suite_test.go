@@ -0,0 +1,11 @@
+package apply_test
+
+import (
+	"testing"
+
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+func TestApply(t *testing.T) {
+	testutils.RegisterAndRun(t)
+}
@@ -0,0 +1,417 @@
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/weaveworks/eksctl/pkg/actions/identityproviders"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// Action is the kind of change that apply makes to a resource
+type Action string
+
+const (
+	// ActionCreate creates a resource that is declared in the config but does not exist
+	ActionCreate Action = "create"
+	// ActionUpdate updates a resource that exists but differs from the config
+	ActionUpdate Action = "update"
+	// ActionDelete deletes a resource that exists but is not declared in the config
+	ActionDelete Action = "delete"
+	// ActionUnsupported reports a difference that apply cannot reconcile, e.g. a new instance type
+	// for an existing nodegroup, which requires replacing the nodegroup
+	ActionUnsupported Action = "unsupported"
+)
+
+const eksBuildSuffix = "-eksbuild."
+
+// Change describes a single difference between the ClusterConfig and the live cluster
+type Change struct {
+	Action   Action
+	Resource string
+	Name     string
+	Details  string
+}
+
+func (c Change) String() string {
+	symbol := map[Action]string{
+		ActionCreate:      "+",
+		ActionUpdate:      "~",
+		ActionDelete:      "-",
+		ActionUnsupported: "!",
+	}[c.Action]
+	msg := fmt.Sprintf("%s %s %q", symbol, c.Resource, c.Name)
+	if c.Details != "" {
+		msg = fmt.Sprintf("%s (%s)", msg, c.Details)
+	}
+	return msg
+}
+
+// Plan holds all changes required to bring a cluster in line with its ClusterConfig
+type Plan struct {
+	NodeGroupsToCreate        []*api.NodeGroup
+	ManagedNodeGroupsToCreate []*api.ManagedNodeGroup
+	NodeGroupsToDelete        []string
+	NodeGroupsToMap           []*api.NodeGroup
+	NodeGroupsToScale         []*api.NodeGroupBase
+
+	ServiceAccountsToCreate []*api.ClusterIAMServiceAccount
+	ServiceAccountsToUpdate []*api.ClusterIAMServiceAccount
+	ServiceAccountsToDelete []string
+
+	AddonsToCreate []*api.Addon
+	AddonsToUpdate []*api.Addon
+	AddonsToDelete []string
+
+	FargateProfilesToCreate []*api.FargateProfile
+	FargateProfilesToDelete []string
+
+	IdentityProvidersToAssociate    []api.IdentityProvider
+	IdentityProvidersToDisassociate []identityproviders.DisassociateIdentityProvider
+
+	UpdateLogging   bool
+	UpdateEndpoints bool
+
+	changes []Change
+}
+
+// Changes returns every change in the plan, in the order they were found
+func (p *Plan) Changes() []Change {
+	return p.changes
+}
+
+// Unsupported returns the differences in the plan that apply cannot reconcile
+func (p *Plan) Unsupported() []Change {
+	var unsupported []Change
+	for _, c := range p.changes {
+		if c.Action == ActionUnsupported {
+			unsupported = append(unsupported, c)
+		}
+	}
+	return unsupported
+}
+
+// IsEmpty returns true when the live cluster already matches the config
+func (p *Plan) IsEmpty() bool {
+	return len(p.changes) == 0
+}
+
+func (p *Plan) record(action Action, resource, name, detailsFmt string, args ...interface{}) {
+	p.changes = append(p.changes, Change{
+		Action:   action,
+		Resource: resource,
+		Name:     name,
+		Details:  fmt.Sprintf(detailsFmt, args...),
+	})
+}
+
+// DesiredTemplates holds the templates rendered from the config for the stacks that are only
+// updated when their template differs from the one of the existing stack
+type DesiredTemplates struct {
+	// ServiceAccounts maps the `namespace/name` of iamserviceaccounts to the template of their IAM role
+	ServiceAccounts map[string]string
+	// Addons maps the names of addons with policies set to the template of their IAM role
+	Addons map[string]string
+}
+
+// Diff compares the ClusterConfig with the live state of the cluster and returns
+// the plan that reconciles them; resources that exist but are not declared are only
+// scheduled for deletion when prune is true
+func Diff(cfg *api.ClusterConfig, state *LiveState, templates DesiredTemplates, prune bool) *Plan {
+	p := &Plan{}
+	diffNodeGroups(p, cfg, state, prune)
+	diffServiceAccounts(p, cfg, state, templates.ServiceAccounts, prune)
+	diffAddons(p, cfg, state, templates.Addons, prune)
+	diffFargateProfiles(p, cfg, state, prune)
+	diffIdentityProviders(p, cfg, state, prune)
+	diffClusterSettings(p, cfg, state)
+	return p
+}
+
+// diffNodeGroups compares which nodegroups exist, whether their instance role is mapped and their scaling;
+// the other fields of existing nodegroups that differ are reported as unsupported, as most of them require
+// replacing the nodegroup
+func diffNodeGroups(p *Plan, cfg *api.ClusterConfig, state *LiveState, prune bool) {
+	declared := sets.NewString()
+
+	for _, ng := range cfg.NodeGroups {
+		declared.Insert(ng.Name)
+		if _, ok := state.NodeGroups[ng.Name]; !ok {
+			p.NodeGroupsToCreate = append(p.NodeGroupsToCreate, ng)
+			p.record(ActionCreate, "nodegroup", ng.Name, "")
+			continue
+		}
+		if roleARN, ok := state.NodeGroupRoles[ng.Name]; ok && !state.MappedRoleARNs.Has(roleARN) {
+			// the role is set on a copy so that computing a plan leaves the config untouched
+			toMap := ng.DeepCopy()
+			if toMap.IAM == nil {
+				toMap.IAM = &api.NodeGroupIAM{}
+			}
+			toMap.IAM.InstanceRoleARN = roleARN
+			p.NodeGroupsToMap = append(p.NodeGroupsToMap, toMap)
+			p.record(ActionUpdate, "nodegroup", ng.Name, "map instance role %s in aws-auth ConfigMap", roleARN)
+		}
+		if summary, ok := state.NodeGroupSummaries[ng.Name]; ok {
+			p.recordScaling("nodegroup", ng.NodeGroupBase, scalingChanges(ng.NodeGroupBase, summary.MinSize, summary.MaxSize, summary.DesiredCapacity))
+
+			var unsupported []string
+			if ng.InstanceType != "" && ng.InstanceType != "mixed" && summary.InstanceType != "" && ng.InstanceType != summary.InstanceType {
+				unsupported = append(unsupported, fmt.Sprintf("instanceType %s -> %s", summary.InstanceType, ng.InstanceType))
+			}
+			if api.IsAMI(ng.AMI) && api.IsAMI(summary.ImageID) && ng.AMI != summary.ImageID {
+				unsupported = append(unsupported, fmt.Sprintf("ami %s -> %s", summary.ImageID, ng.AMI))
+			}
+			p.recordUnsupported("nodegroup", ng.Name, unsupported)
+		}
+	}
+
+	for _, ng := range cfg.ManagedNodeGroups {
+		declared.Insert(ng.Name)
+		if _, ok := state.NodeGroups[ng.Name]; !ok {
+			p.ManagedNodeGroupsToCreate = append(p.ManagedNodeGroupsToCreate, ng)
+			p.record(ActionCreate, "managed nodegroup", ng.Name, "")
+			continue
+		}
+		live, ok := state.ManagedNodeGroups[ng.Name]
+		if !ok {
+			continue
+		}
+		if scaling := live.ScalingConfig; scaling != nil {
+			p.recordScaling("managed nodegroup", ng.NodeGroupBase, scalingChanges(ng.NodeGroupBase,
+				int(aws.Int64Value(scaling.MinSize)), int(aws.Int64Value(scaling.MaxSize)), int(aws.Int64Value(scaling.DesiredSize))))
+		}
+
+		var unsupported []string
+		desiredTypes := ng.InstanceTypes
+		if ng.InstanceType != "" {
+			desiredTypes = []string{ng.InstanceType}
+		}
+		// the instance types are not set on managed nodegroups that take them from a launch template
+		if currentTypes := aws.StringValueSlice(live.InstanceTypes); len(desiredTypes) > 0 && len(currentTypes) > 0 && !sets.NewString(desiredTypes...).Equal(sets.NewString(currentTypes...)) {
+			unsupported = append(unsupported, fmt.Sprintf("instanceTypes [%s] -> [%s]", strings.Join(currentTypes, ", "), strings.Join(desiredTypes, ", ")))
+		}
+		// eksctl sets labels of its own on managed nodegroups, so only the declared labels are compared
+		currentLabels := aws.StringValueMap(live.Labels)
+		for _, key := range sets.StringKeySet(ng.Labels).List() {
+			if current, ok := currentLabels[key]; !ok || current != ng.Labels[key] {
+				unsupported = append(unsupported, fmt.Sprintf("label %s=%q -> %q", key, current, ng.Labels[key]))
+			}
+		}
+		p.recordUnsupported("managed nodegroup", ng.Name, unsupported)
+	}
+
+	if !prune {
+		return
+	}
+	for _, name := range sets.StringKeySet(state.NodeGroups).List() {
+		if !declared.Has(name) {
+			p.NodeGroupsToDelete = append(p.NodeGroupsToDelete, name)
+			p.record(ActionDelete, "nodegroup", name, "%s", state.NodeGroups[name])
+		}
+	}
+}
+
+func diffServiceAccounts(p *Plan, cfg *api.ClusterConfig, state *LiveState, serviceAccountTemplates map[string]string, prune bool) {
+	for _, sa := range cfg.IAM.ServiceAccounts {
+		name := sa.NameString()
+		if !state.ServiceAccounts.Has(name) {
+			p.ServiceAccountsToCreate = append(p.ServiceAccountsToCreate, sa)
+			p.record(ActionCreate, "iamserviceaccount", name, "")
+			continue
+		}
+		desired, ok := serviceAccountTemplates[name]
+		if sa.AttachRoleARN == "" && ok && !templatesEqual(desired, state.ServiceAccountTemplates[name]) {
+			p.ServiceAccountsToUpdate = append(p.ServiceAccountsToUpdate, sa)
+			p.record(ActionUpdate, "iamserviceaccount", name, "reconcile IAM role stack")
+		}
+	}
+
+	if !prune {
+		return
+	}
+	// the iamserviceaccounts created implicitly by eksctl (i.e. aws-node with withOIDC) and those
+	// owned by an addon are managed along with the cluster and the addon, and are never pruned
+	declared := sets.NewString()
+	for _, sa := range api.IAMServiceAccountsWithImplicitServiceAccounts(cfg) {
+		declared.Insert(sa.NameString())
+	}
+	for _, name := range state.ServiceAccounts.List() {
+		if !declared.Has(name) && !state.AddonServiceAccounts.Has(name) {
+			p.ServiceAccountsToDelete = append(p.ServiceAccountsToDelete, name)
+			p.record(ActionDelete, "iamserviceaccount", name, "")
+		}
+	}
+}
+
+func diffAddons(p *Plan, cfg *api.ClusterConfig, state *LiveState, addonTemplates map[string]string, prune bool) {
+	declared := sets.NewString()
+
+	for _, addon := range cfg.Addons {
+		declared.Insert(addon.Name)
+		currentVersion, ok := state.Addons[addon.Name]
+		if !ok {
+			p.AddonsToCreate = append(p.AddonsToCreate, addon)
+			p.record(ActionCreate, "addon", addon.Name, "")
+			continue
+		}
+		var changes []string
+		// "latest" can only be resolved against the EKS API, so it is left to `update addon`
+		if addon.Version != "" && addon.Version != "latest" && !addonVersionMatches(addon.Version, currentVersion) {
+			changes = append(changes, fmt.Sprintf("%s -> %s", currentVersion, addon.Version))
+		}
+		if currentRole := state.AddonServiceAccountRoles[addon.Name]; addon.ServiceAccountRoleARN != "" && addon.ServiceAccountRoleARN != currentRole {
+			changes = append(changes, fmt.Sprintf("serviceAccountRoleARN %q -> %q", currentRole, addon.ServiceAccountRoleARN))
+		}
+		if desired, ok := addonTemplates[addon.Name]; ok && !templatesEqual(desired, state.AddonTemplates[addon.Name]) {
+			changes = append(changes, "reconcile IAM role stack")
+		}
+		if len(changes) > 0 {
+			p.AddonsToUpdate = append(p.AddonsToUpdate, addon)
+			p.record(ActionUpdate, "addon", addon.Name, "%s", strings.Join(changes, ", "))
+		}
+	}
+
+	if !prune {
+		return
+	}
+	for _, name := range sets.StringKeySet(state.Addons).List() {
+		if !declared.Has(name) {
+			p.AddonsToDelete = append(p.AddonsToDelete, name)
+			p.record(ActionDelete, "addon", name, "")
+		}
+	}
+}
+
+func diffFargateProfiles(p *Plan, cfg *api.ClusterConfig, state *LiveState, prune bool) {
+	declared := sets.NewString()
+
+	for _, profile := range cfg.FargateProfiles {
+		declared.Insert(profile.Name)
+		if !state.FargateProfiles.Has(profile.Name) {
+			p.FargateProfilesToCreate = append(p.FargateProfilesToCreate, profile)
+			p.record(ActionCreate, "fargateprofile", profile.Name, "")
+		}
+	}
+
+	if !prune {
+		return
+	}
+	for _, name := range state.FargateProfiles.List() {
+		if !declared.Has(name) {
+			p.FargateProfilesToDelete = append(p.FargateProfilesToDelete, name)
+			p.record(ActionDelete, "fargateprofile", name, "")
+		}
+	}
+}
+
+func diffIdentityProviders(p *Plan, cfg *api.ClusterConfig, state *LiveState, prune bool) {
+	declared := sets.NewString()
+
+	for _, idp := range cfg.IdentityProviders {
+		oidc, ok := idp.Inner.(*api.OIDCIdentityProvider)
+		if !ok {
+			continue
+		}
+		declared.Insert(oidc.Name)
+		if _, ok := state.IdentityProviders[oidc.Name]; !ok {
+			p.IdentityProvidersToAssociate = append(p.IdentityProvidersToAssociate, idp)
+			p.record(ActionCreate, "identityprovider", oidc.Name, "associate")
+		}
+	}
+
+	if !prune {
+		return
+	}
+	for _, name := range sets.StringKeySet(state.IdentityProviders).List() {
+		if !declared.Has(name) {
+			p.IdentityProvidersToDisassociate = append(p.IdentityProvidersToDisassociate, identityproviders.DisassociateIdentityProvider{
+				Name: name,
+				Type: state.IdentityProviders[name],
+			})
+			p.record(ActionDelete, "identityprovider", name, "disassociate")
+		}
+	}
+}
+
+func diffClusterSettings(p *Plan, cfg *api.ClusterConfig, state *LiveState) {
+	// an empty ClusterLogging is set by default, so logging is only reconciled when enableTypes is set
+	if cfg.CloudWatch != nil && cfg.CloudWatch.ClusterLogging != nil && cfg.CloudWatch.ClusterLogging.EnableTypes != nil {
+		desired := sets.NewString(cfg.CloudWatch.ClusterLogging.EnableTypes...)
+		if !desired.Equal(state.EnabledLogTypes) {
+			p.UpdateLogging = true
+			p.record(ActionUpdate, "cloudwatch logging", cfg.Metadata.Name, "enabled types [%s] -> [%s]",
+				strings.Join(state.EnabledLogTypes.List(), ", "), strings.Join(desired.List(), ", "))
+		}
+	}
+
+	if cfg.VPC != nil && cfg.VPC.ClusterEndpoints != nil && state.ClusterEndpoints != nil {
+		desired, current := cfg.VPC.ClusterEndpoints, state.ClusterEndpoints
+		if differs(desired.PrivateAccess, current.PrivateAccess) || differs(desired.PublicAccess, current.PublicAccess) {
+			p.UpdateEndpoints = true
+			p.record(ActionUpdate, "endpoint access", cfg.Metadata.Name, "private=%t public=%t -> private=%t public=%t",
+				api.IsEnabled(current.PrivateAccess), api.IsEnabled(current.PublicAccess),
+				api.IsEnabled(desired.PrivateAccess), api.IsEnabled(desired.PublicAccess))
+		}
+	}
+}
+
+// scalingChanges returns the scaling fields that are set on a nodegroup and differ from the current ones
+func scalingChanges(ng *api.NodeGroupBase, minSize, maxSize, desiredCapacity int) []string {
+	var changes []string
+	compare := func(field string, desired *int, current int) {
+		if desired != nil && *desired != current {
+			changes = append(changes, fmt.Sprintf("%s %d -> %d", field, current, *desired))
+		}
+	}
+	compare("minSize", ng.MinSize, minSize)
+	compare("maxSize", ng.MaxSize, maxSize)
+	compare("desiredCapacity", ng.DesiredCapacity, desiredCapacity)
+	return changes
+}
+
+func (p *Plan) recordScaling(resource string, ng *api.NodeGroupBase, changes []string) {
+	if len(changes) == 0 {
+		return
+	}
+	p.NodeGroupsToScale = append(p.NodeGroupsToScale, ng)
+	p.record(ActionUpdate, resource, ng.Name, "scale %s", strings.Join(changes, ", "))
+}
+
+func (p *Plan) recordUnsupported(resource, name string, changes []string) {
+	if len(changes) == 0 {
+		return
+	}
+	p.record(ActionUnsupported, resource, name, "%s", strings.Join(changes, ", "))
+}
+
+// differs returns true when desired is set and does not match current
+func differs(desired, current *bool) bool {
+	return desired != nil && api.IsEnabled(desired) != api.IsEnabled(current)
+}
+
+// addonVersionMatches returns true when the installed version of an addon is the desired one;
+// the eksbuild suffix of the installed version is ignored unless the desired version has one
+func addonVersionMatches(desired, current string) bool {
+	desired, current = strings.TrimPrefix(desired, "v"), strings.TrimPrefix(current, "v")
+	if !strings.Contains(desired, eksBuildSuffix) {
+		current = strings.SplitN(current, eksBuildSuffix, 2)[0]
+	}
+	return desired == current
+}
+
+// templatesEqual compares two JSON templates structurally, so that the order of the keys does not matter
+func templatesEqual(a, b string) bool {
+	var aValue, bValue interface{}
+	if err := json.Unmarshal([]byte(a), &aValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(b), &bValue); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
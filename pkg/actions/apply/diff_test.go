@@ -0,0 +1,263 @@
+package apply_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/actions/apply"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+)
+
+var _ = Describe("Diff", func() {
+	var (
+		cfg   *api.ClusterConfig
+		state *apply.LiveState
+	)
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "cluster-1"
+		state = apply.NewLiveState()
+	})
+
+	It("returns an empty plan when the cluster matches the config", func() {
+		ng := cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		state.NodeGroups["ng-1"] = api.NodeGroupTypeUnmanaged
+
+		plan := apply.Diff(cfg, state, apply.DesiredTemplates{}, true)
+		Expect(plan.IsEmpty()).To(BeTrue())
+	})
+
+	It("creates resources that are declared but do not exist", func() {
+		ng := cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		mng := api.NewManagedNodeGroup()
+		mng.Name = "mng-1"
+		cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, mng)
+		cfg.IAM.ServiceAccounts = []*api.ClusterIAMServiceAccount{{
+			ClusterIAMMeta: api.ClusterIAMMeta{Name: "sa-1", Namespace: "default"},
+		}}
+		cfg.Addons = []*api.Addon{{Name: "vpc-cni"}}
+		cfg.FargateProfiles = []*api.FargateProfile{{Name: "fp-1"}}
+
+		plan := apply.Diff(cfg, state, apply.DesiredTemplates{}, false)
+		Expect(plan.NodeGroupsToCreate).To(ConsistOf(ng))
+		Expect(plan.ManagedNodeGroupsToCreate).To(ConsistOf(mng))
+		Expect(plan.ServiceAccountsToCreate).To(HaveLen(1))
+		Expect(plan.AddonsToCreate).To(HaveLen(1))
+		Expect(plan.FargateProfilesToCreate).To(HaveLen(1))
+		Expect(plan.Changes()).To(HaveLen(5))
+		Expect(plan.Changes()[0].String()).To(Equal(`+ nodegroup "ng-1"`))
+	})
+
+	It("updates addons whose version differs", func() {
+		cfg.Addons = []*api.Addon{{Name: "vpc-cni", Version: "v1.9.0"}, {Name: "coredns", Version: "latest"}}
+		state.Addons["vpc-cni"] = "v1.8.0-eksbuild.1"
+		state.Addons["coredns"] = "v1.8.0-eksbuild.1"
+
+		plan := apply.Diff(cfg, state, apply.DesiredTemplates{}, false)
+		Expect(plan.AddonsToUpdate).To(HaveLen(1))
+		Expect(plan.AddonsToUpdate[0].Name).To(Equal("vpc-cni"))
+		Expect(plan.Changes()[0].String()).To(Equal(`~ addon "vpc-cni" (v1.8.0-eksbuild.1 -> v1.9.0)`))
+	})
+
+	It("compares addon versions exactly, ignoring the eksbuild suffix unless it is declared", func() {
+		cfg.Addons = []*api.Addon{{Name: "vpc-cni", Version: "v1.1"}, {Name: "coredns", Version: "v1.8.4"}, {Name: "kube-proxy", Version: "v1.21.2-eksbuild.2"}}
+		state.Addons["vpc-cni"] = "v1.10.1-eksbuild.1"
+		state.Addons["coredns"] = "v1.8.4-eksbuild.1"
+		state.Addons["kube-proxy"] = "v1.21.2-eksbuild.2"
+
+		plan := apply.Diff(cfg, state, apply.DesiredTemplates{}, false)
+		Expect(plan.AddonsToUpdate).To(HaveLen(1))
+		Expect(plan.AddonsToUpdate[0].Name).To(Equal("vpc-cni"))
+	})
+
+	It("only updates iamserviceaccounts whose template differs from the one of their stack", func() {
+		cfg.IAM.ServiceAccounts = []*api.ClusterIAMServiceAccount{
+			{ClusterIAMMeta: api.ClusterIAMMeta{Name: "sa-1", Namespace: "default"}},
+			{ClusterIAMMeta: api.ClusterIAMMeta{Name: "sa-2", Namespace: "default"}},
+			{ClusterIAMMeta: api.ClusterIAMMeta{Name: "sa-3", Namespace: "default"}, AttachRoleARN: "arn:aws:iam::123456789012:role/sa-3"},
+		}
+		state.ServiceAccounts.Insert("default/sa-1", "default/sa-2", "default/sa-3")
+		state.ServiceAccountTemplates["default/sa-1"] = `{"Resources": {"Role1": {"Type": "AWS::IAM::Role"}}, "Description": "sa-1"}`
+		state.ServiceAccountTemplates["default/sa-2"] = `{"Description": "sa-2", "Resources": {}}`
+
+		plan := apply.Diff(cfg, state, apply.DesiredTemplates{
+			ServiceAccounts: map[string]string{
+				"default/sa-1": `{"Description": "sa-1", "Resources": {"Role1": {"Type": "AWS::IAM::Role"}}}`,
+				"default/sa-2": `{"Description": "sa-2", "Resources": {"Role1": {"Type": "AWS::IAM::Role"}}}`,
+			},
+		}, false)
+		Expect(plan.ServiceAccountsToUpdate).To(HaveLen(1))
+		Expect(plan.ServiceAccountsToUpdate[0].Name).To(Equal("sa-2"))
+		Expect(plan.Changes()).To(HaveLen(1))
+	})
+
+	It("maps nodegroup instance roles that are missing from aws-auth", func() {
+		ng := cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		state.NodeGroups["ng-1"] = api.NodeGroupTypeUnmanaged
+		state.NodeGroupRoles["ng-1"] = "arn:aws:iam::123456789012:role/ng-1"
+
+		plan := apply.Diff(cfg, state, apply.DesiredTemplates{}, false)
+		Expect(plan.NodeGroupsToMap).To(HaveLen(1))
+		Expect(plan.NodeGroupsToMap[0].Name).To(Equal("ng-1"))
+		Expect(plan.NodeGroupsToMap[0].IAM.InstanceRoleARN).To(Equal("arn:aws:iam::123456789012:role/ng-1"))
+		By("leaving the config untouched")
+		Expect(ng.IAM.InstanceRoleARN).To(BeEmpty())
+
+		state.MappedRoleARNs.Insert("arn:aws:iam::123456789012:role/ng-1")
+		Expect(apply.Diff(cfg, state, apply.DesiredTemplates{}, false).IsEmpty()).To(BeTrue())
+	})
+
+	It("updates addons whose service account role or IAM role template differs", func() {
+		cfg.Addons = []*api.Addon{
+			{Name: "vpc-cni", AttachPolicyARNs: []string{"arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy"}},
+			{Name: "coredns", ServiceAccountRoleARN: "arn:aws:iam::123456789012:role/coredns"},
+			{Name: "kube-proxy", AttachPolicyARNs: []string{"arn:aws:iam::aws:policy/kube-proxy"}},
+		}
+		state.Addons["vpc-cni"] = "v1.10.1-eksbuild.1"
+		state.Addons["coredns"] = "v1.8.4-eksbuild.1"
+		state.Addons["kube-proxy"] = "v1.21.2-eksbuild.2"
+		state.AddonServiceAccountRoles["coredns"] = "arn:aws:iam::123456789012:role/old"
+		state.AddonTemplates["vpc-cni"] = `{"Resources": {"Role1": {"Properties": {"ManagedPolicyArns": []}}}}`
+		state.AddonTemplates["kube-proxy"] = `{"Resources": {"Role1": {"Properties": {"ManagedPolicyArns": ["arn:aws:iam::aws:policy/kube-proxy"]}}}}`
+
+		plan := apply.Diff(cfg, state, apply.DesiredTemplates{
+			Addons: map[string]string{
+				"vpc-cni":    `{"Resources": {"Role1": {"Properties": {"ManagedPolicyArns": ["arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy"]}}}}`,
+				"kube-proxy": `{"Resources": {"Role1": {"Properties": {"ManagedPolicyArns": ["arn:aws:iam::aws:policy/kube-proxy"]}}}}`,
+			},
+		}, false)
+		Expect(plan.AddonsToUpdate).To(HaveLen(2))
+		Expect(plan.Changes()[0].String()).To(Equal(`~ addon "vpc-cni" (reconcile IAM role stack)`))
+		Expect(plan.Changes()[1].String()).To(Equal(`~ addon "coredns" (serviceAccountRoleARN "arn:aws:iam::123456789012:role/old" -> "arn:aws:iam::123456789012:role/coredns")`))
+	})
+
+	Context("existing nodegroups", func() {
+		var (
+			ng  *api.NodeGroup
+			mng *api.ManagedNodeGroup
+		)
+
+		BeforeEach(func() {
+			ng = cfg.NewNodeGroup()
+			ng.Name = "ng-1"
+			mng = api.NewManagedNodeGroup()
+			mng.Name = "mng-1"
+			cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, mng)
+			state.NodeGroups["ng-1"] = api.NodeGroupTypeUnmanaged
+			state.NodeGroups["mng-1"] = api.NodeGroupTypeManaged
+			state.NodeGroupSummaries["ng-1"] = &manager.NodeGroupSummary{
+				Name:            "ng-1",
+				MinSize:         1,
+				MaxSize:         3,
+				DesiredCapacity: 2,
+				InstanceType:    api.DefaultNodeType,
+				ImageID:         "ami-1",
+			}
+			state.ManagedNodeGroups["mng-1"] = &awseks.Nodegroup{
+				ScalingConfig: &awseks.NodegroupScalingConfig{MinSize: aws.Int64(1), MaxSize: aws.Int64(3), DesiredSize: aws.Int64(2)},
+				InstanceTypes: aws.StringSlice([]string{"m5.large"}),
+				Labels:        aws.StringMap(map[string]string{"alpha.eksctl.io/nodegroup-name": "mng-1", "role": "worker"}),
+			}
+		})
+
+		It("leaves nodegroups whose declared fields match alone", func() {
+			ng.DesiredCapacity = aws.Int(2)
+			ng.AMI = "ami-1"
+			mng.MaxSize = aws.Int(3)
+			mng.InstanceTypes = []string{"m5.large"}
+			mng.Labels = map[string]string{"role": "worker"}
+
+			Expect(apply.Diff(cfg, state, apply.DesiredTemplates{}, false).IsEmpty()).To(BeTrue())
+		})
+
+		It("scales nodegroups whose declared scaling differs", func() {
+			ng.DesiredCapacity = aws.Int(3)
+			mng.MinSize = aws.Int(2)
+			mng.MaxSize = aws.Int(5)
+
+			plan := apply.Diff(cfg, state, apply.DesiredTemplates{}, false)
+			Expect(plan.NodeGroupsToScale).To(ConsistOf(ng.NodeGroupBase, mng.NodeGroupBase))
+			Expect(plan.Unsupported()).To(BeEmpty())
+			Expect(plan.Changes()[0].String()).To(Equal(`~ nodegroup "ng-1" (scale desiredCapacity 2 -> 3)`))
+			Expect(plan.Changes()[1].String()).To(Equal(`~ managed nodegroup "mng-1" (scale minSize 1 -> 2, maxSize 3 -> 5)`))
+		})
+
+		It("reports the other declared fields that differ as unsupported changes", func() {
+			ng.InstanceType = "m5.xlarge"
+			ng.AMI = "ami-2"
+			mng.InstanceType = "c5.large"
+			mng.Labels = map[string]string{"role": "batch", "team": "a"}
+
+			plan := apply.Diff(cfg, state, apply.DesiredTemplates{}, false)
+			Expect(plan.NodeGroupsToScale).To(BeEmpty())
+			Expect(plan.Unsupported()).To(HaveLen(2))
+			Expect(plan.Changes()[0].String()).To(Equal(`! nodegroup "ng-1" (instanceType m5.large -> m5.xlarge, ami ami-1 -> ami-2)`))
+			Expect(plan.Changes()[1].String()).To(Equal(`! managed nodegroup "mng-1" (instanceTypes [m5.large] -> [c5.large], label role="worker" -> "batch", label team="" -> "a")`))
+		})
+	})
+
+	Context("resources that exist but are not declared", func() {
+		BeforeEach(func() {
+			state.NodeGroups["ng-old"] = api.NodeGroupTypeManaged
+			state.ServiceAccounts.Insert("default/sa-old")
+			state.Addons["kube-proxy"] = "v1.21.2-eksbuild.2"
+			state.FargateProfiles.Insert("fp-old")
+			state.IdentityProviders["idp-old"] = api.OIDCIdentityProviderType
+		})
+
+		It("leaves them alone without prune", func() {
+			Expect(apply.Diff(cfg, state, apply.DesiredTemplates{}, false).IsEmpty()).To(BeTrue())
+		})
+
+		It("deletes them with prune", func() {
+			plan := apply.Diff(cfg, state, apply.DesiredTemplates{}, true)
+			Expect(plan.NodeGroupsToDelete).To(ConsistOf("ng-old"))
+			Expect(plan.ServiceAccountsToDelete).To(ConsistOf("default/sa-old"))
+			Expect(plan.AddonsToDelete).To(ConsistOf("kube-proxy"))
+			Expect(plan.FargateProfilesToDelete).To(ConsistOf("fp-old"))
+			Expect(plan.IdentityProvidersToDisassociate).To(HaveLen(1))
+			Expect(plan.IdentityProvidersToDisassociate[0].Name).To(Equal("idp-old"))
+			for _, change := range plan.Changes() {
+				Expect(change.Action).To(Equal(apply.ActionDelete))
+			}
+		})
+
+		It("does not prune the iamserviceaccounts managed by eksctl or by an addon", func() {
+			cfg.IAM.WithOIDC = api.Enabled()
+			state.ServiceAccounts.Insert("kube-system/aws-node", "kube-system/ebs-csi-controller-sa")
+			state.AddonServiceAccounts.Insert("kube-system/ebs-csi-controller-sa")
+
+			plan := apply.Diff(cfg, state, apply.DesiredTemplates{}, true)
+			Expect(plan.ServiceAccountsToDelete).To(ConsistOf("default/sa-old"))
+		})
+	})
+
+	Context("cluster settings", func() {
+		It("updates logging only when it is declared", func() {
+			state.EnabledLogTypes.Insert("api")
+			Expect(apply.Diff(cfg, state, apply.DesiredTemplates{}, false).UpdateLogging).To(BeFalse())
+
+			cfg.CloudWatch = &api.ClusterCloudWatch{ClusterLogging: &api.ClusterCloudWatchLogging{EnableTypes: []string{"api", "audit"}}}
+			Expect(apply.Diff(cfg, state, apply.DesiredTemplates{}, false).UpdateLogging).To(BeTrue())
+
+			state.EnabledLogTypes.Insert("audit")
+			Expect(apply.Diff(cfg, state, apply.DesiredTemplates{}, false).UpdateLogging).To(BeFalse())
+		})
+
+		It("updates endpoint access when it differs", func() {
+			state.ClusterEndpoints = &api.ClusterEndpoints{PrivateAccess: api.Disabled(), PublicAccess: api.Enabled()}
+			cfg.VPC.ClusterEndpoints = &api.ClusterEndpoints{PrivateAccess: api.Enabled()}
+			Expect(apply.Diff(cfg, state, apply.DesiredTemplates{}, false).UpdateEndpoints).To(BeTrue())
+
+			cfg.VPC.ClusterEndpoints = &api.ClusterEndpoints{PrivateAccess: api.Disabled(), PublicAccess: api.Enabled()}
+			Expect(apply.Diff(cfg, state, apply.DesiredTemplates{}, false).UpdateEndpoints).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,218 @@
+package apply
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/weaveworks/eksctl/pkg/actions/identityproviders"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/authconfigmap"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/fargate"
+)
+
+// LiveState holds the resources that currently exist for a cluster
+type LiveState struct {
+	// NodeGroups maps nodegroup names to their type, as found in nodegroup stacks
+	NodeGroups map[string]api.NodeGroupType
+	// NodeGroupRoles maps unmanaged nodegroup names to their instance role ARN
+	NodeGroupRoles map[string]string
+	// NodeGroupSummaries maps unmanaged nodegroup names to their summary, which holds the scaling of their auto scaling group
+	NodeGroupSummaries map[string]*manager.NodeGroupSummary
+	// ManagedNodeGroups maps the names of the declared managed nodegroups that exist to their description from the EKS API
+	ManagedNodeGroups map[string]*awseks.Nodegroup
+	// ServiceAccounts holds the `namespace/name` of every iamserviceaccount stack
+	ServiceAccounts sets.String
+	// AddonServiceAccounts holds the `namespace/name` of the iamserviceaccount stacks owned by an EKS addon
+	AddonServiceAccounts sets.String
+	// ServiceAccountTemplates maps the `namespace/name` of the declared iamserviceaccounts to the template of their stack
+	ServiceAccountTemplates map[string]string
+	// Addons maps EKS addon names to their installed version
+	Addons map[string]string
+	// AddonServiceAccountRoles maps EKS addon names to the ARN of the IAM role of their service account
+	AddonServiceAccountRoles map[string]string
+	// AddonTemplates maps the names of the declared addons to the template of their IAM role stack
+	AddonTemplates map[string]string
+	// FargateProfiles holds the names of all Fargate profiles
+	FargateProfiles sets.String
+	// IdentityProviders maps identity provider names to their type
+	IdentityProviders map[string]api.IdentityProviderType
+	// EnabledLogTypes holds the CloudWatch log types currently enabled
+	EnabledLogTypes sets.String
+	// ClusterEndpoints holds the current API server endpoint access
+	ClusterEndpoints *api.ClusterEndpoints
+	// MappedRoleARNs holds the role ARNs present in the aws-auth ConfigMap
+	MappedRoleARNs sets.String
+}
+
+// NewLiveState returns an empty LiveState
+func NewLiveState() *LiveState {
+	return &LiveState{
+		NodeGroups:               map[string]api.NodeGroupType{},
+		NodeGroupRoles:           map[string]string{},
+		NodeGroupSummaries:       map[string]*manager.NodeGroupSummary{},
+		ManagedNodeGroups:        map[string]*awseks.Nodegroup{},
+		ServiceAccounts:          sets.NewString(),
+		AddonServiceAccounts:     sets.NewString(),
+		ServiceAccountTemplates:  map[string]string{},
+		Addons:                   map[string]string{},
+		AddonServiceAccountRoles: map[string]string{},
+		AddonTemplates:           map[string]string{},
+		FargateProfiles:          sets.NewString(),
+		IdentityProviders:        map[string]api.IdentityProviderType{},
+		EnabledLogTypes:          sets.NewString(),
+		MappedRoleARNs:           sets.NewString(),
+	}
+}
+
+// getLiveState gathers the current state of the cluster from CloudFormation, the EKS API
+// and the aws-auth ConfigMap
+func (m *Manager) getLiveState() (*LiveState, error) {
+	state := NewLiveState()
+	clusterName := m.cfg.Metadata.Name
+
+	nodeGroupStacks, err := m.stackManager.ListNodeGroupStacks()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing nodegroup stacks")
+	}
+	for _, s := range nodeGroupStacks {
+		state.NodeGroups[s.NodeGroupName] = s.Type
+	}
+
+	summaries, err := m.stackManager.GetUnmanagedNodeGroupSummaries("")
+	if err != nil {
+		return nil, errors.Wrap(err, "getting nodegroup summaries")
+	}
+	for _, s := range summaries {
+		state.NodeGroupSummaries[s.Name] = s
+		if s.NodeInstanceRoleARN != "" {
+			state.NodeGroupRoles[s.Name] = s.NodeInstanceRoleARN
+		}
+	}
+
+	eksAPI := m.ctl.Provider.EKS()
+	for _, ng := range m.cfg.ManagedNodeGroups {
+		if state.NodeGroups[ng.Name] != api.NodeGroupTypeManaged {
+			continue
+		}
+		output, err := eksAPI.DescribeNodegroup(&awseks.DescribeNodegroupInput{
+			ClusterName:   &clusterName,
+			NodegroupName: aws.String(ng.Name),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "describing managed nodegroup %q", ng.Name)
+		}
+		state.ManagedNodeGroups[ng.Name] = output.Nodegroup
+	}
+
+	serviceAccountStacks, err := m.stackManager.DescribeIAMServiceAccountStacks()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing iamserviceaccount stacks")
+	}
+	declaredServiceAccounts := sets.NewString()
+	for _, sa := range m.cfg.IAM.ServiceAccounts {
+		declaredServiceAccounts.Insert(sa.NameString())
+	}
+	for _, s := range serviceAccountStacks {
+		name := manager.GetIAMServiceAccountName(s)
+		state.ServiceAccounts.Insert(name)
+		if m.stackManager.GetIAMAddonName(s) != "" {
+			state.AddonServiceAccounts.Insert(name)
+		}
+		// the templates are only compared for the declared iamserviceaccounts
+		if declaredServiceAccounts.Has(name) {
+			template, err := m.stackManager.GetStackTemplate(*s.StackName)
+			if err != nil {
+				return nil, errors.Wrapf(err, "getting template of stack %q", *s.StackName)
+			}
+			state.ServiceAccountTemplates[name] = template
+		}
+	}
+
+	addons, err := eksAPI.ListAddons(&awseks.ListAddonsInput{
+		ClusterName: &clusterName,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing addons")
+	}
+	for _, name := range addons.Addons {
+		output, err := eksAPI.DescribeAddon(&awseks.DescribeAddonInput{
+			ClusterName: &clusterName,
+			AddonName:   name,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "describing addon %q", *name)
+		}
+		state.Addons[*name] = aws.StringValue(output.Addon.AddonVersion)
+		if roleARN := aws.StringValue(output.Addon.ServiceAccountRoleArn); roleARN != "" {
+			state.AddonServiceAccountRoles[*name] = roleARN
+		}
+	}
+
+	addonStacks, err := m.stackManager.GetIAMAddonsStacks()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing addon IAM stacks")
+	}
+	declaredAddons := sets.NewString()
+	for _, addon := range m.cfg.Addons {
+		declaredAddons.Insert(addon.Name)
+	}
+	for _, s := range addonStacks {
+		// the templates are only compared for the declared addons
+		name := m.stackManager.GetIAMAddonName(s)
+		if !declaredAddons.Has(name) {
+			continue
+		}
+		template, err := m.stackManager.GetStackTemplate(*s.StackName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting template of stack %q", *s.StackName)
+		}
+		state.AddonTemplates[name] = template
+	}
+
+	fargateClient := fargate.NewFromProvider(clusterName, m.ctl.Provider, m.stackManager)
+	profiles, err := fargateClient.ListProfiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing Fargate profiles")
+	}
+	state.FargateProfiles.Insert(aws.StringValueSlice(profiles)...)
+
+	idpManager := identityproviders.NewManager(*m.cfg.Metadata, eksAPI)
+	idps, err := idpManager.Get(identityproviders.GetIdentityProvidersOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing identity providers")
+	}
+	for _, idp := range idps {
+		state.IdentityProviders[idp.Name] = idp.Type
+	}
+
+	enabled, _, err := m.ctl.GetCurrentClusterConfigForLogging(m.cfg)
+	if err != nil {
+		return nil, err
+	}
+	state.EnabledLogTypes = enabled
+
+	vpcConfig, err := m.ctl.GetCurrentClusterVPCConfig(m.cfg)
+	if err != nil {
+		return nil, err
+	}
+	state.ClusterEndpoints = vpcConfig.ClusterEndpoints
+
+	acm, err := authconfigmap.NewFromClientSet(m.clientSet)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := acm.GetIdentities()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading aws-auth ConfigMap")
+	}
+	for _, identity := range identities {
+		state.MappedRoleARNs.Insert(identity.ARN())
+	}
+
+	logger.Debug("live state = %#v", state)
+	return state, nil
+}
@@ -0,0 +1,295 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	actionsaddon "github.com/weaveworks/eksctl/pkg/actions/addon"
+	actionsfargate "github.com/weaveworks/eksctl/pkg/actions/fargate"
+	"github.com/weaveworks/eksctl/pkg/actions/identityproviders"
+	"github.com/weaveworks/eksctl/pkg/actions/irsa"
+	"github.com/weaveworks/eksctl/pkg/actions/nodegroup"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/authconfigmap"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/fargate"
+	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
+)
+
+// newTasks builds a single task tree out of the plan; creates and updates run before
+// deletes, and each kind of resource is handled in its own sub-task so that dependencies
+// (e.g. addons before nodegroups) are respected
+func (m *Manager) newTasks(ctx context.Context, plan *Plan, oidc *iamoidc.OpenIDConnectManager, state *LiveState) (*tasks.TaskTree, error) {
+	taskTree := &tasks.TaskTree{Parallel: false}
+
+	appendSubTasks := func(subTasks ...tasks.Task) {
+		if len(subTasks) == 0 {
+			return
+		}
+		taskTree.Append(&tasks.TaskTree{
+			Tasks:     subTasks,
+			Parallel:  false,
+			IsSubTask: true,
+		})
+	}
+
+	appendSubTasks(m.clusterSettingsTasks(plan)...)
+
+	if len(plan.IdentityProvidersToAssociate) > 0 {
+		appendSubTasks(identityproviders.NewAssociateProvidersTask(*m.cfg.Metadata, plan.IdentityProvidersToAssociate, m.ctl.Provider.EKS()))
+	}
+
+	addonTasks, err := m.addonTasks(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+	appendSubTasks(addonTasks...)
+
+	serviceAccountTasks, err := m.serviceAccountTasks(plan, oidc)
+	if err != nil {
+		return nil, err
+	}
+	appendSubTasks(serviceAccountTasks...)
+
+	appendSubTasks(m.nodeGroupTasks(ctx, plan)...)
+	appendSubTasks(m.fargateTasks(plan)...)
+
+	deleteTasks, err := m.deleteTasks(ctx, plan, state)
+	if err != nil {
+		return nil, err
+	}
+	appendSubTasks(deleteTasks...)
+
+	return taskTree, nil
+}
+
+func (m *Manager) clusterSettingsTasks(plan *Plan) []tasks.Task {
+	var settingsTasks []tasks.Task
+	if plan.UpdateLogging {
+		settingsTasks = append(settingsTasks, &tasks.GenericTask{
+			Description: "update CloudWatch logging configuration",
+			Doer: func() error {
+				return m.ctl.UpdateClusterConfigForLogging(m.cfg)
+			},
+		})
+	}
+	if plan.UpdateEndpoints {
+		settingsTasks = append(settingsTasks, &tasks.GenericTask{
+			Description: "update Kubernetes API endpoint access",
+			Doer: func() error {
+				return m.ctl.UpdateClusterConfigForEndpoints(m.cfg)
+			},
+		})
+	}
+	return settingsTasks
+}
+
+func (m *Manager) newAddonManager() (*actionsaddon.Manager, error) {
+	oidc, err := m.ctl.NewOpenIDConnectManager(m.cfg)
+	if err != nil {
+		return nil, err
+	}
+	oidcProviderExists, err := oidc.CheckProviderExists()
+	if err != nil {
+		return nil, err
+	}
+	return actionsaddon.New(m.cfg, m.ctl.Provider.EKS(), m.stackManager, oidcProviderExists, oidc, m.clientSet, m.ctl.Provider.WaitTimeout())
+}
+
+func (m *Manager) addonTasks(ctx context.Context, plan *Plan) ([]tasks.Task, error) {
+	if len(plan.AddonsToCreate) == 0 && len(plan.AddonsToUpdate) == 0 {
+		return nil, nil
+	}
+	addonManager, err := m.newAddonManager()
+	if err != nil {
+		return nil, err
+	}
+
+	var addonTasks []tasks.Task
+	for _, a := range plan.AddonsToCreate {
+		a := a
+		addonTasks = append(addonTasks, &tasks.GenericTask{
+			Description: fmt.Sprintf("create addon %q", a.Name),
+			Doer: func() error {
+				return addonManager.Create(ctx, a, true)
+			},
+		})
+	}
+	for _, a := range plan.AddonsToUpdate {
+		a := a
+		addonTasks = append(addonTasks, &tasks.GenericTask{
+			Description: fmt.Sprintf("update addon %q", a.Name),
+			Doer: func() error {
+				return addonManager.Update(ctx, a, true)
+			},
+		})
+	}
+	return addonTasks, nil
+}
+
+func (m *Manager) serviceAccountTasks(plan *Plan, oidc *iamoidc.OpenIDConnectManager) ([]tasks.Task, error) {
+	var saTasks []tasks.Task
+	if len(plan.ServiceAccountsToCreate) > 0 {
+		saTasks = append(saTasks, m.stackManager.NewTasksToCreateIAMServiceAccounts(plan.ServiceAccountsToCreate, oidc, kubernetes.NewCachedClientSet(m.clientSet)))
+	}
+	for _, sa := range plan.ServiceAccountsToUpdate {
+		updateTask, err := irsa.NewUpdateIAMServiceAccountTask(m.cfg.Metadata.Name, sa, m.stackManager, oidc, false)
+		if err != nil {
+			return nil, err
+		}
+		saTasks = append(saTasks, updateTask)
+	}
+	return saTasks, nil
+}
+
+func (m *Manager) nodeGroupTasks(ctx context.Context, plan *Plan) []tasks.Task {
+	var ngTasks []tasks.Task
+	if len(plan.NodeGroupsToCreate) > 0 || len(plan.ManagedNodeGroupsToCreate) > 0 {
+		cfg := m.cfg.DeepCopy()
+		cfg.NodeGroups = plan.NodeGroupsToCreate
+		cfg.ManagedNodeGroups = plan.ManagedNodeGroupsToCreate
+		ngTasks = append(ngTasks, &tasks.GenericTask{
+			Description: fmt.Sprintf("create %d nodegroup(s) and %d managed nodegroup(s)", len(cfg.NodeGroups), len(cfg.ManagedNodeGroups)),
+			Doer: func() error {
+				return nodegroup.New(cfg, m.ctl, m.clientSet).Create(ctx, nodegroup.CreateOpts{
+					UpdateAuthConfigMap: true,
+					ConfigFileProvided:  true,
+				}, filter.NewNodeGroupFilter())
+			},
+		})
+	}
+	for _, ng := range plan.NodeGroupsToMap {
+		ng := ng
+		ngTasks = append(ngTasks, &tasks.GenericTask{
+			Description: fmt.Sprintf("map instance role of nodegroup %q in aws-auth ConfigMap", ng.Name),
+			Doer: func() error {
+				return authconfigmap.AddNodeGroup(m.clientSet, ng)
+			},
+		})
+	}
+	for _, ng := range plan.NodeGroupsToScale {
+		ng := ng
+		ngTasks = append(ngTasks, &tasks.GenericTask{
+			Description: fmt.Sprintf("scale nodegroup %q", ng.Name),
+			Doer: func() error {
+				return nodegroup.New(m.cfg, m.ctl, m.clientSet).Scale(ctx, ng)
+			},
+		})
+	}
+	return ngTasks
+}
+
+func (m *Manager) fargateTasks(plan *Plan) []tasks.Task {
+	if len(plan.FargateProfilesToCreate) == 0 {
+		return nil
+	}
+	cfg := m.cfg.DeepCopy()
+	cfg.FargateProfiles = plan.FargateProfilesToCreate
+	return []tasks.Task{&tasks.GenericTask{
+		Description: fmt.Sprintf("create %d Fargate profile(s)", len(cfg.FargateProfiles)),
+		Doer: func() error {
+			return actionsfargate.New(cfg, m.ctl, m.stackManager).Create()
+		},
+	}}
+}
+
+func (m *Manager) deleteTasks(ctx context.Context, plan *Plan, state *LiveState) ([]tasks.Task, error) {
+	var deleteTasks []tasks.Task
+
+	for _, idp := range plan.IdentityProvidersToDisassociate {
+		idp := idp
+		deleteTasks = append(deleteTasks, &tasks.GenericTask{
+			Description: fmt.Sprintf("disassociate identity provider %q", idp.Name),
+			Doer: func() error {
+				timeout := m.ctl.Provider.WaitTimeout()
+				idpManager := identityproviders.NewManager(*m.cfg.Metadata, m.ctl.Provider.EKS())
+				return idpManager.Disassociate(ctx, identityproviders.DisassociateIdentityProvidersOptions{
+					Providers:   []identityproviders.DisassociateIdentityProvider{idp},
+					WaitTimeout: &timeout,
+				})
+			},
+		})
+	}
+
+	if len(plan.FargateProfilesToDelete) > 0 {
+		fargateClient := fargate.NewFromProvider(m.cfg.Metadata.Name, m.ctl.Provider, m.stackManager)
+		for _, name := range plan.FargateProfilesToDelete {
+			name := name
+			deleteTasks = append(deleteTasks, &tasks.GenericTask{
+				Description: fmt.Sprintf("delete Fargate profile %q", name),
+				Doer: func() error {
+					return fargateClient.DeleteProfile(name, true)
+				},
+			})
+		}
+	}
+
+	if len(plan.AddonsToDelete) > 0 {
+		addonManager, err := m.newAddonManager()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range plan.AddonsToDelete {
+			name := name
+			deleteTasks = append(deleteTasks, &tasks.GenericTask{
+				Description: fmt.Sprintf("delete addon %q", name),
+				Doer: func() error {
+					return addonManager.Delete(&api.Addon{Name: name})
+				},
+			})
+		}
+	}
+
+	if len(plan.ServiceAccountsToDelete) > 0 {
+		saTasks, err := m.stackManager.NewTasksToDeleteIAMServiceAccounts(plan.ServiceAccountsToDelete, kubernetes.NewCachedClientSet(m.clientSet), true)
+		if err != nil {
+			return nil, err
+		}
+		deleteTasks = append(deleteTasks, saTasks)
+	}
+
+	if len(plan.NodeGroupsToDelete) > 0 {
+		toDelete := map[string]bool{}
+		nodeGroupManager := nodegroup.New(m.cfg, m.ctl, m.clientSet)
+		for _, name := range plan.NodeGroupsToDelete {
+			toDelete[name] = true
+			// nodes are drained before their stack is deleted, as `delete nodegroup` does
+			drainInput := &nodegroup.DrainInput{
+				NodeGroups:     []eks.KubeNodeGroup{kubeNodeGroup(name, state.NodeGroups[name])},
+				MaxGracePeriod: m.ctl.Provider.WaitTimeout(),
+			}
+			deleteTasks = append(deleteTasks, &tasks.GenericTask{
+				Description: fmt.Sprintf("drain nodegroup %q", name),
+				Doer: func() error {
+					return nodeGroupManager.Drain(drainInput)
+				},
+			})
+			if roleARN, ok := state.NodeGroupRoles[name]; ok {
+				ng := &api.NodeGroup{NodeGroupBase: &api.NodeGroupBase{Name: name, IAM: &api.NodeGroupIAM{InstanceRoleARN: roleARN}}}
+				deleteTasks = append(deleteTasks, &tasks.GenericTask{
+					Description: fmt.Sprintf("remove instance role of nodegroup %q from aws-auth ConfigMap", name),
+					Doer: func() error {
+						return authconfigmap.RemoveNodeGroup(m.clientSet, ng)
+					},
+				})
+			}
+		}
+		ngTasks, err := m.stackManager.NewTasksToDeleteNodeGroups(func(name string) bool { return toDelete[name] }, true, false, nil)
+		if err != nil {
+			return nil, err
+		}
+		deleteTasks = append(deleteTasks, ngTasks)
+	}
+
+	return deleteTasks, nil
+}
+
+func kubeNodeGroup(name string, ngType api.NodeGroupType) eks.KubeNodeGroup {
+	if ngType == api.NodeGroupTypeManaged {
+		return &api.ManagedNodeGroup{NodeGroupBase: &api.NodeGroupBase{Name: name}}
+	}
+	return &api.NodeGroup{NodeGroupBase: &api.NodeGroupBase{Name: name}}
+}
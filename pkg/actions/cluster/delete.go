@@ -134,6 +134,16 @@ func deleteDeprecatedStacks(stackManager manager.StackManager) (bool, error) {
 	return false, nil
 }
 
+// checkTerminationProtection refuses to delete the cluster when any of its stacks
+// has termination protection enabled, before anything is drained or deleted
+func checkTerminationProtection(stackManager manager.StackManager) error {
+	stacks, err := stackManager.DescribeStacks()
+	if err != nil {
+		return err
+	}
+	return manager.CheckTerminationProtection(stacks...)
+}
+
 func checkForUndeletedStacks(stackManager manager.StackManager) error {
 	stacks, err := stackManager.DescribeStacks()
 	if err != nil {
@@ -163,16 +173,31 @@ func drainAllNodegroups(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, stackM
 		return nil
 	}
 
+	// keep the drain policies of the nodegroups defined in the config file, if any
+	drainPolicies := map[string]*api.DrainPolicy{}
+	for _, ng := range cfg.NodeGroups {
+		drainPolicies[ng.Name] = ng.DrainPolicy
+	}
+
 	cfg.NodeGroups = []*api.NodeGroup{}
 	for _, s := range allStacks {
 		if s.Type == api.NodeGroupTypeUnmanaged {
 			cmdutils.PopulateUnmanagedNodegroup(s.NodeGroupName, cfg)
+			drainPolicy, ok := drainPolicies[s.NodeGroupName]
+			if !ok {
+				drainPolicy = drainPolicies[s.PerAZGroup]
+			}
+			cfg.NodeGroups[len(cfg.NodeGroups)-1].DrainPolicy = drainPolicy
 		}
 	}
 
 	logger.Info("will drain %d unmanaged nodegroup(s) in cluster %q", len(cfg.NodeGroups), cfg.Metadata.Name)
 	nodeGroupManager := nodegroup.New(cfg, ctl, clientSet)
-	if err := nodeGroupManager.Drain(cmdutils.ToKubeNodeGroups(cfg), false, ctl.Provider.WaitTimeout(), false); err != nil {
+	drainInput := &nodegroup.DrainInput{
+		NodeGroups:     cmdutils.ToKubeNodeGroups(cfg),
+		MaxGracePeriod: ctl.Provider.WaitTimeout(),
+	}
+	if err := nodeGroupManager.Drain(drainInput); err != nil {
 		return err
 	}
 	attemptVpcCniDeletion(cfg.Metadata.Name, ctl, clientSet)
@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"context"
 	"time"
 
 	"github.com/kris-nova/logger"
@@ -34,7 +35,7 @@ func NewOwnedCluster(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, clusterSt
 	}
 }
 
-func (c *OwnedCluster) Upgrade(dryRun bool) error {
+func (c *OwnedCluster) Upgrade(ctx context.Context, dryRun bool) error {
 	if err := vpc.UseFromClusterStack(c.ctl.Provider, c.clusterStack, c.cfg); err != nil {
 		return errors.Wrapf(err, "getting VPC configuration for cluster %q", c.cfg.Metadata.Name)
 	}
@@ -53,7 +54,7 @@ func (c *OwnedCluster) Upgrade(dryRun bool) error {
 		return err
 	}
 
-	stackUpdateRequired, err := c.stackManager.AppendNewClusterStackResource(dryRun, supportsManagedNodes)
+	stackUpdateRequired, err := c.stackManager.AppendNewClusterStackResource(ctx, dryRun, supportsManagedNodes)
 	if err != nil {
 		return err
 	}
@@ -67,12 +68,24 @@ func (c *OwnedCluster) Upgrade(dryRun bool) error {
 	return nil
 }
 
-func (c *OwnedCluster) Delete(_ time.Duration, wait, force bool) error {
+func (c *OwnedCluster) Delete(ctx context.Context, _ time.Duration, wait, force, disableProtection bool) error {
 	var (
 		clientSet kubernetes.Interface
 		oidc      *iamoidc.OpenIDConnectManager
 	)
 
+	// the stacks also record the buckets their templates were uploaded to, so they are listed before being deleted
+	stacks, err := c.stackManager.DescribeStacks()
+	if err != nil {
+		return err
+	}
+
+	if !disableProtection {
+		if err := manager.CheckTerminationProtection(stacks...); err != nil {
+			return err
+		}
+	}
+
 	clusterOperable, err := c.ctl.CanOperate(c.cfg)
 	if err != nil {
 		logger.Debug("failed to check if cluster is operable: %v", err)
@@ -121,7 +134,7 @@ func (c *OwnedCluster) Delete(_ time.Duration, wait, force bool) error {
 	}
 
 	deleteOIDCProvider := clusterOperable && oidcSupported
-	tasks, err := c.stackManager.NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider, oidc, kubernetes.NewCachedClientSet(clientSet), wait, func(errs chan error, _ string) error {
+	tasks, err := c.stackManager.NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider, oidc, kubernetes.NewCachedClientSet(clientSet), wait, disableProtection, func(errs chan error, _ string) error {
 		logger.Info("trying to cleanup dangling network interfaces")
 		if err := c.ctl.LoadClusterVPC(c.cfg, c.stackManager); err != nil {
 			return errors.Wrapf(err, "getting VPC configuration for cluster %q", c.cfg.Metadata.Name)
@@ -144,7 +157,7 @@ func (c *OwnedCluster) Delete(_ time.Duration, wait, force bool) error {
 	}
 
 	logger.Info(tasks.Describe())
-	if errs := tasks.DoAllSync(); len(errs) > 0 {
+	if errs := tasks.DoAllSyncWithContext(ctx); len(errs) > 0 {
 		return handleErrors(errs, "cluster with nodegroup(s)")
 	}
 
@@ -152,6 +165,10 @@ func (c *OwnedCluster) Delete(_ time.Duration, wait, force bool) error {
 		return err
 	}
 
+	if err := c.stackManager.DeleteUploadedTemplates(stacks); err != nil {
+		logger.Warning("failed to delete the CloudFormation templates uploaded to S3 for the cluster: %v", err)
+	}
+
 	logger.Success("all cluster resources were deleted")
 
 	return nil
@@ -1,6 +1,7 @@
 package cluster_test
 
 import (
+	"context"
 	"time"
 
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
@@ -10,9 +11,11 @@ import (
 
 	"github.com/weaveworks/eksctl/pkg/utils/tasks"
 
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
 
 	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
 	. "github.com/onsi/ginkgo"
@@ -91,6 +94,12 @@ var _ = Describe("Delete", func() {
 				}}},
 			}, nil)
 
+			clusterStacks := []*manager.Stack{{
+				StackName: aws.String("eksctl-my-cluster-cluster"),
+				Tags:      []*cfn.Tag{{Key: aws.String(api.TemplateBucketTag), Value: aws.String("templates")}},
+			}}
+			fakeStackManager.DescribeStacksReturnsOnCall(0, clusterStacks, nil)
+
 			c := cluster.NewOwnedCluster(cfg, ctl, nil, fakeStackManager)
 			fakeClientSet = fake.NewSimpleClientset()
 
@@ -98,12 +107,14 @@ var _ = Describe("Delete", func() {
 				return fakeClientSet, nil
 			})
 
-			err := c.Delete(time.Microsecond, false, false)
+			err := c.Delete(context.Background(), time.Microsecond, false, false, false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeStackManager.DeleteTasksForDeprecatedStacksCallCount()).To(Equal(1))
 			Expect(ranDeleteDeprecatedTasks).To(BeTrue())
 			Expect(fakeStackManager.NewTasksToDeleteClusterWithNodeGroupsCallCount()).To(Equal(1))
 			Expect(ranDeleteClusterTasks).To(BeTrue())
+			Expect(fakeStackManager.DeleteUploadedTemplatesCallCount()).To(Equal(1))
+			Expect(fakeStackManager.DeleteUploadedTemplatesArgsForCall(0)).To(Equal(clusterStacks))
 		})
 	})
 
@@ -137,7 +148,7 @@ var _ = Describe("Delete", func() {
 
 			c := cluster.NewOwnedCluster(cfg, ctl, nil, fakeStackManager)
 
-			err := c.Delete(time.Microsecond, false, false)
+			err := c.Delete(context.Background(), time.Microsecond, false, false, false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeStackManager.DeleteTasksForDeprecatedStacksCallCount()).To(Equal(1))
 			Expect(ranDeleteDeprecatedTasks).To(BeTrue())
@@ -145,4 +156,21 @@ var _ = Describe("Delete", func() {
 			Expect(ranDeleteClusterTasks).To(BeTrue())
 		})
 	})
+
+	Context("when stacks have termination protection enabled", func() {
+		BeforeEach(func() {
+			fakeStackManager.DescribeStacksReturns([]*manager.Stack{{
+				StackName:                   aws.String("eksctl-my-cluster-cluster"),
+				EnableTerminationProtection: aws.Bool(true),
+			}}, nil)
+		})
+
+		It("refuses to delete the cluster", func() {
+			c := cluster.NewOwnedCluster(cfg, ctl, nil, fakeStackManager)
+
+			err := c.Delete(context.Background(), time.Microsecond, false, false, false)
+			Expect(err).To(MatchError(ContainSubstring("use --disable-protection")))
+			Expect(fakeStackManager.NewTasksToDeleteClusterWithNodeGroupsCallCount()).To(Equal(0))
+		})
+	})
 })
@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -46,7 +47,7 @@ func NewUnownedCluster(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, stackMa
 	}
 }
 
-func (c *UnownedCluster) Upgrade(dryRun bool) error {
+func (c *UnownedCluster) Upgrade(_ context.Context, dryRun bool) error {
 	versionUpdateRequired, err := upgrade(c.cfg, c.ctl, dryRun)
 	if err != nil {
 		return err
@@ -57,13 +58,19 @@ func (c *UnownedCluster) Upgrade(dryRun bool) error {
 	return nil
 }
 
-func (c *UnownedCluster) Delete(waitInterval time.Duration, wait, force bool) error {
+func (c *UnownedCluster) Delete(ctx context.Context, waitInterval time.Duration, wait, force, disableProtection bool) error {
 	clusterName := c.cfg.Metadata.Name
 
 	if err := c.checkClusterExists(clusterName); err != nil {
 		return err
 	}
 
+	if !disableProtection {
+		if err := checkTerminationProtection(c.stackManager); err != nil {
+			return err
+		}
+	}
+
 	clusterOperable, err := c.ctl.CanOperate(c.cfg)
 	if err != nil {
 		logger.Debug("failed to check if cluster is operable: %v", err)
@@ -102,11 +109,11 @@ func (c *UnownedCluster) Delete(waitInterval time.Duration, wait, force bool) er
 
 	// we have to wait for nodegroups to delete before deleting the cluster
 	// so the `wait` value is ignored here
-	if err := c.deleteAndWaitForNodegroupsDeletion(waitInterval, allStacks); err != nil {
+	if err := c.deleteAndWaitForNodegroupsDeletion(ctx, waitInterval, allStacks, disableProtection); err != nil {
 		return err
 	}
 
-	if err := c.deleteIAMAndOIDC(wait, clusterOperable, clientSet); err != nil {
+	if err := c.deleteIAMAndOIDC(ctx, wait, clusterOperable, clientSet); err != nil {
 		if err != nil {
 			if force {
 				logger.Warning("error occurred during deletion: %v", err)
@@ -116,7 +123,7 @@ func (c *UnownedCluster) Delete(waitInterval time.Duration, wait, force bool) er
 		}
 	}
 
-	if err := c.deleteCluster(wait); err != nil {
+	if err := c.deleteCluster(ctx, wait); err != nil {
 		return err
 	}
 
@@ -157,7 +164,7 @@ func (c *UnownedCluster) checkClusterExists(clusterName string) error {
 	return nil
 }
 
-func (c *UnownedCluster) deleteIAMAndOIDC(wait bool, clusterOperable bool, clientSet kubernetes.Interface) error {
+func (c *UnownedCluster) deleteIAMAndOIDC(ctx context.Context, wait bool, clusterOperable bool, clientSet kubernetes.Interface) error {
 	var oidc *iamoidc.OpenIDConnectManager
 	oidcSupported := true
 
@@ -203,7 +210,7 @@ func (c *UnownedCluster) deleteIAMAndOIDC(wait bool, clusterOperable bool, clien
 	}
 
 	logger.Info(tasksTree.Describe())
-	if errs := tasksTree.DoAllSync(); len(errs) > 0 {
+	if errs := tasksTree.DoAllSyncWithContext(ctx); len(errs) > 0 {
 		return handleErrors(errs, "cluster IAM and OIDC")
 	}
 
@@ -211,7 +218,7 @@ func (c *UnownedCluster) deleteIAMAndOIDC(wait bool, clusterOperable bool, clien
 	return nil
 }
 
-func (c *UnownedCluster) deleteCluster(wait bool) error {
+func (c *UnownedCluster) deleteCluster(ctx context.Context, wait bool) error {
 	clusterName := c.cfg.Metadata.Name
 
 	out, err := c.ctl.Provider.EKS().DeleteCluster(&awseks.DeleteClusterInput{
@@ -243,10 +250,10 @@ func (c *UnownedCluster) deleteCluster(wait bool) error {
 
 	msg := fmt.Sprintf("waiting for cluster %q to be deleted", clusterName)
 
-	return waiters.Wait(clusterName, msg, acceptors, newRequest, c.ctl.Provider.WaitTimeout(), nil)
+	return waiters.Wait(ctx, clusterName, msg, acceptors, newRequest, c.ctl.Provider.WaitTimeout(), nil)
 }
 
-func (c *UnownedCluster) deleteAndWaitForNodegroupsDeletion(waitInterval time.Duration, allStacks []manager.NodeGroupStack) error {
+func (c *UnownedCluster) deleteAndWaitForNodegroupsDeletion(ctx context.Context, waitInterval time.Duration, allStacks []manager.NodeGroupStack, disableProtection bool) error {
 	clusterName := c.cfg.Metadata.Name
 	eksAPI := c.ctl.Provider.EKS()
 
@@ -264,7 +271,7 @@ func (c *UnownedCluster) deleteAndWaitForNodegroupsDeletion(waitInterval time.Du
 	}
 
 	// we kill every nodegroup with a stack the standard way. wait is always true
-	tasks, err := c.stackManager.NewTasksToDeleteNodeGroups(func(_ string) bool { return true }, true, nil)
+	tasks, err := c.stackManager.NewTasksToDeleteNodeGroups(func(_ string) bool { return true }, true, disableProtection, nil)
 	if err != nil {
 		return err
 	}
@@ -288,7 +295,7 @@ func (c *UnownedCluster) deleteAndWaitForNodegroupsDeletion(waitInterval time.Du
 	// TODO what dis?
 	tasks.PlanMode = false
 	logger.Info(tasks.Describe())
-	if errs := tasks.DoAllSync(); len(errs) > 0 {
+	if errs := tasks.DoAllSyncWithContext(ctx); len(errs) > 0 {
 		return handleErrors(errs, "nodegroup(s)")
 	}
 	return nil
@@ -1,6 +1,8 @@
 package fargate_test
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
@@ -79,7 +81,7 @@ var _ = Describe("Fargate", func() {
 							},
 						},
 					}, nil)
-					fakeStackManager.CreateStackStub = func(_ string, _ builder.ResourceSet, _ map[string]string, _ map[string]string, errchan chan error) error {
+					fakeStackManager.CreateStackStub = func(_ context.Context, _ string, _ builder.ResourceSet, _ map[string]string, _ map[string]string, errchan chan error) error {
 						go func() {
 							errchan <- nil
 						}()
@@ -116,7 +118,7 @@ var _ = Describe("Fargate", func() {
 					err := fargateManager.Create()
 					Expect(err).NotTo(HaveOccurred())
 					Expect(fakeStackManager.CreateStackCallCount()).To(Equal(1))
-					name, stack, _, _, _ := fakeStackManager.CreateStackArgsForCall(0)
+					_, name, stack, _, _, _ := fakeStackManager.CreateStackArgsForCall(0)
 					Expect(name).To(Equal("eksctl-my-cluster-fargate"))
 					output, err := stack.RenderJSON()
 					Expect(err).NotTo(HaveOccurred())
@@ -197,7 +199,7 @@ var _ = Describe("Fargate", func() {
 							},
 						},
 					}, nil)
-					fakeStackManager.CreateStackStub = func(_ string, _ builder.ResourceSet, _ map[string]string, _ map[string]string, errchan chan error) error {
+					fakeStackManager.CreateStackStub = func(_ context.Context, _ string, _ builder.ResourceSet, _ map[string]string, _ map[string]string, errchan chan error) error {
 						go func() {
 							errchan <- nil
 						}()
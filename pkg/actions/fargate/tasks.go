@@ -1,6 +1,8 @@
 package fargate
 
 import (
+	"context"
+
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
@@ -21,12 +23,12 @@ func makeClusterStackName(clusterName string) string {
 	return "eksctl-" + clusterName + "-fargate"
 }
 
-func (t *createFargateStackTask) Do(errs chan error) error {
+func (t *createFargateStackTask) Do(ctx context.Context, errs chan error) error {
 	rs := builder.NewFargateResourceSet(t.cfg)
 	if err := rs.AddAllResources(); err != nil {
 		return errors.Wrap(err, "couldn't add all resources to fargate resource set")
 	}
-	return t.stackManager.CreateStack(makeClusterStackName(t.cfg.Metadata.Name), rs, nil, nil, errs)
+	return t.stackManager.CreateStack(ctx, makeClusterStackName(t.cfg.Metadata.Name), rs, nil, nil, errs)
 }
 
 // ensureFargateRoleStackExists creates fargate IAM resources if they
@@ -1,6 +1,7 @@
 package identityproviders
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -17,7 +18,7 @@ type AssociateIdentityProvidersOptions struct {
 	WaitTimeout *time.Duration
 }
 
-func (m *Manager) Associate(options AssociateIdentityProvidersOptions) error {
+func (m *Manager) Associate(ctx context.Context, options AssociateIdentityProvidersOptions) error {
 	taskTree := tasks.TaskTree{
 		Parallel: true,
 	}
@@ -35,7 +36,7 @@ func (m *Manager) Associate(options AssociateIdentityProvidersOptions) error {
 
 					logger.Info("started associating identity provider %s", idP.Name)
 					if options.WaitTimeout != nil {
-						if err := m.waitForUpdate(update, *options.WaitTimeout); err != nil {
+						if err := m.waitForUpdate(ctx, update, *options.WaitTimeout); err != nil {
 							return err
 						}
 					}
@@ -48,7 +49,7 @@ func (m *Manager) Associate(options AssociateIdentityProvidersOptions) error {
 		}
 	}
 
-	errs := taskTree.DoAllSync()
+	errs := taskTree.DoAllSyncWithContext(ctx)
 	for _, err := range errs {
 		logger.Critical(err.Error())
 	}
@@ -1,6 +1,7 @@
 package identityproviders_test
 
 import (
+	"context"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -40,7 +41,7 @@ var _ = Describe("Associate", func() {
 	})
 	It("associates with all providers", func() {
 		manager := identityproviders.NewManager(api.ClusterMeta{}, &eksAPI)
-		err := manager.Associate(identityproviders.AssociateIdentityProvidersOptions{
+		err := manager.Associate(context.Background(), identityproviders.AssociateIdentityProvidersOptions{
 			Providers: []api.IdentityProvider{
 				{Inner: &api.OIDCIdentityProvider{
 					Name:           "pool-1",
@@ -75,7 +76,7 @@ var _ = Describe("Associate", func() {
 			client.MockRequestForGivenOutput(&updateInput, &updateOutput), &updateOutput,
 		)
 		wait := 1 * time.Minute
-		err := manager.Associate(identityproviders.AssociateIdentityProvidersOptions{
+		err := manager.Associate(context.Background(), identityproviders.AssociateIdentityProvidersOptions{
 			WaitTimeout: &wait,
 			Providers: []api.IdentityProvider{
 				api.IdentityProvider{Inner: &api.OIDCIdentityProvider{
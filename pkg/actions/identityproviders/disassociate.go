@@ -1,6 +1,7 @@
 package identityproviders
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -22,7 +23,7 @@ type DisassociateIdentityProvider struct {
 	Type api.IdentityProviderType
 }
 
-func (m *Manager) Disassociate(options DisassociateIdentityProvidersOptions) error {
+func (m *Manager) Disassociate(ctx context.Context, options DisassociateIdentityProvidersOptions) error {
 	taskTree := tasks.TaskTree{
 		Parallel: true,
 	}
@@ -62,7 +63,7 @@ func (m *Manager) Disassociate(options DisassociateIdentityProvidersOptions) err
 				logger.Info("started disassociating identity provider %s", idP.Name)
 
 				if options.WaitTimeout != nil {
-					if err := m.waitForUpdate(*update.Update, *options.WaitTimeout); err != nil {
+					if err := m.waitForUpdate(ctx, *update.Update, *options.WaitTimeout); err != nil {
 						return err
 					}
 				}
@@ -71,7 +72,7 @@ func (m *Manager) Disassociate(options DisassociateIdentityProvidersOptions) err
 		})
 	}
 
-	errs := taskTree.DoAllSync()
+	errs := taskTree.DoAllSyncWithContext(ctx)
 	for _, err := range errs {
 		logger.Critical(err.Error())
 	}
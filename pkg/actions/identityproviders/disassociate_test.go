@@ -1,6 +1,7 @@
 package identityproviders_test
 
 import (
+	"context"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -47,7 +48,7 @@ var _ = Describe("Disassociate", func() {
 	})
 	It("disassociates from all providers", func() {
 		manager := identityproviders.NewManager(api.ClusterMeta{}, &eksAPI)
-		err := manager.Disassociate(identityproviders.DisassociateIdentityProvidersOptions{
+		err := manager.Disassociate(context.Background(), identityproviders.DisassociateIdentityProvidersOptions{
 			Providers: []identityproviders.DisassociateIdentityProvider{
 				{
 					Name: "pool-1",
@@ -75,7 +76,7 @@ var _ = Describe("Disassociate", func() {
 			client.MockRequestForGivenOutput(&updateInput, &updateOutput), &updateOutput,
 		)
 		wait := 1 * time.Minute
-		err := manager.Disassociate(identityproviders.DisassociateIdentityProvidersOptions{
+		err := manager.Disassociate(context.Background(), identityproviders.DisassociateIdentityProvidersOptions{
 			WaitTimeout: &wait,
 			Providers: []identityproviders.DisassociateIdentityProvider{
 				{
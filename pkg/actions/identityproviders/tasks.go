@@ -1,6 +1,8 @@
 package identityproviders
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/utils/tasks"
@@ -13,12 +15,10 @@ type AssociateProvidersTask struct {
 }
 
 func NewAssociateProvidersTask(metadata api.ClusterMeta, providers []api.IdentityProvider, eks eksiface.EKSAPI) tasks.Task {
-	return tasks.SynchronousTask{
-		SynchronousTaskIface: &AssociateProvidersTask{
-			metadata:  metadata,
-			providers: providers,
-			eks:       eks,
-		},
+	return &AssociateProvidersTask{
+		metadata:  metadata,
+		providers: providers,
+		eks:       eks,
 	}
 }
 
@@ -26,7 +26,8 @@ func (t *AssociateProvidersTask) Describe() string {
 	return "associate identity providers with cluster"
 }
 
-func (t *AssociateProvidersTask) Do() error {
+func (t *AssociateProvidersTask) Do(ctx context.Context, errorCh chan error) error {
+	defer close(errorCh)
 	m := NewManager(t.metadata, t.eks)
-	return m.Associate(AssociateIdentityProvidersOptions{Providers: t.providers})
+	return m.Associate(ctx, AssociateIdentityProvidersOptions{Providers: t.providers})
 }
@@ -1,6 +1,7 @@
 package identityproviders
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -12,7 +13,7 @@ import (
 )
 
 func (m *Manager) waitForUpdate(
-	update eks.Update, timeout time.Duration,
+	ctx context.Context, update eks.Update, timeout time.Duration,
 ) error {
 	clusterName := m.metadata.Name
 	newRequest := func() *request.Request {
@@ -39,5 +40,5 @@ func (m *Manager) waitForUpdate(
 		clusterName,
 	)
 
-	return waiters.Wait(clusterName, msg, acceptors, newRequest, timeout, nil)
+	return waiters.Wait(ctx, clusterName, msg, acceptors, newRequest, timeout, nil)
 }
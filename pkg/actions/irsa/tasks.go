@@ -1,6 +1,7 @@
 package irsa
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -12,7 +13,9 @@ import (
 	"github.com/weaveworks/eksctl/pkg/utils/tasks"
 )
 
-func NewUpdateIAMServiceAccountTask(clusterName string, sa *api.ClusterIAMServiceAccount, stackManager manager.StackManager, oidcManager *iamoidc.OpenIDConnectManager) (*tasks.TaskTree, error) {
+// NewUpdateIAMServiceAccountTask returns a task that updates the IAM role stack of a service account;
+// when plan is true the task only previews the changes to the stack
+func NewUpdateIAMServiceAccountTask(clusterName string, sa *api.ClusterIAMServiceAccount, stackManager manager.StackManager, oidcManager *iamoidc.OpenIDConnectManager, plan bool) (*tasks.TaskTree, error) {
 
 	rs := builder.NewIAMRoleResourceSetForServiceAccount(sa, oidcManager)
 	err := rs.AddAllResources()
@@ -36,6 +39,7 @@ func NewUpdateIAMServiceAccountTask(clusterName string, sa *api.ClusterIAMServic
 			templateData: templateData,
 			sa:           sa,
 			clusterName:  clusterName,
+			plan:         plan,
 		},
 	)
 	return taskTree, nil
@@ -47,22 +51,24 @@ type updateIAMServiceAccountTask struct {
 	templateData manager.TemplateData
 	clusterName  string
 	info         string
+	plan         bool
 }
 
 func (t *updateIAMServiceAccountTask) Describe() string { return t.info }
 
-func (t *updateIAMServiceAccountTask) Do(errorCh chan error) error {
+func (t *updateIAMServiceAccountTask) Do(ctx context.Context, errorCh chan error) error {
 	stackName := makeIAMServiceAccountStackName(t.clusterName, t.sa.Namespace, t.sa.Name)
 	go func() {
 		errorCh <- nil
 	}()
 
 	desc := fmt.Sprintf("updating policies for IAMServiceAccount %s/%s", t.sa.Namespace, t.sa.Name)
-	return t.stackManager.UpdateStack(manager.UpdateStackOptions{
+	return t.stackManager.UpdateStack(ctx, manager.UpdateStackOptions{
 		StackName:     stackName,
 		ChangeSetName: fmt.Sprintf("updating-policy-%s", uuid.NewString()),
 		Description:   desc,
 		TemplateData:  t.templateData,
 		Wait:          true,
+		Plan:          t.plan,
 	})
 }
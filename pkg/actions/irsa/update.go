@@ -13,6 +13,9 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 )
 
+// UpdateIAMServiceAccounts updates the IAM role stacks of the service accounts that exist; when plan is true,
+// the changes are previewed with ChangeSets that are created through the CloudFormation API and always deleted
+// without being executed
 func (a *Manager) UpdateIAMServiceAccounts(iamServiceAccounts []*api.ClusterIAMServiceAccount, plan bool) error {
 	var nonExistingSAs []string
 	updateTasks := &tasks.TaskTree{Parallel: true}
@@ -33,11 +36,10 @@ func (a *Manager) UpdateIAMServiceAccounts(iamServiceAccounts []*api.ClusterIAMS
 			continue
 		}
 
-		taskTree, err := NewUpdateIAMServiceAccountTask(a.clusterName, iamServiceAccount, a.stackManager, a.oidcManager)
+		taskTree, err := NewUpdateIAMServiceAccountTask(a.clusterName, iamServiceAccount, a.stackManager, a.oidcManager, plan)
 		if err != nil {
 			return err
 		}
-		taskTree.PlanMode = plan
 		updateTasks.Append(taskTree)
 	}
 	if len(nonExistingSAs) > 0 {
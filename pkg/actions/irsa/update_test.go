@@ -57,7 +57,7 @@ var _ = Describe("Update", func() {
 			Expect(fakeStackManager.ListStacksMatchingArgsForCall(0)).To(Equal("eksctl-.*-addon-iamserviceaccount"))
 			Expect(fakeStackManager.UpdateStackCallCount()).To(Equal(1))
 			fakeStackManager.UpdateStackArgsForCall(0)
-			options := fakeStackManager.UpdateStackArgsForCall(0)
+			_, options := fakeStackManager.UpdateStackArgsForCall(0)
 			Expect(options.StackName).To(Equal("eksctl-my-cluster-addon-iamserviceaccount-default-test-sa"))
 			Expect(options.ChangeSetName).To(ContainSubstring("updating-policy"))
 			Expect(options.Description).To(Equal("updating policies for IAMServiceAccount default/test-sa"))
@@ -68,7 +68,7 @@ var _ = Describe("Update", func() {
 		})
 
 		When("in plan mode", func() {
-			It("does not trigger an update", func() {
+			It("only previews the update", func() {
 				fakeStackManager.ListStacksMatchingReturns([]*cloudformation.Stack{
 					{
 						StackName: aws.String("eksctl-my-cluster-addon-iamserviceaccount-default-test-sa"),
@@ -80,7 +80,10 @@ var _ = Describe("Update", func() {
 
 				Expect(fakeStackManager.ListStacksMatchingCallCount()).To(Equal(1))
 				Expect(fakeStackManager.ListStacksMatchingArgsForCall(0)).To(Equal("eksctl-.*-addon-iamserviceaccount"))
-				Expect(fakeStackManager.UpdateStackCallCount()).To(Equal(0))
+				Expect(fakeStackManager.UpdateStackCallCount()).To(Equal(1))
+				_, options := fakeStackManager.UpdateStackArgsForCall(0)
+				Expect(options.StackName).To(Equal("eksctl-my-cluster-addon-iamserviceaccount-default-test-sa"))
+				Expect(options.Plan).To(BeTrue())
 			})
 		})
 	})
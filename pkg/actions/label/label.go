@@ -1,32 +1,49 @@
 package label
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
 	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/autoscaler"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 //counterfeiter:generate -o fakes/fake_managed_service.go . Service
 type Service interface {
 	GetLabels(nodeGroupName string) (map[string]string, error)
-	UpdateLabels(nodeGroupName string, labelsToAdd map[string]string, labelsToRemove []string) error
+	UpdateLabels(ctx context.Context, nodeGroupName string, labelsToAdd map[string]string, labelsToRemove []string) error
 }
 
 type Manager struct {
 	service     Service
 	eksAPI      eksiface.EKSAPI
+	asgAPI      autoscalingiface.AutoScalingAPI
 	clusterName string
 }
 
-func New(clusterName string, service Service, eksAPI eksiface.EKSAPI) *Manager {
+func New(clusterName string, service Service, eksAPI eksiface.EKSAPI, asgAPI autoscalingiface.AutoScalingAPI) *Manager {
 	return &Manager{
 		service:     service,
 		eksAPI:      eksAPI,
+		asgAPI:      asgAPI,
 		clusterName: clusterName,
 	}
 }
 
+// nodeGroupASGs returns the Auto Scaling groups of a nodegroup, whose cluster-autoscaler
+// node-template tags have to be kept in sync with the labels of the nodegroup
+func (m *Manager) nodeGroupASGs(nodeGroupName string) ([]string, error) {
+	asgNames, err := autoscaler.ManagedNodeGroupASGs(m.eksAPI, m.clusterName, nodeGroupName)
+	if err != nil {
+		return nil, errors.Wrap(err, "updating cluster-autoscaler node-template tags")
+	}
+	return asgNames, nil
+}
+
 // If a ValidationError code is returned then an eksctl-marked stack was not
 // found for that nodegroup so we can then try to call the EKS api directly.
 func isValidationError(err error) bool {
@@ -1,10 +1,13 @@
 package label_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -29,7 +32,7 @@ var _ = Describe("Labels", func() {
 		mockProvider = mockprovider.NewMockProvider()
 		clusterName = "foo"
 		nodegroupName = "bar"
-		manager = label.New(clusterName, fakeManagedService, mockProvider.EKS())
+		manager = label.New(clusterName, fakeManagedService, mockProvider.EKS(), mockProvider.ASG())
 		manager.SetService(fakeManagedService)
 	})
 
@@ -104,6 +107,9 @@ var _ = Describe("Labels", func() {
 
 		BeforeEach(func() {
 			labels = map[string]string{"k1": "v1"}
+			mockNodegroupASG(mockProvider, clusterName, nodegroupName)
+			mockASGTags(mockProvider, 0)
+			mockProvider.MockASG().On("CreateOrUpdateTags", mock.Anything).Return(&autoscaling.CreateOrUpdateTagsOutput{}, nil)
 		})
 
 		When("the nodegroup is owned by eksctl", func() {
@@ -112,7 +118,48 @@ var _ = Describe("Labels", func() {
 			})
 
 			It("sets new labels by updating the nodegroup stack", func() {
-				Expect(manager.Set(nodegroupName, labels)).To(Succeed())
+				Expect(manager.Set(context.Background(), nodegroupName, labels)).To(Succeed())
+			})
+
+			It("adds the labels as cluster-autoscaler node-template tags to the nodegroup's Auto Scaling group", func() {
+				Expect(manager.Set(context.Background(), nodegroupName, labels)).To(Succeed())
+
+				Expect(mockProvider.MockASG().AssertCalled(GinkgoT(), "CreateOrUpdateTags", &autoscaling.CreateOrUpdateTagsInput{
+					Tags: []*autoscaling.Tag{
+						{
+							ResourceId:        aws.String("asg-1"),
+							ResourceType:      aws.String("auto-scaling-group"),
+							Key:               aws.String("k8s.io/cluster-autoscaler/node-template/label/k1"),
+							Value:             aws.String("v1"),
+							PropagateAtLaunch: aws.Bool(false),
+						},
+					},
+				})).To(BeTrue())
+			})
+
+			When("tagging the Auto Scaling group fails", func() {
+				BeforeEach(func() {
+					mockProvider.MockASG().ExpectedCalls = nil
+					mockASGTags(mockProvider, 0)
+					mockProvider.MockASG().On("CreateOrUpdateTags", mock.Anything).Return(nil, errors.New("access denied"))
+				})
+
+				It("keeps the labels that were set", func() {
+					Expect(manager.Set(context.Background(), nodegroupName, labels)).To(Succeed())
+					Expect(fakeManagedService.UpdateLabelsCallCount()).To(Equal(1))
+				})
+			})
+
+			When("the Auto Scaling group would have more than 50 tags", func() {
+				BeforeEach(func() {
+					mockProvider.MockASG().ExpectedCalls = nil
+					mockASGTags(mockProvider, 50)
+				})
+
+				It("does not tag the Auto Scaling group", func() {
+					Expect(manager.Set(context.Background(), nodegroupName, labels)).To(Succeed())
+					mockProvider.MockASG().AssertNotCalled(GinkgoT(), "CreateOrUpdateTags", mock.Anything)
+				})
 			})
 
 			When("the service returns an error", func() {
@@ -121,7 +168,7 @@ var _ = Describe("Labels", func() {
 				})
 
 				It("fails", func() {
-					err := manager.Set(nodegroupName, labels)
+					err := manager.Set(context.Background(), nodegroupName, labels)
 					Expect(err).To(HaveOccurred())
 				})
 			})
@@ -144,7 +191,7 @@ var _ = Describe("Labels", func() {
 					},
 				}).Return(&awseks.UpdateNodegroupConfigOutput{}, nil)
 
-				Expect(manager.Set(nodegroupName, labels)).To(Succeed())
+				Expect(manager.Set(context.Background(), nodegroupName, labels)).To(Succeed())
 			})
 
 			When("the EKS api returns an error", func() {
@@ -155,7 +202,7 @@ var _ = Describe("Labels", func() {
 				It("fails", func() {
 					mockProvider.MockEKS().On("UpdateNodegroupConfig", mock.Anything).Return(&awseks.UpdateNodegroupConfigOutput{}, errors.New("oh-noes"))
 
-					err := manager.Set(nodegroupName, labels)
+					err := manager.Set(context.Background(), nodegroupName, labels)
 					Expect(err).To(HaveOccurred())
 				})
 			})
@@ -167,6 +214,8 @@ var _ = Describe("Labels", func() {
 
 		BeforeEach(func() {
 			labels = []string{"k1"}
+			mockNodegroupASG(mockProvider, clusterName, nodegroupName)
+			mockProvider.MockASG().On("DeleteTags", mock.Anything).Return(&autoscaling.DeleteTagsOutput{}, nil)
 		})
 
 		When("the nodegroup is owned by eksctl", func() {
@@ -175,7 +224,21 @@ var _ = Describe("Labels", func() {
 			})
 
 			It("removes labels by updating the nodegroup stack", func() {
-				Expect(manager.Unset(nodegroupName, labels)).To(Succeed())
+				Expect(manager.Unset(context.Background(), nodegroupName, labels)).To(Succeed())
+			})
+
+			It("removes the cluster-autoscaler node-template tags of the labels from the nodegroup's Auto Scaling group", func() {
+				Expect(manager.Unset(context.Background(), nodegroupName, labels)).To(Succeed())
+
+				Expect(mockProvider.MockASG().AssertCalled(GinkgoT(), "DeleteTags", &autoscaling.DeleteTagsInput{
+					Tags: []*autoscaling.Tag{
+						{
+							ResourceId:   aws.String("asg-1"),
+							ResourceType: aws.String("auto-scaling-group"),
+							Key:          aws.String("k8s.io/cluster-autoscaler/node-template/label/k1"),
+						},
+					},
+				})).To(BeTrue())
 			})
 
 			When("the service returns an error", func() {
@@ -184,7 +247,7 @@ var _ = Describe("Labels", func() {
 				})
 
 				It("fails", func() {
-					err := manager.Unset(nodegroupName, labels)
+					err := manager.Unset(context.Background(), nodegroupName, labels)
 					Expect(err).To(HaveOccurred())
 				})
 			})
@@ -207,7 +270,7 @@ var _ = Describe("Labels", func() {
 					},
 				}).Return(&awseks.UpdateNodegroupConfigOutput{}, nil)
 
-				Expect(manager.Unset(nodegroupName, labels)).To(Succeed())
+				Expect(manager.Unset(context.Background(), nodegroupName, labels)).To(Succeed())
 			})
 
 			When("the EKS api returns an error", func() {
@@ -218,10 +281,35 @@ var _ = Describe("Labels", func() {
 				It("fails", func() {
 					mockProvider.MockEKS().On("UpdateNodegroupConfig", mock.Anything).Return(&awseks.UpdateNodegroupConfigOutput{}, errors.New("oh-noes"))
 
-					err := manager.Unset(nodegroupName, labels)
+					err := manager.Unset(context.Background(), nodegroupName, labels)
 					Expect(err).To(HaveOccurred())
 				})
 			})
 		})
 	})
 })
+
+// mockASGTags gives the Auto Scaling group of the nodegroup count tags
+func mockASGTags(mockProvider *mockprovider.MockProvider, count int) {
+	var tags []*autoscaling.TagDescription
+	for i := 0; i < count; i++ {
+		tags = append(tags, &autoscaling.TagDescription{ResourceId: aws.String("asg-1"), Key: aws.String(fmt.Sprintf("tag-%d", i))})
+	}
+	mockProvider.MockASG().On("DescribeTagsPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		pager := args.Get(1).(func(*autoscaling.DescribeTagsOutput, bool) bool)
+		pager(&autoscaling.DescribeTagsOutput{Tags: tags}, true)
+	}).Return(nil)
+}
+
+func mockNodegroupASG(mockProvider *mockprovider.MockProvider, clusterName, nodegroupName string) {
+	mockProvider.MockEKS().On("DescribeNodegroup", &awseks.DescribeNodegroupInput{
+		ClusterName:   aws.String(clusterName),
+		NodegroupName: aws.String(nodegroupName),
+	}).Return(&awseks.DescribeNodegroupOutput{
+		Nodegroup: &awseks.Nodegroup{
+			Resources: &awseks.NodegroupResources{
+				AutoScalingGroups: []*awseks.AutoScalingGroup{{Name: aws.String("asg-1")}},
+			},
+		},
+	}, nil)
+}
@@ -1,16 +1,40 @@
 package label
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/kris-nova/logger"
+
+	"github.com/weaveworks/eksctl/pkg/autoscaler"
 )
 
-func (m *Manager) Set(nodeGroupName string, labels map[string]string) error {
-	err := m.service.UpdateLabels(nodeGroupName, labels, nil)
+func (m *Manager) Set(ctx context.Context, nodeGroupName string, labels map[string]string) error {
+	err := m.service.UpdateLabels(ctx, nodeGroupName, labels, nil)
 	if err != nil && isValidationError(err) {
-		return m.setLabelsOnUnownedNodeGroup(nodeGroupName, labels)
+		err = m.setLabelsOnUnownedNodeGroup(nodeGroupName, labels)
+	}
+	if err != nil {
+		return err
+	}
+	// the labels are applied, cluster-autoscaler only needs the tags to scale the nodegroup up from zero
+	if err := m.setNodeTemplateTags(nodeGroupName, labels); err != nil {
+		logger.Warning("failed to add the labels as cluster-autoscaler node-template tags to the Auto Scaling group(s) of nodegroup %q: %v", nodeGroupName, err)
+	}
+	return nil
+}
+
+func (m *Manager) setNodeTemplateTags(nodeGroupName string, labels map[string]string) error {
+	asgNames, err := m.nodeGroupASGs(nodeGroupName)
+	if err != nil {
+		return err
+	}
+	tags := make(map[string]string, len(labels))
+	for k, v := range labels {
+		tags[autoscaler.LabelTag(k)] = v
 	}
-	return err
+	return autoscaler.TagASGs(m.asgAPI, asgNames, tags)
 }
 
 func (m *Manager) setLabelsOnUnownedNodeGroup(nodeGroupName string, labels map[string]string) error {
@@ -1,23 +1,46 @@
 package label
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/kris-nova/logger"
+
+	"github.com/weaveworks/eksctl/pkg/autoscaler"
 )
 
-func (m *Manager) Unset(nodeGroupName string, labels []string) error {
-	err := m.service.UpdateLabels(nodeGroupName, nil, labels)
+func (m *Manager) Unset(ctx context.Context, nodeGroupName string, labels []string) error {
+	err := m.service.UpdateLabels(ctx, nodeGroupName, nil, labels)
 	if err != nil {
 		switch {
 		case isValidationError(err):
-			return m.unsetLabelsOnUnownedNodeGroup(nodeGroupName, labels)
+			if err := m.unsetLabelsOnUnownedNodeGroup(nodeGroupName, labels); err != nil {
+				return err
+			}
 		default:
 			return err
 		}
 	}
+	// the labels are removed, cluster-autoscaler only needs the tags to scale the nodegroup up from zero
+	if err := m.unsetNodeTemplateTags(nodeGroupName, labels); err != nil {
+		logger.Warning("failed to remove the cluster-autoscaler node-template tags of the labels from the Auto Scaling group(s) of nodegroup %q: %v", nodeGroupName, err)
+	}
 	return nil
 }
 
+func (m *Manager) unsetNodeTemplateTags(nodeGroupName string, labels []string) error {
+	asgNames, err := m.nodeGroupASGs(nodeGroupName)
+	if err != nil {
+		return err
+	}
+	var keys []string
+	for _, label := range labels {
+		keys = append(keys, autoscaler.LabelTag(label))
+	}
+	return autoscaler.UntagASGs(m.asgAPI, asgNames, keys)
+}
+
 func (m *Manager) unsetLabelsOnUnownedNodeGroup(nodeGroupName string, labels []string) error {
 	var pointyLabels []*string
 	for _, v := range labels {
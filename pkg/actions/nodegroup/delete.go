@@ -1,16 +1,18 @@
 package nodegroup
 
 import (
+	"context"
 	"fmt"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/utils/tasks"
 
 	"github.com/kris-nova/logger"
 )
 
-func (m *Manager) Delete(nodeGroups []*api.NodeGroup, managedNodeGroups []*api.ManagedNodeGroup, wait, plan bool) error {
+func (m *Manager) Delete(ctx context.Context, nodeGroups []*api.NodeGroup, managedNodeGroups []*api.ManagedNodeGroup, wait, plan, disableProtection bool) error {
 	var nodeGroupsWithStacks []eks.KubeNodeGroup
 
 	for _, n := range nodeGroups {
@@ -20,12 +22,12 @@ func (m *Manager) Delete(nodeGroups []*api.NodeGroup, managedNodeGroups []*api.M
 	tasks := &tasks.TaskTree{Parallel: true}
 
 	for _, n := range managedNodeGroups {
-		hasStacks, err := m.hasStacks(n.Name)
+		stack, err := m.findStack(n.Name)
 		if err != nil {
 			return err
 		}
 
-		if hasStacks {
+		if stack != nil {
 			nodeGroupsWithStacks = append(nodeGroupsWithStacks, n)
 		} else {
 			tasks.Append(m.stackManager.NewTaskToDeleteUnownedNodeGroup(m.cfg.Metadata.Name, n.Name, m.ctl.Provider.EKS(), nil))
@@ -41,7 +43,7 @@ func (m *Manager) Delete(nodeGroups []*api.NodeGroup, managedNodeGroups []*api.M
 		return false
 	}
 
-	deleteTasks, err := m.stackManager.NewTasksToDeleteNodeGroups(shouldDelete, wait, nil)
+	deleteTasks, err := m.stackManager.NewTasksToDeleteNodeGroups(shouldDelete, wait, disableProtection, nil)
 	if err != nil {
 		return err
 	}
@@ -49,12 +51,32 @@ func (m *Manager) Delete(nodeGroups []*api.NodeGroup, managedNodeGroups []*api.M
 
 	tasks.PlanMode = plan
 	logger.Info(tasks.Describe())
-	if errs := tasks.DoAllSync(); len(errs) > 0 {
+	if errs := tasks.DoAllSyncWithContext(ctx); len(errs) > 0 {
 		return handleErrors(errs, "nodegroup(s)")
 	}
 	return nil
 }
 
+// CheckTerminationProtection returns an error when the stack of any of the nodegroups has termination protection enabled
+func (m *Manager) CheckTerminationProtection(nodeGroups []eks.KubeNodeGroup) error {
+	stacks, err := m.stackManager.DescribeNodeGroupStacks()
+	if err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for _, ng := range nodeGroups {
+		names[ng.NameString()] = true
+	}
+	var toDelete []*manager.Stack
+	for _, s := range stacks {
+		if names[m.stackManager.GetNodeGroupName(s)] {
+			toDelete = append(toDelete, s)
+		}
+	}
+	return manager.CheckTerminationProtection(toDelete...)
+}
+
 func handleErrors(errs []error, subject string) error {
 	logger.Info("%d error(s) occurred while deleting %s", len(errs), subject)
 	for _, err := range errs {
@@ -8,10 +8,30 @@ import (
 	"github.com/weaveworks/eksctl/pkg/drain"
 )
 
-func (m *Manager) Drain(nodeGroups []eks.KubeNodeGroup, plan bool, maxGracePeriod time.Duration, disableEviction bool) error {
-	if !plan {
-		for _, n := range nodeGroups {
-			nodeGroupDrainer := drain.NewNodeGroupDrainer(m.clientSet, n, m.ctl.Provider.WaitTimeout(), maxGracePeriod, false, disableEviction)
+// DrainInput holds the nodegroups to drain and how to drain them
+type DrainInput struct {
+	NodeGroups      []eks.KubeNodeGroup
+	Plan            bool
+	MaxGracePeriod  time.Duration
+	DisableEviction bool
+	// Undo uncordons the nodes instead of draining them
+	Undo bool
+	// Parallelism is the number of nodes drained at once
+	Parallelism int
+	// PDBTimeout is how long evictions may be blocked by a PodDisruptionBudget
+	// before PDBTimeoutAction is applied to the blocked pods; zero waits forever
+	PDBTimeout       time.Duration
+	PDBTimeoutAction drain.PDBTimeoutAction
+}
+
+func (m *Manager) Drain(input *DrainInput) error {
+	if !input.Plan {
+		for _, n := range input.NodeGroups {
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(m.clientSet, n, m.ctl.Provider.WaitTimeout(), input.MaxGracePeriod, input.Undo, input.DisableEviction, input.Parallelism)
+			if err := nodeGroupDrainer.SetDrainPolicy(m.cfg.NodeGroupDrainPolicy(n.NameString())); err != nil {
+				return err
+			}
+			nodeGroupDrainer.SetPDBTimeout(input.PDBTimeout, input.PDBTimeoutAction)
 			if err := nodeGroupDrainer.Drain(); err != nil {
 				return err
 			}
@@ -19,3 +39,20 @@ func (m *Manager) Drain(nodeGroups []eks.KubeNodeGroup, plan bool, maxGracePerio
 	}
 	return nil
 }
+
+// DrainDryRun reports what draining the nodegroups would do with the pods of their nodes
+func (m *Manager) DrainDryRun(input *DrainInput) ([]drain.PodDrainReport, error) {
+	reports := []drain.PodDrainReport{}
+	for _, n := range input.NodeGroups {
+		nodeGroupDrainer := drain.NewNodeGroupDrainer(m.clientSet, n, m.ctl.Provider.WaitTimeout(), input.MaxGracePeriod, input.Undo, input.DisableEviction, input.Parallelism)
+		if err := nodeGroupDrainer.SetDrainPolicy(m.cfg.NodeGroupDrainPolicy(n.NameString())); err != nil {
+			return nil, err
+		}
+		nodeGroupReports, err := nodeGroupDrainer.DryRun()
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, nodeGroupReports...)
+	}
+	return reports, nil
+}
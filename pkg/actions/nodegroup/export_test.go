@@ -1,6 +1,10 @@
 package nodegroup
 
 import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/eks"
 )
@@ -22,3 +26,20 @@ func (m *Manager) MockKubeProvider(k eks.KubeProvider) {
 func (m *Manager) MockNodeGroupService(ngSvc eks.NodeGroupInitialiser) {
 	m.init = ngSvc
 }
+
+// SetPollInterval sets how often the replacement of instances is checked.
+func SetPollInterval(interval time.Duration) {
+	pollInterval = interval
+}
+
+// DrainAndWaitForRescheduling records the workloads running on the given nodes, calls drain, and
+// waits until the workloads have been rescheduled to other nodes.
+func (m *Manager) DrainAndWaitForRescheduling(nodes []string, drain func()) error {
+	oldNodes := sets.NewString(nodes...)
+	workloads, err := m.workloadsOnNodes(oldNodes)
+	if err != nil {
+		return err
+	}
+	drain()
+	return m.waitForRescheduling(workloads, oldNodes)
+}
@@ -1,6 +1,7 @@
 package nodegroup
 
 import (
+	"context"
 	"time"
 
 	"github.com/weaveworks/eksctl/pkg/utils/waiters"
@@ -23,7 +24,7 @@ type Manager struct {
 	kubeProvider eks.KubeProvider
 }
 
-type WaitFunc func(name, msg string, acceptors []request.WaiterAcceptor, newRequest func() *request.Request, waitTimeout time.Duration, troubleshoot func(string) error) error
+type WaitFunc func(ctx context.Context, name, msg string, acceptors []request.WaiterAcceptor, newRequest func() *request.Request, waitTimeout time.Duration, troubleshoot func(string) error) error
 
 // New creates a new manager.
 func New(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, clientSet kubernetes.Interface) *Manager {
@@ -40,15 +41,16 @@ func New(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, clientSet kubernetes.
 	}
 }
 
-func (m *Manager) hasStacks(name string) (bool, error) {
+// findStack returns the stack of the nodegroup, or nil when it was not created by eksctl
+func (m *Manager) findStack(name string) (*manager.NodeGroupStack, error) {
 	stacks, err := m.stackManager.ListNodeGroupStacks()
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	for _, stack := range stacks {
-		if stack.NodeGroupName == name {
-			return true, nil
+	for i := range stacks {
+		if stacks[i].NodeGroupName == name {
+			return &stacks[i], nil
 		}
 	}
-	return false, nil
+	return nil, nil
 }
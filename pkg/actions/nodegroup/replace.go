@@ -0,0 +1,248 @@
+package nodegroup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/authconfigmap"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
+	"github.com/weaveworks/eksctl/pkg/drain"
+)
+
+// ReplaceOptions controls how a nodegroup is replaced
+type ReplaceOptions struct {
+	CreateOpts
+	// NodeGroupName is the name of the nodegroup to replace
+	NodeGroupName string
+	// MaxGracePeriod is the maximum termination grace period of pods when draining nodes
+	MaxGracePeriod time.Duration
+	// DisableEviction deletes pods instead of evicting them, bypassing PodDisruptionBudgets
+	DisableEviction bool
+	// PauseBeforeDelete asks for confirmation before deleting the old nodegroup
+	PauseBeforeDelete bool
+	// DisableProtection disables termination protection of the stack of the old nodegroup in order to delete it
+	DisableProtection bool
+	// Confirm asks the user a yes or no question, it is used when PauseBeforeDelete is set
+	Confirm func(question string) bool
+}
+
+// workload identifies the controller of pods
+type workload struct {
+	namespace, kind, name string
+	uid                   types.UID
+}
+
+func (w workload) String() string {
+	return fmt.Sprintf("%s %s/%s", w.kind, w.namespace, w.name)
+}
+
+// Replace replaces a nodegroup with the new nodegroups of the config matching the filter: the new
+// nodegroups are created, the nodes of the old nodegroup are drained once the new nodes are ready,
+// and the old nodegroup is deleted once its workloads have been rescheduled; the old nodes are
+// uncordoned when the new nodes cannot run the workloads; nothing is created when the stack of the old
+// nodegroup is protected against termination, unless DisableProtection is set
+func (m *Manager) Replace(ctx context.Context, options ReplaceOptions, nodegroupFilter filter.NodegroupFilter) error {
+	stack, err := m.findStack(options.NodeGroupName)
+	if err != nil {
+		return err
+	}
+	if stack == nil {
+		return fmt.Errorf("nodegroup %q was not created by eksctl", options.NodeGroupName)
+	}
+	if !options.DisableProtection {
+		oldStack, err := m.stackManager.DescribeNodeGroupStack(options.NodeGroupName)
+		if err != nil {
+			return err
+		}
+		if err := manager.CheckTerminationProtection(oldStack); err != nil {
+			return errors.Wrapf(err, "nodegroup %q cannot be replaced", options.NodeGroupName)
+		}
+	}
+	oldNodeGroup := &api.NodeGroupBase{Name: options.NodeGroupName}
+
+	if err := m.Create(ctx, options.CreateOpts, nodegroupFilter); err != nil {
+		return err
+	}
+	newNodeGroups := cmdutils.ToKubeNodeGroups(m.cfg)
+	if len(newNodeGroups) == 0 {
+		return errors.New("no new nodegroups were created, the config must define the nodegroup to replace it with")
+	}
+	var newNames []string
+	for _, ng := range newNodeGroups {
+		newNames = append(newNames, ng.NameString())
+	}
+
+	for _, ng := range newNodeGroups {
+		if err := m.kubeProvider.WaitForNodes(m.clientSet, ng); err != nil {
+			return errors.Wrapf(err, "new nodegroup %q is not healthy, nodegroup %q was not changed", ng.NameString(), options.NodeGroupName)
+		}
+	}
+
+	nodes, err := m.clientSet.CoreV1().Nodes().List(context.TODO(), oldNodeGroup.ListOptions())
+	if err != nil {
+		return errors.Wrap(err, "listing nodes")
+	}
+	oldNodes := sets.NewString()
+	for _, node := range nodes.Items {
+		oldNodes.Insert(node.Name)
+	}
+	workloads, err := m.workloadsOnNodes(oldNodes)
+	if err != nil {
+		return err
+	}
+
+	rollback := func(err error) error {
+		logger.Warning("uncordoning nodes of nodegroup %q", options.NodeGroupName)
+		undo := drain.NewNodeGroupDrainer(m.clientSet, oldNodeGroup, m.ctl.Provider.WaitTimeout(), options.MaxGracePeriod, true, options.DisableEviction, 1)
+		if undoErr := undo.Drain(); undoErr != nil {
+			logger.Warning("failed to uncordon nodes of nodegroup %q: %v", options.NodeGroupName, undoErr)
+		}
+		return errors.Wrapf(err, "replacing nodegroup %q failed and its nodes were uncordoned, new nodegroup(s) %v were kept", options.NodeGroupName, newNames)
+	}
+
+	logger.Info("draining %d node(s) of nodegroup %q", oldNodes.Len(), options.NodeGroupName)
+	drainer := drain.NewNodeGroupDrainer(m.clientSet, oldNodeGroup, m.ctl.Provider.WaitTimeout(), options.MaxGracePeriod, false, options.DisableEviction, 1)
+	if err := drainer.SetDrainPolicy(m.cfg.NodeGroupDrainPolicy(options.NodeGroupName)); err != nil {
+		return rollback(err)
+	}
+	if err := drainer.Drain(); err != nil {
+		return rollback(err)
+	}
+
+	if err := m.waitForRescheduling(workloads, oldNodes); err != nil {
+		return rollback(err)
+	}
+
+	if options.PauseBeforeDelete && !options.Confirm(fmt.Sprintf("workloads were rescheduled to nodegroup(s) %v, delete nodegroup %q?", newNames, options.NodeGroupName)) {
+		return rollback(errors.New("deletion of the old nodegroup was not confirmed"))
+	}
+
+	return m.deleteReplacedNodeGroup(ctx, stack.Type, options.NodeGroupName, options.DisableProtection)
+}
+
+// workloadsOnNodes returns the number of ready pods of each workload with pods on the given nodes;
+// pods of DaemonSets and Jobs, and pods without a controller are not expected to be rescheduled
+func (m *Manager) workloadsOnNodes(nodes sets.String) (map[workload]int, error) {
+	pods, err := m.clientSet.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing pods")
+	}
+	onNodes := map[workload]bool{}
+	ready := map[workload]int{}
+	for _, pod := range pods.Items {
+		w, ok := podWorkload(pod)
+		if !ok {
+			continue
+		}
+		if nodes.Has(pod.Spec.NodeName) {
+			onNodes[w] = true
+		}
+		if isPodReady(pod) {
+			ready[w]++
+		}
+	}
+	workloads := map[workload]int{}
+	for w := range onNodes {
+		workloads[w] = ready[w]
+	}
+	return workloads, nil
+}
+
+func podWorkload(pod corev1.Pod) (workload, bool) {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil || owner.Kind == "DaemonSet" || owner.Kind == "Job" {
+		return workload{}, false
+	}
+	return workload{namespace: pod.Namespace, kind: owner.Kind, name: owner.Name, uid: owner.UID}, true
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForRescheduling waits until every workload has as many ready pods outside of the old nodes as
+// it had before the nodes were drained
+func (m *Manager) waitForRescheduling(workloads map[workload]int, oldNodes sets.String) error {
+	if len(workloads) == 0 {
+		return nil
+	}
+	logger.Info("waiting for %d workload(s) to be rescheduled", len(workloads))
+	timeout := time.After(m.ctl.Provider.WaitTimeout())
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		pending, err := m.pendingWorkloads(workloads, oldNodes)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			logger.Success("all workloads were rescheduled")
+			return nil
+		}
+		select {
+		case <-timeout:
+			return fmt.Errorf("timed out (after %s) waiting for workloads to be rescheduled: %v", m.ctl.Provider.WaitTimeout(), pending)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) pendingWorkloads(workloads map[workload]int, oldNodes sets.String) ([]string, error) {
+	pods, err := m.clientSet.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing pods")
+	}
+	ready := map[workload]int{}
+	for _, pod := range pods.Items {
+		if w, ok := podWorkload(pod); ok && !oldNodes.Has(pod.Spec.NodeName) && isPodReady(pod) {
+			ready[w]++
+		}
+	}
+	pending := sets.NewString()
+	for w, expected := range workloads {
+		if ready[w] < expected {
+			pending.Insert(fmt.Sprintf("%s (%d/%d ready)", w, ready[w], expected))
+		}
+	}
+	return pending.List(), nil
+}
+
+func (m *Manager) deleteReplacedNodeGroup(ctx context.Context, nodeGroupType api.NodeGroupType, name string, disableProtection bool) error {
+	if nodeGroupType == api.NodeGroupTypeManaged {
+		ng := api.NewManagedNodeGroup()
+		ng.Name = name
+		return m.Delete(ctx, nil, []*api.ManagedNodeGroup{ng}, true, false, disableProtection)
+	}
+
+	ng := api.NewNodeGroup()
+	ng.Name = name
+	if err := m.ctl.GetNodeGroupIAM(m.stackManager, ng); err != nil {
+		logger.Warning("continuing with deletion, error getting instance role ARN for nodegroup %q: %v", name, err)
+	}
+	if err := m.Delete(ctx, []*api.NodeGroup{ng}, nil, true, false, disableProtection); err != nil {
+		return err
+	}
+	if ng.IAM != nil && ng.IAM.InstanceRoleARN != "" {
+		if err := authconfigmap.RemoveNodeGroup(m.clientSet, ng); err != nil {
+			logger.Warning(err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package nodegroup_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/weaveworks/eksctl/pkg/actions/nodegroup"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Replace nodegroup", func() {
+	var (
+		clientSet   *fake.Clientset
+		m           *nodegroup.Manager
+		waitTimeout time.Duration
+		pods        int
+	)
+
+	createPod := func(nodeName, ownerKind, ownerName string, ready bool) string {
+		pods++
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		isController := true
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%d", ownerName, pods),
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{
+					Kind:       ownerKind,
+					Name:       ownerName,
+					UID:        types.UID(ownerName),
+					Controller: &isController,
+				}},
+			},
+			Spec:   corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+		}
+		_, err := clientSet.CoreV1().Pods("default").Create(context.TODO(), pod, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		return pod.Name
+	}
+
+	deletePod := func(name string) {
+		Expect(clientSet.CoreV1().Pods("default").Delete(context.TODO(), name, metav1.DeleteOptions{})).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		waitTimeout = mockprovider.ProviderConfig.WaitTimeout
+		mockprovider.ProviderConfig.WaitTimeout = 50 * time.Millisecond
+		nodegroup.SetPollInterval(time.Millisecond)
+
+		pods = 0
+		clientSet = fake.NewSimpleClientset()
+		ctl := &eks.ClusterProvider{Provider: mockprovider.NewMockProvider()}
+		m = nodegroup.New(api.NewClusterConfig(), ctl, clientSet)
+	})
+
+	AfterEach(func() {
+		mockprovider.ProviderConfig.WaitTimeout = waitTimeout
+	})
+
+	It("waits until the drained workloads are ready on other nodes", func() {
+		web1 := createPod("old-1", "ReplicaSet", "web", true)
+		web2 := createPod("old-1", "ReplicaSet", "web", true)
+		daemon := createPod("old-1", "DaemonSet", "logs", true)
+		createPod("new-1", "StatefulSet", "db", true)
+
+		err := m.DrainAndWaitForRescheduling([]string{"old-1"}, func() {
+			deletePod(web1)
+			deletePod(web2)
+			deletePod(daemon)
+			createPod("new-1", "ReplicaSet", "web", true)
+			createPod("new-1", "ReplicaSet", "web", true)
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("times out when the drained workloads are not ready on other nodes", func() {
+		web1 := createPod("old-1", "ReplicaSet", "web", true)
+		web2 := createPod("old-1", "ReplicaSet", "web", true)
+
+		err := m.DrainAndWaitForRescheduling([]string{"old-1"}, func() {
+			deletePod(web1)
+			deletePod(web2)
+			createPod("new-1", "ReplicaSet", "web", true)
+			createPod("new-1", "ReplicaSet", "web", false)
+		})
+		Expect(err).To(MatchError(ContainSubstring("timed out (after 50ms) waiting for workloads to be rescheduled: [ReplicaSet default/web (1/2 ready)]")))
+	})
+
+	It("refuses to replace a nodegroup whose stack is protected before creating anything", func() {
+		fakeStackManager := new(fakes.FakeStackManager)
+		fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: "old", Type: api.NodeGroupTypeUnmanaged}}, nil)
+		fakeStackManager.DescribeNodeGroupStackReturns(&manager.Stack{
+			StackName:                   aws.String("eksctl-test-nodegroup-old"),
+			EnableTerminationProtection: aws.Bool(true),
+		}, nil)
+		m.SetStackManager(fakeStackManager)
+
+		err := m.Replace(context.Background(), nodegroup.ReplaceOptions{NodeGroupName: "old"}, filter.NewNodeGroupFilter())
+		Expect(err).To(MatchError(`nodegroup "old" cannot be replaced: termination protection is enabled for stack(s) eksctl-test-nodegroup-old, use --disable-protection to delete them`))
+		Expect(fakeStackManager.DescribeNodeGroupStackArgsForCall(0)).To(Equal("old"))
+		Expect(fakeStackManager.NewUnmanagedNodeGroupTaskCallCount()).To(Equal(0))
+		Expect(fakeStackManager.NewManagedNodeGroupTaskCallCount()).To(Equal(0))
+	})
+})
@@ -1,7 +1,9 @@
 package nodegroup
 
 import (
+	"context"
 	"fmt"
+	"sort"
 
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
@@ -16,14 +18,44 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 )
 
-func (m *Manager) Scale(ng *api.NodeGroupBase) error {
-	logger.Info("scaling nodegroup %q in cluster %s", ng.Name, m.cfg.Metadata.Name)
-
+func (m *Manager) Scale(ctx context.Context, ng *api.NodeGroupBase) error {
 	nodegroupStackInfos, err := m.stackManager.DescribeNodeGroupStacksAndResources()
 	if err != nil {
 		return err
 	}
 
+	if _, ok := nodegroupStackInfos[ng.Name]; !ok {
+		if perAZNodeGroups := perAZNodeGroupNames(nodegroupStackInfos, ng.Name); len(perAZNodeGroups) > 0 {
+			logger.Info("scaling each of the %d nodegroups nodegroup %q was expanded into", len(perAZNodeGroups), ng.Name)
+			for _, name := range perAZNodeGroups {
+				azNodeGroup := *ng
+				azNodeGroup.Name = name
+				if err := m.scale(ctx, &azNodeGroup, nodegroupStackInfos); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return m.scale(ctx, ng, nodegroupStackInfos)
+}
+
+// perAZNodeGroupNames returns the names of the nodegroups a nodegroup with perAvailabilityZone enabled was expanded into
+func perAZNodeGroupNames(nodegroupStackInfos map[string]manager.StackInfo, name string) []string {
+	var names []string
+	for ngName, stackInfo := range nodegroupStackInfos {
+		if manager.GetNodeGroupPerAZGroup(stackInfo.Stack.Tags) == name {
+			names = append(names, ngName)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *Manager) scale(ctx context.Context, ng *api.NodeGroupBase, nodegroupStackInfos map[string]manager.StackInfo) error {
+	logger.Info("scaling nodegroup %q in cluster %s", ng.Name, m.cfg.Metadata.Name)
+
+	var err error
 	var stackInfo manager.StackInfo
 	var ok, isUnmanagedNodegroup bool
 	stackInfo, ok = nodegroupStackInfos[ng.Name]
@@ -38,7 +70,7 @@ func (m *Manager) Scale(ng *api.NodeGroupBase) error {
 	if isUnmanagedNodegroup {
 		err = m.scaleUnmanagedNodeGroup(ng, stackInfo)
 	} else {
-		err = m.scaleManagedNodeGroup(ng)
+		err = m.scaleManagedNodeGroup(ctx, ng)
 	}
 
 	if err != nil {
@@ -86,7 +118,7 @@ func (m *Manager) scaleUnmanagedNodeGroup(ng *api.NodeGroupBase, stackInfo manag
 	return nil
 }
 
-func (m *Manager) scaleManagedNodeGroup(ng *api.NodeGroupBase) error {
+func (m *Manager) scaleManagedNodeGroup(ctx context.Context, ng *api.NodeGroupBase) error {
 	scalingConfig := &eks.NodegroupScalingConfig{}
 
 	if ng.MaxSize != nil {
@@ -130,7 +162,7 @@ func (m *Manager) scaleManagedNodeGroup(ng *api.NodeGroupBase) error {
 		},
 	)
 
-	err = m.wait(ng.Name, msg, acceptors, newRequest, m.ctl.Provider.WaitTimeout(), nil)
+	err = m.wait(ctx, ng.Name, msg, acceptors, newRequest, m.ctl.Provider.WaitTimeout(), nil)
 	if err != nil {
 		return err
 	}
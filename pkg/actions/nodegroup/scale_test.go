@@ -1,6 +1,7 @@
 package nodegroup_test
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -86,12 +87,12 @@ var _ = Describe("Scale", func() {
 			}).Return(&request.Request{}, nil)
 
 			waitCallCount := 0
-			m.SetWaiter(func(name, msg string, acceptors []request.WaiterAcceptor, newRequest func() *request.Request, waitTimeout time.Duration, troubleshoot func(string) error) error {
+			m.SetWaiter(func(_ context.Context, name, msg string, acceptors []request.WaiterAcceptor, newRequest func() *request.Request, waitTimeout time.Duration, troubleshoot func(string) error) error {
 				waitCallCount++
 				return nil
 			})
 
-			err := m.Scale(ng)
+			err := m.Scale(context.Background(), ng)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(waitCallCount).To(Equal(1))
@@ -108,7 +109,7 @@ var _ = Describe("Scale", func() {
 					NodegroupName: &ngName,
 				}).Return(nil, fmt.Errorf("foo"))
 
-				err := m.Scale(ng)
+				err := m.Scale(context.Background(), ng)
 
 				Expect(err).To(MatchError(fmt.Sprintf("failed to scale nodegroup for cluster %q, error: foo", clusterName)))
 			})
@@ -150,7 +151,7 @@ var _ = Describe("Scale", func() {
 			})
 
 			It("scales the nodegroup", func() {
-				err := m.Scale(ng)
+				err := m.Scale(context.Background(), ng)
 				Expect(err).NotTo(HaveOccurred())
 			})
 		})
@@ -178,9 +179,54 @@ var _ = Describe("Scale", func() {
 			})
 
 			It("returns an error", func() {
-				err := m.Scale(ng)
+				err := m.Scale(context.Background(), ng)
 				Expect(err).To(MatchError(ContainSubstring("failed to find NodeGroup auto scaling group")))
 			})
 		})
 	})
+
+	Describe("Nodegroup with perAvailabilityZone enabled", func() {
+		BeforeEach(func() {
+			nodegroups := make(map[string]manager.StackInfo)
+			for _, az := range []string{"us-west-2a", "us-west-2b"} {
+				name := fmt.Sprintf("%s-%s", ngName, az)
+				nodegroups[name] = manager.StackInfo{
+					Stack: &manager.Stack{
+						Tags: []*cloudformation.Tag{
+							{
+								Key:   aws.String(api.NodeGroupNameTag),
+								Value: aws.String(name),
+							},
+							{
+								Key:   aws.String(api.NodeGroupTypeTag),
+								Value: aws.String(string(api.NodeGroupTypeUnmanaged)),
+							},
+							{
+								Key:   aws.String(api.NodeGroupPerAZGroupTag),
+								Value: aws.String(ngName),
+							},
+						},
+					},
+					Resources: []*cloudformation.StackResource{
+						{
+							PhysicalResourceId: aws.String("asg-" + az),
+							LogicalResourceId:  aws.String("NodeGroup"),
+						},
+					},
+				}
+				p.MockASG().On("UpdateAutoScalingGroup", &autoscaling.UpdateAutoScalingGroupInput{
+					AutoScalingGroupName: aws.String("asg-" + az),
+					MinSize:              aws.Int64(1),
+					DesiredCapacity:      aws.Int64(3),
+				}).Return(nil, nil)
+			}
+			fakeStackManager.DescribeNodeGroupStacksAndResourcesReturns(nodegroups, nil)
+		})
+
+		It("scales each of the nodegroups it was expanded into", func() {
+			err := m.Scale(context.Background(), ng)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.MockASG().AssertNumberOfCalls(GinkgoT(), "UpdateAutoScalingGroup", 2)).To(BeTrue())
+		})
+	})
 })
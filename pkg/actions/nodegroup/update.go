@@ -1,17 +1,29 @@
 package nodegroup
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
 	"github.com/weaveworks/eksctl/pkg/managed"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
 )
 
-func (m *Manager) Update() error {
+func (m *Manager) Update(ctx context.Context) error {
+	for _, ng := range m.cfg.NodeGroups {
+		if err := m.updateUnmanagedNodegroup(ctx, ng); err != nil {
+			return err
+		}
+	}
 	for _, ng := range m.cfg.ManagedNodeGroups {
 		if err := m.updateNodegroup(ng); err != nil {
 			return err
@@ -71,3 +83,81 @@ func updateUpdateConfig(ng *api.ManagedNodeGroup) (*eks.NodegroupUpdateConfig, e
 
 	return updateConfig, nil
 }
+
+// updateUnmanagedNodegroup updates the scheduled actions, lifecycle hooks and warm pool of the
+// Auto Scaling group of a self-managed nodegroup through a stack update
+func (m *Manager) updateUnmanagedNodegroup(ctx context.Context, ng *api.NodeGroup) error {
+	stack, err := m.findStack(ng.Name)
+	if err != nil {
+		return err
+	}
+	if stack == nil {
+		return fmt.Errorf("could not find nodegroup with name %q", ng.Name)
+	}
+	if stack.Type != api.NodeGroupTypeUnmanaged {
+		return fmt.Errorf("nodegroup %q is a managed nodegroup, it must be defined in managedNodeGroups", ng.Name)
+	}
+
+	ngStack, err := m.stackManager.DescribeNodeGroupStack(ng.Name)
+	if err != nil {
+		return err
+	}
+	template, err := m.stackManager.GetStackTemplate(*ngStack.StackName)
+	if err != nil {
+		return errors.Wrap(err, "error fetching nodegroup template")
+	}
+	if ng.WarmPool != nil {
+		userData := gjson.Get(template, "Resources.NodeGroupLaunchTemplate.Properties.LaunchTemplateData.UserData").String()
+		if !nodebootstrap.BootstrapsAfterWarmPool(userData) {
+			return fmt.Errorf("cannot add a warm pool to nodegroup %q, as its nodes are bootstrapped while in the warm pool; create a new nodegroup with the warm pool instead", ng.Name)
+		}
+	}
+
+	updated, err := setASGFeatures(template, ng)
+	if err != nil {
+		return errors.Wrapf(err, "unexpected error updating the template of nodegroup %q", ng.Name)
+	}
+	if resourcesEqual(updated, template) {
+		logger.Info("nodegroup %s is already up-to-date", ng.Name)
+		return nil
+	}
+
+	logger.Info("updating scheduled actions, lifecycle hooks and warm pool of nodegroup %s", ng.Name)
+	if err := m.stackManager.UpdateNodeGroupStack(ctx, ng.Name, updated, true, false); err != nil {
+		return errors.Wrapf(err, "failed to update nodegroup %s", ng.Name)
+	}
+	logger.Info("nodegroup %s successfully updated", ng.Name)
+	return nil
+}
+
+// resourcesEqual compares the resources of two templates, as setASGFeatures moves the resources
+// it replaces to the end of the template
+func resourcesEqual(a, b string) bool {
+	return reflect.DeepEqual(gjson.Get(a, "Resources").Value(), gjson.Get(b, "Resources").Value())
+}
+
+// setASGFeatures replaces the scheduled actions, lifecycle hooks and warm pool in a nodegroup template
+func setASGFeatures(template string, ng *api.NodeGroup) (string, error) {
+	var err error
+	for name, resource := range gjson.Get(template, "Resources").Map() {
+		if builder.IsNodeGroupASGResource(resource.Get("Type").String()) {
+			if template, err = sjson.Delete(template, "Resources."+name); err != nil {
+				return "", err
+			}
+		}
+	}
+	for name, resource := range builder.NodeGroupASGResources(ng) {
+		if template, err = sjson.Set(template, "Resources."+name, resource); err != nil {
+			return "", err
+		}
+	}
+
+	const lifecycleHooksPath = "Resources.NodeGroup.Properties.LifecycleHookSpecificationList"
+	if hooks := builder.LifecycleHookSpecifications(ng); len(hooks) > 0 {
+		return sjson.Set(template, lifecycleHooksPath, hooks)
+	}
+	if gjson.Get(template, lifecycleHooksPath).Exists() {
+		return sjson.Delete(template, lifecycleHooksPath)
+	}
+	return template, nil
+}
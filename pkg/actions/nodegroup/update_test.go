@@ -1,16 +1,24 @@
 package nodegroup
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/tidwall/gjson"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
+	"github.com/weaveworks/eksctl/pkg/cloudconfig"
 	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
 	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
 )
 
@@ -44,7 +52,7 @@ var _ = Describe("Update", func() {
 		}).Return(nil, awserr.New(awseks.ErrCodeResourceNotFoundException, "test-err", errors.New("err")))
 
 		m = New(cfg, &eks.ClusterProvider{Provider: p}, nil)
-		err := m.Update()
+		err := m.Update(context.Background())
 		Expect(err).To(HaveOccurred())
 		Expect(err).To(MatchError(ContainSubstring("could not find managed nodegroup with name \"my-ng\"")))
 	})
@@ -74,7 +82,7 @@ var _ = Describe("Update", func() {
 		}
 
 		m = New(cfg, &eks.ClusterProvider{Provider: p}, nil)
-		err := m.Update()
+		err := m.Update(context.Background())
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -133,7 +141,85 @@ var _ = Describe("Update", func() {
 		cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, newNg)
 
 		m = New(cfg, &eks.ClusterProvider{Provider: p}, nil)
-		err := m.Update()
+		err := m.Update(context.Background())
 		Expect(err).NotTo(HaveOccurred())
 	})
+
+	It("updates the scheduled actions, lifecycle hooks and warm pool of self-managed nodegroups", func() {
+		fakeStackManager := new(fakes.FakeStackManager)
+		fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: "ng-1", Type: api.NodeGroupTypeUnmanaged}}, nil)
+		fakeStackManager.DescribeNodeGroupStackReturns(&manager.Stack{StackName: aws.String("eksctl-my-cluster-nodegroup-ng-1")}, nil)
+		fakeStackManager.GetStackTemplateReturns(fmt.Sprintf(`{"Resources": {
+			"NodeGroupLaunchTemplate": {"Type": "AWS::EC2::LaunchTemplate", "Properties": {"LaunchTemplateData": {"UserData": %q}}},
+			"NodeGroup": {"Type": "AWS::AutoScaling::AutoScalingGroup", "Properties": {"LifecycleHookSpecificationList": [{"LifecycleHookName": "old"}]}},
+			"ScheduledActionold": {"Type": "AWS::AutoScaling::ScheduledAction", "Properties": {}}
+		}}`, warmPoolUserData()), nil)
+
+		cfg.ManagedNodeGroups = nil
+		cfg.NodeGroups = []*api.NodeGroup{{
+			NodeGroupBase:    &api.NodeGroupBase{Name: "ng-1"},
+			ScheduledActions: []api.ScheduledAction{{Name: "nightly", Recurrence: "0 20 * * *", DesiredCapacity: aws.Int(0)}},
+			WarmPool:         &api.WarmPool{MinSize: aws.Int(1)},
+		}}
+
+		m = New(cfg, &eks.ClusterProvider{Provider: p}, nil)
+		m.stackManager = fakeStackManager
+		Expect(m.Update(context.Background())).To(Succeed())
+
+		Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(1))
+		_, name, template, wait, plan := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
+		Expect(name).To(Equal("ng-1"))
+		Expect(wait).To(BeTrue())
+		Expect(plan).To(BeFalse())
+		Expect(gjson.Get(template, "Resources.ScheduledActionold").Exists()).To(BeFalse())
+		Expect(gjson.Get(template, "Resources.NodeGroup.Properties.LifecycleHookSpecificationList").Exists()).To(BeFalse())
+		Expect(gjson.Get(template, "Resources.ScheduledActionnightly.Properties.Recurrence").String()).To(Equal("0 20 * * *"))
+		Expect(gjson.Get(template, "Resources.ScheduledActionnightly.Properties.AutoScalingGroupName.Ref").String()).To(Equal("NodeGroup"))
+		Expect(gjson.Get(template, "Resources.NodeGroupWarmPool.Properties.MinSize").String()).To(Equal("1"))
+
+		By("not updating the stack again once the template is up-to-date")
+		var parsed map[string]interface{}
+		Expect(json.Unmarshal([]byte(template), &parsed)).To(Succeed())
+		reformatted, err := json.MarshalIndent(parsed, "", "  ")
+		Expect(err).NotTo(HaveOccurred())
+		fakeStackManager.GetStackTemplateReturns(string(reformatted), nil)
+		Expect(m.Update(context.Background())).To(Succeed())
+		Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(1))
+	})
+
+	It("does not add a warm pool to self-managed nodegroups whose nodes are bootstrapped when they are launched", func() {
+		fakeStackManager := new(fakes.FakeStackManager)
+		fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: "ng-1", Type: api.NodeGroupTypeUnmanaged}}, nil)
+		fakeStackManager.DescribeNodeGroupStackReturns(&manager.Stack{StackName: aws.String("eksctl-my-cluster-nodegroup-ng-1")}, nil)
+		userData, err := cloudconfig.New().Encode()
+		Expect(err).NotTo(HaveOccurred())
+		fakeStackManager.GetStackTemplateReturns(fmt.Sprintf(`{"Resources": {
+			"NodeGroupLaunchTemplate": {"Type": "AWS::EC2::LaunchTemplate", "Properties": {"LaunchTemplateData": {"UserData": %q}}},
+			"NodeGroup": {"Type": "AWS::AutoScaling::AutoScalingGroup", "Properties": {}}
+		}}`, userData), nil)
+
+		cfg.ManagedNodeGroups = nil
+		cfg.NodeGroups = []*api.NodeGroup{{
+			NodeGroupBase: &api.NodeGroupBase{Name: "ng-1"},
+			WarmPool:      &api.WarmPool{MinSize: aws.Int(1)},
+		}}
+
+		m = New(cfg, &eks.ClusterProvider{Provider: p}, nil)
+		m.stackManager = fakeStackManager
+		err = m.Update(context.Background())
+		Expect(err).To(MatchError(ContainSubstring(`cannot add a warm pool to nodegroup "ng-1"`)))
+		Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(0))
+	})
 })
+
+// warmPoolUserData returns the userdata of a nodegroup whose nodes are bootstrapped once they leave the warm pool
+func warmPoolUserData() string {
+	clusterConfig := api.NewClusterConfig()
+	clusterConfig.Status = &api.ClusterStatus{}
+	ng := api.NewNodeGroup()
+	ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+	ng.WarmPool = &api.WarmPool{}
+	userData, err := nodebootstrap.NewAL2Bootstrapper(clusterConfig, ng).UserData()
+	Expect(err).NotTo(HaveOccurred())
+	return userData
+}
@@ -1,6 +1,7 @@
 package nodegroup
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -10,14 +11,15 @@ import (
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/managed"
 	"github.com/weaveworks/eksctl/pkg/utils/waiters"
 )
 
-func (m *Manager) Upgrade(options managed.UpgradeOptions) error {
+func (m *Manager) Upgrade(ctx context.Context, options managed.UpgradeOptions) error {
 	stackCollection := manager.NewStackCollection(m.ctl.Provider, m.cfg)
-	hasStacks, err := m.hasStacks(options.NodegroupName)
+	stack, err := m.findStack(options.NodegroupName)
 	if err != nil {
 		return err
 	}
@@ -28,9 +30,22 @@ func (m *Manager) Upgrade(options managed.UpgradeOptions) error {
 		}
 	}
 
-	if hasStacks {
+	if stack != nil && stack.Type == api.NodeGroupTypeUnmanaged {
+		return m.upgradeUnmanaged(ctx, options)
+	}
+
+	if options.AMI != "" || options.UpdateConfig != nil || options.InstanceRefresh {
+		return errors.New("--ami, --max-unavailable, --max-unavailable-percentage and --instance-refresh are only supported for self-managed nodegroups")
+	}
+
+	if stack != nil {
 		managedService := managed.NewService(m.ctl.Provider.EKS(), m.ctl.Provider.SSM(), m.ctl.Provider.EC2(), stackCollection, m.cfg.Metadata.Name)
-		return managedService.UpgradeNodeGroup(options)
+		return managedService.UpgradeNodeGroup(ctx, options)
+	}
+
+	if options.Plan {
+		logger.Info("(plan) nodegroup %q was not created by eksctl, it would be upgraded through the EKS API without a stack update", options.NodegroupName)
+		return nil
 	}
 
 	if err := m.upgrade(options); err != nil {
@@ -38,7 +53,7 @@ func (m *Manager) Upgrade(options managed.UpgradeOptions) error {
 	}
 
 	if options.Wait {
-		return m.waitForUpgrade(options)
+		return m.waitForUpgrade(ctx, options)
 	}
 
 	logger.Info("nodegroup upgrade request submitted successfully")
@@ -105,7 +120,7 @@ func (m *Manager) upgrade(options managed.UpgradeOptions) error {
 	return nil
 }
 
-func (m *Manager) waitForUpgrade(options managed.UpgradeOptions) error {
+func (m *Manager) waitForUpgrade(ctx context.Context, options managed.UpgradeOptions) error {
 
 	newRequest := func() *request.Request {
 		input := &eks.DescribeNodegroupInput{
@@ -126,7 +141,7 @@ func (m *Manager) waitForUpgrade(options managed.UpgradeOptions) error {
 		},
 	)
 
-	err := m.wait(options.NodegroupName, msg, acceptors, newRequest, m.ctl.Provider.WaitTimeout(), nil)
+	err := m.wait(ctx, options.NodegroupName, msg, acceptors, newRequest, m.ctl.Provider.WaitTimeout(), nil)
 	if err != nil {
 		return err
 	}
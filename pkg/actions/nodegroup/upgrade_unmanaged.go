@@ -0,0 +1,445 @@
+package nodegroup
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/blang/semver"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/weaveworks/eksctl/pkg/ami"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/drain"
+	"github.com/weaveworks/eksctl/pkg/managed"
+)
+
+const (
+	launchTemplateDataPath = "Resources.NodeGroupLaunchTemplate.Properties.LaunchTemplateData"
+	mixedInstanceTypePath  = "Resources.NodeGroup.Properties.MixedInstancesPolicy.LaunchTemplate.Overrides.0.InstanceType"
+	defaultMaxGracePeriod  = 10 * time.Minute
+)
+
+// pollInterval is how often the replacement of instances is checked
+var pollInterval = 15 * time.Second
+
+// imageFamilies maps the names of EKS-optimized AMIs to their image family
+var imageFamilies = []struct {
+	prefix, contains, family string
+}{
+	{"amazon-eks-node-al2022-", "", api.NodeImageFamilyAmazonLinux2022},
+	{"amazon-eks-node-", "", api.NodeImageFamilyAmazonLinux2},
+	{"amazon-eks-gpu-node-", "", api.NodeImageFamilyAmazonLinux2},
+	{"amazon-eks-arm64-node-", "", api.NodeImageFamilyAmazonLinux2},
+	{"bottlerocket-aws-k8s-", "", api.NodeImageFamilyBottlerocket},
+	{"Flatcar-stable-", "", api.NodeImageFamilyFlatcar},
+	{"ubuntu-eks/k8s_", "20.04", api.NodeImageFamilyUbuntu2004},
+	{"ubuntu-eks/k8s_", "18.04", api.NodeImageFamilyUbuntu1804},
+	{"Windows_Server-2019-English-Core-EKS_Optimized-", "", api.NodeImageFamilyWindowsServer2019CoreContainer},
+	{"Windows_Server-2019-English-Full-EKS_Optimized-", "", api.NodeImageFamilyWindowsServer2019FullContainer},
+	{"Windows_Server-2004-English-Core-EKS_Optimized-", "", api.NodeImageFamilyWindowsServer2004CoreContainer},
+	{"Windows_Server-20H2-English-Core-EKS_Optimized-", "", api.NodeImageFamilyWindowsServer20H2CoreContainer},
+}
+
+// upgradeUnmanaged upgrades a self-managed nodegroup by updating the AMI of its launch template, and
+// then replacing the instances that use an older version of the launch template
+func (m *Manager) upgradeUnmanaged(ctx context.Context, options managed.UpgradeOptions) error {
+	if options.LaunchTemplateVersion != "" || options.ReleaseVersion != "" || options.ForceUpgrade {
+		return errors.New("--launch-template-version, --release-version and --force-upgrade are only supported for managed nodegroups")
+	}
+	if err := validateUpdateConfig(options.UpdateConfig); err != nil {
+		return err
+	}
+
+	stack, err := m.stackManager.DescribeNodeGroupStack(options.NodegroupName)
+	if err != nil {
+		return err
+	}
+	template, err := m.stackManager.GetStackTemplate(*stack.StackName)
+	if err != nil {
+		return errors.Wrap(err, "error fetching nodegroup template")
+	}
+
+	currentAMI := gjson.Get(template, launchTemplateDataPath+".ImageId").String()
+	if currentAMI == "" {
+		return fmt.Errorf("nodegroup %q does not use a launch template with an AMI, it must be replaced with a new nodegroup", options.NodegroupName)
+	}
+
+	newAMI, err := m.resolveUpgradeAMI(template, currentAMI, options)
+	if err != nil {
+		return err
+	}
+
+	if newAMI == currentAMI {
+		logger.Info("nodegroup %q already uses AMI %q", options.NodegroupName, currentAMI)
+	} else {
+		logger.Info("upgrading nodegroup %q from AMI %q to %q", options.NodegroupName, currentAMI, newAMI)
+		template, err = sjson.Set(template, launchTemplateDataPath+".ImageId", newAMI)
+		if err != nil {
+			return errors.Wrap(err, "unexpected error updating the AMI in the nodegroup template")
+		}
+		if err := m.stackManager.UpdateNodeGroupStack(ctx, options.NodegroupName, template, true, options.Plan); err != nil {
+			return errors.Wrap(err, "error updating nodegroup stack")
+		}
+	}
+
+	if options.Plan {
+		logger.Info("(plan) instances of nodegroup %q using an older launch template version would be replaced", options.NodegroupName)
+		return nil
+	}
+
+	asgName, err := m.stackManager.GetAutoScalingGroupName(stack)
+	if err != nil {
+		return err
+	}
+
+	if options.InstanceRefresh {
+		err = m.refreshInstances(ctx, asgName, options)
+	} else {
+		err = m.replaceOutdatedInstances(ctx, asgName, options)
+	}
+	if err != nil {
+		return err
+	}
+	logger.Success("nodegroup %q successfully upgraded", options.NodegroupName)
+	return nil
+}
+
+// resolveUpgradeAMI returns the AMI set in the options, or else the EKS-optimized AMI of the
+// same image family as the current AMI for the Kubernetes version
+func (m *Manager) resolveUpgradeAMI(template, currentAMI string, options managed.UpgradeOptions) (string, error) {
+	if options.AMI != "" {
+		return options.AMI, nil
+	}
+
+	version := options.KubernetesVersion
+	if controlPlaneVersion := m.ctl.ControlPlaneVersion(); controlPlaneVersion != "" {
+		if version == "" {
+			version = controlPlaneVersion
+		} else if newer, err := isNewerVersion(version, controlPlaneVersion); err != nil {
+			return "", err
+		} else if newer {
+			return "", fmt.Errorf("cannot upgrade nodegroup to Kubernetes version %s as the control plane uses version %s", version, controlPlaneVersion)
+		}
+	}
+	if version == "" {
+		return "", errors.New("unable to determine the Kubernetes version of the cluster, use --kubernetes-version")
+	}
+
+	instanceType := gjson.Get(template, launchTemplateDataPath+".InstanceType").String()
+	if instanceType == "" {
+		instanceType = gjson.Get(template, mixedInstanceTypePath).String()
+	}
+
+	images, err := m.ctl.Provider.EC2().DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: aws.StringSlice([]string{currentAMI}),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "describing AMI %q", currentAMI)
+	}
+	var imageFamily string
+	if len(images.Images) == 1 {
+		imageFamily = imageFamilyFromName(aws.StringValue(images.Images[0].Name))
+	}
+	if imageFamily == "" {
+		return "", fmt.Errorf("AMI %q is not an EKS-optimized AMI, use --ami to set the AMI to upgrade to", currentAMI)
+	}
+
+	resolver := ami.NewMultiResolver(
+		ami.NewSSMResolver(m.ctl.Provider.SSM()),
+		ami.NewAutoResolver(m.ctl.Provider.EC2()),
+	)
+	return resolver.Resolve(m.ctl.Provider.Region(), version, instanceType, imageFamily)
+}
+
+func imageFamilyFromName(name string) string {
+	for _, f := range imageFamilies {
+		if strings.HasPrefix(name, f.prefix) && strings.Contains(name, f.contains) {
+			return f.family
+		}
+	}
+	return ""
+}
+
+func isNewerVersion(version, than string) (bool, error) {
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid Kubernetes version")
+	}
+	t, err := semver.ParseTolerant(than)
+	if err != nil {
+		return false, errors.Wrapf(err, "unexpected error parsing Kubernetes version %q", than)
+	}
+	return v.Major > t.Major || (v.Major == t.Major && v.Minor > t.Minor), nil
+}
+
+func validateUpdateConfig(updateConfig *api.NodeGroupUpdateConfig) error {
+	if updateConfig == nil {
+		return nil
+	}
+	if updateConfig.MaxUnavailable != nil && updateConfig.MaxUnavailablePercentage != nil {
+		return errors.New("cannot use --max-unavailable and --max-unavailable-percentage at the same time")
+	}
+	if updateConfig.MaxUnavailable != nil && *updateConfig.MaxUnavailable < 1 {
+		return fmt.Errorf("--max-unavailable must be at least 1, got %d", *updateConfig.MaxUnavailable)
+	}
+	if p := updateConfig.MaxUnavailablePercentage; p != nil && (*p < 1 || *p > 100) {
+		return fmt.Errorf("--max-unavailable-percentage must be between 1 and 100, got %d", *p)
+	}
+	return nil
+}
+
+// maxUnavailable returns the number of instances that can be replaced at the same time
+func maxUnavailable(updateConfig *api.NodeGroupUpdateConfig, desiredCapacity int) int {
+	n := 1
+	if updateConfig != nil {
+		if updateConfig.MaxUnavailable != nil {
+			n = *updateConfig.MaxUnavailable
+		} else if updateConfig.MaxUnavailablePercentage != nil {
+			n = desiredCapacity * *updateConfig.MaxUnavailablePercentage / 100
+		}
+	}
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// outdatedInstances returns the instances of the group that do not use the launch template version of the group
+func outdatedInstances(group *autoscaling.Group) []*autoscaling.Instance {
+	version := launchTemplateVersion(group)
+	var outdated []*autoscaling.Instance
+	for _, instance := range group.Instances {
+		if instance.LaunchTemplate == nil || aws.StringValue(instance.LaunchTemplate.Version) != version {
+			outdated = append(outdated, instance)
+		}
+	}
+	return outdated
+}
+
+func launchTemplateVersion(group *autoscaling.Group) string {
+	if group.LaunchTemplate != nil {
+		return aws.StringValue(group.LaunchTemplate.Version)
+	}
+	if p := group.MixedInstancesPolicy; p != nil && p.LaunchTemplate != nil && p.LaunchTemplate.LaunchTemplateSpecification != nil {
+		return aws.StringValue(p.LaunchTemplate.LaunchTemplateSpecification.Version)
+	}
+	return ""
+}
+
+func (m *Manager) describeAutoScalingGroup(name string) (*autoscaling.Group, error) {
+	out, err := m.ctl.Provider.ASG().DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: aws.StringSlice([]string{name}),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing ASG %q", name)
+	}
+	if len(out.AutoScalingGroups) != 1 {
+		return nil, fmt.Errorf("couldn't find ASG %q", name)
+	}
+	return out.AutoScalingGroups[0], nil
+}
+
+// replaceOutdatedInstances cordons and drains the nodes of outdated instances in batches bounded
+// by the update config, terminates their instances and waits for the replacements to become ready
+func (m *Manager) replaceOutdatedInstances(ctx context.Context, asgName string, options managed.UpgradeOptions) error {
+	ng := &api.NodeGroupBase{Name: options.NodegroupName}
+	maxGracePeriod := options.MaxGracePeriod
+	if maxGracePeriod == 0 {
+		maxGracePeriod = defaultMaxGracePeriod
+	}
+	drainer := drain.NewNodeGroupDrainer(m.clientSet, ng, m.ctl.Provider.WaitTimeout(), maxGracePeriod, false, options.DisableEviction, 1)
+	if err := drainer.SetDrainPolicy(m.cfg.NodeGroupDrainPolicy(options.NodegroupName)); err != nil {
+		return err
+	}
+
+	for {
+		group, err := m.describeAutoScalingGroup(asgName)
+		if err != nil {
+			return err
+		}
+		outdated := outdatedInstances(group)
+		if len(outdated) == 0 {
+			return nil
+		}
+		batchSize := maxUnavailable(options.UpdateConfig, int(aws.Int64Value(group.DesiredCapacity)))
+		if len(outdated) > batchSize {
+			outdated = outdated[:batchSize]
+		}
+
+		instanceIDs := sets.NewString()
+		for _, instance := range outdated {
+			instanceIDs.Insert(aws.StringValue(instance.InstanceId))
+		}
+		logger.Info("replacing %d outdated instance(s) of nodegroup %q: %v", instanceIDs.Len(), options.NodegroupName, instanceIDs.List())
+
+		nodes, err := m.clientSet.CoreV1().Nodes().List(ctx, ng.ListOptions())
+		if err != nil {
+			return errors.Wrap(err, "listing nodes")
+		}
+		var nodeNames []string
+		for _, node := range nodes.Items {
+			if instanceIDs.Has(instanceIDFromProviderID(node.Spec.ProviderID)) {
+				nodeNames = append(nodeNames, node.Name)
+			}
+		}
+		if err := drainer.DrainNodes(nodeNames); err != nil {
+			return err
+		}
+
+		for _, instanceID := range instanceIDs.List() {
+			_, err := m.ctl.Provider.ASG().TerminateInstanceInAutoScalingGroup(&autoscaling.TerminateInstanceInAutoScalingGroupInput{
+				InstanceId:                     aws.String(instanceID),
+				ShouldDecrementDesiredCapacity: aws.Bool(false),
+			})
+			if err != nil {
+				return errors.Wrapf(err, "terminating instance %q", instanceID)
+			}
+		}
+
+		if err := m.waitForReplacements(ctx, asgName, ng, instanceIDs); err != nil {
+			return err
+		}
+	}
+}
+
+// waitForReplacements waits until the terminated instances have left the group and the
+// group has as many in-service instances with ready nodes as its desired capacity
+func (m *Manager) waitForReplacements(ctx context.Context, asgName string, ng *api.NodeGroupBase, terminated sets.String) error {
+	logger.Info("waiting for replacements of instances %v to become ready", terminated.List())
+	timeout := time.After(m.ctl.Provider.WaitTimeout())
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		done, err := m.replacementsReady(ctx, asgName, ng, terminated)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		select {
+		case <-timeout:
+			return fmt.Errorf("timed out (after %s) waiting for replacements of instances %v to become ready", m.ctl.Provider.WaitTimeout(), terminated.List())
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) replacementsReady(ctx context.Context, asgName string, ng *api.NodeGroupBase, terminated sets.String) (bool, error) {
+	group, err := m.describeAutoScalingGroup(asgName)
+	if err != nil {
+		return false, err
+	}
+	nodes, err := m.clientSet.CoreV1().Nodes().List(ctx, ng.ListOptions())
+	if err != nil {
+		return false, errors.Wrap(err, "listing nodes")
+	}
+	readyInstances := sets.NewString()
+	for _, node := range nodes.Items {
+		if isNodeReady(&node) {
+			readyInstances.Insert(instanceIDFromProviderID(node.Spec.ProviderID))
+		}
+	}
+
+	ready := 0
+	for _, instance := range group.Instances {
+		instanceID := aws.StringValue(instance.InstanceId)
+		if terminated.Has(instanceID) {
+			return false, nil
+		}
+		if aws.StringValue(instance.LifecycleState) == autoscaling.LifecycleStateInService && readyInstances.Has(instanceID) {
+			ready++
+		}
+	}
+	logger.Debug("%d of %d instance(s) of ASG %q are ready", ready, aws.Int64Value(group.DesiredCapacity), asgName)
+	return int64(ready) >= aws.Int64Value(group.DesiredCapacity), nil
+}
+
+// refreshInstances replaces all instances of the group using an ASG instance refresh; the nodes
+// are not drained by eksctl
+func (m *Manager) refreshInstances(ctx context.Context, asgName string, options managed.UpgradeOptions) error {
+	group, err := m.describeAutoScalingGroup(asgName)
+	if err != nil {
+		return err
+	}
+	desiredCapacity := int(aws.Int64Value(group.DesiredCapacity))
+	minHealthyPercentage := 100 - int(math.Ceil(100*float64(maxUnavailable(options.UpdateConfig, desiredCapacity))/math.Max(1, float64(desiredCapacity))))
+	if minHealthyPercentage < 0 {
+		minHealthyPercentage = 0
+	}
+
+	logger.Warning("nodes of nodegroup %q are not drained by eksctl during an instance refresh, pods are rescheduled when their nodes are terminated", options.NodegroupName)
+	out, err := m.ctl.Provider.ASG().StartInstanceRefresh(&autoscaling.StartInstanceRefreshInput{
+		AutoScalingGroupName: aws.String(asgName),
+		Strategy:             aws.String(autoscaling.RefreshStrategyRolling),
+		Preferences: &autoscaling.RefreshPreferences{
+			MinHealthyPercentage: aws.Int64(int64(minHealthyPercentage)),
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "starting instance refresh of ASG %q", asgName)
+	}
+	refreshID := aws.StringValue(out.InstanceRefreshId)
+	logger.Info("started instance refresh %q of ASG %q with a minimum healthy percentage of %d%%", refreshID, asgName, minHealthyPercentage)
+
+	timeout := time.After(m.ctl.Provider.WaitTimeout())
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		refreshes, err := m.ctl.Provider.ASG().DescribeInstanceRefreshes(&autoscaling.DescribeInstanceRefreshesInput{
+			AutoScalingGroupName: aws.String(asgName),
+			InstanceRefreshIds:   aws.StringSlice([]string{refreshID}),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "describing instance refresh %q", refreshID)
+		}
+		if len(refreshes.InstanceRefreshes) != 1 {
+			return fmt.Errorf("couldn't find instance refresh %q", refreshID)
+		}
+		refresh := refreshes.InstanceRefreshes[0]
+		switch status := aws.StringValue(refresh.Status); status {
+		case autoscaling.InstanceRefreshStatusSuccessful:
+			return nil
+		case autoscaling.InstanceRefreshStatusFailed, autoscaling.InstanceRefreshStatusCancelled, autoscaling.InstanceRefreshStatusCancelling:
+			return fmt.Errorf("instance refresh %q of ASG %q is %s: %s", refreshID, asgName, strings.ToLower(status), aws.StringValue(refresh.StatusReason))
+		default:
+			logger.Info("instance refresh %q is %d%% complete", refreshID, aws.Int64Value(refresh.PercentageComplete))
+		}
+		select {
+		case <-timeout:
+			return fmt.Errorf("timed out (after %s) waiting for instance refresh %q to complete", m.ctl.Provider.WaitTimeout(), refreshID)
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// instanceIDFromProviderID returns the instance ID of a provider ID such as aws:///us-west-2a/i-0123456789
+func instanceIDFromProviderID(providerID string) string {
+	return providerID[strings.LastIndex(providerID, "/")+1:]
+}
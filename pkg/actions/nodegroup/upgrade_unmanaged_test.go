@@ -0,0 +1,191 @@
+package nodegroup_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/weaveworks/eksctl/pkg/actions/nodegroup"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/managed"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Upgrade self-managed nodegroup", func() {
+	const (
+		ngName  = "ng-1"
+		asgName = "eksctl-my-cluster-nodegroup-ng-1-NodeGroup"
+	)
+
+	var (
+		p                *mockprovider.MockProvider
+		clientSet        *fake.Clientset
+		fakeStackManager *fakes.FakeStackManager
+		m                *nodegroup.Manager
+		instances        []*autoscaling.Instance
+		launched         int
+	)
+
+	newNode := func(instanceID string) {
+		_, err := clientSet.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "node-" + instanceID,
+				Labels: map[string]string{api.NodeGroupNameLabel: ngName},
+			},
+			Spec: corev1.NodeSpec{ProviderID: "aws:///us-west-2a/" + instanceID},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	newInstance := func(instanceID, version string) *autoscaling.Instance {
+		newNode(instanceID)
+		return &autoscaling.Instance{
+			InstanceId:     aws.String(instanceID),
+			LifecycleState: aws.String(autoscaling.LifecycleStateInService),
+			LaunchTemplate: &autoscaling.LaunchTemplateSpecification{Version: aws.String(version)},
+		}
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "my-cluster"
+		clientSet = fake.NewSimpleClientset()
+		ctl := &eks.ClusterProvider{
+			Provider: p,
+			Status: &eks.ProviderStatus{
+				ClusterInfo: &eks.ClusterInfo{Cluster: &awseks.Cluster{Version: aws.String("1.21")}},
+			},
+		}
+		m = nodegroup.New(cfg, ctl, clientSet)
+		nodegroup.SetPollInterval(time.Millisecond)
+
+		fakeStackManager = new(fakes.FakeStackManager)
+		m.SetStackManager(fakeStackManager)
+		fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: ngName, Type: api.NodeGroupTypeUnmanaged}}, nil)
+		fakeStackManager.DescribeNodeGroupStackReturns(&manager.Stack{StackName: aws.String("eksctl-my-cluster-nodegroup-ng-1")}, nil)
+		fakeStackManager.GetStackTemplateReturns(`{"Resources": {"NodeGroupLaunchTemplate": {"Type": "AWS::EC2::LaunchTemplate", "Properties": {"LaunchTemplateData": {"ImageId": "ami-old", "InstanceType": "m5.large"}}}}}`, nil)
+		fakeStackManager.GetAutoScalingGroupNameReturns(asgName, nil)
+
+		launched = 0
+		instances = []*autoscaling.Instance{newInstance("i-1", "1"), newInstance("i-2", "1"), newInstance("i-3", "1")}
+		p.MockASG().On("DescribeAutoScalingGroups", mock.Anything).Return(func(*autoscaling.DescribeAutoScalingGroupsInput) *autoscaling.DescribeAutoScalingGroupsOutput {
+			return &autoscaling.DescribeAutoScalingGroupsOutput{
+				AutoScalingGroups: []*autoscaling.Group{{
+					AutoScalingGroupName: aws.String(asgName),
+					DesiredCapacity:      aws.Int64(3),
+					LaunchTemplate:       &autoscaling.LaunchTemplateSpecification{Version: aws.String("2")},
+					Instances:            instances,
+				}},
+			}
+		}, nil)
+		p.MockASG().On("TerminateInstanceInAutoScalingGroup", mock.Anything).Run(func(args mock.Arguments) {
+			input := args.Get(0).(*autoscaling.TerminateInstanceInAutoScalingGroupInput)
+			Expect(*input.ShouldDecrementDesiredCapacity).To(BeFalse())
+			var remaining []*autoscaling.Instance
+			for _, instance := range instances {
+				if *instance.InstanceId != *input.InstanceId {
+					remaining = append(remaining, instance)
+				}
+			}
+			launched++
+			instances = append(remaining, newInstance(fmt.Sprintf("i-new-%d", launched), "2"))
+		}).Return(&autoscaling.TerminateInstanceInAutoScalingGroupOutput{}, nil)
+	})
+
+	It("updates the AMI and replaces outdated instances in batches", func() {
+		maxUnavailable := 2
+		err := m.Upgrade(context.Background(), managed.UpgradeOptions{
+			NodegroupName: ngName,
+			AMI:           "ami-new",
+			UpdateConfig:  &api.NodeGroupUpdateConfig{MaxUnavailable: &maxUnavailable},
+			Wait:          true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(1))
+		_, name, template, wait, plan := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
+		Expect(name).To(Equal(ngName))
+		Expect(template).To(ContainSubstring(`"ImageId": "ami-new"`))
+		Expect(wait).To(BeTrue())
+		Expect(plan).To(BeFalse())
+
+		Expect(launched).To(Equal(3))
+		for _, instance := range instances {
+			Expect(*instance.LaunchTemplate.Version).To(Equal("2"))
+		}
+		node, err := clientSet.CoreV1().Nodes().Get(context.TODO(), "node-i-1", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(node.Spec.Unschedulable).To(BeTrue())
+		node, err = clientSet.CoreV1().Nodes().Get(context.TODO(), "node-i-new-1", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(node.Spec.Unschedulable).To(BeFalse())
+	})
+
+	It("resolves the EKS-optimized AMI of the same family for the control plane version", func() {
+		p.MockEC2().On("DescribeImages", &ec2.DescribeImagesInput{ImageIds: aws.StringSlice([]string{"ami-old"})}).
+			Return(&ec2.DescribeImagesOutput{Images: []*ec2.Image{{Name: aws.String("amazon-eks-node-1.20-v20211013")}}}, nil)
+		p.MockSSM().On("GetParameter", &ssm.GetParameterInput{
+			Name: aws.String("/aws/service/eks/optimized-ami/1.21/amazon-linux-2/recommended/image_id"),
+		}).Return(&ssm.GetParameterOutput{Parameter: &ssm.Parameter{Value: aws.String("ami-1.21")}}, nil)
+
+		Expect(m.Upgrade(context.Background(), managed.UpgradeOptions{NodegroupName: ngName, Plan: true})).To(Succeed())
+
+		Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(1))
+		_, _, template, _, plan := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
+		Expect(template).To(ContainSubstring(`"ImageId": "ami-1.21"`))
+		Expect(plan).To(BeTrue())
+		Expect(launched).To(Equal(0))
+	})
+
+	It("detects nodes created from an AmazonLinux2022 AMI and rejects versions the AMI is not published for", func() {
+		p.MockEC2().On("DescribeImages", &ec2.DescribeImagesInput{ImageIds: aws.StringSlice([]string{"ami-old"})}).
+			Return(&ec2.DescribeImagesOutput{Images: []*ec2.Image{{Name: aws.String("amazon-eks-node-al2022-x86_64-standard-1.20-v20211013")}}}, nil)
+
+		err := m.Upgrade(context.Background(), managed.UpgradeOptions{NodegroupName: ngName, Plan: true})
+		Expect(err).To(MatchError(ContainSubstring("AmazonLinux2022 requires EKS version 1.22 and above")))
+		Expect(p.MockSSM().AssertNotCalled(GinkgoT(), "GetParameter", mock.Anything)).To(BeTrue())
+		Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(0))
+	})
+
+	It("rejects Kubernetes versions newer than the control plane", func() {
+		err := m.Upgrade(context.Background(), managed.UpgradeOptions{NodegroupName: ngName, KubernetesVersion: "1.22"})
+		Expect(err).To(MatchError("cannot upgrade nodegroup to Kubernetes version 1.22 as the control plane uses version 1.21"))
+		Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(0))
+	})
+
+	It("replaces instances with an instance refresh", func() {
+		p.MockASG().On("StartInstanceRefresh", &autoscaling.StartInstanceRefreshInput{
+			AutoScalingGroupName: aws.String(asgName),
+			Strategy:             aws.String(autoscaling.RefreshStrategyRolling),
+			Preferences:          &autoscaling.RefreshPreferences{MinHealthyPercentage: aws.Int64(66)},
+		}).Return(&autoscaling.StartInstanceRefreshOutput{InstanceRefreshId: aws.String("refresh-1")}, nil)
+		p.MockASG().On("DescribeInstanceRefreshes", mock.Anything).Return(&autoscaling.DescribeInstanceRefreshesOutput{
+			InstanceRefreshes: []*autoscaling.InstanceRefresh{{
+				InstanceRefreshId: aws.String("refresh-1"),
+				Status:            aws.String(autoscaling.InstanceRefreshStatusSuccessful),
+			}},
+		}, nil)
+
+		Expect(m.Upgrade(context.Background(), managed.UpgradeOptions{NodegroupName: ngName, AMI: "ami-new", InstanceRefresh: true})).To(Succeed())
+		Expect(launched).To(Equal(0))
+	})
+})
@@ -85,9 +85,9 @@ func findRootDeviceMapping(image *ec2.Image) (*ec2.BlockDeviceMapping, error) {
 }
 
 // FindImage will get the AMI to use for the EKS nodes by querying AWS EC2 API.
-// It will only look for images with a status of available and it will pick the
-// image with the newest creation date.
-func FindImage(ec2api ec2iface.EC2API, ownerAccount, namePattern string) (string, error) {
+// It will only look for images with a status of available, and of the given architecture
+// if it is not empty, and it will pick the image with the newest creation date.
+func FindImage(ec2api ec2iface.EC2API, ownerAccount, namePattern, architecture string) (string, error) {
 	input := &ec2.DescribeImagesInput{
 		Owners: []*string{&ownerAccount},
 		Filters: []*ec2.Filter{
@@ -113,6 +113,12 @@ func FindImage(ec2api ec2iface.EC2API, ownerAccount, namePattern string) (string
 			},
 		},
 	}
+	if architecture != "" {
+		input.Filters = append(input.Filters, &ec2.Filter{
+			Name:   aws.String("architecture"),
+			Values: []*string{&architecture},
+		})
+	}
 
 	output, err := ec2api.DescribeImages(input)
 	if err != nil {
@@ -15,6 +15,9 @@ const (
 
 	// ownerIDWindowsFamily is the owner ID used for Ubuntu AMIs
 	ownerIDWindowsFamily = "801119661308"
+
+	// ownerIDFlatcarFamily is the owner ID used for Flatcar Container Linux AMIs
+	ownerIDFlatcarFamily = "075585003325"
 )
 
 // MakeImageSearchPatterns creates a map of image search patterns by image OS family and class
@@ -25,6 +28,15 @@ func MakeImageSearchPatterns(version string) map[string]map[int]string {
 			ImageClassGPU:     fmt.Sprintf("amazon-eks-gpu-node-%s-*", version),
 			ImageClassARM:     fmt.Sprintf("amazon-eks-arm64-node-%s-*", version),
 		},
+		api.NodeImageFamilyAmazonLinux2022: {
+			ImageClassGeneral: fmt.Sprintf("amazon-eks-node-al2022-x86_64-standard-%s-v*", version),
+			ImageClassARM:     fmt.Sprintf("amazon-eks-node-al2022-arm64-standard-%s-v*", version),
+		},
+		// Flatcar AMIs are not built for a specific Kubernetes version, the kubelet is downloaded when bootstrapping
+		api.NodeImageFamilyFlatcar: {
+			ImageClassGeneral: "Flatcar-stable-*-hvm",
+			ImageClassARM:     "Flatcar-stable-*-arm64-hvm",
+		},
 		api.NodeImageFamilyUbuntu2004: {
 			ImageClassGeneral: fmt.Sprintf("ubuntu-eks/k8s_%s/images/*20.04-amd64*", version),
 			ImageClassARM:     fmt.Sprintf("ubuntu-eks/k8s_%s/images/*20.04-arm64*", version),
@@ -52,8 +64,10 @@ func OwnerAccountID(imageFamily, region string) (string, error) {
 	switch imageFamily {
 	case api.NodeImageFamilyUbuntu2004, api.NodeImageFamilyUbuntu1804:
 		return ownerIDUbuntuFamily, nil
-	case api.NodeImageFamilyAmazonLinux2:
+	case api.NodeImageFamilyAmazonLinux2, api.NodeImageFamilyAmazonLinux2022:
 		return api.EKSResourceAccountID(region), nil
+	case api.NodeImageFamilyFlatcar:
+		return ownerIDFlatcarFamily, nil
 	default:
 		if api.IsWindowsImage(imageFamily) {
 			return ownerIDWindowsFamily, nil
@@ -99,7 +113,14 @@ func (r *AutoResolver) Resolve(region, version, instanceType, imageFamily string
 		return "", NewErrFailedResolution(region, version, instanceType, imageFamily)
 	}
 
-	id, err := FindImage(r.api, ownerAccount, namePattern)
+	// the name patterns of the Flatcar images match the images of all architectures, so the images
+	// of the AMI families bootstrapped by eksctl are filtered by the architecture of the instance type
+	var architecture string
+	if imageFamily == api.NodeImageFamilyFlatcar || imageFamily == api.NodeImageFamilyAmazonLinux2022 {
+		architecture = instanceEC2ArchName(instanceType)
+	}
+
+	id, err := FindImage(r.api, ownerAccount, namePattern, architecture)
 	if err != nil {
 		return "", fmt.Errorf("error getting AMI from EC2 API: %w. please verify that AMI Family is supported", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/mock"
 	. "github.com/weaveworks/eksctl/pkg/ami"
@@ -57,6 +58,18 @@ var _ = Describe("AMI Auto Resolution", func() {
 				Expect(ownerAccount).To(BeEquivalentTo("099720109477"))
 				Expect(err).NotTo(HaveOccurred())
 			})
+
+			It("should return the AWS Account ID for AmazonLinux2022 images", func() {
+				ownerAccount, err := OwnerAccountID(api.NodeImageFamilyAmazonLinux2022, region)
+				Expect(ownerAccount).To(BeEquivalentTo("602401143452"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should return the Flatcar Account ID for Flatcar images", func() {
+				ownerAccount, err := OwnerAccountID(api.NodeImageFamilyFlatcar, region)
+				Expect(ownerAccount).To(BeEquivalentTo("075585003325"))
+				Expect(err).NotTo(HaveOccurred())
+			})
 		})
 
 		Context("with a valid region and N instance type", func() {
@@ -211,6 +224,64 @@ var _ = Describe("AMI Auto Resolution", func() {
 					})
 				})
 			})
+
+			Context("and Flatcar family", func() {
+				BeforeEach(func() {
+					imageFamily = "Flatcar"
+					p = mockprovider.NewMockProvider()
+				})
+
+				It("should only look up images of the architecture of the instance type", func() {
+					p.MockEC2().On("DescribeImages", mock.MatchedBy(func(input *ec2.DescribeImagesInput) bool {
+						filters := map[string]string{}
+						for _, filter := range input.Filters {
+							filters[*filter.Name] = *filter.Values[0]
+						}
+						return filters["name"] == "Flatcar-stable-*-arm64-hvm" && filters["architecture"] == "arm64"
+					})).Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{{
+							ImageId:      aws.String(expectedAmi),
+							State:        aws.String("available"),
+							CreationDate: aws.String("2018-08-20T23:25:53.000Z"),
+						}},
+					}, nil)
+
+					resolver := NewAutoResolver(p.MockEC2())
+					resolvedAmi, err = resolver.Resolve(region, version, "a1.large", imageFamily)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resolvedAmi).To(BeEquivalentTo(expectedAmi))
+				})
+			})
+
+			Context("and AmazonLinux2022 family", func() {
+				It("should only look up images of the architecture of the instance type", func() {
+					p = mockprovider.NewMockProvider()
+					addMockDescribeImagesWithArchitecture(p, "amazon-eks-node-al2022-x86_64-standard-1.15-v*", "x86_64", expectedAmi)
+
+					resolver := NewAutoResolver(p.MockEC2())
+					resolvedAmi, err = resolver.Resolve(region, version, "t2.medium", "AmazonLinux2022")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resolvedAmi).To(BeEquivalentTo(expectedAmi))
+				})
+			})
+
+			DescribeTable("other families do not filter images by architecture",
+				func(imageFamily, instanceType, namePattern string) {
+					p = mockprovider.NewMockProvider()
+					addMockDescribeImagesWithArchitecture(p, namePattern, "", expectedAmi)
+
+					resolver := NewAutoResolver(p.MockEC2())
+					resolvedAmi, err = resolver.Resolve(region, version, instanceType, imageFamily)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resolvedAmi).To(BeEquivalentTo(expectedAmi))
+				},
+				Entry("AmazonLinux2", api.NodeImageFamilyAmazonLinux2, "t2.medium", "amazon-eks-node-1.15-v*"),
+				Entry("AmazonLinux2 GPU", api.NodeImageFamilyAmazonLinux2, "p2.xlarge", "amazon-eks-gpu-node-1.15-*"),
+				Entry("AmazonLinux2 ARM", api.NodeImageFamilyAmazonLinux2, "a1.large", "amazon-eks-arm64-node-1.15-*"),
+				Entry("Ubuntu2004 ARM", api.NodeImageFamilyUbuntu2004, "a1.large", "ubuntu-eks/k8s_1.15/images/*20.04-arm64*"),
+				Entry("Ubuntu1804", api.NodeImageFamilyUbuntu1804, "t2.medium", "ubuntu-eks/k8s_1.15/images/*18.04*"),
+				Entry("WindowsServer2019CoreContainer", api.NodeImageFamilyWindowsServer2019CoreContainer, "t2.medium", "Windows_Server-2019-English-Core-EKS_Optimized-1.15-*"),
+			)
 		})
 	})
 })
@@ -268,3 +339,23 @@ func addMockDescribeImagesMultiple(p *mockprovider.MockProvider, expectedNamePat
 		Images: images,
 	}, nil)
 }
+
+// addMockDescribeImagesWithArchitecture mocks a lookup of the name pattern that is filtered by the architecture,
+// or that has no architecture filter if it is empty
+func addMockDescribeImagesWithArchitecture(p *mockprovider.MockProvider, expectedNamePattern, expectedArchitecture, amiID string) {
+	p.MockEC2().On("DescribeImages",
+		mock.MatchedBy(func(input *ec2.DescribeImagesInput) bool {
+			filters := map[string]string{}
+			for _, filter := range input.Filters {
+				filters[*filter.Name] = *filter.Values[0]
+			}
+			return filters["name"] == expectedNamePattern && filters["architecture"] == expectedArchitecture
+		}),
+	).Return(&ec2.DescribeImagesOutput{
+		Images: []*ec2.Image{{
+			ImageId:      aws.String(amiID),
+			State:        aws.String("available"),
+			CreationDate: aws.String("2018-08-20T23:25:53.000Z"),
+		}},
+	}, nil)
+}
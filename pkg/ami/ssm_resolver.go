@@ -79,7 +79,15 @@ func MakeSSMParameterName(version, instanceType, imageFamily string) (string, er
 		return fmt.Sprintf("/aws/service/ami-windows-latest/Windows_Server-20H2-English-Core-EKS_Optimized-%s/%s", version, fieldName), nil
 	case api.NodeImageFamilyBottlerocket:
 		return fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/%s/latest/%s", version, instanceEC2ArchName(instanceType), fieldName), nil
-	case api.NodeImageFamilyUbuntu2004, api.NodeImageFamilyUbuntu1804:
+	case api.NodeImageFamilyAmazonLinux2022:
+		if err := api.ValidateAMIFamilyVersion(imageFamily, version); err != nil {
+			return "", err
+		}
+		if instanceutils.IsGPUInstanceType(instanceType) {
+			return "", errors.Errorf("GPU instance types are not supported for %s", imageFamily)
+		}
+		return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2022/%s/standard/recommended/%s", version, instanceEC2ArchName(instanceType), fieldName), nil
+	case api.NodeImageFamilyUbuntu2004, api.NodeImageFamilyUbuntu1804, api.NodeImageFamilyFlatcar:
 		return "", &UnsupportedQueryError{msg: fmt.Sprintf("SSM Parameter lookups for %s AMIs is not supported yet", imageFamily)}
 	default:
 		return "", fmt.Errorf("unknown image family %s", imageFamily)
@@ -301,6 +301,78 @@ var _ = Describe("AMI Auto Resolution", func() {
 				})
 			})
 
+			Context("and AmazonLinux2022 image family", func() {
+				BeforeEach(func() {
+					instanceType = "t2.medium"
+					imageFamily = "AmazonLinux2022"
+					version = "1.22"
+				})
+
+				Context("and ami is available", func() {
+					BeforeEach(func() {
+						p = mockprovider.NewMockProvider()
+						addMockGetParameter(p, "/aws/service/eks/optimized-ami/1.22/amazon-linux-2022/x86_64/standard/recommended/image_id", expectedAmi)
+						resolver := NewSSMResolver(p.MockSSM())
+						resolvedAmi, err = resolver.Resolve(region, version, instanceType, imageFamily)
+					})
+
+					It("should not error", func() {
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("should have returned an ami id", func() {
+						Expect(resolvedAmi).To(BeEquivalentTo(expectedAmi))
+					})
+				})
+
+				Context("for arm instance type", func() {
+					BeforeEach(func() {
+						instanceType = "a1.large"
+						p = mockprovider.NewMockProvider()
+						addMockGetParameter(p, "/aws/service/eks/optimized-ami/1.22/amazon-linux-2022/arm64/standard/recommended/image_id", expectedAmi)
+						resolver := NewSSMResolver(p.MockSSM())
+						resolvedAmi, err = resolver.Resolve(region, version, instanceType, imageFamily)
+					})
+
+					It("should have returned an ami id", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(resolvedAmi).To(BeEquivalentTo(expectedAmi))
+					})
+				})
+
+				Context("for gpu instance type", func() {
+					It("should return an error", func() {
+						p = mockprovider.NewMockProvider()
+						resolver := NewSSMResolver(p.MockSSM())
+						_, err = resolver.Resolve(region, version, "p2.xlarge", imageFamily)
+
+						Expect(err).To(MatchError("GPU instance types are not supported for AmazonLinux2022"))
+						Expect(p.MockSSM().AssertNumberOfCalls(GinkgoT(), "GetParameter", 0)).To(BeTrue())
+					})
+				})
+
+				Context("for a version the AMI is not published for", func() {
+					It("should return an error", func() {
+						p = mockprovider.NewMockProvider()
+						resolver := NewSSMResolver(p.MockSSM())
+						_, err = resolver.Resolve(region, "1.21", instanceType, imageFamily)
+
+						Expect(err).To(MatchError("AmazonLinux2022 requires EKS version 1.22 and above"))
+						Expect(p.MockSSM().AssertNumberOfCalls(GinkgoT(), "GetParameter", 0)).To(BeTrue())
+					})
+				})
+			})
+
+			Context("and Flatcar image family", func() {
+				It("should return an unsupported query error", func() {
+					p = mockprovider.NewMockProvider()
+					resolver := NewSSMResolver(p.MockSSM())
+					_, err = resolver.Resolve(region, version, "t2.medium", "Flatcar")
+
+					Expect(err).To(BeAssignableToTypeOf(&UnsupportedQueryError{}))
+				})
+			})
+
 		})
 	})
 })
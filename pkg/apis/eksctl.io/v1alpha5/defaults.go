@@ -202,6 +202,11 @@ func setVolumeDefaults(ng *NodeGroupBase, template *LaunchTemplate) {
 
 func setContainerRuntimeDefault(ng *NodeGroup) {
 	if ng.ContainerRuntime == nil {
+		// neither AmazonLinux2022 nor Flatcar nodes are bootstrapped with dockerd
+		if ng.AMIFamily == NodeImageFamilyAmazonLinux2022 || ng.AMIFamily == NodeImageFamilyFlatcar {
+			ng.ContainerRuntime = aws.String(ContainerRuntimeContainerD)
+			return
+		}
 		ng.ContainerRuntime = &DefaultContainerRuntime
 	}
 }
@@ -0,0 +1,117 @@
+package v1alpha5
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// DrainPolicy controls how the nodes of a nodegroup are drained by `drain nodegroup`,
+// `delete nodegroup`, `delete cluster` and when nodes are replaced by an upgrade.
+// When set both cluster-wide and on a nodegroup, lists are combined and the
+// nodegroup's settings take precedence
+type DrainPolicy struct {
+	// IgnoreDaemonSets lists DaemonSets whose pods are left on the nodes,
+	// in addition to the ones that are always ignored
+	// +optional
+	IgnoreDaemonSets []DrainPolicyDaemonSet `json:"ignoreDaemonSets,omitempty"`
+
+	// IgnoreNamespaces lists namespaces whose pods are left on the nodes
+	// +optional
+	IgnoreNamespaces []string `json:"ignoreNamespaces,omitempty"`
+
+	// NeverForceDelete lists label selectors, e.g. `app=etcd`, of pods that are
+	// only ever evicted, and never deleted, even when eviction is disabled or
+	// blocked by a PodDisruptionBudget for longer than the PDB timeout
+	// +optional
+	NeverForceDelete []string `json:"neverForceDelete,omitempty"`
+
+	// NamespaceGracePeriodSeconds overrides the maximum termination grace period
+	// of the pods in the given namespaces
+	// +optional
+	NamespaceGracePeriodSeconds map[string]int `json:"namespaceGracePeriodSeconds,omitempty"`
+
+	// NodeTimeoutSeconds is how long draining a single node may take before the drain fails
+	// +optional
+	NodeTimeoutSeconds *int `json:"nodeTimeoutSeconds,omitempty"`
+
+	// WaitForReplacements waits for the controllers of the pods evicted from a node
+	// to have all their replicas Ready again before draining the next node.
+	// Defaults to `false`
+	// +optional
+	WaitForReplacements *bool `json:"waitForReplacements,omitempty"`
+}
+
+// DrainPolicyDaemonSet identifies a DaemonSet whose pods are not evicted
+type DrainPolicyDaemonSet struct {
+	Name string `json:"name"`
+	// Namespace of the DaemonSet, DaemonSets with the given name are
+	// ignored in all namespaces if not set
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// NodeGroupDrainPolicy returns the drain policy of the given nodegroup, combining the
+// cluster-wide policy with the nodegroup's; it returns nil if neither is set
+func (c *ClusterConfig) NodeGroupDrainPolicy(name string) *DrainPolicy {
+	var nodeGroupPolicy *DrainPolicy
+	for _, ng := range c.AllNodeGroups() {
+		if ng.Name == name {
+			nodeGroupPolicy = ng.DrainPolicy
+			break
+		}
+	}
+	return mergeDrainPolicies(c.DrainPolicy, nodeGroupPolicy)
+}
+
+func mergeDrainPolicies(clusterPolicy, nodeGroupPolicy *DrainPolicy) *DrainPolicy {
+	if clusterPolicy == nil {
+		return nodeGroupPolicy
+	}
+	if nodeGroupPolicy == nil {
+		return clusterPolicy
+	}
+
+	merged := clusterPolicy.DeepCopy()
+	merged.IgnoreDaemonSets = append(merged.IgnoreDaemonSets, nodeGroupPolicy.IgnoreDaemonSets...)
+	merged.IgnoreNamespaces = append(merged.IgnoreNamespaces, nodeGroupPolicy.IgnoreNamespaces...)
+	merged.NeverForceDelete = append(merged.NeverForceDelete, nodeGroupPolicy.NeverForceDelete...)
+	if len(nodeGroupPolicy.NamespaceGracePeriodSeconds) > 0 && merged.NamespaceGracePeriodSeconds == nil {
+		merged.NamespaceGracePeriodSeconds = map[string]int{}
+	}
+	for namespace, gracePeriod := range nodeGroupPolicy.NamespaceGracePeriodSeconds {
+		merged.NamespaceGracePeriodSeconds[namespace] = gracePeriod
+	}
+	if nodeGroupPolicy.NodeTimeoutSeconds != nil {
+		merged.NodeTimeoutSeconds = nodeGroupPolicy.NodeTimeoutSeconds
+	}
+	if nodeGroupPolicy.WaitForReplacements != nil {
+		merged.WaitForReplacements = nodeGroupPolicy.WaitForReplacements
+	}
+	return merged
+}
+
+func validateDrainPolicy(policy *DrainPolicy, path string) error {
+	if policy == nil {
+		return nil
+	}
+	for i, ds := range policy.IgnoreDaemonSets {
+		if ds.Name == "" {
+			return fmt.Errorf("%s.ignoreDaemonSets[%d].name must be set", path, i)
+		}
+	}
+	for i, selector := range policy.NeverForceDelete {
+		if _, err := labels.Parse(selector); err != nil {
+			return fmt.Errorf("%s.neverForceDelete[%d] is not a valid label selector: %v", path, i, err)
+		}
+	}
+	for namespace, gracePeriod := range policy.NamespaceGracePeriodSeconds {
+		if gracePeriod < 0 {
+			return fmt.Errorf("%s.namespaceGracePeriodSeconds[%s] cannot be negative", path, namespace)
+		}
+	}
+	if policy.NodeTimeoutSeconds != nil && *policy.NodeTimeoutSeconds <= 0 {
+		return fmt.Errorf("%s.nodeTimeoutSeconds must be greater than 0", path)
+	}
+	return nil
+}
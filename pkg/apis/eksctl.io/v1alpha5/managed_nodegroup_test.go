@@ -58,6 +58,24 @@ var _ = Describe("Managed Nodegroup Validation", func() {
 				},
 			},
 		}),
+		Entry("Custom Flatcar AMI without overrideBootstrapCommand", &nodeGroupCase{
+			ng: &ManagedNodeGroup{
+				NodeGroupBase: &NodeGroupBase{
+					AMI:            "ami-custom",
+					AMIFamily:      NodeImageFamilyFlatcar,
+					MaxPodsPerNode: 30,
+				},
+			},
+		}),
+		Entry("Custom AmazonLinux2022 AMI without overrideBootstrapCommand", &nodeGroupCase{
+			ng: &ManagedNodeGroup{
+				NodeGroupBase: &NodeGroupBase{
+					AMI:            "ami-custom",
+					AMIFamily:      NodeImageFamilyAmazonLinux2022,
+					MaxPodsPerNode: 30,
+				},
+			},
+		}),
 		Entry("launchTemplate with no ID", &nodeGroupCase{
 			ng: &ManagedNodeGroup{
 				NodeGroupBase:  &NodeGroupBase{},
@@ -21,6 +21,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
 	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/pkg/errors"
@@ -183,12 +184,18 @@ const (
 	NodeImageFamilyUbuntu1804   = "Ubuntu1804"
 	NodeImageFamilyBottlerocket = "Bottlerocket"
 
+	NodeImageFamilyAmazonLinux2022 = "AmazonLinux2022"
+	NodeImageFamilyFlatcar         = "Flatcar"
+
 	NodeImageFamilyWindowsServer2019CoreContainer = "WindowsServer2019CoreContainer"
 	NodeImageFamilyWindowsServer2019FullContainer = "WindowsServer2019FullContainer"
 	NodeImageFamilyWindowsServer2004CoreContainer = "WindowsServer2004CoreContainer"
 	NodeImageFamilyWindowsServer20H2CoreContainer = "WindowsServer20H2CoreContainer"
 )
 
+// AmazonLinux2022MinVersion is the earliest Kubernetes version the Amazon Linux 2022 EKS AMI is published for
+const AmazonLinux2022MinVersion = Version1_22
+
 // Container runtime values.
 const (
 	ContainerRuntimeContainerD = "containerd"
@@ -223,6 +230,10 @@ const (
 	// NodeGroupTypeTag defines the nodegroup type as managed or unmanaged
 	NodeGroupTypeTag = "alpha.eksctl.io/nodegroup-type"
 
+	// NodeGroupPerAZGroupTag defines the tag of the name of the nodegroup with
+	// perAvailabilityZone enabled a nodegroup was expanded from
+	NodeGroupPerAZGroupTag = "alpha.eksctl.io/nodegroup-per-az-group"
+
 	// OldNodeGroupNameTag defines the tag of the nodegroup name
 	OldNodeGroupNameTag = "eksctl.io/v1alpha2/nodegroup-name"
 
@@ -235,6 +246,12 @@ const (
 	// AddonNameTag defines the tag of the IAM service account name
 	AddonNameTag = "alpha.eksctl.io/addon-name"
 
+	// TemplateBucketTag records the S3 bucket the template of a stack was uploaded to
+	TemplateBucketTag = "alpha.eksctl.io/cfn-template-bucket"
+
+	// RetainTag marks resources that are kept when the cluster is deleted
+	RetainTag = "alpha.eksctl.io/retain"
+
 	// ClusterNameLabel defines the tag of the cluster name
 	ClusterNameLabel = "alpha.eksctl.io/cluster-name"
 
@@ -392,6 +409,16 @@ func SupportedRegions() []string {
 	}
 }
 
+// IsSupportedRegion returns true if EKS is available in the given region
+func IsSupportedRegion(region string) bool {
+	for _, supportedRegion := range SupportedRegions() {
+		if region == supportedRegion {
+			return true
+		}
+	}
+	return false
+}
+
 // Partition gives the partition a region belongs to
 func Partition(region string) string {
 	switch region {
@@ -468,6 +495,8 @@ func supportedAMIFamilies() []string {
 		NodeImageFamilyUbuntu2004,
 		NodeImageFamilyUbuntu1804,
 		NodeImageFamilyBottlerocket,
+		NodeImageFamilyAmazonLinux2022,
+		NodeImageFamilyFlatcar,
 		NodeImageFamilyWindowsServer2019CoreContainer,
 		NodeImageFamilyWindowsServer2019FullContainer,
 		NodeImageFamilyWindowsServer2004CoreContainer,
@@ -536,6 +565,25 @@ type ClusterMeta struct {
 	// Annotations are arbitrary metadata ignored by `eksctl`.
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// Protection guards the cluster against accidental deletion
+	// +optional
+	Protection *ClusterProtection `json:"protection,omitempty"`
+}
+
+// ClusterProtection holds the settings protecting the cluster against accidental deletion
+type ClusterProtection struct {
+	// TerminationProtection enables CloudFormation termination protection
+	// on the cluster and nodegroup stacks, which then can only be deleted
+	// using `--disable-protection`
+	// +optional
+	TerminationProtection *bool `json:"terminationProtection,omitempty"`
+	// RetainVPC keeps the VPC created by eksctl, along with its subnets, gateways
+	// and route tables, when the cluster is deleted
+	// +optional
+	RetainVPC *bool `json:"retainVPC,omitempty"`
+	// RetainOIDCProvider keeps the IAM OIDC provider when the cluster is deleted
+	// +optional
+	RetainOIDCProvider *bool `json:"retainOIDCProvider,omitempty"`
 }
 
 // KubernetesNetworkConfig contains cluster networking options
@@ -593,6 +641,9 @@ type ClusterProvider interface {
 	CloudFormation() cloudformationiface.CloudFormationAPI
 	CloudFormationRoleARN() string
 	CloudFormationDisableRollback() bool
+	CloudFormationTemplateBucket() string
+	StreamStackEvents() bool
+	MaxParallelStacks() int
 	ASG() autoscalingiface.AutoScalingAPI
 	EKS() eksiface.EKSAPI
 	EC2() ec2iface.EC2API
@@ -601,6 +652,7 @@ type ClusterProvider interface {
 	STS() stsiface.STSAPI
 	SSM() ssmiface.SSMAPI
 	IAM() iamiface.IAMAPI
+	S3() s3iface.S3API
 	CloudTrail() cloudtrailiface.CloudTrailAPI
 	CloudWatchLogs() cloudwatchlogsiface.CloudWatchLogsAPI
 	Region() string
@@ -614,10 +666,19 @@ type ClusterProvider interface {
 type ProviderConfig struct {
 	CloudFormationRoleARN         string
 	CloudFormationDisableRollback bool
+	// CloudFormationTemplateBucket is the S3 bucket that templates too large to be passed
+	// inline are uploaded to, a bucket is created when it is not set
+	CloudFormationTemplateBucket string
 
 	Region      string
 	Profile     string
 	WaitTimeout time.Duration
+
+	// Quiet disables printing stack events while waiting for stacks
+	Quiet bool
+
+	// MaxParallelStacks limits the number of stacks that are created or deleted at the same time, when set
+	MaxParallelStacks int
 }
 
 // +genclient
@@ -673,6 +734,10 @@ type ClusterConfig struct {
 	// +optional
 	SecretsEncryption *SecretsEncryption `json:"secretsEncryption,omitempty"`
 
+	// DrainPolicy controls how the nodes of all nodegroups are drained
+	// +optional
+	DrainPolicy *DrainPolicy `json:"drainPolicy,omitempty"`
+
 	Status *ClusterStatus `json:"-"`
 
 	// future gitops plans, replacing the Git configuration above
@@ -899,6 +964,18 @@ type NodeGroup struct {
 	// ContainerRuntime defines the runtime (CRI) to use for containers on the node
 	// +optional
 	ContainerRuntime *string `json:"containerRuntime,omitempty"`
+
+	// ScheduledActions scale the Auto Scaling group of the nodegroup on a schedule
+	// +optional
+	ScheduledActions []ScheduledAction `json:"scheduledActions,omitempty"`
+
+	// LifecycleHooks are added to the Auto Scaling group of the nodegroup
+	// +optional
+	LifecycleHooks []LifecycleHook `json:"lifecycleHooks,omitempty"`
+
+	// WarmPool keeps pre-initialized instances ready to be added to the nodegroup
+	// +optional
+	WarmPool *WarmPool `json:"warmPool,omitempty"`
 }
 
 // GetContainerRuntime returns the container runtime.
@@ -1112,6 +1189,84 @@ type MetricsCollection struct {
 	Metrics []string `json:"metrics,omitempty"`
 }
 
+// ScheduledAction scales the Auto Scaling group of a nodegroup on a schedule,
+// see [cloudformation
+// docs](https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-resource-as-scheduledaction.html)
+type ScheduledAction struct {
+	// Name of the scheduled action, unique within the nodegroup and only
+	// made of alphanumeric characters
+	// +required
+	Name string `json:"name"`
+	// Recurrence is a cron expression in UTC, e.g. `"0 20 * * 1-5"`
+	// +optional
+	Recurrence string `json:"recurrence,omitempty"`
+	// StartTime is the time the action starts at, in RFC 3339 format
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+	// EndTime is the time a recurring action stops at, in RFC 3339 format
+	// +optional
+	EndTime string `json:"endTime,omitempty"`
+	// +optional
+	DesiredCapacity *int `json:"desiredCapacity,omitempty"`
+	// +optional
+	MinSize *int `json:"minSize,omitempty"`
+	// +optional
+	MaxSize *int `json:"maxSize,omitempty"`
+}
+
+// LifecycleHook pauses instances as they are launched or terminated,
+// see [cloudformation
+// docs](https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-properties-autoscaling-autoscalinggroup-lifecyclehookspecification.html)
+type LifecycleHook struct {
+	// Name of the lifecycle hook, unique within the nodegroup
+	// +required
+	Name string `json:"name"`
+	// LifecycleTransition is either `"autoscaling:EC2_INSTANCE_LAUNCHING"` or
+	// `"autoscaling:EC2_INSTANCE_TERMINATING"`
+	// +required
+	LifecycleTransition string `json:"lifecycleTransition"`
+	// HeartbeatTimeout is the number of seconds an instance stays paused
+	// +optional
+	HeartbeatTimeout *int `json:"heartbeatTimeout,omitempty"`
+	// DefaultResult is the action taken when the timeout elapses, either
+	// `"CONTINUE"` or `"ABANDON"`
+	// +optional
+	DefaultResult string `json:"defaultResult,omitempty"`
+	// NotificationTargetARN is the ARN of the SNS topic or SQS queue notified
+	// when an instance is paused
+	// +optional
+	NotificationTargetARN string `json:"notificationTargetARN,omitempty"`
+	// RoleARN is the ARN of the IAM role allowing the Auto Scaling group to
+	// publish to the notification target
+	// +optional
+	RoleARN string `json:"roleARN,omitempty"`
+	// NotificationMetadata is included in the notifications
+	// +optional
+	NotificationMetadata string `json:"notificationMetadata,omitempty"`
+}
+
+// WarmPool of pre-initialized instances of the Auto Scaling group of a nodegroup,
+// see [cloudformation
+// docs](https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-resource-autoscaling-warmpool.html)
+type WarmPool struct {
+	// MinSize is the minimum number of instances kept in the warm pool
+	// +optional
+	MinSize *int `json:"minSize,omitempty"`
+	// MaxGroupPreparedCapacity is the maximum number of instances in the
+	// warm pool and the Auto Scaling group combined, defaults to the maximum
+	// size of the nodegroup
+	// +optional
+	MaxGroupPreparedCapacity *int `json:"maxGroupPreparedCapacity,omitempty"`
+	// PoolState is the state of the instances in the warm pool, one of
+	// `"Stopped"`, `"Running"` or `"Hibernated"`; defaults to `"Stopped"`
+	// +optional
+	PoolState string `json:"poolState,omitempty"`
+	// ReuseOnScaleIn returns instances to the warm pool on scale in instead of
+	// terminating them
+	// +optional
+	ReuseOnScaleIn *bool `json:"reuseOnScaleIn,omitempty"`
+}
+
 // ScalingConfig defines the scaling config
 type ScalingConfig struct {
 	// +optional
@@ -1150,6 +1305,11 @@ type NodeGroupBase struct {
 	// Limit nodes to specific subnets
 	// +optional
 	Subnets []string `json:"subnets,omitempty"`
+	// PerAvailabilityZone creates one nodegroup, named `<name>-<az>`, in each of the
+	// availability zones of the nodegroup, or of the cluster if none are set.
+	// Defaults to `false`
+	// +optional
+	PerAvailabilityZone *bool `json:"perAvailabilityZone,omitempty"`
 
 	// +optional
 	InstancePrefix string `json:"instancePrefix,omitempty"`
@@ -1262,6 +1422,11 @@ type NodeGroupBase struct {
 	// Enable EC2 detailed monitoring
 	// +optional
 	EnableDetailedMonitoring *bool `json:"enableDetailedMonitoring,omitempty"`
+
+	// DrainPolicy controls how the nodes of this nodegroup are drained,
+	// on top of the cluster-wide drain policy
+	// +optional
+	DrainPolicy *DrainPolicy `json:"drainPolicy,omitempty"`
 }
 
 // Placement specifies placement group information
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	instanceutils "github.com/weaveworks/eksctl/pkg/utils/instance"
 
@@ -16,6 +17,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
+	"github.com/weaveworks/eksctl/pkg/utils"
 	"github.com/weaveworks/eksctl/pkg/utils/taints"
 
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -85,6 +87,10 @@ func ValidateClusterConfig(cfg *ClusterConfig) error {
 		return err
 	}
 
+	if err := validateDrainPolicy(cfg.DrainPolicy, "drainPolicy"); err != nil {
+		return err
+	}
+
 	// names must be unique across both managed and unmanaged nodegroups
 	ngNames := nameSet{}
 	validateNg := func(ng *NodeGroupBase, path string) error {
@@ -118,6 +124,14 @@ func ValidateClusterConfig(cfg *ClusterConfig) error {
 		}
 	}
 
+	if version := resolvedVersion(cfg.Metadata.Version); version != "" {
+		for _, ng := range cfg.AllNodeGroups() {
+			if err := ValidateAMIFamilyVersion(ng.AMIFamily, version); err != nil {
+				return errors.Wrapf(err, "nodegroup %q", ng.Name)
+			}
+		}
+	}
+
 	if err := validateCloudWatchLogging(cfg); err != nil {
 		return err
 	}
@@ -269,6 +283,10 @@ func validateNodeGroupBase(np NodePool, path string) error {
 		return fmt.Errorf("%s.maxPodsPerNode cannot be negative", path)
 	}
 
+	if err := validateDrainPolicy(ng.DrainPolicy, path+".drainPolicy"); err != nil {
+		return err
+	}
+
 	if IsEnabled(ng.DisablePodIMDS) && ng.IAM != nil {
 		fmtFieldConflictErr := func(_ string) error {
 			return fmt.Errorf("%s.disablePodIMDS and %s.iam.withAddonPolicies cannot be set at the same time", path, path)
@@ -282,6 +300,10 @@ func validateNodeGroupBase(np NodePool, path string) error {
 		return fmt.Errorf("only one of %[1]s.subnets or %[1]s.availabilityZones should be set", path)
 	}
 
+	if IsEnabled(ng.PerAvailabilityZone) && len(ng.Subnets) > 0 {
+		return fmt.Errorf("%[1]s.subnets cannot be set when %[1]s.perAvailabilityZone is enabled; use %[1]s.availabilityZones instead", path)
+	}
+
 	if ng.Placement != nil {
 		if ng.Placement.GroupName == "" {
 			return fmt.Errorf("%s.placement.groupName must be set and non-empty", path)
@@ -487,10 +509,26 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		return err
 	}
 
+	if err := validateScheduledActions(ng.ScheduledActions, path); err != nil {
+		return err
+	}
+
+	if err := validateLifecycleHooks(ng.LifecycleHooks, path); err != nil {
+		return err
+	}
+
+	if err := validateWarmPool(ng, path); err != nil {
+		return err
+	}
+
 	if ng.ContainerRuntime != nil {
-		if *ng.ContainerRuntime == ContainerRuntimeContainerD && ng.AMIFamily != NodeImageFamilyAmazonLinux2 {
+		switch {
+		case *ng.ContainerRuntime == ContainerRuntimeContainerD && ng.AMIFamily != NodeImageFamilyAmazonLinux2 &&
+			ng.AMIFamily != NodeImageFamilyAmazonLinux2022 && ng.AMIFamily != NodeImageFamilyFlatcar:
 			// check if it's dockerd or containerd
-			return fmt.Errorf("%s as runtime is only support for AL2 ami family", ContainerRuntimeContainerD)
+			return fmt.Errorf("%s as runtime is only support for AL2, AL2022 and Flatcar ami families", ContainerRuntimeContainerD)
+		case *ng.ContainerRuntime == ContainerRuntimeDockerD && ng.AMIFamily == NodeImageFamilyAmazonLinux2022:
+			return fmt.Errorf("%s as runtime is not supported for the %s ami family", ContainerRuntimeDockerD, NodeImageFamilyAmazonLinux2022)
 		}
 		if *ng.ContainerRuntime != ContainerRuntimeDockerD && *ng.ContainerRuntime != ContainerRuntimeContainerD {
 			return fmt.Errorf("only %s and %s are supported for container runtime", ContainerRuntimeContainerD, ContainerRuntimeDockerD)
@@ -620,7 +658,8 @@ func validateNodeGroupIAM(iam *NodeGroupIAM, value, fieldName, path string) erro
 // ValidateManagedNodeGroup validates a ManagedNodeGroup and sets some defaults
 func ValidateManagedNodeGroup(ng *ManagedNodeGroup, index int) error {
 	switch ng.AMIFamily {
-	case NodeImageFamilyAmazonLinux2, NodeImageFamilyBottlerocket, NodeImageFamilyUbuntu1804, NodeImageFamilyUbuntu2004:
+	case NodeImageFamilyAmazonLinux2, NodeImageFamilyBottlerocket, NodeImageFamilyUbuntu1804, NodeImageFamilyUbuntu2004,
+		NodeImageFamilyAmazonLinux2022, NodeImageFamilyFlatcar:
 
 	default:
 		return errors.Errorf("%q is not supported for managed nodegroups", ng.AMIFamily)
@@ -754,24 +793,27 @@ func ValidateManagedNodeGroup(ng *ManagedNodeGroup, index int) error {
 		if !IsAMI(ng.AMI) {
 			return errors.Errorf("invalid AMI %q (%s.%s)", ng.AMI, path, "ami")
 		}
+		notSupportedWithCustomAMIErr := func(field string) error {
+			return errors.Errorf("%s.%s is not supported when using a custom AMI (%s.ami)", path, field, path)
+		}
+		if ng.ReleaseVersion != "" {
+			return notSupportedWithCustomAMIErr("releaseVersion")
+		}
+		if bootstrapsCustomAMIs(ng.AMIFamily) {
+			break
+		}
 		if ng.AMIFamily != NodeImageFamilyAmazonLinux2 {
 			return errors.Errorf("cannot set amiFamily to %s when using a custom AMI", ng.AMIFamily)
 		}
 		if ng.OverrideBootstrapCommand == nil {
 			return errors.Errorf("%s.overrideBootstrapCommand is required when using a custom AMI (%s.ami)", path, path)
 		}
-		notSupportedWithCustomAMIErr := func(field string) error {
-			return errors.Errorf("%s.%s is not supported when using a custom AMI (%s.ami)", path, field, path)
-		}
 		if ng.MaxPodsPerNode != 0 {
 			return notSupportedWithCustomAMIErr("maxPodsPerNode")
 		}
 		if ng.SSH != nil && IsEnabled(ng.SSH.EnableSSM) {
 			return notSupportedWithCustomAMIErr("enableSSM")
 		}
-		if ng.ReleaseVersion != "" {
-			return notSupportedWithCustomAMIErr("releaseVersion")
-		}
 
 	case ng.OverrideBootstrapCommand != nil:
 		return errors.Errorf("%s.overrideBootstrapCommand can only be set when a custom AMI (%s.ami) is specified", path, path)
@@ -896,6 +938,154 @@ func validateASGSuspendProcesses(ng *NodeGroup) error {
 	return nil
 }
 
+// scheduled action names are part of the logical IDs of their CloudFormation resources
+var scheduledActionNameRegex = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+
+func validateScheduledActions(actions []ScheduledAction, path string) error {
+	names := map[string]bool{}
+	for i, action := range actions {
+		actionPath := fmt.Sprintf("%s.scheduledActions[%d]", path, i)
+		if action.Name == "" {
+			return fmt.Errorf("%s.name must be set", actionPath)
+		}
+		if !scheduledActionNameRegex.MatchString(action.Name) {
+			return fmt.Errorf("%s.name %q is invalid, it can only contain alphanumeric characters", actionPath, action.Name)
+		}
+		if names[action.Name] {
+			return fmt.Errorf("%s.name %q is not unique", actionPath, action.Name)
+		}
+		names[action.Name] = true
+
+		if action.Recurrence == "" && action.StartTime == "" {
+			return fmt.Errorf("at least one of recurrence or startTime must be set in %s", actionPath)
+		}
+		if action.EndTime != "" && action.Recurrence == "" {
+			return fmt.Errorf("%s.endTime can only be set for recurring actions", actionPath)
+		}
+		if err := validateRFC3339Time(action.StartTime, actionPath+".startTime"); err != nil {
+			return err
+		}
+		if err := validateRFC3339Time(action.EndTime, actionPath+".endTime"); err != nil {
+			return err
+		}
+
+		if action.DesiredCapacity == nil && action.MinSize == nil && action.MaxSize == nil {
+			return fmt.Errorf("at least one of desiredCapacity, minSize or maxSize must be set in %s", actionPath)
+		}
+		if action.MinSize != nil && action.MaxSize != nil && *action.MinSize > *action.MaxSize {
+			return fmt.Errorf("%s.minSize must be less than or equal to %s.maxSize", actionPath, actionPath)
+		}
+		for _, size := range []*int{action.DesiredCapacity, action.MinSize, action.MaxSize} {
+			if size != nil && *size < 0 {
+				return fmt.Errorf("sizes cannot be negative in %s", actionPath)
+			}
+		}
+	}
+	return nil
+}
+
+func validateRFC3339Time(value, path string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, value); err != nil {
+		return errors.Wrapf(err, "invalid %s, it must be in RFC 3339 format", path)
+	}
+	return nil
+}
+
+func validateLifecycleHooks(hooks []LifecycleHook, path string) error {
+	names := map[string]bool{}
+	for i, hook := range hooks {
+		hookPath := fmt.Sprintf("%s.lifecycleHooks[%d]", path, i)
+		if hook.Name == "" {
+			return fmt.Errorf("%s.name must be set", hookPath)
+		}
+		if names[hook.Name] {
+			return fmt.Errorf("%s.name %q is not unique", hookPath, hook.Name)
+		}
+		names[hook.Name] = true
+
+		switch hook.LifecycleTransition {
+		case "autoscaling:EC2_INSTANCE_LAUNCHING", "autoscaling:EC2_INSTANCE_TERMINATING":
+		default:
+			return fmt.Errorf("%s.lifecycleTransition must be one of autoscaling:EC2_INSTANCE_LAUNCHING or autoscaling:EC2_INSTANCE_TERMINATING", hookPath)
+		}
+		switch hook.DefaultResult {
+		case "", "CONTINUE", "ABANDON":
+		default:
+			return fmt.Errorf("%s.defaultResult must be one of CONTINUE or ABANDON", hookPath)
+		}
+		// limits taken from https://docs.aws.amazon.com/autoscaling/ec2/APIReference/API_PutLifecycleHook.html
+		if hook.HeartbeatTimeout != nil && (*hook.HeartbeatTimeout < 30 || *hook.HeartbeatTimeout > 7200) {
+			return fmt.Errorf("%s.heartbeatTimeout must be between 30 and 7200 seconds", hookPath)
+		}
+
+		if hook.RoleARN != "" && hook.NotificationTargetARN == "" {
+			return fmt.Errorf("%s.roleARN can only be set together with %s.notificationTargetARN", hookPath, hookPath)
+		}
+		if hook.NotificationTargetARN != "" {
+			if _, err := arn.Parse(hook.NotificationTargetARN); err != nil {
+				return errors.Wrapf(err, "invalid ARN %q in %s.notificationTargetARN", hook.NotificationTargetARN, hookPath)
+			}
+		}
+		if hook.RoleARN != "" {
+			if _, err := arn.Parse(hook.RoleARN); err != nil {
+				return errors.Wrapf(err, "invalid ARN %q in %s.roleARN", hook.RoleARN, hookPath)
+			}
+		}
+	}
+	return nil
+}
+
+func validateWarmPool(ng *NodeGroup, path string) error {
+	if ng.WarmPool == nil {
+		return nil
+	}
+	warmPoolPath := path + ".warmPool"
+	if HasMixedInstances(ng) {
+		return fmt.Errorf("%s cannot be used with instancesDistribution, warm pools do not support mixed instances policies", warmPoolPath)
+	}
+
+	// instances launched into the warm pool must not join the cluster, eksctl only
+	// bootstraps them once they leave it when it generates their bootstrap scripts
+	switch ng.AMIFamily {
+	case "", NodeImageFamilyAmazonLinux2, NodeImageFamilyUbuntu2004, NodeImageFamilyUbuntu1804:
+	default:
+		return fmt.Errorf("%s is only supported for the %s, %s and %s AMI families", warmPoolPath,
+			NodeImageFamilyAmazonLinux2, NodeImageFamilyUbuntu2004, NodeImageFamilyUbuntu1804)
+	}
+	if IsAMI(ng.AMI) {
+		return fmt.Errorf("%s cannot be used with a custom AMI", warmPoolPath)
+	}
+	if ng.OverrideBootstrapCommand != nil {
+		return fmt.Errorf("%s cannot be used with %s.overrideBootstrapCommand", warmPoolPath, path)
+	}
+
+	switch ng.WarmPool.PoolState {
+	case "", "Stopped", "Running", "Hibernated":
+	default:
+		return fmt.Errorf("%s.poolState must be one of Stopped, Running or Hibernated", warmPoolPath)
+	}
+
+	minSize, maxPrepared := ng.WarmPool.MinSize, ng.WarmPool.MaxGroupPreparedCapacity
+	if minSize != nil && *minSize < 0 {
+		return fmt.Errorf("%s.minSize cannot be negative", warmPoolPath)
+	}
+	if maxPrepared != nil {
+		if *maxPrepared < 0 {
+			return fmt.Errorf("%s.maxGroupPreparedCapacity cannot be negative", warmPoolPath)
+		}
+		if minSize != nil && *minSize > *maxPrepared {
+			return fmt.Errorf("%s.minSize must be less than or equal to %s.maxGroupPreparedCapacity", warmPoolPath, warmPoolPath)
+		}
+		if ng.ScalingConfig != nil && ng.MaxSize != nil && *maxPrepared < *ng.MaxSize {
+			return fmt.Errorf("%s.maxGroupPreparedCapacity must be greater than or equal to %s.maxSize", warmPoolPath, path)
+		}
+	}
+	return nil
+}
+
 func validateNodeGroupSSH(SSH *NodeGroupSSH) error {
 	numSSHFlagsEnabled := countEnabledFields(
 		SSH.PublicKeyPath,
@@ -950,6 +1140,42 @@ func isSupportedAMIFamily(imageFamily string) bool {
 	return false
 }
 
+// ValidateAMIFamilyVersion checks that the AMIs of the family are published for the Kubernetes version
+func ValidateAMIFamilyVersion(imageFamily, version string) error {
+	if imageFamily != NodeImageFamilyAmazonLinux2022 {
+		return nil
+	}
+	supportsAL2022, err := utils.IsMinVersion(AmazonLinux2022MinVersion, version)
+	if err != nil {
+		return err
+	}
+	if !supportsAL2022 {
+		return errors.Errorf("%s requires EKS version %s and above", imageFamily, AmazonLinux2022MinVersion)
+	}
+	return nil
+}
+
+// resolvedVersion returns the Kubernetes version the cluster config refers to,
+// or an empty string if it is only known once the control plane is looked up
+func resolvedVersion(version string) string {
+	switch version {
+	case "", "auto":
+		return ""
+	case "default":
+		return DefaultVersion
+	case "latest":
+		return LatestVersion
+	default:
+		return version
+	}
+}
+
+// bootstrapsCustomAMIs reports whether eksctl generates the complete bootstrap userdata of the AMI family,
+// so that custom AMIs of the family can be used in managed nodegroups without overrideBootstrapCommand
+func bootstrapsCustomAMIs(imageFamily string) bool {
+	return imageFamily == NodeImageFamilyAmazonLinux2022 || imageFamily == NodeImageFamilyFlatcar
+}
+
 // IsWindowsImage reports whether the AMI family is for Windows
 func IsWindowsImage(imageFamily string) bool {
 	switch imageFamily {
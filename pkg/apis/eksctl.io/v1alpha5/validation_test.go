@@ -103,6 +103,64 @@ var _ = Describe("ClusterConfig validation", func() {
 			err = api.ValidateNodeGroup(0, ng0)
 			Expect(err).To(HaveOccurred())
 		})
+		It("containerd is allowed for AmazonLinux2022 and Flatcar", func() {
+			cfg := api.NewClusterConfig()
+			ng0 := cfg.NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.ContainerRuntime = aws.String(api.ContainerRuntimeContainerD)
+			for _, amiFamily := range []string{api.NodeImageFamilyAmazonLinux2022, api.NodeImageFamilyFlatcar} {
+				ng0.AMIFamily = amiFamily
+				Expect(api.ValidateNodeGroup(0, ng0)).To(Succeed())
+			}
+		})
+		It("dockerd is not allowed for AmazonLinux2022", func() {
+			cfg := api.NewClusterConfig()
+			ng0 := cfg.NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.ContainerRuntime = aws.String(api.ContainerRuntimeDockerD)
+			ng0.AMIFamily = api.NodeImageFamilyAmazonLinux2022
+			err := api.ValidateNodeGroup(0, ng0)
+			Expect(err).To(MatchError(ContainSubstring("dockerd as runtime is not supported for the AmazonLinux2022 ami family")))
+		})
+	})
+
+	Describe("AmazonLinux2022 nodegroups", func() {
+		var cfg *api.ClusterConfig
+
+		BeforeEach(func() {
+			cfg = api.NewClusterConfig()
+			ng0 := cfg.NewNodeGroup()
+			ng0.Name = "al2022"
+			ng0.AMIFamily = api.NodeImageFamilyAmazonLinux2022
+		})
+
+		It("are allowed for versions the AMI is published for", func() {
+			cfg.Metadata.Version = api.Version1_22
+			Expect(api.ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("are not allowed for versions the AMI is not published for", func() {
+			for _, version := range []string{api.Version1_21, "default", "latest"} {
+				cfg.Metadata.Version = version
+				err := api.ValidateClusterConfig(cfg)
+				Expect(err).To(MatchError(`nodegroup "al2022": AmazonLinux2022 requires EKS version 1.22 and above`))
+			}
+		})
+
+		It("are validated once the version is resolved when it is auto", func() {
+			cfg.Metadata.Version = "auto"
+			Expect(api.ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("are not allowed in managed nodegroups for versions the AMI is not published for", func() {
+			cfg.NodeGroups = nil
+			ng := api.NewManagedNodeGroup()
+			ng.Name = "al2022"
+			ng.AMIFamily = api.NodeImageFamilyAmazonLinux2022
+			cfg.ManagedNodeGroups = []*api.ManagedNodeGroup{ng}
+			err := api.ValidateClusterConfig(cfg)
+			Expect(err).To(MatchError(`nodegroup "al2022": AmazonLinux2022 requires EKS version 1.22 and above`))
+		})
 	})
 
 	Describe("nodeGroups[*].volumeX", func() {
@@ -687,6 +745,182 @@ var _ = Describe("ClusterConfig validation", func() {
 		})
 	})
 
+	Describe("Auto Scaling group features", func() {
+		var ng *api.NodeGroup
+		BeforeEach(func() {
+			ng = newNodeGroup()
+		})
+
+		It("accepts valid scheduled actions, lifecycle hooks and warm pools", func() {
+			ng.ScalingConfig = &api.ScalingConfig{MaxSize: aws.Int(4)}
+			ng.ScheduledActions = []api.ScheduledAction{
+				{Name: "nightly", Recurrence: "0 20 * * *", DesiredCapacity: aws.Int(0), MinSize: aws.Int(0)},
+				{Name: "once", StartTime: "2021-12-24T20:00:00Z", MaxSize: aws.Int(10)},
+			}
+			ng.LifecycleHooks = []api.LifecycleHook{{
+				Name:                  "drain-logs",
+				LifecycleTransition:   "autoscaling:EC2_INSTANCE_TERMINATING",
+				HeartbeatTimeout:      aws.Int(300),
+				DefaultResult:         "CONTINUE",
+				NotificationTargetARN: "arn:aws:sqs:us-west-2:123456789012:lifecycle",
+				RoleARN:               "arn:aws:iam::123456789012:role/lifecycle",
+			}}
+			ng.WarmPool = &api.WarmPool{MinSize: aws.Int(1), MaxGroupPreparedCapacity: aws.Int(6), PoolState: "Hibernated"}
+			Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("accepts warm pools of nodegroups without a maxSize, as in update nodegroup configs", func() {
+			ng.WarmPool = &api.WarmPool{MaxGroupPreparedCapacity: aws.Int(2)}
+			Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+			ng.ScalingConfig = &api.ScalingConfig{}
+			Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		DescribeTable("invalid configurations", func(update func(*api.NodeGroup), expectedErr string) {
+			update(ng)
+			Expect(api.ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring(expectedErr)))
+		},
+			Entry("scheduled action without a schedule", func(ng *api.NodeGroup) {
+				ng.ScheduledActions = []api.ScheduledAction{{Name: "a", DesiredCapacity: aws.Int(1)}}
+			}, "at least one of recurrence or startTime must be set in nodeGroups[0].scheduledActions[0]"),
+			Entry("scheduled action without sizes", func(ng *api.NodeGroup) {
+				ng.ScheduledActions = []api.ScheduledAction{{Name: "a", Recurrence: "0 20 * * *"}}
+			}, "at least one of desiredCapacity, minSize or maxSize must be set in nodeGroups[0].scheduledActions[0]"),
+			Entry("scheduled action with a name that is not alphanumeric", func(ng *api.NodeGroup) {
+				ng.ScheduledActions = []api.ScheduledAction{{Name: "scale-down", Recurrence: "0 20 * * *", DesiredCapacity: aws.Int(0)}}
+			}, `nodeGroups[0].scheduledActions[0].name "scale-down" is invalid`),
+			Entry("duplicate scheduled action names", func(ng *api.NodeGroup) {
+				ng.ScheduledActions = []api.ScheduledAction{
+					{Name: "a", Recurrence: "0 20 * * *", DesiredCapacity: aws.Int(0)},
+					{Name: "a", Recurrence: "0 7 * * *", DesiredCapacity: aws.Int(2)},
+				}
+			}, `nodeGroups[0].scheduledActions[1].name "a" is not unique`),
+			Entry("scheduled action with an invalid start time", func(ng *api.NodeGroup) {
+				ng.ScheduledActions = []api.ScheduledAction{{Name: "a", StartTime: "tomorrow", DesiredCapacity: aws.Int(0)}}
+			}, "invalid nodeGroups[0].scheduledActions[0].startTime, it must be in RFC 3339 format"),
+			Entry("scheduled action with minSize greater than maxSize", func(ng *api.NodeGroup) {
+				ng.ScheduledActions = []api.ScheduledAction{{Name: "a", Recurrence: "0 20 * * *", MinSize: aws.Int(3), MaxSize: aws.Int(2)}}
+			}, "nodeGroups[0].scheduledActions[0].minSize must be less than or equal to nodeGroups[0].scheduledActions[0].maxSize"),
+			Entry("lifecycle hook with an invalid transition", func(ng *api.NodeGroup) {
+				ng.LifecycleHooks = []api.LifecycleHook{{Name: "a", LifecycleTransition: "launching"}}
+			}, "nodeGroups[0].lifecycleHooks[0].lifecycleTransition must be one of"),
+			Entry("lifecycle hook with an invalid default result", func(ng *api.NodeGroup) {
+				ng.LifecycleHooks = []api.LifecycleHook{{Name: "a", LifecycleTransition: "autoscaling:EC2_INSTANCE_LAUNCHING", DefaultResult: "RETRY"}}
+			}, "nodeGroups[0].lifecycleHooks[0].defaultResult must be one of CONTINUE or ABANDON"),
+			Entry("lifecycle hook with a heartbeat timeout out of range", func(ng *api.NodeGroup) {
+				ng.LifecycleHooks = []api.LifecycleHook{{Name: "a", LifecycleTransition: "autoscaling:EC2_INSTANCE_LAUNCHING", HeartbeatTimeout: aws.Int(10)}}
+			}, "nodeGroups[0].lifecycleHooks[0].heartbeatTimeout must be between 30 and 7200 seconds"),
+			Entry("lifecycle hook with a role but no notification target", func(ng *api.NodeGroup) {
+				ng.LifecycleHooks = []api.LifecycleHook{{Name: "a", LifecycleTransition: "autoscaling:EC2_INSTANCE_LAUNCHING", RoleARN: "arn:aws:iam::123456789012:role/lifecycle"}}
+			}, "nodeGroups[0].lifecycleHooks[0].roleARN can only be set together with nodeGroups[0].lifecycleHooks[0].notificationTargetARN"),
+			Entry("warm pool with mixed instances", func(ng *api.NodeGroup) {
+				ng.InstancesDistribution = &api.NodeGroupInstancesDistribution{InstanceTypes: []string{"m5.large", "m5a.large"}}
+				ng.WarmPool = &api.WarmPool{}
+			}, "nodeGroups[0].warmPool cannot be used with instancesDistribution"),
+			Entry("warm pool with an invalid pool state", func(ng *api.NodeGroup) {
+				ng.WarmPool = &api.WarmPool{PoolState: "Paused"}
+			}, "nodeGroups[0].warmPool.poolState must be one of Stopped, Running or Hibernated"),
+			Entry("warm pool with an AMI family that is not bootstrapped once it leaves the warm pool", func(ng *api.NodeGroup) {
+				ng.AMIFamily = api.NodeImageFamilyBottlerocket
+				ng.WarmPool = &api.WarmPool{}
+			}, "nodeGroups[0].warmPool is only supported for the AmazonLinux2, Ubuntu2004 and Ubuntu1804 AMI families"),
+			Entry("warm pool with a custom AMI", func(ng *api.NodeGroup) {
+				ng.AMI = "ami-123"
+				ng.WarmPool = &api.WarmPool{}
+			}, "nodeGroups[0].warmPool cannot be used with a custom AMI"),
+			Entry("warm pool with overrideBootstrapCommand", func(ng *api.NodeGroup) {
+				ng.OverrideBootstrapCommand = aws.String("/etc/eks/bootstrap.sh")
+				ng.WarmPool = &api.WarmPool{}
+			}, "nodeGroups[0].warmPool cannot be used with nodeGroups[0].overrideBootstrapCommand"),
+			Entry("warm pool with a prepared capacity lower than the nodegroup size", func(ng *api.NodeGroup) {
+				ng.ScalingConfig = &api.ScalingConfig{MaxSize: aws.Int(4)}
+				ng.WarmPool = &api.WarmPool{MaxGroupPreparedCapacity: aws.Int(2)}
+			}, "nodeGroups[0].warmPool.maxGroupPreparedCapacity must be greater than or equal to nodeGroups[0].maxSize"),
+		)
+	})
+
+	Describe("perAvailabilityZone", func() {
+		var ng *api.NodeGroup
+		BeforeEach(func() {
+			ng = newNodeGroup()
+			ng.PerAvailabilityZone = api.Enabled()
+		})
+
+		It("accepts availability zones", func() {
+			ng.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+			Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("fails when subnets are set", func() {
+			ng.Subnets = []string{"subnet-1234"}
+			err := api.ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError("nodeGroups[0].subnets cannot be set when nodeGroups[0].perAvailabilityZone is enabled; use nodeGroups[0].availabilityZones instead"))
+		})
+	})
+
+	Describe("drainPolicy", func() {
+		var ng *api.NodeGroup
+		BeforeEach(func() {
+			ng = newNodeGroup()
+			ng.DrainPolicy = &api.DrainPolicy{}
+		})
+
+		It("accepts a valid drain policy", func() {
+			ng.DrainPolicy = &api.DrainPolicy{
+				IgnoreDaemonSets:            []api.DrainPolicyDaemonSet{{Name: "fluentd", Namespace: "logging"}},
+				IgnoreNamespaces:            []string{"monitoring"},
+				NeverForceDelete:            []string{"app=etcd", "tier in (database)"},
+				NamespaceGracePeriodSeconds: map[string]int{"default": 60},
+				NodeTimeoutSeconds:          aws.Int(600),
+				WaitForReplacements:         api.Enabled(),
+			}
+			Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		DescribeTable("invalid drain policies", func(policy api.DrainPolicy, expectedErr string) {
+			ng.DrainPolicy = &policy
+			Expect(api.ValidateNodeGroup(0, ng)).To(MatchError(expectedErr))
+		},
+			Entry("DaemonSet without a name", api.DrainPolicy{
+				IgnoreDaemonSets: []api.DrainPolicyDaemonSet{{Namespace: "logging"}},
+			}, "nodeGroups[0].drainPolicy.ignoreDaemonSets[0].name must be set"),
+			Entry("invalid selector", api.DrainPolicy{
+				NeverForceDelete: []string{"=etcd"},
+			}, "nodeGroups[0].drainPolicy.neverForceDelete[0] is not a valid label selector: found '=', expected: !, identifier, or 'end of string'"),
+			Entry("negative grace period", api.DrainPolicy{
+				NamespaceGracePeriodSeconds: map[string]int{"default": -1},
+			}, "nodeGroups[0].drainPolicy.namespaceGracePeriodSeconds[default] cannot be negative"),
+			Entry("zero node timeout", api.DrainPolicy{
+				NodeTimeoutSeconds: aws.Int(0),
+			}, "nodeGroups[0].drainPolicy.nodeTimeoutSeconds must be greater than 0"),
+		)
+
+		It("combines the cluster-wide and nodegroup drain policies", func() {
+			cfg := api.NewClusterConfig()
+			cfg.DrainPolicy = &api.DrainPolicy{
+				IgnoreNamespaces:            []string{"monitoring"},
+				NamespaceGracePeriodSeconds: map[string]int{"default": 60, "batch": 600},
+				NodeTimeoutSeconds:          aws.Int(600),
+			}
+			ng.Name = "ng-1"
+			ng.DrainPolicy = &api.DrainPolicy{
+				IgnoreNamespaces:            []string{"logging"},
+				NamespaceGracePeriodSeconds: map[string]int{"default": 30},
+				WaitForReplacements:         api.Enabled(),
+			}
+			cfg.NodeGroups = []*api.NodeGroup{ng}
+
+			Expect(cfg.NodeGroupDrainPolicy("ng-1")).To(Equal(&api.DrainPolicy{
+				IgnoreNamespaces:            []string{"monitoring", "logging"},
+				NamespaceGracePeriodSeconds: map[string]int{"default": 30, "batch": 600},
+				NodeTimeoutSeconds:          aws.Int(600),
+				WaitForReplacements:         api.Enabled(),
+			}))
+			Expect(cfg.NodeGroupDrainPolicy("ng-2")).To(Equal(cfg.DrainPolicy))
+			Expect(cfg.DrainPolicy.IgnoreNamespaces).To(Equal([]string{"monitoring"}))
+		})
+	})
+
 	Describe("ssh flags", func() {
 		var (
 			testKeyPath = "some/path/to/file.pub"
@@ -1119,7 +1353,7 @@ var _ = Describe("ClusterConfig validation", func() {
 		It("fails when the AMIFamily is not supported", func() {
 			ng.AMIFamily = "SomeTrash"
 			err := api.ValidateNodeGroup(0, ng)
-			Expect(err).To(MatchError("AMI Family SomeTrash is not supported - use one of: AmazonLinux2, Ubuntu2004, Ubuntu1804, Bottlerocket, WindowsServer2019CoreContainer, WindowsServer2019FullContainer, WindowsServer2004CoreContainer, WindowsServer20H2CoreContainer"))
+			Expect(err).To(MatchError("AMI Family SomeTrash is not supported - use one of: AmazonLinux2, Ubuntu2004, Ubuntu1804, Bottlerocket, AmazonLinux2022, Flatcar, WindowsServer2019CoreContainer, WindowsServer2019FullContainer, WindowsServer2004CoreContainer, WindowsServer20H2CoreContainer"))
 		})
 	})
 
@@ -0,0 +1,11 @@
+package autoscaler_test
+
+import (
+	"testing"
+
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+func TestAutoscaler(t *testing.T) {
+	testutils.RegisterAndRun(t)
+}
@@ -0,0 +1,209 @@
+// Package autoscaler derives the Auto Scaling group tags cluster-autoscaler uses to
+// build a template of the nodes of a nodegroup when scaling it up from zero
+package autoscaler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	instanceutils "github.com/weaveworks/eksctl/pkg/utils/instance"
+)
+
+const (
+	nodeTemplateTagPrefix = "k8s.io/cluster-autoscaler/node-template/"
+	labelTagPrefix        = nodeTemplateTagPrefix + "label/"
+	taintTagPrefix        = nodeTemplateTagPrefix + "taint/"
+	resourcesTagPrefix    = nodeTemplateTagPrefix + "resources/"
+
+	zoneLabel   = "topology.kubernetes.io/zone"
+	gpuResource = "nvidia.com/gpu"
+
+	// MaxASGTags is the maximum number of tags an Auto Scaling group can have
+	MaxASGTags = 50
+)
+
+// LabelTag returns the key of the tag for a node label
+func LabelTag(label string) string {
+	return labelTagPrefix + label
+}
+
+// NodeTemplateTags returns the node-template tags of a nodegroup, derived from its labels,
+// taints, availability zone, root volume size, and the number of GPUs of its instances
+func NodeTemplateTags(np api.NodePool, gpus int) map[string]string {
+	ng := np.BaseNodeGroup()
+	tags := map[string]string{}
+	for k, v := range ng.Labels {
+		tags[LabelTag(k)] = v
+	}
+	if len(ng.AvailabilityZones) == 1 {
+		tags[LabelTag(zoneLabel)] = ng.AvailabilityZones[0]
+	}
+	for _, taint := range np.NGTaints() {
+		tags[taintTagPrefix+taint.Key] = fmt.Sprintf("%s:%s", taint.Value, taint.Effect)
+	}
+	if ng.VolumeSize != nil {
+		tags[resourcesTagPrefix+"ephemeral-storage"] = fmt.Sprintf("%dGi", *ng.VolumeSize)
+	}
+	if gpus > 0 {
+		tags[resourcesTagPrefix+gpuResource] = fmt.Sprintf("%d", gpus)
+	}
+	return tags
+}
+
+// GPUCount returns the number of NVIDIA GPUs of the given instance types; as cluster-autoscaler
+// expects all nodes of a nodegroup to be alike, the lowest count is returned for mixed instance types
+func GPUCount(ec2API ec2iface.EC2API, instanceTypes []string) (int, error) {
+	var gpuInstanceTypes []string
+	for _, instanceType := range instanceTypes {
+		if instanceutils.IsGPUInstanceType(instanceType) {
+			gpuInstanceTypes = append(gpuInstanceTypes, instanceType)
+		}
+	}
+	if len(gpuInstanceTypes) == 0 || len(gpuInstanceTypes) < len(instanceTypes) {
+		return 0, nil
+	}
+
+	output, err := ec2API.DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+		InstanceTypes: aws.StringSlice(gpuInstanceTypes),
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "describing instance types %v", gpuInstanceTypes)
+	}
+	count := -1
+	for _, instanceType := range output.InstanceTypes {
+		gpus := 0
+		if instanceType.GpuInfo != nil {
+			for _, gpu := range instanceType.GpuInfo.Gpus {
+				if aws.StringValue(gpu.Manufacturer) == "NVIDIA" {
+					gpus += int(aws.Int64Value(gpu.Count))
+				}
+			}
+		}
+		if count == -1 || gpus < count {
+			count = gpus
+		}
+	}
+	if count == -1 {
+		return 0, nil
+	}
+	return count, nil
+}
+
+// ManagedNodeGroupASGs returns the names of the Auto Scaling groups EKS created for a managed nodegroup
+func ManagedNodeGroupASGs(eksAPI eksiface.EKSAPI, clusterName, nodeGroupName string) ([]string, error) {
+	output, err := eksAPI.DescribeNodegroup(&eks.DescribeNodegroupInput{
+		ClusterName:   aws.String(clusterName),
+		NodegroupName: aws.String(nodeGroupName),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing nodegroup %q", nodeGroupName)
+	}
+	var names []string
+	if output.Nodegroup.Resources != nil {
+		for _, asg := range output.Nodegroup.Resources.AutoScalingGroups {
+			names = append(names, aws.StringValue(asg.Name))
+		}
+	}
+	return names, nil
+}
+
+// TagASGs adds or updates tags on Auto Scaling groups; the tags are not propagated to instances.
+// No tags are added when any of the groups would end up with more tags than Auto Scaling allows
+func TagASGs(asgAPI autoscalingiface.AutoScalingAPI, asgNames []string, tags map[string]string) error {
+	if len(asgNames) == 0 || len(tags) == 0 {
+		return nil
+	}
+	if err := checkTagLimit(asgAPI, asgNames, tags); err != nil {
+		return err
+	}
+	var asgTags []*autoscaling.Tag
+	for _, asgName := range asgNames {
+		for _, key := range sortedKeys(tags) {
+			asgTags = append(asgTags, &autoscaling.Tag{
+				ResourceId:        aws.String(asgName),
+				ResourceType:      aws.String("auto-scaling-group"),
+				Key:               aws.String(key),
+				Value:             aws.String(tags[key]),
+				PropagateAtLaunch: aws.Bool(false),
+			})
+		}
+	}
+	if _, err := asgAPI.CreateOrUpdateTags(&autoscaling.CreateOrUpdateTagsInput{Tags: asgTags}); err != nil {
+		return errors.Wrapf(err, "tagging Auto Scaling groups %v", asgNames)
+	}
+	return nil
+}
+
+// UntagASGs removes tags from Auto Scaling groups
+func UntagASGs(asgAPI autoscalingiface.AutoScalingAPI, asgNames []string, keys []string) error {
+	if len(asgNames) == 0 || len(keys) == 0 {
+		return nil
+	}
+	var asgTags []*autoscaling.Tag
+	for _, asgName := range asgNames {
+		for _, key := range keys {
+			asgTags = append(asgTags, &autoscaling.Tag{
+				ResourceId:   aws.String(asgName),
+				ResourceType: aws.String("auto-scaling-group"),
+				Key:          aws.String(key),
+			})
+		}
+	}
+	if _, err := asgAPI.DeleteTags(&autoscaling.DeleteTagsInput{Tags: asgTags}); err != nil {
+		return errors.Wrapf(err, "removing tags from Auto Scaling groups %v", asgNames)
+	}
+	return nil
+}
+
+// checkTagLimit returns an error when adding the tags would exceed the tag limit of any of the groups
+func checkTagLimit(asgAPI autoscalingiface.AutoScalingAPI, asgNames []string, tags map[string]string) error {
+	existing := map[string]map[string]bool{}
+	input := &autoscaling.DescribeTagsInput{
+		Filters: []*autoscaling.Filter{{Name: aws.String("auto-scaling-group"), Values: aws.StringSlice(asgNames)}},
+	}
+	if err := asgAPI.DescribeTagsPages(input, func(page *autoscaling.DescribeTagsOutput, _ bool) bool {
+		for _, tag := range page.Tags {
+			asgName := aws.StringValue(tag.ResourceId)
+			if existing[asgName] == nil {
+				existing[asgName] = map[string]bool{}
+			}
+			existing[asgName][aws.StringValue(tag.Key)] = true
+		}
+		return true
+	}); err != nil {
+		return errors.Wrapf(err, "describing tags of Auto Scaling groups %v", asgNames)
+	}
+
+	for _, asgName := range asgNames {
+		count := len(existing[asgName])
+		for key := range tags {
+			if !existing[asgName][key] {
+				count++
+			}
+		}
+		if count > MaxASGTags {
+			return fmt.Errorf("cannot add %d tag(s) to Auto Scaling group %q, which has %d tag(s), as Auto Scaling groups can have at most %d tags",
+				count-len(existing[asgName]), asgName, len(existing[asgName]), MaxASGTags)
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
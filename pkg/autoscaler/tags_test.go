@@ -0,0 +1,76 @@
+package autoscaler_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/autoscaler"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("cluster-autoscaler tags", func() {
+	Describe("NodeTemplateTags", func() {
+		It("derives the tags from the labels, taints, zone and volume size of a nodegroup", func() {
+			ng := api.NewManagedNodeGroup()
+			ng.Labels = map[string]string{"role": "web"}
+			ng.Taints = []api.NodeGroupTaint{{Key: "dedicated", Value: "web", Effect: "NoSchedule"}}
+			ng.AvailabilityZones = []string{"us-west-2a"}
+			ng.VolumeSize = aws.Int(50)
+
+			Expect(autoscaler.NodeTemplateTags(ng, 2)).To(Equal(map[string]string{
+				"k8s.io/cluster-autoscaler/node-template/label/role":                        "web",
+				"k8s.io/cluster-autoscaler/node-template/label/topology.kubernetes.io/zone": "us-west-2a",
+				"k8s.io/cluster-autoscaler/node-template/taint/dedicated":                   "web:NoSchedule",
+				"k8s.io/cluster-autoscaler/node-template/resources/ephemeral-storage":       "50Gi",
+				"k8s.io/cluster-autoscaler/node-template/resources/nvidia.com/gpu":          "2",
+			}))
+		})
+
+		It("does not add a zone label for nodegroups spanning several zones", func() {
+			ng := api.NewManagedNodeGroup()
+			ng.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+
+			Expect(autoscaler.NodeTemplateTags(ng, 0)).NotTo(HaveKey("k8s.io/cluster-autoscaler/node-template/label/topology.kubernetes.io/zone"))
+		})
+	})
+
+	Describe("GPUCount", func() {
+		var p *mockprovider.MockProvider
+
+		BeforeEach(func() {
+			p = mockprovider.NewMockProvider()
+		})
+
+		It("does not describe instance types when not all of them are GPU instance types", func() {
+			gpus, err := autoscaler.GPUCount(p.EC2(), []string{"p3.2xlarge", "m5.large"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gpus).To(Equal(0))
+		})
+
+		It("returns the lowest number of NVIDIA GPUs of the instance types", func() {
+			p.MockEC2().On("DescribeInstanceTypes", mock.Anything).Return(&ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: []*ec2.InstanceTypeInfo{
+					gpuInstanceType("p3.2xlarge", 1),
+					gpuInstanceType("p3.8xlarge", 4),
+				},
+			}, nil)
+
+			gpus, err := autoscaler.GPUCount(p.EC2(), []string{"p3.2xlarge", "p3.8xlarge"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gpus).To(Equal(1))
+		})
+	})
+})
+
+func gpuInstanceType(instanceType string, gpus int64) *ec2.InstanceTypeInfo {
+	return &ec2.InstanceTypeInfo{
+		InstanceType: aws.String(instanceType),
+		GpuInfo: &ec2.GpuInfo{
+			Gpus: []*ec2.GpuDeviceInfo{{Manufacturer: aws.String("NVIDIA"), Count: aws.Int64(gpus)}},
+		},
+	}
+}
@@ -90,6 +90,17 @@ func maybeSetNameTag(name string, resource interface{}) {
 	}
 }
 
+// maybeSetRetainDeletionPolicy sets the deletion policy of any resource that supports it to Retain
+func maybeSetRetainDeletionPolicy(resource interface{}) {
+	e := reflect.ValueOf(resource).Elem()
+	if e.Kind() == reflect.Struct {
+		f := e.FieldByName("AWSCloudFormationDeletionPolicy")
+		if f.IsValid() && f.CanSet() && f.Kind() == reflect.String {
+			f.SetString("Retain")
+		}
+	}
+}
+
 // newResource adds a resource, and adds Name tag if possible, it returns a reference
 func (r *resourceSet) newResource(name string, resource gfn.Resource) *gfnt.Value {
 	maybeSetNameTag(name, resource)
@@ -7,10 +7,11 @@ import (
 type FakeTemplate struct {
 	Description string
 	Resources   map[string]struct {
-		Type         string
-		Properties   Properties
-		DependsOn    []string
-		UpdatePolicy map[string]map[string]interface{}
+		Type           string
+		Properties     Properties
+		DependsOn      []string
+		UpdatePolicy   map[string]map[string]interface{}
+		DeletionPolicy string
 	}
 	Mappings map[string]interface{}
 	Outputs  map[string]cfn.Output
@@ -60,6 +61,13 @@ type Properties struct {
 	TargetGroupARNs                   []string
 	DesiredCapacity, MinSize, MaxSize string
 
+	LifecycleHookSpecificationList []map[string]interface{}
+	AutoScalingGroupName           interface{}
+	Recurrence, StartTime, EndTime string
+	MaxGroupPreparedCapacity       string
+	PoolState                      string
+	InstanceReusePolicy            map[string]interface{}
+
 	CidrIP, CidrIpv6, IPProtocol string
 	FromPort, ToPort             int
 
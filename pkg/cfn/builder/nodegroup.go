@@ -2,6 +2,7 @@ package builder
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
@@ -15,6 +16,7 @@ import (
 	"github.com/kris-nova/logger"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/autoscaler"
 	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
 	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
 	"github.com/weaveworks/eksctl/pkg/vpc"
@@ -175,12 +177,71 @@ func (n *NodeGroupResourceSet) addResourcesForNodeGroup() error {
 		)
 	}
 
+	gpus, err := autoscaler.GPUCount(n.ec2API, n.spec.InstanceTypeList())
+	if err != nil {
+		logger.Warning("not adding the GPU node-template tag for cluster-autoscaler to nodegroup %q: %v", n.spec.Name, err)
+	}
+	nodeTemplateTags := autoscaler.NodeTemplateTags(n.spec, gpus)
+	var nodeTemplateKeys []string
+	for key := range nodeTemplateTags {
+		// tags set explicitly on the nodegroup take precedence
+		if _, ok := n.spec.Tags[key]; !ok {
+			nodeTemplateKeys = append(nodeTemplateKeys, key)
+		}
+	}
+	sort.Strings(nodeTemplateKeys)
+	// the ASG can have at most 50 tags, including the tags of the stack that CloudFormation propagates to it,
+	// so the node-template tags that do not fit are skipped
+	if free := autoscaler.MaxASGTags - len(asgTagKeys(tags, n.clusterSpec, n.spec)); len(nodeTemplateKeys) > free {
+		if free < 0 {
+			free = 0
+		}
+		logger.Warning("not adding cluster-autoscaler node-template tags %v to nodegroup %q, as Auto Scaling groups can have at most %d tags",
+			nodeTemplateKeys[free:], n.spec.Name, autoscaler.MaxASGTags)
+		nodeTemplateKeys = nodeTemplateKeys[:free]
+	}
+	for _, key := range nodeTemplateKeys {
+		tags = append(tags, map[string]interface{}{
+			"Key":               key,
+			"Value":             nodeTemplateTags[key],
+			"PropagateAtLaunch": "false",
+		})
+	}
+
 	asg := nodeGroupResource(launchTemplateName, vpcZoneIdentifier, tags, n.spec)
 	n.newResource("NodeGroup", asg)
 
+	for name, resource := range NodeGroupASGResources(n.spec) {
+		n.newResource(name, resource)
+	}
+
 	return nil
 }
 
+// asgTagKeys returns the keys of the tags the ASG of a nodegroup has besides its node-template tags: the given tags
+// and the tags of the nodegroup stack, which CloudFormation propagates to the ASG. A tag is reserved for the
+// template bucket, which is recorded on the stack when the template is too large to be passed inline
+func asgTagKeys(tags []map[string]interface{}, clusterSpec *api.ClusterConfig, ng *api.NodeGroup) map[string]struct{} {
+	keys := map[string]struct{}{}
+	for _, key := range []string{
+		api.ClusterNameTag, api.OldClusterNameTag, api.EksctlVersionTag,
+		api.NodeGroupNameTag, api.OldNodeGroupNameTag, api.NodeGroupTypeTag,
+		api.TemplateBucketTag,
+	} {
+		keys[key] = struct{}{}
+	}
+	for _, tag := range tags {
+		keys[tag["Key"].(string)] = struct{}{}
+	}
+	for key := range clusterSpec.Metadata.Tags {
+		keys[key] = struct{}{}
+	}
+	for key := range ng.Tags {
+		keys[key] = struct{}{}
+	}
+	return keys
+}
+
 // generateNodeName formulates the name based on the configuration in input
 func generateNodeName(ng *api.NodeGroupBase, meta *api.ClusterMeta) string {
 	var nameParts []string
@@ -333,6 +394,9 @@ func nodeGroupResource(launchTemplateName *gfnt.Value, vpcZoneIdentifier interfa
 	if len(ng.TargetGroupARNs) > 0 {
 		ngProps["TargetGroupARNs"] = ng.TargetGroupARNs
 	}
+	if len(ng.LifecycleHooks) > 0 {
+		ngProps["LifecycleHookSpecificationList"] = LifecycleHookSpecifications(ng)
+	}
 	if api.HasMixedInstances(ng) {
 		ngProps["MixedInstancesPolicy"] = *mixedInstancesPolicy(launchTemplateName, ng)
 	} else {
@@ -0,0 +1,109 @@
+package builder
+
+import (
+	"fmt"
+
+	gfn "github.com/weaveworks/goformation/v4/cloudformation"
+	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+const (
+	scheduledActionResourceType = "AWS::AutoScaling::ScheduledAction"
+	warmPoolResourceType        = "AWS::AutoScaling::WarmPool"
+)
+
+// NodeGroupASGResources returns the scheduled actions and the warm pool of the
+// Auto Scaling group of a nodegroup, keyed by logical ID
+func NodeGroupASGResources(ng *api.NodeGroup) map[string]gfn.Resource {
+	resources := map[string]gfn.Resource{}
+	asgName := gfnt.MakeRef("NodeGroup")
+
+	for _, action := range ng.ScheduledActions {
+		props := map[string]interface{}{
+			"AutoScalingGroupName": asgName,
+		}
+		if action.Recurrence != "" {
+			props["Recurrence"] = action.Recurrence
+		}
+		if action.StartTime != "" {
+			props["StartTime"] = action.StartTime
+		}
+		if action.EndTime != "" {
+			props["EndTime"] = action.EndTime
+		}
+		if action.DesiredCapacity != nil {
+			props["DesiredCapacity"] = fmt.Sprintf("%d", *action.DesiredCapacity)
+		}
+		if action.MinSize != nil {
+			props["MinSize"] = fmt.Sprintf("%d", *action.MinSize)
+		}
+		if action.MaxSize != nil {
+			props["MaxSize"] = fmt.Sprintf("%d", *action.MaxSize)
+		}
+		resources["ScheduledAction"+action.Name] = &awsCloudFormationResource{
+			Type:       scheduledActionResourceType,
+			Properties: props,
+		}
+	}
+
+	if ng.WarmPool != nil {
+		props := map[string]interface{}{
+			"AutoScalingGroupName": asgName,
+		}
+		if ng.WarmPool.MinSize != nil {
+			props["MinSize"] = fmt.Sprintf("%d", *ng.WarmPool.MinSize)
+		}
+		if ng.WarmPool.MaxGroupPreparedCapacity != nil {
+			props["MaxGroupPreparedCapacity"] = fmt.Sprintf("%d", *ng.WarmPool.MaxGroupPreparedCapacity)
+		}
+		if ng.WarmPool.PoolState != "" {
+			props["PoolState"] = ng.WarmPool.PoolState
+		}
+		if ng.WarmPool.ReuseOnScaleIn != nil {
+			props["InstanceReusePolicy"] = map[string]interface{}{
+				"ReuseOnScaleIn": *ng.WarmPool.ReuseOnScaleIn,
+			}
+		}
+		resources["NodeGroupWarmPool"] = &awsCloudFormationResource{
+			Type:       warmPoolResourceType,
+			Properties: props,
+		}
+	}
+
+	return resources
+}
+
+// IsNodeGroupASGResource returns true for the types of the resources returned by NodeGroupASGResources
+func IsNodeGroupASGResource(resourceType string) bool {
+	return resourceType == scheduledActionResourceType || resourceType == warmPoolResourceType
+}
+
+// LifecycleHookSpecifications returns the lifecycle hooks of the Auto Scaling group of a nodegroup
+func LifecycleHookSpecifications(ng *api.NodeGroup) []map[string]interface{} {
+	var hooks []map[string]interface{}
+	for _, hook := range ng.LifecycleHooks {
+		spec := map[string]interface{}{
+			"LifecycleHookName":   hook.Name,
+			"LifecycleTransition": hook.LifecycleTransition,
+		}
+		if hook.HeartbeatTimeout != nil {
+			spec["HeartbeatTimeout"] = *hook.HeartbeatTimeout
+		}
+		if hook.DefaultResult != "" {
+			spec["DefaultResult"] = hook.DefaultResult
+		}
+		if hook.NotificationTargetARN != "" {
+			spec["NotificationTargetARN"] = hook.NotificationTargetARN
+		}
+		if hook.RoleARN != "" {
+			spec["RoleARN"] = hook.RoleARN
+		}
+		if hook.NotificationMetadata != "" {
+			spec["NotificationMetadata"] = hook.NotificationMetadata
+		}
+		hooks = append(hooks, spec)
+	}
+	return hooks
+}
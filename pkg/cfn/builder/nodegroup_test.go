@@ -3,6 +3,7 @@ package builder_test
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -729,13 +730,79 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 				Expect(ngTemplate.Resources["NodeGroup"].Properties.LaunchTemplate.LaunchTemplateName).To(Equal(map[string]interface{}{"Fn::Sub": "${AWS::StackName}"}))
 				Expect(ngTemplate.Resources["NodeGroup"].Properties.LaunchTemplate.Version["Fn::GetAtt"]).To(Equal([]interface{}{"NodeGroupLaunchTemplate", "LatestVersionNumber"}))
 				tags := ngTemplate.Resources["NodeGroup"].Properties.Tags
-				Expect(tags).To(HaveLen(2))
+				Expect(tags).To(HaveLen(3))
 				Expect(tags[0].Key).To(Equal("Name"))
 				Expect(tags[0].Value).To(Equal("bonsai-ng-abcd1234-Node"))
 				Expect(tags[0].PropagateAtLaunch).To(Equal("true"))
 				Expect(tags[1].Key).To(Equal("kubernetes.io/cluster/bonsai"))
 				Expect(tags[1].Value).To(Equal("owned"))
 				Expect(tags[1].PropagateAtLaunch).To(Equal("true"))
+				Expect(tags[2].Key).To(Equal("k8s.io/cluster-autoscaler/node-template/resources/ephemeral-storage"))
+				Expect(tags[2].Value).To(Equal("80Gi"))
+				Expect(tags[2].PropagateAtLaunch).To(Equal("false"))
+			})
+
+			Context("ng.Labels, ng.Taints and ng.AvailabilityZones are set", func() {
+				BeforeEach(func() {
+					ng.Labels = map[string]string{"role": "web"}
+					ng.Taints = []api.NodeGroupTaint{{Key: "dedicated", Value: "web", Effect: "NoSchedule"}}
+					ng.AvailabilityZones = []string{azA}
+				})
+
+				It("adds cluster-autoscaler node-template tags to the ASG", func() {
+					tags := map[string]fakes.Tag{}
+					for _, tag := range ngTemplate.Resources["NodeGroup"].Properties.Tags {
+						tags[tag.Key.(string)] = tag
+					}
+					Expect(tags).To(HaveKeyWithValue("k8s.io/cluster-autoscaler/node-template/label/role", fakes.Tag{
+						Key: "k8s.io/cluster-autoscaler/node-template/label/role", Value: "web", PropagateAtLaunch: "false",
+					}))
+					Expect(tags).To(HaveKeyWithValue("k8s.io/cluster-autoscaler/node-template/taint/dedicated", fakes.Tag{
+						Key: "k8s.io/cluster-autoscaler/node-template/taint/dedicated", Value: "web:NoSchedule", PropagateAtLaunch: "false",
+					}))
+					Expect(tags).To(HaveKeyWithValue("k8s.io/cluster-autoscaler/node-template/label/topology.kubernetes.io/zone", fakes.Tag{
+						Key: "k8s.io/cluster-autoscaler/node-template/label/topology.kubernetes.io/zone", Value: azA, PropagateAtLaunch: "false",
+					}))
+				})
+			})
+
+			Context("the ASG would have more than 50 tags", func() {
+				BeforeEach(func() {
+					ng.Tags = map[string]string{}
+					for i := 0; i < 40; i++ {
+						ng.Tags[fmt.Sprintf("tag-%02d", i)] = "value"
+					}
+					ng.Labels = map[string]string{"a": "1", "b": "2", "c": "3"}
+				})
+
+				It("skips the node-template tags that do not fit", func() {
+					// 40 nodegroup tags, 7 tags set by eksctl on the stack, and the Name and cluster tags leave room for one
+					tags := ngTemplate.Resources["NodeGroup"].Properties.Tags
+					Expect(tags).To(HaveLen(3))
+					Expect(tags[2]).To(Equal(fakes.Tag{
+						Key: "k8s.io/cluster-autoscaler/node-template/label/a", Value: "1", PropagateAtLaunch: "false",
+					}))
+				})
+			})
+
+			Context("ng.InstanceType is a GPU instance type", func() {
+				BeforeEach(func() {
+					ng.InstanceType = "p3.8xlarge"
+					mockEC2.On("DescribeInstanceTypes", &ec2.DescribeInstanceTypesInput{
+						InstanceTypes: aws.StringSlice([]string{"p3.8xlarge"}),
+					}).Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []*ec2.InstanceTypeInfo{{
+							GpuInfo: &ec2.GpuInfo{Gpus: []*ec2.GpuDeviceInfo{{Manufacturer: aws.String("NVIDIA"), Count: aws.Int64(4)}}},
+						}},
+					}, nil)
+				})
+
+				It("adds the number of GPUs to the node-template tags", func() {
+					tags := ngTemplate.Resources["NodeGroup"].Properties.Tags
+					Expect(tags).To(ContainElement(fakes.Tag{
+						Key: "k8s.io/cluster-autoscaler/node-template/resources/nvidia.com/gpu", Value: "4", PropagateAtLaunch: "false",
+					}))
+				})
 			})
 
 			Context("ng.InstanceName is set", func() {
@@ -851,6 +918,69 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 				})
 			})
 
+			Context("ng.LifecycleHooks are set", func() {
+				BeforeEach(func() {
+					ng.LifecycleHooks = []api.LifecycleHook{{
+						Name:                "drain-logs",
+						LifecycleTransition: "autoscaling:EC2_INSTANCE_TERMINATING",
+						HeartbeatTimeout:    aws.Int(300),
+						DefaultResult:       "CONTINUE",
+					}}
+				})
+
+				It("adds the lifecycle hooks to the resource", func() {
+					Expect(ngTemplate.Resources["NodeGroup"].Properties.LifecycleHookSpecificationList).To(Equal([]map[string]interface{}{{
+						"LifecycleHookName":   "drain-logs",
+						"LifecycleTransition": "autoscaling:EC2_INSTANCE_TERMINATING",
+						"HeartbeatTimeout":    float64(300),
+						"DefaultResult":       "CONTINUE",
+					}}))
+				})
+			})
+
+			Context("ng.ScheduledActions are set", func() {
+				BeforeEach(func() {
+					ng.ScheduledActions = []api.ScheduledAction{
+						{Name: "nightly", Recurrence: "0 20 * * *", DesiredCapacity: aws.Int(0), MinSize: aws.Int(0)},
+						{Name: "morning", Recurrence: "0 7 * * 1-5", DesiredCapacity: aws.Int(2)},
+					}
+				})
+
+				It("adds a scheduled action resource for each action", func() {
+					nightly := ngTemplate.Resources["ScheduledActionnightly"]
+					Expect(nightly.Type).To(Equal("AWS::AutoScaling::ScheduledAction"))
+					Expect(nightly.Properties.AutoScalingGroupName).To(Equal(map[string]interface{}{"Ref": "NodeGroup"}))
+					Expect(nightly.Properties.Recurrence).To(Equal("0 20 * * *"))
+					Expect(nightly.Properties.DesiredCapacity).To(Equal("0"))
+					Expect(nightly.Properties.MinSize).To(Equal("0"))
+					Expect(nightly.Properties.MaxSize).To(BeEmpty())
+
+					morning := ngTemplate.Resources["ScheduledActionmorning"]
+					Expect(morning.Properties.Recurrence).To(Equal("0 7 * * 1-5"))
+					Expect(morning.Properties.DesiredCapacity).To(Equal("2"))
+				})
+			})
+
+			Context("ng.WarmPool is set", func() {
+				BeforeEach(func() {
+					ng.WarmPool = &api.WarmPool{
+						MinSize:        aws.Int(1),
+						PoolState:      "Stopped",
+						ReuseOnScaleIn: aws.Bool(true),
+					}
+				})
+
+				It("adds a warm pool resource", func() {
+					warmPool := ngTemplate.Resources["NodeGroupWarmPool"]
+					Expect(warmPool.Type).To(Equal("AWS::AutoScaling::WarmPool"))
+					Expect(warmPool.Properties.AutoScalingGroupName).To(Equal(map[string]interface{}{"Ref": "NodeGroup"}))
+					Expect(warmPool.Properties.MinSize).To(Equal("1"))
+					Expect(warmPool.Properties.MaxGroupPreparedCapacity).To(BeEmpty())
+					Expect(warmPool.Properties.PoolState).To(Equal("Stopped"))
+					Expect(warmPool.Properties.InstanceReusePolicy).To(Equal(map[string]interface{}{"ReuseOnScaleIn": true}))
+				})
+			})
+
 			Context("has mixed instances", func() {
 				BeforeEach(func() {
 					ng.InstancesDistribution = &api.NodeGroupInstancesDistribution{
@@ -939,7 +1069,7 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 
 				It("appends autoscaling tags to the ASG", func() {
 					tags := ngTemplate.Resources["NodeGroup"].Properties.Tags
-					Expect(tags).To(HaveLen(4))
+					Expect(tags).To(HaveLen(5))
 					Expect(tags[2].Key).To(Equal("k8s.io/cluster-autoscaler/enabled"))
 					Expect(tags[2].Value).To(Equal("true"))
 					Expect(tags[2].PropagateAtLaunch).To(Equal("true"))
@@ -1027,6 +1157,18 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 						Expect(mapping.Ebs["Encrypted"]).To(Equal(true))
 					})
 				})
+
+				Context("encrypted volumes of a protected cluster", func() {
+					BeforeEach(func() {
+						ng.VolumeEncrypted = aws.Bool(true)
+						cfg.Metadata.Protection = &api.ClusterProtection{TerminationProtection: aws.Bool(true), RetainVPC: aws.Bool(true)}
+					})
+
+					It("the volume is still deleted on instance termination", func() {
+						mapping := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.BlockDeviceMappings[0]
+						Expect(mapping.Ebs).NotTo(HaveKey("DeleteOnTermination"))
+					})
+				})
 			})
 
 			Context("ng.SecurityGroups.AttachIDs are set", func() {
@@ -8,6 +8,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/pkg/errors"
+	gfn "github.com/weaveworks/goformation/v4/cloudformation"
 	gfncfn "github.com/weaveworks/goformation/v4/cloudformation/cloudformation"
 	gfnec2 "github.com/weaveworks/goformation/v4/cloudformation/ec2"
 	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
@@ -55,11 +56,15 @@ type subnetDetails struct {
 func NewVPCResourceSet(rs *resourceSet, clusterConfig *api.ClusterConfig, ec2API ec2iface.EC2API) *VPCResourceSet {
 	var vpcRef *gfnt.Value
 	if clusterConfig.VPC.ID == "" {
-		vpcRef = rs.newResource("VPC", &gfnec2.VPC{
+		vpc := &gfnec2.VPC{
 			CidrBlock:          gfnt.NewString(clusterConfig.VPC.CIDR.String()),
 			EnableDnsSupport:   gfnt.True(),
 			EnableDnsHostnames: gfnt.True(),
-		})
+		}
+		if retainVPC(clusterConfig) {
+			maybeSetRetainDeletionPolicy(vpc)
+		}
+		vpcRef = rs.newResource("VPC", vpc)
 	} else {
 		vpcRef = gfnt.NewString(clusterConfig.VPC.ID)
 	}
@@ -76,6 +81,21 @@ func NewVPCResourceSet(rs *resourceSet, clusterConfig *api.ClusterConfig, ec2API
 	}
 }
 
+// retainVPC returns true when the VPC created by eksctl, along with all its networking resources,
+// is kept when the cluster stack is deleted
+func retainVPC(clusterConfig *api.ClusterConfig) bool {
+	p := clusterConfig.Metadata.Protection
+	return p != nil && api.IsEnabled(p.RetainVPC)
+}
+
+// newResource adds a networking resource of the VPC, which is retained along with the VPC
+func (v *VPCResourceSet) newResource(name string, resource gfn.Resource) *gfnt.Value {
+	if retainVPC(v.clusterConfig) {
+		maybeSetRetainDeletionPolicy(resource)
+	}
+	return v.rs.newResource(name, resource)
+}
+
 // AddResources adds all required resources
 func (v *VPCResourceSet) AddResources() (*VPCResource, error) {
 	vpc := v.clusterConfig.VPC
@@ -87,7 +107,7 @@ func (v *VPCResourceSet) AddResources() (*VPCResource, error) {
 	}
 
 	if api.IsEnabled(vpc.AutoAllocateIPv6) {
-		v.rs.newResource("AutoAllocatedCIDRv6", &gfnec2.VPCCidrBlock{
+		v.newResource("AutoAllocatedCIDRv6", &gfnec2.VPCCidrBlock{
 			VpcId:                       v.vpcResource.VPC,
 			AmazonProvidedIpv6CidrBlock: gfnt.True(),
 		})
@@ -99,18 +119,18 @@ func (v *VPCResourceSet) AddResources() (*VPCResource, error) {
 		return v.vpcResource, nil
 	}
 
-	refIG := v.rs.newResource("InternetGateway", &gfnec2.InternetGateway{})
+	refIG := v.newResource("InternetGateway", &gfnec2.InternetGateway{})
 	vpcGA := "VPCGatewayAttachment"
-	v.rs.newResource(vpcGA, &gfnec2.VPCGatewayAttachment{
+	v.newResource(vpcGA, &gfnec2.VPCGatewayAttachment{
 		InternetGatewayId: refIG,
 		VpcId:             v.vpcResource.VPC,
 	})
 
-	refPublicRT := v.rs.newResource("PublicRouteTable", &gfnec2.RouteTable{
+	refPublicRT := v.newResource("PublicRouteTable", &gfnec2.RouteTable{
 		VpcId: v.vpcResource.VPC,
 	})
 
-	v.rs.newResource("PublicSubnetRoute", &gfnec2.Route{
+	v.newResource("PublicSubnetRoute", &gfnec2.Route{
 		RouteTableId:               refPublicRT,
 		DestinationCidrBlock:       internetCIDR,
 		GatewayId:                  refIG,
@@ -216,8 +236,8 @@ func (v *VPCResourceSet) addSubnets(refRT *gfnt.Value, topology api.SubnetTopolo
 			subnet.MapPublicIpOnLaunch = gfnt.True()
 		}
 		subnetAlias := string(topology) + nameAlias
-		refSubnet := v.rs.newResource("Subnet"+subnetAlias, subnet)
-		v.rs.newResource("RouteTableAssociation"+subnetAlias, &gfnec2.SubnetRouteTableAssociation{
+		refSubnet := v.newResource("Subnet"+subnetAlias, subnet)
+		v.newResource("RouteTableAssociation"+subnetAlias, &gfnec2.SubnetRouteTableAssociation{
 			SubnetId:     refSubnet,
 			RouteTableId: refRT,
 		})
@@ -234,7 +254,7 @@ func (v *VPCResourceSet) addSubnets(refRT *gfnt.Value, topology api.SubnetTopolo
 			refSubnetSlices := gfnt.MakeFnCIDR(
 				refAutoAllocateCIDRv6, gfnt.NewInteger(8), gfnt.NewInteger(64),
 			)
-			v.rs.newResource(subnetAlias+"CIDRv6", &gfnec2.SubnetCidrBlock{
+			v.newResource(subnetAlias+"CIDRv6", &gfnec2.SubnetCidrBlock{
 				SubnetId:      refSubnet,
 				Ipv6CidrBlock: gfnt.MakeFnSelect(gfnt.NewInteger(subnetIndexForIPv6), refSubnetSlices),
 			})
@@ -582,27 +602,27 @@ func (v *VPCResourceSet) haNAT() {
 		alphanumericUpperAZ := strings.ToUpper(strings.Join(strings.Split(az, "-"), ""))
 
 		// Allocate an EIP
-		v.rs.newResource("NATIP"+alphanumericUpperAZ, &gfnec2.EIP{
+		v.newResource("NATIP"+alphanumericUpperAZ, &gfnec2.EIP{
 			Domain: gfnt.NewString("vpc"),
 		})
 		// Allocate a NAT gateway in the public subnet
-		refNG := v.rs.newResource("NATGateway"+alphanumericUpperAZ, &gfnec2.NatGateway{
+		refNG := v.newResource("NATGateway"+alphanumericUpperAZ, &gfnec2.NatGateway{
 			AllocationId: gfnt.MakeFnGetAttString("NATIP"+alphanumericUpperAZ, "AllocationId"),
 			SubnetId:     gfnt.MakeRef("SubnetPublic" + alphanumericUpperAZ),
 		})
 
 		// Allocate a routing table for the private subnet
-		refRT := v.rs.newResource("PrivateRouteTable"+alphanumericUpperAZ, &gfnec2.RouteTable{
+		refRT := v.newResource("PrivateRouteTable"+alphanumericUpperAZ, &gfnec2.RouteTable{
 			VpcId: v.vpcResource.VPC,
 		})
 		// Create a route that sends Internet traffic through the NAT gateway
-		v.rs.newResource("NATPrivateSubnetRoute"+alphanumericUpperAZ, &gfnec2.Route{
+		v.newResource("NATPrivateSubnetRoute"+alphanumericUpperAZ, &gfnec2.Route{
 			RouteTableId:         refRT,
 			DestinationCidrBlock: internetCIDR,
 			NatGatewayId:         refNG,
 		})
 		// Associate the routing table with the subnet
-		v.rs.newResource("RouteTableAssociationPrivate"+alphanumericUpperAZ, &gfnec2.SubnetRouteTableAssociation{
+		v.newResource("RouteTableAssociationPrivate"+alphanumericUpperAZ, &gfnec2.SubnetRouteTableAssociation{
 			SubnetId:     gfnt.MakeRef("SubnetPrivate" + alphanumericUpperAZ),
 			RouteTableId: refRT,
 		})
@@ -613,10 +633,10 @@ func (v *VPCResourceSet) singleNAT() {
 	sortedAZs := v.clusterConfig.AvailabilityZones
 	firstUpperAZ := strings.ToUpper(strings.Join(strings.Split(sortedAZs[0], "-"), ""))
 
-	v.rs.newResource("NATIP", &gfnec2.EIP{
+	v.newResource("NATIP", &gfnec2.EIP{
 		Domain: gfnt.NewString("vpc"),
 	})
-	refNG := v.rs.newResource("NATGateway", &gfnec2.NatGateway{
+	refNG := v.newResource("NATGateway", &gfnec2.NatGateway{
 		AllocationId: gfnt.MakeFnGetAttString("NATIP", "AllocationId"),
 		SubnetId:     gfnt.MakeRef("SubnetPublic" + firstUpperAZ),
 	})
@@ -624,16 +644,16 @@ func (v *VPCResourceSet) singleNAT() {
 	for _, az := range v.clusterConfig.AvailabilityZones {
 		alphanumericUpperAZ := strings.ToUpper(strings.Join(strings.Split(az, "-"), ""))
 
-		refRT := v.rs.newResource("PrivateRouteTable"+alphanumericUpperAZ, &gfnec2.RouteTable{
+		refRT := v.newResource("PrivateRouteTable"+alphanumericUpperAZ, &gfnec2.RouteTable{
 			VpcId: v.vpcResource.VPC,
 		})
 
-		v.rs.newResource("NATPrivateSubnetRoute"+alphanumericUpperAZ, &gfnec2.Route{
+		v.newResource("NATPrivateSubnetRoute"+alphanumericUpperAZ, &gfnec2.Route{
 			RouteTableId:         refRT,
 			DestinationCidrBlock: internetCIDR,
 			NatGatewayId:         refNG,
 		})
-		v.rs.newResource("RouteTableAssociationPrivate"+alphanumericUpperAZ, &gfnec2.SubnetRouteTableAssociation{
+		v.newResource("RouteTableAssociationPrivate"+alphanumericUpperAZ, &gfnec2.SubnetRouteTableAssociation{
 			SubnetId:     gfnt.MakeRef("SubnetPrivate" + alphanumericUpperAZ),
 			RouteTableId: refRT,
 		})
@@ -644,10 +664,10 @@ func (v *VPCResourceSet) noNAT() {
 	for _, az := range v.clusterConfig.AvailabilityZones {
 		alphanumericUpperAZ := strings.ToUpper(strings.Join(strings.Split(az, "-"), ""))
 
-		refRT := v.rs.newResource("PrivateRouteTable"+alphanumericUpperAZ, &gfnec2.RouteTable{
+		refRT := v.newResource("PrivateRouteTable"+alphanumericUpperAZ, &gfnec2.RouteTable{
 			VpcId: v.vpcResource.VPC,
 		})
-		v.rs.newResource("RouteTableAssociationPrivate"+alphanumericUpperAZ, &gfnec2.SubnetRouteTableAssociation{
+		v.newResource("RouteTableAssociationPrivate"+alphanumericUpperAZ, &gfnec2.SubnetRouteTableAssociation{
 			SubnetId:     gfnt.MakeRef("SubnetPrivate" + alphanumericUpperAZ),
 			RouteTableId: refRT,
 		})
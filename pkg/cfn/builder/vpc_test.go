@@ -254,6 +254,19 @@ var _ = Describe("VPC Template Builder", func() {
 			})
 		})
 
+		Context("the VPC is retained", func() {
+			BeforeEach(func() {
+				cfg.Metadata.Protection = &api.ClusterProtection{RetainVPC: aws.Bool(true)}
+			})
+
+			It("sets the deletion policy of the VPC and all its networking resources to Retain", func() {
+				Expect(vpcTemplate.Resources).To(HaveKey(igwKey))
+				for name, resource := range vpcTemplate.Resources {
+					Expect(resource.DeletionPolicy).To(Equal("Retain"), "resource %s", name)
+				}
+			})
+		})
+
 		Context("an invalid nat option is set", func() {
 			BeforeEach(func() {
 				*cfg.VPC.NAT.Gateway = "some-trash"
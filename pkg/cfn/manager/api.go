@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -21,6 +22,7 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
 	"github.com/weaveworks/eksctl/pkg/cfn/waiter"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
 	"github.com/weaveworks/eksctl/pkg/version"
 )
 
@@ -78,15 +80,21 @@ type StackCollection struct {
 	roleARN           string
 	region            string
 	waitTimeout       time.Duration
+	streamEvents      bool
+	maxParallelStacks int
+	templateUploader  TemplateUploader
 	sharedTags        []*cloudformation.Tag
+
+	createdStacksMutex sync.Mutex
+	createdStacks      []*Stack
 }
 
 func newTag(key, value string) *cloudformation.Tag {
 	return &cloudformation.Tag{Key: &key, Value: &value}
 }
 
-// NewStackCollection creates a stack manager for a single cluster
-func NewStackCollection(provider api.ClusterProvider, spec *api.ClusterConfig) *StackCollection {
+// makeSharedTags returns the tags that are set on every stack of a cluster
+func makeSharedTags(spec *api.ClusterConfig) []*cloudformation.Tag {
 	tags := []*cloudformation.Tag{
 		newTag(api.ClusterNameTag, spec.Metadata.Name),
 		newTag(api.OldClusterNameTag, spec.Metadata.Name),
@@ -95,9 +103,14 @@ func NewStackCollection(provider api.ClusterProvider, spec *api.ClusterConfig) *
 	for key, value := range spec.Metadata.Tags {
 		tags = append(tags, newTag(key, value))
 	}
+	return tags
+}
+
+// NewStackCollection creates a stack manager for a single cluster
+func NewStackCollection(provider api.ClusterProvider, spec *api.ClusterConfig) *StackCollection {
 	return &StackCollection{
 		spec:              spec,
-		sharedTags:        tags,
+		sharedTags:        makeSharedTags(spec),
 		cloudformationAPI: provider.CloudFormation(),
 		ec2API:            provider.EC2(),
 		eksAPI:            provider.EKS(),
@@ -108,20 +121,29 @@ func NewStackCollection(provider api.ClusterProvider, spec *api.ClusterConfig) *
 		roleARN:           provider.CloudFormationRoleARN(),
 		region:            provider.Region(),
 		waitTimeout:       provider.WaitTimeout(),
+		streamEvents:      provider.StreamStackEvents(),
+		maxParallelStacks: provider.MaxParallelStacks(),
+		templateUploader:  NewS3TemplateUploader(provider.S3(), provider.STS(), provider.CloudFormationTemplateBucket(), spec.Metadata.Name, provider.Region()),
 	}
 }
 
 // DoCreateStackRequest requests the creation of a CloudFormation stack
 func (c *StackCollection) DoCreateStackRequest(i *Stack, templateData TemplateData, tags, parameters map[string]string, withIAM bool, withNamedIAM bool) error {
 	input := &cloudformation.CreateStackInput{
-		StackName:       i.StackName,
-		DisableRollback: aws.Bool(c.disableRollback),
+		StackName:                   i.StackName,
+		DisableRollback:             aws.Bool(c.disableRollback),
+		EnableTerminationProtection: i.EnableTerminationProtection,
 	}
 	input.Tags = append(input.Tags, c.sharedTags...)
 	for k, v := range tags {
 		input.Tags = append(input.Tags, newTag(k, v))
 	}
 
+	templateData, tagsWithBucket, err := c.uploadIfTooLarge(*i.StackName, templateData, input.Tags)
+	if err != nil {
+		return err
+	}
+	input.Tags = tagsWithBucket
 	switch data := templateData.(type) {
 	case TemplateBody:
 		input.SetTemplateBody(string(data))
@@ -157,30 +179,58 @@ func (c *StackCollection) DoCreateStackRequest(i *Stack, templateData TemplateDa
 		return errors.Wrapf(err, "creating CloudFormation stack %q", *i.StackName)
 	}
 	i.StackId = s.StackId
+	c.recordCreatedStack(i)
 	return nil
 }
 
+func (c *StackCollection) recordCreatedStack(s *Stack) {
+	c.createdStacksMutex.Lock()
+	defer c.createdStacksMutex.Unlock()
+	c.createdStacks = append(c.createdStacks, s)
+}
+
+// CreatedStacks returns the stacks whose creation was requested using this
+// StackCollection, in the order of the requests
+func (c *StackCollection) CreatedStacks() []*Stack {
+	c.createdStacksMutex.Lock()
+	defer c.createdStacksMutex.Unlock()
+	return append([]*Stack(nil), c.createdStacks...)
+}
+
 // CreateStack with given name, stack builder instance and parameters;
 // any errors will be written to errs channel, when nil is written,
 // assume completion, do not expect more then one error value on the
-// channel, it's closed immediately after it is written to
-func (c *StackCollection) CreateStack(stackName string, resourceSet builder.ResourceSet, tags, parameters map[string]string, errs chan error) error {
-	stack, err := c.createStackRequest(stackName, resourceSet, tags, parameters)
+// channel, it's closed immediately after it is written to;
+// when resuming an operation, an existing stack of the same name is waited for instead
+func (c *StackCollection) CreateStack(ctx context.Context, stackName string, resourceSet builder.ResourceSet, tags, parameters map[string]string, errs chan error) error {
+	stack, err := c.stackToResume(ctx, stackName)
 	if err != nil {
 		return err
 	}
+	if stack == nil {
+		stack, err = c.createStackRequest(ctx, stackName, resourceSet, tags, parameters)
+		if err != nil {
+			return err
+		}
+	}
 
-	go c.waitUntilStackIsCreated(stack, resourceSet, errs)
+	go c.waitUntilStackIsCreated(ctx, stack, resourceSet, errs)
 	return nil
 }
 
 // createClusterStack creates the cluster stack
-func (c *StackCollection) createClusterStack(stackName string, resourceSet builder.ResourceSet, errCh chan error) error {
-	// Unlike with `createNodeGroupTask`, all tags are already set for the cluster stack
-	stack, err := c.createStackRequest(stackName, resourceSet, nil, nil)
+func (c *StackCollection) createClusterStack(ctx context.Context, stackName string, resourceSet builder.ResourceSet, errCh chan error) error {
+	stack, err := c.stackToResume(ctx, stackName)
 	if err != nil {
 		return err
 	}
+	if stack == nil {
+		// Unlike with `createNodeGroupTask`, all tags are already set for the cluster stack
+		stack, err = c.createStackRequest(ctx, stackName, resourceSet, nil, nil)
+		if err != nil {
+			return err
+		}
+	}
 	go func() {
 		defer close(errCh)
 		troubleshoot := func() {
@@ -195,7 +245,7 @@ func (c *StackCollection) createClusterStack(stackName string, resourceSet build
 			c.troubleshootStackFailureCause(stack, cloudformation.StackStatusCreateComplete)
 		}
 
-		ctx, cancelFunc := context.WithTimeout(context.Background(), c.waitTimeout)
+		ctx, cancelFunc := context.WithTimeout(ctx, c.waitTimeout)
 		defer cancelFunc()
 
 		stack, err := waiter.WaitForStack(ctx, c.cloudformationAPI, *stack.StackId, *stack.StackName, func(attempts int) time.Duration {
@@ -223,8 +273,13 @@ func (c *StackCollection) createClusterStack(stackName string, resourceSet build
 	return nil
 }
 
-func (c *StackCollection) createStackRequest(stackName string, resourceSet builder.ResourceSet, tags, parameters map[string]string) (*Stack, error) {
+// createStackRequest requests the creation of the stack, and records its ID in the journal of ctx,
+// so that resuming the operation knows the stack was created by it
+func (c *StackCollection) createStackRequest(ctx context.Context, stackName string, resourceSet builder.ResourceSet, tags, parameters map[string]string) (*Stack, error) {
 	stack := &Stack{StackName: &stackName}
+	if c.withTerminationProtection(resourceSet) {
+		stack.EnableTerminationProtection = aws.Bool(true)
+	}
 	templateBody, err := resourceSet.RenderJSON()
 	if err != nil {
 		return nil, errors.Wrapf(err, "rendering template for %q stack", *stack.StackName)
@@ -233,13 +288,14 @@ func (c *StackCollection) createStackRequest(stackName string, resourceSet build
 	if err := c.DoCreateStackRequest(stack, TemplateBody(templateBody), tags, parameters, resourceSet.WithIAM(), resourceSet.WithNamedIAM()); err != nil {
 		return nil, err
 	}
+	tasks.JournalFromContext(ctx).RecordStack(stackName, *stack.StackId)
 
 	logger.Info("deploying stack %q", stackName)
 	return stack, nil
 }
 
 // UpdateStack will update a CloudFormation stack by creating and executing a ChangeSet
-func (c *StackCollection) UpdateStack(options UpdateStackOptions) error {
+func (c *StackCollection) UpdateStack(ctx context.Context, options UpdateStackOptions) (err error) {
 	logger.Info(options.Description)
 	i := &Stack{StackName: &options.StackName}
 	// Read existing tags
@@ -247,15 +303,27 @@ func (c *StackCollection) UpdateStack(options UpdateStackOptions) error {
 	if err != nil {
 		return err
 	}
+	if err := c.reconcileTerminationProtection(s, options.Plan); err != nil {
+		return err
+	}
 	if err := c.doCreateChangeSetRequest(options.StackName, options.ChangeSetName, options.Description, options.TemplateData, options.Parameters, s.Capabilities, s.Tags); err != nil {
 		return err
 	}
-	if err := c.doWaitUntilChangeSetIsCreated(i, options.ChangeSetName); err != nil {
+	if options.Plan {
+		defer c.deletePlanChangeSet(options.StackName, options.ChangeSetName, &err)
+	}
+	if err := c.doWaitUntilChangeSetIsCreated(ctx, i, options.ChangeSetName); err != nil {
 		if _, ok := err.(*noChangeError); ok {
+			if options.Plan {
+				logger.Info("(plan) no changes to stack %q", options.StackName)
+			}
 			return nil
 		}
 		return err
 	}
+	if options.Plan {
+		return c.previewChangeSet(i, options.ChangeSetName)
+	}
 	changeSet, err := c.DescribeStackChangeSet(i, options.ChangeSetName)
 	if err != nil {
 		return err
@@ -266,7 +334,7 @@ func (c *StackCollection) UpdateStack(options UpdateStackOptions) error {
 		return err
 	}
 	if options.Wait {
-		return c.doWaitUntilStackIsUpdated(i)
+		return c.doWaitUntilStackIsUpdated(ctx, i)
 	}
 	return nil
 }
@@ -306,15 +374,17 @@ func (c *StackCollection) GetManagedNodeGroupTemplate(nodeGroupName string) (str
 	return templateBody, nil
 }
 
-// UpdateNodeGroupStack updates the nodegroup stack with the specified template
-func (c *StackCollection) UpdateNodeGroupStack(nodeGroupName, template string, wait bool) error {
+// UpdateNodeGroupStack updates the nodegroup stack with the specified template,
+// or only previews the changes when plan is true
+func (c *StackCollection) UpdateNodeGroupStack(ctx context.Context, nodeGroupName, template string, wait, plan bool) error {
 	stackName := c.makeNodeGroupStackName(nodeGroupName)
-	return c.UpdateStack(UpdateStackOptions{
+	return c.UpdateStack(ctx, UpdateStackOptions{
 		StackName:     stackName,
 		ChangeSetName: c.MakeChangeSetName("update-nodegroup"),
 		Description:   "updating nodegroup stack",
 		TemplateData:  TemplateBody(template),
 		Wait:          wait,
+		Plan:          plan,
 	})
 }
 
@@ -405,6 +475,8 @@ func nonTransitionalReadyStackStatuses() []string {
 		cloudformation.StackStatusUpdateComplete,
 		cloudformation.StackStatusRollbackComplete,
 		cloudformation.StackStatusUpdateRollbackComplete,
+		cloudformation.StackStatusImportComplete,
+		cloudformation.StackStatusImportRollbackComplete,
 	}
 }
 
@@ -432,6 +504,9 @@ func nonReadyStackStatuses() []string {
 		cloudformation.StackStatusUpdateRollbackFailed,
 		cloudformation.StackStatusUpdateRollbackCompleteCleanupInProgress,
 		cloudformation.StackStatusReviewInProgress,
+		cloudformation.StackStatusImportInProgress,
+		cloudformation.StackStatusImportRollbackInProgress,
+		cloudformation.StackStatusImportRollbackFailed,
 	}
 }
 
@@ -453,6 +528,11 @@ func allNonDeletedStackStatuses() []string {
 		cloudformation.StackStatusUpdateRollbackCompleteCleanupInProgress,
 		cloudformation.StackStatusUpdateRollbackComplete,
 		cloudformation.StackStatusReviewInProgress,
+		cloudformation.StackStatusImportInProgress,
+		cloudformation.StackStatusImportComplete,
+		cloudformation.StackStatusImportRollbackInProgress,
+		cloudformation.StackStatusImportRollbackFailed,
+		cloudformation.StackStatusImportRollbackComplete,
 	}
 }
 
@@ -482,7 +562,7 @@ func (c *StackCollection) DeleteStackByName(name string) (*Stack, error) {
 // DeleteStackByNameSync sends a request to delete the stack, and waits until status is DELETE_COMPLETE;
 // any errors will be written to errs channel, assume completion when nil is written, do not expect
 // more then one error value on the channel, it's closed immediately after it is written to
-func (c *StackCollection) DeleteStackByNameSync(name string) error {
+func (c *StackCollection) DeleteStackByNameSync(ctx context.Context, name string) error {
 	stack, err := c.DeleteStackByName(name)
 	if err != nil {
 		return err
@@ -490,7 +570,7 @@ func (c *StackCollection) DeleteStackByNameSync(name string) error {
 
 	logger.Info("waiting for stack %q to get deleted", *stack.StackName)
 
-	return c.doWaitUntilStackIsDeleted(stack)
+	return c.doWaitUntilStackIsDeleted(ctx, stack)
 }
 
 // DeleteStackBySpec sends a request to delete the stack
@@ -509,6 +589,7 @@ func (c *StackCollection) DeleteStackBySpec(s *Stack) (*Stack, error) {
 				return nil, errors.Wrapf(err, "not able to delete stack %q", *s.StackName)
 			}
 			logger.Info("will delete stack %q", *s.StackName)
+			c.deleteUploadedStackTemplates(s)
 			return s, nil
 		}
 	}
@@ -531,8 +612,9 @@ func matchesClusterName(key, value, name string) bool {
 
 // DeleteStackBySpecSync sends a request to delete the stack, and waits until status is DELETE_COMPLETE;
 // any errors will be written to errs channel, assume completion when nil is written, do not expect
-// more then one error value on the channel, it's closed immediately after it is written to
-func (c *StackCollection) DeleteStackBySpecSync(s *Stack, errs chan error) error {
+// more then one error value on the channel, it's closed immediately after it is written to;
+// waiting stops when ctx is cancelled
+func (c *StackCollection) DeleteStackBySpecSync(ctx context.Context, s *Stack, errs chan error) error {
 	i, err := c.DeleteStackBySpec(s)
 	if err != nil {
 		return err
@@ -540,7 +622,7 @@ func (c *StackCollection) DeleteStackBySpecSync(s *Stack, errs chan error) error
 
 	logger.Info("waiting for stack %q to get deleted", *i.StackName)
 
-	go c.waitUntilStackIsDeleted(i, errs)
+	go c.waitUntilStackIsDeleted(ctx, i, errs)
 
 	return nil
 }
@@ -614,6 +696,27 @@ func (c *StackCollection) DescribeStackEvents(i *Stack) ([]*cloudformation.Stack
 	return events, nil
 }
 
+// ListStackResources lists the resources of the stack
+func (c *StackCollection) ListStackResources(i *Stack) ([]*cloudformation.StackResourceSummary, error) {
+	input := &cloudformation.ListStackResourcesInput{
+		StackName: i.StackName,
+	}
+	if api.IsSetAndNonEmptyString(i.StackId) {
+		input.StackName = i.StackId
+	}
+
+	var resources []*cloudformation.StackResourceSummary
+	pager := func(p *cloudformation.ListStackResourcesOutput, _ bool) bool {
+		resources = append(resources, p.StackResourceSummaries...)
+		return true
+	}
+	if err := c.cloudformationAPI.ListStackResourcesPages(input, pager); err != nil {
+		return nil, errors.Wrapf(err, "listing resources of CloudFormation stack %q", *i.StackName)
+	}
+
+	return resources, nil
+}
+
 // LookupCloudTrailEvents looks up stack events in CloudTrail
 func (c *StackCollection) LookupCloudTrailEvents(i *Stack) ([]*cloudtrail.Event, error) {
 	input := &cloudtrail.LookupEventsInput{
@@ -647,6 +750,11 @@ func (c *StackCollection) doCreateChangeSetRequest(stackName, changeSetName, des
 
 	input.SetChangeSetType(cloudformation.ChangeSetTypeUpdate)
 
+	templateData, tagsWithBucket, err := c.uploadIfTooLarge(stackName, templateData, input.Tags)
+	if err != nil {
+		return err
+	}
+	input.Tags = tagsWithBucket
 	switch data := templateData.(type) {
 	case TemplateBody:
 		input.SetTemplateBody(string(data))
@@ -1,10 +1,15 @@
 package manager
 
 import (
+	"bytes"
+	"context"
+	"os"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/awstesting"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/kris-nova/logger"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/mock"
@@ -46,7 +51,7 @@ var _ = Describe("StackCollection", func() {
 			p.MockCloudFormation().On("DescribeChangeSet", mock.Anything).Return(describeChangeSetNoChange, nil)
 
 			sm := NewStackCollection(p, api.NewClusterConfig())
-			err := sm.UpdateStack(UpdateStackOptions{
+			err := sm.UpdateStack(context.Background(), UpdateStackOptions{
 				StackName:     stackName,
 				ChangeSetName: changeSetName,
 				Description:   "description",
@@ -55,6 +60,46 @@ var _ = Describe("StackCollection", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("logs that there are no changes and deletes the ChangeSet in plan mode", func() {
+			stackName := "eksctl-stack"
+			changeSetName := "eksctl-changeset"
+			describeChangeSetFailed := &cfn.DescribeChangeSetOutput{
+				StackName:     &stackName,
+				ChangeSetName: &changeSetName,
+				Status:        aws.String(cfn.ChangeSetStatusFailed),
+			}
+			p := mockprovider.NewMockProvider()
+			p.MockCloudFormation().On("DescribeStacks", mock.Anything).Return(&cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{{
+				StackName:   &stackName,
+				StackStatus: aws.String(cfn.StackStatusCreateComplete),
+			}}}, nil)
+			p.MockCloudFormation().On("CreateChangeSet", mock.Anything).Return(nil, nil)
+			req := awstesting.NewClient(nil).NewRequest(&request.Operation{Name: "Operation"}, nil, describeChangeSetFailed)
+			p.MockCloudFormation().On("DescribeChangeSetRequest", mock.Anything).Return(req, describeChangeSetFailed)
+			p.MockCloudFormation().On("DescribeChangeSet", mock.Anything).Return(&cfn.DescribeChangeSetOutput{
+				StackName:    &stackName,
+				StatusReason: aws.String("The submitted information didn't contain changes"),
+			}, nil)
+			p.MockCloudFormation().On("DeleteChangeSet", mock.MatchedBy(func(input *cfn.DeleteChangeSetInput) bool {
+				return *input.StackName == stackName && *input.ChangeSetName == changeSetName
+			})).Return(&cfn.DeleteChangeSetOutput{}, nil)
+
+			output := &bytes.Buffer{}
+			logger.Writer = output
+			defer func() { logger.Writer = os.Stdout }()
+
+			sm := NewStackCollection(p, api.NewClusterConfig())
+			Expect(sm.UpdateStack(context.Background(), UpdateStackOptions{
+				StackName:     stackName,
+				ChangeSetName: changeSetName,
+				Description:   "description",
+				TemplateData:  TemplateBody(""),
+				Plan:          true,
+			})).To(Succeed())
+			Expect(output.String()).To(ContainSubstring(`(plan) no changes to stack "eksctl-stack"`))
+			p.MockCloudFormation().AssertNumberOfCalls(GinkgoT(), "DeleteChangeSet", 1)
+		})
 	})
 
 	It("updates tags (existing + metadata + auto)", func() {
@@ -111,7 +156,7 @@ var _ = Describe("StackCollection", func() {
 		spec.Metadata.Name = clusterName
 		spec.Metadata.Tags = map[string]string{"meta": "data"}
 		sm := NewStackCollection(p, spec)
-		err := sm.UpdateStack(UpdateStackOptions{
+		err := sm.UpdateStack(context.Background(), UpdateStackOptions{
 			StackName:     stackName,
 			ChangeSetName: changeSetName,
 			Description:   "description",
@@ -170,7 +215,7 @@ var _ = Describe("StackCollection", func() {
 			spec.Metadata.Name = clusterName
 			spec.Metadata.Tags = map[string]string{"meta": "data"}
 			sm := NewStackCollection(p, spec)
-			err := sm.UpdateStack(UpdateStackOptions{
+			err := sm.UpdateStack(context.Background(), UpdateStackOptions{
 				StackName:     stackName,
 				ChangeSetName: changeSetName,
 				Description:   "description",
@@ -0,0 +1,147 @@
+package manager
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+)
+
+// ResourceChange is a single resource-level change in a ChangeSet
+type ResourceChange struct {
+	// Action is one of Add, Modify, Remove, Import or Dynamic
+	Action string
+	// LogicalID is the logical ID of the resource in the template
+	LogicalID string
+	// ResourceType is the CloudFormation type of the resource
+	ResourceType string
+	// Replacement is True, False or Conditional for Modify actions, and empty otherwise
+	Replacement string
+	// Properties lists the properties or attributes that changed, properties that force
+	// the resource to be recreated are suffixed with "(recreate)"
+	Properties []string
+}
+
+// RequiresReplacement returns true when CloudFormation may replace the resource
+func (r ResourceChange) RequiresReplacement() bool {
+	return r.Replacement == cloudformation.ReplacementTrue || r.Replacement == cloudformation.ReplacementConditional
+}
+
+// GetResourceChanges returns the resource-level changes described by a ChangeSet
+func GetResourceChanges(changeSet *ChangeSet) []ResourceChange {
+	var changes []ResourceChange
+	for _, change := range changeSet.Changes {
+		rc := change.ResourceChange
+		if rc == nil {
+			continue
+		}
+		resourceChange := ResourceChange{
+			Action:       aws.StringValue(rc.Action),
+			LogicalID:    aws.StringValue(rc.LogicalResourceId),
+			ResourceType: aws.StringValue(rc.ResourceType),
+			Replacement:  aws.StringValue(rc.Replacement),
+		}
+		seen := map[string]bool{}
+		for _, detail := range rc.Details {
+			if detail.Target == nil {
+				continue
+			}
+			property := aws.StringValue(detail.Target.Attribute)
+			if property == cloudformation.ResourceAttributeProperties && detail.Target.Name != nil {
+				property = *detail.Target.Name
+			}
+			if aws.StringValue(detail.Target.RequiresRecreation) == cloudformation.RequiresRecreationAlways {
+				property += " (recreate)"
+			}
+			if !seen[property] {
+				seen[property] = true
+				resourceChange.Properties = append(resourceChange.Properties, property)
+			}
+		}
+		changes = append(changes, resourceChange)
+	}
+	return changes
+}
+
+// FormatChangeSet renders the resource-level changes in a ChangeSet as a table
+func FormatChangeSet(changeSet *ChangeSet) string {
+	changes := GetResourceChanges(changeSet)
+	if len(changes) == 0 {
+		return "no resource changes"
+	}
+
+	symbols := map[string]string{
+		cloudformation.ChangeActionAdd:    "+",
+		cloudformation.ChangeActionModify: "~",
+		cloudformation.ChangeActionRemove: "-",
+	}
+
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tACTION\tLOGICAL ID\tTYPE\tREPLACEMENT\tPROPERTIES")
+	for _, c := range changes {
+		symbol, ok := symbols[c.Action]
+		if !ok {
+			symbol = "?"
+		}
+		replacement := c.Replacement
+		if replacement == "" {
+			replacement = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", symbol, c.Action, c.LogicalID, c.ResourceType, replacement, strings.Join(c.Properties, ", "))
+	}
+	_ = w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func (c *StackCollection) doDeleteChangeSet(stackName, changeSetName string) error {
+	input := &cloudformation.DeleteChangeSetInput{
+		ChangeSetName: &changeSetName,
+		StackName:     &stackName,
+	}
+
+	logger.Debug("deleting changeSet, input = %#v", input)
+
+	if _, err := c.cloudformationAPI.DeleteChangeSet(input); err != nil {
+		return errors.Wrapf(err, "deleting CloudFormation ChangeSet %q for stack %q", changeSetName, stackName)
+	}
+	return nil
+}
+
+// deletePlanChangeSet deletes a ChangeSet that was only created to preview the changes to a stack,
+// however the preview ended; an error of the preview in err takes precedence over an error deleting it
+func (c *StackCollection) deletePlanChangeSet(stackName, changeSetName string, err *error) {
+	deleteErr := c.doDeleteChangeSet(stackName, changeSetName)
+	if deleteErr == nil {
+		return
+	}
+	if *err != nil {
+		logger.Warning("%v", deleteErr)
+		return
+	}
+	*err = deleteErr
+}
+
+// previewChangeSet logs the changes in a ChangeSet without executing it
+func (c *StackCollection) previewChangeSet(i *Stack, changeSetName string) error {
+	changeSet, err := c.DescribeStackChangeSet(i, changeSetName)
+	if err != nil {
+		return err
+	}
+	changes := GetResourceChanges(changeSet)
+	logger.Info("(plan) %d resource change(s) to stack %q", len(changes), *i.StackName)
+	for _, line := range strings.Split(FormatChangeSet(changeSet), "\n") {
+		logger.Info("(plan)   %s", line)
+	}
+	for _, change := range changes {
+		if change.RequiresReplacement() {
+			logger.Warning("(plan) %s %q (%s) will be replaced", change.ResourceType, change.LogicalID, change.Replacement)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("ChangeSet preview", func() {
+	var changeSet *ChangeSet
+
+	BeforeEach(func() {
+		changeSet = &ChangeSet{
+			Changes: []*cfn.Change{
+				{
+					ResourceChange: &cfn.ResourceChange{
+						Action:            aws.String(cfn.ChangeActionModify),
+						LogicalResourceId: aws.String("NodeGroupLaunchTemplate"),
+						ResourceType:      aws.String("AWS::EC2::LaunchTemplate"),
+						Replacement:       aws.String(cfn.ReplacementTrue),
+						Details: []*cfn.ResourceChangeDetail{
+							{
+								Target: &cfn.ResourceTargetDefinition{
+									Attribute:          aws.String(cfn.ResourceAttributeProperties),
+									Name:               aws.String("LaunchTemplateName"),
+									RequiresRecreation: aws.String(cfn.RequiresRecreationAlways),
+								},
+							},
+							{
+								Target: &cfn.ResourceTargetDefinition{
+									Attribute: aws.String(cfn.ResourceAttributeTags),
+								},
+							},
+						},
+					},
+				},
+				{
+					ResourceChange: &cfn.ResourceChange{
+						Action:            aws.String(cfn.ChangeActionAdd),
+						LogicalResourceId: aws.String("PolicyCloudWatchMetrics"),
+						ResourceType:      aws.String("AWS::IAM::Policy"),
+					},
+				},
+				{
+					ResourceChange: &cfn.ResourceChange{
+						Action:            aws.String(cfn.ChangeActionRemove),
+						LogicalResourceId: aws.String("PolicyELBPermissions"),
+						ResourceType:      aws.String("AWS::IAM::Policy"),
+					},
+				},
+			},
+		}
+	})
+
+	It("extracts resource changes", func() {
+		changes := GetResourceChanges(changeSet)
+		Expect(changes).To(HaveLen(3))
+		Expect(changes[0]).To(Equal(ResourceChange{
+			Action:       "Modify",
+			LogicalID:    "NodeGroupLaunchTemplate",
+			ResourceType: "AWS::EC2::LaunchTemplate",
+			Replacement:  "True",
+			Properties:   []string{"LaunchTemplateName (recreate)", "Tags"},
+		}))
+		Expect(changes[0].RequiresReplacement()).To(BeTrue())
+		Expect(changes[1].RequiresReplacement()).To(BeFalse())
+	})
+
+	It("formats resource changes as a table", func() {
+		lines := []string{
+			"   ACTION  LOGICAL ID               TYPE                      REPLACEMENT  PROPERTIES",
+			"~  Modify  NodeGroupLaunchTemplate  AWS::EC2::LaunchTemplate  True         LaunchTemplateName (recreate), Tags",
+			"+  Add     PolicyCloudWatchMetrics  AWS::IAM::Policy          -            ",
+			"-  Remove  PolicyELBPermissions     AWS::IAM::Policy          -            ",
+		}
+		Expect(FormatChangeSet(changeSet)).To(Equal(lines[0] + "\n" + lines[1] + "\n" + lines[2] + "\n" + lines[3]))
+		Expect(FormatChangeSet(&ChangeSet{})).To(Equal("no resource changes"))
+	})
+
+	It("deletes the ChangeSet instead of executing it in plan mode", func() {
+		stackName := "eksctl-stack"
+		changeSetName := "eksctl-changeset"
+		changeSet.StackName = &stackName
+		changeSet.ChangeSetName = &changeSetName
+		changeSet.Status = aws.String(cfn.ChangeSetStatusCreateComplete)
+
+		p := mockprovider.NewMockProvider()
+		p.MockCloudFormation().On("DescribeStacks", mock.Anything).Return(&cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{{
+			StackName:   &stackName,
+			StackStatus: aws.String(cfn.StackStatusCreateComplete),
+		}}}, nil)
+		p.MockCloudFormation().On("CreateChangeSet", mock.Anything).Return(nil, nil)
+		req := awstesting.NewClient(nil).NewRequest(&request.Operation{Name: "Operation"}, nil, changeSet)
+		p.MockCloudFormation().On("DescribeChangeSetRequest", mock.Anything).Return(req, changeSet)
+		p.MockCloudFormation().On("DescribeChangeSet", mock.Anything).Return(changeSet, nil)
+		p.MockCloudFormation().On("DeleteChangeSet", &cfn.DeleteChangeSetInput{
+			ChangeSetName: &changeSetName,
+			StackName:     &stackName,
+		}).Return(nil, nil)
+
+		sm := NewStackCollection(p, api.NewClusterConfig())
+		err := sm.UpdateStack(context.Background(), UpdateStackOptions{
+			StackName:     stackName,
+			ChangeSetName: changeSetName,
+			Description:   "description",
+			TemplateData:  TemplateBody(""),
+			Wait:          true,
+			Plan:          true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		p.MockCloudFormation().AssertCalled(GinkgoT(), "DeleteChangeSet", mock.Anything)
+		p.MockCloudFormation().AssertNotCalled(GinkgoT(), "ExecuteChangeSet", mock.Anything)
+	})
+
+	It("deletes the ChangeSet in plan mode when it cannot be created", func() {
+		stackName := "eksctl-stack"
+		changeSetName := "eksctl-changeset"
+		changeSet.StackName = &stackName
+		changeSet.ChangeSetName = &changeSetName
+		changeSet.Status = aws.String(cfn.ChangeSetStatusFailed)
+		changeSet.StatusReason = aws.String("Template format error")
+
+		p := mockprovider.NewMockProvider()
+		p.MockCloudFormation().On("DescribeStacks", mock.Anything).Return(&cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{{
+			StackName:   &stackName,
+			StackStatus: aws.String(cfn.StackStatusCreateComplete),
+		}}}, nil)
+		p.MockCloudFormation().On("CreateChangeSet", mock.Anything).Return(nil, nil)
+		req := awstesting.NewClient(nil).NewRequest(&request.Operation{Name: "Operation"}, nil, changeSet)
+		p.MockCloudFormation().On("DescribeChangeSetRequest", mock.Anything).Return(req, changeSet)
+		p.MockCloudFormation().On("DescribeChangeSet", mock.Anything).Return(changeSet, nil)
+		p.MockCloudFormation().On("DeleteChangeSet", &cfn.DeleteChangeSetInput{
+			ChangeSetName: &changeSetName,
+			StackName:     &stackName,
+		}).Return(nil, nil)
+
+		sm := NewStackCollection(p, api.NewClusterConfig())
+		err := sm.UpdateStack(context.Background(), UpdateStackOptions{
+			StackName:     stackName,
+			ChangeSetName: changeSetName,
+			Description:   "description",
+			TemplateData:  TemplateBody(""),
+			Wait:          true,
+			Plan:          true,
+		})
+		Expect(err).To(HaveOccurred())
+		p.MockCloudFormation().AssertCalled(GinkgoT(), "DeleteChangeSet", mock.Anything)
+		p.MockCloudFormation().AssertNotCalled(GinkgoT(), "ExecuteChangeSet", mock.Anything)
+	})
+})
@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -27,14 +28,14 @@ func (c *StackCollection) MakeClusterStackName() string {
 }
 
 // createClusterTask creates the cluster
-func (c *StackCollection) createClusterTask(errs chan error, supportsManagedNodes bool) error {
+func (c *StackCollection) createClusterTask(ctx context.Context, errs chan error, supportsManagedNodes bool) error {
 	name := c.MakeClusterStackName()
 	logger.Info("building cluster stack %q", name)
 	stack := builder.NewClusterResourceSet(c.ec2API, c.region, c.spec, supportsManagedNodes, nil)
 	if err := stack.AddAllResources(); err != nil {
 		return err
 	}
-	return c.createClusterStack(name, stack, errs)
+	return c.createClusterStack(ctx, name, stack, errs)
 }
 
 // DescribeClusterStack calls DescribeStacks and filters out cluster stack
@@ -96,7 +97,7 @@ func (c *StackCollection) RefreshFargatePodExecutionRoleARN() error {
 
 // AppendNewClusterStackResource will update cluster
 // stack with new resources in append-only way
-func (c *StackCollection) AppendNewClusterStackResource(plan, supportsManagedNodes bool) (bool, error) {
+func (c *StackCollection) AppendNewClusterStackResource(ctx context.Context, plan, supportsManagedNodes bool) (bool, error) {
 	name := c.MakeClusterStackName()
 
 	// NOTE: currently we can only append new resources to the stack,
@@ -182,7 +183,11 @@ func (c *StackCollection) AppendNewClusterStackResource(plan, supportsManagedNod
 
 	if len(addResources) == 0 && len(addOutputs) == 0 && len(addMappings) == 0 {
 		logger.Success("all resources in cluster stack %q are up-to-date", name)
-		return false, nil
+		s, err := c.DescribeStack(&Stack{StackName: &name})
+		if err != nil {
+			return false, err
+		}
+		return false, c.reconcileTerminationProtection(s, plan)
 	}
 
 	logger.Debug("currentTemplate = %s", currentTemplate)
@@ -190,14 +195,14 @@ func (c *StackCollection) AppendNewClusterStackResource(plan, supportsManagedNod
 	describeUpdate := fmt.Sprintf("updating stack to add new resources %v and outputs %v", addResources, addOutputs)
 	if plan {
 		logger.Info("(plan) %s", describeUpdate)
-		return true, nil
 	}
-	return true, c.UpdateStack(UpdateStackOptions{
+	return true, c.UpdateStack(ctx, UpdateStackOptions{
 		StackName:     name,
 		ChangeSetName: c.MakeChangeSetName("update-cluster"),
 		Description:   describeUpdate,
 		TemplateData:  TemplateBody(currentTemplate),
 		Wait:          true,
+		Plan:          plan,
 	})
 }
 
@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/kris-nova/logger"
@@ -16,7 +17,7 @@ import (
 
 // FixClusterCompatibility adds any resources missing in the CloudFormation stack in order to support new features
 // like Managed Nodegroups and Fargate
-func (c *StackCollection) FixClusterCompatibility() error {
+func (c *StackCollection) FixClusterCompatibility(ctx context.Context) error {
 	logger.Info("checking cluster stack for missing resources")
 	stack, err := c.DescribeClusterStack()
 	if err != nil {
@@ -74,7 +75,7 @@ func (c *StackCollection) FixClusterCompatibility() error {
 	}
 
 	logger.Info("adding missing resources to cluster stack")
-	_, err = c.AppendNewClusterStackResource(false, stackSupportsManagedNodes)
+	_, err = c.AppendNewClusterStackResource(ctx, false, stackSupportsManagedNodes)
 	return err
 }
 
@@ -88,7 +89,7 @@ func (c *StackCollection) hasManagedToUnmanagedSG() (bool, error) {
 }
 
 // EnsureMapPublicIPOnLaunchEnabled sets this subnet property to true when it is not set or is set to false
-func (c *StackCollection) EnsureMapPublicIPOnLaunchEnabled() error {
+func (c *StackCollection) EnsureMapPublicIPOnLaunchEnabled(ctx context.Context) error {
 	// First, make sure we enable the options in EC2. This is to make sure the settings are applied even
 	// if the stacks in Cloudformation have the setting enabled (since a stack update would produce "nothing to change"
 	// and therefore the setting would not be updated)
@@ -129,7 +130,7 @@ func (c *StackCollection) EnsureMapPublicIPOnLaunchEnabled() error {
 		}
 	}
 	description := fmt.Sprintf("update public subnets %q with property MapPublicIpOnLaunch enabled", publicSubnetsNames)
-	if err := c.UpdateStack(UpdateStackOptions{
+	if err := c.UpdateStack(ctx, UpdateStackOptions{
 		StackName:     stackName,
 		ChangeSetName: c.MakeChangeSetName("update-subnets"),
 		Description:   description,
@@ -19,52 +19,71 @@ const (
 )
 
 // NewTasksToCreateClusterWithNodeGroups defines all tasks required to create a cluster along
-// with some nodegroups; see CreateAllNodeGroups for how onlyNodeGroupSubset works
+// with some nodegroups; the post cluster creation tasks are run once the cluster stack has been created,
+// followed by the pre-nodegroup tasks (i.e. the addons the nodes need to become ready), and the
+// nodegroups are created once the cluster stack and the pre-nodegroup tasks are done, so when there
+// are no pre-nodegroup tasks the nodegroups do not wait for the post cluster creation tasks
 func (c *StackCollection) NewTasksToCreateClusterWithNodeGroups(nodeGroups []*api.NodeGroup,
-	managedNodeGroups []*api.ManagedNodeGroup, supportsManagedNodes bool, postClusterCreationTasks ...tasks.Task) *tasks.TaskTree {
-
-	taskTree := tasks.TaskTree{Parallel: false}
-
-	taskTree.Append(
-		&createClusterTask{
-			info:                 fmt.Sprintf("create cluster control plane %q", c.spec.Metadata.Name),
-			stackCollection:      c,
-			supportsManagedNodes: supportsManagedNodes,
-		},
-	)
-
-	appendNodeGroupTasksTo := func(taskTree *tasks.TaskTree) {
-		vpcImporter := vpc.NewStackConfigImporter(c.MakeClusterStackName())
-		nodeGroupTasks := c.NewUnmanagedNodeGroupTask(nodeGroups, false, vpcImporter)
-		managedNodeGroupTasks := c.NewManagedNodeGroupTask(managedNodeGroups, false, vpcImporter)
-		if managedNodeGroupTasks.Len() > 0 {
-			nodeGroupTasks.Append(managedNodeGroupTasks.Tasks...)
-		}
+	managedNodeGroups []*api.ManagedNodeGroup, supportsManagedNodes bool, preNodeGroupTasks []tasks.Task, postClusterCreationTasks ...tasks.Task) (*tasks.TaskTree, error) {
 
-		if nodeGroupTasks.Len() > 0 {
-			nodeGroupTasks.IsSubTask = true
-			taskTree.Append(nodeGroupTasks)
-		}
+	clusterTask := &createClusterTask{
+		info:                 fmt.Sprintf("create cluster control plane %q", c.spec.Metadata.Name),
+		stackCollection:      c,
+		supportsManagedNodes: supportsManagedNodes,
 	}
 
+	vpcImporter := vpc.NewStackConfigImporter(c.MakeClusterStackName())
+	nodeGroupTasks := c.NewUnmanagedNodeGroupTask(nodeGroups, false, vpcImporter)
+	managedNodeGroupTasks := c.NewManagedNodeGroupTask(managedNodeGroups, false, vpcImporter)
+
+	return c.newClusterCreationTaskTree(clusterTask, postClusterCreationTasks, preNodeGroupTasks, append(nodeGroupTasks.Tasks, managedNodeGroupTasks.Tasks...))
+}
+
+// newClusterCreationTaskTree returns a tree running the post cluster creation tasks, in order, once
+// clusterTask has completed, then the pre-nodegroup tasks, in order; every nodegroup task runs once
+// clusterTask and the pre-nodegroup tasks have completed
+func (c *StackCollection) newClusterCreationTaskTree(clusterTask tasks.Task, postClusterCreationTasks, preNodeGroupTasks, nodeGroupTasks []tasks.Task) (*tasks.TaskTree, error) {
+	taskTree := &tasks.TaskTree{Parallel: true, MaxParallel: c.maxParallelStacks}
+	taskTree.Append(clusterTask)
+
+	lastPostClusterCreationTask := clusterTask
 	if len(postClusterCreationTasks) > 0 {
-		postClusterCreationTaskTree := tasks.TaskTree{
+		postClusterCreationTaskTree := &tasks.TaskTree{
 			Parallel:  false,
 			IsSubTask: true,
 		}
 		postClusterCreationTaskTree.Append(postClusterCreationTasks...)
-		appendNodeGroupTasksTo(&postClusterCreationTaskTree)
-		taskTree.Append(&postClusterCreationTaskTree)
-	} else {
-		appendNodeGroupTasksTo(&taskTree)
+		if err := taskTree.AppendWithDependencies(postClusterCreationTaskTree, clusterTask); err != nil {
+			return nil, err
+		}
+		lastPostClusterCreationTask = postClusterCreationTaskTree
+	}
+
+	nodeGroupDependencies := []tasks.Task{clusterTask}
+	if len(preNodeGroupTasks) > 0 {
+		preNodeGroupTaskTree := &tasks.TaskTree{
+			Parallel:  false,
+			IsSubTask: true,
+		}
+		preNodeGroupTaskTree.Append(preNodeGroupTasks...)
+		if err := taskTree.AppendWithDependencies(preNodeGroupTaskTree, lastPostClusterCreationTask); err != nil {
+			return nil, err
+		}
+		nodeGroupDependencies = append(nodeGroupDependencies, preNodeGroupTaskTree)
+	}
+
+	for _, task := range nodeGroupTasks {
+		if err := taskTree.AppendWithDependencies(task, nodeGroupDependencies...); err != nil {
+			return nil, err
+		}
 	}
 
-	return &taskTree
+	return taskTree, nil
 }
 
 // NewUnmanagedNodeGroupTask defines tasks required to create all of the nodegroups
 func (c *StackCollection) NewUnmanagedNodeGroupTask(nodeGroups []*api.NodeGroup, forceAddCNIPolicy bool, vpcImporter vpc.Importer) *tasks.TaskTree {
-	taskTree := &tasks.TaskTree{Parallel: true}
+	taskTree := &tasks.TaskTree{Parallel: true, MaxParallel: c.maxParallelStacks}
 
 	for _, ng := range nodeGroups {
 		taskTree.Append(&nodeGroupTask{
@@ -82,7 +101,7 @@ func (c *StackCollection) NewUnmanagedNodeGroupTask(nodeGroups []*api.NodeGroup,
 
 // NewManagedNodeGroupTask defines tasks required to create managed nodegroups
 func (c *StackCollection) NewManagedNodeGroupTask(nodeGroups []*api.ManagedNodeGroup, forceAddCNIPolicy bool, vpcImporter vpc.Importer) *tasks.TaskTree {
-	taskTree := &tasks.TaskTree{Parallel: true}
+	taskTree := &tasks.TaskTree{Parallel: true, MaxParallel: c.maxParallelStacks}
 	for _, ng := range nodeGroups {
 		taskTree.Append(&managedNodeGroupTask{
 			stackCollection:   c,
@@ -106,7 +125,7 @@ func (c *StackCollection) NewClusterCompatTask() tasks.Task {
 
 // NewTasksToCreateIAMServiceAccounts defines tasks required to create all of the IAM ServiceAccounts
 func (c *StackCollection) NewTasksToCreateIAMServiceAccounts(serviceAccounts []*api.ClusterIAMServiceAccount, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter) *tasks.TaskTree {
-	taskTree := &tasks.TaskTree{Parallel: true}
+	taskTree := &tasks.TaskTree{Parallel: true, MaxParallel: c.maxParallelStacks}
 
 	for i := range serviceAccounts {
 		sa := serviceAccounts[i]
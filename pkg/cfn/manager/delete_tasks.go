@@ -21,18 +21,37 @@ import (
 // think Jake is deleting this soon
 func deleteAll(_ string) bool { return true }
 
-// NewTasksToDeleteClusterWithNodeGroups defines tasks required to delete the given cluster along with all of its resources
-func (c *StackCollection) NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider bool, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter, wait bool, cleanup func(chan error, string) error) (*tasks.TaskTree, error) {
-	taskTree := &tasks.TaskTree{Parallel: false}
+// NewTasksToDeleteClusterWithNodeGroups defines tasks required to delete the given cluster along with all of its resources;
+// iamserviceaccounts, the OIDC provider and addon IAM roles are deleted once the nodegroups have been deleted, and the
+// cluster stack once they have all been deleted; stacks with termination protection enabled are only deleted when
+// disableProtection is set
+func (c *StackCollection) NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider bool, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter, wait, disableProtection bool, cleanup func(chan error, string) error) (*tasks.TaskTree, error) {
+	taskTree := &tasks.TaskTree{Parallel: true, MaxParallel: c.maxParallelStacks}
+
+	clusterStack, err := c.DescribeClusterStack()
+	if err != nil {
+		return nil, err
+	}
+	if clusterStack == nil {
+		return nil, &StackNotFoundErr{ClusterName: c.spec.Metadata.Name}
+	}
+	if !disableProtection {
+		if err := CheckTerminationProtection(clusterStack); err != nil {
+			return nil, err
+		}
+	}
 
-	nodeGroupTasks, err := c.NewTasksToDeleteNodeGroups(deleteAll, true, cleanup)
+	nodeGroupTasks, err := c.NewTasksToDeleteNodeGroups(deleteAll, true, disableProtection, cleanup)
 
 	if err != nil {
 		return nil, err
 	}
+	// the roles of iamserviceaccounts and addons are used by pods running on the nodes
+	var afterNodeGroups []tasks.Task
 	if nodeGroupTasks.Len() > 0 {
 		nodeGroupTasks.IsSubTask = true
 		taskTree.Append(nodeGroupTasks)
+		afterNodeGroups = append(afterNodeGroups, nodeGroupTasks)
 	}
 
 	if deleteOIDCProvider {
@@ -43,7 +62,9 @@ func (c *StackCollection) NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvid
 
 		if serviceAccountAndOIDCTasks.Len() > 0 {
 			serviceAccountAndOIDCTasks.IsSubTask = true
-			taskTree.Append(serviceAccountAndOIDCTasks)
+			if err := taskTree.AppendWithDependencies(serviceAccountAndOIDCTasks, afterNodeGroups...); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -54,70 +75,55 @@ func (c *StackCollection) NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvid
 
 	if deleteAddonIAMtasks.Len() > 0 {
 		deleteAddonIAMtasks.IsSubTask = true
-		taskTree.Append(deleteAddonIAMtasks)
+		if err := taskTree.AppendWithDependencies(deleteAddonIAMtasks, afterNodeGroups...); err != nil {
+			return nil, err
+		}
 	}
 
-	clusterStack, err := c.DescribeClusterStack()
-	if err != nil {
+	deleteClusterTask := c.newDeleteStackTask(fmt.Sprintf("delete cluster control plane %q", c.spec.Metadata.Name), clusterStack, wait)
+	if err := taskTree.AppendWithDependencies(deleteClusterTask, taskTree.Tasks...); err != nil {
 		return nil, err
 	}
-	if clusterStack == nil {
-		return nil, &StackNotFoundErr{ClusterName: c.spec.Metadata.Name}
-	}
-
-	info := fmt.Sprintf("delete cluster control plane %q", c.spec.Metadata.Name)
-	if wait {
-		taskTree.Append(&taskWithStackSpec{
-			info:  info,
-			stack: clusterStack,
-			call:  c.DeleteStackBySpecSync,
-		})
-	} else {
-		taskTree.Append(&asyncTaskWithStackSpec{
-			info:  info,
-			stack: clusterStack,
-			call:  c.DeleteStackBySpec,
-		})
-	}
 
 	return taskTree, nil
 }
 
-// NewTasksToDeleteNodeGroups defines tasks required to delete all of the nodegroups
-func (c *StackCollection) NewTasksToDeleteNodeGroups(shouldDelete func(string) bool, wait bool, cleanup func(chan error, string) error) (*tasks.TaskTree, error) {
+// NewTasksToDeleteNodeGroups defines tasks required to delete all of the nodegroups;
+// stacks with termination protection enabled are only deleted when disableProtection is set
+func (c *StackCollection) NewTasksToDeleteNodeGroups(shouldDelete func(string) bool, wait, disableProtection bool, cleanup func(chan error, string) error) (*tasks.TaskTree, error) {
 	nodeGroupStacks, err := c.DescribeNodeGroupStacks()
 	if err != nil {
 		return nil, err
 	}
 
-	taskTree := &tasks.TaskTree{Parallel: true}
-
+	var stacksToDelete []*Stack
 	for _, s := range nodeGroupStacks {
-		name := c.GetNodeGroupName(s)
-
-		if !shouldDelete(name) {
-			continue
+		if shouldDelete(c.GetNodeGroupName(s)) {
+			stacksToDelete = append(stacksToDelete, s)
+		}
+	}
+	if !disableProtection {
+		if err := CheckTerminationProtection(stacksToDelete...); err != nil {
+			return nil, err
 		}
+	}
+
+	taskTree := &tasks.TaskTree{Parallel: true, MaxParallel: c.maxParallelStacks}
+
+	for _, s := range stacksToDelete {
+		name := c.GetNodeGroupName(s)
 
+		var dependsOn []tasks.Task
 		if *s.StackStatus == cloudformation.StackStatusDeleteFailed && cleanup != nil {
-			taskTree.Append(&tasks.TaskWithNameParam{
+			cleanupTask := &tasks.TaskWithNameParam{
 				Info: fmt.Sprintf("cleanup for nodegroup %q", name),
 				Call: cleanup,
-			})
+			}
+			taskTree.Append(cleanupTask)
+			dependsOn = append(dependsOn, cleanupTask)
 		}
-		info := fmt.Sprintf("delete nodegroup %q", name)
-		if wait {
-			taskTree.Append(&taskWithStackSpec{
-				info:  info,
-				stack: s,
-				call:  c.DeleteStackBySpecSync,
-			})
-		} else {
-			taskTree.Append(&asyncTaskWithStackSpec{
-				info:  info,
-				stack: s,
-				call:  c.DeleteStackBySpec,
-			})
+		if err := taskTree.AppendWithDependencies(c.newDeleteStackTask(fmt.Sprintf("delete nodegroup %q", name), s, wait), dependsOn...); err != nil {
+			return nil, err
 		}
 	}
 
@@ -208,7 +214,9 @@ func (c *StackCollection) NewTasksToDeleteOIDCProviderWithIAMServiceAccounts(oid
 		return nil, err
 	}
 
-	if providerExists {
+	if providerExists && oidc.IsRetained() {
+		logger.Info("IAM OIDC provider %q is retained", oidc.ProviderARN)
+	} else if providerExists {
 		taskTree.Append(&asyncTaskWithoutParams{
 			info: "delete IAM OIDC provider",
 			call: oidc.DeleteProvider,
@@ -240,7 +248,7 @@ func (c *StackCollection) NewTasksToDeleteIAMServiceAccounts(serviceAccounts []s
 	}
 
 	stacksMap := stacksToServiceAccountMap(serviceAccountStacks)
-	taskTree := &tasks.TaskTree{Parallel: true}
+	taskTree := &tasks.TaskTree{Parallel: true, MaxParallel: c.maxParallelStacks}
 
 	for _, serviceAccount := range serviceAccounts {
 		saTasks := &tasks.TaskTree{
@@ -296,7 +304,7 @@ func (c *StackCollection) NewTaskToDeleteAddonIAM(wait bool) (*tasks.TaskTree, e
 	if err != nil {
 		return nil, err
 	}
-	taskTree := &tasks.TaskTree{Parallel: true}
+	taskTree := &tasks.TaskTree{Parallel: true, MaxParallel: c.maxParallelStacks}
 	for _, s := range stacks {
 		info := fmt.Sprintf("delete addon IAM %q", *s.StackName)
 
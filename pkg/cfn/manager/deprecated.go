@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -69,7 +70,7 @@ func (c *StackCollection) DeleteTasksForDeprecatedStacks() (*tasks.TaskTree, err
 				},
 			)
 
-			return waiters.Wait(c.spec.Metadata.Name, msg, acceptors, newRequest, c.waitTimeout, nil)
+			return waiters.Wait(context.TODO(), c.spec.Metadata.Name, msg, acceptors, newRequest, c.waitTimeout, nil)
 		},
 	}
 
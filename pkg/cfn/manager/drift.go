@@ -0,0 +1,137 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/utils/waiters"
+)
+
+const driftDetectionStatus = "DetectionStatus"
+
+// StackDrift holds the result of drift detection for a single stack
+type StackDrift struct {
+	StackName string `json:"stackName"`
+	// Status is one of DRIFTED, IN_SYNC, UNKNOWN or NOT_CHECKED
+	Status string `json:"status"`
+	// Reason explains why drift detection failed for some resources, if it did
+	Reason    string          `json:"reason,omitempty"`
+	Resources []ResourceDrift `json:"resources,omitempty"`
+}
+
+// ResourceDrift describes a resource that was modified or deleted outside of CloudFormation
+type ResourceDrift struct {
+	LogicalID    string `json:"logicalID"`
+	PhysicalID   string `json:"physicalID"`
+	ResourceType string `json:"resourceType"`
+	// Status is either MODIFIED or DELETED
+	Status      string               `json:"status"`
+	Differences []PropertyDifference `json:"differences,omitempty"`
+}
+
+// PropertyDifference is a single property of a resource that differs from the template
+type PropertyDifference struct {
+	PropertyPath   string `json:"propertyPath"`
+	DifferenceType string `json:"differenceType"`
+	ExpectedValue  string `json:"expectedValue"`
+	ActualValue    string `json:"actualValue"`
+}
+
+// IsDrifted returns true when resources of the stack were changed outside of CloudFormation
+func (d *StackDrift) IsDrifted() bool {
+	return d.Status == cfn.StackDriftStatusDrifted
+}
+
+// DetectStackDrift runs CloudFormation drift detection on a stack, waits for it
+// to complete and returns the resources that have drifted
+func (c *StackCollection) DetectStackDrift(ctx context.Context, s *Stack) (*StackDrift, error) {
+	output, err := c.cloudformationAPI.DetectStackDrift(&cfn.DetectStackDriftInput{
+		StackName: s.StackName,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "starting drift detection for stack %q", *s.StackName)
+	}
+
+	if err := c.waitUntilDriftDetectionIsComplete(ctx, s, output.StackDriftDetectionId); err != nil {
+		return nil, err
+	}
+
+	status, err := c.cloudformationAPI.DescribeStackDriftDetectionStatus(&cfn.DescribeStackDriftDetectionStatusInput{
+		StackDriftDetectionId: output.StackDriftDetectionId,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing drift detection status for stack %q", *s.StackName)
+	}
+
+	drift := &StackDrift{
+		StackName: *s.StackName,
+		Status:    aws.StringValue(status.StackDriftStatus),
+	}
+	if aws.StringValue(status.DetectionStatus) == cfn.StackDriftDetectionStatusDetectionFailed {
+		drift.Reason = aws.StringValue(status.DetectionStatusReason)
+		logger.Warning("drift detection for stack %q did not complete for all resources: %s", *s.StackName, drift.Reason)
+	}
+
+	input := &cfn.DescribeStackResourceDriftsInput{
+		StackName: s.StackName,
+		StackResourceDriftStatusFilters: aws.StringSlice([]string{
+			cfn.StackResourceDriftStatusModified,
+			cfn.StackResourceDriftStatusDeleted,
+		}),
+	}
+	pager := func(p *cfn.DescribeStackResourceDriftsOutput, _ bool) bool {
+		for _, r := range p.StackResourceDrifts {
+			resource := ResourceDrift{
+				LogicalID:    aws.StringValue(r.LogicalResourceId),
+				PhysicalID:   aws.StringValue(r.PhysicalResourceId),
+				ResourceType: aws.StringValue(r.ResourceType),
+				Status:       aws.StringValue(r.StackResourceDriftStatus),
+			}
+			for _, d := range r.PropertyDifferences {
+				resource.Differences = append(resource.Differences, PropertyDifference{
+					PropertyPath:   aws.StringValue(d.PropertyPath),
+					DifferenceType: aws.StringValue(d.DifferenceType),
+					ExpectedValue:  aws.StringValue(d.ExpectedValue),
+					ActualValue:    aws.StringValue(d.ActualValue),
+				})
+			}
+			drift.Resources = append(drift.Resources, resource)
+		}
+		return true
+	}
+	if err := c.cloudformationAPI.DescribeStackResourceDriftsPages(input, pager); err != nil {
+		return nil, errors.Wrapf(err, "describing drifted resources of stack %q", *s.StackName)
+	}
+
+	return drift, nil
+}
+
+func (c *StackCollection) waitUntilDriftDetectionIsComplete(ctx context.Context, s *Stack, detectionID *string) error {
+	msg := fmt.Sprintf("waiting for drift detection of CloudFormation stack %q", *s.StackName)
+
+	newRequest := func() *request.Request {
+		req, _ := c.cloudformationAPI.DescribeStackDriftDetectionStatusRequest(&cfn.DescribeStackDriftDetectionStatusInput{
+			StackDriftDetectionId: detectionID,
+		})
+		return req
+	}
+
+	// detection fails when some resources do not support drift detection, the results
+	// for the remaining resources are still available, so this is not treated as an error
+	acceptors := waiters.MakeAcceptors(driftDetectionStatus, cfn.StackDriftDetectionStatusDetectionComplete, nil,
+		request.WaiterAcceptor{
+			State:    request.SuccessWaiterState,
+			Matcher:  request.PathWaiterMatch,
+			Argument: driftDetectionStatus,
+			Expected: cfn.StackDriftDetectionStatusDetectionFailed,
+		},
+	)
+
+	return waiters.Wait(ctx, *s.StackName, msg, acceptors, newRequest, c.waitTimeout, nil)
+}
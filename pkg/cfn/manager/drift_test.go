@@ -0,0 +1,147 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Stack drift detection", func() {
+	const (
+		stackName   = "eksctl-test-cluster"
+		detectionID = "detection-1"
+	)
+
+	var (
+		p  *mockprovider.MockProvider
+		sc *StackCollection
+	)
+
+	// mockDetection mocks a drift detection that completes with the given status, the waiter
+	// polls the same status as the one that is described once the detection is complete
+	mockDetection := func(status *cfn.DescribeStackDriftDetectionStatusOutput, drifts []*cfn.StackResourceDrift) {
+		p.MockCloudFormation().On("DetectStackDrift", mock.MatchedBy(func(input *cfn.DetectStackDriftInput) bool {
+			return *input.StackName == stackName
+		})).Return(&cfn.DetectStackDriftOutput{StackDriftDetectionId: aws.String(detectionID)}, nil)
+
+		req := awstesting.NewClient(nil).NewRequest(&request.Operation{Name: "Operation"}, nil, status)
+		p.MockCloudFormation().On("DescribeStackDriftDetectionStatusRequest", mock.MatchedBy(func(input *cfn.DescribeStackDriftDetectionStatusInput) bool {
+			return *input.StackDriftDetectionId == detectionID
+		})).Return(req, status)
+		p.MockCloudFormation().On("DescribeStackDriftDetectionStatus", mock.MatchedBy(func(input *cfn.DescribeStackDriftDetectionStatusInput) bool {
+			return *input.StackDriftDetectionId == detectionID
+		})).Return(status, nil)
+
+		p.MockCloudFormation().On("DescribeStackResourceDriftsPages", mock.MatchedBy(func(input *cfn.DescribeStackResourceDriftsInput) bool {
+			return *input.StackName == stackName
+		}), mock.Anything).Run(func(args mock.Arguments) {
+			pager := args.Get(1).(func(*cfn.DescribeStackResourceDriftsOutput, bool) bool)
+			pager(&cfn.DescribeStackResourceDriftsOutput{StackResourceDrifts: drifts}, true)
+		}).Return(nil)
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		sc = NewStackCollection(p, cfg)
+	})
+
+	AfterEach(func() {
+		By("waiting for the detection to complete")
+		p.MockCloudFormation().AssertCalled(GinkgoT(), "DescribeStackDriftDetectionStatusRequest", mock.Anything)
+	})
+
+	It("returns the resources of a drifted stack", func() {
+		mockDetection(&cfn.DescribeStackDriftDetectionStatusOutput{
+			DetectionStatus:  aws.String(cfn.StackDriftDetectionStatusDetectionComplete),
+			StackDriftStatus: aws.String(cfn.StackDriftStatusDrifted),
+		}, []*cfn.StackResourceDrift{
+			{
+				LogicalResourceId:        aws.String("ControlPlaneSecurityGroup"),
+				PhysicalResourceId:       aws.String("sg-1"),
+				ResourceType:             aws.String("AWS::EC2::SecurityGroup"),
+				StackResourceDriftStatus: aws.String(cfn.StackResourceDriftStatusModified),
+				PropertyDifferences: []*cfn.PropertyDifference{{
+					PropertyPath:   aws.String("/SecurityGroupIngress/0/CidrIp"),
+					DifferenceType: aws.String(cfn.DifferenceTypeNotEqual),
+					ExpectedValue:  aws.String("10.0.0.0/16"),
+					ActualValue:    aws.String("0.0.0.0/0"),
+				}},
+			},
+			{
+				LogicalResourceId:        aws.String("PolicyCloudWatchMetrics"),
+				PhysicalResourceId:       aws.String("policy-1"),
+				ResourceType:             aws.String("AWS::IAM::Policy"),
+				StackResourceDriftStatus: aws.String(cfn.StackResourceDriftStatusDeleted),
+			},
+		})
+
+		drift, err := sc.DetectStackDrift(context.Background(), &Stack{StackName: aws.String(stackName)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift.IsDrifted()).To(BeTrue())
+		Expect(drift.Reason).To(BeEmpty())
+		Expect(drift.Resources).To(Equal([]ResourceDrift{
+			{
+				LogicalID:    "ControlPlaneSecurityGroup",
+				PhysicalID:   "sg-1",
+				ResourceType: "AWS::EC2::SecurityGroup",
+				Status:       cfn.StackResourceDriftStatusModified,
+				Differences: []PropertyDifference{{
+					PropertyPath:   "/SecurityGroupIngress/0/CidrIp",
+					DifferenceType: cfn.DifferenceTypeNotEqual,
+					ExpectedValue:  "10.0.0.0/16",
+					ActualValue:    "0.0.0.0/0",
+				}},
+			},
+			{
+				LogicalID:    "PolicyCloudWatchMetrics",
+				PhysicalID:   "policy-1",
+				ResourceType: "AWS::IAM::Policy",
+				Status:       cfn.StackResourceDriftStatusDeleted,
+			},
+		}))
+	})
+
+	It("returns no resources for a stack that is in sync", func() {
+		mockDetection(&cfn.DescribeStackDriftDetectionStatusOutput{
+			DetectionStatus:  aws.String(cfn.StackDriftDetectionStatusDetectionComplete),
+			StackDriftStatus: aws.String(cfn.StackDriftStatusInSync),
+		}, nil)
+
+		drift, err := sc.DetectStackDrift(context.Background(), &Stack{StackName: aws.String(stackName)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift.Status).To(Equal(cfn.StackDriftStatusInSync))
+		Expect(drift.IsDrifted()).To(BeFalse())
+		Expect(drift.Resources).To(BeEmpty())
+	})
+
+	It("returns the results of the other resources and the reason when detection fails for some resources", func() {
+		mockDetection(&cfn.DescribeStackDriftDetectionStatusOutput{
+			DetectionStatus:       aws.String(cfn.StackDriftDetectionStatusDetectionFailed),
+			DetectionStatusReason: aws.String("Failed to detect drift on resource [ClusterSharedNodeSecurityGroup]"),
+			StackDriftStatus:      aws.String(cfn.StackDriftStatusDrifted),
+		}, []*cfn.StackResourceDrift{{
+			LogicalResourceId:        aws.String("VPC"),
+			PhysicalResourceId:       aws.String("vpc-1"),
+			ResourceType:             aws.String("AWS::EC2::VPC"),
+			StackResourceDriftStatus: aws.String(cfn.StackResourceDriftStatusModified),
+		}})
+
+		drift, err := sc.DetectStackDrift(context.Background(), &Stack{StackName: aws.String(stackName)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift.IsDrifted()).To(BeTrue())
+		Expect(drift.Reason).To(Equal("Failed to detect drift on resource [ClusterSharedNodeSecurityGroup]"))
+		Expect(drift.Resources).To(HaveLen(1))
+		Expect(drift.Resources[0].LogicalID).To(Equal("VPC"))
+	})
+})
@@ -1,6 +1,9 @@
 package manager
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type StackNotFoundErr struct {
 	ClusterName string
@@ -9,3 +12,12 @@ type StackNotFoundErr struct {
 func (e *StackNotFoundErr) Error() string {
 	return fmt.Sprintf("no eksctl-managed CloudFormation stacks found for %q", e.ClusterName)
 }
+
+// StackProtectedErr is returned when deleting stacks that have termination protection enabled
+type StackProtectedErr struct {
+	StackNames []string
+}
+
+func (e *StackProtectedErr) Error() string {
+	return fmt.Sprintf("termination protection is enabled for stack(s) %s, use --disable-protection to delete them", strings.Join(e.StackNames, ", "))
+}
@@ -0,0 +1,111 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
+	"github.com/kris-nova/logger"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/utils/progress"
+)
+
+// stackEventsClockSkew allows for the clocks of the client and CloudFormation to differ
+// when skipping events that happened before waiting started
+const stackEventsClockSkew = time.Minute
+
+// stackEventStreamer prints the events of a stack as they happen; it is polled by the stack waiter
+// each time it describes the stack, so that streaming does not add its own polling loop. Each line is
+// prefixed with the name of the stack, so that the events of stacks waited for in parallel can be told apart
+type stackEventStreamer struct {
+	cloudformationAPI cloudformationiface.CloudFormationAPI
+	stack             *Stack
+	since             time.Time
+	lastEventID       string
+}
+
+func newStackEventStreamer(cloudformationAPI cloudformationiface.CloudFormationAPI, stack *Stack) *stackEventStreamer {
+	return &stackEventStreamer{
+		cloudformationAPI: cloudformationAPI,
+		stack:             stack,
+		since:             time.Now().Add(-stackEventsClockSkew),
+	}
+}
+
+// poll prints the events that happened since the last poll, or since the current operation started
+func (s *stackEventStreamer) poll() {
+	input := &cfn.DescribeStackEventsInput{
+		StackName: s.stack.StackName,
+	}
+	if api.IsSetAndNonEmptyString(s.stack.StackId) {
+		input.StackName = s.stack.StackId
+	}
+
+	// events are returned newest first
+	var events []*cfn.StackEvent
+	pager := func(p *cfn.DescribeStackEventsOutput, _ bool) bool {
+		for _, e := range p.StackEvents {
+			if s.lastEventID != "" {
+				if *e.EventId == s.lastEventID {
+					return false
+				}
+			} else if e.Timestamp != nil && e.Timestamp.Before(s.since) {
+				return false
+			}
+			events = append(events, e)
+			if s.lastEventID == "" && s.isOperationStart(e) {
+				return false
+			}
+		}
+		return true
+	}
+	if err := s.cloudformationAPI.DescribeStackEventsPages(input, pager); err != nil {
+		logger.Debug("describing events of stack %q: %v", *s.stack.StackName, err)
+		return
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	s.lastEventID = *events[0].EventId
+	for i := len(events) - 1; i >= 0; i-- {
+		s.print(events[i])
+	}
+}
+
+// isOperationStart returns true for the event of the stack itself that starts a create, update or delete
+func (s *stackEventStreamer) isOperationStart(e *cfn.StackEvent) bool {
+	if aws.StringValue(e.ResourceType) != "AWS::CloudFormation::Stack" || aws.StringValue(e.LogicalResourceId) != *s.stack.StackName {
+		return false
+	}
+	switch aws.StringValue(e.ResourceStatus) {
+	case cfn.ResourceStatusCreateInProgress, cfn.ResourceStatusUpdateInProgress, cfn.ResourceStatusDeleteInProgress:
+		return true
+	}
+	return false
+}
+
+func (s *stackEventStreamer) print(e *cfn.StackEvent) {
+	progress.Emit(progress.Event{
+		Type:         progress.StackStatus,
+		Stack:        *s.stack.StackName,
+		Resource:     aws.StringValue(e.LogicalResourceId),
+		ResourceType: aws.StringValue(e.ResourceType),
+		Status:       aws.StringValue(e.ResourceStatus),
+		Reason:       aws.StringValue(e.ResourceStatusReason),
+	})
+
+	msg := fmt.Sprintf("[%s] %s/%s: %s", *s.stack.StackName, aws.StringValue(e.ResourceType), aws.StringValue(e.LogicalResourceId), aws.StringValue(e.ResourceStatus))
+	if e.ResourceStatusReason != nil {
+		msg = fmt.Sprintf("%s – %s", msg, *e.ResourceStatusReason)
+	}
+	if strings.HasSuffix(aws.StringValue(e.ResourceStatus), "_FAILED") {
+		logger.Warning(msg)
+		return
+	}
+	logger.Info(msg)
+}
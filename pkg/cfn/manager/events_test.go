@@ -0,0 +1,139 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/kris-nova/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("stackEventStreamer", func() {
+	var (
+		p        *mockprovider.MockProvider
+		streamer *stackEventStreamer
+		output   *bytes.Buffer
+		events   []*cfn.StackEvent
+	)
+
+	newEvent := func(id, logicalID, resourceType, status string) *cfn.StackEvent {
+		return &cfn.StackEvent{
+			EventId:           aws.String(id),
+			LogicalResourceId: aws.String(logicalID),
+			ResourceType:      aws.String(resourceType),
+			ResourceStatus:    aws.String(status),
+			Timestamp:         aws.Time(time.Now()),
+		}
+	}
+
+	BeforeEach(func() {
+		output = &bytes.Buffer{}
+		logger.Writer = output
+
+		p = mockprovider.NewMockProvider()
+		p.MockCloudFormation().On("DescribeStackEventsPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			pager := args.Get(1).(func(*cfn.DescribeStackEventsOutput, bool) bool)
+			pager(&cfn.DescribeStackEventsOutput{StackEvents: events}, true)
+		}).Return(nil)
+
+		// events of a previous update of the stack, newest first
+		events = []*cfn.StackEvent{
+			newEvent("3", "eksctl-test-cluster", "AWS::CloudFormation::Stack", cfn.ResourceStatusUpdateInProgress),
+			newEvent("2", "eksctl-test-cluster", "AWS::CloudFormation::Stack", cfn.ResourceStatusCreateComplete),
+			newEvent("1", "VPC", "AWS::EC2::VPC", cfn.ResourceStatusCreateComplete),
+		}
+		streamer = newStackEventStreamer(p.MockCloudFormation(), &Stack{StackName: aws.String("eksctl-test-cluster")})
+	})
+
+	AfterEach(func() {
+		logger.Writer = os.Stdout
+	})
+
+	It("prints the events of the current operation, then only new events", func() {
+		streamer.poll()
+		Expect(output.String()).To(ContainSubstring("[eksctl-test-cluster] AWS::CloudFormation::Stack/eksctl-test-cluster: UPDATE_IN_PROGRESS"))
+		Expect(output.String()).NotTo(ContainSubstring("AWS::EC2::VPC/VPC"))
+
+		output.Reset()
+		failed := newEvent("5", "NATGateway", "AWS::EC2::NatGateway", cfn.ResourceStatusUpdateFailed)
+		failed.ResourceStatusReason = aws.String("subnet not found")
+		events = append([]*cfn.StackEvent{
+			failed,
+			newEvent("4", "NATGateway", "AWS::EC2::NatGateway", cfn.ResourceStatusUpdateInProgress),
+		}, events...)
+
+		streamer.poll()
+		Expect(output.String()).NotTo(ContainSubstring("AWS::CloudFormation::Stack"))
+		Expect(output.String()).To(MatchRegexp(`(?s)AWS::EC2::NatGateway/NATGateway: UPDATE_IN_PROGRESS.*AWS::EC2::NatGateway/NATGateway: UPDATE_FAILED – subnet not found`))
+	})
+
+	It("skips events from before waiting started", func() {
+		for _, e := range events {
+			e.Timestamp = aws.Time(time.Now().Add(-time.Hour))
+		}
+		streamer.poll()
+		Expect(output.String()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("waiting for a stack with stack events streamed", func() {
+	var (
+		p      *mockprovider.MockProvider
+		sc     *StackCollection
+		output *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		output = &bytes.Buffer{}
+		logger.Writer = output
+
+		p = mockprovider.NewMockProvider()
+		failedStack := &cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{{
+			StackName:   aws.String("eksctl-test-cluster"),
+			StackStatus: aws.String(cfn.StackStatusRollbackComplete),
+		}}}
+		req := awstesting.NewClient(nil).NewRequest(&request.Operation{Name: "Operation"}, nil, failedStack)
+		p.MockCloudFormation().On("DescribeStacksRequest", mock.Anything).Return(req, failedStack)
+		p.MockCloudFormation().On("DescribeStacks", mock.Anything).Return(failedStack, nil)
+		p.MockCloudFormation().On("DescribeStackEventsPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			failed := &cfn.StackEvent{
+				EventId:              aws.String("1"),
+				LogicalResourceId:    aws.String("VPC"),
+				ResourceType:         aws.String("AWS::EC2::VPC"),
+				ResourceStatus:       aws.String(cfn.ResourceStatusCreateFailed),
+				ResourceStatusReason: aws.String("limit exceeded"),
+				Timestamp:            aws.Time(time.Now()),
+			}
+			pager := args.Get(1).(func(*cfn.DescribeStackEventsOutput, bool) bool)
+			pager(&cfn.DescribeStackEventsOutput{StackEvents: []*cfn.StackEvent{failed}}, true)
+		}).Return(nil)
+
+		sc = NewStackCollection(p, api.NewClusterConfig())
+		sc.streamEvents = true
+	})
+
+	AfterEach(func() {
+		logger.Writer = os.Stdout
+	})
+
+	It("prints the events that led to the failure once", func() {
+		err := sc.DoWaitUntilStackIsCreated(context.Background(), &Stack{StackName: aws.String("eksctl-test-cluster")})
+		Expect(err).To(HaveOccurred())
+
+		Expect(strings.Count(output.String(), "AWS::EC2::VPC/VPC: CREATE_FAILED")).To(Equal(1))
+		Expect(output.String()).NotTo(ContainSubstring("fetching stack events in attempt to troubleshoot"))
+		p.MockCloudFormation().AssertNumberOfCalls(GinkgoT(), "DescribeStackEventsPages", 1)
+	})
+})
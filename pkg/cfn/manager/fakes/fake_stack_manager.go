@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/aws/aws-sdk-go/service/cloudformation"
@@ -17,11 +18,12 @@ import (
 )
 
 type FakeStackManager struct {
-	AppendNewClusterStackResourceStub        func(bool, bool) (bool, error)
+	AppendNewClusterStackResourceStub        func(context.Context, bool, bool) (bool, error)
 	appendNewClusterStackResourceMutex       sync.RWMutex
 	appendNewClusterStackResourceArgsForCall []struct {
-		arg1 bool
+		arg1 context.Context
 		arg2 bool
+		arg3 bool
 	}
 	appendNewClusterStackResourceReturns struct {
 		result1 bool
@@ -31,14 +33,15 @@ type FakeStackManager struct {
 		result1 bool
 		result2 error
 	}
-	CreateStackStub        func(string, builder.ResourceSet, map[string]string, map[string]string, chan error) error
+	CreateStackStub        func(context.Context, string, builder.ResourceSet, map[string]string, map[string]string, chan error) error
 	createStackMutex       sync.RWMutex
 	createStackArgsForCall []struct {
-		arg1 string
-		arg2 builder.ResourceSet
-		arg3 map[string]string
+		arg1 context.Context
+		arg2 string
+		arg3 builder.ResourceSet
 		arg4 map[string]string
-		arg5 chan error
+		arg5 map[string]string
+		arg6 chan error
 	}
 	createStackReturns struct {
 		result1 error
@@ -46,6 +49,29 @@ type FakeStackManager struct {
 	createStackReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CreatedStacksStub        func() []*cloudformation.Stack
+	createdStacksMutex       sync.RWMutex
+	createdStacksArgsForCall []struct {
+	}
+	createdStacksReturns struct {
+		result1 []*cloudformation.Stack
+	}
+	createdStacksReturnsOnCall map[int]struct {
+		result1 []*cloudformation.Stack
+	}
+	DeleteProtectedStackBySpecSyncStub        func(context.Context, *cloudformation.Stack, chan error) error
+	deleteProtectedStackBySpecSyncMutex       sync.RWMutex
+	deleteProtectedStackBySpecSyncArgsForCall []struct {
+		arg1 context.Context
+		arg2 *cloudformation.Stack
+		arg3 chan error
+	}
+	deleteProtectedStackBySpecSyncReturns struct {
+		result1 error
+	}
+	deleteProtectedStackBySpecSyncReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DeleteStackByNameStub        func(string) (*cloudformation.Stack, error)
 	deleteStackByNameMutex       sync.RWMutex
 	deleteStackByNameArgsForCall []struct {
@@ -59,10 +85,11 @@ type FakeStackManager struct {
 		result1 *cloudformation.Stack
 		result2 error
 	}
-	DeleteStackByNameSyncStub        func(string) error
+	DeleteStackByNameSyncStub        func(context.Context, string) error
 	deleteStackByNameSyncMutex       sync.RWMutex
 	deleteStackByNameSyncArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	deleteStackByNameSyncReturns struct {
 		result1 error
@@ -83,11 +110,12 @@ type FakeStackManager struct {
 		result1 *cloudformation.Stack
 		result2 error
 	}
-	DeleteStackBySpecSyncStub        func(*cloudformation.Stack, chan error) error
+	DeleteStackBySpecSyncStub        func(context.Context, *cloudformation.Stack, chan error) error
 	deleteStackBySpecSyncMutex       sync.RWMutex
 	deleteStackBySpecSyncArgsForCall []struct {
-		arg1 *cloudformation.Stack
-		arg2 chan error
+		arg1 context.Context
+		arg2 *cloudformation.Stack
+		arg3 chan error
 	}
 	deleteStackBySpecSyncReturns struct {
 		result1 error
@@ -107,6 +135,17 @@ type FakeStackManager struct {
 		result1 *tasks.TaskTree
 		result2 error
 	}
+	DeleteUploadedTemplatesStub        func([]*cloudformation.Stack) error
+	deleteUploadedTemplatesMutex       sync.RWMutex
+	deleteUploadedTemplatesArgsForCall []struct {
+		arg1 []*cloudformation.Stack
+	}
+	deleteUploadedTemplatesReturns struct {
+		result1 error
+	}
+	deleteUploadedTemplatesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DescribeClusterStackStub        func() (*cloudformation.Stack, error)
 	describeClusterStackMutex       sync.RWMutex
 	describeClusterStackArgsForCall []struct {
@@ -220,6 +259,20 @@ type FakeStackManager struct {
 		result1 []*cloudformation.Stack
 		result2 error
 	}
+	DetectStackDriftStub        func(context.Context, *cloudformation.Stack) (*manager.StackDrift, error)
+	detectStackDriftMutex       sync.RWMutex
+	detectStackDriftArgsForCall []struct {
+		arg1 context.Context
+		arg2 *cloudformation.Stack
+	}
+	detectStackDriftReturns struct {
+		result1 *manager.StackDrift
+		result2 error
+	}
+	detectStackDriftReturnsOnCall map[int]struct {
+		result1 *manager.StackDrift
+		result2 error
+	}
 	DoCreateStackRequestStub        func(*cloudformation.Stack, manager.TemplateData, map[string]string, map[string]string, bool, bool) error
 	doCreateStackRequestMutex       sync.RWMutex
 	doCreateStackRequestArgsForCall []struct {
@@ -236,10 +289,11 @@ type FakeStackManager struct {
 	doCreateStackRequestReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DoWaitUntilStackIsCreatedStub        func(*cloudformation.Stack) error
+	DoWaitUntilStackIsCreatedStub        func(context.Context, *cloudformation.Stack) error
 	doWaitUntilStackIsCreatedMutex       sync.RWMutex
 	doWaitUntilStackIsCreatedArgsForCall []struct {
-		arg1 *cloudformation.Stack
+		arg1 context.Context
+		arg2 *cloudformation.Stack
 	}
 	doWaitUntilStackIsCreatedReturns struct {
 		result1 error
@@ -247,9 +301,10 @@ type FakeStackManager struct {
 	doWaitUntilStackIsCreatedReturnsOnCall map[int]struct {
 		result1 error
 	}
-	EnsureMapPublicIPOnLaunchEnabledStub        func() error
+	EnsureMapPublicIPOnLaunchEnabledStub        func(context.Context) error
 	ensureMapPublicIPOnLaunchEnabledMutex       sync.RWMutex
 	ensureMapPublicIPOnLaunchEnabledArgsForCall []struct {
+		arg1 context.Context
 	}
 	ensureMapPublicIPOnLaunchEnabledReturns struct {
 		result1 error
@@ -257,9 +312,10 @@ type FakeStackManager struct {
 	ensureMapPublicIPOnLaunchEnabledReturnsOnCall map[int]struct {
 		result1 error
 	}
-	FixClusterCompatibilityStub        func() error
+	FixClusterCompatibilityStub        func(context.Context) error
 	fixClusterCompatibilityMutex       sync.RWMutex
 	fixClusterCompatibilityArgsForCall []struct {
+		arg1 context.Context
 	}
 	fixClusterCompatibilityReturns struct {
 		result1 error
@@ -415,6 +471,18 @@ type FakeStackManager struct {
 		result1 bool
 		result2 error
 	}
+	ImportResourcesStub        func(context.Context, manager.ImportResourcesOptions) error
+	importResourcesMutex       sync.RWMutex
+	importResourcesArgsForCall []struct {
+		arg1 context.Context
+		arg2 manager.ImportResourcesOptions
+	}
+	importResourcesReturns struct {
+		result1 error
+	}
+	importResourcesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ListClusterStackNamesStub        func() ([]string, error)
 	listClusterStackNamesMutex       sync.RWMutex
 	listClusterStackNamesArgsForCall []struct {
@@ -451,6 +519,19 @@ type FakeStackManager struct {
 		result1 []manager.NodeGroupStack
 		result2 error
 	}
+	ListStackResourcesStub        func(*cloudformation.Stack) ([]*cloudformation.StackResourceSummary, error)
+	listStackResourcesMutex       sync.RWMutex
+	listStackResourcesArgsForCall []struct {
+		arg1 *cloudformation.Stack
+	}
+	listStackResourcesReturns struct {
+		result1 []*cloudformation.StackResourceSummary
+		result2 error
+	}
+	listStackResourcesReturnsOnCall map[int]struct {
+		result1 []*cloudformation.StackResourceSummary
+		result2 error
+	}
 	ListStacksStub        func(...string) ([]*cloudformation.Stack, error)
 	listStacksMutex       sync.RWMutex
 	listStacksArgsForCall []struct {
@@ -562,19 +643,22 @@ type FakeStackManager struct {
 	newTaskToDeleteUnownedNodeGroupReturnsOnCall map[int]struct {
 		result1 tasks.Task
 	}
-	NewTasksToCreateClusterWithNodeGroupsStub        func([]*v1alpha5.NodeGroup, []*v1alpha5.ManagedNodeGroup, bool, ...tasks.Task) *tasks.TaskTree
+	NewTasksToCreateClusterWithNodeGroupsStub        func([]*v1alpha5.NodeGroup, []*v1alpha5.ManagedNodeGroup, bool, []tasks.Task, ...tasks.Task) (*tasks.TaskTree, error)
 	newTasksToCreateClusterWithNodeGroupsMutex       sync.RWMutex
 	newTasksToCreateClusterWithNodeGroupsArgsForCall []struct {
 		arg1 []*v1alpha5.NodeGroup
 		arg2 []*v1alpha5.ManagedNodeGroup
 		arg3 bool
 		arg4 []tasks.Task
+		arg5 []tasks.Task
 	}
 	newTasksToCreateClusterWithNodeGroupsReturns struct {
 		result1 *tasks.TaskTree
+		result2 error
 	}
 	newTasksToCreateClusterWithNodeGroupsReturnsOnCall map[int]struct {
 		result1 *tasks.TaskTree
+		result2 error
 	}
 	NewTasksToCreateIAMServiceAccountsStub        func([]*v1alpha5.ClusterIAMServiceAccount, *iamoidc.OpenIDConnectManager, kubernetes.ClientSetGetter) *tasks.TaskTree
 	newTasksToCreateIAMServiceAccountsMutex       sync.RWMutex
@@ -589,14 +673,15 @@ type FakeStackManager struct {
 	newTasksToCreateIAMServiceAccountsReturnsOnCall map[int]struct {
 		result1 *tasks.TaskTree
 	}
-	NewTasksToDeleteClusterWithNodeGroupsStub        func(bool, *iamoidc.OpenIDConnectManager, kubernetes.ClientSetGetter, bool, func(chan error, string) error) (*tasks.TaskTree, error)
+	NewTasksToDeleteClusterWithNodeGroupsStub        func(bool, *iamoidc.OpenIDConnectManager, kubernetes.ClientSetGetter, bool, bool, func(chan error, string) error) (*tasks.TaskTree, error)
 	newTasksToDeleteClusterWithNodeGroupsMutex       sync.RWMutex
 	newTasksToDeleteClusterWithNodeGroupsArgsForCall []struct {
 		arg1 bool
 		arg2 *iamoidc.OpenIDConnectManager
 		arg3 kubernetes.ClientSetGetter
 		arg4 bool
-		arg5 func(chan error, string) error
+		arg5 bool
+		arg6 func(chan error, string) error
 	}
 	newTasksToDeleteClusterWithNodeGroupsReturns struct {
 		result1 *tasks.TaskTree
@@ -621,12 +706,13 @@ type FakeStackManager struct {
 		result1 *tasks.TaskTree
 		result2 error
 	}
-	NewTasksToDeleteNodeGroupsStub        func(func(_ string) bool, bool, func(chan error, string) error) (*tasks.TaskTree, error)
+	NewTasksToDeleteNodeGroupsStub        func(func(_ string) bool, bool, bool, func(chan error, string) error) (*tasks.TaskTree, error)
 	newTasksToDeleteNodeGroupsMutex       sync.RWMutex
 	newTasksToDeleteNodeGroupsArgsForCall []struct {
 		arg1 func(_ string) bool
 		arg2 bool
-		arg3 func(chan error, string) error
+		arg3 bool
+		arg4 func(chan error, string) error
 	}
 	newTasksToDeleteNodeGroupsReturns struct {
 		result1 *tasks.TaskTree
@@ -695,12 +781,14 @@ type FakeStackManager struct {
 	stackStatusIsNotTransitionalReturnsOnCall map[int]struct {
 		result1 bool
 	}
-	UpdateNodeGroupStackStub        func(string, string, bool) error
+	UpdateNodeGroupStackStub        func(context.Context, string, string, bool, bool) error
 	updateNodeGroupStackMutex       sync.RWMutex
 	updateNodeGroupStackArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 bool
+		arg3 string
+		arg4 bool
+		arg5 bool
 	}
 	updateNodeGroupStackReturns struct {
 		result1 error
@@ -708,10 +796,11 @@ type FakeStackManager struct {
 	updateNodeGroupStackReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UpdateStackStub        func(manager.UpdateStackOptions) error
+	UpdateStackStub        func(context.Context, manager.UpdateStackOptions) error
 	updateStackMutex       sync.RWMutex
 	updateStackArgsForCall []struct {
-		arg1 manager.UpdateStackOptions
+		arg1 context.Context
+		arg2 manager.UpdateStackOptions
 	}
 	updateStackReturns struct {
 		result1 error
@@ -723,19 +812,20 @@ type FakeStackManager struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStackManager) AppendNewClusterStackResource(arg1 bool, arg2 bool) (bool, error) {
+func (fake *FakeStackManager) AppendNewClusterStackResource(arg1 context.Context, arg2 bool, arg3 bool) (bool, error) {
 	fake.appendNewClusterStackResourceMutex.Lock()
 	ret, specificReturn := fake.appendNewClusterStackResourceReturnsOnCall[len(fake.appendNewClusterStackResourceArgsForCall)]
 	fake.appendNewClusterStackResourceArgsForCall = append(fake.appendNewClusterStackResourceArgsForCall, struct {
-		arg1 bool
+		arg1 context.Context
 		arg2 bool
-	}{arg1, arg2})
+		arg3 bool
+	}{arg1, arg2, arg3})
 	stub := fake.AppendNewClusterStackResourceStub
 	fakeReturns := fake.appendNewClusterStackResourceReturns
-	fake.recordInvocation("AppendNewClusterStackResource", []interface{}{arg1, arg2})
+	fake.recordInvocation("AppendNewClusterStackResource", []interface{}{arg1, arg2, arg3})
 	fake.appendNewClusterStackResourceMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -749,17 +839,17 @@ func (fake *FakeStackManager) AppendNewClusterStackResourceCallCount() int {
 	return len(fake.appendNewClusterStackResourceArgsForCall)
 }
 
-func (fake *FakeStackManager) AppendNewClusterStackResourceCalls(stub func(bool, bool) (bool, error)) {
+func (fake *FakeStackManager) AppendNewClusterStackResourceCalls(stub func(context.Context, bool, bool) (bool, error)) {
 	fake.appendNewClusterStackResourceMutex.Lock()
 	defer fake.appendNewClusterStackResourceMutex.Unlock()
 	fake.AppendNewClusterStackResourceStub = stub
 }
 
-func (fake *FakeStackManager) AppendNewClusterStackResourceArgsForCall(i int) (bool, bool) {
+func (fake *FakeStackManager) AppendNewClusterStackResourceArgsForCall(i int) (context.Context, bool, bool) {
 	fake.appendNewClusterStackResourceMutex.RLock()
 	defer fake.appendNewClusterStackResourceMutex.RUnlock()
 	argsForCall := fake.appendNewClusterStackResourceArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStackManager) AppendNewClusterStackResourceReturns(result1 bool, result2 error) {
@@ -788,22 +878,23 @@ func (fake *FakeStackManager) AppendNewClusterStackResourceReturnsOnCall(i int,
 	}{result1, result2}
 }
 
-func (fake *FakeStackManager) CreateStack(arg1 string, arg2 builder.ResourceSet, arg3 map[string]string, arg4 map[string]string, arg5 chan error) error {
+func (fake *FakeStackManager) CreateStack(arg1 context.Context, arg2 string, arg3 builder.ResourceSet, arg4 map[string]string, arg5 map[string]string, arg6 chan error) error {
 	fake.createStackMutex.Lock()
 	ret, specificReturn := fake.createStackReturnsOnCall[len(fake.createStackArgsForCall)]
 	fake.createStackArgsForCall = append(fake.createStackArgsForCall, struct {
-		arg1 string
-		arg2 builder.ResourceSet
-		arg3 map[string]string
+		arg1 context.Context
+		arg2 string
+		arg3 builder.ResourceSet
 		arg4 map[string]string
-		arg5 chan error
-	}{arg1, arg2, arg3, arg4, arg5})
+		arg5 map[string]string
+		arg6 chan error
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
 	stub := fake.CreateStackStub
 	fakeReturns := fake.createStackReturns
-	fake.recordInvocation("CreateStack", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("CreateStack", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.createStackMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1
@@ -817,17 +908,17 @@ func (fake *FakeStackManager) CreateStackCallCount() int {
 	return len(fake.createStackArgsForCall)
 }
 
-func (fake *FakeStackManager) CreateStackCalls(stub func(string, builder.ResourceSet, map[string]string, map[string]string, chan error) error) {
+func (fake *FakeStackManager) CreateStackCalls(stub func(context.Context, string, builder.ResourceSet, map[string]string, map[string]string, chan error) error) {
 	fake.createStackMutex.Lock()
 	defer fake.createStackMutex.Unlock()
 	fake.CreateStackStub = stub
 }
 
-func (fake *FakeStackManager) CreateStackArgsForCall(i int) (string, builder.ResourceSet, map[string]string, map[string]string, chan error) {
+func (fake *FakeStackManager) CreateStackArgsForCall(i int) (context.Context, string, builder.ResourceSet, map[string]string, map[string]string, chan error) {
 	fake.createStackMutex.RLock()
 	defer fake.createStackMutex.RUnlock()
 	argsForCall := fake.createStackArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeStackManager) CreateStackReturns(result1 error) {
@@ -853,6 +944,122 @@ func (fake *FakeStackManager) CreateStackReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeStackManager) CreatedStacks() []*cloudformation.Stack {
+	fake.createdStacksMutex.Lock()
+	ret, specificReturn := fake.createdStacksReturnsOnCall[len(fake.createdStacksArgsForCall)]
+	fake.createdStacksArgsForCall = append(fake.createdStacksArgsForCall, struct {
+	}{})
+	stub := fake.CreatedStacksStub
+	fakeReturns := fake.createdStacksReturns
+	fake.recordInvocation("CreatedStacks", []interface{}{})
+	fake.createdStacksMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStackManager) CreatedStacksCallCount() int {
+	fake.createdStacksMutex.RLock()
+	defer fake.createdStacksMutex.RUnlock()
+	return len(fake.createdStacksArgsForCall)
+}
+
+func (fake *FakeStackManager) CreatedStacksCalls(stub func() []*cloudformation.Stack) {
+	fake.createdStacksMutex.Lock()
+	defer fake.createdStacksMutex.Unlock()
+	fake.CreatedStacksStub = stub
+}
+
+func (fake *FakeStackManager) CreatedStacksReturns(result1 []*cloudformation.Stack) {
+	fake.createdStacksMutex.Lock()
+	defer fake.createdStacksMutex.Unlock()
+	fake.CreatedStacksStub = nil
+	fake.createdStacksReturns = struct {
+		result1 []*cloudformation.Stack
+	}{result1}
+}
+
+func (fake *FakeStackManager) CreatedStacksReturnsOnCall(i int, result1 []*cloudformation.Stack) {
+	fake.createdStacksMutex.Lock()
+	defer fake.createdStacksMutex.Unlock()
+	fake.CreatedStacksStub = nil
+	if fake.createdStacksReturnsOnCall == nil {
+		fake.createdStacksReturnsOnCall = make(map[int]struct {
+			result1 []*cloudformation.Stack
+		})
+	}
+	fake.createdStacksReturnsOnCall[i] = struct {
+		result1 []*cloudformation.Stack
+	}{result1}
+}
+
+func (fake *FakeStackManager) DeleteProtectedStackBySpecSync(arg1 context.Context, arg2 *cloudformation.Stack, arg3 chan error) error {
+	fake.deleteProtectedStackBySpecSyncMutex.Lock()
+	ret, specificReturn := fake.deleteProtectedStackBySpecSyncReturnsOnCall[len(fake.deleteProtectedStackBySpecSyncArgsForCall)]
+	fake.deleteProtectedStackBySpecSyncArgsForCall = append(fake.deleteProtectedStackBySpecSyncArgsForCall, struct {
+		arg1 context.Context
+		arg2 *cloudformation.Stack
+		arg3 chan error
+	}{arg1, arg2, arg3})
+	stub := fake.DeleteProtectedStackBySpecSyncStub
+	fakeReturns := fake.deleteProtectedStackBySpecSyncReturns
+	fake.recordInvocation("DeleteProtectedStackBySpecSync", []interface{}{arg1, arg2, arg3})
+	fake.deleteProtectedStackBySpecSyncMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStackManager) DeleteProtectedStackBySpecSyncCallCount() int {
+	fake.deleteProtectedStackBySpecSyncMutex.RLock()
+	defer fake.deleteProtectedStackBySpecSyncMutex.RUnlock()
+	return len(fake.deleteProtectedStackBySpecSyncArgsForCall)
+}
+
+func (fake *FakeStackManager) DeleteProtectedStackBySpecSyncCalls(stub func(context.Context, *cloudformation.Stack, chan error) error) {
+	fake.deleteProtectedStackBySpecSyncMutex.Lock()
+	defer fake.deleteProtectedStackBySpecSyncMutex.Unlock()
+	fake.DeleteProtectedStackBySpecSyncStub = stub
+}
+
+func (fake *FakeStackManager) DeleteProtectedStackBySpecSyncArgsForCall(i int) (context.Context, *cloudformation.Stack, chan error) {
+	fake.deleteProtectedStackBySpecSyncMutex.RLock()
+	defer fake.deleteProtectedStackBySpecSyncMutex.RUnlock()
+	argsForCall := fake.deleteProtectedStackBySpecSyncArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStackManager) DeleteProtectedStackBySpecSyncReturns(result1 error) {
+	fake.deleteProtectedStackBySpecSyncMutex.Lock()
+	defer fake.deleteProtectedStackBySpecSyncMutex.Unlock()
+	fake.DeleteProtectedStackBySpecSyncStub = nil
+	fake.deleteProtectedStackBySpecSyncReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStackManager) DeleteProtectedStackBySpecSyncReturnsOnCall(i int, result1 error) {
+	fake.deleteProtectedStackBySpecSyncMutex.Lock()
+	defer fake.deleteProtectedStackBySpecSyncMutex.Unlock()
+	fake.DeleteProtectedStackBySpecSyncStub = nil
+	if fake.deleteProtectedStackBySpecSyncReturnsOnCall == nil {
+		fake.deleteProtectedStackBySpecSyncReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteProtectedStackBySpecSyncReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStackManager) DeleteStackByName(arg1 string) (*cloudformation.Stack, error) {
 	fake.deleteStackByNameMutex.Lock()
 	ret, specificReturn := fake.deleteStackByNameReturnsOnCall[len(fake.deleteStackByNameArgsForCall)]
@@ -917,18 +1124,19 @@ func (fake *FakeStackManager) DeleteStackByNameReturnsOnCall(i int, result1 *clo
 	}{result1, result2}
 }
 
-func (fake *FakeStackManager) DeleteStackByNameSync(arg1 string) error {
+func (fake *FakeStackManager) DeleteStackByNameSync(arg1 context.Context, arg2 string) error {
 	fake.deleteStackByNameSyncMutex.Lock()
 	ret, specificReturn := fake.deleteStackByNameSyncReturnsOnCall[len(fake.deleteStackByNameSyncArgsForCall)]
 	fake.deleteStackByNameSyncArgsForCall = append(fake.deleteStackByNameSyncArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.DeleteStackByNameSyncStub
 	fakeReturns := fake.deleteStackByNameSyncReturns
-	fake.recordInvocation("DeleteStackByNameSync", []interface{}{arg1})
+	fake.recordInvocation("DeleteStackByNameSync", []interface{}{arg1, arg2})
 	fake.deleteStackByNameSyncMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -942,17 +1150,17 @@ func (fake *FakeStackManager) DeleteStackByNameSyncCallCount() int {
 	return len(fake.deleteStackByNameSyncArgsForCall)
 }
 
-func (fake *FakeStackManager) DeleteStackByNameSyncCalls(stub func(string) error) {
+func (fake *FakeStackManager) DeleteStackByNameSyncCalls(stub func(context.Context, string) error) {
 	fake.deleteStackByNameSyncMutex.Lock()
 	defer fake.deleteStackByNameSyncMutex.Unlock()
 	fake.DeleteStackByNameSyncStub = stub
 }
 
-func (fake *FakeStackManager) DeleteStackByNameSyncArgsForCall(i int) string {
+func (fake *FakeStackManager) DeleteStackByNameSyncArgsForCall(i int) (context.Context, string) {
 	fake.deleteStackByNameSyncMutex.RLock()
 	defer fake.deleteStackByNameSyncMutex.RUnlock()
 	argsForCall := fake.deleteStackByNameSyncArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStackManager) DeleteStackByNameSyncReturns(result1 error) {
@@ -1042,19 +1250,20 @@ func (fake *FakeStackManager) DeleteStackBySpecReturnsOnCall(i int, result1 *clo
 	}{result1, result2}
 }
 
-func (fake *FakeStackManager) DeleteStackBySpecSync(arg1 *cloudformation.Stack, arg2 chan error) error {
+func (fake *FakeStackManager) DeleteStackBySpecSync(arg1 context.Context, arg2 *cloudformation.Stack, arg3 chan error) error {
 	fake.deleteStackBySpecSyncMutex.Lock()
 	ret, specificReturn := fake.deleteStackBySpecSyncReturnsOnCall[len(fake.deleteStackBySpecSyncArgsForCall)]
 	fake.deleteStackBySpecSyncArgsForCall = append(fake.deleteStackBySpecSyncArgsForCall, struct {
-		arg1 *cloudformation.Stack
-		arg2 chan error
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 *cloudformation.Stack
+		arg3 chan error
+	}{arg1, arg2, arg3})
 	stub := fake.DeleteStackBySpecSyncStub
 	fakeReturns := fake.deleteStackBySpecSyncReturns
-	fake.recordInvocation("DeleteStackBySpecSync", []interface{}{arg1, arg2})
+	fake.recordInvocation("DeleteStackBySpecSync", []interface{}{arg1, arg2, arg3})
 	fake.deleteStackBySpecSyncMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -1068,17 +1277,17 @@ func (fake *FakeStackManager) DeleteStackBySpecSyncCallCount() int {
 	return len(fake.deleteStackBySpecSyncArgsForCall)
 }
 
-func (fake *FakeStackManager) DeleteStackBySpecSyncCalls(stub func(*cloudformation.Stack, chan error) error) {
+func (fake *FakeStackManager) DeleteStackBySpecSyncCalls(stub func(context.Context, *cloudformation.Stack, chan error) error) {
 	fake.deleteStackBySpecSyncMutex.Lock()
 	defer fake.deleteStackBySpecSyncMutex.Unlock()
 	fake.DeleteStackBySpecSyncStub = stub
 }
 
-func (fake *FakeStackManager) DeleteStackBySpecSyncArgsForCall(i int) (*cloudformation.Stack, chan error) {
+func (fake *FakeStackManager) DeleteStackBySpecSyncArgsForCall(i int) (context.Context, *cloudformation.Stack, chan error) {
 	fake.deleteStackBySpecSyncMutex.RLock()
 	defer fake.deleteStackBySpecSyncMutex.RUnlock()
 	argsForCall := fake.deleteStackBySpecSyncArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStackManager) DeleteStackBySpecSyncReturns(result1 error) {
@@ -1160,6 +1369,72 @@ func (fake *FakeStackManager) DeleteTasksForDeprecatedStacksReturnsOnCall(i int,
 	}{result1, result2}
 }
 
+func (fake *FakeStackManager) DeleteUploadedTemplates(arg1 []*cloudformation.Stack) error {
+	var arg1Copy []*cloudformation.Stack
+	if arg1 != nil {
+		arg1Copy = make([]*cloudformation.Stack, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.deleteUploadedTemplatesMutex.Lock()
+	ret, specificReturn := fake.deleteUploadedTemplatesReturnsOnCall[len(fake.deleteUploadedTemplatesArgsForCall)]
+	fake.deleteUploadedTemplatesArgsForCall = append(fake.deleteUploadedTemplatesArgsForCall, struct {
+		arg1 []*cloudformation.Stack
+	}{arg1Copy})
+	stub := fake.DeleteUploadedTemplatesStub
+	fakeReturns := fake.deleteUploadedTemplatesReturns
+	fake.recordInvocation("DeleteUploadedTemplates", []interface{}{arg1Copy})
+	fake.deleteUploadedTemplatesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStackManager) DeleteUploadedTemplatesCallCount() int {
+	fake.deleteUploadedTemplatesMutex.RLock()
+	defer fake.deleteUploadedTemplatesMutex.RUnlock()
+	return len(fake.deleteUploadedTemplatesArgsForCall)
+}
+
+func (fake *FakeStackManager) DeleteUploadedTemplatesCalls(stub func([]*cloudformation.Stack) error) {
+	fake.deleteUploadedTemplatesMutex.Lock()
+	defer fake.deleteUploadedTemplatesMutex.Unlock()
+	fake.DeleteUploadedTemplatesStub = stub
+}
+
+func (fake *FakeStackManager) DeleteUploadedTemplatesArgsForCall(i int) []*cloudformation.Stack {
+	fake.deleteUploadedTemplatesMutex.RLock()
+	defer fake.deleteUploadedTemplatesMutex.RUnlock()
+	argsForCall := fake.deleteUploadedTemplatesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStackManager) DeleteUploadedTemplatesReturns(result1 error) {
+	fake.deleteUploadedTemplatesMutex.Lock()
+	defer fake.deleteUploadedTemplatesMutex.Unlock()
+	fake.DeleteUploadedTemplatesStub = nil
+	fake.deleteUploadedTemplatesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStackManager) DeleteUploadedTemplatesReturnsOnCall(i int, result1 error) {
+	fake.deleteUploadedTemplatesMutex.Lock()
+	defer fake.deleteUploadedTemplatesMutex.Unlock()
+	fake.DeleteUploadedTemplatesStub = nil
+	if fake.deleteUploadedTemplatesReturnsOnCall == nil {
+		fake.deleteUploadedTemplatesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteUploadedTemplatesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStackManager) DescribeClusterStack() (*cloudformation.Stack, error) {
 	fake.describeClusterStackMutex.Lock()
 	ret, specificReturn := fake.describeClusterStackReturnsOnCall[len(fake.describeClusterStackArgsForCall)]
@@ -1697,6 +1972,71 @@ func (fake *FakeStackManager) DescribeStacksReturnsOnCall(i int, result1 []*clou
 	}{result1, result2}
 }
 
+func (fake *FakeStackManager) DetectStackDrift(arg1 context.Context, arg2 *cloudformation.Stack) (*manager.StackDrift, error) {
+	fake.detectStackDriftMutex.Lock()
+	ret, specificReturn := fake.detectStackDriftReturnsOnCall[len(fake.detectStackDriftArgsForCall)]
+	fake.detectStackDriftArgsForCall = append(fake.detectStackDriftArgsForCall, struct {
+		arg1 context.Context
+		arg2 *cloudformation.Stack
+	}{arg1, arg2})
+	stub := fake.DetectStackDriftStub
+	fakeReturns := fake.detectStackDriftReturns
+	fake.recordInvocation("DetectStackDrift", []interface{}{arg1, arg2})
+	fake.detectStackDriftMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStackManager) DetectStackDriftCallCount() int {
+	fake.detectStackDriftMutex.RLock()
+	defer fake.detectStackDriftMutex.RUnlock()
+	return len(fake.detectStackDriftArgsForCall)
+}
+
+func (fake *FakeStackManager) DetectStackDriftCalls(stub func(context.Context, *cloudformation.Stack) (*manager.StackDrift, error)) {
+	fake.detectStackDriftMutex.Lock()
+	defer fake.detectStackDriftMutex.Unlock()
+	fake.DetectStackDriftStub = stub
+}
+
+func (fake *FakeStackManager) DetectStackDriftArgsForCall(i int) (context.Context, *cloudformation.Stack) {
+	fake.detectStackDriftMutex.RLock()
+	defer fake.detectStackDriftMutex.RUnlock()
+	argsForCall := fake.detectStackDriftArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStackManager) DetectStackDriftReturns(result1 *manager.StackDrift, result2 error) {
+	fake.detectStackDriftMutex.Lock()
+	defer fake.detectStackDriftMutex.Unlock()
+	fake.DetectStackDriftStub = nil
+	fake.detectStackDriftReturns = struct {
+		result1 *manager.StackDrift
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStackManager) DetectStackDriftReturnsOnCall(i int, result1 *manager.StackDrift, result2 error) {
+	fake.detectStackDriftMutex.Lock()
+	defer fake.detectStackDriftMutex.Unlock()
+	fake.DetectStackDriftStub = nil
+	if fake.detectStackDriftReturnsOnCall == nil {
+		fake.detectStackDriftReturnsOnCall = make(map[int]struct {
+			result1 *manager.StackDrift
+			result2 error
+		})
+	}
+	fake.detectStackDriftReturnsOnCall[i] = struct {
+		result1 *manager.StackDrift
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeStackManager) DoCreateStackRequest(arg1 *cloudformation.Stack, arg2 manager.TemplateData, arg3 map[string]string, arg4 map[string]string, arg5 bool, arg6 bool) error {
 	fake.doCreateStackRequestMutex.Lock()
 	ret, specificReturn := fake.doCreateStackRequestReturnsOnCall[len(fake.doCreateStackRequestArgsForCall)]
@@ -1763,18 +2103,19 @@ func (fake *FakeStackManager) DoCreateStackRequestReturnsOnCall(i int, result1 e
 	}{result1}
 }
 
-func (fake *FakeStackManager) DoWaitUntilStackIsCreated(arg1 *cloudformation.Stack) error {
+func (fake *FakeStackManager) DoWaitUntilStackIsCreated(arg1 context.Context, arg2 *cloudformation.Stack) error {
 	fake.doWaitUntilStackIsCreatedMutex.Lock()
 	ret, specificReturn := fake.doWaitUntilStackIsCreatedReturnsOnCall[len(fake.doWaitUntilStackIsCreatedArgsForCall)]
 	fake.doWaitUntilStackIsCreatedArgsForCall = append(fake.doWaitUntilStackIsCreatedArgsForCall, struct {
-		arg1 *cloudformation.Stack
-	}{arg1})
+		arg1 context.Context
+		arg2 *cloudformation.Stack
+	}{arg1, arg2})
 	stub := fake.DoWaitUntilStackIsCreatedStub
 	fakeReturns := fake.doWaitUntilStackIsCreatedReturns
-	fake.recordInvocation("DoWaitUntilStackIsCreated", []interface{}{arg1})
+	fake.recordInvocation("DoWaitUntilStackIsCreated", []interface{}{arg1, arg2})
 	fake.doWaitUntilStackIsCreatedMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -1788,17 +2129,17 @@ func (fake *FakeStackManager) DoWaitUntilStackIsCreatedCallCount() int {
 	return len(fake.doWaitUntilStackIsCreatedArgsForCall)
 }
 
-func (fake *FakeStackManager) DoWaitUntilStackIsCreatedCalls(stub func(*cloudformation.Stack) error) {
+func (fake *FakeStackManager) DoWaitUntilStackIsCreatedCalls(stub func(context.Context, *cloudformation.Stack) error) {
 	fake.doWaitUntilStackIsCreatedMutex.Lock()
 	defer fake.doWaitUntilStackIsCreatedMutex.Unlock()
 	fake.DoWaitUntilStackIsCreatedStub = stub
 }
 
-func (fake *FakeStackManager) DoWaitUntilStackIsCreatedArgsForCall(i int) *cloudformation.Stack {
+func (fake *FakeStackManager) DoWaitUntilStackIsCreatedArgsForCall(i int) (context.Context, *cloudformation.Stack) {
 	fake.doWaitUntilStackIsCreatedMutex.RLock()
 	defer fake.doWaitUntilStackIsCreatedMutex.RUnlock()
 	argsForCall := fake.doWaitUntilStackIsCreatedArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStackManager) DoWaitUntilStackIsCreatedReturns(result1 error) {
@@ -1824,17 +2165,18 @@ func (fake *FakeStackManager) DoWaitUntilStackIsCreatedReturnsOnCall(i int, resu
 	}{result1}
 }
 
-func (fake *FakeStackManager) EnsureMapPublicIPOnLaunchEnabled() error {
+func (fake *FakeStackManager) EnsureMapPublicIPOnLaunchEnabled(arg1 context.Context) error {
 	fake.ensureMapPublicIPOnLaunchEnabledMutex.Lock()
 	ret, specificReturn := fake.ensureMapPublicIPOnLaunchEnabledReturnsOnCall[len(fake.ensureMapPublicIPOnLaunchEnabledArgsForCall)]
 	fake.ensureMapPublicIPOnLaunchEnabledArgsForCall = append(fake.ensureMapPublicIPOnLaunchEnabledArgsForCall, struct {
-	}{})
+		arg1 context.Context
+	}{arg1})
 	stub := fake.EnsureMapPublicIPOnLaunchEnabledStub
 	fakeReturns := fake.ensureMapPublicIPOnLaunchEnabledReturns
-	fake.recordInvocation("EnsureMapPublicIPOnLaunchEnabled", []interface{}{})
+	fake.recordInvocation("EnsureMapPublicIPOnLaunchEnabled", []interface{}{arg1})
 	fake.ensureMapPublicIPOnLaunchEnabledMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -1848,12 +2190,19 @@ func (fake *FakeStackManager) EnsureMapPublicIPOnLaunchEnabledCallCount() int {
 	return len(fake.ensureMapPublicIPOnLaunchEnabledArgsForCall)
 }
 
-func (fake *FakeStackManager) EnsureMapPublicIPOnLaunchEnabledCalls(stub func() error) {
+func (fake *FakeStackManager) EnsureMapPublicIPOnLaunchEnabledCalls(stub func(context.Context) error) {
 	fake.ensureMapPublicIPOnLaunchEnabledMutex.Lock()
 	defer fake.ensureMapPublicIPOnLaunchEnabledMutex.Unlock()
 	fake.EnsureMapPublicIPOnLaunchEnabledStub = stub
 }
 
+func (fake *FakeStackManager) EnsureMapPublicIPOnLaunchEnabledArgsForCall(i int) context.Context {
+	fake.ensureMapPublicIPOnLaunchEnabledMutex.RLock()
+	defer fake.ensureMapPublicIPOnLaunchEnabledMutex.RUnlock()
+	argsForCall := fake.ensureMapPublicIPOnLaunchEnabledArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeStackManager) EnsureMapPublicIPOnLaunchEnabledReturns(result1 error) {
 	fake.ensureMapPublicIPOnLaunchEnabledMutex.Lock()
 	defer fake.ensureMapPublicIPOnLaunchEnabledMutex.Unlock()
@@ -1877,17 +2226,18 @@ func (fake *FakeStackManager) EnsureMapPublicIPOnLaunchEnabledReturnsOnCall(i in
 	}{result1}
 }
 
-func (fake *FakeStackManager) FixClusterCompatibility() error {
+func (fake *FakeStackManager) FixClusterCompatibility(arg1 context.Context) error {
 	fake.fixClusterCompatibilityMutex.Lock()
 	ret, specificReturn := fake.fixClusterCompatibilityReturnsOnCall[len(fake.fixClusterCompatibilityArgsForCall)]
 	fake.fixClusterCompatibilityArgsForCall = append(fake.fixClusterCompatibilityArgsForCall, struct {
-	}{})
+		arg1 context.Context
+	}{arg1})
 	stub := fake.FixClusterCompatibilityStub
 	fakeReturns := fake.fixClusterCompatibilityReturns
-	fake.recordInvocation("FixClusterCompatibility", []interface{}{})
+	fake.recordInvocation("FixClusterCompatibility", []interface{}{arg1})
 	fake.fixClusterCompatibilityMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -1901,12 +2251,19 @@ func (fake *FakeStackManager) FixClusterCompatibilityCallCount() int {
 	return len(fake.fixClusterCompatibilityArgsForCall)
 }
 
-func (fake *FakeStackManager) FixClusterCompatibilityCalls(stub func() error) {
+func (fake *FakeStackManager) FixClusterCompatibilityCalls(stub func(context.Context) error) {
 	fake.fixClusterCompatibilityMutex.Lock()
 	defer fake.fixClusterCompatibilityMutex.Unlock()
 	fake.FixClusterCompatibilityStub = stub
 }
 
+func (fake *FakeStackManager) FixClusterCompatibilityArgsForCall(i int) context.Context {
+	fake.fixClusterCompatibilityMutex.RLock()
+	defer fake.fixClusterCompatibilityMutex.RUnlock()
+	argsForCall := fake.fixClusterCompatibilityArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeStackManager) FixClusterCompatibilityReturns(result1 error) {
 	fake.fixClusterCompatibilityMutex.Lock()
 	defer fake.fixClusterCompatibilityMutex.Unlock()
@@ -2665,6 +3022,68 @@ func (fake *FakeStackManager) HasClusterStackUsingCachedListReturnsOnCall(i int,
 	}{result1, result2}
 }
 
+func (fake *FakeStackManager) ImportResources(arg1 context.Context, arg2 manager.ImportResourcesOptions) error {
+	fake.importResourcesMutex.Lock()
+	ret, specificReturn := fake.importResourcesReturnsOnCall[len(fake.importResourcesArgsForCall)]
+	fake.importResourcesArgsForCall = append(fake.importResourcesArgsForCall, struct {
+		arg1 context.Context
+		arg2 manager.ImportResourcesOptions
+	}{arg1, arg2})
+	stub := fake.ImportResourcesStub
+	fakeReturns := fake.importResourcesReturns
+	fake.recordInvocation("ImportResources", []interface{}{arg1, arg2})
+	fake.importResourcesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStackManager) ImportResourcesCallCount() int {
+	fake.importResourcesMutex.RLock()
+	defer fake.importResourcesMutex.RUnlock()
+	return len(fake.importResourcesArgsForCall)
+}
+
+func (fake *FakeStackManager) ImportResourcesCalls(stub func(context.Context, manager.ImportResourcesOptions) error) {
+	fake.importResourcesMutex.Lock()
+	defer fake.importResourcesMutex.Unlock()
+	fake.ImportResourcesStub = stub
+}
+
+func (fake *FakeStackManager) ImportResourcesArgsForCall(i int) (context.Context, manager.ImportResourcesOptions) {
+	fake.importResourcesMutex.RLock()
+	defer fake.importResourcesMutex.RUnlock()
+	argsForCall := fake.importResourcesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStackManager) ImportResourcesReturns(result1 error) {
+	fake.importResourcesMutex.Lock()
+	defer fake.importResourcesMutex.Unlock()
+	fake.ImportResourcesStub = nil
+	fake.importResourcesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStackManager) ImportResourcesReturnsOnCall(i int, result1 error) {
+	fake.importResourcesMutex.Lock()
+	defer fake.importResourcesMutex.Unlock()
+	fake.ImportResourcesStub = nil
+	if fake.importResourcesReturnsOnCall == nil {
+		fake.importResourcesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.importResourcesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStackManager) ListClusterStackNames() ([]string, error) {
 	fake.listClusterStackNamesMutex.Lock()
 	ret, specificReturn := fake.listClusterStackNamesReturnsOnCall[len(fake.listClusterStackNamesArgsForCall)]
@@ -2833,6 +3252,70 @@ func (fake *FakeStackManager) ListNodeGroupStacksReturnsOnCall(i int, result1 []
 	}{result1, result2}
 }
 
+func (fake *FakeStackManager) ListStackResources(arg1 *cloudformation.Stack) ([]*cloudformation.StackResourceSummary, error) {
+	fake.listStackResourcesMutex.Lock()
+	ret, specificReturn := fake.listStackResourcesReturnsOnCall[len(fake.listStackResourcesArgsForCall)]
+	fake.listStackResourcesArgsForCall = append(fake.listStackResourcesArgsForCall, struct {
+		arg1 *cloudformation.Stack
+	}{arg1})
+	stub := fake.ListStackResourcesStub
+	fakeReturns := fake.listStackResourcesReturns
+	fake.recordInvocation("ListStackResources", []interface{}{arg1})
+	fake.listStackResourcesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStackManager) ListStackResourcesCallCount() int {
+	fake.listStackResourcesMutex.RLock()
+	defer fake.listStackResourcesMutex.RUnlock()
+	return len(fake.listStackResourcesArgsForCall)
+}
+
+func (fake *FakeStackManager) ListStackResourcesCalls(stub func(*cloudformation.Stack) ([]*cloudformation.StackResourceSummary, error)) {
+	fake.listStackResourcesMutex.Lock()
+	defer fake.listStackResourcesMutex.Unlock()
+	fake.ListStackResourcesStub = stub
+}
+
+func (fake *FakeStackManager) ListStackResourcesArgsForCall(i int) *cloudformation.Stack {
+	fake.listStackResourcesMutex.RLock()
+	defer fake.listStackResourcesMutex.RUnlock()
+	argsForCall := fake.listStackResourcesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStackManager) ListStackResourcesReturns(result1 []*cloudformation.StackResourceSummary, result2 error) {
+	fake.listStackResourcesMutex.Lock()
+	defer fake.listStackResourcesMutex.Unlock()
+	fake.ListStackResourcesStub = nil
+	fake.listStackResourcesReturns = struct {
+		result1 []*cloudformation.StackResourceSummary
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStackManager) ListStackResourcesReturnsOnCall(i int, result1 []*cloudformation.StackResourceSummary, result2 error) {
+	fake.listStackResourcesMutex.Lock()
+	defer fake.listStackResourcesMutex.Unlock()
+	fake.ListStackResourcesStub = nil
+	if fake.listStackResourcesReturnsOnCall == nil {
+		fake.listStackResourcesReturnsOnCall = make(map[int]struct {
+			result1 []*cloudformation.StackResourceSummary
+			result2 error
+		})
+	}
+	fake.listStackResourcesReturnsOnCall[i] = struct {
+		result1 []*cloudformation.StackResourceSummary
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeStackManager) ListStacks(arg1 ...string) ([]*cloudformation.Stack, error) {
 	fake.listStacksMutex.Lock()
 	ret, specificReturn := fake.listStacksReturnsOnCall[len(fake.listStacksArgsForCall)]
@@ -3389,7 +3872,7 @@ func (fake *FakeStackManager) NewTaskToDeleteUnownedNodeGroupReturnsOnCall(i int
 	}{result1}
 }
 
-func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroups(arg1 []*v1alpha5.NodeGroup, arg2 []*v1alpha5.ManagedNodeGroup, arg3 bool, arg4 ...tasks.Task) *tasks.TaskTree {
+func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroups(arg1 []*v1alpha5.NodeGroup, arg2 []*v1alpha5.ManagedNodeGroup, arg3 bool, arg4 []tasks.Task, arg5 ...tasks.Task) (*tasks.TaskTree, error) {
 	var arg1Copy []*v1alpha5.NodeGroup
 	if arg1 != nil {
 		arg1Copy = make([]*v1alpha5.NodeGroup, len(arg1))
@@ -3400,6 +3883,11 @@ func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroups(arg1 []*v1al
 		arg2Copy = make([]*v1alpha5.ManagedNodeGroup, len(arg2))
 		copy(arg2Copy, arg2)
 	}
+	var arg4Copy []tasks.Task
+	if arg4 != nil {
+		arg4Copy = make([]tasks.Task, len(arg4))
+		copy(arg4Copy, arg4)
+	}
 	fake.newTasksToCreateClusterWithNodeGroupsMutex.Lock()
 	ret, specificReturn := fake.newTasksToCreateClusterWithNodeGroupsReturnsOnCall[len(fake.newTasksToCreateClusterWithNodeGroupsArgsForCall)]
 	fake.newTasksToCreateClusterWithNodeGroupsArgsForCall = append(fake.newTasksToCreateClusterWithNodeGroupsArgsForCall, struct {
@@ -3407,18 +3895,19 @@ func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroups(arg1 []*v1al
 		arg2 []*v1alpha5.ManagedNodeGroup
 		arg3 bool
 		arg4 []tasks.Task
-	}{arg1Copy, arg2Copy, arg3, arg4})
+		arg5 []tasks.Task
+	}{arg1Copy, arg2Copy, arg3, arg4Copy, arg5})
 	stub := fake.NewTasksToCreateClusterWithNodeGroupsStub
 	fakeReturns := fake.newTasksToCreateClusterWithNodeGroupsReturns
-	fake.recordInvocation("NewTasksToCreateClusterWithNodeGroups", []interface{}{arg1Copy, arg2Copy, arg3, arg4})
+	fake.recordInvocation("NewTasksToCreateClusterWithNodeGroups", []interface{}{arg1Copy, arg2Copy, arg3, arg4Copy, arg5})
 	fake.newTasksToCreateClusterWithNodeGroupsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4...)
+		return stub(arg1, arg2, arg3, arg4, arg5...)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroupsCallCount() int {
@@ -3427,40 +3916,43 @@ func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroupsCallCount() i
 	return len(fake.newTasksToCreateClusterWithNodeGroupsArgsForCall)
 }
 
-func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroupsCalls(stub func([]*v1alpha5.NodeGroup, []*v1alpha5.ManagedNodeGroup, bool, ...tasks.Task) *tasks.TaskTree) {
+func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroupsCalls(stub func([]*v1alpha5.NodeGroup, []*v1alpha5.ManagedNodeGroup, bool, []tasks.Task, ...tasks.Task) (*tasks.TaskTree, error)) {
 	fake.newTasksToCreateClusterWithNodeGroupsMutex.Lock()
 	defer fake.newTasksToCreateClusterWithNodeGroupsMutex.Unlock()
 	fake.NewTasksToCreateClusterWithNodeGroupsStub = stub
 }
 
-func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroupsArgsForCall(i int) ([]*v1alpha5.NodeGroup, []*v1alpha5.ManagedNodeGroup, bool, []tasks.Task) {
+func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroupsArgsForCall(i int) ([]*v1alpha5.NodeGroup, []*v1alpha5.ManagedNodeGroup, bool, []tasks.Task, []tasks.Task) {
 	fake.newTasksToCreateClusterWithNodeGroupsMutex.RLock()
 	defer fake.newTasksToCreateClusterWithNodeGroupsMutex.RUnlock()
 	argsForCall := fake.newTasksToCreateClusterWithNodeGroupsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
-func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroupsReturns(result1 *tasks.TaskTree) {
+func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroupsReturns(result1 *tasks.TaskTree, result2 error) {
 	fake.newTasksToCreateClusterWithNodeGroupsMutex.Lock()
 	defer fake.newTasksToCreateClusterWithNodeGroupsMutex.Unlock()
 	fake.NewTasksToCreateClusterWithNodeGroupsStub = nil
 	fake.newTasksToCreateClusterWithNodeGroupsReturns = struct {
 		result1 *tasks.TaskTree
-	}{result1}
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroupsReturnsOnCall(i int, result1 *tasks.TaskTree) {
+func (fake *FakeStackManager) NewTasksToCreateClusterWithNodeGroupsReturnsOnCall(i int, result1 *tasks.TaskTree, result2 error) {
 	fake.newTasksToCreateClusterWithNodeGroupsMutex.Lock()
 	defer fake.newTasksToCreateClusterWithNodeGroupsMutex.Unlock()
 	fake.NewTasksToCreateClusterWithNodeGroupsStub = nil
 	if fake.newTasksToCreateClusterWithNodeGroupsReturnsOnCall == nil {
 		fake.newTasksToCreateClusterWithNodeGroupsReturnsOnCall = make(map[int]struct {
 			result1 *tasks.TaskTree
+			result2 error
 		})
 	}
 	fake.newTasksToCreateClusterWithNodeGroupsReturnsOnCall[i] = struct {
 		result1 *tasks.TaskTree
-	}{result1}
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *FakeStackManager) NewTasksToCreateIAMServiceAccounts(arg1 []*v1alpha5.ClusterIAMServiceAccount, arg2 *iamoidc.OpenIDConnectManager, arg3 kubernetes.ClientSetGetter) *tasks.TaskTree {
@@ -3531,7 +4023,7 @@ func (fake *FakeStackManager) NewTasksToCreateIAMServiceAccountsReturnsOnCall(i
 	}{result1}
 }
 
-func (fake *FakeStackManager) NewTasksToDeleteClusterWithNodeGroups(arg1 bool, arg2 *iamoidc.OpenIDConnectManager, arg3 kubernetes.ClientSetGetter, arg4 bool, arg5 func(chan error, string) error) (*tasks.TaskTree, error) {
+func (fake *FakeStackManager) NewTasksToDeleteClusterWithNodeGroups(arg1 bool, arg2 *iamoidc.OpenIDConnectManager, arg3 kubernetes.ClientSetGetter, arg4 bool, arg5 bool, arg6 func(chan error, string) error) (*tasks.TaskTree, error) {
 	fake.newTasksToDeleteClusterWithNodeGroupsMutex.Lock()
 	ret, specificReturn := fake.newTasksToDeleteClusterWithNodeGroupsReturnsOnCall[len(fake.newTasksToDeleteClusterWithNodeGroupsArgsForCall)]
 	fake.newTasksToDeleteClusterWithNodeGroupsArgsForCall = append(fake.newTasksToDeleteClusterWithNodeGroupsArgsForCall, struct {
@@ -3539,14 +4031,15 @@ func (fake *FakeStackManager) NewTasksToDeleteClusterWithNodeGroups(arg1 bool, a
 		arg2 *iamoidc.OpenIDConnectManager
 		arg3 kubernetes.ClientSetGetter
 		arg4 bool
-		arg5 func(chan error, string) error
-	}{arg1, arg2, arg3, arg4, arg5})
+		arg5 bool
+		arg6 func(chan error, string) error
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
 	stub := fake.NewTasksToDeleteClusterWithNodeGroupsStub
 	fakeReturns := fake.newTasksToDeleteClusterWithNodeGroupsReturns
-	fake.recordInvocation("NewTasksToDeleteClusterWithNodeGroups", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("NewTasksToDeleteClusterWithNodeGroups", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.newTasksToDeleteClusterWithNodeGroupsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -3560,17 +4053,17 @@ func (fake *FakeStackManager) NewTasksToDeleteClusterWithNodeGroupsCallCount() i
 	return len(fake.newTasksToDeleteClusterWithNodeGroupsArgsForCall)
 }
 
-func (fake *FakeStackManager) NewTasksToDeleteClusterWithNodeGroupsCalls(stub func(bool, *iamoidc.OpenIDConnectManager, kubernetes.ClientSetGetter, bool, func(chan error, string) error) (*tasks.TaskTree, error)) {
+func (fake *FakeStackManager) NewTasksToDeleteClusterWithNodeGroupsCalls(stub func(bool, *iamoidc.OpenIDConnectManager, kubernetes.ClientSetGetter, bool, bool, func(chan error, string) error) (*tasks.TaskTree, error)) {
 	fake.newTasksToDeleteClusterWithNodeGroupsMutex.Lock()
 	defer fake.newTasksToDeleteClusterWithNodeGroupsMutex.Unlock()
 	fake.NewTasksToDeleteClusterWithNodeGroupsStub = stub
 }
 
-func (fake *FakeStackManager) NewTasksToDeleteClusterWithNodeGroupsArgsForCall(i int) (bool, *iamoidc.OpenIDConnectManager, kubernetes.ClientSetGetter, bool, func(chan error, string) error) {
+func (fake *FakeStackManager) NewTasksToDeleteClusterWithNodeGroupsArgsForCall(i int) (bool, *iamoidc.OpenIDConnectManager, kubernetes.ClientSetGetter, bool, bool, func(chan error, string) error) {
 	fake.newTasksToDeleteClusterWithNodeGroupsMutex.RLock()
 	defer fake.newTasksToDeleteClusterWithNodeGroupsMutex.RUnlock()
 	argsForCall := fake.newTasksToDeleteClusterWithNodeGroupsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeStackManager) NewTasksToDeleteClusterWithNodeGroupsReturns(result1 *tasks.TaskTree, result2 error) {
@@ -3670,20 +4163,21 @@ func (fake *FakeStackManager) NewTasksToDeleteIAMServiceAccountsReturnsOnCall(i
 	}{result1, result2}
 }
 
-func (fake *FakeStackManager) NewTasksToDeleteNodeGroups(arg1 func(_ string) bool, arg2 bool, arg3 func(chan error, string) error) (*tasks.TaskTree, error) {
+func (fake *FakeStackManager) NewTasksToDeleteNodeGroups(arg1 func(_ string) bool, arg2 bool, arg3 bool, arg4 func(chan error, string) error) (*tasks.TaskTree, error) {
 	fake.newTasksToDeleteNodeGroupsMutex.Lock()
 	ret, specificReturn := fake.newTasksToDeleteNodeGroupsReturnsOnCall[len(fake.newTasksToDeleteNodeGroupsArgsForCall)]
 	fake.newTasksToDeleteNodeGroupsArgsForCall = append(fake.newTasksToDeleteNodeGroupsArgsForCall, struct {
 		arg1 func(_ string) bool
 		arg2 bool
-		arg3 func(chan error, string) error
-	}{arg1, arg2, arg3})
+		arg3 bool
+		arg4 func(chan error, string) error
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.NewTasksToDeleteNodeGroupsStub
 	fakeReturns := fake.newTasksToDeleteNodeGroupsReturns
-	fake.recordInvocation("NewTasksToDeleteNodeGroups", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("NewTasksToDeleteNodeGroups", []interface{}{arg1, arg2, arg3, arg4})
 	fake.newTasksToDeleteNodeGroupsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -3697,17 +4191,17 @@ func (fake *FakeStackManager) NewTasksToDeleteNodeGroupsCallCount() int {
 	return len(fake.newTasksToDeleteNodeGroupsArgsForCall)
 }
 
-func (fake *FakeStackManager) NewTasksToDeleteNodeGroupsCalls(stub func(func(_ string) bool, bool, func(chan error, string) error) (*tasks.TaskTree, error)) {
+func (fake *FakeStackManager) NewTasksToDeleteNodeGroupsCalls(stub func(func(_ string) bool, bool, bool, func(chan error, string) error) (*tasks.TaskTree, error)) {
 	fake.newTasksToDeleteNodeGroupsMutex.Lock()
 	defer fake.newTasksToDeleteNodeGroupsMutex.Unlock()
 	fake.NewTasksToDeleteNodeGroupsStub = stub
 }
 
-func (fake *FakeStackManager) NewTasksToDeleteNodeGroupsArgsForCall(i int) (func(_ string) bool, bool, func(chan error, string) error) {
+func (fake *FakeStackManager) NewTasksToDeleteNodeGroupsArgsForCall(i int) (func(_ string) bool, bool, bool, func(chan error, string) error) {
 	fake.newTasksToDeleteNodeGroupsMutex.RLock()
 	defer fake.newTasksToDeleteNodeGroupsMutex.RUnlock()
 	argsForCall := fake.newTasksToDeleteNodeGroupsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStackManager) NewTasksToDeleteNodeGroupsReturns(result1 *tasks.TaskTree, result2 error) {
@@ -4044,20 +4538,22 @@ func (fake *FakeStackManager) StackStatusIsNotTransitionalReturnsOnCall(i int, r
 	}{result1}
 }
 
-func (fake *FakeStackManager) UpdateNodeGroupStack(arg1 string, arg2 string, arg3 bool) error {
+func (fake *FakeStackManager) UpdateNodeGroupStack(arg1 context.Context, arg2 string, arg3 string, arg4 bool, arg5 bool) error {
 	fake.updateNodeGroupStackMutex.Lock()
 	ret, specificReturn := fake.updateNodeGroupStackReturnsOnCall[len(fake.updateNodeGroupStackArgsForCall)]
 	fake.updateNodeGroupStackArgsForCall = append(fake.updateNodeGroupStackArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 bool
-	}{arg1, arg2, arg3})
+		arg3 string
+		arg4 bool
+		arg5 bool
+	}{arg1, arg2, arg3, arg4, arg5})
 	stub := fake.UpdateNodeGroupStackStub
 	fakeReturns := fake.updateNodeGroupStackReturns
-	fake.recordInvocation("UpdateNodeGroupStack", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("UpdateNodeGroupStack", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.updateNodeGroupStackMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
@@ -4071,17 +4567,17 @@ func (fake *FakeStackManager) UpdateNodeGroupStackCallCount() int {
 	return len(fake.updateNodeGroupStackArgsForCall)
 }
 
-func (fake *FakeStackManager) UpdateNodeGroupStackCalls(stub func(string, string, bool) error) {
+func (fake *FakeStackManager) UpdateNodeGroupStackCalls(stub func(context.Context, string, string, bool, bool) error) {
 	fake.updateNodeGroupStackMutex.Lock()
 	defer fake.updateNodeGroupStackMutex.Unlock()
 	fake.UpdateNodeGroupStackStub = stub
 }
 
-func (fake *FakeStackManager) UpdateNodeGroupStackArgsForCall(i int) (string, string, bool) {
+func (fake *FakeStackManager) UpdateNodeGroupStackArgsForCall(i int) (context.Context, string, string, bool, bool) {
 	fake.updateNodeGroupStackMutex.RLock()
 	defer fake.updateNodeGroupStackMutex.RUnlock()
 	argsForCall := fake.updateNodeGroupStackArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeStackManager) UpdateNodeGroupStackReturns(result1 error) {
@@ -4107,18 +4603,19 @@ func (fake *FakeStackManager) UpdateNodeGroupStackReturnsOnCall(i int, result1 e
 	}{result1}
 }
 
-func (fake *FakeStackManager) UpdateStack(arg1 manager.UpdateStackOptions) error {
+func (fake *FakeStackManager) UpdateStack(arg1 context.Context, arg2 manager.UpdateStackOptions) error {
 	fake.updateStackMutex.Lock()
 	ret, specificReturn := fake.updateStackReturnsOnCall[len(fake.updateStackArgsForCall)]
 	fake.updateStackArgsForCall = append(fake.updateStackArgsForCall, struct {
-		arg1 manager.UpdateStackOptions
-	}{arg1})
+		arg1 context.Context
+		arg2 manager.UpdateStackOptions
+	}{arg1, arg2})
 	stub := fake.UpdateStackStub
 	fakeReturns := fake.updateStackReturns
-	fake.recordInvocation("UpdateStack", []interface{}{arg1})
+	fake.recordInvocation("UpdateStack", []interface{}{arg1, arg2})
 	fake.updateStackMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -4132,17 +4629,17 @@ func (fake *FakeStackManager) UpdateStackCallCount() int {
 	return len(fake.updateStackArgsForCall)
 }
 
-func (fake *FakeStackManager) UpdateStackCalls(stub func(manager.UpdateStackOptions) error) {
+func (fake *FakeStackManager) UpdateStackCalls(stub func(context.Context, manager.UpdateStackOptions) error) {
 	fake.updateStackMutex.Lock()
 	defer fake.updateStackMutex.Unlock()
 	fake.UpdateStackStub = stub
 }
 
-func (fake *FakeStackManager) UpdateStackArgsForCall(i int) manager.UpdateStackOptions {
+func (fake *FakeStackManager) UpdateStackArgsForCall(i int) (context.Context, manager.UpdateStackOptions) {
 	fake.updateStackMutex.RLock()
 	defer fake.updateStackMutex.RUnlock()
 	argsForCall := fake.updateStackArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStackManager) UpdateStackReturns(result1 error) {
@@ -4175,6 +4672,10 @@ func (fake *FakeStackManager) Invocations() map[string][][]interface{} {
 	defer fake.appendNewClusterStackResourceMutex.RUnlock()
 	fake.createStackMutex.RLock()
 	defer fake.createStackMutex.RUnlock()
+	fake.createdStacksMutex.RLock()
+	defer fake.createdStacksMutex.RUnlock()
+	fake.deleteProtectedStackBySpecSyncMutex.RLock()
+	defer fake.deleteProtectedStackBySpecSyncMutex.RUnlock()
 	fake.deleteStackByNameMutex.RLock()
 	defer fake.deleteStackByNameMutex.RUnlock()
 	fake.deleteStackByNameSyncMutex.RLock()
@@ -4185,6 +4686,8 @@ func (fake *FakeStackManager) Invocations() map[string][][]interface{} {
 	defer fake.deleteStackBySpecSyncMutex.RUnlock()
 	fake.deleteTasksForDeprecatedStacksMutex.RLock()
 	defer fake.deleteTasksForDeprecatedStacksMutex.RUnlock()
+	fake.deleteUploadedTemplatesMutex.RLock()
+	defer fake.deleteUploadedTemplatesMutex.RUnlock()
 	fake.describeClusterStackMutex.RLock()
 	defer fake.describeClusterStackMutex.RUnlock()
 	fake.describeIAMServiceAccountStacksMutex.RLock()
@@ -4203,6 +4706,8 @@ func (fake *FakeStackManager) Invocations() map[string][][]interface{} {
 	defer fake.describeStackEventsMutex.RUnlock()
 	fake.describeStacksMutex.RLock()
 	defer fake.describeStacksMutex.RUnlock()
+	fake.detectStackDriftMutex.RLock()
+	defer fake.detectStackDriftMutex.RUnlock()
 	fake.doCreateStackRequestMutex.RLock()
 	defer fake.doCreateStackRequestMutex.RUnlock()
 	fake.doWaitUntilStackIsCreatedMutex.RLock()
@@ -4235,12 +4740,16 @@ func (fake *FakeStackManager) Invocations() map[string][][]interface{} {
 	defer fake.getUnmanagedNodeGroupSummariesMutex.RUnlock()
 	fake.hasClusterStackUsingCachedListMutex.RLock()
 	defer fake.hasClusterStackUsingCachedListMutex.RUnlock()
+	fake.importResourcesMutex.RLock()
+	defer fake.importResourcesMutex.RUnlock()
 	fake.listClusterStackNamesMutex.RLock()
 	defer fake.listClusterStackNamesMutex.RUnlock()
 	fake.listIAMServiceAccountStacksMutex.RLock()
 	defer fake.listIAMServiceAccountStacksMutex.RUnlock()
 	fake.listNodeGroupStacksMutex.RLock()
 	defer fake.listNodeGroupStacksMutex.RUnlock()
+	fake.listStackResourcesMutex.RLock()
+	defer fake.listStackResourcesMutex.RUnlock()
 	fake.listStacksMutex.RLock()
 	defer fake.listStacksMutex.RUnlock()
 	fake.listStacksMatchingMutex.RLock()
@@ -0,0 +1,251 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+)
+
+// StackGraph holds the stacks of a cluster and their dependencies, which are the
+// outputs of a stack imported by another stack using Fn::ImportValue
+type StackGraph struct {
+	Stacks       []*StackGraphNode
+	Dependencies []*StackDependency
+}
+
+// StackGraphNode is a stack of a StackGraph
+type StackGraphNode struct {
+	Name          string
+	Status        string
+	EksctlVersion string
+	// Stale is set when the stack was last created or updated by an older version of eksctl than the cluster stack
+	Stale bool
+	// ImportedBy lists the stacks importing outputs of the stack, which cannot be deleted before them
+	ImportedBy []string
+}
+
+// StackDependency is a stack importing the outputs of another stack
+type StackDependency struct {
+	Importer string
+	Exporter string
+	Outputs  []string
+}
+
+// NewStackGraph builds the dependency graph of all stacks of the cluster from their templates
+func NewStackGraph(stackManager StackManager) (*StackGraph, error) {
+	stacks, err := stackManager.DescribeStacks()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(stacks, func(i, j int) bool { return *stacks[i].StackName < *stacks[j].StackName })
+
+	g := &StackGraph{}
+	nodes := map[string]*StackGraphNode{}
+	versions := map[string]semver.Version{}
+	exporters := map[string]string{}
+	var clusterStack string
+	for _, s := range stacks {
+		node := &StackGraphNode{
+			Name:   *s.StackName,
+			Status: aws.StringValue(s.StackStatus),
+		}
+		v, found, err := GetEksctlVersionFromTags(s.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			node.EksctlVersion = v.String()
+			versions[node.Name] = v
+		}
+		if getClusterName(s) != "" {
+			clusterStack = node.Name
+		}
+		for _, o := range s.Outputs {
+			if o.ExportName != nil {
+				exporters[*o.ExportName] = node.Name
+			}
+		}
+		nodes[node.Name] = node
+		g.Stacks = append(g.Stacks, node)
+	}
+
+	if clusterVersion, ok := versions[clusterStack]; ok {
+		for _, node := range g.Stacks {
+			if node.Name == clusterStack || node.Status == cfn.StackStatusDeleteComplete {
+				continue
+			}
+			v, found := versions[node.Name]
+			node.Stale = !found || v.LT(clusterVersion)
+		}
+	}
+
+	for _, s := range stacks {
+		if *s.StackStatus == cfn.StackStatusDeleteComplete {
+			continue
+		}
+		template, err := stackManager.GetStackTemplate(*s.StackName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting template of stack %q", *s.StackName)
+		}
+		imports, err := findImportedValues(template)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing template of stack %q", *s.StackName)
+		}
+
+		dependencies := map[string]*StackDependency{}
+		for _, exportName := range imports {
+			exporter, ok := exporters[exportName]
+			if !ok {
+				// the export does not belong to a stack of the cluster
+				continue
+			}
+			d, ok := dependencies[exporter]
+			if !ok {
+				d = &StackDependency{Importer: *s.StackName, Exporter: exporter}
+				dependencies[exporter] = d
+				g.Dependencies = append(g.Dependencies, d)
+				nodes[exporter].ImportedBy = append(nodes[exporter].ImportedBy, *s.StackName)
+			}
+			// eksctl names exports <stack name>::<output name>
+			d.Outputs = append(d.Outputs, strings.TrimPrefix(exportName, exporter+"::"))
+		}
+	}
+	return g, nil
+}
+
+// findImportedValues returns the sorted names of the exports imported by the template
+func findImportedValues(template string) ([]string, error) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(template), &doc); err != nil {
+		return nil, err
+	}
+
+	names := map[string]struct{}{}
+	var walk func(interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, value := range v {
+				if key == "Fn::ImportValue" {
+					if name, ok := importedValueName(value); ok {
+						names[name] = struct{}{}
+						continue
+					}
+				}
+				walk(value)
+			}
+		case []interface{}:
+			for _, value := range v {
+				walk(value)
+			}
+		}
+	}
+	walk(doc)
+
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+// importedValueName returns the export name passed to Fn::ImportValue, which is
+// either a string or a Fn::Sub without variables
+func importedValueName(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case map[string]interface{}:
+		if sub, ok := v["Fn::Sub"].(string); ok && len(v) == 1 {
+			return sub, true
+		}
+	}
+	return "", false
+}
+
+func stackColor(node *StackGraphNode) string {
+	switch {
+	case node.Status == cfn.StackStatusDeleteComplete:
+		return "grey"
+	case strings.Contains(node.Status, "FAILED"), strings.Contains(node.Status, "ROLLBACK"):
+		return "red"
+	case strings.HasSuffix(node.Status, "_IN_PROGRESS"):
+		return "yellow"
+	default:
+		return "green"
+	}
+}
+
+func stackLabel(node *StackGraphNode, newline string) string {
+	lines := []string{node.Name, node.Status}
+	if node.EksctlVersion != "" {
+		lines = append(lines, "eksctl "+node.EksctlVersion)
+	} else {
+		lines = append(lines, "eksctl version unknown")
+	}
+	if node.Stale {
+		lines = append(lines, "stale")
+	}
+	if len(node.ImportedBy) > 0 {
+		lines = append(lines, fmt.Sprintf("cannot be deleted before %d stack(s)", len(node.ImportedBy)))
+	}
+	return strings.Join(lines, newline)
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language
+func (g *StackGraph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph stacks {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=filled];\n")
+	for _, node := range g.Stacks {
+		style := ""
+		if node.Stale {
+			style = ", peripheries=2"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q, fillcolor=%s%s];\n", node.Name, stackLabel(node, "\n"), stackColor(node), style)
+	}
+	for _, d := range g.Dependencies {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", d.Importer, d.Exporter, strings.Join(d.Outputs, "\n"))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart
+func (g *StackGraph) WriteMermaid(w io.Writer) error {
+	ids := map[string]string{}
+	for i, node := range g.Stacks {
+		ids[node.Name] = fmt.Sprintf("s%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, node := range g.Stacks {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[node.Name], stackLabel(node, "<br/>"))
+	}
+	for _, d := range g.Dependencies {
+		fmt.Fprintf(&b, "  %s -->|\"%s\"| %s\n", ids[d.Importer], strings.Join(d.Outputs, "<br/>"), ids[d.Exporter])
+	}
+	for _, node := range g.Stacks {
+		fmt.Fprintf(&b, "  style %s fill:%s\n", ids[node.Name], mermaidColors[stackColor(node)])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var mermaidColors = map[string]string{
+	"green":  "#9f9",
+	"yellow": "#ff9",
+	"red":    "#f99",
+	"grey":   "#ccc",
+}
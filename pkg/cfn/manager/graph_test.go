@@ -0,0 +1,108 @@
+package manager
+
+import (
+	"bytes"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackGraph", func() {
+	var (
+		p  *mockprovider.MockProvider
+		sc *StackCollection
+	)
+
+	mockStack := func(name, status, eksctlVersion string, outputs []*cfn.Output, template string) {
+		tags := []*cfn.Tag{{Key: aws.String(api.ClusterNameTag), Value: aws.String("test")}}
+		if eksctlVersion != "" {
+			tags = append(tags, &cfn.Tag{Key: aws.String(api.EksctlVersionTag), Value: aws.String(eksctlVersion)})
+		}
+		p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+			return *input.StackName == name
+		})).Return(&cfn.DescribeStacksOutput{
+			Stacks: []*cfn.Stack{{
+				StackName:   aws.String(name),
+				StackStatus: aws.String(status),
+				Tags:        tags,
+				Outputs:     outputs,
+			}},
+		}, nil)
+		p.MockCloudFormation().On("GetTemplate", mock.MatchedBy(func(input *cfn.GetTemplateInput) bool {
+			return *input.StackName == name
+		})).Return(&cfn.GetTemplateOutput{TemplateBody: aws.String(template)}, nil)
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		sc = NewStackCollection(p, cfg)
+
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			pager := args.Get(1).(func(*cfn.ListStacksOutput, bool) bool)
+			pager(&cfn.ListStacksOutput{StackSummaries: []*cfn.StackSummary{
+				{StackName: aws.String("eksctl-test-cluster")},
+				{StackName: aws.String("eksctl-test-nodegroup-ng-1")},
+				{StackName: aws.String("eksctl-test-nodegroup-ng-2")},
+			}}, true)
+		}).Return(nil)
+
+		mockStack("eksctl-test-cluster", cfn.StackStatusUpdateComplete, "0.80.0", []*cfn.Output{
+			{OutputKey: aws.String("VPC"), ExportName: aws.String("eksctl-test-cluster::VPC")},
+			{OutputKey: aws.String("SubnetsPrivate"), ExportName: aws.String("eksctl-test-cluster::SubnetsPrivate")},
+		}, `{"Resources": {}}`)
+		mockStack("eksctl-test-nodegroup-ng-1", cfn.StackStatusCreateComplete, "0.80.0", nil, `{
+			"Resources": {
+				"SG": {"Type": "AWS::EC2::SecurityGroup", "Properties": {"VpcId": {"Fn::ImportValue": "eksctl-test-cluster::VPC"}}},
+				"ASG": {"Type": "AWS::AutoScaling::AutoScalingGroup", "Properties": {"VPCZoneIdentifier": {"Fn::Split": [",", {"Fn::ImportValue": "eksctl-test-cluster::SubnetsPrivate"}]}}}
+			}
+		}`)
+		mockStack("eksctl-test-nodegroup-ng-2", cfn.StackStatusUpdateRollbackFailed, "0.70.0", nil, `{
+			"Resources": {
+				"SG": {"Type": "AWS::EC2::SecurityGroup", "Properties": {"VpcId": {"Fn::ImportValue": {"Fn::Sub": "eksctl-test-cluster::VPC"}}}}
+			}
+		}`)
+	})
+
+	It("finds the imported outputs of the cluster stack and stale stacks", func() {
+		graph, err := NewStackGraph(sc)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(graph.Stacks).To(HaveLen(3))
+		Expect(graph.Stacks[0].Name).To(Equal("eksctl-test-cluster"))
+		Expect(graph.Stacks[0].EksctlVersion).To(Equal("0.80.0"))
+		Expect(graph.Stacks[0].ImportedBy).To(Equal([]string{"eksctl-test-nodegroup-ng-1", "eksctl-test-nodegroup-ng-2"}))
+		Expect(graph.Stacks[1].Stale).To(BeFalse())
+		Expect(graph.Stacks[2].Stale).To(BeTrue())
+
+		Expect(graph.Dependencies).To(Equal([]*StackDependency{
+			{Importer: "eksctl-test-nodegroup-ng-1", Exporter: "eksctl-test-cluster", Outputs: []string{"SubnetsPrivate", "VPC"}},
+			{Importer: "eksctl-test-nodegroup-ng-2", Exporter: "eksctl-test-cluster", Outputs: []string{"VPC"}},
+		}))
+	})
+
+	It("renders the graph as DOT and Mermaid", func() {
+		graph, err := NewStackGraph(sc)
+		Expect(err).NotTo(HaveOccurred())
+
+		dot := new(bytes.Buffer)
+		Expect(graph.WriteDOT(dot)).To(Succeed())
+		Expect(dot.String()).To(HavePrefix("digraph stacks {\n"))
+		Expect(dot.String()).To(ContainSubstring(`"eksctl-test-nodegroup-ng-1" -> "eksctl-test-cluster" [label="SubnetsPrivate\nVPC"];`))
+		Expect(dot.String()).To(ContainSubstring(`"eksctl-test-nodegroup-ng-2" [label="eksctl-test-nodegroup-ng-2\nUPDATE_ROLLBACK_FAILED\neksctl 0.70.0\nstale", fillcolor=red, peripheries=2];`))
+
+		mermaid := new(bytes.Buffer)
+		Expect(graph.WriteMermaid(mermaid)).To(Succeed())
+		Expect(mermaid.String()).To(HavePrefix("flowchart LR\n"))
+		Expect(mermaid.String()).To(ContainSubstring(`s2 -->|"VPC"| s0`))
+		Expect(mermaid.String()).To(ContainSubstring(`s0["eksctl-test-cluster<br/>UPDATE_COMPLETE<br/>eksctl 0.80.0<br/>cannot be deleted before 2 stack(s)"]`))
+		Expect(mermaid.String()).To(ContainSubstring("style s0 fill:#9f9"))
+	})
+})
@@ -0,0 +1,215 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// emptyTemplate is the template that resources are added to when they are imported into a new stack
+const emptyTemplate = `{"AWSTemplateFormatVersion": "2010-09-09", "Resources": {}}`
+
+// ResourceToImport describes an existing resource that is imported into a stack
+type ResourceToImport struct {
+	// LogicalID is the logical ID of the resource in the template
+	LogicalID string
+	// ResourceType is the CloudFormation type of the resource
+	ResourceType string
+	// Identifier holds the properties that identify the resource, e.g. VpcId for AWS::EC2::VPC
+	Identifier map[string]string
+	// Properties are the properties of the resource in the template, they must
+	// include the identifier properties that are part of the resource's schema
+	Properties map[string]interface{}
+}
+
+// ImportResourcesOptions describes the resources to import into a stack
+type ImportResourcesOptions struct {
+	StackName     string
+	ChangeSetName string
+	Description   string
+	Resources     []ResourceToImport
+	// NewStack creates the stack with the imported resources, instead of adding them to an existing stack
+	NewStack bool
+	// Tags are set on the stack in addition to the shared tags when NewStack is true
+	Tags map[string]string
+	// Outputs are added to the stack once the resources are imported
+	Outputs map[string]string
+	// Plan creates the ChangeSet through the CloudFormation API and logs the resource changes
+	// it contains without executing it; the ChangeSet, and the stack when NewStack is true,
+	// are always deleted afterwards, also when the preview fails or is interrupted
+	Plan bool
+}
+
+// ImportResources adds existing resources to a stack by creating and executing an IMPORT ChangeSet;
+// the resources are added with DeletionPolicy Retain, so they are kept when the stack is deleted
+func (c *StackCollection) ImportResources(ctx context.Context, options ImportResourcesOptions) (err error) {
+	logger.Info(options.Description)
+	i := &Stack{StackName: &options.StackName}
+
+	var (
+		template = emptyTemplate
+		tags     []*cloudformation.Tag
+	)
+	if options.NewStack {
+		for k, v := range options.Tags {
+			tags = append(tags, newTag(k, v))
+		}
+	} else {
+		s, err := c.DescribeStack(i)
+		if err != nil {
+			return err
+		}
+		if template, err = c.GetStackTemplate(options.StackName); err != nil {
+			return errors.Wrapf(err, "error getting stack template %s", options.StackName)
+		}
+		tags = s.Tags
+	}
+
+	template, err = addImportedResources(template, options.Resources)
+	if err != nil {
+		return errors.Wrapf(err, "adding resources to the template of stack %q", options.StackName)
+	}
+	logger.Debug("template = %s", template)
+
+	input := &cloudformation.CreateChangeSetInput{
+		StackName:     &options.StackName,
+		ChangeSetName: &options.ChangeSetName,
+		ChangeSetType: aws.String(cloudformation.ChangeSetTypeImport),
+		Description:   &options.Description,
+		// imported IAM roles have a RoleName, which requires CAPABILITY_NAMED_IAM
+		Capabilities: stackCapabilitiesNamedIAM,
+		Tags:         append(tags, c.sharedTags...),
+	}
+	templateData, tagsWithBucket, err := c.uploadIfTooLarge(options.StackName, TemplateBody(template), input.Tags)
+	if err != nil {
+		return err
+	}
+	input.Tags = tagsWithBucket
+	switch data := templateData.(type) {
+	case TemplateBody:
+		input.SetTemplateBody(string(data))
+	case TemplateURL:
+		input.SetTemplateURL(string(data))
+	}
+	if cfnRole := c.roleARN; cfnRole != "" {
+		input.SetRoleARN(cfnRole)
+	}
+	for _, r := range options.Resources {
+		input.ResourcesToImport = append(input.ResourcesToImport, &cloudformation.ResourceToImport{
+			LogicalResourceId:  aws.String(r.LogicalID),
+			ResourceType:       aws.String(r.ResourceType),
+			ResourceIdentifier: aws.StringMap(r.Identifier),
+		})
+	}
+
+	logger.Debug("creating changeSet, input = %#v", input)
+	if _, err := c.cloudformationAPI.CreateChangeSet(input); err != nil {
+		return errors.Wrapf(err, "creating ChangeSet %q for stack %q", options.ChangeSetName, options.StackName)
+	}
+	if options.Plan {
+		if options.NewStack {
+			// a stack created by an IMPORT ChangeSet stays in REVIEW_IN_PROGRESS until the ChangeSet is executed,
+			// deleting it deletes the ChangeSet as well
+			defer func() {
+				if _, deleteErr := c.DeleteStackByName(options.StackName); deleteErr != nil {
+					if err != nil {
+						logger.Warning("%v", deleteErr)
+						return
+					}
+					err = deleteErr
+				}
+			}()
+		} else {
+			defer c.deletePlanChangeSet(options.StackName, options.ChangeSetName, &err)
+		}
+	}
+	if err := c.doWaitUntilChangeSetIsCreated(ctx, i, options.ChangeSetName); err != nil {
+		return err
+	}
+
+	if options.Plan {
+		if err := c.previewChangeSet(i, options.ChangeSetName); err != nil {
+			return err
+		}
+		if len(options.Outputs) > 0 {
+			logger.Info("outputs %v will be added to stack %q once the resources are imported", sortedKeys(options.Outputs), options.StackName)
+		}
+		return nil
+	}
+
+	if err := c.doExecuteChangeSet(options.StackName, options.ChangeSetName); err != nil {
+		logger.Warning("error executing Cloudformation changeSet %s in stack %s. Check the Cloudformation console for further details", options.ChangeSetName, options.StackName)
+		return err
+	}
+	if err := c.doWaitUntilStackIsImported(ctx, i); err != nil {
+		return err
+	}
+	if len(options.Outputs) == 0 {
+		return nil
+	}
+
+	// an IMPORT ChangeSet can only add resources, so the outputs are added with an update
+	template, err = addOutputs(template, options.Outputs)
+	if err != nil {
+		return errors.Wrapf(err, "adding outputs to the template of stack %q", options.StackName)
+	}
+	return c.UpdateStack(ctx, UpdateStackOptions{
+		StackName:     options.StackName,
+		ChangeSetName: options.ChangeSetName + "-outputs",
+		Description:   fmt.Sprintf("adding outputs %v to stack %q", sortedKeys(options.Outputs), options.StackName),
+		TemplateData:  TemplateBody(template),
+		Wait:          true,
+	})
+}
+
+// addImportedResources adds the resources to the template with DeletionPolicy Retain, which
+// CloudFormation requires for every imported resource
+func addImportedResources(template string, resources []ResourceToImport) (string, error) {
+	if !gjson.Get(template, resourcesRootPath).IsObject() {
+		return "", errors.New("unexpected template format")
+	}
+	for _, r := range resources {
+		path := resourcesRootPath + "." + r.LogicalID
+		if gjson.Get(template, path).Exists() {
+			return "", fmt.Errorf("resource %q already exists in the template", r.LogicalID)
+		}
+		var err error
+		template, err = sjson.Set(template, path, map[string]interface{}{
+			"Type":           r.ResourceType,
+			"DeletionPolicy": "Retain",
+			"Properties":     r.Properties,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return template, nil
+}
+
+// addOutputs adds the outputs to the template, replacing any outputs with the same name
+func addOutputs(template string, outputs map[string]string) (string, error) {
+	for name, value := range outputs {
+		var err error
+		template, err = sjson.Set(template, "Outputs."+name, map[string]string{"Value": value})
+		if err != nil {
+			return "", err
+		}
+	}
+	return template, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
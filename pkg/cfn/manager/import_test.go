@@ -0,0 +1,107 @@
+package manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"github.com/tidwall/gjson"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("addImportedResources", func() {
+	resources := []ResourceToImport{{
+		LogicalID:    "VPC",
+		ResourceType: "AWS::EC2::VPC",
+		Identifier:   map[string]string{"VpcId": "vpc-1"},
+		Properties:   map[string]interface{}{"CidrBlock": "10.0.0.0/16"},
+	}}
+
+	It("adds the resources with DeletionPolicy Retain and keeps the existing ones", func() {
+		template, err := addImportedResources(`{"Resources": {"ControlPlane": {"Type": "AWS::EKS::Cluster"}}}`, resources)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gjson.Get(template, "Resources.ControlPlane.Type").String()).To(Equal("AWS::EKS::Cluster"))
+		Expect(gjson.Get(template, "Resources.VPC.Type").String()).To(Equal("AWS::EC2::VPC"))
+		Expect(gjson.Get(template, "Resources.VPC.DeletionPolicy").String()).To(Equal("Retain"))
+		Expect(gjson.Get(template, "Resources.VPC.Properties.CidrBlock").String()).To(Equal("10.0.0.0/16"))
+	})
+
+	It("fails when a resource with the same logical ID exists", func() {
+		_, err := addImportedResources(`{"Resources": {"VPC": {"Type": "AWS::EC2::VPC"}}}`, resources)
+		Expect(err).To(MatchError(`resource "VPC" already exists in the template`))
+	})
+})
+
+var _ = Describe("nodegroup stack with an imported auto scaling group", func() {
+	It("is listed like the nodegroups created by eksctl", func() {
+		template, err := addImportedResources(emptyTemplate, []ResourceToImport{{
+			LogicalID:    "NodeGroup",
+			ResourceType: "AWS::AutoScaling::AutoScalingGroup",
+			Identifier:   map[string]string{"AutoScalingGroupName": "ng-1"},
+			Properties: map[string]interface{}{
+				"AutoScalingGroupName": "ng-1",
+				"MinSize":              "1",
+				"MaxSize":              "3",
+				"DesiredCapacity":      "2",
+			},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		template, err = addOutputs(template, map[string]string{
+			outputs.NodeGroupInstanceRoleARN: "arn:aws:iam::123456789012:role/ng-1-role",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gjson.Get(template, "Outputs.InstanceRoleARN.Value").String()).To(Equal("arn:aws:iam::123456789012:role/ng-1-role"))
+
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		p := mockprovider.NewMockProvider()
+		sc := NewStackCollection(p, cfg)
+
+		stackName := "eksctl-test-nodegroup-ng-1"
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[1].(func(p *cfn.ListStacksOutput, last bool) (shouldContinue bool))
+			consume(&cfn.ListStacksOutput{StackSummaries: []*cfn.StackSummary{{StackName: aws.String(stackName)}}}, true)
+		}).Return(nil)
+		p.MockCloudFormation().On("DescribeStacks", mock.Anything).Return(&cfn.DescribeStacksOutput{
+			Stacks: []*cfn.Stack{{
+				StackName:   aws.String(stackName),
+				StackStatus: aws.String(cfn.StackStatusUpdateComplete),
+				Tags: []*cfn.Tag{
+					{Key: aws.String(api.NodeGroupNameTag), Value: aws.String("ng-1")},
+					{Key: aws.String(api.OldNodeGroupNameTag), Value: aws.String("ng-1")},
+					{Key: aws.String(api.NodeGroupTypeTag), Value: aws.String(string(api.NodeGroupTypeUnmanaged))},
+				},
+				Outputs: []*cfn.Output{{
+					OutputKey:   aws.String(outputs.NodeGroupInstanceRoleARN),
+					OutputValue: aws.String("arn:aws:iam::123456789012:role/ng-1-role"),
+				}},
+			}},
+		}, nil)
+		p.MockCloudFormation().On("GetTemplate", mock.Anything).Return(&cfn.GetTemplateOutput{
+			TemplateBody: aws.String(template),
+		}, nil)
+		p.MockCloudFormation().On("DescribeStackResource", mock.Anything).Return(&cfn.DescribeStackResourceOutput{
+			StackResourceDetail: &cfn.StackResourceDetail{PhysicalResourceId: aws.String("ng-1")},
+		}, nil)
+		p.MockASG().On("DescribeAutoScalingGroups", mock.Anything).Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []*autoscaling.Group{{
+				DesiredCapacity: aws.Int64(2),
+				MinSize:         aws.Int64(1),
+				MaxSize:         aws.Int64(3),
+			}},
+		}, nil)
+
+		summaries, err := sc.GetUnmanagedNodeGroupSummaries("ng-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summaries).To(HaveLen(1))
+		Expect(summaries[0].Name).To(Equal("ng-1"))
+		Expect(summaries[0].AutoScalingGroupName).To(Equal("ng-1"))
+		Expect(summaries[0].DesiredCapacity).To(Equal(2))
+		Expect(summaries[0].NodeInstanceRoleARN).To(Equal("arn:aws:iam::123456789012:role/ng-1-role"))
+	})
+})
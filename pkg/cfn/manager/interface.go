@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/cloudtrail"
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
@@ -21,6 +23,10 @@ type UpdateStackOptions struct {
 	TemplateData  TemplateData
 	Parameters    map[string]string
 	Wait          bool
+	// Plan creates the ChangeSet through the CloudFormation API and logs the resource changes
+	// it contains without executing it; the ChangeSet is always deleted afterwards, also when
+	// the preview fails or is interrupted
+	Plan bool
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -33,54 +39,60 @@ type StackManager interface {
 	DescribeNodeGroupStacks() ([]*Stack, error)
 	GetNodeGroupStackType(name string) (v1alpha5.NodeGroupType, error)
 	GetNodeGroupName(s *Stack) string
-	DoWaitUntilStackIsCreated(i *Stack) error
+	DoWaitUntilStackIsCreated(ctx context.Context, i *Stack) error
 	DoCreateStackRequest(i *Stack, templateData TemplateData, tags, parameters map[string]string, withIAM bool, withNamedIAM bool) error
-	CreateStack(name string, stack builder.ResourceSet, tags, parameters map[string]string, errs chan error) error
-	UpdateStack(options UpdateStackOptions) error
+	CreateStack(ctx context.Context, name string, stack builder.ResourceSet, tags, parameters map[string]string, errs chan error) error
+	CreatedStacks() []*Stack
+	UpdateStack(ctx context.Context, options UpdateStackOptions) error
+	ImportResources(ctx context.Context, options ImportResourcesOptions) error
 	DescribeStack(i *Stack) (*Stack, error)
 	GetManagedNodeGroupTemplate(nodeGroupName string) (string, error)
-	UpdateNodeGroupStack(nodeGroupName, template string, wait bool) error
+	UpdateNodeGroupStack(ctx context.Context, nodeGroupName, template string, wait, plan bool) error
 	ListStacksMatching(nameRegex string, statusFilters ...string) ([]*Stack, error)
 	ListClusterStackNames() ([]string, error)
 	ListStacks(statusFilters ...string) ([]*Stack, error)
 	StackStatusIsNotTransitional(s *Stack) bool
 	StackStatusIsNotReady(s *Stack) bool
 	DeleteStackByName(name string) (*Stack, error)
-	DeleteStackByNameSync(name string) error
+	DeleteStackByNameSync(ctx context.Context, name string) error
 	DeleteStackBySpec(s *Stack) (*Stack, error)
-	DeleteStackBySpecSync(s *Stack, errs chan error) error
+	DeleteStackBySpecSync(ctx context.Context, s *Stack, errs chan error) error
+	DeleteProtectedStackBySpecSync(ctx context.Context, s *Stack, errs chan error) error
 	DescribeStacks() ([]*Stack, error)
 	GetClusterStackIfExists() (*Stack, error)
 	HasClusterStackUsingCachedList(clusterStackNames []string) (bool, error)
 	DescribeStackEvents(i *Stack) ([]*cloudformation.StackEvent, error)
+	ListStackResources(i *Stack) ([]*cloudformation.StackResourceSummary, error)
 	LookupCloudTrailEvents(i *Stack) ([]*cloudtrail.Event, error)
 	DescribeStackChangeSet(i *Stack, changeSetName string) (*ChangeSet, error)
+	DetectStackDrift(ctx context.Context, s *Stack) (*StackDrift, error)
 	MakeChangeSetName(action string) string
 	DescribeClusterStack() (*Stack, error)
+	DeleteUploadedTemplates(stacks []*Stack) error
 	RefreshFargatePodExecutionRoleARN() error
-	AppendNewClusterStackResource(plan, supportsManagedNodes bool) (bool, error)
+	AppendNewClusterStackResource(ctx context.Context, plan, supportsManagedNodes bool) (bool, error)
 	GetFargateStack() (*Stack, error)
 	GetStackTemplate(stackName string) (string, error)
 	MakeClusterStackName() string
 	NewTasksToCreateClusterWithNodeGroups(nodeGroups []*v1alpha5.NodeGroup,
-		managedNodeGroups []*v1alpha5.ManagedNodeGroup, supportsManagedNodes bool, postClusterCreationTasks ...tasks.Task) *tasks.TaskTree
+		managedNodeGroups []*v1alpha5.ManagedNodeGroup, supportsManagedNodes bool, preNodeGroupTasks []tasks.Task, postClusterCreationTasks ...tasks.Task) (*tasks.TaskTree, error)
 	NewUnmanagedNodeGroupTask(nodeGroups []*v1alpha5.NodeGroup, forceAddCNIPolicy bool, importer vpc.Importer) *tasks.TaskTree
 	NewManagedNodeGroupTask(nodeGroups []*v1alpha5.ManagedNodeGroup, forceAddCNIPolicy bool, importer vpc.Importer) *tasks.TaskTree
 	NewClusterCompatTask() tasks.Task
 	NewTasksToCreateIAMServiceAccounts(serviceAccounts []*v1alpha5.ClusterIAMServiceAccount, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter) *tasks.TaskTree
 	DeleteTasksForDeprecatedStacks() (*tasks.TaskTree, error)
-	NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider bool, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter, wait bool, cleanup func(chan error, string) error) (*tasks.TaskTree, error)
-	NewTasksToDeleteNodeGroups(shouldDelete func(_ string) bool, wait bool, cleanup func(chan error, string) error) (*tasks.TaskTree, error)
+	NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider bool, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter, wait, disableProtection bool, cleanup func(chan error, string) error) (*tasks.TaskTree, error)
+	NewTasksToDeleteNodeGroups(shouldDelete func(_ string) bool, wait, disableProtection bool, cleanup func(chan error, string) error) (*tasks.TaskTree, error)
 	NewTaskToDeleteUnownedNodeGroup(clusterName, nodegroup string, eksAPI eksiface.EKSAPI, waitCondition *DeleteWaitCondition) tasks.Task
 	NewTasksToDeleteOIDCProviderWithIAMServiceAccounts(oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter) (*tasks.TaskTree, error)
 	NewTasksToDeleteIAMServiceAccounts(serviceAccounts []string, clientSetGetter kubernetes.ClientSetGetter, wait bool) (*tasks.TaskTree, error)
 	NewTaskToDeleteAddonIAM(wait bool) (*tasks.TaskTree, error)
-	FixClusterCompatibility() error
+	FixClusterCompatibility(ctx context.Context) error
 	DescribeIAMServiceAccountStacks() ([]*Stack, error)
 	ListIAMServiceAccountStacks() ([]string, error)
 	GetIAMServiceAccounts() ([]*v1alpha5.ClusterIAMServiceAccount, error)
 	GetIAMAddonsStacks() ([]*Stack, error)
 	GetIAMAddonName(s *Stack) string
-	EnsureMapPublicIPOnLaunchEnabled() error
+	EnsureMapPublicIPOnLaunchEnabled(ctx context.Context) error
 	GetAutoScalingGroupName(s *Stack) (string, error)
 }
@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/autoscaler"
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
 	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
 	"github.com/weaveworks/eksctl/pkg/version"
@@ -47,6 +49,8 @@ type NodeGroupSummary struct {
 type NodeGroupStack struct {
 	NodeGroupName string
 	Type          api.NodeGroupType
+	// PerAZGroup is the name of the nodegroup with perAvailabilityZone enabled this nodegroup was expanded from
+	PerAZGroup string
 }
 
 // makeNodeGroupStackName generates the name of the nodegroup stack identified by its name, isolated by the cluster this StackCollection operates on
@@ -55,7 +59,7 @@ func (c *StackCollection) makeNodeGroupStackName(name string) string {
 }
 
 // createNodeGroupTask creates the nodegroup
-func (c *StackCollection) createNodeGroupTask(errs chan error, ng *api.NodeGroup, forceAddCNIPolicy bool, vpcImporter vpc.Importer) error {
+func (c *StackCollection) createNodeGroupTask(ctx context.Context, errs chan error, ng *api.NodeGroup, forceAddCNIPolicy bool, vpcImporter vpc.Importer) error {
 	name := c.makeNodeGroupStackName(ng.Name)
 
 	logger.Info("building nodegroup stack %q", name)
@@ -75,10 +79,10 @@ func (c *StackCollection) createNodeGroupTask(errs chan error, ng *api.NodeGroup
 	ng.Tags[api.OldNodeGroupNameTag] = ng.Name
 	ng.Tags[api.NodeGroupTypeTag] = string(api.NodeGroupTypeUnmanaged)
 
-	return c.CreateStack(name, stack, ng.Tags, nil, errs)
+	return c.CreateStack(ctx, name, stack, ng.Tags, nil, errs)
 }
 
-func (c *StackCollection) createManagedNodeGroupTask(errorCh chan error, ng *api.ManagedNodeGroup, forceAddCNIPolicy bool, vpcImporter vpc.Importer) error {
+func (c *StackCollection) createManagedNodeGroupTask(ctx context.Context, errorCh chan error, ng *api.ManagedNodeGroup, forceAddCNIPolicy bool, vpcImporter vpc.Importer) error {
 	name := c.makeNodeGroupStackName(ng.Name)
 
 	logger.Info("building managed nodegroup stack %q", name)
@@ -88,7 +92,44 @@ func (c *StackCollection) createManagedNodeGroupTask(errorCh chan error, ng *api
 		return err
 	}
 
-	return c.CreateStack(name, stack, ng.Tags, nil, errorCh)
+	stackErrCh := make(chan error)
+	if err := c.CreateStack(ctx, name, stack, ng.Tags, nil, stackErrCh); err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(errorCh)
+		if err := <-stackErrCh; err != nil {
+			errorCh <- err
+			return
+		}
+		// the nodegroup is created, cluster-autoscaler only needs the tags to scale the nodegroup up from zero
+		if err := c.tagManagedNodeGroupASGs(ng); err != nil {
+			logger.Warning("failed to add the cluster-autoscaler node-template tags to the Auto Scaling group(s) of nodegroup %q: %v", ng.Name, err)
+		}
+		errorCh <- nil
+	}()
+	return nil
+}
+
+// tagManagedNodeGroupASGs adds the cluster-autoscaler node-template tags to the Auto Scaling groups
+// of a managed nodegroup; EKS owns those groups, so the tags cannot be set in the nodegroup stack
+func (c *StackCollection) tagManagedNodeGroupASGs(ng *api.ManagedNodeGroup) error {
+	gpus, err := autoscaler.GPUCount(c.ec2API, ng.InstanceTypeList())
+	if err != nil {
+		logger.Warning("not adding the GPU node-template tag for cluster-autoscaler to nodegroup %q: %v", ng.Name, err)
+	}
+	tags := autoscaler.NodeTemplateTags(ng, gpus)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	asgNames, err := autoscaler.ManagedNodeGroupASGs(c.eksAPI, c.spec.Metadata.Name, ng.Name)
+	if err != nil {
+		return err
+	}
+	logger.Info("adding cluster-autoscaler node-template tags to the Auto Scaling group(s) of nodegroup %q", ng.Name)
+	return autoscaler.TagASGs(c.asgAPI, asgNames, tags)
 }
 
 // DescribeNodeGroupStacks calls DescribeStacks and filters out nodegroups
@@ -134,6 +175,7 @@ func (c *StackCollection) ListNodeGroupStacks() ([]NodeGroupStack, error) {
 		nodeGroupStacks = append(nodeGroupStacks, NodeGroupStack{
 			NodeGroupName: c.GetNodeGroupName(stack),
 			Type:          nodeGroupType,
+			PerAZGroup:    GetNodeGroupPerAZGroup(stack.Tags),
 		})
 	}
 	return nodeGroupStacks, nil
@@ -468,3 +510,14 @@ func GetNodegroupTagName(tags []*cfn.Tag) string {
 	}
 	return ""
 }
+
+// GetNodeGroupPerAZGroup returns the name of the nodegroup with perAvailabilityZone enabled
+// a nodegroup stack was expanded from, if any
+func GetNodeGroupPerAZGroup(tags []*cfn.Tag) string {
+	for _, tag := range tags {
+		if *tag.Key == api.NodeGroupPerAZGroupTag {
+			return *tag.Value
+		}
+	}
+	return ""
+}
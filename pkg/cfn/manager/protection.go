@@ -0,0 +1,123 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
+)
+
+// CheckTerminationProtection returns a StackProtectedErr when any of the stacks has termination protection enabled
+func CheckTerminationProtection(stacks ...*Stack) error {
+	var protected []string
+	for _, s := range stacks {
+		if s != nil && api.IsEnabled(s.EnableTerminationProtection) {
+			protected = append(protected, *s.StackName)
+		}
+	}
+	if len(protected) > 0 {
+		return &StackProtectedErr{StackNames: protected}
+	}
+	return nil
+}
+
+// withTerminationProtection returns true when termination protection is configured for
+// the stack built from resourceSet, which is only the case for cluster and nodegroup stacks
+func (c *StackCollection) withTerminationProtection(resourceSet builder.ResourceSet) bool {
+	if p := c.spec.Metadata.Protection; p == nil || !api.IsEnabled(p.TerminationProtection) {
+		return false
+	}
+	switch resourceSet.(type) {
+	case *builder.ClusterResourceSet, *builder.NodeGroupResourceSet, *builder.ManagedNodeGroupResourceSet:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *StackCollection) disableTerminationProtection(s *Stack) error {
+	if !api.IsEnabled(s.EnableTerminationProtection) {
+		return nil
+	}
+	logger.Info("disabling termination protection of stack %q", *s.StackName)
+	if _, err := c.cloudformationAPI.UpdateTerminationProtection(&cloudformation.UpdateTerminationProtectionInput{
+		StackName:                   s.StackId,
+		EnableTerminationProtection: aws.Bool(false),
+	}); err != nil {
+		return errors.Wrapf(err, "disabling termination protection of stack %q", *s.StackName)
+	}
+	return nil
+}
+
+// reconcileTerminationProtection updates termination protection of a cluster or nodegroup stack being updated
+// to match the config; protection is only disabled when the config disables it explicitly, as configs built
+// from flags, e.g. by `eksctl upgrade cluster --name`, don't carry the protection settings
+func (c *StackCollection) reconcileTerminationProtection(s *Stack, plan bool) error {
+	if getClusterName(s) == "" && c.GetNodeGroupName(s) == "" {
+		return nil
+	}
+	p := c.spec.Metadata.Protection
+	if p == nil || p.TerminationProtection == nil {
+		return nil
+	}
+	enable := api.IsEnabled(p.TerminationProtection)
+	if enable == api.IsEnabled(s.EnableTerminationProtection) {
+		return nil
+	}
+	action := "disabling"
+	if enable {
+		action = "enabling"
+	}
+	if plan {
+		logger.Info("(plan) %s termination protection of stack %q", action, *s.StackName)
+		return nil
+	}
+	logger.Info("%s termination protection of stack %q", action, *s.StackName)
+	if _, err := c.cloudformationAPI.UpdateTerminationProtection(&cloudformation.UpdateTerminationProtectionInput{
+		StackName:                   s.StackId,
+		EnableTerminationProtection: aws.Bool(enable),
+	}); err != nil {
+		return errors.Wrapf(err, "%s termination protection of stack %q", action, *s.StackName)
+	}
+	return nil
+}
+
+// deleteProtectedStackBySpec disables termination protection of the stack before deleting it
+func (c *StackCollection) deleteProtectedStackBySpec(s *Stack) (*Stack, error) {
+	if err := c.disableTerminationProtection(s); err != nil {
+		return nil, err
+	}
+	return c.DeleteStackBySpec(s)
+}
+
+// DeleteProtectedStackBySpecSync disables termination protection of the stack before deleting it
+// and waiting for the deletion
+func (c *StackCollection) DeleteProtectedStackBySpecSync(ctx context.Context, s *Stack, errs chan error) error {
+	if err := c.disableTerminationProtection(s); err != nil {
+		return err
+	}
+	return c.DeleteStackBySpecSync(ctx, s, errs)
+}
+
+// newDeleteStackTask returns the task deleting the stack, which is expected
+// to have been checked using CheckTerminationProtection
+func (c *StackCollection) newDeleteStackTask(info string, s *Stack, wait bool) tasks.Task {
+	if wait {
+		return &taskWithStackSpec{
+			info:  info,
+			stack: s,
+			call:  c.DeleteProtectedStackBySpecSync,
+		}
+	}
+	return &asyncTaskWithStackSpec{
+		info:  info,
+		stack: s,
+		call:  c.deleteProtectedStackBySpec,
+	}
+}
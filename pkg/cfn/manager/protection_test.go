@@ -0,0 +1,126 @@
+package manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Termination protection", func() {
+	var (
+		p  *mockprovider.MockProvider
+		sc *StackCollection
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		sc = NewStackCollection(p, cfg)
+
+		const stackName = "eksctl-test-nodegroup-ng-1"
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			pager := args.Get(1).(func(*cfn.ListStacksOutput, bool) bool)
+			pager(&cfn.ListStacksOutput{StackSummaries: []*cfn.StackSummary{{StackName: aws.String(stackName)}}}, true)
+		}).Return(nil)
+		p.MockCloudFormation().On("DescribeStacks", mock.Anything).Return(&cfn.DescribeStacksOutput{
+			Stacks: []*cfn.Stack{{
+				StackName:                   aws.String(stackName),
+				StackId:                     aws.String(stackName + "-id"),
+				StackStatus:                 aws.String(cfn.StackStatusCreateComplete),
+				EnableTerminationProtection: aws.Bool(true),
+				Tags: []*cfn.Tag{
+					{Key: aws.String(api.ClusterNameTag), Value: aws.String("test")},
+					{Key: aws.String(api.NodeGroupNameTag), Value: aws.String("ng-1")},
+				},
+			}},
+		}, nil)
+	})
+
+	It("refuses to delete protected nodegroup stacks", func() {
+		_, err := sc.NewTasksToDeleteNodeGroups(func(string) bool { return true }, false, false, nil)
+		Expect(err).To(MatchError(`termination protection is enabled for stack(s) eksctl-test-nodegroup-ng-1, use --disable-protection to delete them`))
+	})
+
+	It("ignores protected stacks that are not deleted", func() {
+		taskTree, err := sc.NewTasksToDeleteNodeGroups(func(string) bool { return false }, false, false, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(taskTree.Len()).To(Equal(0))
+	})
+
+	It("disables termination protection before deleting the stack", func() {
+		p.MockCloudFormation().On("UpdateTerminationProtection", mock.MatchedBy(func(input *cfn.UpdateTerminationProtectionInput) bool {
+			return *input.StackName == "eksctl-test-nodegroup-ng-1-id" && !*input.EnableTerminationProtection
+		})).Return(&cfn.UpdateTerminationProtectionOutput{}, nil)
+		p.MockCloudFormation().On("DeleteStack", mock.Anything).Return(&cfn.DeleteStackOutput{}, nil)
+
+		taskTree, err := sc.NewTasksToDeleteNodeGroups(func(string) bool { return true }, false, true, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(taskTree.DoAllSync()).To(BeEmpty())
+
+		var calls []string
+		for _, call := range p.MockCloudFormation().Calls {
+			calls = append(calls, call.Method)
+		}
+		Expect(calls).To(ContainElements("UpdateTerminationProtection", "DeleteStack"))
+		Expect(calls[len(calls)-1]).To(Equal("DeleteStack"))
+	})
+
+	Context("reconciling termination protection of updated stacks", func() {
+		nodeGroupStack := func(protected bool) *Stack {
+			return &Stack{
+				StackName:                   aws.String("eksctl-test-nodegroup-ng-1"),
+				StackId:                     aws.String("eksctl-test-nodegroup-ng-1-id"),
+				EnableTerminationProtection: aws.Bool(protected),
+				Tags:                        []*cfn.Tag{{Key: aws.String(api.NodeGroupNameTag), Value: aws.String("ng-1")}},
+			}
+		}
+
+		BeforeEach(func() {
+			p.MockCloudFormation().On("UpdateTerminationProtection", mock.Anything).Return(&cfn.UpdateTerminationProtectionOutput{}, nil)
+		})
+
+		It("enables termination protection when the config enables it", func() {
+			sc.spec.Metadata.Protection = &api.ClusterProtection{TerminationProtection: aws.Bool(true)}
+			Expect(sc.reconcileTerminationProtection(nodeGroupStack(false), false)).To(Succeed())
+			p.MockCloudFormation().AssertCalled(GinkgoT(), "UpdateTerminationProtection", &cfn.UpdateTerminationProtectionInput{
+				StackName:                   aws.String("eksctl-test-nodegroup-ng-1-id"),
+				EnableTerminationProtection: aws.Bool(true),
+			})
+		})
+
+		It("disables termination protection when the config disables it", func() {
+			sc.spec.Metadata.Protection = &api.ClusterProtection{TerminationProtection: aws.Bool(false)}
+			Expect(sc.reconcileTerminationProtection(nodeGroupStack(true), false)).To(Succeed())
+			p.MockCloudFormation().AssertCalled(GinkgoT(), "UpdateTerminationProtection", &cfn.UpdateTerminationProtectionInput{
+				StackName:                   aws.String("eksctl-test-nodegroup-ng-1-id"),
+				EnableTerminationProtection: aws.Bool(false),
+			})
+		})
+
+		It("leaves termination protection alone when the config doesn't set it", func() {
+			Expect(sc.reconcileTerminationProtection(nodeGroupStack(true), false)).To(Succeed())
+			p.MockCloudFormation().AssertNotCalled(GinkgoT(), "UpdateTerminationProtection", mock.Anything)
+		})
+
+		It("only plans the change in plan mode", func() {
+			sc.spec.Metadata.Protection = &api.ClusterProtection{TerminationProtection: aws.Bool(true)}
+			Expect(sc.reconcileTerminationProtection(nodeGroupStack(false), true)).To(Succeed())
+			p.MockCloudFormation().AssertNotCalled(GinkgoT(), "UpdateTerminationProtection", mock.Anything)
+		})
+
+		It("ignores stacks that are neither cluster nor nodegroup stacks", func() {
+			sc.spec.Metadata.Protection = &api.ClusterProtection{TerminationProtection: aws.Bool(true)}
+			Expect(sc.reconcileTerminationProtection(&Stack{
+				StackName: aws.String("eksctl-test-addon-iamserviceaccount-default-s3-reader"),
+				Tags:      []*cfn.Tag{{Key: aws.String(api.IAMServiceAccountNameTag), Value: aws.String("default/s3-reader")}},
+			}, false)).To(Succeed())
+			p.MockCloudFormation().AssertNotCalled(GinkgoT(), "UpdateTerminationProtection", mock.Anything)
+		})
+	})
+})
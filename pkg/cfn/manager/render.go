@@ -0,0 +1,335 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
+	"github.com/weaveworks/eksctl/pkg/vpc"
+)
+
+const (
+	// UnresolvedValue is used in rendered templates in place of values that can only be looked up from AWS
+	UnresolvedValue = "UNRESOLVED"
+
+	// TemplateManifestFile is the name of the file listing all rendered stacks
+	TemplateManifestFile = "manifest.json"
+
+	unresolvedEndpoint = "https://" + UnresolvedValue
+)
+
+// RenderedStack is the template of a stack along with the settings it would be created with
+type RenderedStack struct {
+	StackName    string            `json:"stackName"`
+	TemplateFile string            `json:"templateFile"`
+	Tags         map[string]string `json:"tags"`
+	Parameters   map[string]string `json:"parameters,omitempty"`
+	Capabilities []string          `json:"capabilities,omitempty"`
+
+	TemplateBody []byte `json:"-"`
+}
+
+// Unresolved describes a value that was left out of a rendered template as it can only be looked up from AWS
+type Unresolved struct {
+	Stack  string `json:"stack,omitempty"`
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// TemplateManifest lists the stacks rendered for a cluster
+type TemplateManifest struct {
+	Cluster    string           `json:"cluster"`
+	Region     string           `json:"region"`
+	Stacks     []*RenderedStack `json:"stacks"`
+	Unresolved []Unresolved     `json:"unresolved,omitempty"`
+}
+
+// Write writes every template to dir, along with the manifest
+func (m *TemplateManifest) Write(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "creating directory %q", dir)
+	}
+	for _, s := range m.Stacks {
+		if err := os.WriteFile(filepath.Join(dir, s.TemplateFile), s.TemplateBody, 0644); err != nil {
+			return errors.Wrapf(err, "writing template for stack %q", s.StackName)
+		}
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, TemplateManifestFile), append(data, '\n'), 0644)
+}
+
+// TemplateRenderer renders the templates of the stacks that eksctl would create for a cluster,
+// without calling any AWS APIs; values that would otherwise be looked up are either taken from
+// the config or replaced with UnresolvedValue and recorded in the manifest
+type TemplateRenderer struct {
+	stackCollection *StackCollection
+	manifest        *TemplateManifest
+}
+
+// NewTemplateRenderer creates a TemplateRenderer for the given cluster, spec.Metadata.Region must be set
+func NewTemplateRenderer(spec *api.ClusterConfig) *TemplateRenderer {
+	r := &TemplateRenderer{
+		manifest: &TemplateManifest{
+			Cluster: spec.Metadata.Name,
+			Region:  spec.Metadata.Region,
+		},
+	}
+	r.stackCollection = &StackCollection{
+		spec:       spec,
+		sharedTags: makeSharedTags(spec),
+		region:     spec.Metadata.Region,
+		ec2API:     &offlineEC2API{renderer: r, spec: spec},
+	}
+	return r
+}
+
+// Manifest returns the manifest of all stacks rendered so far
+func (r *TemplateRenderer) Manifest() *TemplateManifest {
+	return r.manifest
+}
+
+// RenderClusterStack renders the cluster stack
+func (r *TemplateRenderer) RenderClusterStack(supportsManagedNodes bool) error {
+	c := r.stackCollection
+	name := c.MakeClusterStackName()
+	logger.Info("rendering cluster stack %q", name)
+	stack := builder.NewClusterResourceSet(c.ec2API, c.region, c.spec, supportsManagedNodes, nil)
+	if err := stack.AddAllResources(); err != nil {
+		return errors.Wrapf(err, "rendering stack %q", name)
+	}
+	return r.addStack(name, stack, nil)
+}
+
+// RenderNodeGroupStacks renders a stack for each of the given nodegroups; the nodegroups import
+// the VPC and security groups from the outputs of the cluster stack
+func (r *TemplateRenderer) RenderNodeGroupStacks(nodeGroups []*api.NodeGroup, managedNodeGroups []*api.ManagedNodeGroup, forceAddCNIPolicy bool) error {
+	c := r.stackCollection
+	vpcImporter := vpc.NewStackConfigImporter(c.MakeClusterStackName())
+	if len(nodeGroups) > 0 || len(managedNodeGroups) > 0 {
+		r.setUnresolvedClusterStatus()
+	}
+
+	for _, ng := range nodeGroups {
+		name := c.makeNodeGroupStackName(ng.Name)
+		logger.Info("rendering nodegroup stack %q", name)
+		if api.IsAMI(ng.AMI) {
+			ng.CustomAMI = true
+		}
+		r.resolveNodeGroup(name, ng.NodeGroupBase, !ng.CustomAMI)
+
+		bootstrapper, err := nodebootstrap.NewBootstrapper(c.spec, ng)
+		if err != nil {
+			return errors.Wrap(err, "error creating bootstrapper")
+		}
+		stack := builder.NewNodeGroupResourceSet(c.ec2API, nil, c.spec, ng, bootstrapper, forceAddCNIPolicy, vpcImporter)
+		if err := stack.AddAllResources(); err != nil {
+			return errors.Wrapf(err, "rendering stack %q", name)
+		}
+
+		if ng.Tags == nil {
+			ng.Tags = make(map[string]string)
+		}
+		ng.Tags[api.NodeGroupNameTag] = ng.Name
+		ng.Tags[api.OldNodeGroupNameTag] = ng.Name
+		ng.Tags[api.NodeGroupTypeTag] = string(api.NodeGroupTypeUnmanaged)
+		if err := r.addStack(name, stack, ng.Tags); err != nil {
+			return err
+		}
+	}
+
+	for _, ng := range managedNodeGroups {
+		name := c.makeNodeGroupStackName(ng.Name)
+		logger.Info("rendering managed nodegroup stack %q", name)
+		hasNativeAMIFamilySupport := ng.AMIFamily == api.NodeImageFamilyAmazonLinux2 || ng.AMIFamily == api.NodeImageFamilyBottlerocket
+		r.resolveNodeGroup(name, ng.NodeGroupBase, !hasNativeAMIFamilySupport && !api.IsAMI(ng.AMI))
+
+		bootstrapper := nodebootstrap.NewManagedBootstrapper(c.spec, ng)
+		stack := builder.NewManagedNodeGroup(c.ec2API, c.spec, ng, builder.NewLaunchTemplateFetcher(c.ec2API), bootstrapper, forceAddCNIPolicy, vpcImporter)
+		if err := stack.AddAllResources(); err != nil {
+			return errors.Wrapf(err, "rendering stack %q", name)
+		}
+		if err := r.addStack(name, stack, ng.Tags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderIAMServiceAccountStacks renders a stack for the IAM role of each iamserviceaccount; as the OIDC
+// provider is only created along with the cluster, its ARN is left unresolved
+func (r *TemplateRenderer) RenderIAMServiceAccountStacks(serviceAccounts []*api.ClusterIAMServiceAccount) error {
+	c := r.stackCollection
+	partition := api.Partition(c.region)
+	issuerHostnameAndPath := fmt.Sprintf("oidc.eks.%s.amazonaws.com/id/%s", c.region, UnresolvedValue)
+	oidc, err := iamoidc.NewOpenIDConnectManager(nil, UnresolvedValue, "https://"+issuerHostnameAndPath, partition, nil)
+	if err != nil {
+		return err
+	}
+	oidc.ProviderARN = fmt.Sprintf("arn:%s:iam::%s:oidc-provider/%s", partition, UnresolvedValue, issuerHostnameAndPath)
+
+	for _, sa := range serviceAccounts {
+		if sa.AttachRoleARN != "" {
+			continue
+		}
+		name := c.makeIAMServiceAccountStackName(sa.Namespace, sa.Name)
+		logger.Info("rendering iamserviceaccount stack %q", name)
+		stack := builder.NewIAMRoleResourceSetForServiceAccount(sa, oidc)
+		if err := stack.AddAllResources(); err != nil {
+			return errors.Wrapf(err, "rendering stack %q", name)
+		}
+		r.MarkUnresolved(name, "oidcProviderARN", "the IAM OIDC provider is only created once the control plane has been created")
+
+		tags := map[string]string{
+			api.IAMServiceAccountNameTag: sa.NameString(),
+		}
+		for k, v := range sa.Tags {
+			tags[k] = v
+		}
+		if err := r.addStack(name, stack, tags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TemplateRenderer) addStack(name string, resourceSet builder.ResourceSet, tags map[string]string) error {
+	templateBody, err := resourceSet.RenderJSON()
+	if err != nil {
+		return errors.Wrapf(err, "rendering template for %q stack", name)
+	}
+
+	stack := &RenderedStack{
+		StackName:    name,
+		TemplateFile: name + ".json",
+		Tags:         map[string]string{},
+		TemplateBody: templateBody,
+	}
+	for _, tag := range r.stackCollection.sharedTags {
+		stack.Tags[*tag.Key] = *tag.Value
+	}
+	for k, v := range tags {
+		stack.Tags[k] = v
+	}
+	if resourceSet.WithIAM() {
+		stack.Capabilities = aws.StringValueSlice(stackCapabilitiesIAM)
+	}
+	if resourceSet.WithNamedIAM() {
+		stack.Capabilities = aws.StringValueSlice(stackCapabilitiesNamedIAM)
+	}
+	r.manifest.Stacks = append(r.manifest.Stacks, stack)
+	return nil
+}
+
+// MarkUnresolved records a value that could not be rendered in the manifest
+func (r *TemplateRenderer) MarkUnresolved(stack, field, reason string) {
+	r.manifest.Unresolved = append(r.manifest.Unresolved, Unresolved{
+		Stack:  stack,
+		Field:  field,
+		Reason: reason,
+	})
+}
+
+// setUnresolvedClusterStatus fills in the cluster endpoint and CA, which nodegroup userdata depends on,
+// unless they were already loaded from an existing cluster
+func (r *TemplateRenderer) setUnresolvedClusterStatus() {
+	spec := r.stackCollection.spec
+	if spec.Status == nil {
+		spec.Status = &api.ClusterStatus{}
+	}
+	if spec.Status.Endpoint != "" {
+		return
+	}
+	spec.Status.Endpoint = unresolvedEndpoint
+	spec.Status.CertificateAuthorityData = []byte(UnresolvedValue)
+	r.MarkUnresolved("", "status.endpoint", "the API server endpoint can only be read from the EKS API")
+	r.MarkUnresolved("", "status.certificateAuthorityData", "the cluster CA can only be read from the EKS API")
+}
+
+// resolveNodeGroup replaces the settings of a nodegroup that are normally looked up while
+// creating it, see NodeGroupService.Normalize
+func (r *TemplateRenderer) resolveNodeGroup(stackName string, ng *api.NodeGroupBase, needsAMI bool) {
+	if needsAMI {
+		resolver := ng.AMI
+		if resolver == "" {
+			resolver = api.NodeImageResolverAutoSSM
+		}
+		ng.AMI = UnresolvedValue
+		r.MarkUnresolved(stackName, "ami", fmt.Sprintf("the AMI for %s %s is resolved using the %q resolver; set an AMI ID to render it", ng.AMIFamily, r.stackCollection.spec.Metadata.Version, resolver))
+	} else if api.IsAMI(ng.AMI) && !api.IsSetAndNonEmptyString(ng.VolumeName) && ng.AMIFamily != api.NodeImageFamilyBottlerocket {
+		r.MarkUnresolved(stackName, "volumeName", fmt.Sprintf("the root device name of %s is read from the AMI; set volumeName to render it", ng.AMI))
+	}
+
+	if api.IsEnabled(ng.SSH.Allow) && !api.IsSetAndNonEmptyString(ng.SSH.PublicKeyName) {
+		ng.SSH.PublicKeyName = aws.String(UnresolvedValue)
+		r.MarkUnresolved(stackName, "ssh.publicKeyName", "the public key is imported into EC2 when the nodegroup is created; set publicKeyName to render it")
+	}
+
+	if r.stackCollection.spec.VPC.ID == "" && len(ng.AvailabilityZones) > 0 {
+		r.MarkUnresolved(stackName, "availabilityZones", "the IDs of subnets in a new VPC are only known once the cluster stack has been created")
+	}
+}
+
+// offlineEC2API answers the EC2 calls made while building templates without calling AWS
+type offlineEC2API struct {
+	ec2iface.EC2API
+	renderer *TemplateRenderer
+	spec     *api.ClusterConfig
+}
+
+func errOffline(operation string) error {
+	return fmt.Errorf("%s requires access to AWS and cannot be used when rendering templates offline", operation)
+}
+
+// DescribeVpcEndpointServices assumes that every requested endpoint service is available in all of the cluster's zones
+func (o *offlineEC2API) DescribeVpcEndpointServices(input *ec2.DescribeVpcEndpointServicesInput) (*ec2.DescribeVpcEndpointServicesOutput, error) {
+	s3ServiceName := fmt.Sprintf("com.amazonaws.%s.%s", o.spec.Metadata.Region, api.EndpointServiceS3)
+	output := &ec2.DescribeVpcEndpointServicesOutput{}
+	for _, serviceName := range input.ServiceNames {
+		serviceType := ec2.ServiceTypeInterface
+		if *serviceName == s3ServiceName {
+			serviceType = ec2.ServiceTypeGateway
+		}
+		output.ServiceDetails = append(output.ServiceDetails, &ec2.ServiceDetail{
+			ServiceName:       serviceName,
+			ServiceType:       []*ec2.ServiceTypeDetail{{ServiceType: aws.String(serviceType)}},
+			AvailabilityZones: aws.StringSlice(o.spec.AvailabilityZones),
+		})
+	}
+	o.renderer.MarkUnresolved(o.renderer.stackCollection.MakeClusterStackName(), "privateCluster.additionalEndpointServices",
+		"VPC endpoints are assumed to be available in all of the cluster's availability zones")
+	return output, nil
+}
+
+// DescribeSubnets is not supported offline
+func (o *offlineEC2API) DescribeSubnets(*ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+	return nil, errOffline("looking up subnets")
+}
+
+// DescribeRouteTables is not supported offline
+func (o *offlineEC2API) DescribeRouteTables(*ec2.DescribeRouteTablesInput) (*ec2.DescribeRouteTablesOutput, error) {
+	return nil, errOffline("looking up route tables")
+}
+
+// DescribeInstanceTypes is not supported offline
+func (o *offlineEC2API) DescribeInstanceTypes(*ec2.DescribeInstanceTypesInput) (*ec2.DescribeInstanceTypesOutput, error) {
+	return nil, errOffline("looking up instance types (e.g. for EFA)")
+}
+
+// DescribeLaunchTemplateVersions is not supported offline
+func (o *offlineEC2API) DescribeLaunchTemplateVersions(*ec2.DescribeLaunchTemplateVersionsInput) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+	return nil, errOffline("using an existing launch template")
+}
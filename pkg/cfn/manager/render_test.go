@@ -0,0 +1,117 @@
+package manager
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/vpc"
+)
+
+var _ = Describe("TemplateRenderer", func() {
+	var (
+		cfg      *api.ClusterConfig
+		renderer *TemplateRenderer
+	)
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		cfg.Metadata.Region = "us-west-2"
+		cfg.Metadata.Version = api.DefaultVersion
+		cfg.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+
+		ng := cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.InstanceType = "m5.large"
+		api.SetNodeGroupDefaults(ng, cfg.Metadata)
+
+		mng := api.NewManagedNodeGroup()
+		mng.Name = "mng-1"
+		api.SetManagedNodeGroupDefaults(mng, cfg.Metadata)
+		cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, mng)
+
+		api.SetClusterConfigDefaults(cfg)
+		Expect(vpc.SetSubnets(cfg.VPC, cfg.AvailabilityZones)).To(Succeed())
+
+		renderer = NewTemplateRenderer(cfg)
+	})
+
+	It("renders the cluster and nodegroup stacks without calling AWS", func() {
+		Expect(renderer.RenderClusterStack(true)).To(Succeed())
+		Expect(renderer.RenderNodeGroupStacks(cfg.NodeGroups, cfg.ManagedNodeGroups, false)).To(Succeed())
+
+		manifest := renderer.Manifest()
+		Expect(manifest.Cluster).To(Equal("test-cluster"))
+		Expect(manifest.Region).To(Equal("us-west-2"))
+
+		var names []string
+		for _, s := range manifest.Stacks {
+			names = append(names, s.StackName)
+			Expect(s.Tags).To(HaveKeyWithValue(api.ClusterNameTag, "test-cluster"))
+			Expect(s.Capabilities).To(ConsistOf("CAPABILITY_IAM"))
+		}
+		Expect(names).To(Equal([]string{
+			"eksctl-test-cluster-cluster",
+			"eksctl-test-cluster-nodegroup-ng-1",
+			"eksctl-test-cluster-nodegroup-mng-1",
+		}))
+		Expect(manifest.Stacks[1].Tags).To(HaveKeyWithValue(api.NodeGroupTypeTag, string(api.NodeGroupTypeUnmanaged)))
+
+		By("marking the values that can only be looked up from AWS as unresolved")
+		Expect(cfg.NodeGroups[0].AMI).To(Equal(UnresolvedValue))
+		Expect(manifest.Unresolved).To(ContainElements(
+			Unresolved{Field: "status.endpoint", Reason: "the API server endpoint can only be read from the EKS API"},
+			Unresolved{Stack: "eksctl-test-cluster-nodegroup-ng-1", Field: "ami", Reason: `the AMI for AmazonLinux2 ` + api.DefaultVersion + ` is resolved using the "auto-ssm" resolver; set an AMI ID to render it`},
+		))
+		Expect(unresolvedStacks(manifest)).NotTo(ContainElement("eksctl-test-cluster-nodegroup-mng-1"))
+	})
+
+	It("uses the AMI when one is set", func() {
+		volumeName := "/dev/xvda"
+		cfg.ManagedNodeGroups[0].AMI = "ami-123"
+		cfg.ManagedNodeGroups[0].VolumeName = &volumeName
+		Expect(renderer.RenderNodeGroupStacks(nil, cfg.ManagedNodeGroups, false)).To(Succeed())
+
+		Expect(string(renderer.Manifest().Stacks[0].TemplateBody)).To(ContainSubstring("ami-123"))
+		Expect(unresolvedStacks(renderer.Manifest())).NotTo(ContainElement("eksctl-test-cluster-nodegroup-mng-1"))
+	})
+
+	It("fails when a lookup cannot be done offline", func() {
+		cfg.ManagedNodeGroups[0].LaunchTemplate = &api.LaunchTemplate{ID: "lt-123"}
+		err := renderer.RenderNodeGroupStacks(nil, cfg.ManagedNodeGroups, false)
+		Expect(err).To(MatchError(ContainSubstring("cannot be used when rendering templates offline")))
+	})
+
+	It("writes the templates and the manifest", func() {
+		Expect(renderer.RenderClusterStack(true)).To(Succeed())
+		dir, err := os.MkdirTemp("", "templates")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		Expect(renderer.Manifest().Write(dir)).To(Succeed())
+
+		template, err := os.ReadFile(filepath.Join(dir, "eksctl-test-cluster-cluster.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(template)).To(ContainSubstring("AWS::EKS::Cluster"))
+
+		data, err := os.ReadFile(filepath.Join(dir, TemplateManifestFile))
+		Expect(err).NotTo(HaveOccurred())
+		var manifest TemplateManifest
+		Expect(json.Unmarshal(data, &manifest)).To(Succeed())
+		Expect(manifest.Stacks).To(HaveLen(1))
+		Expect(manifest.Stacks[0].TemplateFile).To(Equal("eksctl-test-cluster-cluster.json"))
+	})
+})
+
+func unresolvedStacks(manifest *TemplateManifest) []string {
+	var stacks []string
+	for _, u := range manifest.Unresolved {
+		stacks = append(stacks, u.Stack)
+	}
+	return stacks
+}
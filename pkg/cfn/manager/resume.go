@@ -0,0 +1,70 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
+)
+
+// stackToResume returns the stack named stackName when an operation is being resumed and the
+// stack was created by the interrupted run, so that the caller waits for it instead of creating
+// it; stacks the interrupted run failed to create are deleted, so that they are created again,
+// and stacks in any other state are left alone and returned as an error
+func (c *StackCollection) stackToResume(ctx context.Context, stackName string) (*Stack, error) {
+	journal := tasks.JournalFromContext(ctx)
+	if !journal.Resuming() {
+		return nil, nil
+	}
+
+	s, err := c.DescribeStack(&Stack{StackName: &stackName})
+	if err != nil {
+		if isStackDoesNotExistErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	switch status := *s.StackStatus; status {
+	case cloudformation.StackStatusCreateInProgress, cloudformation.StackStatusCreateComplete,
+		cloudformation.StackStatusUpdateInProgress, cloudformation.StackStatusUpdateComplete,
+		cloudformation.StackStatusUpdateCompleteCleanupInProgress:
+		logger.Info("resuming with existing stack %q (%s)", stackName, status)
+		c.recordCreatedStack(s)
+		return s, nil
+	case cloudformation.StackStatusDeleteInProgress:
+		logger.Info("waiting for stack %q to be deleted before creating it again", stackName)
+		return nil, c.doWaitUntilStackIsDeleted(ctx, s)
+	case cloudformation.StackStatusCreateFailed, cloudformation.StackStatusRollbackInProgress,
+		cloudformation.StackStatusRollbackFailed, cloudformation.StackStatusRollbackComplete:
+		if id := journal.StackID(stackName); id == "" || id != *s.StackId {
+			return nil, fmt.Errorf("stack %q (%s) is not recorded as created by the operation being resumed, "+
+				"delete it before resuming the operation", stackName, status)
+		}
+		if api.IsEnabled(s.EnableTerminationProtection) {
+			logger.Warning("disabling termination protection of stack %q (%s), which was created by the operation being resumed, to create it again",
+				stackName, status)
+		}
+		logger.Info("deleting stack %q (%s) to create it again", stackName, status)
+		errs := make(chan error)
+		if err := c.DeleteProtectedStackBySpecSync(ctx, s, errs); err != nil {
+			return nil, err
+		}
+		return nil, <-errs
+	default:
+		return nil, fmt.Errorf("unable to resume the creation of stack %q in state %s, "+
+			"delete the stack or wait for it to be in a stable state before resuming the operation", stackName, status)
+	}
+}
+
+func isStackDoesNotExistErr(err error) bool {
+	awsErr, ok := errors.Cause(err).(awserr.Error)
+	return ok && awsErr.Code() == "ValidationError" && strings.Contains(awsErr.Message(), "does not exist")
+}
@@ -0,0 +1,155 @@
+package manager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/awstesting"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
+)
+
+var _ = Describe("Resuming stack creation", func() {
+	const stackName = "eksctl-test-nodegroup-ng-1"
+
+	var (
+		p         *mockprovider.MockProvider
+		sc        *StackCollection
+		resumeCtx context.Context
+		dir       string
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		sc = NewStackCollection(p, cfg)
+
+		var err error
+		dir, err = os.MkdirTemp("", "journal")
+		Expect(err).NotTo(HaveOccurred())
+		store := &tasks.FileJournalStore{Path: filepath.Join(dir, "journal.json")}
+		Expect(store.Save([]byte(`{"tasks": {}}`))).To(Succeed())
+		journal, err := tasks.LoadJournal(store)
+		Expect(err).NotTo(HaveOccurred())
+		resumeCtx = tasks.WithJournal(context.Background(), journal)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("does not look for existing stacks when not resuming", func() {
+		s, err := sc.stackToResume(context.Background(), stackName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).To(BeNil())
+		Expect(p.MockCloudFormation().Calls).To(BeEmpty())
+	})
+
+	It("resumes with a stack that is being created", func() {
+		p.MockCloudFormation().On("DescribeStacks", mock.Anything).Return(&cfn.DescribeStacksOutput{
+			Stacks: []*cfn.Stack{{
+				StackName:   aws.String(stackName),
+				StackId:     aws.String(stackName + "-id"),
+				StackStatus: aws.String(cfn.StackStatusCreateInProgress),
+			}},
+		}, nil)
+
+		s, err := sc.stackToResume(resumeCtx, stackName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*s.StackId).To(Equal(stackName + "-id"))
+		Expect(sc.CreatedStacks()).To(ConsistOf(s))
+	})
+
+	describeStack := func(id, status string) {
+		p.MockCloudFormation().On("DescribeStacks", mock.Anything).Return(&cfn.DescribeStacksOutput{
+			Stacks: []*cfn.Stack{{
+				StackName:                   aws.String(stackName),
+				StackId:                     aws.String(id),
+				StackStatus:                 aws.String(status),
+				EnableTerminationProtection: aws.Bool(true),
+				Tags:                        []*cfn.Tag{{Key: aws.String(api.ClusterNameTag), Value: aws.String("test")}},
+			}},
+		}, nil)
+	}
+
+	It("deletes stacks the interrupted run failed to create", func() {
+		tasks.JournalFromContext(resumeCtx).RecordStack(stackName, stackName+"-id")
+		describeStack(stackName+"-id", cfn.StackStatusRollbackComplete)
+		p.MockCloudFormation().On("UpdateTerminationProtection", mock.MatchedBy(func(input *cfn.UpdateTerminationProtectionInput) bool {
+			return *input.StackName == stackName+"-id" && !*input.EnableTerminationProtection
+		})).Return(&cfn.UpdateTerminationProtectionOutput{}, nil)
+		p.MockCloudFormation().On("DeleteStack", mock.Anything).Return(&cfn.DeleteStackOutput{}, nil)
+		deleted := &cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{{
+			StackName:   aws.String(stackName),
+			StackStatus: aws.String(cfn.StackStatusDeleteComplete),
+		}}}
+		req := awstesting.NewClient(nil).NewRequest(&request.Operation{Name: "Operation"}, nil, deleted)
+		p.MockCloudFormation().On("DescribeStacksRequest", mock.Anything).Return(req, deleted)
+
+		s, err := sc.stackToResume(resumeCtx, stackName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).To(BeNil())
+		p.MockCloudFormation().AssertCalled(GinkgoT(), "UpdateTerminationProtection", mock.Anything)
+		p.MockCloudFormation().AssertCalled(GinkgoT(), "DeleteStack", mock.Anything)
+	})
+
+	It("does not delete failed stacks the interrupted run did not record", func() {
+		describeStack(stackName+"-id", cfn.StackStatusCreateFailed)
+
+		_, err := sc.stackToResume(resumeCtx, stackName)
+		Expect(err).To(MatchError(ContainSubstring(`stack "eksctl-test-nodegroup-ng-1" (CREATE_FAILED) is not recorded as created by the operation being resumed`)))
+		p.MockCloudFormation().AssertNotCalled(GinkgoT(), "UpdateTerminationProtection", mock.Anything)
+		p.MockCloudFormation().AssertNotCalled(GinkgoT(), "DeleteStack", mock.Anything)
+	})
+
+	It("does not delete failed stacks replacing the stack created by the interrupted run", func() {
+		tasks.JournalFromContext(resumeCtx).RecordStack(stackName, stackName+"-id")
+		describeStack(stackName+"-other-id", cfn.StackStatusRollbackComplete)
+
+		_, err := sc.stackToResume(resumeCtx, stackName)
+		Expect(err).To(MatchError(ContainSubstring("is not recorded as created by the operation being resumed")))
+		p.MockCloudFormation().AssertNotCalled(GinkgoT(), "DeleteStack", mock.Anything)
+	})
+
+	It("fails on stacks in any other state", func() {
+		tasks.JournalFromContext(resumeCtx).RecordStack(stackName, stackName+"-id")
+		describeStack(stackName+"-id", cfn.StackStatusUpdateRollbackFailed)
+
+		_, err := sc.stackToResume(resumeCtx, stackName)
+		Expect(err).To(MatchError(ContainSubstring(`unable to resume the creation of stack "eksctl-test-nodegroup-ng-1" in state UPDATE_ROLLBACK_FAILED`)))
+		p.MockCloudFormation().AssertNotCalled(GinkgoT(), "UpdateTerminationProtection", mock.Anything)
+		p.MockCloudFormation().AssertNotCalled(GinkgoT(), "DeleteStack", mock.Anything)
+	})
+
+	It("records the ID of the stacks it creates in the journal", func() {
+		p.MockCloudFormation().On("CreateStack", mock.Anything).Return(&cfn.CreateStackOutput{StackId: aws.String(stackName + "-id")}, nil)
+		resourceSet := builder.NewIAMRoleResourceSetForServiceAccount(&api.ClusterIAMServiceAccount{
+			ClusterIAMMeta: api.ClusterIAMMeta{Name: "s3-reader", Namespace: "default"},
+		}, nil)
+
+		_, err := sc.createStackRequest(resumeCtx, stackName, resourceSet, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks.JournalFromContext(resumeCtx).StackID(stackName)).To(Equal(stackName + "-id"))
+	})
+
+	It("creates stacks that do not exist", func() {
+		p.MockCloudFormation().On("DescribeStacks", mock.Anything).Return(nil,
+			awserr.New("ValidationError", "Stack with id "+stackName+" does not exist", nil))
+
+		s, err := sc.stackToResume(resumeCtx, stackName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).To(BeNil())
+	})
+})
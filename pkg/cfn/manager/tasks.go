@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -9,6 +10,7 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
 	kubewrapper "github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
 	"github.com/weaveworks/eksctl/pkg/vpc"
 )
 
@@ -20,8 +22,12 @@ type createClusterTask struct {
 
 func (t *createClusterTask) Describe() string { return t.info }
 
-func (t *createClusterTask) Do(errorCh chan error) error {
-	return t.stackCollection.createClusterTask(errorCh, t.supportsManagedNodes)
+// RerunOnResume implements tasks.Rerunnable, tasks creating stacks are run again when
+// resuming to wait for their stacks and read their outputs
+func (t *createClusterTask) RerunOnResume() bool { return true }
+
+func (t *createClusterTask) Do(ctx context.Context, errorCh chan error) error {
+	return t.stackCollection.createClusterTask(ctx, errorCh, t.supportsManagedNodes)
 }
 
 type nodeGroupTask struct {
@@ -32,9 +38,10 @@ type nodeGroupTask struct {
 	stackCollection   *StackCollection
 }
 
-func (t *nodeGroupTask) Describe() string { return t.info }
-func (t *nodeGroupTask) Do(errs chan error) error {
-	return t.stackCollection.createNodeGroupTask(errs, t.nodeGroup, t.forceAddCNIPolicy, t.vpcImporter)
+func (t *nodeGroupTask) Describe() string    { return t.info }
+func (t *nodeGroupTask) RerunOnResume() bool { return true }
+func (t *nodeGroupTask) Do(ctx context.Context, errs chan error) error {
+	return t.stackCollection.createNodeGroupTask(ctx, errs, t.nodeGroup, t.forceAddCNIPolicy, t.vpcImporter)
 }
 
 type managedNodeGroupTask struct {
@@ -45,10 +52,11 @@ type managedNodeGroupTask struct {
 	vpcImporter       vpc.Importer
 }
 
-func (t *managedNodeGroupTask) Describe() string { return t.info }
+func (t *managedNodeGroupTask) Describe() string    { return t.info }
+func (t *managedNodeGroupTask) RerunOnResume() bool { return true }
 
-func (t *managedNodeGroupTask) Do(errorCh chan error) error {
-	return t.stackCollection.createManagedNodeGroupTask(errorCh, t.nodeGroup, t.forceAddCNIPolicy, t.vpcImporter)
+func (t *managedNodeGroupTask) Do(ctx context.Context, errorCh chan error) error {
+	return t.stackCollection.createManagedNodeGroupTask(ctx, errorCh, t.nodeGroup, t.forceAddCNIPolicy, t.vpcImporter)
 }
 
 type clusterCompatTask struct {
@@ -58,9 +66,9 @@ type clusterCompatTask struct {
 
 func (t *clusterCompatTask) Describe() string { return t.info }
 
-func (t *clusterCompatTask) Do(errorCh chan error) error {
+func (t *clusterCompatTask) Do(ctx context.Context, errorCh chan error) error {
 	defer close(errorCh)
-	return t.stackCollection.FixClusterCompatibility()
+	return t.stackCollection.FixClusterCompatibility(ctx)
 }
 
 type taskWithClusterIAMServiceAccountSpec struct {
@@ -70,20 +78,21 @@ type taskWithClusterIAMServiceAccountSpec struct {
 	oidc            *iamoidc.OpenIDConnectManager
 }
 
-func (t *taskWithClusterIAMServiceAccountSpec) Describe() string { return t.info }
-func (t *taskWithClusterIAMServiceAccountSpec) Do(errs chan error) error {
-	return t.stackCollection.createIAMServiceAccountTask(errs, t.serviceAccount, t.oidc)
+func (t *taskWithClusterIAMServiceAccountSpec) Describe() string    { return t.info }
+func (t *taskWithClusterIAMServiceAccountSpec) RerunOnResume() bool { return true }
+func (t *taskWithClusterIAMServiceAccountSpec) Do(ctx context.Context, errs chan error) error {
+	return t.stackCollection.createIAMServiceAccountTask(ctx, errs, t.serviceAccount, t.oidc)
 }
 
 type taskWithStackSpec struct {
 	info  string
 	stack *Stack
-	call  func(*Stack, chan error) error
+	call  func(context.Context, *Stack, chan error) error
 }
 
 func (t *taskWithStackSpec) Describe() string { return t.info }
-func (t *taskWithStackSpec) Do(errs chan error) error {
-	return t.call(t.stack, errs)
+func (t *taskWithStackSpec) Do(ctx context.Context, errs chan error) error {
+	return t.call(ctx, t.stack, errs)
 }
 
 type asyncTaskWithStackSpec struct {
@@ -93,7 +102,7 @@ type asyncTaskWithStackSpec struct {
 }
 
 func (t *asyncTaskWithStackSpec) Describe() string { return t.info + " [async]" }
-func (t *asyncTaskWithStackSpec) Do(errs chan error) error {
+func (t *asyncTaskWithStackSpec) Do(_ context.Context, errs chan error) error {
 	_, err := t.call(t.stack)
 	close(errs)
 	return err
@@ -105,13 +114,14 @@ type asyncTaskWithoutParams struct {
 }
 
 func (t *asyncTaskWithoutParams) Describe() string { return t.info }
-func (t *asyncTaskWithoutParams) Do(errs chan error) error {
+func (t *asyncTaskWithoutParams) Do(_ context.Context, errs chan error) error {
 	err := t.call()
 	close(errs)
 	return err
 }
 
 type kubernetesTask struct {
+	tasks.TransientErrorRetries
 	info       string
 	kubernetes kubewrapper.ClientSetGetter
 	objectMeta v1.ObjectMeta
@@ -119,7 +129,7 @@ type kubernetesTask struct {
 }
 
 func (t *kubernetesTask) Describe() string { return t.info }
-func (t *kubernetesTask) Do(errs chan error) error {
+func (t *kubernetesTask) Do(_ context.Context, errs chan error) error {
 	if t.kubernetes == nil {
 		return fmt.Errorf("cannot start task %q as Kubernetes client configurtaion wasn't provided", t.Describe())
 	}
@@ -1,13 +1,24 @@
 package manager
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/iam"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
 	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
 	vpcfakes "github.com/weaveworks/eksctl/pkg/vpc/fakes"
 )
 
@@ -17,7 +28,21 @@ func (t *task) Describe() string {
 	return fmt.Sprintf("task %d", t.id)
 }
 
-func (t *task) Do(chan error) error {
+func (t *task) Do(context.Context, chan error) error {
+	return nil
+}
+
+type funcTask struct {
+	info string
+	call func() error
+}
+
+func (t *funcTask) Describe() string { return t.info }
+
+func (t *funcTask) Do(_ context.Context, errs chan error) error {
+	go func() {
+		errs <- t.call()
+	}()
 	return nil
 }
 
@@ -113,63 +138,228 @@ var _ = Describe("StackCollection Tasks", func() {
 					Expect(tasks.Describe()).To(Equal(`no tasks`))
 				}
 				{
-					tasks := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("bar", "foo"), nil, true)
+					tasks, err := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("bar", "foo"), nil, true, nil)
+					Expect(err).NotTo(HaveOccurred())
 					Expect(tasks.Describe()).To(Equal(`
-2 sequential tasks: { create cluster control plane "test-cluster", 
-    2 parallel sub-tasks: { 
-        create nodegroup "bar",
-        create nodegroup "foo",
-    } 
+3 parallel tasks: { #1: create cluster control plane "test-cluster", #2 (after #1): create nodegroup "bar", #3 (after #1): create nodegroup "foo" 
 }
 `))
 				}
 				{
-					tasks := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("bar"), nil, false)
+					tasks, err := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("bar"), nil, false, nil)
+					Expect(err).NotTo(HaveOccurred())
 					Expect(tasks.Describe()).To(Equal(`
-2 sequential tasks: { create cluster control plane "test-cluster", create nodegroup "bar" 
+2 parallel tasks: { #1: create cluster control plane "test-cluster", #2 (after #1): create nodegroup "bar" 
 }
 `))
 				}
 				{
-					tasks := stackManager.NewTasksToCreateClusterWithNodeGroups(nil, nil, true)
+					tasks, err := stackManager.NewTasksToCreateClusterWithNodeGroups(nil, nil, true, nil)
+					Expect(err).NotTo(HaveOccurred())
 					Expect(tasks.Describe()).To(Equal(`1 task: { create cluster control plane "test-cluster" }`))
 				}
 				{
-					tasks := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("bar", "foo"), makeManagedNodeGroups("m1", "m2"), false)
+					tasks, err := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("bar", "foo"), makeManagedNodeGroups("m1", "m2"), false, nil)
+					Expect(err).NotTo(HaveOccurred())
 					Expect(tasks.Describe()).To(Equal(`
-2 sequential tasks: { create cluster control plane "test-cluster", 
-    4 parallel sub-tasks: { 
-        create nodegroup "bar",
-        create nodegroup "foo",
-        create managed nodegroup "m1",
-        create managed nodegroup "m2",
-    } 
+5 parallel tasks: { #1: create cluster control plane "test-cluster", #2 (after #1): create nodegroup "bar", #3 (after #1): create nodegroup "foo", #4 (after #1): create managed nodegroup "m1", #5 (after #1): create managed nodegroup "m2" 
 }
 `))
 				}
 				{
-					tasks := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("foo"), makeManagedNodeGroups("m1"), true)
+					tasks, err := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("foo"), makeManagedNodeGroups("m1"), true, nil)
+					Expect(err).NotTo(HaveOccurred())
 					Expect(tasks.Describe()).To(Equal(`
-2 sequential tasks: { create cluster control plane "test-cluster", 
-    2 parallel sub-tasks: { 
-        create nodegroup "foo",
-        create managed nodegroup "m1",
-    } 
+3 parallel tasks: { #1: create cluster control plane "test-cluster", #2 (after #1): create nodegroup "foo", #3 (after #1): create managed nodegroup "m1" 
+}
+`))
+				}
+				{
+					tasks, err := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("bar"), nil, false, nil, &task{id: 1}, &task{id: 2})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(tasks.Describe()).To(Equal(`
+3 parallel tasks: { #1: create cluster control plane "test-cluster", 
+    #2 (after #1): 2 sequential sub-tasks: { 
+        task 1,
+        task 2,
+    }, #3 (after #1): create nodegroup "bar" 
 }
 `))
 				}
 				{
-					tasks := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("bar"), nil, false, &task{id: 1})
+					tasks, err := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("bar"), makeManagedNodeGroups("m1"), false, []tasks.Task{&task{id: 3}}, &task{id: 1}, &task{id: 2})
+					Expect(err).NotTo(HaveOccurred())
 					Expect(tasks.Describe()).To(Equal(`
-2 sequential tasks: { create cluster control plane "test-cluster", 
-    2 sequential sub-tasks: { 
+5 parallel tasks: { #1: create cluster control plane "test-cluster", 
+    #2 (after #1): 2 sequential sub-tasks: { 
         task 1,
-        create nodegroup "bar",
-    } 
+        task 2,
+    }, #3 (after #2): task 3, #4 (after #1, #3): create nodegroup "bar", #5 (after #1, #3): create managed nodegroup "m1" 
 }
 `))
 				}
 			})
+
+			It("should limit the number of stacks created at the same time", func() {
+				stackManager.maxParallelStacks = 2
+
+				var nodeGroups []*api.NodeGroup
+				for _, name := range []string{"a", "b", "c"} {
+					ng := api.NewNodeGroup()
+					ng.Name = name
+					nodeGroups = append(nodeGroups, ng)
+				}
+
+				tasks, err := stackManager.NewTasksToCreateClusterWithNodeGroups(nodeGroups, nil, true, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tasks.MaxParallel).To(Equal(2))
+				Expect(tasks.Describe()).To(HavePrefix("\n4 parallel tasks (at most 2 at a time): {"))
+				for _, task := range tasks.Tasks[1:] {
+					Expect(tasks.Dependencies(task)).To(ConsistOf(tasks.Tasks[0]))
+				}
+			})
+
+			It("should start creating the nodegroups without waiting for the post cluster creation tasks", func() {
+				nodeGroupStarted := make(chan struct{})
+				var order []string
+				var mutex sync.Mutex
+				record := func(event string) {
+					mutex.Lock()
+					defer mutex.Unlock()
+					order = append(order, event)
+				}
+				newTask := func(info string, call func() error) tasks.Task {
+					return &funcTask{info: info, call: func() error {
+						record("start " + info)
+						defer record("finish " + info)
+						return call()
+					}}
+				}
+				waitForNodeGroup := func() error {
+					select {
+					case <-nodeGroupStarted:
+						return nil
+					case <-time.After(5 * time.Second):
+						return errors.New("timed out waiting for the nodegroup to start")
+					}
+				}
+				noop := func() error { return nil }
+
+				taskTree, err := stackManager.newClusterCreationTaskTree(
+					newTask("cluster", noop),
+					[]tasks.Task{newTask("iamserviceaccounts", waitForNodeGroup), newTask("addons", waitForNodeGroup)},
+					nil,
+					[]tasks.Task{newTask("nodegroup", func() error {
+						close(nodeGroupStarted)
+						return nil
+					})},
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(taskTree.DoAllSync()).To(BeEmpty())
+				Expect(order[0]).To(Equal("start cluster"))
+				Expect(order[1]).To(Equal("finish cluster"))
+				Expect(order).To(ContainElement("start nodegroup"))
+				indexOf := func(event string) int {
+					for i, e := range order {
+						if e == event {
+							return i
+						}
+					}
+					return -1
+				}
+				Expect(indexOf("start nodegroup")).To(BeNumerically("<", indexOf("finish iamserviceaccounts")))
+				Expect(indexOf("finish iamserviceaccounts")).To(BeNumerically("<", indexOf("start addons")))
+			})
+
+			It("should create the nodegroups once the pre-nodegroup addons have been created", func() {
+				var order []string
+				var mutex sync.Mutex
+				record := func(event string) {
+					mutex.Lock()
+					defer mutex.Unlock()
+					order = append(order, event)
+				}
+				newTask := func(info string) tasks.Task {
+					return &funcTask{info: info, call: func() error {
+						record("start " + info)
+						// give any task not waiting for this one a chance to start
+						time.Sleep(10 * time.Millisecond)
+						record("finish " + info)
+						return nil
+					}}
+				}
+
+				taskTree, err := stackManager.newClusterCreationTaskTree(
+					newTask("cluster"),
+					[]tasks.Task{newTask("iamserviceaccounts")},
+					[]tasks.Task{newTask("vpc-cni")},
+					[]tasks.Task{newTask("nodegroup"), newTask("managed nodegroup")},
+				)
+				Expect(err).NotTo(HaveOccurred())
+				for _, task := range taskTree.Tasks[3:] {
+					Expect(taskTree.Dependencies(task)).To(ConsistOf(taskTree.Tasks[0], taskTree.Tasks[2]))
+				}
+				Expect(taskTree.DoAllSync()).To(BeEmpty())
+				Expect(order[:6]).To(Equal([]string{
+					"start cluster", "finish cluster",
+					"start iamserviceaccounts", "finish iamserviceaccounts",
+					"start vpc-cni", "finish vpc-cni",
+				}))
+				Expect(order[6:]).To(ConsistOf("start nodegroup", "finish nodegroup", "start managed nodegroup", "finish managed nodegroup"))
+			})
+		})
+
+		Context("deleting a cluster", func() {
+			BeforeEach(func() {
+				p = mockprovider.NewMockProvider()
+				cfg = newClusterConfig("test-cluster")
+				stackManager = NewStackCollection(p, cfg)
+				stackManager.maxParallelStacks = 2
+
+				stacks := map[string][]*cfn.Tag{
+					"eksctl-test-cluster-cluster":                                   {{Key: aws.String(api.ClusterNameTag), Value: aws.String("test-cluster")}},
+					"eksctl-test-cluster-nodegroup-ng-1":                            {{Key: aws.String(api.NodeGroupNameTag), Value: aws.String("ng-1")}},
+					"eksctl-test-cluster-addon-iamserviceaccount-default-s3-reader": {{Key: aws.String(api.IAMServiceAccountNameTag), Value: aws.String("default/s3-reader")}},
+					"eksctl-test-cluster-addon-vpc-cni":                             {{Key: aws.String(api.AddonNameTag), Value: aws.String("vpc-cni")}},
+				}
+				var summaries []*cfn.StackSummary
+				for name, tags := range stacks {
+					name, tags := name, tags
+					summaries = append(summaries, &cfn.StackSummary{StackName: aws.String(name)})
+					p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+						return *input.StackName == name
+					})).Return(&cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{{
+						StackName:   aws.String(name),
+						StackId:     aws.String(name + "-id"),
+						StackStatus: aws.String(cfn.StackStatusCreateComplete),
+						Tags:        tags,
+					}}}, nil)
+				}
+				p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					pager := args.Get(1).(func(*cfn.ListStacksOutput, bool) bool)
+					pager(&cfn.ListStacksOutput{StackSummaries: summaries}, true)
+				}).Return(nil)
+				p.MockIAM().On("GetOpenIDConnectProvider", mock.Anything).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "not found", nil))
+			})
+
+			It("deletes iamserviceaccounts and addon IAM roles after the nodegroups", func() {
+				oidc, err := iamoidc.NewOpenIDConnectManager(p.IAM(), "123456789012", "https://oidc.eks.us-west-2.amazonaws.com/id/A39A2842863C47208955D753DE205E6E", "aws", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				taskTree, err := stackManager.NewTasksToDeleteClusterWithNodeGroups(true, oidc, nil, false, false, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(taskTree.MaxParallel).To(Equal(2))
+				Expect(taskTree.Tasks).To(HaveLen(4))
+
+				nodeGroupTasks, serviceAccountTasks, addonIAMTasks, clusterTask := taskTree.Tasks[0], taskTree.Tasks[1], taskTree.Tasks[2], taskTree.Tasks[3]
+				Expect(nodeGroupTasks.Describe()).To(ContainSubstring(`delete nodegroup "ng-1"`))
+				Expect(serviceAccountTasks.Describe()).To(ContainSubstring(`delete IAM role for serviceaccount "default/s3-reader"`))
+				Expect(addonIAMTasks.Describe()).To(ContainSubstring(`delete addon IAM "eksctl-test-cluster-addon-vpc-cni"`))
+				Expect(taskTree.Dependencies(nodeGroupTasks)).To(BeEmpty())
+				Expect(taskTree.Dependencies(serviceAccountTasks)).To(ConsistOf(nodeGroupTasks))
+				Expect(taskTree.Dependencies(addonIAMTasks)).To(ConsistOf(nodeGroupTasks))
+				Expect(taskTree.Dependencies(clusterTask)).To(ConsistOf(nodeGroupTasks, serviceAccountTasks, addonIAMTasks))
+			})
 		})
 	})
 })
@@ -0,0 +1,379 @@
+package manager
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/version"
+)
+
+// maxTemplateBodySize is the maximum size of a template that can be passed inline to CloudFormation
+const maxTemplateBodySize = 51200
+
+// TemplateUploader stores templates that are too large to be passed inline to CloudFormation
+type TemplateUploader interface {
+	// Upload stores the template of a stack and returns the URL CloudFormation reads it from,
+	// along with the name of the bucket it was stored in
+	Upload(stackName string, templateBody []byte) (string, string, error)
+	// Delete deletes all templates stored for the cluster in the bucket
+	Delete(bucket string) error
+	// DeleteStack deletes the templates stored for a stack of the cluster in the bucket
+	DeleteStack(bucket, stackName string) error
+}
+
+// S3TemplateUploader uploads templates to an S3 bucket, under a prefix named after the cluster and the stack;
+// when no bucket is given, a bucket named after the account and region is created and tagged by eksctl.
+// Every request is made with the account as the expected bucket owner, so that templates are never read from
+// or written to a bucket of the same name owned by another account
+type S3TemplateUploader struct {
+	s3API       s3iface.S3API
+	stsAPI      stsiface.STSAPI
+	bucket      string
+	clusterName string
+	region      string
+
+	mu           sync.Mutex
+	bucketExists bool
+
+	accountMu sync.Mutex
+	account   string
+}
+
+// NewS3TemplateUploader creates a new S3TemplateUploader
+func NewS3TemplateUploader(s3API s3iface.S3API, stsAPI stsiface.STSAPI, bucket, clusterName, region string) *S3TemplateUploader {
+	return &S3TemplateUploader{
+		s3API:       s3API,
+		stsAPI:      stsAPI,
+		bucket:      bucket,
+		clusterName: clusterName,
+		region:      region,
+	}
+}
+
+// Upload stores the template in the bucket, creating the bucket if needed
+func (u *S3TemplateUploader) Upload(stackName string, templateBody []byte) (string, string, error) {
+	bucket, err := u.ensureBucket()
+	if err != nil {
+		return "", "", err
+	}
+	owner, err := u.accountID()
+	if err != nil {
+		return "", "", err
+	}
+
+	key := fmt.Sprintf("%s%d.json", u.stackPrefix(stackName), time.Now().Unix())
+	logger.Debug("uploading template of stack %q to s3://%s/%s", stackName, bucket, key)
+	if _, err := u.s3API.PutObject(&s3.PutObjectInput{
+		Bucket:              aws.String(bucket),
+		Key:                 aws.String(key),
+		Body:                bytes.NewReader(templateBody),
+		ContentType:         aws.String("application/json"),
+		ExpectedBucketOwner: aws.String(owner),
+	}); err != nil {
+		return "", "", errors.Wrapf(err, "uploading template of stack %q to bucket %q", stackName, bucket)
+	}
+
+	endpoint, err := endpoints.DefaultResolver().EndpointFor(s3.EndpointsID, u.region)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "resolving S3 endpoint for region %q", u.region)
+	}
+	return fmt.Sprintf("%s/%s/%s", endpoint.URL, bucket, key), bucket, nil
+}
+
+// Delete deletes the templates of the cluster from the bucket, and the bucket itself if it was created
+// by eksctl and is left empty
+func (u *S3TemplateUploader) Delete(bucket string) error {
+	return u.deleteTemplates(bucket, u.clusterName+"/")
+}
+
+// DeleteStack deletes the templates of the stack from the bucket, and the bucket itself if it was created
+// by eksctl and is left empty
+func (u *S3TemplateUploader) DeleteStack(bucket, stackName string) error {
+	return u.deleteTemplates(bucket, u.stackPrefix(stackName))
+}
+
+// stackPrefix returns the prefix of the keys of the templates of a stack, which ends with a slash
+// so that it doesn't match the templates of other stacks whose names start with the same name
+func (u *S3TemplateUploader) stackPrefix(stackName string) string {
+	return fmt.Sprintf("%s/%s/", u.clusterName, stackName)
+}
+
+func (u *S3TemplateUploader) deleteTemplates(bucket, prefix string) error {
+	owner, err := u.accountID()
+	if err != nil {
+		return err
+	}
+	var objects []*s3.ObjectIdentifier
+	input := &s3.ListObjectsV2Input{
+		Bucket:              aws.String(bucket),
+		Prefix:              aws.String(prefix),
+		ExpectedBucketOwner: aws.String(owner),
+	}
+	if err := u.s3API.ListObjectsV2Pages(input, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, o := range page.Contents {
+			objects = append(objects, &s3.ObjectIdentifier{Key: o.Key})
+		}
+		return true
+	}); err != nil {
+		if isNoSuchBucket(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "listing templates in bucket %q", bucket)
+	}
+
+	// DeleteObjects accepts up to 1000 keys
+	for len(objects) > 0 {
+		batch := objects
+		if len(batch) > 1000 {
+			batch = batch[:1000]
+		}
+		objects = objects[len(batch):]
+		if _, err := u.s3API.DeleteObjects(&s3.DeleteObjectsInput{
+			Bucket:              aws.String(bucket),
+			Delete:              &s3.Delete{Objects: batch, Quiet: aws.Bool(true)},
+			ExpectedBucketOwner: aws.String(owner),
+		}); err != nil {
+			return errors.Wrapf(err, "deleting templates from bucket %q", bucket)
+		}
+		logger.Info("deleted %d template(s) from bucket %q", len(batch), bucket)
+	}
+
+	owned, err := u.createdByEksctl(bucket, owner)
+	if err != nil || !owned {
+		return err
+	}
+	// the bucket is shared by all clusters in the region, so it is only deleted once it is empty
+	remaining, err := u.s3API.ListObjectsV2(&s3.ListObjectsV2Input{
+		Bucket:              aws.String(bucket),
+		MaxKeys:             aws.Int64(1),
+		ExpectedBucketOwner: aws.String(owner),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "listing objects in bucket %q", bucket)
+	}
+	if len(remaining.Contents) > 0 {
+		return nil
+	}
+	if _, err := u.s3API.DeleteBucket(&s3.DeleteBucketInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: aws.String(owner),
+	}); err != nil {
+		return errors.Wrapf(err, "deleting bucket %q", bucket)
+	}
+	logger.Info("deleted bucket %q", bucket)
+	return nil
+}
+
+// ensureBucket returns the name of the bucket, after creating it if it doesn't exist
+func (u *S3TemplateUploader) ensureBucket() (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	owner, err := u.accountID()
+	if err != nil {
+		return "", err
+	}
+	bucket := u.bucketName(owner)
+	if u.bucketExists {
+		return bucket, nil
+	}
+
+	exists, err := u.exists(bucket, owner)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		if u.bucket != "" {
+			return "", fmt.Errorf("bucket %q does not exist", bucket)
+		}
+		if err := u.createBucket(bucket, owner); err != nil {
+			return "", err
+		}
+	}
+	u.bucketExists = true
+	return bucket, nil
+}
+
+func (u *S3TemplateUploader) createBucket(bucket, owner string) error {
+	logger.Info("creating bucket %q for CloudFormation templates", bucket)
+	input := &s3.CreateBucketInput{
+		Bucket: aws.String(bucket),
+	}
+	// us-east-1 is the default location and is rejected as a location constraint
+	if u.region != api.RegionUSEast1 {
+		input.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+			LocationConstraint: aws.String(u.region),
+		}
+	}
+	// CreateBucket doesn't accept an expected owner, but fails when the bucket is owned by another account
+	if _, err := u.s3API.CreateBucket(input); err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == s3.ErrCodeBucketAlreadyExists {
+			return fmt.Errorf("bucket %q is owned by another account than %s", bucket, owner)
+		}
+		return errors.Wrapf(err, "creating bucket %q", bucket)
+	}
+
+	if _, err := u.s3API.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: aws.String(owner),
+		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(true),
+			BlockPublicPolicy:     aws.Bool(true),
+			IgnorePublicAcls:      aws.Bool(true),
+			RestrictPublicBuckets: aws.Bool(true),
+		},
+	}); err != nil {
+		return errors.Wrapf(err, "blocking public access to bucket %q", bucket)
+	}
+
+	if _, err := u.s3API.PutBucketTagging(&s3.PutBucketTaggingInput{
+		Bucket: aws.String(bucket),
+		Tagging: &s3.Tagging{
+			TagSet: []*s3.Tag{{Key: aws.String(api.EksctlVersionTag), Value: aws.String(version.GetVersion())}},
+		},
+		ExpectedBucketOwner: aws.String(owner),
+	}); err != nil {
+		return errors.Wrapf(err, "tagging bucket %q", bucket)
+	}
+	return nil
+}
+
+// exists reports whether the bucket exists, and fails when it is owned by another account
+func (u *S3TemplateUploader) exists(bucket, owner string) (bool, error) {
+	if _, err := u.s3API.HeadBucket(&s3.HeadBucketInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: aws.String(owner),
+	}); err != nil {
+		if isNoSuchBucket(err) {
+			return false, nil
+		}
+		// S3 answers 403 when the bucket is owned by another account than the expected owner
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Forbidden" {
+			return false, fmt.Errorf("bucket %q exists but is not owned by account %s", bucket, owner)
+		}
+		return false, errors.Wrapf(err, "checking whether bucket %q exists", bucket)
+	}
+	return true, nil
+}
+
+// createdByEksctl reports whether the bucket carries the tag set by createBucket
+func (u *S3TemplateUploader) createdByEksctl(bucket, owner string) (bool, error) {
+	output, err := u.s3API.GetBucketTagging(&s3.GetBucketTaggingInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: aws.String(owner),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NoSuchTagSet" {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "getting tags of bucket %q", bucket)
+	}
+	for _, tag := range output.TagSet {
+		if aws.StringValue(tag.Key) == api.EksctlVersionTag {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func isNoSuchBucket(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && (awsErr.Code() == "NotFound" || awsErr.Code() == s3.ErrCodeNoSuchBucket)
+}
+
+// bucketName returns the configured bucket, or the name of the bucket created by eksctl for the account and region
+func (u *S3TemplateUploader) bucketName(account string) string {
+	if u.bucket != "" {
+		return u.bucket
+	}
+	return fmt.Sprintf("eksctl-cfn-templates-%s-%s", account, u.region)
+}
+
+// accountID returns the ID of the account the buckets are expected to be owned by, which is only looked up once
+func (u *S3TemplateUploader) accountID() (string, error) {
+	u.accountMu.Lock()
+	defer u.accountMu.Unlock()
+
+	if u.account != "" {
+		return u.account, nil
+	}
+	output, err := u.stsAPI.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", errors.Wrap(err, "getting account ID")
+	}
+	u.account = aws.StringValue(output.Account)
+	return u.account, nil
+}
+
+// uploadIfTooLarge uploads templates that exceed the size CloudFormation accepts inline, and returns their URL instead;
+// the bucket is recorded in the TemplateBucketTag of the stack so that it can be cleaned up when the stack is deleted
+func (c *StackCollection) uploadIfTooLarge(stackName string, templateData TemplateData, tags []*cloudformation.Tag) (TemplateData, []*cloudformation.Tag, error) {
+	body, ok := templateData.(TemplateBody)
+	if !ok || len(body) <= maxTemplateBodySize || c.templateUploader == nil {
+		return templateData, tags, nil
+	}
+	logger.Info("template of stack %q is %d bytes, which is larger than CloudFormation accepts inline, uploading it to S3", stackName, len(body))
+	url, bucket, err := c.templateUploader.Upload(stackName, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	var withBucket []*cloudformation.Tag
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) != api.TemplateBucketTag {
+			withBucket = append(withBucket, tag)
+		}
+	}
+	return TemplateURL(url), append(withBucket, newTag(api.TemplateBucketTag, bucket)), nil
+}
+
+// DeleteUploadedTemplates deletes the templates that were uploaded for the given stacks of the cluster,
+// from the buckets recorded in their tags
+func (c *StackCollection) DeleteUploadedTemplates(stacks []*Stack) error {
+	if c.templateUploader == nil {
+		return nil
+	}
+	buckets := map[string]bool{}
+	for _, s := range stacks {
+		for _, tag := range s.Tags {
+			if aws.StringValue(tag.Key) == api.TemplateBucketTag {
+				buckets[aws.StringValue(tag.Value)] = true
+			}
+		}
+	}
+	for bucket := range buckets {
+		if err := c.templateUploader.Delete(bucket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteUploadedStackTemplates deletes the templates that were uploaded for a stack that is being deleted;
+// CloudFormation only reads them when the stack is created or updated, so a failure doesn't fail the deletion
+func (c *StackCollection) deleteUploadedStackTemplates(s *Stack) {
+	if c.templateUploader == nil {
+		return
+	}
+	for _, tag := range s.Tags {
+		if aws.StringValue(tag.Key) != api.TemplateBucketTag {
+			continue
+		}
+		if err := c.templateUploader.DeleteStack(aws.StringValue(tag.Value), *s.StackName); err != nil {
+			logger.Warning("failed to delete the uploaded templates of stack %q: %v", *s.StackName, err)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package manager
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+// localTemplateUploader keeps uploaded templates in memory
+type localTemplateUploader struct {
+	templates      map[string][]byte
+	deletedBuckets []string
+	deletedStacks  []string
+}
+
+func (u *localTemplateUploader) Upload(stackName string, templateBody []byte) (string, string, error) {
+	u.templates[stackName] = templateBody
+	return "https://templates.local/" + stackName, "local", nil
+}
+
+func (u *localTemplateUploader) Delete(bucket string) error {
+	u.templates = map[string][]byte{}
+	u.deletedBuckets = append(u.deletedBuckets, bucket)
+	return nil
+}
+
+func (u *localTemplateUploader) DeleteStack(bucket, stackName string) error {
+	delete(u.templates, stackName)
+	u.deletedStacks = append(u.deletedStacks, bucket+"/"+stackName)
+	return nil
+}
+
+var _ = Describe("Template upload", func() {
+	var (
+		p  *mockprovider.MockProvider
+		sc *StackCollection
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		sc = NewStackCollection(p, cfg)
+	})
+
+	Context("StackCollection", func() {
+		var uploader *localTemplateUploader
+
+		BeforeEach(func() {
+			uploader = &localTemplateUploader{templates: map[string][]byte{}}
+			sc.templateUploader = uploader
+			p.MockCloudFormation().On("CreateStack", mock.Anything).Return(&cfn.CreateStackOutput{StackId: aws.String("stack-id")}, nil)
+		})
+
+		It("passes small templates inline", func() {
+			stack := &Stack{StackName: aws.String("eksctl-test-cluster")}
+			Expect(sc.DoCreateStackRequest(stack, TemplateBody(`{"Resources": {}}`), nil, nil, false, false)).To(Succeed())
+
+			input := p.MockCloudFormation().Calls[0].Arguments.Get(0).(*cfn.CreateStackInput)
+			Expect(*input.TemplateBody).To(Equal(`{"Resources": {}}`))
+			Expect(input.TemplateURL).To(BeNil())
+			Expect(uploader.templates).To(BeEmpty())
+			Expect(input.Tags).NotTo(ContainElement(newTag(api.TemplateBucketTag, "local")))
+		})
+
+		It("uploads templates that are too large to be passed inline", func() {
+			template := fmt.Sprintf(`{"Description": %q, "Resources": {}}`, strings.Repeat("x", maxTemplateBodySize))
+			stack := &Stack{StackName: aws.String("eksctl-test-nodegroup-ng-1")}
+			Expect(sc.DoCreateStackRequest(stack, TemplateBody(template), nil, nil, false, false)).To(Succeed())
+
+			input := p.MockCloudFormation().Calls[0].Arguments.Get(0).(*cfn.CreateStackInput)
+			Expect(input.TemplateBody).To(BeNil())
+			Expect(*input.TemplateURL).To(Equal("https://templates.local/eksctl-test-nodegroup-ng-1"))
+			Expect(uploader.templates).To(HaveKeyWithValue("eksctl-test-nodegroup-ng-1", []byte(template)))
+			Expect(input.Tags).To(ContainElement(newTag(api.TemplateBucketTag, "local")))
+		})
+
+		It("only deletes templates from the buckets recorded in the stack tags", func() {
+			Expect(sc.DeleteUploadedTemplates([]*Stack{
+				{StackName: aws.String("eksctl-test-cluster")},
+			})).To(Succeed())
+			Expect(uploader.deletedBuckets).To(BeEmpty())
+
+			Expect(sc.DeleteUploadedTemplates([]*Stack{
+				{StackName: aws.String("eksctl-test-cluster")},
+				{StackName: aws.String("eksctl-test-nodegroup-ng-1"), Tags: []*cfn.Tag{newTag(api.TemplateBucketTag, "templates")}},
+				{StackName: aws.String("eksctl-test-nodegroup-ng-2"), Tags: []*cfn.Tag{newTag(api.TemplateBucketTag, "templates")}},
+			})).To(Succeed())
+			Expect(uploader.deletedBuckets).To(Equal([]string{"templates"}))
+		})
+
+		It("deletes the templates of a stack when the stack is deleted", func() {
+			p.MockCloudFormation().On("DeleteStack", mock.Anything).Return(&cfn.DeleteStackOutput{}, nil)
+			_, err := sc.DeleteStackBySpec(&Stack{
+				StackName: aws.String("eksctl-test-nodegroup-ng-1"),
+				StackId:   aws.String("stack-id"),
+				Tags: []*cfn.Tag{
+					newTag(api.ClusterNameTag, "test"),
+					newTag(api.TemplateBucketTag, "templates"),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uploader.deletedStacks).To(Equal([]string{"templates/eksctl-test-nodegroup-ng-1"}))
+
+			By("not deleting templates of stacks that have none")
+			_, err = sc.DeleteStackBySpec(&Stack{
+				StackName: aws.String("eksctl-test-nodegroup-ng-2"),
+				StackId:   aws.String("stack-id"),
+				Tags:      []*cfn.Tag{newTag(api.ClusterNameTag, "test")},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uploader.deletedStacks).To(HaveLen(1))
+		})
+	})
+
+	Context("S3TemplateUploader", func() {
+		BeforeEach(func() {
+			p.MockSTS().On("GetCallerIdentity", mock.Anything).Return(&sts.GetCallerIdentityOutput{
+				Account: aws.String("123456789012"),
+			}, nil)
+		})
+
+		It("creates a tagged bucket when no bucket is set", func() {
+			const bucket = "eksctl-cfn-templates-123456789012-us-west-2"
+			p.MockS3().On("HeadBucket", mock.MatchedBy(func(input *s3.HeadBucketInput) bool {
+				return *input.ExpectedBucketOwner == "123456789012"
+			})).Return(nil, awserr.New("NotFound", "Not Found", nil))
+			p.MockS3().On("CreateBucket", mock.MatchedBy(func(input *s3.CreateBucketInput) bool {
+				return *input.Bucket == bucket && *input.CreateBucketConfiguration.LocationConstraint == "us-west-2"
+			})).Return(&s3.CreateBucketOutput{}, nil)
+			p.MockS3().On("PutPublicAccessBlock", mock.Anything).Return(&s3.PutPublicAccessBlockOutput{}, nil)
+			p.MockS3().On("PutBucketTagging", mock.MatchedBy(func(input *s3.PutBucketTaggingInput) bool {
+				return *input.Tagging.TagSet[0].Key == api.EksctlVersionTag && *input.ExpectedBucketOwner == "123456789012"
+			})).Return(&s3.PutBucketTaggingOutput{}, nil)
+			p.MockS3().On("PutObject", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+				body := new(bytes.Buffer)
+				_, _ = body.ReadFrom(input.Body)
+				return *input.Bucket == bucket && strings.HasPrefix(*input.Key, "test/eksctl-test-cluster/") && body.String() == "{}" &&
+					*input.ExpectedBucketOwner == "123456789012"
+			})).Return(&s3.PutObjectOutput{}, nil)
+
+			uploader := NewS3TemplateUploader(p.S3(), p.STS(), "", "test", "us-west-2")
+			url, uploadedTo, err := uploader.Upload("eksctl-test-cluster", []byte("{}"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(url).To(HavePrefix("https://s3.us-west-2.amazonaws.com/" + bucket + "/test/eksctl-test-cluster/"))
+			Expect(uploadedTo).To(Equal(bucket))
+
+			By("not checking for the bucket again")
+			_, _, err = uploader.Upload("eksctl-test-cluster", []byte("{}"))
+			Expect(err).NotTo(HaveOccurred())
+			p.MockS3().AssertNumberOfCalls(GinkgoT(), "HeadBucket", 1)
+			p.MockS3().AssertNumberOfCalls(GinkgoT(), "CreateBucket", 1)
+			p.MockSTS().AssertNumberOfCalls(GinkgoT(), "GetCallerIdentity", 1)
+		})
+
+		It("refuses to use a bucket owned by another account", func() {
+			p.MockS3().On("HeadBucket", mock.Anything).Return(nil, awserr.New("Forbidden", "Forbidden", nil))
+
+			uploader := NewS3TemplateUploader(p.S3(), p.STS(), "templates", "test", "us-west-2")
+			_, _, err := uploader.Upload("eksctl-test-cluster", []byte("{}"))
+			Expect(err).To(MatchError(`bucket "templates" exists but is not owned by account 123456789012`))
+			p.MockS3().AssertNotCalled(GinkgoT(), "PutObject", mock.Anything)
+		})
+
+		It("refuses to create a bucket whose name is taken by another account", func() {
+			p.MockS3().On("HeadBucket", mock.Anything).Return(nil, awserr.New("NotFound", "Not Found", nil))
+			p.MockS3().On("CreateBucket", mock.Anything).Return(nil, awserr.New(s3.ErrCodeBucketAlreadyExists, "The requested bucket name is not available", nil))
+
+			uploader := NewS3TemplateUploader(p.S3(), p.STS(), "", "test", "us-west-2")
+			_, _, err := uploader.Upload("eksctl-test-cluster", []byte("{}"))
+			Expect(err).To(MatchError(`bucket "eksctl-cfn-templates-123456789012-us-west-2" is owned by another account than 123456789012`))
+			p.MockS3().AssertNotCalled(GinkgoT(), "PutObject", mock.Anything)
+		})
+
+		It("deletes the templates of the cluster from a bucket that was not created by eksctl", func() {
+			p.MockS3().On("ListObjectsV2Pages", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+				return *input.Bucket == "templates" && *input.Prefix == "test/" && *input.ExpectedBucketOwner == "123456789012"
+			}), mock.Anything).Run(func(args mock.Arguments) {
+				pager := args.Get(1).(func(*s3.ListObjectsV2Output, bool) bool)
+				pager(&s3.ListObjectsV2Output{Contents: []*s3.Object{
+					{Key: aws.String("test/eksctl-test-cluster/1.json")},
+					{Key: aws.String("test/eksctl-test-nodegroup-ng-1/2.json")},
+				}}, true)
+			}).Return(nil)
+			p.MockS3().On("DeleteObjects", mock.MatchedBy(func(input *s3.DeleteObjectsInput) bool {
+				return *input.Bucket == "templates" && len(input.Delete.Objects) == 2 && *input.ExpectedBucketOwner == "123456789012"
+			})).Return(&s3.DeleteObjectsOutput{}, nil)
+			p.MockS3().On("GetBucketTagging", mock.Anything).Return(nil, awserr.New("NoSuchTagSet", "The TagSet does not exist", nil))
+
+			uploader := NewS3TemplateUploader(p.S3(), p.STS(), "", "test", "us-west-2")
+			Expect(uploader.Delete("templates")).To(Succeed())
+			p.MockS3().AssertNotCalled(GinkgoT(), "DeleteBucket", mock.Anything)
+		})
+
+		It("deletes the bucket created by eksctl once it is empty", func() {
+			const bucket = "eksctl-cfn-templates-123456789012-us-west-2"
+			p.MockS3().On("ListObjectsV2Pages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				pager := args.Get(1).(func(*s3.ListObjectsV2Output, bool) bool)
+				pager(&s3.ListObjectsV2Output{Contents: []*s3.Object{{Key: aws.String("test/eksctl-test-cluster/1.json")}}}, true)
+			}).Return(nil)
+			p.MockS3().On("DeleteObjects", mock.Anything).Return(&s3.DeleteObjectsOutput{}, nil)
+			p.MockS3().On("GetBucketTagging", mock.Anything).Return(&s3.GetBucketTaggingOutput{
+				TagSet: []*s3.Tag{{Key: aws.String(api.EksctlVersionTag), Value: aws.String("0.1.0")}},
+			}, nil)
+			p.MockS3().On("ListObjectsV2", mock.Anything).Return(&s3.ListObjectsV2Output{}, nil)
+			p.MockS3().On("DeleteBucket", mock.MatchedBy(func(input *s3.DeleteBucketInput) bool {
+				return *input.Bucket == bucket && *input.ExpectedBucketOwner == "123456789012"
+			})).Return(&s3.DeleteBucketOutput{}, nil)
+
+			uploader := NewS3TemplateUploader(p.S3(), p.STS(), "", "test", "us-west-2")
+			Expect(uploader.Delete(bucket)).To(Succeed())
+			p.MockS3().AssertNumberOfCalls(GinkgoT(), "DeleteBucket", 1)
+		})
+
+		It("deletes the templates of a stack, without those of stacks whose names start with its name", func() {
+			p.MockS3().On("ListObjectsV2Pages", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+				return *input.Bucket == "templates" && *input.Prefix == "test/eksctl-test-nodegroup-ng/"
+			}), mock.Anything).Run(func(args mock.Arguments) {
+				pager := args.Get(1).(func(*s3.ListObjectsV2Output, bool) bool)
+				pager(&s3.ListObjectsV2Output{Contents: []*s3.Object{{Key: aws.String("test/eksctl-test-nodegroup-ng/1.json")}}}, true)
+			}).Return(nil)
+			p.MockS3().On("DeleteObjects", mock.MatchedBy(func(input *s3.DeleteObjectsInput) bool {
+				return *input.Bucket == "templates" && len(input.Delete.Objects) == 1
+			})).Return(&s3.DeleteObjectsOutput{}, nil)
+			p.MockS3().On("GetBucketTagging", mock.Anything).Return(nil, awserr.New("NoSuchTagSet", "The TagSet does not exist", nil))
+
+			uploader := NewS3TemplateUploader(p.S3(), p.STS(), "", "test", "us-west-2")
+			Expect(uploader.DeleteStack("templates", "eksctl-test-nodegroup-ng")).To(Succeed())
+			p.MockS3().AssertNumberOfCalls(GinkgoT(), "DeleteObjects", 1)
+		})
+
+		It("ignores a bucket that no longer exists", func() {
+			p.MockS3().On("ListObjectsV2Pages", mock.Anything, mock.Anything).Return(awserr.New(s3.ErrCodeNoSuchBucket, "The specified bucket does not exist", nil))
+
+			uploader := NewS3TemplateUploader(p.S3(), p.STS(), "", "test", "us-west-2")
+			Expect(uploader.Delete("templates")).To(Succeed())
+		})
+	})
+})
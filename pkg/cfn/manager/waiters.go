@@ -1,9 +1,11 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/kris-nova/logger"
@@ -11,6 +13,7 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/utils/progress"
 	"github.com/weaveworks/eksctl/pkg/utils/waiters"
 )
 
@@ -23,9 +26,15 @@ const (
 // so this is custom version that is more suitable for our use, as there is no way to add any
 // custom acceptors
 
-func (c *StackCollection) waitWithAcceptors(i *Stack, acceptors []request.WaiterAcceptor) error {
+func (c *StackCollection) waitWithAcceptors(ctx context.Context, i *Stack, acceptors []request.WaiterAcceptor) error {
 	msg := fmt.Sprintf("waiting for CloudFormation stack %q", *i.StackName)
 
+	var streamer *stackEventStreamer
+	if c.streamEvents {
+		streamer = newStackEventStreamer(c.cloudformationAPI, i)
+	}
+
+	var lastStatus string
 	newRequest := func() *request.Request {
 		input := &cfn.DescribeStacksInput{
 			StackName: i.StackName,
@@ -33,7 +42,19 @@ func (c *StackCollection) waitWithAcceptors(i *Stack, acceptors []request.Waiter
 		if api.IsSetAndNonEmptyString(i.StackId) {
 			input.StackName = i.StackId
 		}
-		req, _ := c.cloudformationAPI.DescribeStacksRequest(input)
+		req, output := c.cloudformationAPI.DescribeStacksRequest(input)
+		if req != nil && output != nil {
+			req.Handlers.Complete.PushBack(func(r *request.Request) {
+				if r.Error == nil && len(output.Stacks) > 0 {
+					lastStatus = emitStackStatus(output.Stacks[0], lastStatus)
+				}
+				// events are fetched on the poll of the waiter, which also prints the events that
+				// led to the final status before the waiter returns
+				if streamer != nil {
+					streamer.poll()
+				}
+			})
+		}
 		return req
 	}
 
@@ -43,12 +64,16 @@ func (c *StackCollection) waitWithAcceptors(i *Stack, acceptors []request.Waiter
 			logger.Debug("describeErr=%v", err)
 		} else {
 			logger.Critical("unexpected status %q while %s", *s.StackStatus, msg)
+			if streamer != nil {
+				// the events that led to the failure have been printed as they happened
+				return nil
+			}
 			c.troubleshootStackFailureCause(i, desiredStatus)
 		}
 		return nil
 	}
 
-	return waiters.Wait(*i.StackName, msg, acceptors, newRequest, c.waitTimeout, troubleshoot)
+	return waiters.Wait(ctx, *i.StackName, msg, acceptors, newRequest, c.waitTimeout, troubleshoot)
 }
 
 type noChangeError struct {
@@ -59,7 +84,7 @@ func (e *noChangeError) Error() string {
 	return e.msg
 }
 
-func (c *StackCollection) waitWithAcceptorsChangeSet(i *Stack, changesetName string, acceptors []request.WaiterAcceptor) error {
+func (c *StackCollection) waitWithAcceptorsChangeSet(ctx context.Context, i *Stack, changesetName string, acceptors []request.WaiterAcceptor) error {
 	msg := fmt.Sprintf("waiting for CloudFormation changeset %q for stack %q", changesetName, *i.StackName)
 
 	newRequest := func() *request.Request {
@@ -86,7 +111,7 @@ func (c *StackCollection) waitWithAcceptorsChangeSet(i *Stack, changesetName str
 		return nil
 	}
 
-	return waiters.Wait(*i.StackName, msg, acceptors, newRequest, c.waitTimeout, troubleshoot)
+	return waiters.Wait(ctx, *i.StackName, msg, acceptors, newRequest, c.waitTimeout, troubleshoot)
 }
 
 func (c *StackCollection) troubleshootStackFailureCause(i *Stack, desiredStatus string) {
@@ -127,9 +152,9 @@ func (c *StackCollection) troubleshootStackFailureCause(i *Stack, desiredStatus
 }
 
 // DoWaitUntilStackIsCreated blocks until the given stack's
-// creation has completed.
-func (c *StackCollection) DoWaitUntilStackIsCreated(i *Stack) error {
-	return c.waitWithAcceptors(i,
+// creation has completed, or ctx is cancelled.
+func (c *StackCollection) DoWaitUntilStackIsCreated(ctx context.Context, i *Stack) error {
+	return c.waitWithAcceptors(ctx, i,
 		waiters.MakeAcceptors(
 			stackStatus,
 			cfn.StackStatusCreateComplete,
@@ -151,10 +176,10 @@ func (c *StackCollection) DoWaitUntilStackIsCreated(i *Stack) error {
 	)
 }
 
-func (c *StackCollection) waitUntilStackIsCreated(i *Stack, stack builder.ResourceSet, errs chan error) {
+func (c *StackCollection) waitUntilStackIsCreated(ctx context.Context, i *Stack, stack builder.ResourceSet, errs chan error) {
 	defer close(errs)
 
-	if err := c.DoWaitUntilStackIsCreated(i); err != nil {
+	if err := c.DoWaitUntilStackIsCreated(ctx, i); err != nil {
 		errs <- err
 		return
 	}
@@ -170,8 +195,8 @@ func (c *StackCollection) waitUntilStackIsCreated(i *Stack, stack builder.Resour
 	errs <- nil
 }
 
-func (c *StackCollection) doWaitUntilStackIsDeleted(i *Stack) error {
-	return c.waitWithAcceptors(i,
+func (c *StackCollection) doWaitUntilStackIsDeleted(ctx context.Context, i *Stack) error {
+	return c.waitWithAcceptors(ctx, i,
 		waiters.MakeAcceptors(
 			stackStatus,
 			cfn.StackStatusDeleteComplete,
@@ -204,18 +229,18 @@ func (c *StackCollection) doWaitUntilStackIsDeleted(i *Stack) error {
 	)
 }
 
-func (c *StackCollection) waitUntilStackIsDeleted(i *Stack, errs chan error) {
+func (c *StackCollection) waitUntilStackIsDeleted(ctx context.Context, i *Stack, errs chan error) {
 	defer close(errs)
 
-	if err := c.doWaitUntilStackIsDeleted(i); err != nil {
+	if err := c.doWaitUntilStackIsDeleted(ctx, i); err != nil {
 		errs <- err
 		return
 	}
 	errs <- nil
 }
 
-func (c *StackCollection) doWaitUntilStackIsUpdated(i *Stack) error {
-	return c.waitWithAcceptors(i,
+func (c *StackCollection) doWaitUntilStackIsUpdated(ctx context.Context, i *Stack) error {
+	return c.waitWithAcceptors(ctx, i,
 		waiters.MakeAcceptors(
 			stackStatus,
 			cfn.StackStatusUpdateComplete,
@@ -239,8 +264,33 @@ func (c *StackCollection) doWaitUntilStackIsUpdated(i *Stack) error {
 	)
 }
 
-func (c *StackCollection) doWaitUntilChangeSetIsCreated(i *Stack, changesetName string) error {
-	return c.waitWithAcceptorsChangeSet(i, changesetName,
+func (c *StackCollection) doWaitUntilStackIsImported(ctx context.Context, i *Stack) error {
+	return c.waitWithAcceptors(ctx, i,
+		waiters.MakeAcceptors(
+			stackStatus,
+			cfn.StackStatusImportComplete,
+			[]string{
+				cfn.StackStatusImportRollbackInProgress,
+				cfn.StackStatusImportRollbackFailed,
+				cfn.StackStatusImportRollbackComplete,
+				cfn.StackStatusRollbackInProgress,
+				cfn.StackStatusRollbackFailed,
+				cfn.StackStatusRollbackComplete,
+				cfn.StackStatusDeleteInProgress,
+				cfn.StackStatusDeleteFailed,
+				cfn.StackStatusDeleteComplete,
+			},
+			request.WaiterAcceptor{
+				State:    request.FailureWaiterState,
+				Matcher:  request.ErrorWaiterMatch,
+				Expected: "ValidationError",
+			},
+		),
+	)
+}
+
+func (c *StackCollection) doWaitUntilChangeSetIsCreated(ctx context.Context, i *Stack, changesetName string) error {
+	return c.waitWithAcceptorsChangeSet(ctx, i, changesetName,
 		waiters.MakeAcceptors(
 			changesetStatus,
 			cfn.ChangeSetStatusCreateComplete,
@@ -256,3 +306,17 @@ func (c *StackCollection) doWaitUntilChangeSetIsCreated(i *Stack, changesetName
 		),
 	)
 }
+
+// emitStackStatus reports the status of the stack when it differs from lastStatus, and returns it
+func emitStackStatus(s *Stack, lastStatus string) string {
+	status := aws.StringValue(s.StackStatus)
+	if status != lastStatus {
+		progress.Emit(progress.Event{
+			Type:   progress.StackStatus,
+			Stack:  aws.StringValue(s.StackName),
+			Status: status,
+			Reason: aws.StringValue(s.StackStatusReason),
+		})
+	}
+	return status
+}
@@ -0,0 +1,577 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// stackConverter converts the values of a single stack's template to Terraform expressions
+type stackConverter struct {
+	exporter *Exporter
+	stack    *stack
+}
+
+func isIntrinsic(m map[string]interface{}) (string, interface{}, bool) {
+	if len(m) != 1 {
+		return "", nil, false
+	}
+	for k, v := range m {
+		if k == "Ref" || strings.HasPrefix(k, "Fn::") {
+			return k, v, true
+		}
+	}
+	return "", nil, false
+}
+
+// isPlainObject returns true for maps that are not intrinsic functions
+func isPlainObject(v interface{}) (map[string]interface{}, bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	if _, _, intrinsic := isIntrinsic(m); intrinsic {
+		return nil, false
+	}
+	return m, true
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// convert converts a value of a template, resolving intrinsic functions to Terraform expressions
+func (c *stackConverter) convert(v interface{}) (value, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if fn, args, ok := isIntrinsic(v); ok {
+			return c.convertIntrinsic(fn, args)
+		}
+		o := newObject()
+		for _, k := range sortedKeys(v) {
+			converted, err := c.convert(v[k])
+			if err != nil {
+				return nil, err
+			}
+			if !isNoValue(converted) {
+				o.set(k, converted)
+			}
+		}
+		return o, nil
+	case []interface{}:
+		l := list{}
+		for _, item := range v {
+			converted, err := c.convert(item)
+			if err != nil {
+				return nil, err
+			}
+			if !isNoValue(converted) {
+				l = append(l, converted)
+			}
+		}
+		return l, nil
+	default:
+		return literal{v}, nil
+	}
+}
+
+func (c *stackConverter) convertIntrinsic(fn string, args interface{}) (value, error) {
+	switch fn {
+	case "Ref":
+		name, ok := args.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid Ref %v", args)
+		}
+		return c.ref(name)
+
+	case "Fn::GetAtt":
+		logicalID, attr, err := getAttArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		return c.getAtt(logicalID, attr)
+
+	case "Fn::Sub":
+		return c.sub(args)
+
+	case "Fn::Join":
+		a, ok := args.([]interface{})
+		if !ok || len(a) != 2 {
+			return nil, fmt.Errorf("invalid Fn::Join %v", args)
+		}
+		delimiter, _ := a[0].(string)
+		values, err := c.convert(a[1])
+		if err != nil {
+			return nil, err
+		}
+		if l, ok := values.(list); ok {
+			var parts []string
+			for _, v := range l {
+				lit, ok := v.(literal)
+				if !ok {
+					break
+				}
+				parts = append(parts, fmt.Sprint(lit.v))
+			}
+			if len(parts) == len(l) {
+				return literal{strings.Join(parts, delimiter)}, nil
+			}
+		}
+		return call{"join", []value{literal{delimiter}, values}}, nil
+
+	case "Fn::Split":
+		a, ok := args.([]interface{})
+		if !ok || len(a) != 2 {
+			return nil, fmt.Errorf("invalid Fn::Split %v", args)
+		}
+		delimiter, _ := a[0].(string)
+		source, err := c.convert(a[1])
+		if err != nil {
+			return nil, err
+		}
+		// splitting a joined value, e.g. the subnets of the cluster stack, returns the original list
+		if joined, ok := source.(call); ok && joined.name == "join" {
+			if d, ok := joined.args[0].(literal); ok && d.v == delimiter {
+				return joined.args[1], nil
+			}
+		}
+		return call{"split", []value{literal{delimiter}, source}}, nil
+
+	case "Fn::Select":
+		a, ok := args.([]interface{})
+		if !ok || len(a) != 2 {
+			return nil, fmt.Errorf("invalid Fn::Select %v", args)
+		}
+		index, err := c.convert(a[0])
+		if err != nil {
+			return nil, err
+		}
+		values, err := c.convert(a[1])
+		if err != nil {
+			return nil, err
+		}
+		if l, ok := values.(list); ok {
+			if i, ok := toIndex(index); ok && i < len(l) {
+				return l[i], nil
+			}
+		}
+		return call{"element", []value{values, index}}, nil
+
+	case "Fn::GetAZs":
+		return c.exporter.useData("aws_availability_zones", "available", ".names"), nil
+
+	case "Fn::Base64":
+		v, err := c.convert(args)
+		if err != nil {
+			return nil, err
+		}
+		return call{"base64encode", []value{v}}, nil
+
+	case "Fn::FindInMap":
+		a, ok := args.([]interface{})
+		if !ok || len(a) != 3 {
+			return nil, fmt.Errorf("invalid Fn::FindInMap %v", args)
+		}
+		mapName, _ := a[0].(string)
+		local, err := c.exporter.useMapping(c.stack, mapName)
+		if err != nil {
+			return nil, err
+		}
+		expr := "local." + local
+		for _, key := range a[1:] {
+			k, err := c.convert(key)
+			if err != nil {
+				return nil, err
+			}
+			expr += "[" + renderValue(k) + "]"
+		}
+		return expression(expr), nil
+
+	case "Fn::ImportValue":
+		return c.importValue(args)
+
+	case "Fn::Cidr":
+		a, ok := args.([]interface{})
+		if !ok || len(a) != 3 {
+			return nil, fmt.Errorf("invalid Fn::Cidr %v", args)
+		}
+		var converted []string
+		for _, arg := range a {
+			v, err := c.convert(arg)
+			if err != nil {
+				return nil, err
+			}
+			converted = append(converted, renderValue(v))
+		}
+		block, count, bits := converted[0], converted[1], converted[2]
+		// Fn::Cidr takes the number of host bits of each CIDR, while cidrsubnet takes the number of bits to add to the prefix
+		return expression(fmt.Sprintf(`[for i in range(%s) : cidrsubnet(%s, (length(regexall(":", %s)) > 0 ? 128 : 32) - %s - tonumber(split("/", %s)[1]), i)]`,
+			count, block, block, bits, block)), nil
+
+	default:
+		return nil, fmt.Errorf("%s is not supported", fn)
+	}
+}
+
+func getAttArgs(args interface{}) (string, string, error) {
+	switch a := args.(type) {
+	case string:
+		parts := strings.SplitN(a, ".", 2)
+		if len(parts) == 2 {
+			return parts[0], parts[1], nil
+		}
+	case []interface{}:
+		if len(a) == 2 {
+			logicalID, ok1 := a[0].(string)
+			attr, ok2 := a[1].(string)
+			if ok1 && ok2 {
+				return logicalID, attr, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("invalid Fn::GetAtt %v", args)
+}
+
+func toIndex(v value) (int, bool) {
+	l, ok := v.(literal)
+	if !ok {
+		return 0, false
+	}
+	switch i := l.v.(type) {
+	case float64:
+		return int(i), true
+	case string:
+		n, err := strconv.Atoi(i)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func renderValue(v value) string {
+	var b strings.Builder
+	v.render(&b, 0)
+	return b.String()
+}
+
+func (c *stackConverter) ref(name string) (value, error) {
+	switch name {
+	case "AWS::NoValue":
+		return noValue, nil
+	case "AWS::StackName":
+		return literal{c.stack.name}, nil
+	case "AWS::Region":
+		return c.exporter.useData("aws_region", "current", ".name"), nil
+	case "AWS::AccountId":
+		return c.exporter.useData("aws_caller_identity", "current", ".account_id"), nil
+	case "AWS::Partition":
+		return c.exporter.useData("aws_partition", "current", ".partition"), nil
+	case "AWS::URLSuffix":
+		return c.exporter.useData("aws_partition", "current", ".dns_suffix"), nil
+	}
+
+	if r, ok := c.stack.resources[name]; ok {
+		spec, ok := resourceSpecs[r.Type]
+		if !ok {
+			return c.unsupportedReference(r), nil
+		}
+		attr := spec.refAttribute
+		if attr == "" {
+			attr = "id"
+		}
+		return expression(spec.tfType + "." + c.resourceName(name) + "." + attr), nil
+	}
+
+	if p, ok := c.stack.parameters[name]; ok {
+		return c.exporter.useVariable(c.stack.prefix+"_"+snakeCase(name), fmt.Sprintf("value of parameter %q of stack %q", name, c.stack.name), p.Default), nil
+	}
+	return nil, fmt.Errorf("unresolved reference to %q", name)
+}
+
+func (c *stackConverter) getAtt(logicalID, attr string) (value, error) {
+	r, ok := c.stack.resources[logicalID]
+	if !ok {
+		return nil, fmt.Errorf("Fn::GetAtt references unknown resource %q", logicalID)
+	}
+	spec, ok := resourceSpecs[r.Type]
+	if !ok {
+		return c.unsupportedReference(r), nil
+	}
+	tfAttr, ok := spec.attributes[attr]
+	if !ok {
+		tfAttr = snakeCase(attr)
+	}
+	return expression(spec.tfType + "." + c.resourceName(logicalID) + "." + tfAttr), nil
+}
+
+// unsupportedReference replaces references to resources that cannot be exported with a variable
+func (c *stackConverter) unsupportedReference(r *cfnResource) value {
+	return c.exporter.useVariable(c.resourceName(r.logicalID), fmt.Sprintf("ID of %s (%s) of stack %q, which cannot be exported", r.logicalID, r.Type, c.stack.name), nil)
+}
+
+var subPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+func (c *stackConverter) sub(args interface{}) (value, error) {
+	var (
+		text string
+		vars map[string]interface{}
+	)
+	switch a := args.(type) {
+	case string:
+		text = a
+	case []interface{}:
+		if len(a) != 2 {
+			return nil, fmt.Errorf("invalid Fn::Sub %v", args)
+		}
+		text, _ = a[0].(string)
+		vars, _ = a[1].(map[string]interface{})
+	default:
+		return nil, fmt.Errorf("invalid Fn::Sub %v", args)
+	}
+
+	var (
+		parts   template
+		pending strings.Builder
+	)
+	flush := func() {
+		if pending.Len() > 0 {
+			parts = append(parts, literal{pending.String()})
+			pending.Reset()
+		}
+	}
+
+	last := 0
+	for _, m := range subPattern.FindAllStringSubmatchIndex(text, -1) {
+		pending.WriteString(text[last:m[0]])
+		last = m[1]
+		name := text[m[2]:m[3]]
+		if strings.HasPrefix(name, "!") {
+			pending.WriteString("${" + name[1:] + "}")
+			continue
+		}
+
+		var (
+			v   value
+			err error
+		)
+		if varValue, ok := vars[name]; ok {
+			v, err = c.convert(varValue)
+		} else if i := strings.Index(name, "."); i > 0 && !strings.HasPrefix(name, "AWS::") {
+			v, err = c.getAtt(name[:i], name[i+1:])
+		} else {
+			v, err = c.ref(name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := literalString(v); ok {
+			pending.WriteString(s)
+			continue
+		}
+		flush()
+		parts = append(parts, v)
+	}
+	pending.WriteString(text[last:])
+	flush()
+
+	switch len(parts) {
+	case 0:
+		return literal{""}, nil
+	case 1:
+		if _, ok := literalString(parts[0]); ok {
+			return parts[0], nil
+		}
+	}
+	return parts, nil
+}
+
+func literalString(v value) (string, bool) {
+	l, ok := v.(literal)
+	if !ok {
+		return "", false
+	}
+	s, ok := l.v.(string)
+	return s, ok
+}
+
+func (c *stackConverter) importValue(args interface{}) (value, error) {
+	name, ok := c.resolveString(args)
+	if !ok {
+		return nil, fmt.Errorf("the name of Fn::ImportValue %v cannot be resolved", args)
+	}
+	export, ok := c.exporter.exports[name]
+	if !ok {
+		return c.exporter.useVariable("import_"+snakeCase(name), fmt.Sprintf("value of CloudFormation export %q", name), nil), nil
+	}
+	return (&stackConverter{exporter: c.exporter, stack: export.stack}).convert(export.value)
+}
+
+// resolveString resolves a value to a string without using Terraform expressions, e.g. for export names
+func (c *stackConverter) resolveString(v interface{}) (string, bool) {
+	return c.physicalValue(v, nil)
+}
+
+// physicalValue resolves a value of the template to what it evaluates to in a live stack,
+// given the physical IDs of the resources of the stack
+func (c *stackConverter) physicalValue(v interface{}, physicalIDs map[string]string) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case map[string]interface{}:
+		fn, args, ok := isIntrinsic(v)
+		if !ok {
+			return "", false
+		}
+		switch fn {
+		case "Ref":
+			name, _ := args.(string)
+			if name == "AWS::StackName" {
+				return c.stack.name, true
+			}
+			id, ok := physicalIDs[name]
+			return id, ok && id != ""
+		case "Fn::Sub":
+			text, ok := args.(string)
+			if !ok {
+				return "", false
+			}
+			resolved := true
+			result := subPattern.ReplaceAllStringFunc(text, func(m string) string {
+				name := m[2 : len(m)-1]
+				s, ok := c.physicalValue(map[string]interface{}{"Ref": name}, physicalIDs)
+				if !ok {
+					resolved = false
+				}
+				return s
+			})
+			return result, resolved
+		case "Fn::ImportValue":
+			name, ok := c.physicalValue(args, physicalIDs)
+			if !ok {
+				return "", false
+			}
+			export, ok := c.exporter.exports[name]
+			if !ok {
+				return "", false
+			}
+			other := &stackConverter{exporter: c.exporter, stack: export.stack}
+			return other.physicalValue(export.value, c.exporter.physicalIDs[export.stack.name])
+		}
+	}
+	return "", false
+}
+
+func (c *stackConverter) resourceName(logicalID string) string {
+	return c.stack.prefix + "_" + snakeCase(logicalID)
+}
+
+// convertProperties converts the properties of a resource into attributes and nested blocks of body
+func (c *stackConverter) convertProperties(spec *resourceSpec, properties map[string]interface{}, b *body) error {
+	for _, key := range sortedKeys(properties) {
+		v := properties[key]
+		if spec.skip[key] {
+			continue
+		}
+		name, ok := spec.names[key]
+		if !ok {
+			name = snakeCase(key)
+		}
+
+		if nested, ok := isPlainObject(v); ok && spec.flatten[key] {
+			if err := c.convertProperties(spec, nested, b); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if key == "Tags" {
+			if tags, ok := c.convertTags(v); ok {
+				b.setAttribute(name, tags)
+				continue
+			}
+		}
+
+		if spec.json[key] {
+			converted, err := c.convert(v)
+			if err != nil {
+				return errors.Wrap(err, key)
+			}
+			b.setAttribute(name, call{"jsonencode", []value{converted}})
+			continue
+		}
+
+		if !spec.maps[key] {
+			if nested, ok := isPlainObject(v); ok {
+				if err := c.convertProperties(spec, nested, b.appendBlock(name)); err != nil {
+					return err
+				}
+				continue
+			}
+			if items, ok := plainObjects(v); ok {
+				for _, item := range items {
+					if err := c.convertProperties(spec, item, b.appendBlock(name)); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+		}
+
+		converted, err := c.convert(v)
+		if err != nil {
+			return errors.Wrap(err, key)
+		}
+		b.setAttribute(name, converted)
+	}
+	return nil
+}
+
+// plainObjects returns the items of a non-empty list made only of objects
+func plainObjects(v interface{}) ([]map[string]interface{}, bool) {
+	l, ok := v.([]interface{})
+	if !ok || len(l) == 0 {
+		return nil, false
+	}
+	var items []map[string]interface{}
+	for _, item := range l {
+		m, ok := isPlainObject(item)
+		if !ok {
+			return nil, false
+		}
+		items = append(items, m)
+	}
+	return items, true
+}
+
+// convertTags converts a list of Key/Value tags to a map
+func (c *stackConverter) convertTags(v interface{}) (value, bool) {
+	items, ok := plainObjects(v)
+	if !ok {
+		return nil, false
+	}
+	tags := newObject()
+	for _, item := range items {
+		key, ok := item["Key"].(string)
+		if !ok {
+			return nil, false
+		}
+		converted, err := c.convert(item["Value"])
+		if err != nil {
+			return nil, false
+		}
+		tags.set(key, converted)
+	}
+	return tags, true
+}
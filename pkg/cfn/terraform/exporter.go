@@ -0,0 +1,318 @@
+// Package terraform exports the CloudFormation templates generated by eksctl as Terraform configuration
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Result is the Terraform configuration exported from a set of stacks
+type Result struct {
+	// Config is the content of the Terraform configuration file
+	Config []byte
+	// Unsupported lists the resources that could not be exported, as <stack>/<logical ID> (<type>)
+	Unsupported []string
+	// Imports is the number of import blocks
+	Imports int
+	// MissingImports lists the Terraform resources whose ID could not be determined from the stacks
+	MissingImports []string
+}
+
+// Exporter converts the templates of the stacks of a cluster to a single Terraform configuration,
+// resolving references between resources, including references to outputs of other stacks
+type Exporter struct {
+	clusterName string
+	region      string
+
+	stacks      []*stack
+	exports     map[string]*export
+	physicalIDs map[string]map[string]string
+
+	data      map[string]bool
+	locals    map[string]value
+	variables map[string]*variable
+}
+
+type cfnTemplate struct {
+	Parameters map[string]*cfnParameter
+	Mappings   map[string]interface{}
+	Conditions map[string]interface{}
+	Resources  map[string]*cfnResource
+	Outputs    map[string]*cfnOutput
+}
+
+type cfnParameter struct {
+	Default interface{}
+}
+
+type cfnResource struct {
+	logicalID  string
+	Type       string
+	Properties map[string]interface{}
+	DependsOn  interface{}
+	Condition  string
+}
+
+type cfnOutput struct {
+	Value  interface{}
+	Export *struct {
+		Name interface{}
+	}
+}
+
+type stack struct {
+	name string
+	// prefix is prepended to the names of all Terraform resources of the stack
+	prefix string
+
+	resources  map[string]*cfnResource
+	parameters map[string]*cfnParameter
+	mappings   map[string]interface{}
+	conditions map[string]interface{}
+	outputs    map[string]*cfnOutput
+}
+
+type export struct {
+	stack *stack
+	value interface{}
+}
+
+type variable struct {
+	description  string
+	defaultValue interface{}
+}
+
+// NewExporter creates an exporter for the stacks of a cluster
+func NewExporter(clusterName, region string) *Exporter {
+	return &Exporter{
+		clusterName: clusterName,
+		region:      region,
+		exports:     map[string]*export{},
+		physicalIDs: map[string]map[string]string{},
+		data:        map[string]bool{},
+		locals:      map[string]value{},
+		variables:   map[string]*variable{},
+	}
+}
+
+// AddStack adds the JSON template of a stack; stacks must be added before the stacks importing their outputs are exported
+func (e *Exporter) AddStack(stackName string, templateBody []byte) error {
+	var t cfnTemplate
+	if err := json.Unmarshal(templateBody, &t); err != nil {
+		return errors.Wrapf(err, "parsing template of stack %q", stackName)
+	}
+	for logicalID, r := range t.Resources {
+		r.logicalID = logicalID
+	}
+
+	s := &stack{
+		name:       stackName,
+		prefix:     snakeCase(strings.TrimPrefix(stackName, fmt.Sprintf("eksctl-%s-", e.clusterName))),
+		resources:  t.Resources,
+		parameters: t.Parameters,
+		mappings:   t.Mappings,
+		conditions: t.Conditions,
+		outputs:    t.Outputs,
+	}
+	if len(s.conditions) > 0 {
+		return fmt.Errorf("stack %q uses conditions, which cannot be exported", stackName)
+	}
+
+	c := &stackConverter{exporter: e, stack: s}
+	for name, output := range s.outputs {
+		if output.Export == nil {
+			continue
+		}
+		exportName, ok := c.resolveString(output.Export.Name)
+		if !ok {
+			return fmt.Errorf("the export name of output %q of stack %q cannot be resolved", name, stackName)
+		}
+		e.exports[exportName] = &export{stack: s, value: output.Value}
+	}
+	e.stacks = append(e.stacks, s)
+	return nil
+}
+
+// SetPhysicalIDs sets the physical IDs of the resources of an existing stack, by logical ID,
+// so that import blocks are added for the resources of the stack
+func (e *Exporter) SetPhysicalIDs(stackName string, physicalIDs map[string]string) {
+	e.physicalIDs[stackName] = physicalIDs
+}
+
+// Export converts all stacks
+func (e *Exporter) Export() (*Result, error) {
+	result := &Result{}
+	root := &body{}
+	resources := &body{}
+	imports := &body{}
+	outputs := &body{}
+
+	for _, s := range e.stacks {
+		c := &stackConverter{exporter: e, stack: s}
+		_, withImports := e.physicalIDs[s.name]
+
+		for _, logicalID := range sortedResourceIDs(s.resources) {
+			r := s.resources[logicalID]
+			if r.Condition != "" {
+				return nil, fmt.Errorf("resource %q of stack %q uses a condition, which cannot be exported", logicalID, s.name)
+			}
+			if _, ok := resourceSpecs[r.Type]; !ok {
+				result.Unsupported = append(result.Unsupported, fmt.Sprintf("%s/%s (%s)", s.name, logicalID, r.Type))
+				continue
+			}
+			converted, err := c.convertResource(r)
+			if err != nil {
+				return nil, errors.Wrapf(err, "converting resource %q of stack %q", logicalID, s.name)
+			}
+			for _, tr := range converted {
+				resources.appendBlock("resource", tr.typ, tr.name).items = tr.body.items
+				if !withImports {
+					continue
+				}
+				if tr.importID == "" {
+					result.MissingImports = append(result.MissingImports, tr.typ+"."+tr.name)
+					continue
+				}
+				ib := imports.appendBlock("import")
+				ib.setAttribute("to", expression(tr.typ+"."+tr.name))
+				ib.setAttribute("id", literal{tr.importID})
+				result.Imports++
+			}
+		}
+
+		for _, name := range sortedOutputNames(s.outputs) {
+			v, err := c.convert(s.outputs[name].Value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "converting output %q of stack %q", name, s.name)
+			}
+			outputs.appendBlock("output", s.prefix+"_"+snakeCase(name)).setAttribute("value", v)
+		}
+	}
+
+	tf := root.appendBlock("terraform")
+	if result.Imports > 0 {
+		// import blocks were added in Terraform 1.5
+		tf.setAttribute("required_version", literal{">= 1.5.0"})
+	}
+	aws := newObject()
+	aws.set("source", literal{"hashicorp/aws"})
+	aws.set("version", literal{">= 5.0"})
+	tf.appendBlock("required_providers").setAttribute("aws", aws)
+
+	root.appendBlock("provider", "aws").setAttribute("region", literal{e.region})
+
+	for _, key := range sortedSet(e.data) {
+		parts := strings.SplitN(key, ".", 2)
+		root.appendBlock("data", parts[0], parts[1])
+	}
+
+	if len(e.locals) > 0 {
+		locals := root.appendBlock("locals")
+		for _, name := range sortedValueNames(e.locals) {
+			locals.setAttribute(name, e.locals[name])
+		}
+	}
+
+	for _, name := range sortedVariableNames(e.variables) {
+		v := e.variables[name]
+		vb := root.appendBlock("variable", name)
+		vb.setAttribute("description", literal{v.description})
+		if v.defaultValue != nil {
+			c := &stackConverter{exporter: e}
+			converted, err := c.convert(v.defaultValue)
+			if err != nil {
+				return nil, err
+			}
+			vb.setAttribute("default", converted)
+		}
+	}
+
+	root.items = append(root.items, resources.items...)
+	root.items = append(root.items, imports.items...)
+	root.items = append(root.items, outputs.items...)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Terraform configuration exported by eksctl from the CloudFormation stacks of cluster %q\n\n", e.clusterName)
+	root.render(&sb, 0)
+	result.Config = []byte(sb.String())
+	return result, nil
+}
+
+func (e *Exporter) useData(typ, name, attr string) value {
+	e.data[typ+"."+name] = true
+	return expression("data." + typ + "." + name + attr)
+}
+
+func (e *Exporter) useVariable(name, description string, defaultValue interface{}) value {
+	if _, ok := e.variables[name]; !ok {
+		e.variables[name] = &variable{description: description, defaultValue: defaultValue}
+	}
+	return expression("var." + name)
+}
+
+// useMapping adds a mapping of a stack to the locals, mappings with the same name are expected to be the same in all stacks
+func (e *Exporter) useMapping(s *stack, mapName string) (string, error) {
+	name := snakeCase(mapName)
+	if _, ok := e.locals[name]; ok {
+		return name, nil
+	}
+	mapping, ok := s.mappings[mapName]
+	if !ok {
+		return "", fmt.Errorf("Fn::FindInMap references unknown mapping %q", mapName)
+	}
+	v, err := (&stackConverter{exporter: e, stack: s}).convert(mapping)
+	if err != nil {
+		return "", err
+	}
+	e.locals[name] = v
+	return name, nil
+}
+
+func sortedResourceIDs(m map[string]*cfnResource) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedOutputNames(m map[string]*cfnOutput) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedValueNames(m map[string]value) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedVariableNames(m map[string]*variable) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedSet(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,229 @@
+package terraform
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const clusterTemplate = `{
+  "Mappings": {
+    "ServicePrincipalPartitionMap": {
+      "aws": {"EKS": "eks.amazonaws.com"}
+    }
+  },
+  "Resources": {
+    "VPC": {
+      "Type": "AWS::EC2::VPC",
+      "Properties": {
+        "CidrBlock": "192.168.0.0/16",
+        "Tags": [{"Key": "Name", "Value": {"Fn::Sub": "${AWS::StackName}/VPC"}}]
+      }
+    },
+    "SubnetPublicUSWEST2A": {
+      "Type": "AWS::EC2::Subnet",
+      "Properties": {
+        "AvailabilityZone": "us-west-2a",
+        "CidrBlock": "192.168.0.0/19",
+        "VpcId": {"Ref": "VPC"}
+      }
+    },
+    "ControlPlaneSecurityGroup": {
+      "Type": "AWS::EC2::SecurityGroup",
+      "Properties": {
+        "GroupDescription": "Communication between the control plane and worker nodegroups",
+        "VpcId": {"Ref": "VPC"}
+      }
+    },
+    "ServiceRole": {
+      "Type": "AWS::IAM::Role",
+      "Properties": {
+        "AssumeRolePolicyDocument": {
+          "Statement": [{
+            "Action": ["sts:AssumeRole"],
+            "Effect": "Allow",
+            "Principal": {"Service": [{"Fn::FindInMap": ["ServicePrincipalPartitionMap", {"Ref": "AWS::Partition"}, "EKS"]}]}
+          }]
+        }
+      }
+    },
+    "ControlPlane": {
+      "Type": "AWS::EKS::Cluster",
+      "Properties": {
+        "Name": "test",
+        "ResourcesVpcConfig": {
+          "SecurityGroupIds": [{"Ref": "ControlPlaneSecurityGroup"}],
+          "SubnetIds": [{"Ref": "SubnetPublicUSWEST2A"}]
+        },
+        "RoleArn": {"Fn::GetAtt": ["ServiceRole", "Arn"]},
+        "Version": "1.21"
+      },
+      "DependsOn": ["ServiceRole"]
+    },
+    "FargatePodExecutionRole": {
+      "Type": "AWS::Custom::Unknown",
+      "Properties": {}
+    }
+  },
+  "Outputs": {
+    "SecurityGroup": {
+      "Value": {"Ref": "ControlPlaneSecurityGroup"},
+      "Export": {"Name": {"Fn::Sub": "${AWS::StackName}::SecurityGroup"}}
+    },
+    "SubnetsPublic": {
+      "Value": {"Fn::Join": [",", [{"Ref": "SubnetPublicUSWEST2A"}]]},
+      "Export": {"Name": {"Fn::Sub": "${AWS::StackName}::SubnetsPublic"}}
+    }
+  }
+}`
+
+const nodeGroupTemplate = `{
+  "Resources": {
+    "SG": {
+      "Type": "AWS::EC2::SecurityGroup",
+      "Properties": {
+        "GroupDescription": "nodes",
+        "SecurityGroupIngress": [{
+          "SourceSecurityGroupId": {"Fn::ImportValue": "eksctl-test-cluster::SecurityGroup"},
+          "IpProtocol": "tcp",
+          "FromPort": 443,
+          "ToPort": 443
+        }]
+      }
+    },
+    "NodeGroup": {
+      "Type": "AWS::AutoScaling::AutoScalingGroup",
+      "Properties": {
+        "MinSize": "2",
+        "MaxSize": "2",
+        "VPCZoneIdentifier": {"Fn::Split": [",", {"Fn::ImportValue": "eksctl-test-cluster::SubnetsPublic"}]},
+        "LaunchTemplate": {
+          "LaunchTemplateName": {"Fn::Sub": "${AWS::StackName}"},
+          "Version": {"Fn::GetAtt": ["NodeGroupLaunchTemplate", "LatestVersionNumber"]}
+        },
+        "Tags": [{"Key": "Name", "Value": "test-ng-1-Node", "PropagateAtLaunch": "true"}]
+      }
+    },
+    "NodeGroupLaunchTemplate": {
+      "Type": "AWS::EC2::LaunchTemplate",
+      "Properties": {
+        "LaunchTemplateName": {"Fn::Sub": "${AWS::StackName}"},
+        "LaunchTemplateData": {
+          "ImageId": "ami-123",
+          "SecurityGroupIds": [{"Ref": "SG"}]
+        }
+      }
+    }
+  }
+}`
+
+var _ = Describe("Exporter", func() {
+	var exporter *Exporter
+
+	BeforeEach(func() {
+		exporter = NewExporter("test", "us-west-2")
+		Expect(exporter.AddStack("eksctl-test-cluster", []byte(clusterTemplate))).To(Succeed())
+		Expect(exporter.AddStack("eksctl-test-nodegroup-ng-1", []byte(nodeGroupTemplate))).To(Succeed())
+	})
+
+	It("converts resources and resolves references", func() {
+		result, err := exporter.Export()
+		Expect(err).NotTo(HaveOccurred())
+		config := string(result.Config)
+
+		Expect(config).To(ContainSubstring(`provider "aws" {
+  region = "us-west-2"
+}`))
+		Expect(config).To(ContainSubstring(`data "aws_partition" "current" {}`))
+		Expect(config).To(ContainSubstring(`resource "aws_vpc" "cluster_vpc" {
+  cidr_block = "192.168.0.0/16"
+  tags = {
+    Name = "eksctl-test-cluster/VPC"
+  }
+}`))
+		Expect(config).To(ContainSubstring(`resource "aws_eks_cluster" "cluster_control_plane" {
+  name       = "test"
+  role_arn   = aws_iam_role.cluster_service_role.arn
+  version    = "1.21"
+  depends_on = [aws_iam_role.cluster_service_role]
+
+  vpc_config {
+    security_group_ids = [aws_security_group.cluster_control_plane_security_group.id]
+    subnet_ids         = [aws_subnet.cluster_subnet_public_uswest2a.id]
+  }
+}`))
+		Expect(config).To(ContainSubstring(`Service = [local.service_principal_partition_map[data.aws_partition.current.partition]["EKS"]]`))
+
+		By("resolving imported values to resources of other stacks")
+		Expect(config).To(ContainSubstring(`source_security_group_id = aws_security_group.cluster_control_plane_security_group.id`))
+		Expect(config).To(ContainSubstring(`vpc_zone_identifier = [aws_subnet.cluster_subnet_public_uswest2a.id]`))
+
+		By("referencing the launch template of the stack by ID")
+		Expect(config).To(ContainSubstring(`  launch_template {
+    id      = aws_launch_template.nodegroup_ng_1_node_group_launch_template.id
+    version = aws_launch_template.nodegroup_ng_1_node_group_launch_template.latest_version
+  }`))
+		Expect(config).To(ContainSubstring(`  tag {
+    key                 = "Name"
+    value               = "test-ng-1-Node"
+    propagate_at_launch = true
+  }`))
+
+		By("converting inline security group rules to separate rules")
+		Expect(config).To(ContainSubstring(`resource "aws_security_group_rule" "nodegroup_ng_1_sg_ingress_0" {`))
+		Expect(config).To(ContainSubstring(`resource "aws_security_group_rule" "nodegroup_ng_1_sg_egress_default" {`))
+
+		Expect(config).To(ContainSubstring(`output "cluster_subnets_public" {
+  value = join(",", [aws_subnet.cluster_subnet_public_uswest2a.id])
+}`))
+		Expect(config).NotTo(ContainSubstring("import {"))
+		Expect(result.Unsupported).To(ConsistOf("eksctl-test-cluster/FargatePodExecutionRole (AWS::Custom::Unknown)"))
+	})
+
+	It("adds import blocks for the resources of existing stacks", func() {
+		exporter.SetPhysicalIDs("eksctl-test-cluster", map[string]string{
+			"VPC":                       "vpc-1",
+			"SubnetPublicUSWEST2A":      "subnet-1",
+			"ControlPlaneSecurityGroup": "sg-1",
+			"ServiceRole":               "eksctl-test-cluster-ServiceRole",
+			"ControlPlane":              "test",
+		})
+		exporter.SetPhysicalIDs("eksctl-test-nodegroup-ng-1", map[string]string{
+			"SG":                      "sg-2",
+			"NodeGroup":               "eksctl-test-nodegroup-ng-1-NodeGroup",
+			"NodeGroupLaunchTemplate": "lt-1",
+		})
+
+		result, err := exporter.Export()
+		Expect(err).NotTo(HaveOccurred())
+		config := string(result.Config)
+
+		Expect(config).To(ContainSubstring(`required_version = ">= 1.5.0"`))
+		Expect(config).To(ContainSubstring(`import {
+  to = aws_vpc.cluster_vpc
+  id = "vpc-1"
+}`))
+		Expect(config).To(ContainSubstring(`import {
+  to = aws_security_group_rule.nodegroup_ng_1_sg_ingress_0
+  id = "sg-2_ingress_tcp_443_443_sg-1"
+}`))
+		Expect(config).To(ContainSubstring(`import {
+  to = aws_security_group_rule.nodegroup_ng_1_sg_egress_default
+  id = "sg-2_egress_all_0_0_0.0.0.0/0"
+}`))
+		Expect(result.Imports).To(Equal(11))
+		Expect(result.MissingImports).To(BeEmpty())
+	})
+
+	It("fails on templates using conditions", func() {
+		err := exporter.AddStack("eksctl-test-addon", []byte(`{"Conditions": {"IsIPv6": {}}, "Resources": {}}`))
+		Expect(err).To(MatchError(ContainSubstring("uses conditions")))
+	})
+})
+
+var _ = Describe("snakeCase", func() {
+	It("splits words and acronyms", func() {
+		Expect(snakeCase("VPCZoneIdentifier")).To(Equal("vpc_zone_identifier"))
+		Expect(snakeCase("SubnetPublicUSWEST2A")).To(Equal("subnet_public_uswest2a"))
+		Expect(snakeCase("nodegroup-ng-1")).To(Equal("nodegroup_ng_1"))
+	})
+})
@@ -0,0 +1,335 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const indentWidth = 2
+
+// value is a Terraform expression
+type value interface {
+	// render writes the expression, indent is the indentation of the line the expression starts on
+	render(b *strings.Builder, indent int)
+}
+
+// literal is a string, number, bool or null
+type literal struct {
+	v interface{}
+}
+
+// expression is a raw expression, e.g. a reference
+type expression string
+
+// template is a string template made of literal strings and interpolated expressions
+type template []value
+
+type list []value
+
+type object struct {
+	keys   []string
+	values map[string]value
+}
+
+type call struct {
+	name string
+	args []value
+}
+
+// noValue is the result of converting AWS::NoValue, it removes the attribute or element it is assigned to
+var noValue = expression("")
+
+func isNoValue(v value) bool {
+	e, ok := v.(expression)
+	return ok && e == noValue
+}
+
+func newObject() *object {
+	return &object{values: map[string]value{}}
+}
+
+func (o *object) set(key string, v value) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = v
+}
+
+func (l literal) render(b *strings.Builder, _ int) {
+	switch v := l.v.(type) {
+	case nil:
+		b.WriteString("null")
+	case string:
+		b.WriteString(`"` + escapeString(v) + `"`)
+	case float64:
+		b.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	case int:
+		b.WriteString(strconv.Itoa(v))
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	default:
+		fmt.Fprintf(b, "%v", v)
+	}
+}
+
+func (e expression) render(b *strings.Builder, _ int) {
+	b.WriteString(string(e))
+}
+
+func (t template) render(b *strings.Builder, indent int) {
+	if len(t) == 1 {
+		if _, ok := t[0].(literal); !ok {
+			t[0].render(b, indent)
+			return
+		}
+	}
+	b.WriteString(`"`)
+	for _, part := range t {
+		if l, ok := part.(literal); ok {
+			if s, ok := l.v.(string); ok {
+				b.WriteString(escapeString(s))
+				continue
+			}
+		}
+		b.WriteString("${")
+		part.render(b, indent)
+		b.WriteString("}")
+	}
+	b.WriteString(`"`)
+}
+
+func (l list) render(b *strings.Builder, indent int) {
+	if len(l) == 0 {
+		b.WriteString("[]")
+		return
+	}
+	if inline := renderInline(l); !strings.Contains(inline, "\n") && len(inline)+indent < 100 {
+		b.WriteString(inline)
+		return
+	}
+	b.WriteString("[\n")
+	for _, v := range l {
+		writeIndent(b, indent+indentWidth)
+		v.render(b, indent+indentWidth)
+		b.WriteString(",\n")
+	}
+	writeIndent(b, indent)
+	b.WriteString("]")
+}
+
+func renderInline(l list) string {
+	var b strings.Builder
+	b.WriteString("[")
+	for i, v := range l {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		v.render(&b, 0)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func (o *object) render(b *strings.Builder, indent int) {
+	if len(o.keys) == 0 {
+		b.WriteString("{}")
+		return
+	}
+	keys := make([]string, len(o.keys))
+	for i, k := range o.keys {
+		keys[i] = objectKey(k)
+	}
+	b.WriteString("{\n")
+	writeAligned(b, indent+indentWidth, keys, func(i int) value { return o.values[o.keys[i]] })
+	writeIndent(b, indent)
+	b.WriteString("}")
+}
+
+func (c call) render(b *strings.Builder, indent int) {
+	b.WriteString(c.name + "(")
+	for i, arg := range c.args {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		arg.render(b, indent)
+	}
+	b.WriteString(")")
+}
+
+// writeAligned writes key = value lines, aligning the equals signs of consecutive single-line values
+func writeAligned(b *strings.Builder, indent int, keys []string, valueAt func(int) value) {
+	rendered := make([]string, len(keys))
+	for i := range keys {
+		var vb strings.Builder
+		valueAt(i).render(&vb, indent)
+		rendered[i] = vb.String()
+	}
+
+	for start := 0; start < len(keys); {
+		if strings.Contains(rendered[start], "\n") {
+			writeIndent(b, indent)
+			fmt.Fprintf(b, "%s = %s\n", keys[start], rendered[start])
+			start++
+			continue
+		}
+		end, width := start, 0
+		for ; end < len(keys) && !strings.Contains(rendered[end], "\n"); end++ {
+			if len(keys[end]) > width {
+				width = len(keys[end])
+			}
+		}
+		for i := start; i < end; i++ {
+			writeIndent(b, indent)
+			fmt.Fprintf(b, "%-*s = %s\n", width, keys[i], rendered[i])
+		}
+		start = end
+	}
+}
+
+func writeIndent(b *strings.Builder, indent int) {
+	b.WriteString(strings.Repeat(" ", indent))
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+func objectKey(key string) string {
+	if identifierPattern.MatchString(key) {
+		return key
+	}
+	return `"` + escapeString(key) + `"`
+}
+
+func escapeString(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			// template sequences are escaped by doubling the leading character
+			b.WriteRune(r)
+			b.WriteRune(r)
+		case !unicode.IsPrint(r):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// body is the body of a block, holding attributes and nested blocks in order
+type body struct {
+	items []interface{}
+}
+
+type attribute struct {
+	name  string
+	value value
+}
+
+type block struct {
+	typ    string
+	labels []string
+	body   *body
+}
+
+func (b *body) setAttribute(name string, v value) {
+	if isNoValue(v) {
+		return
+	}
+	for _, item := range b.items {
+		if a, ok := item.(*attribute); ok && a.name == name {
+			a.value = v
+			return
+		}
+	}
+	b.items = append(b.items, &attribute{name: name, value: v})
+}
+
+func (b *body) appendBlock(typ string, labels ...string) *body {
+	nested := &body{}
+	b.items = append(b.items, &block{typ: typ, labels: labels, body: nested})
+	return nested
+}
+
+// render writes the attributes of the body followed by its blocks, like `terraform fmt` orders them
+func (b *body) render(sb *strings.Builder, indent int) {
+	var (
+		attributes []*attribute
+		blocks     []*block
+	)
+	for _, item := range b.items {
+		switch item := item.(type) {
+		case *attribute:
+			attributes = append(attributes, item)
+		case *block:
+			blocks = append(blocks, item)
+		}
+	}
+
+	keys := make([]string, len(attributes))
+	for i, a := range attributes {
+		keys[i] = a.name
+	}
+	writeAligned(sb, indent, keys, func(i int) value { return attributes[i].value })
+
+	for i, nested := range blocks {
+		if i > 0 || len(attributes) > 0 {
+			sb.WriteString("\n")
+		}
+		nested.render(sb, indent)
+	}
+}
+
+func (b *block) render(sb *strings.Builder, indent int) {
+	writeIndent(sb, indent)
+	sb.WriteString(b.typ)
+	for _, label := range b.labels {
+		sb.WriteString(` "` + escapeString(label) + `"`)
+	}
+	if len(b.body.items) == 0 {
+		sb.WriteString(" {}\n")
+		return
+	}
+	sb.WriteString(" {\n")
+	b.body.render(sb, indent+indentWidth)
+	writeIndent(sb, indent)
+	sb.WriteString("}\n")
+}
+
+// snakeCase converts a CloudFormation name to a Terraform name, e.g. VPCZoneIdentifier to vpc_zone_identifier
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				if !strings.HasSuffix(b.String(), "_") {
+					b.WriteRune('_')
+				}
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.Trim(b.String(), "_")
+}
@@ -0,0 +1,624 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// resourceSpec describes how a CloudFormation resource type maps to a Terraform resource type
+type resourceSpec struct {
+	tfType string
+	// refAttribute is the attribute Ref resolves to, id by default
+	refAttribute string
+	// attributes maps Fn::GetAtt attributes to Terraform attributes, that are otherwise converted to snake case
+	attributes map[string]string
+	// names maps properties to Terraform arguments, that are otherwise converted to snake case
+	names map[string]string
+	// maps lists the properties that are maps rather than nested blocks
+	maps map[string]bool
+	// json lists the properties that are JSON documents
+	json map[string]bool
+	// flatten lists the properties whose properties belong to the resource itself
+	flatten map[string]bool
+	// skip lists the properties that have no Terraform equivalent or are converted separately
+	skip map[string]bool
+
+	// convert replaces the generic conversion of the resource
+	convert func(c *stackConverter, spec *resourceSpec, r *cfnResource) ([]*tfResource, error)
+	// importID returns the ID the resource is imported with given the ID CloudFormation reports,
+	// which is used as is by default
+	importID func(c *stackConverter, properties map[string]interface{}, physicalID string) (string, bool)
+}
+
+// tfResource is a converted resource
+type tfResource struct {
+	typ      string
+	name     string
+	body     *body
+	importID string
+}
+
+// resourceSpecs lists the supported resource types, it is set in init as the conversion functions refer to it
+var resourceSpecs map[string]*resourceSpec
+
+func init() {
+	resourceSpecs = map[string]*resourceSpec{
+		"AWS::EC2::VPC": {
+			tfType: "aws_vpc",
+		},
+		"AWS::EC2::Subnet": {
+			tfType: "aws_subnet",
+		},
+		"AWS::EC2::InternetGateway": {
+			tfType: "aws_internet_gateway",
+		},
+		"AWS::EC2::VPCGatewayAttachment": {
+			tfType: "aws_internet_gateway_attachment",
+			importID: func(c *stackConverter, properties map[string]interface{}, _ string) (string, bool) {
+				return c.physicalIDs(":", properties["InternetGatewayId"], properties["VpcId"])
+			},
+		},
+		"AWS::EC2::EgressOnlyInternetGateway": {
+			tfType: "aws_egress_only_internet_gateway",
+		},
+		"AWS::EC2::EIP": {
+			tfType:       "aws_eip",
+			refAttribute: "public_ip",
+		},
+		"AWS::EC2::NatGateway": {
+			tfType: "aws_nat_gateway",
+		},
+		"AWS::EC2::RouteTable": {
+			tfType: "aws_route_table",
+		},
+		"AWS::EC2::Route": {
+			tfType: "aws_route",
+			importID: func(c *stackConverter, properties map[string]interface{}, _ string) (string, bool) {
+				destination := properties["DestinationCidrBlock"]
+				if destination == nil {
+					destination = properties["DestinationIpv6CidrBlock"]
+				}
+				return c.physicalIDs("_", properties["RouteTableId"], destination)
+			},
+		},
+		"AWS::EC2::SubnetRouteTableAssociation": {
+			tfType: "aws_route_table_association",
+			importID: func(c *stackConverter, properties map[string]interface{}, _ string) (string, bool) {
+				return c.physicalIDs("/", properties["SubnetId"], properties["RouteTableId"])
+			},
+		},
+		"AWS::EC2::SecurityGroup": {
+			tfType: "aws_security_group",
+			attributes: map[string]string{
+				"GroupId": "id",
+			},
+			names: map[string]string{
+				"GroupDescription": "description",
+				"GroupName":        "name",
+			},
+			skip: map[string]bool{
+				"SecurityGroupIngress": true,
+				"SecurityGroupEgress":  true,
+			},
+			convert: convertSecurityGroup,
+		},
+		"AWS::EC2::SecurityGroupIngress": {
+			tfType:  "aws_security_group_rule",
+			convert: convertSecurityGroupRule,
+		},
+		"AWS::EC2::SecurityGroupEgress": {
+			tfType:  "aws_security_group_rule",
+			convert: convertSecurityGroupRule,
+		},
+		"AWS::EC2::VPCEndpoint": {
+			tfType: "aws_vpc_endpoint",
+			names: map[string]string{
+				"PolicyDocument": "policy",
+			},
+			json: map[string]bool{
+				"PolicyDocument": true,
+			},
+		},
+		"AWS::EC2::LaunchTemplate": {
+			tfType: "aws_launch_template",
+			attributes: map[string]string{
+				"LatestVersionNumber":  "latest_version",
+				"DefaultVersionNumber": "default_version",
+			},
+			names: map[string]string{
+				"LaunchTemplateName": "name",
+				"SecurityGroupIds":   "vpc_security_group_ids",
+				"Groups":             "security_groups",
+			},
+			flatten: map[string]bool{
+				"LaunchTemplateData": true,
+			},
+		},
+		"AWS::IAM::Role": {
+			tfType:       "aws_iam_role",
+			refAttribute: "name",
+			attributes: map[string]string{
+				"RoleId": "unique_id",
+			},
+			names: map[string]string{
+				"RoleName":                 "name",
+				"AssumeRolePolicyDocument": "assume_role_policy",
+				"Policies":                 "inline_policy",
+				"PolicyName":               "name",
+				"PolicyDocument":           "policy",
+			},
+			json: map[string]bool{
+				"AssumeRolePolicyDocument": true,
+				"PolicyDocument":           true,
+			},
+		},
+		"AWS::IAM::Policy": {
+			tfType:  "aws_iam_role_policy",
+			convert: convertRolePolicy,
+		},
+		"AWS::IAM::InstanceProfile": {
+			tfType:       "aws_iam_instance_profile",
+			refAttribute: "name",
+			names: map[string]string{
+				"InstanceProfileName": "name",
+			},
+			convert: convertInstanceProfile,
+		},
+		"AWS::AutoScaling::AutoScalingGroup": {
+			tfType:       "aws_autoscaling_group",
+			refAttribute: "name",
+			names: map[string]string{
+				"AutoScalingGroupName":           "name",
+				"Cooldown":                       "default_cooldown",
+				"TargetGroupARNs":                "target_group_arns",
+				"Overrides":                      "override",
+				"LifecycleHookSpecificationList": "initial_lifecycle_hook",
+				"LifecycleHookName":              "name",
+				"NotificationTargetARN":          "notification_target_arn",
+				"RoleARN":                        "role_arn",
+			},
+			skip: map[string]bool{
+				"MetricsCollection": true,
+			},
+			convert: convertAutoScalingGroup,
+		},
+		"AWS::EKS::Cluster": {
+			tfType:       "aws_eks_cluster",
+			refAttribute: "name",
+			attributes: map[string]string{
+				"CertificateAuthorityData": "certificate_authority[0].data",
+				"ClusterSecurityGroupId":   "vpc_config[0].cluster_security_group_id",
+				"OpenIdConnectIssuerUrl":   "identity[0].oidc[0].issuer",
+			},
+			names: map[string]string{
+				"ResourcesVpcConfig": "vpc_config",
+			},
+		},
+		"AWS::EKS::Nodegroup": {
+			tfType: "aws_eks_node_group",
+			names: map[string]string{
+				"NodegroupName":        "node_group_name",
+				"NodeRole":             "node_role_arn",
+				"Subnets":              "subnet_ids",
+				"Taints":               "taint",
+				"SourceSecurityGroups": "source_security_group_ids",
+				"ForceUpdateEnabled":   "force_update_version",
+			},
+			maps: map[string]bool{
+				"Labels": true,
+				"Tags":   true,
+			},
+			convert: convertNodeGroup,
+			importID: func(_ *stackConverter, _ map[string]interface{}, physicalID string) (string, bool) {
+				// the physical ID is <cluster>/<nodegroup>
+				return strings.Replace(physicalID, "/", ":", 1), physicalID != ""
+			},
+		},
+	}
+}
+
+// convertResource converts a resource of the stack into one or more Terraform resources
+func (c *stackConverter) convertResource(r *cfnResource) ([]*tfResource, error) {
+	spec := resourceSpecs[r.Type]
+	if spec.convert != nil {
+		return spec.convert(c, spec, r)
+	}
+	tr, err := c.convertGeneric(spec, c.resourceName(r.logicalID), r, r.Properties)
+	if err != nil {
+		return nil, err
+	}
+	return []*tfResource{tr}, nil
+}
+
+// convertGeneric converts a resource whose properties map to Terraform arguments one to one
+func (c *stackConverter) convertGeneric(spec *resourceSpec, name string, r *cfnResource, properties map[string]interface{}) (*tfResource, error) {
+	tr := &tfResource{
+		typ:  spec.tfType,
+		name: name,
+		body: &body{},
+	}
+	if err := c.convertProperties(spec, properties, tr.body); err != nil {
+		return nil, err
+	}
+	c.addDependencies(r, tr.body)
+	tr.importID = c.importID(spec, properties, r.logicalID)
+	return tr, nil
+}
+
+func (c *stackConverter) addDependencies(r *cfnResource, b *body) {
+	var dependsOn []string
+	switch d := r.DependsOn.(type) {
+	case string:
+		dependsOn = []string{d}
+	case []interface{}:
+		for _, item := range d {
+			if s, ok := item.(string); ok {
+				dependsOn = append(dependsOn, s)
+			}
+		}
+	}
+
+	var references list
+	for _, logicalID := range dependsOn {
+		dependency, ok := c.stack.resources[logicalID]
+		if !ok {
+			continue
+		}
+		spec, ok := resourceSpecs[dependency.Type]
+		if !ok {
+			continue
+		}
+		references = append(references, expression(spec.tfType+"."+c.resourceName(logicalID)))
+	}
+	if len(references) > 0 {
+		b.setAttribute("depends_on", references)
+	}
+}
+
+// importID returns the ID of an existing resource, or an empty string if it is unknown
+func (c *stackConverter) importID(spec *resourceSpec, properties map[string]interface{}, logicalID string) string {
+	physicalIDs, ok := c.exporter.physicalIDs[c.stack.name]
+	if !ok {
+		return ""
+	}
+	physicalID := physicalIDs[logicalID]
+	if spec.importID == nil {
+		return physicalID
+	}
+	id, ok := spec.importID(c, properties, physicalID)
+	if !ok {
+		return ""
+	}
+	return id
+}
+
+// physicalIDs resolves the values of an existing stack and joins them
+func (c *stackConverter) physicalIDs(separator string, values ...interface{}) (string, bool) {
+	var ids []string
+	for _, v := range values {
+		id, ok := c.physicalValue(v, c.exporter.physicalIDs[c.stack.name])
+		if !ok {
+			return "", false
+		}
+		ids = append(ids, id)
+	}
+	return strings.Join(ids, separator), true
+}
+
+// withoutProperties returns a copy of properties without keys
+func withoutProperties(properties map[string]interface{}, keys ...string) map[string]interface{} {
+	result := make(map[string]interface{}, len(properties))
+	for k, v := range properties {
+		result[k] = v
+	}
+	for _, k := range keys {
+		delete(result, k)
+	}
+	return result
+}
+
+// convertSecurityGroup converts the inline rules of a security group to separate rules, as security group rules
+// cannot be both inline and separate in Terraform; the default egress rule is also added as Terraform removes it
+func convertSecurityGroup(c *stackConverter, spec *resourceSpec, r *cfnResource) ([]*tfResource, error) {
+	name := c.resourceName(r.logicalID)
+	sg, err := c.convertGeneric(spec, name, r, r.Properties)
+	if err != nil {
+		return nil, err
+	}
+	converted := []*tfResource{sg}
+
+	groupID := map[string]interface{}{"Ref": r.logicalID}
+	addRules := func(key, ruleType string) error {
+		rules, _ := plainObjects(r.Properties[key])
+		for i, rule := range rules {
+			properties := withoutProperties(rule)
+			properties["GroupId"] = groupID
+			tr, err := c.convertRule(fmt.Sprintf("%s_%s_%d", name, ruleType, i), ruleType, r, properties)
+			if err != nil {
+				return errors.Wrap(err, key)
+			}
+			converted = append(converted, tr)
+		}
+		return nil
+	}
+	if err := addRules("SecurityGroupIngress", "ingress"); err != nil {
+		return nil, err
+	}
+	if err := addRules("SecurityGroupEgress", "egress"); err != nil {
+		return nil, err
+	}
+
+	if _, ok := r.Properties["SecurityGroupEgress"]; !ok {
+		tr, err := c.convertRule(name+"_egress_default", "egress", r, map[string]interface{}{
+			"GroupId":     groupID,
+			"IpProtocol":  "-1",
+			"CidrIp":      "0.0.0.0/0",
+			"Description": "Allow all outbound traffic, like the default egress rule of a security group",
+		})
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, tr)
+	}
+	return converted, nil
+}
+
+func convertSecurityGroupRule(c *stackConverter, _ *resourceSpec, r *cfnResource) ([]*tfResource, error) {
+	ruleType := "ingress"
+	if r.Type == "AWS::EC2::SecurityGroupEgress" {
+		ruleType = "egress"
+	}
+	tr, err := c.convertRule(c.resourceName(r.logicalID), ruleType, r, r.Properties)
+	if err != nil {
+		return nil, err
+	}
+	return []*tfResource{tr}, nil
+}
+
+// convertRule converts an ingress or egress rule, which is a separate resource or part of a security group
+func (c *stackConverter) convertRule(name, ruleType string, r *cfnResource, properties map[string]interface{}) (*tfResource, error) {
+	tr := &tfResource{
+		typ:  "aws_security_group_rule",
+		name: name,
+		body: &body{},
+	}
+	b := tr.body
+	b.setAttribute("type", literal{ruleType})
+
+	attributes := []struct {
+		property, name string
+		list           bool
+	}{
+		{"GroupId", "security_group_id", false},
+		{"Description", "description", false},
+		{"IpProtocol", "protocol", false},
+		{"FromPort", "from_port", false},
+		{"ToPort", "to_port", false},
+		{"CidrIp", "cidr_blocks", true},
+		{"CidrIpv6", "ipv6_cidr_blocks", true},
+		{"SourceSecurityGroupId", "source_security_group_id", false},
+		{"DestinationSecurityGroupId", "source_security_group_id", false},
+		{"SourcePrefixListId", "prefix_list_ids", true},
+		{"DestinationPrefixListId", "prefix_list_ids", true},
+	}
+	for _, a := range attributes {
+		v, ok := properties[a.property]
+		if !ok {
+			continue
+		}
+		converted, err := c.convert(v)
+		if err != nil {
+			return nil, errors.Wrap(err, a.property)
+		}
+		if a.list {
+			converted = list{converted}
+		}
+		b.setAttribute(a.name, converted)
+	}
+
+	protocol, _ := c.physicalValue(properties["IpProtocol"], nil)
+	if protocol == "-1" {
+		// ports are required by Terraform but ignored for all protocols
+		b.setAttribute("from_port", literal{0})
+		b.setAttribute("to_port", literal{0})
+	}
+	c.addDependencies(r, b)
+
+	if _, ok := c.exporter.physicalIDs[c.stack.name]; ok {
+		tr.importID, _ = c.ruleImportID(ruleType, properties)
+	}
+	return tr, nil
+}
+
+// ruleImportID returns <security group>_<type>_<protocol>_<from port>_<to port>_<source>
+func (c *stackConverter) ruleImportID(ruleType string, properties map[string]interface{}) (string, bool) {
+	physicalIDs := c.exporter.physicalIDs[c.stack.name]
+	groupID, ok := c.physicalValue(properties["GroupId"], physicalIDs)
+	if !ok {
+		return "", false
+	}
+	protocol, _ := c.physicalValue(properties["IpProtocol"], physicalIDs)
+	fromPort, _ := c.physicalValue(properties["FromPort"], physicalIDs)
+	toPort, _ := c.physicalValue(properties["ToPort"], physicalIDs)
+	if protocol == "-1" {
+		protocol, fromPort, toPort = "all", "0", "0"
+	}
+
+	var source string
+	for _, key := range []string{"CidrIp", "CidrIpv6", "SourceSecurityGroupId", "DestinationSecurityGroupId", "SourcePrefixListId", "DestinationPrefixListId"} {
+		if v, ok := properties[key]; ok {
+			if source, ok = c.physicalValue(v, physicalIDs); !ok {
+				return "", false
+			}
+			break
+		}
+	}
+	if source == "" {
+		return "", false
+	}
+	return strings.Join([]string{groupID, ruleType, protocol, fromPort, toPort, source}, "_"), true
+}
+
+// convertRolePolicy converts a policy to an inline policy of each role it is attached to
+func convertRolePolicy(c *stackConverter, spec *resourceSpec, r *cfnResource) ([]*tfResource, error) {
+	roles, _ := r.Properties["Roles"].([]interface{})
+	if len(roles) == 0 || r.Properties["Groups"] != nil || r.Properties["Users"] != nil {
+		return nil, errors.New("only policies attached to roles can be exported")
+	}
+
+	var converted []*tfResource
+	for i, role := range roles {
+		name := c.resourceName(r.logicalID)
+		if len(roles) > 1 {
+			name = fmt.Sprintf("%s_%d", name, i)
+		}
+		tr := &tfResource{
+			typ:  spec.tfType,
+			name: name,
+			body: &body{},
+		}
+		for _, a := range []struct {
+			property, name string
+			value          interface{}
+		}{
+			{"PolicyName", "name", r.Properties["PolicyName"]},
+			{"Roles", "role", role},
+		} {
+			v, err := c.convert(a.value)
+			if err != nil {
+				return nil, errors.Wrap(err, a.property)
+			}
+			tr.body.setAttribute(a.name, v)
+		}
+		document, err := c.convert(r.Properties["PolicyDocument"])
+		if err != nil {
+			return nil, errors.Wrap(err, "PolicyDocument")
+		}
+		tr.body.setAttribute("policy", call{"jsonencode", []value{document}})
+		c.addDependencies(r, tr.body)
+
+		if _, ok := c.exporter.physicalIDs[c.stack.name]; ok {
+			tr.importID, _ = c.physicalIDs(":", role, r.Properties["PolicyName"])
+		}
+		converted = append(converted, tr)
+	}
+	return converted, nil
+}
+
+func convertInstanceProfile(c *stackConverter, spec *resourceSpec, r *cfnResource) ([]*tfResource, error) {
+	roles, _ := r.Properties["Roles"].([]interface{})
+	if len(roles) != 1 {
+		return nil, errors.New("only instance profiles with a single role can be exported")
+	}
+	tr, err := c.convertGeneric(spec, c.resourceName(r.logicalID), r, withoutProperties(r.Properties, "Roles"))
+	if err != nil {
+		return nil, err
+	}
+	role, err := c.convert(roles[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "Roles")
+	}
+	tr.body.setAttribute("role", role)
+	return []*tfResource{tr}, nil
+}
+
+func convertAutoScalingGroup(c *stackConverter, spec *resourceSpec, r *cfnResource) ([]*tfResource, error) {
+	properties := withoutProperties(r.Properties, "Tags")
+
+	// the launch template is referenced by name, which is replaced with a reference to the launch template of the stack
+	if lt, ok := isPlainObject(properties["LaunchTemplate"]); ok {
+		properties["LaunchTemplate"] = c.launchTemplateByID(lt, "Id")
+	}
+	if policy, ok := isPlainObject(properties["MixedInstancesPolicy"]); ok {
+		policy = withoutProperties(policy)
+		if lt, ok := isPlainObject(policy["LaunchTemplate"]); ok {
+			lt = withoutProperties(lt)
+			if spec, ok := isPlainObject(lt["LaunchTemplateSpecification"]); ok {
+				lt["LaunchTemplateSpecification"] = c.launchTemplateByID(spec, "LaunchTemplateId")
+			}
+			policy["LaunchTemplate"] = lt
+		}
+		properties["MixedInstancesPolicy"] = policy
+	}
+
+	tr, err := c.convertGeneric(spec, c.resourceName(r.logicalID), r, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	if metrics, ok := plainObjects(r.Properties["MetricsCollection"]); ok {
+		for _, m := range metrics {
+			for _, a := range []struct{ property, name string }{{"Metrics", "enabled_metrics"}, {"Granularity", "metrics_granularity"}} {
+				v, err := c.convert(m[a.property])
+				if err != nil {
+					return nil, errors.Wrap(err, "MetricsCollection")
+				}
+				tr.body.setAttribute(a.name, v)
+			}
+		}
+	}
+
+	// tags of autoscaling groups are blocks as they are propagated to instances individually
+	tags, _ := plainObjects(r.Properties["Tags"])
+	for _, tag := range tags {
+		tb := tr.body.appendBlock("tag")
+		for _, a := range []struct{ property, name string }{{"Key", "key"}, {"Value", "value"}, {"PropagateAtLaunch", "propagate_at_launch"}} {
+			v, err := c.convert(tag[a.property])
+			if err != nil {
+				return nil, errors.Wrap(err, "Tags")
+			}
+			if l, ok := v.(literal); ok && a.property == "PropagateAtLaunch" {
+				if s, ok := l.v.(string); ok {
+					v = literal{s == "true"}
+				}
+			}
+			tb.setAttribute(a.name, v)
+		}
+	}
+	return []*tfResource{tr}, nil
+}
+
+// launchTemplateByID replaces the name of a launch template of the stack with its ID
+func (c *stackConverter) launchTemplateByID(spec map[string]interface{}, idProperty string) map[string]interface{} {
+	name, ok := c.resolveString(spec["LaunchTemplateName"])
+	if !ok {
+		return spec
+	}
+	for logicalID, r := range c.stack.resources {
+		if r.Type != "AWS::EC2::LaunchTemplate" {
+			continue
+		}
+		if ltName, ok := c.resolveString(r.Properties["LaunchTemplateName"]); ok && ltName == name {
+			spec = withoutProperties(spec, "LaunchTemplateName")
+			spec[idProperty] = map[string]interface{}{"Ref": logicalID}
+			return spec
+		}
+	}
+	return spec
+}
+
+func convertNodeGroup(c *stackConverter, spec *resourceSpec, r *cfnResource) ([]*tfResource, error) {
+	properties := r.Properties
+	// the version of the launch template is required by Terraform, EKS uses the default version when it is not set
+	if lt, ok := isPlainObject(properties["LaunchTemplate"]); ok && lt["Version"] == nil {
+		lt = withoutProperties(lt)
+		lt["Version"] = "$Default"
+		if ref, ok := lt["Id"].(map[string]interface{}); ok {
+			if logicalID, ok := ref["Ref"].(string); ok {
+				if ltResource, ok := c.stack.resources[logicalID]; ok && ltResource.Type == "AWS::EC2::LaunchTemplate" {
+					lt["Version"] = map[string]interface{}{"Fn::GetAtt": []interface{}{logicalID, "DefaultVersionNumber"}}
+				}
+			}
+		}
+		properties = withoutProperties(properties)
+		properties["LaunchTemplate"] = lt
+	}
+
+	tr, err := c.convertGeneric(spec, c.resourceName(r.logicalID), r, properties)
+	if err != nil {
+		return nil, err
+	}
+	return []*tfResource{tr}, nil
+}
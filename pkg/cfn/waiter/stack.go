@@ -9,6 +9,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/utils/progress"
 )
 
 // NextDelay returns the amount of time to wait before the next retry given the number of attempts.
@@ -16,7 +18,12 @@ type NextDelay func(attempts int) time.Duration
 
 // WaitForStack waits for the cluster stack to reach a success or failure state, and returns the stack.
 func WaitForStack(ctx context.Context, cfnAPI cloudformationiface.CloudFormationAPI, stackID, stackName string, nextDelay NextDelay) (*cloudformation.Stack, error) {
-	var lastStack *cloudformation.Stack
+	var (
+		lastStack  *cloudformation.Stack
+		lastStatus string
+		attempt    int
+	)
+	waiterName := "wait_" + stackName
 	waiter := &Waiter{
 		NextDelay: nextDelay,
 		Operation: func() (bool, error) {
@@ -24,12 +31,35 @@ func WaitForStack(ctx context.Context, cfnAPI cloudformationiface.CloudFormation
 				err     error
 				success bool
 			)
+			attempt++
+			progress.Emit(progress.Event{Type: progress.WaiterPoll, Waiter: waiterName, Attempt: attempt})
 			lastStack, success, err = describeStackStatus(context.Background(), cfnAPI, stackID, stackName)
+			if lastStack != nil && *lastStack.StackStatus != lastStatus {
+				lastStatus = *lastStack.StackStatus
+				progress.Emit(progress.Event{
+					Type:   progress.StackStatus,
+					Stack:  stackName,
+					Status: lastStatus,
+					Reason: aws.StringValue(lastStack.StackStatusReason),
+				})
+			}
 			return success, err
 		},
 	}
 
-	if err := waiter.Wait(ctx); err != nil {
+	startTime := time.Now()
+	err := waiter.Wait(ctx)
+	status := "succeeded"
+	if err != nil {
+		status = "failed"
+	}
+	progress.Emit(progress.Event{
+		Type:            progress.WaiterFinished,
+		Waiter:          waiterName,
+		Status:          status,
+		DurationSeconds: time.Since(startTime).Seconds(),
+	}.WithError(err))
+	if err != nil {
 		return nil, err
 	}
 
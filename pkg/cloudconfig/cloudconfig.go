@@ -91,11 +91,16 @@ func (c *CloudConfig) AddScript(p, s string) {
 
 // RunScript adds and runs a script on the node
 func (c *CloudConfig) RunScript(name, s string) {
-	p := path.Join(scriptDir, name)
+	p := ScriptPath(name)
 	c.AddScript(p, s)
 	c.AddCommand(p)
 }
 
+// ScriptPath returns the path of a script added by RunScript on the node
+func ScriptPath(name string) string {
+	return path.Join(scriptDir, name)
+}
+
 // Encode encodes the cloud config
 func (c *CloudConfig) Encode() (string, error) {
 	data, err := yaml.Marshal(c)
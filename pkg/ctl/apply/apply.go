@@ -0,0 +1,72 @@
+package apply
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/weaveworks/eksctl/pkg/actions/apply"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+// Command will create the `apply` command
+func Command(cmd *cmdutils.Cmd) {
+	applyWithRunFunc(cmd, doApply)
+}
+
+func applyWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, prune bool) error) {
+	cmd.ClusterConfig = api.NewClusterConfig()
+	cmd.Plan = false
+
+	var prune bool
+
+	cmd.SetDescription("apply", "Reconcile a cluster with a ClusterConfig file",
+		"Compares the ClusterConfig with the live cluster and creates, updates and (with --prune) deletes nodegroups, "+
+			"iamserviceaccounts, addons, Fargate profiles, identity providers, CloudWatch logging and endpoint access to match it. "+
+			"Existing nodegroups are scaled and have their instance role mapped; the other fields of existing nodegroups that "+
+			"differ from the config (e.g. instance type, AMI or labels) are only reported, use the nodegroup commands for those")
+
+	cmd.CobraCommand.Args = cobra.NoArgs
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
+		if err := cmdutils.NewApplyLoader(cmd).Load(); err != nil {
+			return err
+		}
+		return runFunc(cmd, prune)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		fs.BoolVar(&cmd.Plan, "plan", false, "only print the changes that would be made, do not apply them")
+		fs.BoolVar(&prune, "prune", false, "delete resources that exist in the cluster but are not declared in the config file")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, &cmd.ProviderConfig, true)
+}
+
+func doApply(cmd *cmdutils.Cmd, prune bool) error {
+	cfg := cmd.ClusterConfig
+
+	ctl, err := cmd.NewProviderForExistingCluster()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
+	return apply.New(cfg, ctl, clientSet).Apply(ctx, apply.Options{
+		Plan:  cmd.Plan,
+		Prune: prune,
+	})
+}
@@ -0,0 +1,64 @@
+package apply
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ctl/ctltest"
+)
+
+var _ = Describe("apply", func() {
+	var (
+		cfg   *api.ClusterConfig
+		prune bool
+	)
+
+	newMockCmd := func(args ...string) *ctltest.MockCmd {
+		return ctltest.NewMockCmd(func(cmd *cmdutils.Cmd, runFunc func(*cmdutils.Cmd) error) {
+			applyWithRunFunc(cmd, func(cmd *cmdutils.Cmd, p bool) error {
+				prune = p
+				return runFunc(cmd)
+			})
+		}, "root", append([]string{"apply"}, args...)...)
+	}
+
+	BeforeEach(func() {
+		prune = false
+		cfg = &api.ClusterConfig{
+			TypeMeta: api.ClusterConfigTypeMeta(),
+			Metadata: &api.ClusterMeta{
+				Name:   "cluster-1",
+				Region: "us-west-2",
+			},
+		}
+	})
+
+	It("returns an error if the config file is not set", func() {
+		_, err := newMockCmd().Execute()
+		Expect(err).To(MatchError(ContainSubstring("--config-file must be set")))
+	})
+
+	It("does not accept arguments", func() {
+		_, err := newMockCmd("cluster-1", "--config-file", ctltest.CreateConfigFile(cfg)).Execute()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("applies changes by default", func() {
+		cmd := newMockCmd("--config-file", ctltest.CreateConfigFile(cfg))
+		_, err := cmd.Execute()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmd.Cmd.Plan).To(BeFalse())
+		Expect(prune).To(BeFalse())
+		Expect(cmd.Cmd.ClusterConfig.Metadata.Name).To(Equal("cluster-1"))
+	})
+
+	It("sets plan and prune from flags", func() {
+		cmd := newMockCmd("--config-file", ctltest.CreateConfigFile(cfg), "--plan", "--prune")
+		_, err := cmd.Execute()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmd.Cmd.Plan).To(BeTrue())
+		Expect(prune).To(BeTrue())
+	})
+})
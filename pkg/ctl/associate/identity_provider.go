@@ -83,5 +83,8 @@ func doAssociateIdentityProvider(cmd *cmdutils.Cmd, timeout time.Duration) error
 		options.WaitTimeout = &timeout
 	}
 
-	return manager.Associate(options)
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
+	return manager.Associate(ctx, options)
 }
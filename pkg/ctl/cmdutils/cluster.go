@@ -1,6 +1,9 @@
 package cmdutils
 
 import (
+	"fmt"
+	"strings"
+
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
 	"github.com/weaveworks/eksctl/pkg/eks"
@@ -14,13 +17,13 @@ func ApplyFilter(clusterConfig *api.ClusterConfig, ngFilter filter.NodegroupFilt
 	)
 
 	for _, ng := range clusterConfig.NodeGroups {
-		if ngFilter.Match(ng.NameString()) {
+		if ngFilter.MatchNodeGroup(ng.NodeGroupBase) {
 			filteredNodeGroups = append(filteredNodeGroups, ng)
 		}
 	}
 
 	for _, ng := range clusterConfig.ManagedNodeGroups {
-		if ngFilter.Match(ng.NameString()) {
+		if ngFilter.MatchNodeGroup(ng.NodeGroupBase) {
 			filteredManagedNodeGroups = append(filteredManagedNodeGroups, ng)
 		}
 	}
@@ -56,3 +59,22 @@ func ToNodePools(clusterConfig *api.ClusterConfig) []api.NodePool {
 	}
 	return nodePools
 }
+
+// SetClusterVersion resolves the "auto" and "latest" versions and validates the result
+func SetClusterVersion(meta *api.ClusterMeta) error {
+	if meta.Version == "" || meta.Version == "auto" {
+		meta.Version = api.DefaultVersion
+	}
+	if meta.Version == "latest" {
+		meta.Version = api.LatestVersion
+	}
+	if meta.Version != api.DefaultVersion {
+		if !api.IsSupportedVersion(meta.Version) {
+			if api.IsDeprecatedVersion(meta.Version) {
+				return fmt.Errorf("invalid version, %s is no longer supported, supported values: %s\nsee also: https://docs.aws.amazon.com/eks/latest/userguide/kubernetes-versions.html", meta.Version, strings.Join(api.SupportedVersions(), ", "))
+			}
+			return fmt.Errorf("invalid version, supported values: %s", strings.Join(api.SupportedVersions(), ", "))
+		}
+	}
+	return nil
+}
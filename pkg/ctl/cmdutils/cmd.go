@@ -30,11 +30,45 @@ type Cmd struct {
 // instance of eks.ClusterProvider, it may return an error if configuration
 // is invalid or region is not supported
 func (c *Cmd) NewCtl() (*eks.ClusterProvider, error) {
+	if err := c.setDefaultsAndValidate(); err != nil {
+		return nil, err
+	}
+
+	ctl, err := eks.New(&c.ProviderConfig, c.ClusterConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ctl.IsSupportedRegion() {
+		return nil, ErrUnsupportedRegion(&c.ProviderConfig)
+	}
+
+	return ctl, nil
+}
+
+// InitOffline performs the same defaulting and validation as NewCtl without creating
+// an AWS session; as the region cannot be looked up, it must be set explicitly
+func (c *Cmd) InitOffline() error {
+	if c.ProviderConfig.Region == "" {
+		c.ProviderConfig.Region = c.ClusterConfig.Metadata.Region
+	}
+	if c.ProviderConfig.Region == "" {
+		return ErrMustBeSet("--region")
+	}
+	if !api.IsSupportedRegion(c.ProviderConfig.Region) {
+		return ErrUnsupportedRegion(&c.ProviderConfig)
+	}
+	c.ClusterConfig.Metadata.Region = c.ProviderConfig.Region
+
+	return c.setDefaultsAndValidate()
+}
+
+func (c *Cmd) setDefaultsAndValidate() error {
 	api.SetClusterConfigDefaults(c.ClusterConfig)
 
 	if err := api.ValidateClusterConfig(c.ClusterConfig); err != nil {
 		if c.Validate {
-			return nil, err
+			return err
 		}
 		logger.Warning("ignoring validation error: %s", err.Error())
 	}
@@ -42,7 +76,7 @@ func (c *Cmd) NewCtl() (*eks.ClusterProvider, error) {
 	for i, ng := range c.ClusterConfig.NodeGroups {
 		if err := api.ValidateNodeGroup(i, ng); err != nil {
 			if c.Validate {
-				return nil, err
+				return err
 			}
 			logger.Warning("ignoring validation error: %s", err.Error())
 		}
@@ -54,20 +88,10 @@ func (c *Cmd) NewCtl() (*eks.ClusterProvider, error) {
 	for i, ng := range c.ClusterConfig.ManagedNodeGroups {
 		api.SetManagedNodeGroupDefaults(ng, c.ClusterConfig.Metadata)
 		if err := api.ValidateManagedNodeGroup(ng, i); err != nil {
-			return nil, err
+			return err
 		}
 	}
-
-	ctl, err := eks.New(&c.ProviderConfig, c.ClusterConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	if !ctl.IsSupportedRegion() {
-		return nil, ErrUnsupportedRegion(&c.ProviderConfig)
-	}
-
-	return ctl, nil
+	return nil
 }
 
 // NewProviderForExistingCluster is a wrapper for NewCtl that also validates that the cluster exists and is not a
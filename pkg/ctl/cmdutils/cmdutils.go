@@ -114,9 +114,12 @@ func AddCommonFlagsForAWS(group *NamedFlagSetGroup, p *api.ProviderConfig, addCf
 		if err := fs.MarkHidden("aws-api-timeout"); err != nil {
 			logger.Debug("ignoring error %q", err.Error())
 		}
+		fs.BoolVar(&p.Quiet, "quiet", false, "do not print CloudFormation stack events while waiting for stacks")
 		if addCfnOptions {
 			fs.StringVar(&p.CloudFormationRoleARN, "cfn-role-arn", "", "IAM role used by CloudFormation to call AWS API on your behalf")
 			fs.BoolVar(&p.CloudFormationDisableRollback, "cfn-disable-rollback", false, "for debugging: If a stack fails, do not roll it back. Be careful, this may lead to unintentional resource consumption!")
+			fs.StringVar(&p.CloudFormationTemplateBucket, "cfn-template-bucket", "", "S3 bucket to upload templates that are too large to be passed to CloudFormation inline to (default: a bucket created by eksctl)")
+			fs.IntVar(&p.MaxParallelStacks, "max-parallel-stacks", 0, "maximum number of stacks to create or delete at the same time (default: no limit)")
 		}
 	})
 }
@@ -184,6 +187,11 @@ func AddUpdateAuthConfigMap(fs *pflag.FlagSet, updateAuthConfigMap *bool, descri
 	fs.BoolVar(updateAuthConfigMap, "update-auth-configmap", true, description)
 }
 
+// AddDisableProtectionFlag adds common --disable-protection flag
+func AddDisableProtectionFlag(fs *pflag.FlagSet, disableProtection *bool) {
+	fs.BoolVar(disableProtection, "disable-protection", false, "Disable termination protection of stacks in order to delete them")
+}
+
 // AddSubnetIDs adds common --subnet-ids flag
 func AddSubnetIDs(fs *pflag.FlagSet, subnetIDs *[]string, description string) {
 	fs.StringSliceVar(subnetIDs, "subnet-ids", nil, description)
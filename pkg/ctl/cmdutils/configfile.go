@@ -192,6 +192,21 @@ func NewMetadataLoader(cmd *Cmd) ClusterConfigLoader {
 	return l
 }
 
+// setClusterVPCDefaults sets the VPC and NAT gateway of a config file that does not declare them
+func setClusterVPCDefaults(clusterConfig *api.ClusterConfig) {
+	if clusterConfig.VPC == nil {
+		clusterConfig.VPC = api.NewClusterVPC()
+	}
+
+	if clusterConfig.VPC.NAT == nil {
+		clusterConfig.VPC.NAT = api.DefaultClusterNAT()
+	}
+
+	if !api.IsSetAndNonEmptyString(clusterConfig.VPC.NAT.Gateway) {
+		*clusterConfig.VPC.NAT.Gateway = api.ClusterSingleNAT
+	}
+}
+
 // NewCreateClusterLoader will load config or use flags for 'eksctl create cluster'
 func NewCreateClusterLoader(cmd *Cmd, ngFilter *filter.NodeGroupFilter, ng *api.NodeGroup, params *CreateClusterCmdParams) ClusterConfigLoader {
 	l := newCommonClusterConfigLoader(cmd)
@@ -215,7 +230,7 @@ func NewCreateClusterLoader(cmd *Cmd, ngFilter *filter.NodeGroupFilter, ng *api.
 
 	validateDryRun := func() error {
 		if !params.DryRun {
-			return nil
+			return validateOutputTemplates(params.OutputTemplatesDir)
 		}
 
 		flagsIncompatibleWithDryRun := append([]string{
@@ -232,17 +247,7 @@ func NewCreateClusterLoader(cmd *Cmd, ngFilter *filter.NodeGroupFilter, ng *api.
 
 	l.validateWithConfigFile = func() error {
 		clusterConfig := l.ClusterConfig
-		if clusterConfig.VPC == nil {
-			clusterConfig.VPC = api.NewClusterVPC()
-		}
-
-		if clusterConfig.VPC.NAT == nil {
-			clusterConfig.VPC.NAT = api.DefaultClusterNAT()
-		}
-
-		if !api.IsSetAndNonEmptyString(clusterConfig.VPC.NAT.Gateway) {
-			*clusterConfig.VPC.NAT.Gateway = api.ClusterSingleNAT
-		}
+		setClusterVPCDefaults(clusterConfig)
 
 		if clusterConfig.PrivateCluster != nil && clusterConfig.PrivateCluster.Enabled {
 			if clusterEndpoints := clusterConfig.VPC.ClusterEndpoints; clusterEndpoints != nil && (clusterEndpoints.PublicAccess != nil || clusterEndpoints.PrivateAccess != nil) {
@@ -343,6 +348,14 @@ func validateDryRunOptions(cmd *cobra.Command, incompatibleFlags []string) error
 	return nil
 }
 
+// validateOutputTemplates checks that --output-templates is only used with --dry-run, which is validated separately
+func validateOutputTemplates(outputTemplatesDir string) error {
+	if outputTemplatesDir != "" {
+		return errors.New("--output-templates can only be used with --dry-run")
+	}
+	return nil
+}
+
 // NewCreateNodeGroupLoader will load config or use flags for 'eksctl create nodegroup'
 func NewCreateNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *filter.NodeGroupFilter, ngOptions CreateNGOptions, mngOptions CreateManagedNGOptions) ClusterConfigLoader {
 	l := newCommonClusterConfigLoader(cmd)
@@ -351,7 +364,7 @@ func NewCreateNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *filter.Node
 
 	validateDryRun := func() error {
 		if !ngOptions.DryRun {
-			return nil
+			return validateOutputTemplates(ngOptions.OutputTemplatesDir)
 		}
 		// Filters (--include / --exclude) cannot be represented in ClusterConfig, however, they affect the output, so they're allowed
 		flagsIncompatibleWithDryRun := append([]string{
@@ -546,6 +559,56 @@ func NewUtilsEnableLoggingLoader(cmd *Cmd) ClusterConfigLoader {
 	return l
 }
 
+// NewUtilsExportTerraformLoader will load config or use flags for 'eksctl utils export-terraform';
+// the config file is required unless the stacks of an existing cluster are exported
+func NewUtilsExportTerraformLoader(cmd *Cmd, withImports bool) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.validateWithoutConfigFile = func() error {
+		if !withImports {
+			return ErrMustBeSet("--config-file")
+		}
+		return l.validateMetadataWithoutConfigFile()
+	}
+
+	l.validateWithConfigFile = func() error {
+		if withImports {
+			return nil
+		}
+		// the stacks are rendered from the config file as `create cluster` would, so it gets the same
+		// VPC defaults; the remaining cluster and nodegroup defaults are set by cmd.InitOffline
+		setClusterVPCDefaults(l.ClusterConfig)
+		api.SetClusterEndpointAccessDefaults(l.ClusterConfig.VPC)
+		return nil
+	}
+
+	return l
+}
+
+// NewUtilsAdoptLoader will load config for 'eksctl utils adopt', the resources
+// to adopt are described in the config file, so it is required
+func NewUtilsAdoptLoader(cmd *Cmd) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.validateWithoutConfigFile = func() error {
+		return ErrMustBeSet("--config-file")
+	}
+
+	return l
+}
+
+// NewUtilsRenderUserDataLoader will load config for 'eksctl utils render-userdata', the nodegroups
+// are rendered offline from the config file, so it is required
+func NewUtilsRenderUserDataLoader(cmd *Cmd) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.validateWithoutConfigFile = func() error {
+		return ErrMustBeSet("--config-file")
+	}
+
+	return l
+}
+
 // NewUtilsEnableEndpointAccessLoader will load config or use flags for 'eksctl utils update-cluster-endpoints'.
 func NewUtilsEnableEndpointAccessLoader(cmd *Cmd, privateAccess, publicAccess bool) ClusterConfigLoader {
 	l := newCommonClusterConfigLoader(cmd)
@@ -771,9 +834,31 @@ func NewUpdateNodegroupLoader(cmd *Cmd) ClusterConfigLoader {
 	l := newCommonClusterConfigLoader(cmd)
 
 	l.validateWithConfigFile = func() error {
-		length := len(l.ClusterConfig.ManagedNodeGroups)
+		length := len(l.ClusterConfig.ManagedNodeGroups) + len(l.ClusterConfig.NodeGroups)
 		if length < 1 {
-			return ErrMustBeSet("managedNodeGroups field")
+			return ErrMustBeSet("managedNodeGroups or nodeGroups field")
+		}
+
+		for i, ng := range l.ClusterConfig.NodeGroups {
+			logger.Info("validating nodegroup %q", ng.Name)
+
+			var unsupportedFields []string
+			var err error
+			if unsupportedFields, err = validateSupportedConfigFields(*ng.NodeGroupBase, []string{"Name"}, unsupportedFields); err != nil {
+				return err
+			}
+
+			if unsupportedFields, err = validateSupportedConfigFields(*ng, []string{"NodeGroupBase", "ScheduledActions", "LifecycleHooks", "WarmPool"}, unsupportedFields); err != nil {
+				return err
+			}
+
+			if len(unsupportedFields) > 0 {
+				logger.Warning("unchanged fields for nodegroup %s: the following fields remain unchanged; they are not supported by `eksctl update nodegroup`: %s", ng.Name, strings.Join(unsupportedFields[:], ", "))
+			}
+
+			if err := api.ValidateNodeGroup(i, ng); err != nil {
+				return err
+			}
 		}
 
 		for _, ng := range l.ClusterConfig.ManagedNodeGroups {
@@ -806,6 +891,16 @@ func NewUpdateNodegroupLoader(cmd *Cmd) ClusterConfigLoader {
 	return l
 }
 
+// NewApplyLoader will load config for `eksctl apply`, which always requires a config file
+func NewApplyLoader(cmd *Cmd) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.validateWithoutConfigFile = func() error {
+		return ErrMustBeSet("--config-file")
+	}
+	return l
+}
+
 // NewGetNodegroupLoader loads config file and validates command for `eksctl get nodegroup`.
 func NewGetNodegroupLoader(cmd *Cmd, ng *api.NodeGroup) ClusterConfigLoader {
 	l := newCommonClusterConfigLoader(cmd)
@@ -19,6 +19,10 @@ type CreateClusterCmdParams struct {
 	WithoutNodeGroup      bool
 	Fargate               bool
 	DryRun                bool
+	OutputTemplatesDir    string
+	RollbackOnInterrupt   bool
+	Resume                bool
+	JournalConfigMap      bool
 	CreateNGOptions
 	CreateManagedNGOptions
 }
@@ -35,4 +39,5 @@ type CreateNGOptions struct {
 	InstallNeuronDevicePlugin bool
 	InstallNvidiaDevicePlugin bool
 	DryRun                    bool
+	OutputTemplatesDir        string
 }
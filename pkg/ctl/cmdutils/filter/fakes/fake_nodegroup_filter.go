@@ -26,6 +26,17 @@ type FakeNodegroupFilter struct {
 	matchReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	MatchNodeGroupStub        func(*v1alpha5.NodeGroupBase) bool
+	matchNodeGroupMutex       sync.RWMutex
+	matchNodeGroupArgsForCall []struct {
+		arg1 *v1alpha5.NodeGroupBase
+	}
+	matchNodeGroupReturns struct {
+		result1 bool
+	}
+	matchNodeGroupReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	SetOnlyLocalStub        func(eksiface.EKSAPI, filter.StackLister, *v1alpha5.ClusterConfig) error
 	setOnlyLocalMutex       sync.RWMutex
 	setOnlyLocalArgsForCall []struct {
@@ -136,6 +147,67 @@ func (fake *FakeNodegroupFilter) MatchReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeNodegroupFilter) MatchNodeGroup(arg1 *v1alpha5.NodeGroupBase) bool {
+	fake.matchNodeGroupMutex.Lock()
+	ret, specificReturn := fake.matchNodeGroupReturnsOnCall[len(fake.matchNodeGroupArgsForCall)]
+	fake.matchNodeGroupArgsForCall = append(fake.matchNodeGroupArgsForCall, struct {
+		arg1 *v1alpha5.NodeGroupBase
+	}{arg1})
+	stub := fake.MatchNodeGroupStub
+	fakeReturns := fake.matchNodeGroupReturns
+	fake.recordInvocation("MatchNodeGroup", []interface{}{arg1})
+	fake.matchNodeGroupMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeNodegroupFilter) MatchNodeGroupCallCount() int {
+	fake.matchNodeGroupMutex.RLock()
+	defer fake.matchNodeGroupMutex.RUnlock()
+	return len(fake.matchNodeGroupArgsForCall)
+}
+
+func (fake *FakeNodegroupFilter) MatchNodeGroupCalls(stub func(*v1alpha5.NodeGroupBase) bool) {
+	fake.matchNodeGroupMutex.Lock()
+	defer fake.matchNodeGroupMutex.Unlock()
+	fake.MatchNodeGroupStub = stub
+}
+
+func (fake *FakeNodegroupFilter) MatchNodeGroupArgsForCall(i int) *v1alpha5.NodeGroupBase {
+	fake.matchNodeGroupMutex.RLock()
+	defer fake.matchNodeGroupMutex.RUnlock()
+	argsForCall := fake.matchNodeGroupArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeNodegroupFilter) MatchNodeGroupReturns(result1 bool) {
+	fake.matchNodeGroupMutex.Lock()
+	defer fake.matchNodeGroupMutex.Unlock()
+	fake.MatchNodeGroupStub = nil
+	fake.matchNodeGroupReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeNodegroupFilter) MatchNodeGroupReturnsOnCall(i int, result1 bool) {
+	fake.matchNodeGroupMutex.Lock()
+	defer fake.matchNodeGroupMutex.Unlock()
+	fake.MatchNodeGroupStub = nil
+	if fake.matchNodeGroupReturnsOnCall == nil {
+		fake.matchNodeGroupReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.matchNodeGroupReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeNodegroupFilter) SetOnlyLocal(arg1 eksiface.EKSAPI, arg2 filter.StackLister, arg3 *v1alpha5.ClusterConfig) error {
 	fake.setOnlyLocalMutex.Lock()
 	ret, specificReturn := fake.setOnlyLocalReturnsOnCall[len(fake.setOnlyLocalArgsForCall)]
@@ -206,6 +278,8 @@ func (fake *FakeNodegroupFilter) Invocations() map[string][][]interface{} {
 	defer fake.logInfoMutex.RUnlock()
 	fake.matchMutex.RLock()
 	defer fake.matchMutex.RUnlock()
+	fake.matchNodeGroupMutex.RLock()
+	defer fake.matchNodeGroupMutex.RUnlock()
 	fake.setOnlyLocalMutex.RLock()
 	defer fake.setOnlyLocalMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -85,17 +85,23 @@ func (f *Filter) describeExcludeRules() string {
 // Match given name against the filter and returns
 // true or false if it has to be included or excluded
 func (f *Filter) Match(name string) bool {
+	return f.matchAny(name)
+}
+
+// matchAny matches the names of a resource that goes by several names against the filter;
+// the resource is included when any of its names is included and none of them is excluded
+func (f *Filter) matchAny(names ...string) bool {
 	if f.ExcludeAll {
 		return false // force exclude
 	}
 
-	// Name overwrites
-	if f.includeNames.Has(name) && !f.excludeNames.Has(name) {
-		return true
+	// Name overwrites, exclusion takes precedence
+	if f.excludeNames.HasAny(names...) {
+		return false
 	}
 
-	if f.excludeNames.Has(name) {
-		return false
+	if f.includeNames.HasAny(names...) {
+		return true
 	}
 
 	hasIncludeRules := f.hasIncludeRules()
@@ -105,20 +111,22 @@ func (f *Filter) Match(name string) bool {
 		return true
 	}
 
-	// Exclusion override takes precedence
-	if f.excludeNames.Has(name) {
-		return false
+	excluded := false
+	if hasExcludeRules {
+		for _, name := range names {
+			if f.matchGlobs(name, f.excludeGlobs) {
+				excluded = true
+				break
+			}
+		}
 	}
 
 	if hasIncludeRules {
-		if f.matchGlobs(name, f.includeGlobs) {
-			if hasExcludeRules {
+		for _, name := range names {
+			if f.matchGlobs(name, f.includeGlobs) {
 				// exclusion takes precedence
-				if f.matchGlobs(name, f.excludeGlobs) {
-					return false
-				}
+				return !excluded
 			}
-			return true
 		}
 
 		// if there are include rules and it doesn't match then it must be excluded regardless of the exclude rules
@@ -126,18 +134,7 @@ func (f *Filter) Match(name string) bool {
 	}
 
 	// With only exclusion rules, everything that is not excluded is included
-	if hasExcludeRules {
-		// Overwrites by name take precedence
-		if f.excludeNames.Has(name) {
-			return false
-		}
-		if f.matchGlobs(name, f.excludeGlobs) {
-			return false
-		}
-		return true
-	}
-
-	return false
+	return !excluded
 }
 
 // doMatchAll all names against the filter and return two sets of names - included and excluded
@@ -20,6 +20,7 @@ import (
 type NodegroupFilter interface {
 	SetOnlyLocal(eksAPI eksiface.EKSAPI, lister StackLister, clusterConfig *api.ClusterConfig) error
 	Match(ngName string) bool
+	MatchNodeGroup(ng *api.NodeGroupBase) bool
 	LogInfo(cfg *api.ClusterConfig)
 }
 
@@ -218,13 +219,21 @@ func stackExists(stacks []manager.NodeGroupStack, stackName string) bool {
 
 // LogInfo prints out a user-friendly message about how filter was applied
 func (f *NodeGroupFilter) LogInfo(cfg *api.ClusterConfig) {
-	allNames := f.collectNames(cfg.NodeGroups)
-
+	included, excluded := sets.NewString(), sets.NewString()
+	logMatch := func(ng *api.NodeGroupBase) {
+		if f.MatchNodeGroup(ng) {
+			included.Insert(ng.NameString())
+		} else {
+			excluded.Insert(ng.NameString())
+		}
+	}
+	for _, ng := range cfg.NodeGroups {
+		logMatch(ng.NodeGroupBase)
+	}
 	for _, mng := range cfg.ManagedNodeGroups {
-		allNames.Insert(mng.NameString())
+		logMatch(mng.NodeGroupBase)
 	}
 
-	included, excluded := f.matchAll(allNames)
 	f.delegate.doLogInfo("nodegroup", included, excluded)
 }
 
@@ -270,6 +279,28 @@ func (f *NodeGroupFilter) Match(ngName string) bool {
 	return f.delegate.Match(ngName)
 }
 
+// MatchNodeGroup decides whether the given nodegroup is considered included by this filter, like Match; the nodegroups
+// a nodegroup with perAvailabilityZone enabled is expanded into are also matched by the name of that nodegroup,
+// e.g. `--include=ng-1` includes `ng-1-us-west-2a`
+func (f *NodeGroupFilter) MatchNodeGroup(ng *api.NodeGroupBase) bool {
+	groupName, ok := ng.Tags[api.NodeGroupPerAZGroupTag]
+	if !ok {
+		return f.Match(ng.NameString())
+	}
+
+	ngName := ng.NameString()
+	if f.onlyRemote {
+		if !f.onlyRemoteNodegroups().Has(ngName) {
+			return false
+		}
+	} else if f.onlyLocal {
+		if !f.onlyLocalNodegroups().Has(ngName) {
+			return false
+		}
+	}
+	return f.delegate.matchAny(ngName, groupName)
+}
+
 func (f *NodeGroupFilter) onlyLocalNodegroups() sets.String {
 	return f.localNodegroups.Difference(f.remoteNodegroups)
 }
@@ -282,7 +313,7 @@ func (f *NodeGroupFilter) onlyRemoteNodegroups() sets.String {
 func (f *NodeGroupFilter) FilterMatching(nodeGroups []*api.NodeGroup) []*api.NodeGroup {
 	var match []*api.NodeGroup
 	for _, ng := range nodeGroups {
-		if f.Match(ng.NameString()) {
+		if f.MatchNodeGroup(ng.NodeGroupBase) {
 			match = append(match, ng)
 		}
 	}
@@ -292,7 +323,7 @@ func (f *NodeGroupFilter) FilterMatching(nodeGroups []*api.NodeGroup) []*api.Nod
 // ForEach iterates over each nodegroup that is included by the filter and calls iterFn
 func (f *NodeGroupFilter) ForEach(nodeGroups []*api.NodeGroup, iterFn func(i int, ng *api.NodeGroup) error) error {
 	for i, ng := range nodeGroups {
-		if f.Match(ng.NameString()) {
+		if f.MatchNodeGroup(ng.NodeGroupBase) {
 			if err := iterFn(i, ng); err != nil {
 				return err
 			}
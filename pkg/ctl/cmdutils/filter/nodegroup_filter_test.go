@@ -136,6 +136,48 @@ var _ = Describe("nodegroup filter", func() {
 			Expect(excluded).To(HaveLen(4))
 			Expect(excluded.HasAll("test-ng1a", "test-ng1b", "test-ng2a", "test-ng2b")).To(BeTrue())
 		})
+
+		Context("with nodegroups expanded from a nodegroup with perAvailabilityZone enabled", func() {
+			expand := func() []*api.NodeGroup {
+				var azNodeGroups []*api.NodeGroup
+				for _, az := range []string{"us-west-2a", "us-west-2b"} {
+					ng := cfg.NewNodeGroup()
+					ng.Name = "test-ng-az-" + az
+					ng.Tags = map[string]string{api.NodeGroupPerAZGroupTag: "test-ng-az"}
+					azNodeGroups = append(azNodeGroups, ng)
+				}
+				return azNodeGroups
+			}
+
+			BeforeEach(func() {
+				ng := cfg.NewNodeGroup()
+				ng.Name = "test-ng-az"
+				ng.PerAvailabilityZone = api.Enabled()
+			})
+
+			It("should include them by the name of the nodegroup they were expanded from", func() {
+				err := filter.AppendIncludeGlobs(getNodeGroupNames(cfg), "test-ng-az")
+				Expect(err).NotTo(HaveOccurred())
+
+				azNodeGroups := expand()
+				err = filter.SetOnlyLocal(mockProvider.EKS(), newMockStackLister("test-ng-az-us-west-2a"), cfg)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filter.MatchNodeGroup(azNodeGroups[0].NodeGroupBase)).To(BeFalse())
+				Expect(filter.MatchNodeGroup(azNodeGroups[1].NodeGroupBase)).To(BeTrue())
+				Expect(filter.MatchNodeGroup(cfg.NodeGroups[0].NodeGroupBase)).To(BeFalse())
+				Expect(filter.FilterMatching(azNodeGroups)).To(ConsistOf(azNodeGroups[1]))
+			})
+
+			It("should exclude them by the name of the nodegroup they were expanded from", func() {
+				err := filter.AppendExcludeGlobs("test-ng-az")
+				Expect(err).NotTo(HaveOccurred())
+
+				azNodeGroups := expand()
+				Expect(filter.FilterMatching(azNodeGroups)).To(BeEmpty())
+				Expect(filter.MatchNodeGroup(cfg.NodeGroups[0].NodeGroupBase)).To(BeTrue())
+			})
+		})
 	})
 
 	Context("ForEach", func() {
@@ -12,7 +12,21 @@ func PopulateNodegroup(stackManager manager.StackManager, name string, cfg *api.
 	nodeGroupType, err := stackManager.GetNodeGroupStackType(name)
 	if err != nil {
 		logger.Debug("failed to fetch nodegroup %q stack: %v", name, err)
-		_, err := ctl.EKS().DescribeNodegroup(&eks.DescribeNodegroupInput{
+		perAZStacks, err := PerAZNodeGroupStacks(stackManager, name)
+		if err != nil {
+			return err
+		}
+		if len(perAZStacks) > 0 {
+			logger.Info("nodegroup %q was expanded into %d nodegroups, one per availability zone", name, len(perAZStacks))
+			for _, s := range perAZStacks {
+				if err := PopulateNodegroupFromStack(s.Type, s.NodeGroupName, cfg); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		_, err = ctl.EKS().DescribeNodegroup(&eks.DescribeNodegroupInput{
 			ClusterName:   &cfg.Metadata.Name,
 			NodegroupName: &name,
 		})
@@ -56,3 +70,71 @@ func PopulateUnmanagedNodegroup(nodeGroupName string, cfg *api.ClusterConfig) {
 		},
 	})
 }
+
+// PerAZNodeGroupStacks returns the stacks of the nodegroups a nodegroup with perAvailabilityZone
+// enabled was expanded into
+func PerAZNodeGroupStacks(stackManager manager.StackManager, name string) ([]manager.NodeGroupStack, error) {
+	nodeGroupStacks, err := stackManager.ListNodeGroupStacks()
+	if err != nil {
+		return nil, err
+	}
+	var perAZStacks []manager.NodeGroupStack
+	for _, s := range nodeGroupStacks {
+		if s.PerAZGroup == name {
+			perAZStacks = append(perAZStacks, s)
+		}
+	}
+	return perAZStacks, nil
+}
+
+// PopulatePerAZNodeGroups replaces the nodegroups with perAvailabilityZone enabled in a ClusterConfig
+// by the nodegroups they were expanded into, tagged with the name of the nodegroup they were expanded from
+func PopulatePerAZNodeGroups(stackManager manager.StackManager, cfg *api.ClusterConfig) error {
+	var nodeGroups []*api.NodeGroup
+	for _, ng := range cfg.NodeGroups {
+		if !api.IsEnabled(ng.PerAvailabilityZone) {
+			nodeGroups = append(nodeGroups, ng)
+			continue
+		}
+		perAZStacks, err := PerAZNodeGroupStacks(stackManager, ng.Name)
+		if err != nil {
+			return err
+		}
+		for _, s := range perAZStacks {
+			azNodeGroup := ng.DeepCopy()
+			azNodeGroup.Name = s.NodeGroupName
+			azNodeGroup.PerAvailabilityZone = nil
+			setPerAZGroupTag(azNodeGroup.NodeGroupBase, ng.Name)
+			nodeGroups = append(nodeGroups, azNodeGroup)
+		}
+	}
+	cfg.NodeGroups = nodeGroups
+
+	var managedNodeGroups []*api.ManagedNodeGroup
+	for _, ng := range cfg.ManagedNodeGroups {
+		if !api.IsEnabled(ng.PerAvailabilityZone) {
+			managedNodeGroups = append(managedNodeGroups, ng)
+			continue
+		}
+		perAZStacks, err := PerAZNodeGroupStacks(stackManager, ng.Name)
+		if err != nil {
+			return err
+		}
+		for _, s := range perAZStacks {
+			azNodeGroup := ng.DeepCopy()
+			azNodeGroup.Name = s.NodeGroupName
+			azNodeGroup.PerAvailabilityZone = nil
+			setPerAZGroupTag(azNodeGroup.NodeGroupBase, ng.Name)
+			managedNodeGroups = append(managedNodeGroups, azNodeGroup)
+		}
+	}
+	cfg.ManagedNodeGroups = managedNodeGroups
+	return nil
+}
+
+func setPerAZGroupTag(ng *api.NodeGroupBase, groupName string) {
+	if ng.Tags == nil {
+		ng.Tags = map[string]string{}
+	}
+	ng.Tags[api.NodeGroupPerAZGroupTag] = groupName
+}
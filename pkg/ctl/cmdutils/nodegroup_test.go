@@ -7,7 +7,9 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
 	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
 )
 
@@ -45,6 +47,23 @@ var _ = Describe("PopulateNodegroup", func() {
 		})
 	})
 
+	Context("nodegroup with perAvailabilityZone enabled", func() {
+		It("adds the nodegroups it was expanded into to the cfg", func() {
+			fakeStackManager.GetNodeGroupStackTypeReturns("", errors.New(""))
+			fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{
+				{NodeGroupName: "ng-us-west-2a", Type: api.NodeGroupTypeManaged, PerAZGroup: ngName},
+				{NodeGroupName: "ng-us-west-2b", Type: api.NodeGroupTypeManaged, PerAZGroup: ngName},
+				{NodeGroupName: "other", Type: api.NodeGroupTypeManaged},
+			}, nil)
+
+			err = PopulateNodegroup(fakeStackManager, ngName, cfg, mockProvider)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ManagedNodeGroups).To(HaveLen(2))
+			Expect(cfg.ManagedNodeGroups[0].Name).To(Equal("ng-us-west-2a"))
+			Expect(cfg.ManagedNodeGroups[1].Name).To(Equal("ng-us-west-2b"))
+		})
+	})
+
 	Context("unowned nodegroup", func() {
 		It("is added to the cfg", func() {
 			clusterName := "cluster-name"
@@ -61,3 +80,52 @@ var _ = Describe("PopulateNodegroup", func() {
 		})
 	})
 })
+
+var _ = Describe("PopulatePerAZNodeGroups", func() {
+	It("replaces nodegroups with perAvailabilityZone enabled by the nodegroups they were expanded into", func() {
+		fakeStackManager := new(fakes.FakeStackManager)
+		fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{
+			{NodeGroupName: "ng-us-west-2a", Type: api.NodeGroupTypeUnmanaged, PerAZGroup: "ng"},
+			{NodeGroupName: "ng-us-west-2b", Type: api.NodeGroupTypeUnmanaged, PerAZGroup: "ng"},
+		}, nil)
+
+		cfg := api.NewClusterConfig()
+		perAZ := api.NewNodeGroup()
+		perAZ.Name = "ng"
+		perAZ.PerAvailabilityZone = aws.Bool(true)
+		other := api.NewNodeGroup()
+		other.Name = "other"
+		cfg.NodeGroups = []*api.NodeGroup{perAZ, other}
+
+		Expect(PopulatePerAZNodeGroups(fakeStackManager, cfg)).To(Succeed())
+		Expect(cfg.NodeGroups).To(HaveLen(3))
+		Expect(cfg.NodeGroups[0].Name).To(Equal("ng-us-west-2a"))
+		Expect(cfg.NodeGroups[0].PerAvailabilityZone).To(BeNil())
+		Expect(cfg.NodeGroups[0].Tags).To(HaveKeyWithValue(api.NodeGroupPerAZGroupTag, "ng"))
+		Expect(cfg.NodeGroups[1].Name).To(Equal("ng-us-west-2b"))
+		Expect(cfg.NodeGroups[2].Name).To(Equal("other"))
+	})
+
+	It("keeps the nodegroups expanded from a nodegroup included by name", func() {
+		fakeStackManager := new(fakes.FakeStackManager)
+		fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{
+			{NodeGroupName: "ng-us-west-2a", Type: api.NodeGroupTypeUnmanaged, PerAZGroup: "ng"},
+			{NodeGroupName: "other", Type: api.NodeGroupTypeUnmanaged},
+		}, nil)
+
+		cfg := api.NewClusterConfig()
+		perAZ := api.NewNodeGroup()
+		perAZ.Name = "ng"
+		perAZ.PerAvailabilityZone = aws.Bool(true)
+		other := api.NewNodeGroup()
+		other.Name = "other"
+		cfg.NodeGroups = []*api.NodeGroup{perAZ, other}
+
+		ngFilter := filter.NewNodeGroupFilter()
+		Expect(ngFilter.AppendIncludeGlobs(cfg.GetAllNodeGroupNames(), "ng")).To(Succeed())
+		Expect(PopulatePerAZNodeGroups(fakeStackManager, cfg)).To(Succeed())
+		ApplyFilter(cfg, ngFilter)
+		Expect(cfg.NodeGroups).To(HaveLen(1))
+		Expect(cfg.NodeGroups[0].Name).To(Equal("ng-us-west-2a"))
+	})
+})
@@ -0,0 +1,121 @@
+package cmdutils
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/az"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/vpc"
+)
+
+// RenderClusterTemplates renders the templates of all stacks that `create cluster` would create
+// without calling AWS; the region and version must already be set
+func RenderClusterTemplates(cfg *api.ClusterConfig, ngFilter *filter.NodeGroupFilter, zones []string) (*manager.TemplateManifest, error) {
+	if err := validateOfflineNodePools(cfg); err != nil {
+		return nil, err
+	}
+	if err := setOfflineNetworking(cfg, zones); err != nil {
+		return nil, err
+	}
+
+	logFiltered := ApplyFilter(cfg, ngFilter)
+	logFiltered()
+
+	supportsManagedNodes, err := eks.VersionSupportsManagedNodes(cfg.Metadata.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	renderer := manager.NewTemplateRenderer(cfg)
+	if err := renderer.RenderClusterStack(supportsManagedNodes); err != nil {
+		return nil, err
+	}
+	if err := renderer.RenderNodeGroupStacks(cfg.NodeGroups, cfg.ManagedNodeGroups, false); err != nil {
+		return nil, err
+	}
+	if api.IsEnabled(cfg.IAM.WithOIDC) {
+		if err := renderer.RenderIAMServiceAccountStacks(cfg.IAM.ServiceAccounts); err != nil {
+			return nil, err
+		}
+	}
+	return renderer.Manifest(), nil
+}
+
+// RenderNodeGroupTemplates renders the templates of the nodegroup stacks that `create nodegroup` would create
+// without calling AWS; the cluster is expected to have been created by eksctl
+func RenderNodeGroupTemplates(cfg *api.ClusterConfig, ngFilter *filter.NodeGroupFilter) (*manager.TemplateManifest, error) {
+	if err := validateOfflineNodePools(cfg); err != nil {
+		return nil, err
+	}
+
+	renderer := manager.NewTemplateRenderer(cfg)
+	if cfg.Metadata.Version == "latest" {
+		cfg.Metadata.Version = api.LatestVersion
+	}
+	if cfg.Metadata.Version == "" || cfg.Metadata.Version == "auto" {
+		cfg.Metadata.Version = api.DefaultVersion
+		renderer.MarkUnresolved("", "metadata.version", fmt.Sprintf("the version of the control plane is not known, %s was assumed; set metadata.version to render it", api.DefaultVersion))
+	}
+
+	logFiltered := ApplyFilter(cfg, ngFilter)
+	logFiltered()
+
+	// whether aws-node uses IRSA can only be checked against the cluster, so the CNI policy is always added
+	if err := renderer.RenderNodeGroupStacks(cfg.NodeGroups, cfg.ManagedNodeGroups, true); err != nil {
+		return nil, err
+	}
+	return renderer.Manifest(), nil
+}
+
+func validateOfflineNodePools(cfg *api.ClusterConfig) error {
+	for _, np := range ToNodePools(cfg) {
+		if is := np.BaseNodeGroup().InstanceSelector; is != nil && !is.IsZero() {
+			return fmt.Errorf("instanceSelector of nodegroup %q cannot be used when rendering templates offline", np.BaseNodeGroup().Name)
+		}
+	}
+	return nil
+}
+
+// setOfflineNetworking sets the availability zones and subnets that would otherwise be selected
+// or looked up using the EC2 API
+func setOfflineNetworking(cfg *api.ClusterConfig, zones []string) error {
+	if !cfg.HasAnySubnets() {
+		if len(zones) != 0 {
+			cfg.AvailabilityZones = zones
+		}
+		if len(cfg.AvailabilityZones) == 0 {
+			return errors.New("availability zones cannot be selected offline, set them using --zones or availabilityZones")
+		}
+		if len(cfg.AvailabilityZones) < az.MinRequiredAvailabilityZones {
+			return fmt.Errorf("only %d zones specified %v, %d are required (can be non-unique)", len(cfg.AvailabilityZones), cfg.AvailabilityZones, az.MinRequiredAvailabilityZones)
+		}
+		return vpc.SetSubnets(cfg.VPC, cfg.AvailabilityZones)
+	}
+
+	if len(zones) != 0 {
+		return fmt.Errorf("subnets and --zones %s", IncompatibleFlags)
+	}
+	if cfg.VPC.ID == "" {
+		return errors.New("vpc.id must be set when using existing subnets offline")
+	}
+
+	subnetZones := sets.NewString()
+	for _, subnets := range []api.AZSubnetMapping{cfg.VPC.Subnets.Private, cfg.VPC.Subnets.Public} {
+		for name, subnet := range subnets {
+			if subnet.ID == "" || subnet.AZ == "" {
+				return fmt.Errorf("subnet %q must have both id and az set to be used offline", name)
+			}
+			subnetZones.Insert(subnet.AZ)
+		}
+	}
+	if len(cfg.AvailabilityZones) == 0 {
+		cfg.AvailabilityZones = subnetZones.List()
+	}
+	return nil
+}
@@ -89,11 +89,14 @@ func createAddonCmd(cmd *cmdutils.Cmd) {
 			return err
 		}
 
+		ctx, cancel := cmdutils.NewInterruptContext()
+		defer cancel()
+
 		for _, a := range cmd.ClusterConfig.Addons {
 			if force { //force is specified at cmdline level
 				a.Force = true
 			}
-			err := addonManager.Create(a, wait)
+			err := addonManager.Create(ctx, a, wait)
 			if err != nil {
 				return err
 			}
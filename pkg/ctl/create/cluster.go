@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strings"
 
 	"github.com/aws/amazon-ec2-instance-selector/v2/pkg/selector"
 	"github.com/weaveworks/eksctl/pkg/kops"
@@ -23,6 +22,7 @@ import (
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
 	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
 	"github.com/weaveworks/eksctl/pkg/printers"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
 	"github.com/weaveworks/eksctl/pkg/utils/kubectl"
@@ -78,6 +78,10 @@ func createClusterCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.C
 		fs.BoolVarP(&params.InstallWindowsVPCController, "install-vpc-controllers", "", false, "Install VPC controller that's required for Windows workloads")
 		fs.BoolVarP(&params.Fargate, "fargate", "", false, "Create a Fargate profile scheduling pods in the default and kube-system namespaces onto Fargate")
 		fs.BoolVarP(&params.DryRun, "dry-run", "", false, "Dry-run mode that skips cluster creation and outputs a ClusterConfig")
+		fs.StringVar(&params.OutputTemplatesDir, "output-templates", "", "Write the CloudFormation templates of all stacks and a manifest to the given directory without calling AWS (requires --dry-run)")
+		fs.BoolVar(&params.RollbackOnInterrupt, "rollback-on-interrupt", false, "Delete the stacks created so far without asking when interrupted")
+		fs.BoolVar(&params.Resume, "resume", false, "Resume an interrupted or failed creation of the cluster from its journal, skipping the tasks that have completed")
+		fs.BoolVar(&params.JournalConfigMap, "journal-configmap", false, fmt.Sprintf("Also keep the journal in the %q ConfigMap of the cluster, so that creation can be resumed from another machine", kubernetes.JournalConfigMapName))
 
 		_ = fs.MarkDeprecated("install-vpc-controllers", vpcControllerInfoMessage)
 	})
@@ -120,6 +124,10 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 		return api.ErrInvalidName(meta.Name)
 	}
 
+	if params.OutputTemplatesDir != "" {
+		return renderClusterTemplates(cmd, ngFilter, params)
+	}
+
 	printer := printers.NewJSONPrinter()
 
 	ctl, err := cmd.NewCtl()
@@ -137,19 +145,8 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 
 	cmdutils.LogRegionAndVersionInfo(meta)
 
-	if cfg.Metadata.Version == "" || cfg.Metadata.Version == "auto" {
-		cfg.Metadata.Version = api.DefaultVersion
-	}
-	if cfg.Metadata.Version == "latest" {
-		cfg.Metadata.Version = api.LatestVersion
-	}
-	if cfg.Metadata.Version != api.DefaultVersion {
-		if !api.IsSupportedVersion(cfg.Metadata.Version) {
-			if api.IsDeprecatedVersion(cfg.Metadata.Version) {
-				return fmt.Errorf("invalid version, %s is no longer supported, supported values: %s\nsee also: https://docs.aws.amazon.com/eks/latest/userguide/kubernetes-versions.html", cfg.Metadata.Version, strings.Join(api.SupportedVersions(), ", "))
-			}
-			return fmt.Errorf("invalid version, supported values: %s", strings.Join(api.SupportedVersions(), ", "))
-		}
+	if err := cmdutils.SetClusterVersion(cfg.Metadata); err != nil {
+		return err
 	}
 
 	if err := cfg.ValidatePrivateCluster(); err != nil {
@@ -218,9 +215,15 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 		return cmdutils.PrintDryRunConfig(cfg, os.Stdout)
 	}
 
-	if err := nodeGroupService.Normalize(nodePools, cfg.Metadata); err != nil {
+	if err := nodeGroupService.Normalize(nodePools, cfg); err != nil {
+		return err
+	}
+
+	journal, err := openClusterJournal(ctl, cfg, params)
+	if err != nil {
 		return err
 	}
+	meta = cfg.Metadata
 
 	logger.Info("using Kubernetes version %s", meta.Version)
 	logger.Info("creating %s", cfg.LogString())
@@ -258,25 +261,43 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 	if err != nil {
 		return err
 	}
-	postClusterCreationTasks := ctl.CreateExtraClusterConfigTasks(cfg)
+	postClusterCreationTasks, err := ctl.CreateExtraClusterConfigTasks(cfg)
+	if err != nil {
+		return err
+	}
 
 	supported, err := utils.IsMinVersion(api.Version1_18, cfg.Metadata.Version)
 	if err != nil {
 		return err
 	}
 
-	var preNodegroupAddons, postNodegroupAddons *tasks.TaskTree
+	var (
+		preNodegroupAddons, postNodegroupAddons *tasks.TaskTree
+		preNodeGroupTasks                       []tasks.Task
+	)
 	if supported && len(cfg.Addons) > 0 {
 		preNodegroupAddons, postNodegroupAddons = addon.CreateAddonTasks(cfg, ctl, true, cmd.ProviderConfig.WaitTimeout)
-		postClusterCreationTasks.Append(preNodegroupAddons)
+		// addons are created once the cluster config has been updated and the iamserviceaccounts they may use have
+		// been created, and the nodegroups wait for the addons their nodes need to become ready, i.e. vpc-cni
+		preNodeGroupTasks = append(preNodeGroupTasks, preNodegroupAddons)
+	}
+
+	taskTree, err := stackManager.NewTasksToCreateClusterWithNodeGroups(cfg.NodeGroups, cfg.ManagedNodeGroups, supportsManagedNodes, preNodeGroupTasks, postClusterCreationTasks)
+	if err != nil {
+		return err
 	}
 
-	taskTree := stackManager.NewTasksToCreateClusterWithNodeGroups(cfg.NodeGroups, cfg.ManagedNodeGroups, supportsManagedNodes, postClusterCreationTasks)
+	interruptCtx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+	ctx := tasks.WithJournal(interruptCtx, journal)
 
 	logger.Info(taskTree.Describe())
-	if errs := taskTree.DoAllSync(); len(errs) > 0 {
+	if errs := taskTree.DoAllSyncWithContext(tasks.WithJournalSection(ctx, "cluster")); len(errs) > 0 {
+		if ctx.Err() != nil {
+			return interruptedCreation(stackManager, journal, meta, params)
+		}
 		logger.Warning("%d error(s) occurred and cluster hasn't been created properly, you may wish to check CloudFormation console", len(errs))
-		logger.Info("to cleanup resources, run 'eksctl delete cluster --region=%s --name=%s'", meta.Region, meta.Name)
+		logResumeHint(meta)
 		for _, err := range errs {
 			ufe := &api.UnsupportedFeatureError{}
 			if errors.As(err, &ufe) {
@@ -311,15 +332,21 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 		ngTasks := ctl.ClusterTasksForNodeGroups(cfg, params.InstallNeuronDevicePlugin, params.InstallNvidiaDevicePlugin)
 
 		logger.Info(ngTasks.Describe())
-		if errs := ngTasks.DoAllSync(); len(errs) > 0 {
+		if errs := ngTasks.DoAllSyncWithContext(tasks.WithJournalSection(ctx, "nodegroups")); len(errs) > 0 {
+			if ctx.Err() != nil {
+				return interruptedCreation(stackManager, journal, meta, params)
+			}
 			logger.Warning("%d error(s) occurred and post actions have failed, you may wish to check CloudFormation console", len(errs))
-			logger.Info("to cleanup resources, run 'eksctl delete cluster --region=%s --name=%s'", meta.Region, meta.Name)
+			logResumeHint(meta)
 			for _, err := range errs {
 				logger.Critical("%s\n", err.Error())
 			}
 			return fmt.Errorf("failed to create cluster %q", meta.Name)
 		}
 		logger.Success("all EKS cluster resources for %q have been created", meta.Name)
+		if err := journal.Delete(); err != nil {
+			logger.Warning("unable to delete the journal of the creation of cluster %q: %v", meta.Name, err)
+		}
 
 		// create Kubernetes client
 		clientSet, err := ctl.NewStdClientSet(cfg)
@@ -82,6 +82,7 @@ var _ = Describe("create cluster", func() {
 			Entry("with appmesh-access flag", "--appmesh-access", "true"),
 			Entry("with alb-ingress-access flag", "--alb-ingress-access", "true"),
 			Entry("with managed flag unset", "--managed", "false"),
+			Entry("with output-templates flag", "--dry-run", "--output-templates", "templates"),
 		)
 
 		DescribeTable("invalid flags or arguments",
@@ -108,6 +109,10 @@ var _ = Describe("create cluster", func() {
 				args:  []string{"--name", "eksctl-testing-k_8_cluster01"},
 				error: "validation for eksctl-testing-k_8_cluster01 failed, name must satisfy regular expression pattern: [a-zA-Z][-a-zA-Z0-9]*",
 			}),
+			Entry("with output-templates flag but without dry-run", invalidParamsCase{
+				args:  []string{"--output-templates", "templates"},
+				error: "--output-templates can only be used with --dry-run",
+			}),
 		)
 	})
 
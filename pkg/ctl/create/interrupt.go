@@ -0,0 +1,90 @@
+package create
+
+import (
+	"context"
+	"fmt"
+
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/kris-nova/logger"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
+)
+
+// interruptedCreation handles the interruption of cluster creation, the journal is kept
+// to resume the creation unless the created stacks have been deleted
+func interruptedCreation(stackManager manager.StackManager, journal *tasks.Journal, meta *api.ClusterMeta, params *cmdutils.CreateClusterCmdParams) error {
+	if handleInterruptedCreation(stackManager, meta, params.RollbackOnInterrupt, cmdutils.Confirm) {
+		if err := journal.Delete(); err != nil {
+			logger.Warning("unable to delete the journal of the creation of cluster %q: %v", meta.Name, err)
+		}
+	} else {
+		logger.Info("to resume the creation, run the same command with --resume")
+	}
+	return fmt.Errorf("creation of cluster %q was interrupted", meta.Name)
+}
+
+// logResumeHint prints how to continue after cluster creation has failed
+func logResumeHint(meta *api.ClusterMeta) {
+	logger.Info("to resume the creation once the cause of the failure is fixed, run the same command with --resume")
+	logger.Info("to cleanup resources, run 'eksctl delete cluster --region=%s --name=%s'", meta.Region, meta.Name)
+}
+
+// handleInterruptedCreation deletes the stacks that were created before cluster creation was
+// interrupted, when rollback is set or the user confirms it, and prints the stacks left behind;
+// it returns true when the stacks have been deleted
+func handleInterruptedCreation(stackManager manager.StackManager, meta *api.ClusterMeta, rollback bool, confirm func(string) bool) bool {
+	stacks := stackManager.CreatedStacks()
+	if len(stacks) == 0 {
+		logger.Info("no stacks were created before the interruption")
+		return true
+	}
+
+	if !rollback {
+		rollback = confirm(fmt.Sprintf("delete the %d stack(s) created before the interruption?", len(stacks)))
+	}
+	if rollback {
+		deleteCreatedStacks(stackManager, stacks)
+	}
+
+	var leftBehind []string
+	for _, s := range stacks {
+		status := "unknown status"
+		if current, err := stackManager.DescribeStack(s); err == nil {
+			status = *current.StackStatus
+		}
+		if status != cfn.StackStatusDeleteComplete {
+			leftBehind = append(leftBehind, fmt.Sprintf("%s (%s)", *s.StackName, status))
+		}
+	}
+
+	if len(leftBehind) == 0 {
+		logger.Info("all stacks created before the interruption have been deleted")
+		return true
+	}
+	logger.Warning("the following stacks were left behind:")
+	for _, s := range leftBehind {
+		logger.Warning("  %s", s)
+	}
+	logger.Info("to cleanup resources, run 'eksctl delete cluster --region=%s --name=%s'", meta.Region, meta.Name)
+	return false
+}
+
+// deleteCreatedStacks deletes the stacks in the reverse order of their creation, so that
+// stacks importing outputs of other stacks are deleted first; the termination protection
+// eksctl enabled when creating them is disabled first, as deleting them has been requested
+func deleteCreatedStacks(stackManager manager.StackManager, stacks []*manager.Stack) {
+	for i := len(stacks) - 1; i >= 0; i-- {
+		s := stacks[i]
+		errs := make(chan error)
+		if err := stackManager.DeleteProtectedStackBySpecSync(context.Background(), s, errs); err != nil {
+			logger.Critical("deleting stack %q: %v", *s.StackName, err)
+			continue
+		}
+		if err := <-errs; err != nil {
+			logger.Critical("deleting stack %q: %v", *s.StackName, err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package create
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
+)
+
+var _ = Describe("interrupted cluster creation", func() {
+	var (
+		stackManager *fakes.FakeStackManager
+		meta         *api.ClusterMeta
+		statuses     map[string]string
+		questions    []string
+	)
+
+	confirm := func(answer bool) func(string) bool {
+		return func(question string) bool {
+			questions = append(questions, question)
+			return answer
+		}
+	}
+
+	BeforeEach(func() {
+		stackManager = new(fakes.FakeStackManager)
+		meta = &api.ClusterMeta{Name: "test", Region: "us-west-2"}
+		questions = nil
+		statuses = map[string]string{
+			"eksctl-test-cluster":        cfn.StackStatusCreateComplete,
+			"eksctl-test-nodegroup-ng-1": cfn.StackStatusCreateInProgress,
+		}
+		stackManager.CreatedStacksReturns([]*manager.Stack{
+			{StackName: aws.String("eksctl-test-cluster")},
+			{StackName: aws.String("eksctl-test-nodegroup-ng-1")},
+		})
+		stackManager.DescribeStackStub = func(s *manager.Stack) (*manager.Stack, error) {
+			return &manager.Stack{StackName: s.StackName, StackStatus: aws.String(statuses[*s.StackName])}, nil
+		}
+		stackManager.DeleteProtectedStackBySpecSyncStub = func(_ context.Context, s *manager.Stack, errs chan error) error {
+			statuses[*s.StackName] = cfn.StackStatusDeleteComplete
+			go func() {
+				errs <- nil
+				close(errs)
+			}()
+			return nil
+		}
+	})
+
+	It("deletes the created stacks in reverse order without asking when rollback is set", func() {
+		Expect(handleInterruptedCreation(stackManager, meta, true, confirm(false))).To(BeTrue())
+
+		Expect(questions).To(BeEmpty())
+		Expect(stackManager.DeleteProtectedStackBySpecSyncCallCount()).To(Equal(2))
+		_, s, _ := stackManager.DeleteProtectedStackBySpecSyncArgsForCall(0)
+		Expect(*s.StackName).To(Equal("eksctl-test-nodegroup-ng-1"))
+		_, s, _ = stackManager.DeleteProtectedStackBySpecSyncArgsForCall(1)
+		Expect(*s.StackName).To(Equal("eksctl-test-cluster"))
+	})
+
+	It("asks before deleting the created stacks", func() {
+		handleInterruptedCreation(stackManager, meta, false, confirm(true))
+
+		Expect(questions).To(Equal([]string{"delete the 2 stack(s) created before the interruption?"}))
+		Expect(stackManager.DeleteProtectedStackBySpecSyncCallCount()).To(Equal(2))
+	})
+
+	It("leaves the stacks behind when the user declines", func() {
+		Expect(handleInterruptedCreation(stackManager, meta, false, confirm(false))).To(BeFalse())
+
+		Expect(questions).To(HaveLen(1))
+		Expect(stackManager.DeleteProtectedStackBySpecSyncCallCount()).To(Equal(0))
+		Expect(stackManager.DescribeStackCallCount()).To(Equal(2))
+	})
+})
@@ -0,0 +1,81 @@
+package create
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
+)
+
+// clusterJournalPath returns the path of the local journal of the creation of the cluster
+func clusterJournalPath(meta *api.ClusterMeta) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, ".eksctl", "journals", meta.Region, meta.Name+".json")
+}
+
+// clusterJournalStores returns the stores of the journal of the creation of the cluster,
+// which is kept in a local file, and in a ConfigMap of the cluster when withConfigMap is set
+func clusterJournalStores(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, withConfigMap bool) []tasks.JournalStore {
+	stores := []tasks.JournalStore{&tasks.FileJournalStore{Path: clusterJournalPath(cfg.Metadata)}}
+	if withConfigMap {
+		stores = append(stores, &kubernetes.ConfigMapJournalStore{
+			ClientSetGetter: &kubernetes.CallbackClientSet{
+				Callback: func() (kubernetes.Interface, error) {
+					// the status is set once the control plane is ready
+					if cfg.Status == nil || cfg.Status.Endpoint == "" {
+						return nil, fmt.Errorf("the endpoint of cluster %q is not known yet", cfg.Metadata.Name)
+					}
+					return ctl.NewStdClientSet(cfg)
+				},
+			},
+		})
+	}
+	return stores
+}
+
+// openClusterJournal returns a new journal for the creation of the cluster, or the journal of the
+// creation to resume, in which case the cluster config is replaced with the one the journal was
+// started with, so that the same tasks are run
+func openClusterJournal(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, params *cmdutils.CreateClusterCmdParams) (*tasks.Journal, error) {
+	stores := clusterJournalStores(ctl, cfg, params.JournalConfigMap)
+
+	if !params.Resume {
+		if data, err := stores[0].Load(); err == nil && data != nil {
+			logger.Warning("found the journal of a previous creation of cluster %q which did not complete; use --resume to continue it instead", cfg.Metadata.Name)
+		}
+		return tasks.NewJournal(cfg, stores...)
+	}
+
+	if params.JournalConfigMap {
+		if err := ctl.RefreshClusterStatus(cfg); err != nil {
+			logger.Debug("unable to get the status of cluster %q: %v", cfg.Metadata.Name, err)
+		}
+	}
+	journal, err := tasks.LoadJournal(stores...)
+	if err != nil {
+		return nil, err
+	}
+	if journal == nil {
+		return nil, fmt.Errorf("no journal found to resume the creation of cluster %q", cfg.Metadata.Name)
+	}
+
+	resumed := &api.ClusterConfig{}
+	if err := journal.DecodeSpec(resumed); err != nil {
+		return nil, errors.Wrapf(err, "resuming the creation of cluster %q", cfg.Metadata.Name)
+	}
+	resumed.Status = cfg.Status
+	*cfg = *resumed
+	logger.Info("resuming the creation of cluster %q", cfg.Metadata.Name)
+	return journal, nil
+}
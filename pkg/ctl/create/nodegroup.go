@@ -39,6 +39,9 @@ func createNodeGroupCmd(cmd *cmdutils.Cmd) {
 		if err := cmdutils.NewCreateNodeGroupLoader(cmd, ng, ngFilter, options.CreateNGOptions, options.CreateManagedNGOptions).Load(); err != nil {
 			return errors.Wrap(err, "couldn't create node group filter from command line options")
 		}
+		if options.OutputTemplatesDir != "" {
+			return renderNodeGroupTemplates(cmd, ngFilter, options.CreateNGOptions)
+		}
 		ctl, err := cmd.NewProviderForExistingCluster()
 		if err != nil {
 			return errors.Wrap(err, "couldn't create cluster provider from options")
@@ -62,8 +65,11 @@ func createNodeGroupCmd(cmd *cmdutils.Cmd) {
 			return err
 		}
 
+		ctx, cancel := cmdutils.NewInterruptContext()
+		defer cancel()
+
 		manager := nodegroup.New(cmd.ClusterConfig, ctl, clientSet)
-		return manager.Create(nodegroup.CreateOpts{
+		return manager.Create(ctx, nodegroup.CreateOpts{
 			InstallNeuronDevicePlugin: options.InstallNeuronDevicePlugin,
 			InstallNvidiaDevicePlugin: options.InstallNvidiaDevicePlugin,
 			UpdateAuthConfigMap:       options.UpdateAuthConfigMap,
@@ -103,6 +109,7 @@ func createNodeGroupCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc runFn) {
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 		cmdutils.AddSubnetIDs(fs, &options.SubnetIDs, "Define an optional list of subnet IDs to create the nodegroup in")
 		fs.BoolVarP(&options.DryRun, "dry-run", "", false, "Dry-run mode that skips nodegroup creation and outputs a ClusterConfig")
+		fs.StringVar(&options.OutputTemplatesDir, "output-templates", "", "Write the CloudFormation templates of the nodegroup stacks and a manifest to the given directory without calling AWS (requires --dry-run)")
 		fs.BoolVarP(&options.SkipOutdatedAddonsCheck, "skip-outdated-addons-check", "", false, "whether the creation of ARM nodegroups should proceed when the cluster addons are outdated")
 	})
 
@@ -0,0 +1,67 @@
+package create
+
+import (
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
+)
+
+// renderClusterTemplates writes the templates of all stacks that `create cluster` would create,
+// without creating an AWS session
+func renderClusterTemplates(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params *cmdutils.CreateClusterCmdParams) error {
+	cfg := cmd.ClusterConfig
+
+	if err := cmd.InitOffline(); err != nil {
+		return err
+	}
+	if err := cmdutils.SetClusterVersion(cfg.Metadata); err != nil {
+		return err
+	}
+	if err := cfg.ValidatePrivateCluster(); err != nil {
+		return err
+	}
+	if err := cfg.ValidateClusterEndpointConfig(); err != nil {
+		return err
+	}
+
+	if params.KopsClusterNameForVPC != "" {
+		return errors.New("--vpc-from-kops-cluster cannot be used with --output-templates")
+	}
+	if checkSubnetsGivenAsFlags(params) {
+		return errors.New("--vpc-private-subnets/--vpc-public-subnets cannot be used with --output-templates, set the subnets in the config file instead")
+	}
+
+	manifest, err := cmdutils.RenderClusterTemplates(cfg, ngFilter, params.AvailabilityZones)
+	if err != nil {
+		return err
+	}
+	return writeTemplates(manifest, params.OutputTemplatesDir)
+}
+
+// renderNodeGroupTemplates writes the templates of the nodegroup stacks that `create nodegroup` would create,
+// without creating an AWS session
+func renderNodeGroupTemplates(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, options cmdutils.CreateNGOptions) error {
+	if err := cmd.InitOffline(); err != nil {
+		return err
+	}
+
+	manifest, err := cmdutils.RenderNodeGroupTemplates(cmd.ClusterConfig, ngFilter)
+	if err != nil {
+		return err
+	}
+	return writeTemplates(manifest, options.OutputTemplatesDir)
+}
+
+func writeTemplates(manifest *manager.TemplateManifest, dir string) error {
+	if err := manifest.Write(dir); err != nil {
+		return err
+	}
+	logger.Success("wrote %d CloudFormation template(s) and %s to %q", len(manifest.Stacks), manager.TemplateManifestFile, dir)
+	if len(manifest.Unresolved) > 0 {
+		logger.Warning("%d value(s) could not be resolved without calling AWS and were set to %q, see %s for details", len(manifest.Unresolved), manager.UnresolvedValue, manager.TemplateManifestFile)
+	}
+	return nil
+}
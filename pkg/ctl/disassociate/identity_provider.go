@@ -99,7 +99,10 @@ func doDisassociateIdentityProvider(cmd *cmdutils.Cmd, cliProvidedIDP cliProvide
 		options.WaitTimeout = &timeout
 	}
 
-	return manager.Disassociate(options)
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
+	return manager.Disassociate(ctx, options)
 }
 
 func cliToProviders(cfg *api.ClusterConfig, cliProvidedIDP cliProvidedIDP) []identityproviders.DisassociateIdentityProvider {
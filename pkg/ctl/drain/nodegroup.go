@@ -1,6 +1,9 @@
 package drain
 
 import (
+	"fmt"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/weaveworks/eksctl/pkg/actions/nodegroup"
@@ -12,28 +15,49 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
+	"github.com/weaveworks/eksctl/pkg/drain"
+	"github.com/weaveworks/eksctl/pkg/printers"
 )
 
+type drainOptions struct {
+	undo             bool
+	onlyMissing      bool
+	maxGracePeriod   time.Duration
+	disableEviction  bool
+	parallelism      int
+	pdbTimeout       time.Duration
+	pdbTimeoutAction string
+	dryRun           bool
+	output           printers.Type
+}
+
 func drainNodeGroupCmd(cmd *cmdutils.Cmd) {
-	drainNodeGroupWithRunFunc(cmd, func(cmd *cmdutils.Cmd, ng *api.NodeGroup, undo, onlyMissing bool, maxGracePeriod time.Duration, disableEviction bool) error {
-		return doDrainNodeGroup(cmd, ng, undo, onlyMissing, maxGracePeriod, disableEviction)
+	drainNodeGroupWithRunFunc(cmd, func(cmd *cmdutils.Cmd, ng *api.NodeGroup, options drainOptions) error {
+		return doDrainNodeGroup(cmd, ng, options)
 	})
 }
 
-func drainNodeGroupWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, ng *api.NodeGroup, undo, onlyMissing bool, maxGracePeriod time.Duration, disableEviction bool) error) {
+func drainNodeGroupWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, ng *api.NodeGroup, options drainOptions) error) {
 	cfg := api.NewClusterConfig()
 	ng := api.NewNodeGroup()
 	cmd.ClusterConfig = cfg
 
-	var undo, onlyMissing bool
-	var maxGracePeriod time.Duration
-	var disableEviction bool
+	var options drainOptions
 
 	cmd.SetDescription("nodegroup", "Cordon and drain a nodegroup", "", "ng")
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return runFunc(cmd, ng, undo, onlyMissing, maxGracePeriod, disableEviction)
+		if options.parallelism < 1 {
+			return fmt.Errorf("--parallelism must be at least 1")
+		}
+		if !isValidPDBTimeoutAction(options.pdbTimeoutAction) {
+			return fmt.Errorf("--pdb-timeout-action must be one of %v", drain.PDBTimeoutActions())
+		}
+		if options.dryRun && options.undo {
+			return fmt.Errorf("--dry-run cannot be used with --undo")
+		}
+		return runFunc(cmd, ng, options)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
@@ -43,19 +67,33 @@ func drainNodeGroupWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd
 		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
 		cmdutils.AddApproveFlag(fs, cmd)
 		cmdutils.AddNodeGroupFilterFlags(fs, &cmd.Include, &cmd.Exclude)
-		fs.BoolVar(&onlyMissing, "only-missing", false, "Only drain nodegroups that are not defined in the given config file")
-		fs.BoolVar(&undo, "undo", false, "Uncordon the nodegroup")
+		fs.BoolVar(&options.onlyMissing, "only-missing", false, "Only drain nodegroups that are not defined in the given config file")
+		fs.BoolVar(&options.undo, "undo", false, "Uncordon the nodegroup")
 		defaultMaxGracePeriod, _ := time.ParseDuration("10m")
-		fs.DurationVar(&maxGracePeriod, "max-grace-period", defaultMaxGracePeriod, "Maximum pods termination grace period")
+		fs.DurationVar(&options.maxGracePeriod, "max-grace-period", defaultMaxGracePeriod, "Maximum pods termination grace period")
 		defaultDisableEviction := false
-		fs.BoolVar(&disableEviction, "disable-eviction", defaultDisableEviction, "Force drain to use delete, even if eviction is supported. This will bypass checking PodDisruptionBudgets, use with caution.")
+		fs.BoolVar(&options.disableEviction, "disable-eviction", defaultDisableEviction, "Force drain to use delete, even if eviction is supported. This will bypass checking PodDisruptionBudgets, use with caution.")
+		fs.IntVar(&options.parallelism, "parallelism", 1, "Number of nodes to drain at once; evictions still respect PodDisruptionBudgets")
+		fs.DurationVar(&options.pdbTimeout, "pdb-timeout", 0, "How long evictions may be blocked by a PodDisruptionBudget before --pdb-timeout-action is applied to the blocked pods (0 waits until --timeout)")
+		fs.StringVar(&options.pdbTimeoutAction, "pdb-timeout-action", string(drain.PDBTimeoutActionDelete), fmt.Sprintf("What to do with pods blocked by a PodDisruptionBudget for longer than --pdb-timeout, one of %v", drain.PDBTimeoutActions()))
+		fs.BoolVar(&options.dryRun, "dry-run", false, "Report the pods that would be evicted or skipped on each node, without draining them")
+		fs.StringVarP(&options.output, "output", "o", printers.TableType, "Output format of the --dry-run report (valid option: table, json, yaml)")
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, &cmd.ProviderConfig, true)
 }
 
-func doDrainNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, undo, onlyMissing bool, maxGracePeriod time.Duration, disableEviction bool) error {
+func isValidPDBTimeoutAction(action string) bool {
+	for _, a := range drain.PDBTimeoutActions() {
+		if action == string(a) {
+			return true
+		}
+	}
+	return false
+}
+
+func doDrainNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, options drainOptions) error {
 	ngFilter := filter.NewNodeGroupFilter()
 
 	if err := cmdutils.NewDeleteNodeGroupLoader(cmd, ng, ngFilter).Load(); err != nil {
@@ -83,7 +121,10 @@ func doDrainNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, undo, onlyMissing bo
 
 	if cmd.ClusterConfigFile != "" {
 		logger.Info("comparing %d nodegroups defined in the given config (%q) against remote state", len(cfg.NodeGroups), cmd.ClusterConfigFile)
-		if onlyMissing {
+		if err := cmdutils.PopulatePerAZNodeGroups(stackManager, cfg); err != nil {
+			return err
+		}
+		if options.onlyMissing {
 			err = ngFilter.SetOnlyRemote(ctl.Provider.EKS(), stackManager, cfg)
 			if err != nil {
 				return err
@@ -97,16 +138,35 @@ func doDrainNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, undo, onlyMissing bo
 	}
 
 	logFiltered := cmdutils.ApplyFilter(cfg, ngFilter)
+	logFiltered()
+
+	drainInput := &nodegroup.DrainInput{
+		NodeGroups:       cmdutils.ToKubeNodeGroups(cfg),
+		Plan:             cmd.Plan,
+		MaxGracePeriod:   options.maxGracePeriod,
+		DisableEviction:  options.disableEviction,
+		Undo:             options.undo,
+		Parallelism:      options.parallelism,
+		PDBTimeout:       options.pdbTimeout,
+		PDBTimeoutAction: drain.PDBTimeoutAction(options.pdbTimeoutAction),
+	}
+
+	if options.dryRun {
+		reports, err := nodegroup.New(cfg, ctl, clientSet).DrainDryRun(drainInput)
+		if err != nil {
+			return err
+		}
+		return printDrainReports(reports, options.output)
+	}
 
 	verb := "drain"
-	if undo {
+	if options.undo {
 		verb = "uncordon"
 	}
 
 	logAction := func(resource string, count int) {
 		cmdutils.LogIntendedAction(cmd.Plan, "%s %d %s in cluster %q", verb, count, resource, cfg.Metadata.Name)
 	}
-	logFiltered()
 
 	logAction("nodegroup(s)", len(cfg.NodeGroups))
 	logAction("managed nodegroup(s)", len(cfg.ManagedNodeGroups))
@@ -116,7 +176,48 @@ func doDrainNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, undo, onlyMissing bo
 	if cmd.Plan {
 		return nil
 	}
-	allNodeGroups := cmdutils.ToKubeNodeGroups(cfg)
+	return nodegroup.New(cfg, ctl, clientSet).Drain(drainInput)
+}
+
+func printDrainReports(reports []drain.PodDrainReport, output printers.Type) error {
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+
+	if output == printers.TableType {
+		addDrainReportTableColumns(printer.(*printers.TablePrinter))
+	}
+
+	return printer.PrintObjWithKind("pods", reports, os.Stdout)
+}
 
-	return nodegroup.New(cfg, ctl, clientSet).Drain(allNodeGroups, cmd.Plan, maxGracePeriod, disableEviction)
+func addDrainReportTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("NODEGROUP", func(r drain.PodDrainReport) string {
+		return r.NodeGroup
+	})
+	printer.AddColumn("NODE", func(r drain.PodDrainReport) string {
+		return r.Node
+	})
+	printer.AddColumn("NAMESPACE", func(r drain.PodDrainReport) string {
+		return r.Namespace
+	})
+	printer.AddColumn("POD", func(r drain.PodDrainReport) string {
+		return r.Pod
+	})
+	printer.AddColumn("ACTION", func(r drain.PodDrainReport) string {
+		return string(r.Action)
+	})
+	printer.AddColumn("REASON", func(r drain.PodDrainReport) string {
+		return r.Reason
+	})
+	printer.AddColumn("LOCAL STORAGE", func(r drain.PodDrainReport) string {
+		return strconv.FormatBool(r.LocalStorage)
+	})
+	printer.AddColumn("NO CONTROLLER", func(r drain.PodDrainReport) string {
+		return strconv.FormatBool(r.NoController)
+	})
+	printer.AddColumn("BLOCKING PDB", func(r drain.PodDrainReport) string {
+		return r.BlockingPodDisruptionBudget
+	})
 }
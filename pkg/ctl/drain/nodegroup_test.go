@@ -24,7 +24,7 @@ var _ = Describe("drain node group", func() {
 			cmd := newMockEmptyCmd(args...)
 			count := 0
 			cmdutils.AddResourceCmd(cmdutils.NewGrouping(), cmd.parentCmd, func(cmd *cmdutils.Cmd) {
-				drainNodeGroupWithRunFunc(cmd, func(cmd *cmdutils.Cmd, ng *v1alpha5.NodeGroup, undo, onlyMissing bool, maxGracePeriod time.Duration, disableEviction bool) error {
+				drainNodeGroupWithRunFunc(cmd, func(cmd *cmdutils.Cmd, ng *v1alpha5.NodeGroup, options drainOptions) error {
 					Expect(cmd.ClusterConfig.Metadata.Name).To(Equal("clusterName"))
 					Expect(ng.Name).To(Equal("ng"))
 					count++
@@ -37,8 +37,27 @@ var _ = Describe("drain node group", func() {
 		},
 		Entry("with valid details", "nodegroup", "--cluster", "clusterName", "--name", "ng"),
 		Entry("with deprecated flag --only", "nodegroup", "--cluster", "clusterName", "--name", "ng", "--only", "ng"),
+		Entry("with --dry-run", "nodegroup", "--cluster", "clusterName", "--name", "ng", "--dry-run", "--output", "json"),
+		Entry("with parallelism and a PDB timeout", "nodegroup", "--cluster", "clusterName", "--name", "ng", "--parallelism", "5", "--pdb-timeout", "10m", "--pdb-timeout-action", "skip"),
 	)
 
+	It("passes the drain options", func() {
+		cmd := newMockEmptyCmd("nodegroup", "--cluster", "clusterName", "--name", "ng", "--parallelism", "5", "--pdb-timeout", "10m", "--undo")
+		cmdutils.AddResourceCmd(cmdutils.NewGrouping(), cmd.parentCmd, func(cmd *cmdutils.Cmd) {
+			drainNodeGroupWithRunFunc(cmd, func(cmd *cmdutils.Cmd, ng *v1alpha5.NodeGroup, options drainOptions) error {
+				Expect(options.parallelism).To(Equal(5))
+				Expect(options.pdbTimeout).To(Equal(10 * time.Minute))
+				Expect(options.pdbTimeoutAction).To(Equal("delete"))
+				Expect(options.undo).To(BeTrue())
+				Expect(options.dryRun).To(BeFalse())
+				Expect(options.output).To(Equal("table"))
+				return nil
+			})
+		})
+		_, err := cmd.execute()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
 	DescribeTable("invalid flags or arguments",
 		func(c invalidParamsCase) {
 			cmd := newDefaultCmd(c.args...)
@@ -54,5 +73,17 @@ var _ = Describe("drain node group", func() {
 			args:  []string{"nodegroup", "ng", "--cluster", "dummy", "--name", "ng"},
 			error: fmt.Errorf("Error: --name=ng and argument ng cannot be used at the same time"),
 		}),
+		Entry("invalid --parallelism", invalidParamsCase{
+			args:  []string{"nodegroup", "--cluster", "dummy", "--name", "ng", "--parallelism", "0"},
+			error: fmt.Errorf("Error: --parallelism must be at least 1"),
+		}),
+		Entry("invalid --pdb-timeout-action", invalidParamsCase{
+			args:  []string{"nodegroup", "--cluster", "dummy", "--name", "ng", "--pdb-timeout-action", "retry"},
+			error: fmt.Errorf("Error: --pdb-timeout-action must be one of [delete skip]"),
+		}),
+		Entry("--dry-run with --undo", invalidParamsCase{
+			args:  []string{"nodegroup", "--cluster", "dummy", "--name", "ng", "--dry-run", "--undo"},
+			error: fmt.Errorf("Error: --dry-run cannot be used with --undo"),
+		}),
 	)
 })
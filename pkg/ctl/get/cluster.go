@@ -3,12 +3,14 @@ package get
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	awseks "github.com/aws/aws-sdk-go/service/eks"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/printers"
 
@@ -127,7 +129,11 @@ func getAndPrintCluster(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, params
 	}
 
 	if params.output == printers.TableType {
-		addGetClusterSummaryTableColumns(printer.(*printers.TablePrinter))
+		clusterStack, err := ctl.NewStackManager(cfg).DescribeClusterStack()
+		if err != nil {
+			return err
+		}
+		addGetClusterSummaryTableColumns(printer.(*printers.TablePrinter), clusterStack)
 	}
 
 	cluster, err := ctl.GetCluster(cfg.Metadata.Name)
@@ -139,7 +145,7 @@ func getAndPrintCluster(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, params
 	return printer.PrintObjWithKind("clusters", []*awseks.Cluster{cluster}, os.Stdout)
 }
 
-func addGetClusterSummaryTableColumns(printer *printers.TablePrinter) {
+func addGetClusterSummaryTableColumns(printer *printers.TablePrinter, clusterStack *manager.Stack) {
 	printer.AddColumn("NAME", func(c *awseks.Cluster) string {
 		if c.Name == nil {
 			return "-"
@@ -201,4 +207,10 @@ func addGetClusterSummaryTableColumns(printer *printers.TablePrinter) {
 		}
 		return "EKS"
 	})
+	printer.AddColumn("TERMINATION PROTECTION", func(c *awseks.Cluster) string {
+		if clusterStack == nil {
+			return "-"
+		}
+		return strconv.FormatBool(api.IsEnabled(clusterStack.EnableTerminationProtection))
+	})
 }
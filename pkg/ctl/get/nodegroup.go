@@ -78,11 +78,24 @@ func doGetNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *getCmdParams)
 			return err
 		}
 	} else {
-		summary, err := nodegroup.New(cfg, ctl, clientSet).Get(ng.Name)
+		names := []string{ng.Name}
+		perAZStacks, err := cmdutils.PerAZNodeGroupStacks(ctl.NewStackManager(cfg), ng.Name)
 		if err != nil {
 			return err
 		}
-		summaries = append(summaries, summary)
+		if len(perAZStacks) > 0 {
+			names = nil
+			for _, s := range perAZStacks {
+				names = append(names, s.NodeGroupName)
+			}
+		}
+		for _, name := range names {
+			summary, err := nodegroup.New(cfg, ctl, clientSet).Get(name)
+			if err != nil {
+				return err
+			}
+			summaries = append(summaries, summary)
+		}
 	}
 
 	printer, err := printers.NewPrinter(params.output)
@@ -0,0 +1,113 @@
+package replace
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/weaveworks/eksctl/pkg/actions/nodegroup"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
+)
+
+type replaceOptions struct {
+	nodeGroupName       string
+	updateAuthConfigMap bool
+	maxGracePeriod      time.Duration
+	disableEviction     bool
+	pauseBeforeDelete   bool
+	disableProtection   bool
+}
+
+func replaceNodeGroupCmd(cmd *cmdutils.Cmd) {
+	replaceNodeGroupWithRunFunc(cmd, func(cmd *cmdutils.Cmd, options replaceOptions) error {
+		return doReplaceNodeGroup(cmd, options)
+	})
+}
+
+func replaceNodeGroupWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, options replaceOptions) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var options replaceOptions
+
+	cmd.SetDescription("nodegroup", "Replace a nodegroup with a new nodegroup defined in a config file",
+		"Creates the new nodegroups of the config file, drains the nodes of the old nodegroup once the new nodes are ready, and deletes the old nodegroup once its workloads have been rescheduled", "ng")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return runFunc(cmd, options)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		fs.StringVar(&cfg.Metadata.Name, "cluster", "", "EKS cluster name")
+		cmdutils.AddRegionFlag(fs, &cmd.ProviderConfig)
+		fs.StringVarP(&options.nodeGroupName, "name", "n", "", "name of the nodegroup to replace")
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddNodeGroupFilterFlags(fs, &cmd.Include, &cmd.Exclude)
+		cmdutils.AddUpdateAuthConfigMap(fs, &options.updateAuthConfigMap, "Add the IAM role of the new nodegroup to aws-auth configmap")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmd.FlagSetGroup.InFlagSet("Replacement", func(fs *pflag.FlagSet) {
+		fs.DurationVar(&options.maxGracePeriod, "max-grace-period", 10*time.Minute, "Maximum pods termination grace period when draining the old nodes")
+		fs.BoolVar(&options.disableEviction, "disable-eviction", false, "Force drain to use delete, even if eviction is supported. This will bypass checking PodDisruptionBudgets, use with caution.")
+		fs.BoolVar(&options.pauseBeforeDelete, "pause-before-delete", false, "Ask for confirmation before deleting the old nodegroup, the old nodes are uncordoned if deletion is not confirmed")
+		cmdutils.AddDisableProtectionFlag(fs, &options.disableProtection)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, &cmd.ProviderConfig, true)
+}
+
+func doReplaceNodeGroup(cmd *cmdutils.Cmd, options replaceOptions) error {
+	if cmd.ClusterConfigFile == "" {
+		return cmdutils.ErrMustBeSet("--config-file")
+	}
+	if options.nodeGroupName != "" && cmd.NameArg != "" {
+		return cmdutils.ErrFlagAndArg("--name", options.nodeGroupName, cmd.NameArg)
+	}
+	if cmd.NameArg != "" {
+		options.nodeGroupName = cmd.NameArg
+	}
+	if options.nodeGroupName == "" {
+		return cmdutils.ErrMustBeSet("--name")
+	}
+
+	ngFilter := filter.NewNodeGroupFilter()
+	if err := cmdutils.NewCreateNodeGroupLoader(cmd, api.NewNodeGroup(), ngFilter, cmdutils.CreateNGOptions{}, cmdutils.CreateManagedNGOptions{}).Load(); err != nil {
+		return err
+	}
+
+	ctl, err := cmd.NewProviderForExistingCluster()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(cmd.ClusterConfig.Metadata)
+
+	if ok, err := ctl.CanOperate(cmd.ClusterConfig); !ok {
+		return err
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cmd.ClusterConfig)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
+	return nodegroup.New(cmd.ClusterConfig, ctl, clientSet).Replace(ctx, nodegroup.ReplaceOptions{
+		CreateOpts: nodegroup.CreateOpts{
+			UpdateAuthConfigMap: options.updateAuthConfigMap,
+			ConfigFileProvided:  true,
+		},
+		NodeGroupName:     options.nodeGroupName,
+		MaxGracePeriod:    options.maxGracePeriod,
+		DisableEviction:   options.disableEviction,
+		PauseBeforeDelete: options.pauseBeforeDelete,
+		DisableProtection: options.disableProtection,
+		Confirm:           cmdutils.Confirm,
+	}, ngFilter)
+}
@@ -89,5 +89,8 @@ func scaleNodegroup(cmd *cmdutils.Cmd, ng *api.NodeGroupBase) error {
 		return err
 	}
 
-	return nodegroup.New(cfg, ctl, nil).Scale(ng)
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
+	return nodegroup.New(cfg, ctl, nil).Scale(ctx, ng)
 }
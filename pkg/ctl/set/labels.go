@@ -63,10 +63,13 @@ func setLabels(cmd *cmdutils.Cmd, options labelOptions) error {
 		logger.Info("setting label(s) on nodegroup %s in cluster %s", options.nodeGroupName, cmd.ClusterConfig.Metadata)
 	}
 
-	manager := label.New(cfg.Metadata.Name, service, ctl.Provider.EKS())
+	manager := label.New(cfg.Metadata.Name, service, ctl.Provider.EKS(), ctl.Provider.ASG())
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
 	// when there is no config file provided
 	if cmd.ClusterConfigFile == "" {
-		if err := manager.Set(options.nodeGroupName, options.labels); err != nil {
+		if err := manager.Set(ctx, options.nodeGroupName, options.labels); err != nil {
 			return err
 		}
 		logger.Info("done")
@@ -85,7 +88,7 @@ func setLabels(cmd *cmdutils.Cmd, options labelOptions) error {
 			logger.Info("no new labels to add for nodegroup %s", mng.Name)
 			continue
 		}
-		if err := manager.Set(mng.Name, mng.Labels); err != nil {
+		if err := manager.Set(ctx, mng.Name, mng.Labels); err != nil {
 			return err
 		}
 	}
@@ -21,7 +21,7 @@ func updateAddonCmd(cmd *cmdutils.Cmd) {
 		"",
 	)
 
-	var force, wait bool
+	var force, wait, plan bool
 	cmd.ClusterConfig.Addons = []*api.Addon{{}}
 	cmd.FlagSetGroup.InFlagSet("Addon", func(fs *pflag.FlagSet) {
 		fs.StringVar(&cmd.ClusterConfig.Addons[0].Name, "name", "", "Addon name")
@@ -29,6 +29,7 @@ func updateAddonCmd(cmd *cmdutils.Cmd) {
 		fs.StringVar(&cmd.ClusterConfig.Addons[0].ServiceAccountRoleARN, "service-account-role-arn", "", "Addon serviceAccountRoleARN")
 		fs.BoolVar(&force, "force", false, "Force applies the add-on to overwrite an existing add-on")
 		fs.BoolVar(&wait, "wait", false, "Wait for the addon update to complete")
+		fs.BoolVar(&plan, "plan", false, "Preview the changes to the addon and its IAM role stack without applying them")
 	})
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
@@ -41,11 +42,11 @@ func updateAddonCmd(cmd *cmdutils.Cmd) {
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return updateAddon(cmd, force, wait)
+		return updateAddon(cmd, force, wait, plan)
 	}
 }
 
-func updateAddon(cmd *cmdutils.Cmd, force, wait bool) error {
+func updateAddon(cmd *cmdutils.Cmd, force, wait, plan bool) error {
 	if err := cmdutils.NewCreateOrUpgradeAddonLoader(cmd).Load(); err != nil {
 		return err
 	}
@@ -87,11 +88,20 @@ func updateAddon(cmd *cmdutils.Cmd, force, wait bool) error {
 		return err
 	}
 
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
 	for _, a := range cmd.ClusterConfig.Addons {
 		if force { //force is specified at cmdline level
 			a.Force = true
 		}
-		err := addonManager.Update(a, wait)
+		if plan {
+			if err := addonManager.PlanUpdate(ctx, a); err != nil {
+				return err
+			}
+			continue
+		}
+		err := addonManager.Update(ctx, a, wait)
 		if err != nil {
 			return err
 		}
@@ -20,7 +20,7 @@ func updateNodeGroupCmd(cmd *cmdutils.Cmd) {
 
 		Please consult the eksctl documentation for more info on which config fields can be updated with this command.
 		To upgrade a nodegroup, please use 'eksctl upgrade nodegroup' instead.
-		Note that this is only available for managed nodegroups. 
+		For self-managed nodegroups, only scheduledActions, lifecycleHooks and warmPool can be updated.
 	`),
 	)
 
@@ -51,5 +51,8 @@ func updateNodegroup(cmd *cmdutils.Cmd) error {
 		return err
 	}
 
-	return nodegroup.New(cmd.ClusterConfig, ctl, nil).Update()
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
+	return nodegroup.New(cmd.ClusterConfig, ctl, nil).Update(ctx)
 }
@@ -27,6 +27,24 @@ var _ = Describe("update nodegroup", func() {
 		cmd := newMockCmd("nodegroup", "--config-file", config)
 		_, err := cmd.execute()
 		Expect(err).To(HaveOccurred())
-		Expect(err).To(MatchError(ContainSubstring("managedNodeGroups field must be set")))
+		Expect(err).To(MatchError(ContainSubstring("managedNodeGroups or nodeGroups field must be set")))
+	})
+
+	It("validates the Auto Scaling group features of self-managed nodegroups", func() {
+		cfg := &api.ClusterConfig{
+			TypeMeta: api.ClusterConfigTypeMeta(),
+			Metadata: &api.ClusterMeta{
+				Name:   "cluster-1",
+				Region: "us-west-2",
+			},
+			NodeGroups: []*api.NodeGroup{{
+				NodeGroupBase: &api.NodeGroupBase{Name: "ng-1"},
+				WarmPool:      &api.WarmPool{PoolState: "Paused"},
+			}},
+		}
+		config := ctltest.CreateConfigFile(cfg)
+		cmd := newMockCmd("nodegroup", "--config-file", config)
+		_, err := cmd.execute()
+		Expect(err).To(MatchError(ContainSubstring("nodeGroups[0].warmPool.poolState must be one of Stopped, Running or Hibernated")))
 	})
 })
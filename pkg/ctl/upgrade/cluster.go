@@ -80,5 +80,8 @@ func DoUpgradeCluster(cmd *cmdutils.Cmd) error {
 		return err
 	}
 
-	return c.Upgrade(cmd.Plan)
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
+	return c.Upgrade(ctx, cmd.Plan)
 }
@@ -20,9 +20,21 @@ func upgradeNodeGroupCmd(cmd *cmdutils.Cmd) {
 
 	cmd.SetDescription("nodegroup", "Upgrade nodegroup", "")
 
-	var options managed.UpgradeOptions
-	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+	var (
+		options                                  managed.UpgradeOptions
+		maxUnavailable, maxUnavailablePercentage int
+	)
+	cmd.CobraCommand.RunE = func(c *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
+		if f := c.Flags(); f.Changed("max-unavailable") || f.Changed("max-unavailable-percentage") {
+			options.UpdateConfig = &api.NodeGroupUpdateConfig{}
+			if f.Changed("max-unavailable") {
+				options.UpdateConfig.MaxUnavailable = &maxUnavailable
+			}
+			if f.Changed("max-unavailable-percentage") {
+				options.UpdateConfig.MaxUnavailablePercentage = &maxUnavailablePercentage
+			}
+		}
 		return upgradeNodeGroup(cmd, options)
 	}
 
@@ -33,6 +45,16 @@ func upgradeNodeGroupCmd(cmd *cmdutils.Cmd) {
 		fs.BoolVar(&options.ForceUpgrade, "force-upgrade", false, "Force the update if the existing node group's pods are unable to be drained due to a pod disruption budget issue")
 		fs.StringVar(&options.ReleaseVersion, "release-version", "", "AMI version of the EKS optimized AMI to use")
 		fs.BoolVar(&options.Wait, "wait", true, "nodegroup upgrade to complete")
+		fs.BoolVar(&options.Plan, "plan", false, "preview the resource changes to the nodegroup stack without applying them")
+	})
+
+	cmd.FlagSetGroup.InFlagSet("Self-managed nodegroup", func(fs *pflag.FlagSet) {
+		fs.StringVar(&options.AMI, "ami", "", "AMI to upgrade to, by default the EKS-optimized AMI of the same family for the Kubernetes version is used")
+		fs.IntVar(&maxUnavailable, "max-unavailable", 1, "maximum number of nodes that are replaced at the same time")
+		fs.IntVar(&maxUnavailablePercentage, "max-unavailable-percentage", 0, "maximum percentage of nodes that are replaced at the same time")
+		fs.BoolVar(&options.InstanceRefresh, "instance-refresh", false, "replace instances with an ASG instance refresh instead of draining and terminating them in batches")
+		fs.DurationVar(&options.MaxGracePeriod, "max-grace-period", 10*time.Minute, "maximum pods termination grace period when draining nodes")
+		fs.BoolVar(&options.DisableEviction, "disable-eviction", false, "force drain to use delete, even if eviction is supported. This will bypass checking PodDisruptionBudgets, use with caution.")
 	})
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
@@ -78,6 +100,9 @@ func upgradeNodeGroup(cmd *cmdutils.Cmd, options managed.UpgradeOptions) error {
 		return err
 	}
 
-	return nodegroup.New(cfg, ctl, clientSet).Upgrade(options)
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
+	return nodegroup.New(cfg, ctl, clientSet).Upgrade(ctx, options)
 
 }
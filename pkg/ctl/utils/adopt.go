@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/weaveworks/eksctl/pkg/actions/adopt"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+func adoptCmd(cmd *cmdutils.Cmd) {
+	adoptWithRunFunc(cmd, doAdopt)
+}
+
+func adoptWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("adopt", "Adopt existing resources into the CloudFormation stacks of a cluster",
+		"Imports the existing VPC, subnets, control plane security group and IAM roles described in the config file into "+
+			"the cluster stack, which is created if the cluster has none, and the auto scaling groups named after nodegroups that have no stack into new nodegroup stacks. "+
+			"Adopted resources are retained when their stack is deleted")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		if err := cmdutils.NewUtilsAdoptLoader(cmd).Load(); err != nil {
+			return err
+		}
+		return runFunc(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, &cmd.ProviderConfig, false)
+}
+
+func doAdopt(cmd *cmdutils.Cmd) error {
+	cfg := cmd.ClusterConfig
+
+	ctl, err := cmd.NewProviderForExistingCluster()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
+	stackManager := ctl.NewStackManager(cfg)
+	return adopt.New(cfg, stackManager, ctl.Provider.EC2(), ctl.Provider.IAM(), ctl.Provider.ASG()).Adopt(ctx, cmd.Plan)
+}
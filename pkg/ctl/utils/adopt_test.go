@@ -0,0 +1,14 @@
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("adopt", func() {
+	It("requires a config file", func() {
+		cmd := newMockCmd("adopt", "--approve")
+		_, err := cmd.execute()
+		Expect(err).To(MatchError(ContainSubstring("--config-file must be set")))
+	})
+})
@@ -2,12 +2,14 @@ package utils
 
 import (
 	"os"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/printers"
 
@@ -20,6 +22,7 @@ func describeStacksCmd(cmd *cmdutils.Cmd) {
 
 	var all, events, trail bool
 	var output printers.Type
+	var graph string
 
 	cmd.SetDescription("describe-stacks", "Describe CloudFormation stack for a given cluster", "")
 
@@ -34,6 +37,16 @@ func describeStacksCmd(cmd *cmdutils.Cmd) {
 				return errors.Errorf("since the output flag is specified, the flags `all`, `events` and `trail` cannot be used")
 			}
 		}
+		if graph != "" {
+			if output != "" || events || trail {
+				return errors.Errorf("since the graph flag is specified, the flags `output`, `events` and `trail` cannot be used")
+			}
+			if graph != graphFormatDOT && graph != graphFormatMermaid {
+				return errors.Errorf("graph format %q is not supported (valid options: %s and %s)", graph, graphFormatDOT, graphFormatMermaid)
+			}
+			logger.Writer = os.Stderr
+			return doDescribeStacksGraph(cmd, graph)
+		}
 		switch output {
 		case printers.TableType:
 			return errors.Errorf("output type %q is not supported", output)
@@ -54,12 +67,19 @@ func describeStacksCmd(cmd *cmdutils.Cmd) {
 		fs.BoolVar(&events, "events", false, "include stack events")
 		fs.BoolVar(&trail, "trail", false, "lookup CloudTrail events for the cluster")
 		fs.StringVarP(&output, "output", "o", "", "specifies the output formats (valid option: json and yaml)")
+		fs.StringVar(&graph, "graph", "", "print the dependencies between the stacks as a graph (valid options: dot and mermaid)")
+		fs.Lookup("graph").NoOptDefVal = graphFormatDOT
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, &cmd.ProviderConfig, false)
 }
 
+const (
+	graphFormatDOT     = "dot"
+	graphFormatMermaid = "mermaid"
+)
+
 func doDescribeStacksCmd(cmd *cmdutils.Cmd, all, events, trail bool, printer printers.OutputPrinter) error {
 	cfg := cmd.ClusterConfig
 
@@ -71,16 +91,8 @@ func doDescribeStacksCmd(cmd *cmdutils.Cmd, all, events, trail bool, printer pri
 		cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
 	}
 
-	if cfg.Metadata.Name != "" && cmd.NameArg != "" {
-		return cmdutils.ErrFlagAndArg(cmdutils.ClusterNameFlag(cmd), cfg.Metadata.Name, cmd.NameArg)
-	}
-
-	if cmd.NameArg != "" {
-		cfg.Metadata.Name = cmd.NameArg
-	}
-
-	if cfg.Metadata.Name == "" {
-		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
+	if err := setClusterName(cmd); err != nil {
+		return err
 	}
 
 	stackManager := ctl.NewStackManager(cfg)
@@ -125,3 +137,52 @@ func doDescribeStacksCmd(cmd *cmdutils.Cmd, all, events, trail bool, printer pri
 
 	return nil
 }
+
+func doDescribeStacksGraph(cmd *cmdutils.Cmd, format string) error {
+	cfg := cmd.ClusterConfig
+
+	ctl, err := cmd.NewProviderForExistingCluster()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+
+	if err := setClusterName(cmd); err != nil {
+		return err
+	}
+
+	graph, err := manager.NewStackGraph(ctl.NewStackManager(cfg))
+	if err != nil {
+		return err
+	}
+
+	for _, s := range graph.Stacks {
+		if len(s.ImportedBy) > 0 {
+			logger.Info("stack %q cannot be deleted before %s, which import its outputs", s.Name, strings.Join(s.ImportedBy, ", "))
+		}
+		if s.Stale {
+			logger.Warning("stack %q is stale, it was last updated by an older version of eksctl than the cluster stack", s.Name)
+		}
+	}
+
+	if format == graphFormatMermaid {
+		return graph.WriteMermaid(os.Stdout)
+	}
+	return graph.WriteDOT(os.Stdout)
+}
+
+func setClusterName(cmd *cmdutils.Cmd) error {
+	cfg := cmd.ClusterConfig
+	if cfg.Metadata.Name != "" && cmd.NameArg != "" {
+		return cmdutils.ErrFlagAndArg(cmdutils.ClusterNameFlag(cmd), cfg.Metadata.Name, cmd.NameArg)
+	}
+
+	if cmd.NameArg != "" {
+		cfg.Metadata.Name = cmd.NameArg
+	}
+
+	if cfg.Metadata.Name == "" {
+		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
+	}
+	return nil
+}
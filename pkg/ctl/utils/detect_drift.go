@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/printers"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
+)
+
+// driftedResource is a row of the detect-drift table
+type driftedResource struct {
+	StackName string
+	manager.ResourceDrift
+}
+
+func detectDriftCmd(cmd *cmdutils.Cmd) {
+	detectDriftWithRunFunc(cmd, doDetectDrift)
+}
+
+func detectDriftWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, output printers.Type) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var output printers.Type
+
+	cmd.SetDescription("detect-drift", "Detect drift of the CloudFormation stacks of a cluster",
+		"Runs CloudFormation drift detection on all stacks created by eksctl for the cluster and reports resources that "+
+			"were modified or deleted outside of CloudFormation; exits with an error when drift is found")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return runFunc(cmd, output)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, &cmd.ProviderConfig)
+		fs.StringVarP(&output, "output", "o", printers.TableType, "specifies the output format (valid option: table, json, yaml)")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, &cmd.ProviderConfig, false)
+}
+
+func doDetectDrift(cmd *cmdutils.Cmd, output printers.Type) error {
+	cfg := cmd.ClusterConfig
+
+	if cfg.Metadata.Name != "" && cmd.NameArg != "" {
+		return cmdutils.ErrFlagAndArg(cmdutils.ClusterNameFlag(cmd), cfg.Metadata.Name, cmd.NameArg)
+	}
+
+	if cmd.NameArg != "" {
+		cfg.Metadata.Name = cmd.NameArg
+	}
+
+	if cfg.Metadata.Name == "" {
+		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
+	}
+
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+
+	ctl, err := cmd.NewProviderForExistingCluster()
+	if err != nil {
+		return err
+	}
+
+	if output == printers.TableType {
+		cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+	} else {
+		//log warnings and errors to stdout
+		logger.Writer = os.Stderr
+	}
+
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
+	drifts, err := detectDrift(ctx, ctl.NewStackManager(cfg))
+	if err != nil {
+		return err
+	}
+
+	if output == printers.TableType {
+		var rows []driftedResource
+		for _, d := range drifts {
+			for _, r := range d.Resources {
+				rows = append(rows, driftedResource{StackName: d.StackName, ResourceDrift: r})
+			}
+		}
+		addDriftTableColumns(printer.(*printers.TablePrinter))
+		if err := printer.PrintObjWithKind("drifted resources", rows, os.Stdout); err != nil {
+			return err
+		}
+	} else if err := printer.PrintObj(drifts, os.Stdout); err != nil {
+		return err
+	}
+
+	var drifted []string
+	for _, d := range drifts {
+		if d.IsDrifted() {
+			drifted = append(drifted, d.StackName)
+		}
+	}
+	if len(drifted) > 0 {
+		return fmt.Errorf("drift detected in %d stack(s): %s", len(drifted), strings.Join(drifted, ", "))
+	}
+	logger.Success("no drift detected in %d stack(s) of cluster %q", len(drifts), cfg.Metadata.Name)
+	return nil
+}
+
+// detectDrift runs drift detection on all stacks of the cluster in parallel;
+// stacks that are being created, updated or deleted are skipped
+func detectDrift(ctx context.Context, stackManager manager.StackManager) ([]*manager.StackDrift, error) {
+	stacks, err := stackManager.DescribeStacks()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu     sync.Mutex
+		drifts []*manager.StackDrift
+	)
+	taskTree := &tasks.TaskTree{Parallel: true}
+	for _, s := range stacks {
+		s := s
+		if strings.HasSuffix(*s.StackStatus, "_IN_PROGRESS") || *s.StackStatus == cloudformation.StackStatusDeleteComplete {
+			logger.Warning("skipping drift detection for stack %q in status %s", *s.StackName, *s.StackStatus)
+			continue
+		}
+		taskTree.Append(&tasks.GenericTask{
+			Description: fmt.Sprintf("detect drift of stack %q", *s.StackName),
+			Doer: func() error {
+				drift, err := stackManager.DetectStackDrift(ctx, s)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				drifts = append(drifts, drift)
+				return nil
+			},
+		})
+	}
+
+	if errs := taskTree.DoAllSyncWithContext(ctx); len(errs) > 0 {
+		for _, err := range errs {
+			logger.Critical("%s\n", err.Error())
+		}
+		return nil, errors.New("failed to detect drift of all stacks")
+	}
+
+	// tasks complete in any order, keep the output stable
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].StackName < drifts[j].StackName
+	})
+	return drifts, nil
+}
+
+func addDriftTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("STACK", func(r driftedResource) string {
+		return r.StackName
+	})
+	printer.AddColumn("RESOURCE", func(r driftedResource) string {
+		return r.LogicalID
+	})
+	printer.AddColumn("TYPE", func(r driftedResource) string {
+		return r.ResourceType
+	})
+	printer.AddColumn("STATUS", func(r driftedResource) string {
+		return r.Status
+	})
+	printer.AddColumn("DIFFERENCES", func(r driftedResource) string {
+		var differences []string
+		for _, d := range r.Differences {
+			differences = append(differences, fmt.Sprintf("%s (%s)", d.PropertyPath, d.DifferenceType))
+		}
+		return strings.Join(differences, ", ")
+	})
+}
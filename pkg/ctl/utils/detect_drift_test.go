@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
+)
+
+var _ = Describe("detect-drift", func() {
+	It("requires a cluster name", func() {
+		cmd := newMockCmd("detect-drift")
+		_, err := cmd.execute()
+		Expect(err).To(MatchError(ContainSubstring("--cluster must be set")))
+	})
+
+	It("detects drift of all settled stacks, sorted by name", func() {
+		stackManager := new(fakes.FakeStackManager)
+		stackManager.DescribeStacksReturns([]*manager.Stack{
+			{StackName: aws.String("eksctl-test-nodegroup-ng-1"), StackStatus: aws.String(cloudformation.StackStatusUpdateComplete)},
+			{StackName: aws.String("eksctl-test-cluster"), StackStatus: aws.String(cloudformation.StackStatusCreateComplete)},
+			{StackName: aws.String("eksctl-test-nodegroup-ng-2"), StackStatus: aws.String(cloudformation.StackStatusCreateInProgress)},
+		}, nil)
+		stackManager.DetectStackDriftStub = func(_ context.Context, s *manager.Stack) (*manager.StackDrift, error) {
+			drift := &manager.StackDrift{StackName: *s.StackName, Status: cloudformation.StackDriftStatusInSync}
+			if *s.StackName == "eksctl-test-cluster" {
+				drift.Status = cloudformation.StackDriftStatusDrifted
+				drift.Resources = []manager.ResourceDrift{{
+					LogicalID:    "ControlPlaneSecurityGroup",
+					ResourceType: "AWS::EC2::SecurityGroup",
+					Status:       cloudformation.StackResourceDriftStatusModified,
+				}}
+			}
+			return drift, nil
+		}
+
+		drifts, err := detectDrift(context.Background(), stackManager)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stackManager.DetectStackDriftCallCount()).To(Equal(2))
+		Expect(drifts).To(HaveLen(2))
+		Expect(drifts[0].StackName).To(Equal("eksctl-test-cluster"))
+		Expect(drifts[0].IsDrifted()).To(BeTrue())
+		Expect(drifts[1].StackName).To(Equal("eksctl-test-nodegroup-ng-1"))
+		Expect(drifts[1].IsDrifted()).To(BeFalse())
+	})
+})
@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cfn/terraform"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
+)
+
+const terraformConfigFile = "main.tf"
+
+type exportTerraformOptions struct {
+	outputDir   string
+	zones       []string
+	withImports bool
+}
+
+func exportTerraformCmd(cmd *cmdutils.Cmd) {
+	exportTerraformWithRunFunc(cmd, doExportTerraform)
+}
+
+func exportTerraformWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, options exportTerraformOptions) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var options exportTerraformOptions
+
+	cmd.SetDescription("export-terraform", "Export the CloudFormation stacks of a cluster as Terraform configuration",
+		"Converts the CloudFormation templates eksctl generates for the cluster in the config file to Terraform configuration. "+
+			"With --with-imports, the stacks of an existing cluster are exported instead, along with import blocks that "+
+			"map every resource to its ID so the cluster can be adopted by Terraform")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return runFunc(cmd, options)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, &cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		fs.StringVar(&options.outputDir, "output-dir", "", fmt.Sprintf("directory to write %s to", terraformConfigFile))
+		fs.StringSliceVar(&options.zones, "zones", nil, "availability zones of the subnets, required unless set in the config file")
+		fs.BoolVar(&options.withImports, "with-imports", false, "export the stacks of an existing cluster and add import blocks for their resources")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, &cmd.ProviderConfig, false)
+}
+
+func doExportTerraform(cmd *cmdutils.Cmd, options exportTerraformOptions) error {
+	if err := cmdutils.NewUtilsExportTerraformLoader(cmd, options.withImports).Load(); err != nil {
+		return err
+	}
+	cfg := cmd.ClusterConfig
+
+	if options.outputDir == "" {
+		return cmdutils.ErrMustBeSet("--output-dir")
+	}
+
+	var exporter *terraform.Exporter
+	if options.withImports {
+		if len(options.zones) > 0 {
+			return fmt.Errorf("--zones and --with-imports %s", cmdutils.IncompatibleFlags)
+		}
+		ctl, err := cmd.NewProviderForExistingCluster()
+		if err != nil {
+			return err
+		}
+		cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+
+		exporter = terraform.NewExporter(cfg.Metadata.Name, cfg.Metadata.Region)
+		if err := addDeployedStacks(exporter, ctl.NewStackManager(cfg), ctl.Provider.EC2()); err != nil {
+			return err
+		}
+	} else {
+		if err := cmd.InitOffline(); err != nil {
+			return err
+		}
+		if err := cmdutils.SetClusterVersion(cfg.Metadata); err != nil {
+			return err
+		}
+		manifest, err := cmdutils.RenderClusterTemplates(cfg, filter.NewNodeGroupFilter(), options.zones)
+		if err != nil {
+			return err
+		}
+		for _, u := range manifest.Unresolved {
+			logger.Warning("%s was set to %q: %s", u.Field, manager.UnresolvedValue, u.Reason)
+		}
+
+		exporter = terraform.NewExporter(cfg.Metadata.Name, cfg.Metadata.Region)
+		for _, s := range manifest.Stacks {
+			if err := exporter.AddStack(s.StackName, s.TemplateBody); err != nil {
+				return err
+			}
+		}
+	}
+
+	result, err := exporter.Export()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(options.outputDir, 0755); err != nil {
+		return errors.Wrapf(err, "creating directory %q", options.outputDir)
+	}
+	path := filepath.Join(options.outputDir, terraformConfigFile)
+	if err := os.WriteFile(path, result.Config, 0644); err != nil {
+		return errors.Wrapf(err, "writing %q", path)
+	}
+
+	for _, r := range result.Unsupported {
+		logger.Warning("resource %s cannot be exported to Terraform and was left out", r)
+	}
+	for _, r := range result.MissingImports {
+		logger.Warning("the ID of %s could not be determined, add an import block for it manually", r)
+	}
+	if options.withImports {
+		logger.Success("wrote Terraform configuration with %d import block(s) to %q", result.Imports, path)
+	} else {
+		logger.Success("wrote Terraform configuration to %q", path)
+	}
+	return nil
+}
+
+// addDeployedStacks adds the templates of all stacks of the cluster along with the IDs of their resources
+func addDeployedStacks(exporter *terraform.Exporter, stackManager manager.StackManager, ec2API ec2iface.EC2API) error {
+	stacks, err := stackManager.DescribeStacks()
+	if err != nil {
+		return err
+	}
+	sort.Slice(stacks, func(i, j int) bool {
+		return *stacks[i].StackName < *stacks[j].StackName
+	})
+
+	for _, s := range stacks {
+		if strings.HasSuffix(*s.StackStatus, "_IN_PROGRESS") || *s.StackStatus == cloudformation.StackStatusDeleteComplete {
+			logger.Warning("skipping stack %q in status %s", *s.StackName, *s.StackStatus)
+			continue
+		}
+
+		template, err := stackManager.GetStackTemplate(*s.StackName)
+		if err != nil {
+			return err
+		}
+		if err := exporter.AddStack(*s.StackName, []byte(template)); err != nil {
+			return err
+		}
+
+		resources, err := stackManager.ListStackResources(s)
+		if err != nil {
+			return err
+		}
+		physicalIDs, err := importableIDs(resources, ec2API)
+		if err != nil {
+			return err
+		}
+		exporter.SetPhysicalIDs(*s.StackName, physicalIDs)
+	}
+	return nil
+}
+
+// importableIDs maps the logical IDs of resources to their physical IDs; Elastic IPs are identified by their
+// public IP in CloudFormation, but imported using their allocation ID
+func importableIDs(resources []*cloudformation.StackResourceSummary, ec2API ec2iface.EC2API) (map[string]string, error) {
+	physicalIDs := map[string]string{}
+	var publicIPs []string
+	for _, r := range resources {
+		physicalID := aws.StringValue(r.PhysicalResourceId)
+		physicalIDs[*r.LogicalResourceId] = physicalID
+		if *r.ResourceType == "AWS::EC2::EIP" && physicalID != "" {
+			publicIPs = append(publicIPs, physicalID)
+		}
+	}
+	if len(publicIPs) == 0 {
+		return physicalIDs, nil
+	}
+
+	output, err := ec2API.DescribeAddresses(&ec2.DescribeAddressesInput{
+		PublicIps: aws.StringSlice(publicIPs),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "describing Elastic IPs")
+	}
+	allocationIDs := map[string]string{}
+	for _, address := range output.Addresses {
+		allocationIDs[aws.StringValue(address.PublicIp)] = aws.StringValue(address.AllocationId)
+	}
+	for logicalID, physicalID := range physicalIDs {
+		if allocationID, ok := allocationIDs[physicalID]; ok {
+			physicalIDs[logicalID] = allocationID
+		}
+	}
+	return physicalIDs, nil
+}
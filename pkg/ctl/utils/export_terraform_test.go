@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
+	"github.com/weaveworks/eksctl/pkg/cfn/terraform"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("export-terraform", func() {
+	It("requires a config file unless exporting with imports", func() {
+		cmd := newMockCmd("export-terraform", "--output-dir", "terraform")
+		_, err := cmd.execute()
+		Expect(err).To(MatchError(ContainSubstring("--config-file must be set")))
+	})
+
+	Context("without imports", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "export-terraform")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		exportConfig := func(config string) (string, error) {
+			configFile := filepath.Join(dir, "cluster.yaml")
+			Expect(os.WriteFile(configFile, []byte(config), 0644)).To(Succeed())
+			outputDir := filepath.Join(dir, "terraform")
+			cmd := newMockCmd("export-terraform", "--config-file", configFile, "--output-dir", outputDir)
+			if _, err := cmd.execute(); err != nil {
+				return "", err
+			}
+			result, err := os.ReadFile(filepath.Join(outputDir, terraformConfigFile))
+			Expect(err).NotTo(HaveOccurred())
+			return string(result), nil
+		}
+
+		const clusterMeta = `apiVersion: eksctl.io/v1alpha5
+kind: ClusterConfig
+metadata:
+  name: test
+  region: us-west-2
+  version: "1.21"
+availabilityZones: [us-west-2a, us-west-2b]
+nodeGroups:
+  - name: ng-1
+`
+
+		It("exports the stacks of a cluster without a vpc in the config file", func() {
+			config, err := exportConfig(clusterMeta)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(ContainSubstring(`resource "aws_vpc" "cluster_vpc"`))
+			Expect(config).To(ContainSubstring(`resource "aws_nat_gateway"`))
+			Expect(config).To(ContainSubstring(`resource "aws_autoscaling_group"`))
+		})
+
+		It("exports the stacks of a cluster with a vpc in the config file", func() {
+			config, err := exportConfig(clusterMeta + `vpc:
+  cidr: 10.10.0.0/16
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(ContainSubstring(`"10.10.0.0/16"`))
+			Expect(config).To(ContainSubstring(`resource "aws_nat_gateway"`))
+		})
+	})
+
+	It("exports the stacks of an existing cluster with the IDs of their resources", func() {
+		stackManager := new(fakes.FakeStackManager)
+		stackManager.DescribeStacksReturns([]*manager.Stack{
+			{StackName: aws.String("eksctl-test-cluster"), StackStatus: aws.String(cloudformation.StackStatusCreateComplete)},
+			{StackName: aws.String("eksctl-test-nodegroup-ng-1"), StackStatus: aws.String(cloudformation.StackStatusDeleteInProgress)},
+		}, nil)
+		stackManager.GetStackTemplateReturns(`{
+  "Resources": {
+    "NATIP": {"Type": "AWS::EC2::EIP", "Properties": {"Domain": "vpc"}},
+    "NATGateway": {
+      "Type": "AWS::EC2::NatGateway",
+      "Properties": {"AllocationId": {"Fn::GetAtt": ["NATIP", "AllocationId"]}, "SubnetId": "subnet-1"}
+    }
+  }
+}`, nil)
+		stackManager.ListStackResourcesReturns([]*cloudformation.StackResourceSummary{
+			{LogicalResourceId: aws.String("NATIP"), PhysicalResourceId: aws.String("203.0.113.1"), ResourceType: aws.String("AWS::EC2::EIP")},
+			{LogicalResourceId: aws.String("NATGateway"), PhysicalResourceId: aws.String("nat-1"), ResourceType: aws.String("AWS::EC2::NatGateway")},
+		}, nil)
+
+		p := mockprovider.NewMockProvider()
+		p.MockEC2().On("DescribeAddresses", mock.MatchedBy(func(input *ec2.DescribeAddressesInput) bool {
+			return len(input.PublicIps) == 1 && *input.PublicIps[0] == "203.0.113.1"
+		})).Return(&ec2.DescribeAddressesOutput{
+			Addresses: []*ec2.Address{{PublicIp: aws.String("203.0.113.1"), AllocationId: aws.String("eipalloc-1")}},
+		}, nil)
+
+		exporter := terraform.NewExporter("test", "us-west-2")
+		Expect(addDeployedStacks(exporter, stackManager, p.EC2())).To(Succeed())
+		Expect(stackManager.GetStackTemplateCallCount()).To(Equal(1))
+
+		result, err := exporter.Export()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Imports).To(Equal(2))
+		Expect(string(result.Config)).To(ContainSubstring(`import {
+  to = aws_eip.cluster_natip
+  id = "eipalloc-1"
+}`))
+		Expect(string(result.Config)).To(ContainSubstring(`allocation_id = aws_eip.cluster_natip.allocation_id`))
+	})
+})
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+type renderUserDataOptions struct {
+	nodeGroupName string
+}
+
+func renderUserDataCmd(cmd *cmdutils.Cmd) {
+	renderUserDataWithRunFunc(cmd, doRenderUserData)
+}
+
+func renderUserDataWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, options renderUserDataOptions) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var options renderUserDataOptions
+
+	cmd.SetDescription("render-userdata", "Print the decoded userdata of the nodegroups in a config file",
+		"Renders the userdata eksctl would give to the nodes of each nodegroup in the config file without calling AWS, "+
+			"along with the kubelet settings, labels and taints that went into it and where each of them came from")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return runFunc(cmd, options)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddRegionFlag(fs, &cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		fs.StringVar(&options.nodeGroupName, "nodegroup", "", "name of the nodegroup to render, all nodegroups are rendered if not set")
+	})
+}
+
+func doRenderUserData(cmd *cmdutils.Cmd, options renderUserDataOptions) error {
+	if err := cmdutils.NewUtilsRenderUserDataLoader(cmd).Load(); err != nil {
+		return err
+	}
+	if err := cmd.InitOffline(); err != nil {
+		return err
+	}
+	return renderUserData(cmd.ClusterConfig, options.nodeGroupName, os.Stdout)
+}
+
+// renderUserData writes the derived settings and the decoded userdata of the given nodegroup,
+// or of all nodegroups if nodeGroupName is empty
+func renderUserData(cfg *api.ClusterConfig, nodeGroupName string, w io.Writer) error {
+	var nodePools []api.NodePool
+	for _, np := range cmdutils.ToNodePools(cfg) {
+		if nodeGroupName == "" || np.BaseNodeGroup().Name == nodeGroupName {
+			nodePools = append(nodePools, np)
+		}
+	}
+	if len(nodePools) == 0 {
+		if nodeGroupName != "" {
+			return fmt.Errorf("nodegroup %q not found in the config file", nodeGroupName)
+		}
+		return errors.New("no nodegroups found in the config file")
+	}
+
+	setUnresolvedClusterStatus(cfg)
+
+	for i, np := range nodePools {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if err := renderNodePoolUserData(cfg, np, w); err != nil {
+			return errors.Wrapf(err, "rendering userdata of nodegroup %q", np.BaseNodeGroup().Name)
+		}
+	}
+	return nil
+}
+
+func renderNodePoolUserData(cfg *api.ClusterConfig, np api.NodePool, w io.Writer) error {
+	ng := np.BaseNodeGroup()
+	// the derived settings are read before creating the bootstrapper, which sets the cluster DNS of the nodegroup
+	settings, err := nodebootstrap.DerivedSettings(cfg, np)
+	if err != nil {
+		return err
+	}
+
+	var bootstrapper nodebootstrap.Bootstrapper
+	switch np := np.(type) {
+	case *api.NodeGroup:
+		np.CustomAMI = api.IsAMI(np.AMI)
+		bootstrapper, err = nodebootstrap.NewBootstrapper(cfg, np)
+		if err != nil {
+			return err
+		}
+	case *api.ManagedNodeGroup:
+		bootstrapper = nodebootstrap.NewManagedBootstrapper(cfg, np)
+		if bootstrapper == nil {
+			return fmt.Errorf("amiFamily %s is not supported for managed nodegroups", np.AMIFamily)
+		}
+	}
+
+	userData, err := bootstrapper.UserData()
+	if err != nil {
+		return err
+	}
+	decoded, err := nodebootstrap.DecodeUserData(userData)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "# nodegroup %q (%s)\n", ng.Name, ng.AMIFamily)
+	printer := printers.NewTablePrinter()
+	addDerivedSettingColumns(printer.(*printers.TablePrinter))
+	if err := printer.PrintObjWithKind("settings", settings, w); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+	if decoded == "" {
+		fmt.Fprintln(w, "# no userdata, the nodes are bootstrapped by EKS")
+		return nil
+	}
+	fmt.Fprintln(w, decoded)
+	return nil
+}
+
+// setUnresolvedClusterStatus sets the cluster status that is normally read from the EKS API; the cluster DNS
+// can only be derived when the service IPv4 CIDR is set in the config file
+func setUnresolvedClusterStatus(cfg *api.ClusterConfig) {
+	if cfg.Status == nil {
+		cfg.Status = &api.ClusterStatus{}
+	}
+	if cfg.Status.Endpoint == "" {
+		cfg.Status.Endpoint = "https://" + manager.UnresolvedValue
+		cfg.Status.CertificateAuthorityData = []byte(manager.UnresolvedValue)
+		logger.Warning("the API server endpoint and the cluster CA can only be read from the EKS API, they were set to %q", manager.UnresolvedValue)
+	}
+	if cfg.Status.KubernetesNetworkConfig == nil && cfg.KubernetesNetworkConfig != nil && cfg.KubernetesNetworkConfig.ServiceIPv4CIDR != "" {
+		cfg.Status.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{
+			ServiceIPv4CIDR: cfg.KubernetesNetworkConfig.ServiceIPv4CIDR,
+		}
+	}
+}
+
+func addDerivedSettingColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("SETTING", func(s nodebootstrap.DerivedSetting) string {
+		return s.Name
+	})
+	printer.AddColumn("VALUE", func(s nodebootstrap.DerivedSetting) string {
+		if s.Value == "" {
+			return "-"
+		}
+		return s.Value
+	})
+	printer.AddColumn("SOURCE", func(s nodebootstrap.DerivedSetting) string {
+		return s.Source
+	})
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("render-userdata", func() {
+	It("requires a config file", func() {
+		cmd := newMockCmd("render-userdata", "--nodegroup", "ng-1")
+		_, err := cmd.execute()
+		Expect(err).To(MatchError(ContainSubstring("--config-file must be set")))
+	})
+
+	Context("with nodegroups", func() {
+		var cfg *api.ClusterConfig
+
+		BeforeEach(func() {
+			cfg = api.NewClusterConfig()
+			cfg.Metadata.Name = "test"
+			cfg.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{ServiceIPv4CIDR: "10.200.0.0/16"}
+
+			ng := cfg.NewNodeGroup()
+			ng.Name = "ng-1"
+			ng.AMIFamily = api.NodeImageFamilyBottlerocket
+			ng.Labels = map[string]string{"role": "worker"}
+			api.SetNodeGroupDefaults(ng, cfg.Metadata)
+
+			mng := api.NewManagedNodeGroup()
+			mng.Name = "mng-1"
+			mng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			api.SetManagedNodeGroupDefaults(mng, cfg.Metadata)
+			cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, mng)
+		})
+
+		It("prints the derived settings and the decoded userdata of the given nodegroup", func() {
+			out := new(bytes.Buffer)
+			Expect(renderUserData(cfg, "ng-1", out)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring(`# nodegroup "ng-1" (Bottlerocket)`))
+			Expect(out.String()).To(MatchRegexp(`clusterDNS\s+10\.200\.0\.10\s+10th address of the service IPv4 CIDR 10\.200\.0\.0/16`))
+			Expect(out.String()).To(MatchRegexp(`label\s+role=worker\s+labels of the nodegroup`))
+			Expect(out.String()).To(ContainSubstring(`cluster-dns-ip = "10.200.0.10"`))
+			Expect(out.String()).To(ContainSubstring(`api-server = "https://UNRESOLVED"`))
+			Expect(out.String()).NotTo(ContainSubstring("mng-1"))
+		})
+
+		It("renders all nodegroups when no nodegroup is given", func() {
+			out := new(bytes.Buffer)
+			Expect(renderUserData(cfg, "", out)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring(`# nodegroup "ng-1" (Bottlerocket)`))
+			Expect(out.String()).To(ContainSubstring(`# nodegroup "mng-1" (AmazonLinux2)`))
+			Expect(out.String()).To(ContainSubstring("# no userdata, the nodes are bootstrapped by EKS"))
+		})
+
+		It("fails for a nodegroup that is not in the config file", func() {
+			Expect(renderUserData(cfg, "ng-2", new(bytes.Buffer))).To(MatchError(`nodegroup "ng-2" not found in the config file`))
+		})
+	})
+})
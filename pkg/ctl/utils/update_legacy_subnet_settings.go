@@ -58,7 +58,10 @@ func doUpdateLegacySubnetSettings(cmd *cmdutils.Cmd) error {
 	}
 
 	logger.Info("updating settings { MapPublicIpOnLaunch: enabled } for public subnets %v", cfg.VPC.Subnets.Public)
-	err = stackManager.EnsureMapPublicIPOnLaunchEnabled()
+	ctx, cancel := cmdutils.NewInterruptContext()
+	defer cancel()
+
+	err = stackManager.EnsureMapPublicIPOnLaunchEnabled(ctx)
 	if err != nil {
 		logger.Warning(err.Error())
 		return err
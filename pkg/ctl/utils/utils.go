@@ -11,6 +11,10 @@ func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
 
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, writeKubeconfigCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, describeStacksCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, detectDriftCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, exportTerraformCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, renderUserDataCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, adoptCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateKubeProxyCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateAWSNodeCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateCoreDNSCmd)
@@ -18,6 +18,7 @@ package evictor
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -53,6 +54,13 @@ type Evictor struct {
 
 	policyAPIGroupVersion string
 	UseEvictions          bool
+
+	// IgnoreNamespaces are namespaces whose pods are left on the node
+	IgnoreNamespaces []string
+	// NamespaceGracePeriodSeconds overrides the maximum grace period of the pods in the given namespaces
+	NamespaceGracePeriodSeconds map[string]int
+	// NeverDeleteSelectors select pods that are only ever evicted, even if disableEviction is set
+	NeverDeleteSelectors []labels.Selector
 }
 
 func New(clientSet kubernetes.Interface, maxGracePeriod time.Duration, ignoreDaemonSets []metav1.ObjectMeta, disableEviction bool) *Evictor {
@@ -74,7 +82,7 @@ func New(clientSet kubernetes.Interface, maxGracePeriod time.Duration, ignoreDae
 
 // CanUseEvictions uses Discovery API to find out if evictions are supported
 func (d *Evictor) CanUseEvictions() error {
-	if d.disableEviction {
+	if d.disableEviction && len(d.NeverDeleteSelectors) == 0 {
 		d.UseEvictions = false
 		return nil
 	}
@@ -110,12 +118,17 @@ func (d *Evictor) CanUseEvictions() error {
 func (d *Evictor) makeDeleteOptions(pod corev1.Pod) metav1.DeleteOptions {
 	deleteOptions := metav1.DeleteOptions{}
 
+	maxGracePeriodSeconds := d.maxGracePeriodSeconds
+	if namespaceGracePeriodSeconds, ok := d.NamespaceGracePeriodSeconds[pod.Namespace]; ok {
+		maxGracePeriodSeconds = namespaceGracePeriodSeconds
+	}
+
 	gracePeriodSeconds := int64(corev1.DefaultTerminationGracePeriodSeconds)
 	if pod.Spec.TerminationGracePeriodSeconds != nil {
-		if *pod.Spec.TerminationGracePeriodSeconds < int64(d.maxGracePeriodSeconds) {
+		if *pod.Spec.TerminationGracePeriodSeconds < int64(maxGracePeriodSeconds) {
 			gracePeriodSeconds = *pod.Spec.TerminationGracePeriodSeconds
 		} else {
-			gracePeriodSeconds = int64(d.maxGracePeriodSeconds)
+			gracePeriodSeconds = int64(maxGracePeriodSeconds)
 		}
 	}
 
@@ -123,13 +136,28 @@ func (d *Evictor) makeDeleteOptions(pod corev1.Pod) metav1.DeleteOptions {
 	return deleteOptions
 }
 
-// EvictOrDeletePod will evict Pod if policy API is available, otherwise deletes it. If disableEviction is true, we skip straight to the delete step
+// EvictOrDeletePod will evict Pod if policy API is available, otherwise deletes it. If disableEviction is true, we skip straight to the delete step,
+// unless the Pod is selected by NeverDeleteSelectors
 // NOTE: CanUseEvictions must be called prior to this
 func (d *Evictor) EvictOrDeletePod(pod corev1.Pod) error {
-	if d.UseEvictions {
+	neverDelete := d.NeverDelete(pod)
+	if d.UseEvictions && (!d.disableEviction || neverDelete) {
 		return d.evictPod(pod)
 	}
-	return d.deletePod(pod)
+	if neverDelete {
+		return fmt.Errorf("pod %s/%s can only be evicted, but the cluster does not support evictions", pod.Namespace, pod.Name)
+	}
+	return d.DeletePod(pod)
+}
+
+// NeverDelete returns whether the Pod is selected by NeverDeleteSelectors
+func (d *Evictor) NeverDelete(pod corev1.Pod) bool {
+	for _, selector := range d.NeverDeleteSelectors {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return true
+		}
+	}
+	return false
 }
 
 // evictPod will evict the give Pod, or return an error if it couldn't
@@ -150,8 +178,9 @@ func (d *Evictor) evictPod(pod corev1.Pod) error {
 	return d.client.PolicyV1beta1().Evictions(eviction.Namespace).Evict(context.TODO(), eviction)
 }
 
-// deletePod will Delete the given Pod, or return an error if it couldn't
-func (d *Evictor) deletePod(pod corev1.Pod) error {
+// DeletePod will Delete the given Pod, or return an error if it couldn't; unlike
+// an eviction, deleting a Pod does not respect its PodDisruptionBudgets
+func (d *Evictor) DeletePod(pod corev1.Pod) error {
 	return d.client.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, d.makeDeleteOptions(pod))
 }
 
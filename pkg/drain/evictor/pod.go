@@ -28,12 +28,13 @@ import (
 )
 
 const (
-	daemonSetFatal      = "DaemonSet-managed Pods (use --ignore-daemonsets to ignore)"
-	daemonSetWarning    = "ignoring DaemonSet-managed Pods"
-	localStorageFatal   = "Pods with local storage (use --delete-local-data to override)"
-	localStorageWarning = "deleting Pods with local storage"
-	unmanagedFatal      = "Pods not managed by ReplicationController, ReplicaSet, Job, DaemonSet or StatefulSet (use --force to override)"
-	unmanagedWarning    = "deleting Pods not managed by ReplicationController, ReplicaSet, Job, DaemonSet or StatefulSet"
+	daemonSetFatal          = "DaemonSet-managed Pods (use --ignore-daemonsets to ignore)"
+	daemonSetWarning        = "ignoring DaemonSet-managed Pods"
+	localStorageFatal       = "Pods with local storage (use --delete-local-data to override)"
+	localStorageWarning     = "deleting Pods with local storage"
+	unmanagedFatal          = "Pods not managed by ReplicationController, ReplicaSet, Job, DaemonSet or StatefulSet (use --force to override)"
+	unmanagedWarning        = "deleting Pods not managed by ReplicationController, ReplicaSet, Job, DaemonSet or StatefulSet"
+	ignoredNamespaceWarning = "ignoring Pods in namespaces ignored by the drain policy"
 
 	drainPodAnnotation       = "pod.alpha.kubernetes.io/drain"
 	drainPodAnnotationForce  = "force"
@@ -99,6 +100,11 @@ type PodDeleteStatus struct {
 	Message string
 }
 
+// IsError returns whether the pod cannot be deleted
+func (s PodDeleteStatus) IsError() bool {
+	return s.Reason == podDeleteStatusTypeError
+}
+
 // Takes a Pod and returns a PodDeleteStatus
 type podFilter func(corev1.Pod) PodDeleteStatus
 
@@ -141,6 +147,7 @@ func makePodDeleteStatusWithError(message string) PodDeleteStatus {
 
 func (d *Evictor) makeFilters() []podFilter {
 	return []podFilter{
+		d.namespaceFilter,
 		d.annotationFilter,
 		d.daemonSetFilter,
 		d.mirrorPodFilter,
@@ -149,7 +156,8 @@ func (d *Evictor) makeFilters() []podFilter {
 	}
 }
 
-func hasLocalStorage(pod corev1.Pod) bool {
+// HasLocalStorage returns whether a pod uses emptyDir volumes backed by the node's storage
+func HasLocalStorage(pod corev1.Pod) bool {
 	for _, volume := range pod.Spec.Volumes {
 		if volume.EmptyDir != nil && volume.EmptyDir.Medium != "Memory" {
 			return true
@@ -159,6 +167,15 @@ func hasLocalStorage(pod corev1.Pod) bool {
 	return false
 }
 
+func (d *Evictor) namespaceFilter(pod corev1.Pod) PodDeleteStatus {
+	for _, namespace := range d.IgnoreNamespaces {
+		if pod.Namespace == namespace {
+			return makePodDeleteStatusWithWarning(false, ignoredNamespaceWarning)
+		}
+	}
+	return makePodDeleteStatusOkay()
+}
+
 func (d *Evictor) annotationFilter(pod corev1.Pod) PodDeleteStatus {
 	if v, ok := pod.Annotations[drainPodAnnotation]; ok {
 		annotation := fmt.Sprintf("due to annotation %s=%s", drainPodAnnotation, v)
@@ -224,7 +241,7 @@ func (d *Evictor) mirrorPodFilter(pod corev1.Pod) PodDeleteStatus {
 }
 
 func (d *Evictor) localStorageFilter(pod corev1.Pod) PodDeleteStatus {
-	if !hasLocalStorage(pod) {
+	if !HasLocalStorage(pod) {
 		return makePodDeleteStatusOkay()
 	}
 	// Any finished Pod can be removed.
@@ -1,5 +1,12 @@
 package drain
 
+import "time"
+
 func (n *NodeGroupDrainer) SetDrainer(drainer Evictor) {
 	n.evictor = drainer
 }
+
+// SetRetryDelay sets how long is waited before draining nodes with pods left to evict again.
+func SetRetryDelay(delay time.Duration) {
+	retryDelay = delay
+}
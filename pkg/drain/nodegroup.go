@@ -3,6 +3,7 @@ package drain
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -10,11 +11,14 @@ import (
 	"github.com/weaveworks/eksctl/pkg/drain/evictor"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/eks"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 )
@@ -22,59 +26,146 @@ import (
 // this is our custom addition, it's not part of the package
 // we copied from Kubernetes
 
-// retryDelay is how long is slept before retry after an error occurs during drainage
-const retryDelay = 5 * time.Second
+// retryDelay is how long is waited before draining nodes that still have pods left to evict
+// again, e.g. because an error occurred or evictions are blocked by a PodDisruptionBudget
+var retryDelay = 5 * time.Second
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 //counterfeiter:generate -o fakes/fake_evictor.go . Evictor
 type Evictor interface {
 	CanUseEvictions() error
 	EvictOrDeletePod(pod corev1.Pod) error
+	DeletePod(pod corev1.Pod) error
 	GetPodsForEviction(nodeName string) (*evictor.PodDeleteList, []error)
+	NeverDelete(pod corev1.Pod) bool
+}
+
+// PDBTimeoutAction is what is done with a pod whose eviction has been blocked
+// by a PodDisruptionBudget for longer than the PDB timeout
+type PDBTimeoutAction string
+
+const (
+	// PDBTimeoutActionDelete deletes the pod, bypassing the PodDisruptionBudget
+	PDBTimeoutActionDelete PDBTimeoutAction = "delete"
+	// PDBTimeoutActionSkip leaves the pod on the node
+	PDBTimeoutActionSkip PDBTimeoutAction = "skip"
+)
+
+// PDBTimeoutActions returns the valid PDB timeout actions
+func PDBTimeoutActions() []PDBTimeoutAction {
+	return []PDBTimeoutAction{PDBTimeoutActionDelete, PDBTimeoutActionSkip}
+}
+
+// defaultIgnoreDaemonSets are the DaemonSets whose pods are never evicted
+var defaultIgnoreDaemonSets = []metav1.ObjectMeta{
+	{
+		Namespace: "kube-system",
+		Name:      "aws-node",
+	},
+	{
+		Namespace: "kube-system",
+		Name:      "kube-proxy",
+	},
+	{
+		Name: "node-exporter",
+	},
+	{
+		Name: "prom-node-exporter",
+	},
+	{
+		Name: "weave-scope",
+	},
+	{
+		Name: "weave-scope-agent",
+	},
+	{
+		Name: "weave-net",
+	},
 }
 
 type NodeGroupDrainer struct {
-	clientSet   kubernetes.Interface
-	evictor     Evictor
-	ng          eks.KubeNodeGroup
-	waitTimeout time.Duration
-	undo        bool
+	clientSet        kubernetes.Interface
+	evictor          Evictor
+	ng               eks.KubeNodeGroup
+	waitTimeout      time.Duration
+	maxGracePeriod   time.Duration
+	undo             bool
+	disableEviction  bool
+	parallelism      int
+	pdbTimeout       time.Duration
+	pdbTimeoutAction PDBTimeoutAction
+
+	// drain policy
+	ignoreDaemonSets    []metav1.ObjectMeta
+	nodeTimeout         time.Duration
+	waitForReplacements bool
+
+	blockedEvictions   *blockedEvictions
+	evictedControllers *evictedControllers
+	nodeDrainStarts    *nodeDrainStarts
 }
 
-func NewNodeGroupDrainer(clientSet kubernetes.Interface, ng eks.KubeNodeGroup, waitTimeout time.Duration, maxGracePeriod time.Duration, undo bool, disableEviction bool) NodeGroupDrainer {
-	ignoreDaemonSets := []metav1.ObjectMeta{
-		{
-			Namespace: "kube-system",
-			Name:      "aws-node",
-		},
-		{
-			Namespace: "kube-system",
-			Name:      "kube-proxy",
-		},
-		{
-			Name: "node-exporter",
-		},
-		{
-			Name: "prom-node-exporter",
-		},
-		{
-			Name: "weave-scope",
-		},
-		{
-			Name: "weave-scope-agent",
-		},
-		{
-			Name: "weave-net",
-		},
+func NewNodeGroupDrainer(clientSet kubernetes.Interface, ng eks.KubeNodeGroup, waitTimeout time.Duration, maxGracePeriod time.Duration, undo bool, disableEviction bool, parallelism int) NodeGroupDrainer {
+	if parallelism < 1 {
+		parallelism = 1
 	}
 
 	return NodeGroupDrainer{
-		evictor:     evictor.New(clientSet, maxGracePeriod, ignoreDaemonSets, disableEviction),
-		clientSet:   clientSet,
-		ng:          ng,
-		waitTimeout: waitTimeout,
-		undo:        undo,
+		evictor:            evictor.New(clientSet, maxGracePeriod, defaultIgnoreDaemonSets, disableEviction),
+		clientSet:          clientSet,
+		ng:                 ng,
+		waitTimeout:        waitTimeout,
+		maxGracePeriod:     maxGracePeriod,
+		undo:               undo,
+		disableEviction:    disableEviction,
+		parallelism:        parallelism,
+		ignoreDaemonSets:   defaultIgnoreDaemonSets,
+		blockedEvictions:   newBlockedEvictions(),
+		evictedControllers: newEvictedControllers(),
+		nodeDrainStarts:    newNodeDrainStarts(),
+	}
+}
+
+// SetDrainPolicy configures the drainer according to the drain policy, replacing its evictor
+// with one that applies the policy
+func (n *NodeGroupDrainer) SetDrainPolicy(policy *api.DrainPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	ignoreDaemonSets := append([]metav1.ObjectMeta{}, defaultIgnoreDaemonSets...)
+	for _, ds := range policy.IgnoreDaemonSets {
+		ignoreDaemonSets = append(ignoreDaemonSets, metav1.ObjectMeta{Namespace: ds.Namespace, Name: ds.Name})
+	}
+
+	var neverDeleteSelectors []labels.Selector
+	for _, s := range policy.NeverForceDelete {
+		selector, err := labels.Parse(s)
+		if err != nil {
+			return errors.Wrapf(err, "parsing drain policy selector %q", s)
+		}
+		neverDeleteSelectors = append(neverDeleteSelectors, selector)
 	}
+
+	e := evictor.New(n.clientSet, n.maxGracePeriod, ignoreDaemonSets, n.disableEviction)
+	e.IgnoreNamespaces = policy.IgnoreNamespaces
+	e.NamespaceGracePeriodSeconds = policy.NamespaceGracePeriodSeconds
+	e.NeverDeleteSelectors = neverDeleteSelectors
+	n.evictor = e
+
+	n.ignoreDaemonSets = ignoreDaemonSets
+	if policy.NodeTimeoutSeconds != nil {
+		n.nodeTimeout = time.Duration(*policy.NodeTimeoutSeconds) * time.Second
+	}
+	n.waitForReplacements = api.IsEnabled(policy.WaitForReplacements)
+	return nil
+}
+
+// SetPDBTimeout sets how long evictions may be blocked by a PodDisruptionBudget
+// before the action is applied to the blocked pods; a zero timeout waits forever
+func (n *NodeGroupDrainer) SetPDBTimeout(timeout time.Duration, action PDBTimeoutAction) {
+	n.pdbTimeout = timeout
+	n.pdbTimeoutAction = action
 }
 
 // Drain drains a nodegroup
@@ -104,12 +195,14 @@ func (n *NodeGroupDrainer) Drain() error {
 	// or any other changes in the ASG
 	timer := time.NewTimer(n.waitTimeout)
 	defer timer.Stop()
+	retry := time.NewTimer(0)
+	defer retry.Stop()
 
 	for {
 		select {
 		case <-timer.C:
-			return fmt.Errorf("timed out (after %s) waiting for nodegroup %q to be drained", n.waitTimeout, n.ng.NameString())
-		default:
+			return fmt.Errorf("timed out (after %s) waiting for nodegroup %q to be drained%s", n.waitTimeout, n.ng.NameString(), n.blockedEvictionsMessage())
+		case <-retry.C:
 			nodes, err := n.clientSet.CoreV1().Nodes().List(context.TODO(), listOptions)
 			if err != nil {
 				return err
@@ -132,22 +225,65 @@ func (n *NodeGroupDrainer) Drain() error {
 			logger.Debug("already drained: %v", drainedNodes.List())
 			logger.Debug("will drain: %v", newPendingNodes.List())
 
-			for _, node := range newPendingNodes.List() {
-				pending, err := n.evictPods(node)
-				if err != nil {
-					logger.Warning("pod eviction error (%q) on node %s", err, node)
-					time.Sleep(retryDelay)
-					continue
-				}
-				logger.Debug("%d pods to be evicted from %s", pending, node)
-				if pending == 0 {
-					drainedNodes.Insert(node)
-				}
+			drained, err := n.drainNodes(newPendingNodes.List())
+			if err != nil {
+				return err
+			}
+			drainedNodes.Insert(drained...)
+			if len(drained) < newPendingNodes.Len() {
+				retry.Reset(retryDelay)
+			} else {
+				// look for nodes added to the nodegroup in the meantime straight away
+				retry.Reset(0)
 			}
 		}
 	}
 }
 
+// DrainNodes cordons and drains the given nodes of the nodegroup, so that they
+// can be replaced; nodes that no longer exist are ignored
+func (n *NodeGroupDrainer) DrainNodes(nodeNames []string) error {
+	if err := n.evictor.CanUseEvictions(); err != nil {
+		return errors.Wrap(err, "checking if cluster implements policy API")
+	}
+
+	pendingNodes := sets.NewString(nodeNames...)
+	nodes := &corev1.NodeList{}
+	for _, name := range pendingNodes.List() {
+		node, err := n.clientSet.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			pendingNodes.Delete(name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		nodes.Items = append(nodes.Items, *node)
+	}
+	n.toggleCordon(true, nodes)
+
+	timer := time.NewTimer(n.waitTimeout)
+	defer timer.Stop()
+	retry := time.NewTimer(0)
+	defer retry.Stop()
+
+	for pendingNodes.Len() > 0 {
+		select {
+		case <-timer.C:
+			return fmt.Errorf("timed out (after %s) waiting for nodes %v to be drained%s", n.waitTimeout, pendingNodes.List(), n.blockedEvictionsMessage())
+		case <-retry.C:
+			drained, err := n.drainNodes(pendingNodes.List())
+			if err != nil {
+				return err
+			}
+			pendingNodes.Delete(drained...)
+			retry.Reset(retryDelay)
+		}
+	}
+	logger.Success("drained nodes: %v", nodeNames)
+	return nil
+}
+
 func (n *NodeGroupDrainer) toggleCordon(cordon bool, nodes *corev1.NodeList) {
 	for _, node := range nodes.Items {
 		c := NewCordonHelper(&node, cordon)
@@ -167,7 +303,72 @@ func (n *NodeGroupDrainer) toggleCordon(cordon bool, nodes *corev1.NodeList) {
 
 }
 
-func (n *NodeGroupDrainer) evictPods(node string) (int, error) {
+// drainNodes evicts the pods of the given nodes, draining up to parallelism nodes at
+// once, and returns the nodes that have no pods left to evict; it returns an error
+// if a node has been draining for longer than the node timeout
+func (n *NodeGroupDrainer) drainNodes(nodes []string) ([]string, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		drained  []string
+		drainErr error
+	)
+	pdbs := n.newPodDisruptionBudgets()
+	sem := make(chan struct{}, n.parallelism)
+	for _, node := range nodes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(node string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			ok, err := n.drainNode(node, pdbs)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				drainErr = err
+			} else if ok {
+				drained = append(drained, node)
+			}
+		}(node)
+	}
+	wg.Wait()
+	return drained, drainErr
+}
+
+// drainNode evicts the pods of a node once, and returns whether the node has no pods
+// left to evict; when the drain policy requires it, it then waits for the controllers
+// of the evicted pods to be Ready again. Retryable errors are logged and leave the node pending
+func (n *NodeGroupDrainer) drainNode(node string, pdbs *podDisruptionBudgets) (bool, error) {
+	started := n.nodeDrainStarts.start(node)
+	if n.nodeTimeout > 0 && time.Since(started) > n.nodeTimeout {
+		return false, fmt.Errorf("timed out (after %s) waiting for node %q to be drained%s", n.nodeTimeout, node, n.blockedEvictionsMessage())
+	}
+
+	pending, err := n.evictPods(node, pdbs)
+	if err != nil {
+		logger.Warning("pod eviction error (%q) on node %s", err, node)
+		return false, nil
+	}
+	logger.Debug("%d pods to be evicted from %s", pending, node)
+	if pending > 0 {
+		return false, nil
+	}
+
+	if n.waitForReplacements {
+		timeout := n.waitTimeout
+		if n.nodeTimeout > 0 {
+			timeout = n.nodeTimeout - time.Since(started)
+		}
+		if err := n.waitForReplacementsOf(node, timeout); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (n *NodeGroupDrainer) evictPods(node string, pdbs *podDisruptionBudgets) (int, error) {
 	list, errs := n.evictor.GetPodsForEviction(node)
 	if len(errs) > 0 {
 		return 0, fmt.Errorf("errs: %v", errs) // TODO: improve formatting
@@ -175,17 +376,39 @@ func (n *NodeGroupDrainer) evictPods(node string) (int, error) {
 	if w := list.Warnings(); w != "" {
 		logger.Warning(w)
 	}
-	pods := list.Pods()
-	pending := len(pods)
-	for _, pod := range pods {
+	pending := 0
+	for _, pod := range list.Pods() {
+		if n.blockedEvictions.isSkipped(pod) {
+			continue
+		}
+		pending++
 		// TODO: handle API rate limiter error
-		if err := n.evictor.EvictOrDeletePod(pod); err != nil {
+		err := n.evictor.EvictOrDeletePod(pod)
+		if err == nil {
+			n.blockedEvictions.unblock(pod)
+			n.evictedControllers.add(node, pod)
+		}
+		if apierrors.IsTooManyRequests(err) {
+			err = n.handleBlockedEviction(pod, pdbs)
+			if n.blockedEvictions.isSkipped(pod) {
+				pending--
+			}
+		}
+		if err != nil {
 			return pending, errors.Wrapf(err, "error evicting pod: %s/%s", pod.Namespace, pod.Name)
 		}
 	}
 	return pending, nil
 }
 
+func (n *NodeGroupDrainer) blockedEvictionsMessage() string {
+	blocked := n.blockedEvictions.list()
+	if len(blocked) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("; evictions blocked by PodDisruptionBudgets: %v", blocked)
+}
+
 func cordonStatus(desired bool) string {
 	if desired {
 		return "cordon"
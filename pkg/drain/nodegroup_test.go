@@ -3,9 +3,14 @@ package drain_test
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/weaveworks/eksctl/pkg/drain/evictor"
 
@@ -16,6 +21,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	. "github.com/onsi/ginkgo"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/drain"
 	"github.com/weaveworks/eksctl/pkg/eks/mocks"
 	"k8s.io/client-go/kubernetes/fake"
@@ -35,6 +41,7 @@ var _ = Describe("Drain", func() {
 		mockNG.Mock.On("ListOptions").Return(metav1.ListOptions{})
 		fakeClientSet = fake.NewSimpleClientset()
 		fakeEvictor = new(fakes.FakeEvictor)
+		drain.SetRetryDelay(10 * time.Millisecond)
 	})
 
 	When("all nodes drain successfully", func() {
@@ -73,7 +80,7 @@ var _ = Describe("Drain", func() {
 		})
 
 		It("does not error", func() {
-			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, false)
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, false, 1)
 			nodeGroupDrainer.SetDrainer(fakeEvictor)
 
 			err := nodeGroupDrainer.Drain()
@@ -117,7 +124,7 @@ var _ = Describe("Drain", func() {
 		})
 
 		It("times out and errors", func() {
-			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*2, time.Second, false, false)
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*2, time.Second, false, false, 1)
 			nodeGroupDrainer.SetDrainer(fakeEvictor)
 
 			err := nodeGroupDrainer.Drain()
@@ -131,7 +138,7 @@ var _ = Describe("Drain", func() {
 		})
 
 		It("errors", func() {
-			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second, time.Second, false, false)
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second, time.Second, false, false, 1)
 			nodeGroupDrainer.SetDrainer(fakeEvictor)
 
 			err := nodeGroupDrainer.Drain()
@@ -175,7 +182,7 @@ var _ = Describe("Drain", func() {
 		})
 
 		It("does not error", func() {
-			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, true)
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, true, 1)
 			nodeGroupDrainer.SetDrainer(fakeEvictor)
 
 			err := nodeGroupDrainer.Drain()
@@ -205,7 +212,7 @@ var _ = Describe("Drain", func() {
 		})
 
 		It("uncordons all the nodes", func() {
-			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, true, false)
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, true, false, 1)
 			nodeGroupDrainer.SetDrainer(fakeEvictor)
 
 			err := nodeGroupDrainer.Drain()
@@ -219,4 +226,354 @@ var _ = Describe("Drain", func() {
 			Expect(fakeEvictor.EvictOrDeletePodCallCount()).To(BeZero())
 		})
 	})
+
+	When("draining some nodes of the nodegroup", func() {
+		BeforeEach(func() {
+			for _, name := range []string{"node-1", "node-2"} {
+				_, err := fakeClientSet.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+				}, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			fakeEvictor.GetPodsForEvictionReturns(&evictor.PodDeleteList{}, nil)
+		})
+
+		It("only cordons and drains the given nodes, ignoring nodes that no longer exist", func() {
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, false, 1)
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+
+			Expect(nodeGroupDrainer.DrainNodes([]string{"node-1", "node-3"})).To(Succeed())
+
+			Expect(fakeEvictor.GetPodsForEvictionCallCount()).To(Equal(1))
+			Expect(fakeEvictor.GetPodsForEvictionArgsForCall(0)).To(Equal("node-1"))
+
+			node, err := fakeClientSet.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(node.Spec.Unschedulable).To(BeTrue())
+			node, err = fakeClientSet.CoreV1().Nodes().Get(context.TODO(), "node-2", metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(node.Spec.Unschedulable).To(BeFalse())
+		})
+	})
+
+	When("draining several nodes in parallel", func() {
+		BeforeEach(func() {
+			for _, name := range []string{"node-1", "node-2", "node-3", "node-4"} {
+				_, err := fakeClientSet.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+				}, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("drains up to parallelism nodes at once", func() {
+			var running, maxRunning int32
+			fakeEvictor.GetPodsForEvictionStub = func(string) (*evictor.PodDeleteList, []error) {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					max := atomic.LoadInt32(&maxRunning)
+					if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+						break
+					}
+				}
+				time.Sleep(50 * time.Millisecond)
+				return &evictor.PodDeleteList{}, nil
+			}
+
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, false, 2)
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+
+			Expect(nodeGroupDrainer.Drain()).To(Succeed())
+			Expect(fakeEvictor.GetPodsForEvictionCallCount()).To(Equal(4))
+			Expect(atomic.LoadInt32(&maxRunning)).To(Equal(int32(2)))
+		})
+	})
+
+	When("a PodDisruptionBudget blocks an eviction", func() {
+		var pod corev1.Pod
+
+		BeforeEach(func() {
+			pod = corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "web-1",
+					Namespace: "default",
+					Labels:    map[string]string{"app": "web"},
+				},
+			}
+			_, err := fakeClientSet.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = fakeClientSet.PolicyV1beta1().PodDisruptionBudgets("default").Create(context.TODO(), &policyv1beta1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-pdb", Namespace: "default"},
+				Spec: policyv1beta1.PodDisruptionBudgetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeEvictor.GetPodsForEvictionStub = func(string) (*evictor.PodDeleteList, []error) {
+				if fakeEvictor.DeletePodCallCount() > 0 {
+					return &evictor.PodDeleteList{}, nil
+				}
+				return &evictor.PodDeleteList{
+					Items: []evictor.PodDelete{{Pod: pod, Status: evictor.PodDeleteStatus{Delete: true}}},
+				}, nil
+			}
+			fakeEvictor.EvictOrDeletePodReturns(apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0))
+		})
+
+		It("reports the budget and the pod when timing out", func() {
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second, time.Second, false, false, 1)
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+
+			err := nodeGroupDrainer.Drain()
+			Expect(err).To(MatchError(`timed out (after 1s) waiting for nodegroup "node-1" to be drained; evictions blocked by PodDisruptionBudgets: [default/web-1 (default/web-pdb)]`))
+			Expect(fakeEvictor.DeletePodCallCount()).To(BeZero())
+		})
+
+		It("deletes the pod once the budget has been blocking for longer than the PDB timeout", func() {
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, false, 1)
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+			nodeGroupDrainer.SetPDBTimeout(10*time.Millisecond, drain.PDBTimeoutActionDelete)
+
+			Expect(nodeGroupDrainer.Drain()).To(Succeed())
+			Expect(fakeEvictor.DeletePodCallCount()).To(Equal(1))
+			Expect(fakeEvictor.DeletePodArgsForCall(0)).To(Equal(pod))
+		})
+
+		It("leaves the pod on the node when skipping blocked pods", func() {
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, false, 1)
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+			nodeGroupDrainer.SetPDBTimeout(10*time.Millisecond, drain.PDBTimeoutActionSkip)
+
+			Expect(nodeGroupDrainer.Drain()).To(Succeed())
+			Expect(fakeEvictor.DeletePodCallCount()).To(BeZero())
+		})
+
+		It("waits before retrying blocked evictions and lists the budgets once per pass", func() {
+			pod2 := pod
+			pod2.Name = "web-2"
+			fakeEvictor.GetPodsForEvictionReturns(&evictor.PodDeleteList{
+				Items: []evictor.PodDelete{
+					{Pod: pod, Status: evictor.PodDeleteStatus{Delete: true}},
+					{Pod: pod2, Status: evictor.PodDeleteStatus{Delete: true}},
+				},
+			}, nil)
+			fakeEvictor.GetPodsForEvictionStub = nil
+			drain.SetRetryDelay(100 * time.Millisecond)
+
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second, time.Second, false, false, 1)
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+
+			Expect(nodeGroupDrainer.Drain()).To(MatchError(ContainSubstring("timed out (after 1s)")))
+			passes := fakeEvictor.GetPodsForEvictionCallCount()
+			Expect(passes).To(BeNumerically("<=", 11))
+
+			pdbLists := 0
+			for _, action := range fakeClientSet.Actions() {
+				if action.GetVerb() == "list" && action.GetResource().Resource == "poddisruptionbudgets" {
+					pdbLists++
+				}
+			}
+			Expect(pdbLists).To(Equal(passes))
+		})
+
+		It("never deletes pods the drain policy protects", func() {
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second, time.Second, false, false, 1)
+			Expect(nodeGroupDrainer.SetDrainPolicy(&api.DrainPolicy{NeverForceDelete: []string{"app=web"}})).To(Succeed())
+			fakeEvictor.NeverDeleteReturns(true)
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+			nodeGroupDrainer.SetPDBTimeout(10*time.Millisecond, drain.PDBTimeoutActionDelete)
+
+			err := nodeGroupDrainer.Drain()
+			Expect(err).To(MatchError(ContainSubstring("evictions blocked by PodDisruptionBudgets: [default/web-1 (default/web-pdb)]")))
+			Expect(fakeEvictor.DeletePodCallCount()).To(BeZero())
+		})
+	})
+
+	When("a drain policy is set", func() {
+		var pod corev1.Pod
+
+		BeforeEach(func() {
+			controller := true
+			pod = corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "web-1",
+					Namespace:       "default",
+					OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web", Controller: &controller}},
+				},
+			}
+			_, err := fakeClientSet.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeEvictor.GetPodsForEvictionReturnsOnCall(0, &evictor.PodDeleteList{
+				Items: []evictor.PodDelete{{Pod: pod, Status: evictor.PodDeleteStatus{Delete: true}}},
+			}, nil)
+			fakeEvictor.GetPodsForEvictionReturns(&evictor.PodDeleteList{}, nil)
+		})
+
+		createReplicaSet := func(replicas, readyReplicas int32) {
+			_, err := fakeClientSet.AppsV1().ReplicaSets("default").Create(context.TODO(), &appsv1.ReplicaSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+				Status:     appsv1.ReplicaSetStatus{ReadyReplicas: readyReplicas},
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("fails once a node has been draining for longer than the node timeout", func() {
+			fakeEvictor.GetPodsForEvictionReturns(&evictor.PodDeleteList{
+				Items: []evictor.PodDelete{{Pod: pod, Status: evictor.PodDeleteStatus{Delete: true}}},
+			}, nil)
+
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, false, 1)
+			Expect(nodeGroupDrainer.SetDrainPolicy(&api.DrainPolicy{NodeTimeoutSeconds: aws.Int(1)})).To(Succeed())
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+
+			Expect(nodeGroupDrainer.Drain()).To(MatchError(`timed out (after 1s) waiting for node "node-1" to be drained`))
+		})
+
+		It("waits for the controllers of evicted pods to be Ready", func() {
+			createReplicaSet(2, 2)
+
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, false, 1)
+			Expect(nodeGroupDrainer.SetDrainPolicy(&api.DrainPolicy{WaitForReplacements: api.Enabled()})).To(Succeed())
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+
+			Expect(nodeGroupDrainer.Drain()).To(Succeed())
+			Expect(fakeEvictor.EvictOrDeletePodCallCount()).To(Equal(1))
+		})
+
+		It("fails when the controllers of evicted pods do not become Ready in time", func() {
+			createReplicaSet(2, 1)
+
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, false, 1)
+			Expect(nodeGroupDrainer.SetDrainPolicy(&api.DrainPolicy{
+				NodeTimeoutSeconds:  aws.Int(1),
+				WaitForReplacements: api.Enabled(),
+			})).To(Succeed())
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+
+			Expect(nodeGroupDrainer.Drain()).To(MatchError(ContainSubstring(`waiting for the replacements of the pods evicted from node "node-1" to become Ready`)))
+		})
+
+		It("rejects invalid selectors", func() {
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, false, 1)
+			Expect(nodeGroupDrainer.SetDrainPolicy(&api.DrainPolicy{NeverForceDelete: []string{"=web"}})).To(MatchError(ContainSubstring(`parsing drain policy selector "=web"`)))
+		})
+	})
+
+	When("running a dry run", func() {
+		controlledBy := func(kind, name string) []metav1.OwnerReference {
+			controller := true
+			return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+		}
+
+		BeforeEach(func() {
+			_, err := fakeClientSet.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = fakeClientSet.PolicyV1beta1().PodDisruptionBudgets("default").Create(context.TODO(), &policyv1beta1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-pdb", Namespace: "default"},
+				Spec: policyv1beta1.PodDisruptionBudgetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+				Status: policyv1beta1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeEvictor.GetPodsForEvictionReturns(&evictor.PodDeleteList{
+				Items: []evictor.PodDelete{
+					{
+						Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+							Name: "web-1", Namespace: "default", Labels: map[string]string{"app": "web"}, OwnerReferences: controlledBy("ReplicaSet", "web"),
+						}},
+						Status: evictor.PodDeleteStatus{Delete: true, Reason: "Okay"},
+					},
+					{
+						Pod: corev1.Pod{
+							ObjectMeta: metav1.ObjectMeta{Name: "bare", Namespace: "default"},
+							Spec: corev1.PodSpec{Volumes: []corev1.Volume{
+								{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+							}},
+						},
+						Status: evictor.PodDeleteStatus{Delete: true, Reason: "Warning", Message: "deleting Pods not managed by ReplicationController, ReplicaSet, Job, DaemonSet or StatefulSet"},
+					},
+					{
+						Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+							Name: "aws-node-abcde", Namespace: "kube-system", OwnerReferences: controlledBy("DaemonSet", "aws-node"),
+						}},
+						Status: evictor.PodDeleteStatus{Delete: false, Reason: "Warning", Message: "ignoring DaemonSet-managed Pods"},
+					},
+					{
+						Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+							Name: "fluentd-abcde", Namespace: "logging", OwnerReferences: controlledBy("DaemonSet", "fluentd"),
+						}},
+						Status: evictor.PodDeleteStatus{Delete: false, Reason: "Warning", Message: "ignoring DaemonSet-managed Pods"},
+					},
+					{
+						Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+							Name: "static", Namespace: "kube-system", Annotations: map[string]string{corev1.MirrorPodAnnotationKey: "mirror"},
+						}},
+						Status: evictor.PodDeleteStatus{Delete: false, Reason: "Skip"},
+					},
+					{
+						Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+							Name: "pinned", Namespace: "default", OwnerReferences: controlledBy("ReplicaSet", "pinned"),
+						}},
+						Status: evictor.PodDeleteStatus{Delete: false, Reason: "Error", Message: "cannot be drained due to annotation pod.alpha.kubernetes.io/drain=never"},
+					},
+				},
+			}, nil)
+		})
+
+		It("reports what would be done with each pod without draining", func() {
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, false, 1)
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+
+			reports, err := nodeGroupDrainer.DryRun()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports).To(Equal([]drain.PodDrainReport{
+				{NodeGroup: "node-1", Node: nodeName, Namespace: "default", Pod: "web-1", Action: drain.PodActionEvict, BlockingPodDisruptionBudget: "default/web-pdb"},
+				{NodeGroup: "node-1", Node: nodeName, Namespace: "default", Pod: "bare", Action: drain.PodActionEvict, LocalStorage: true, NoController: true},
+				{NodeGroup: "node-1", Node: nodeName, Namespace: "kube-system", Pod: "aws-node-abcde", Action: drain.PodActionSkip, Reason: "DaemonSet aws-node is always ignored"},
+				{NodeGroup: "node-1", Node: nodeName, Namespace: "logging", Pod: "fluentd-abcde", Action: drain.PodActionSkip, Reason: "managed by DaemonSet fluentd"},
+				{NodeGroup: "node-1", Node: nodeName, Namespace: "kube-system", Pod: "static", Action: drain.PodActionSkip, Reason: "mirror pod", NoController: true},
+				{NodeGroup: "node-1", Node: nodeName, Namespace: "default", Pod: "pinned", Action: drain.PodActionFail, Reason: "cannot be drained due to annotation pod.alpha.kubernetes.io/drain=never"},
+			}))
+
+			Expect(fakeEvictor.EvictOrDeletePodCallCount()).To(BeZero())
+			node, err := fakeClientSet.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(node.Spec.Unschedulable).To(BeFalse())
+		})
+
+		It("reports DaemonSets ignored by the drain policy", func() {
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, false, 1)
+			Expect(nodeGroupDrainer.SetDrainPolicy(&api.DrainPolicy{
+				IgnoreDaemonSets: []api.DrainPolicyDaemonSet{{Name: "fluentd"}},
+			})).To(Succeed())
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+
+			reports, err := nodeGroupDrainer.DryRun()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[3].Pod).To(Equal("fluentd-abcde"))
+			Expect(reports[3].Reason).To(Equal("DaemonSet fluentd is ignored by the drain policy"))
+		})
+
+		It("does not report blocking PodDisruptionBudgets when eviction is disabled", func() {
+			nodeGroupDrainer := drain.NewNodeGroupDrainer(fakeClientSet, &mockNG, time.Second*10, time.Second, false, true, 1)
+			nodeGroupDrainer.SetDrainer(fakeEvictor)
+
+			reports, err := nodeGroupDrainer.DryRun()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports[0].Pod).To(Equal("web-1"))
+			Expect(reports[0].BlockingPodDisruptionBudget).To(BeEmpty())
+		})
+	})
 })
@@ -0,0 +1,202 @@
+package drain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// blockedEvictions keeps track of the pods whose eviction is blocked by a PodDisruptionBudget,
+// and of how long each budget has been blocking evictions
+type blockedEvictions struct {
+	mu sync.Mutex
+	// pods maps blocked pods to the budget blocking them
+	pods map[string]string
+	// since maps budgets to the time they first blocked an eviction
+	since     map[string]time.Time
+	skipped   map[string]bool
+	protected map[string]bool
+}
+
+func newBlockedEvictions() *blockedEvictions {
+	return &blockedEvictions{
+		pods:      map[string]string{},
+		since:     map[string]time.Time{},
+		skipped:   map[string]bool{},
+		protected: map[string]bool{},
+	}
+}
+
+// block records that the eviction of a pod is blocked by a budget, returning how long the
+// budget has been blocking evictions and whether the pod was not known to be blocked yet
+func (b *blockedEvictions) block(pod corev1.Pod, pdb string) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := podKey(pod)
+	_, known := b.pods[key]
+	b.pods[key] = pdb
+	since, ok := b.since[pdb]
+	if !ok {
+		since = time.Now()
+		b.since[pdb] = since
+	}
+	return time.Since(since), !known
+}
+
+// unblock records that a pod is no longer blocked; a budget that no longer blocks
+// any pod has its blocking time reset
+func (b *blockedEvictions) unblock(pod corev1.Pod) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.unblockLocked(podKey(pod))
+}
+
+func (b *blockedEvictions) skip(pod corev1.Pod) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := podKey(pod)
+	b.unblockLocked(key)
+	b.skipped[key] = true
+}
+
+func (b *blockedEvictions) unblockLocked(key string) {
+	pdb, ok := b.pods[key]
+	if !ok {
+		return
+	}
+	delete(b.pods, key)
+	for _, other := range b.pods {
+		if other == pdb {
+			return
+		}
+	}
+	delete(b.since, pdb)
+}
+
+// protect records that a blocked pod cannot be deleted, returning whether it was not known yet
+func (b *blockedEvictions) protect(pod corev1.Pod) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := podKey(pod)
+	if b.protected[key] {
+		return false
+	}
+	b.protected[key] = true
+	return true
+}
+
+func (b *blockedEvictions) isSkipped(pod corev1.Pod) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.skipped[podKey(pod)]
+}
+
+// list returns the blocked pods along with the budget blocking them
+func (b *blockedEvictions) list() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var blocked []string
+	for pod, pdb := range b.pods {
+		blocked = append(blocked, fmt.Sprintf("%s (%s)", pod, pdb))
+	}
+	sort.Strings(blocked)
+	return blocked
+}
+
+// handleBlockedEviction reports a pod whose eviction was refused because of a PodDisruptionBudget,
+// and deletes or skips it once the budget has been blocking evictions for longer than the PDB timeout
+func (n *NodeGroupDrainer) handleBlockedEviction(pod corev1.Pod, pdbs *podDisruptionBudgets) error {
+	budget, err := pdbs.forPod(pod)
+	if err != nil {
+		return errors.Wrapf(err, "finding the PodDisruptionBudget blocking the eviction of pod %s", podKey(pod))
+	}
+	pdb := "<unknown>"
+	if budget != nil {
+		pdb = fmt.Sprintf("%s/%s", budget.Namespace, budget.Name)
+	}
+
+	blockedFor, newlyBlocked := n.blockedEvictions.block(pod, pdb)
+	if newlyBlocked {
+		if budget != nil {
+			logger.Warning("eviction of pod %s is blocked by PodDisruptionBudget %s (%d disruptions allowed)", podKey(pod), pdb, budget.Status.DisruptionsAllowed)
+		} else {
+			logger.Warning("eviction of pod %s is blocked by a PodDisruptionBudget", podKey(pod))
+		}
+	}
+	if n.pdbTimeout == 0 || blockedFor < n.pdbTimeout {
+		return nil
+	}
+
+	if n.pdbTimeoutAction == PDBTimeoutActionSkip {
+		logger.Warning("PodDisruptionBudget %s has been blocking evictions for more than %s, leaving pod %s on its node", pdb, n.pdbTimeout, podKey(pod))
+		n.blockedEvictions.skip(pod)
+		return nil
+	}
+	if n.evictor.NeverDelete(pod) {
+		if n.blockedEvictions.protect(pod) {
+			logger.Warning("PodDisruptionBudget %s has been blocking evictions for more than %s, but the drain policy forbids deleting pod %s, waiting for it to be evicted", pdb, n.pdbTimeout, podKey(pod))
+		}
+		return nil
+	}
+	logger.Warning("PodDisruptionBudget %s has been blocking evictions for more than %s, deleting pod %s", pdb, n.pdbTimeout, podKey(pod))
+	if err := n.evictor.DeletePod(pod); err != nil {
+		return err
+	}
+	n.blockedEvictions.unblock(pod)
+	return nil
+}
+
+// podDisruptionBudgets lists the PodDisruptionBudgets of the cluster the first time they are
+// needed, so that they are fetched once per pass over the nodes rather than once per pod
+type podDisruptionBudgets struct {
+	clientSet kubernetes.Interface
+	mu        sync.Mutex
+	items     []policyv1beta1.PodDisruptionBudget
+	listed    bool
+}
+
+func (n *NodeGroupDrainer) newPodDisruptionBudgets() *podDisruptionBudgets {
+	return &podDisruptionBudgets{clientSet: n.clientSet}
+}
+
+// forPod returns the PodDisruptionBudget selecting a pod, if any
+func (p *podDisruptionBudgets) forPod(pod corev1.Pod) (*policyv1beta1.PodDisruptionBudget, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.listed {
+		pdbs, err := p.clientSet.PolicyV1beta1().PodDisruptionBudgets(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		p.items = pdbs.Items
+		p.listed = true
+	}
+	for i, pdb := range p.items {
+		if pdb.Namespace == pod.Namespace && matchesPod(pdb, pod) {
+			return &p.items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func matchesPod(pdb policyv1beta1.PodDisruptionBudget, pod corev1.Pod) bool {
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil || selector.Empty() {
+		return false
+	}
+	return selector.Matches(labels.Set(pod.Labels))
+}
+
+func podKey(pod corev1.Pod) string {
+	return fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+}
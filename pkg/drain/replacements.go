@@ -0,0 +1,147 @@
+package drain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// replacementsPollInterval is how often the controllers of evicted pods are checked for readiness
+const replacementsPollInterval = 5 * time.Second
+
+// controller identifies the controller of an evicted pod
+type controller struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// evictedControllers keeps track of the controllers of the pods evicted from each node
+type evictedControllers struct {
+	mu          sync.Mutex
+	controllers map[string]map[controller]struct{}
+}
+
+func newEvictedControllers() *evictedControllers {
+	return &evictedControllers{
+		controllers: map[string]map[controller]struct{}{},
+	}
+}
+
+func (e *evictedControllers) add(node string, pod corev1.Pod) {
+	ref := metav1.GetControllerOf(&pod)
+	if ref == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.controllers[node] == nil {
+		e.controllers[node] = map[controller]struct{}{}
+	}
+	e.controllers[node][controller{kind: ref.Kind, namespace: pod.Namespace, name: ref.Name}] = struct{}{}
+}
+
+func (e *evictedControllers) list(node string) []controller {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var controllers []controller
+	for c := range e.controllers[node] {
+		controllers = append(controllers, c)
+	}
+	return controllers
+}
+
+// nodeDrainStarts keeps track of when each node started being drained
+type nodeDrainStarts struct {
+	mu     sync.Mutex
+	starts map[string]time.Time
+}
+
+func newNodeDrainStarts() *nodeDrainStarts {
+	return &nodeDrainStarts{
+		starts: map[string]time.Time{},
+	}
+}
+
+// start returns when the node started being drained, recording the current time the first time it is called
+func (s *nodeDrainStarts) start(node string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	started, ok := s.starts[node]
+	if !ok {
+		started = time.Now()
+		s.starts[node] = started
+	}
+	return started
+}
+
+// waitForReplacementsOf waits for the controllers of the pods evicted from the node to have
+// all their replicas Ready
+func (n *NodeGroupDrainer) waitForReplacementsOf(node string, timeout time.Duration) error {
+	controllers := n.evictedControllers.list(node)
+	if len(controllers) == 0 {
+		return nil
+	}
+	logger.Info("waiting for the replacements of the pods evicted from node %q to become Ready", node)
+	err := wait.PollImmediate(replacementsPollInterval, timeout, func() (bool, error) {
+		for _, c := range controllers {
+			ready, err := n.isControllerReady(c)
+			if err != nil || !ready {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return errors.Errorf("timed out (after %s) waiting for the replacements of the pods evicted from node %q to become Ready", timeout, node)
+	}
+	return err
+}
+
+// isControllerReady returns whether all the replicas of the controller are Ready; controllers
+// that do not keep a number of replicas running, and deleted controllers, are always Ready
+func (n *NodeGroupDrainer) isControllerReady(c controller) (bool, error) {
+	var (
+		replicas      *int32
+		readyReplicas int32
+		err           error
+	)
+	switch c.kind {
+	case "ReplicaSet":
+		rs, getErr := n.clientSet.AppsV1().ReplicaSets(c.namespace).Get(context.TODO(), c.name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			replicas, readyReplicas = rs.Spec.Replicas, rs.Status.ReadyReplicas
+		}
+	case "StatefulSet":
+		sts, getErr := n.clientSet.AppsV1().StatefulSets(c.namespace).Get(context.TODO(), c.name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			replicas, readyReplicas = sts.Spec.Replicas, sts.Status.ReadyReplicas
+		}
+	case "ReplicationController":
+		rc, getErr := n.clientSet.CoreV1().ReplicationControllers(c.namespace).Get(context.TODO(), c.name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			replicas, readyReplicas = rc.Spec.Replicas, rc.Status.ReadyReplicas
+		}
+	default:
+		return true, nil
+	}
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	desired := int32(1)
+	if replicas != nil {
+		desired = *replicas
+	}
+	return readyReplicas >= desired, nil
+}
@@ -0,0 +1,129 @@
+package drain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/weaveworks/eksctl/pkg/drain/evictor"
+)
+
+// PodAction is what draining a node would do with one of its pods
+type PodAction string
+
+const (
+	// PodActionEvict means the pod would be evicted
+	PodActionEvict PodAction = "evict"
+	// PodActionSkip means the pod would be left on the node
+	PodActionSkip PodAction = "skip"
+	// PodActionFail means the pod cannot be drained, and would make the drain fail
+	PodActionFail PodAction = "fail"
+)
+
+// PodDrainReport describes what draining a node would do with one of its pods
+type PodDrainReport struct {
+	NodeGroup string    `json:"nodeGroup"`
+	Node      string    `json:"node"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	Action    PodAction `json:"action"`
+	// Reason explains why the pod would be skipped, or why it cannot be drained
+	Reason string `json:"reason,omitempty"`
+	// LocalStorage is set for pods using emptyDir volumes, whose data is lost on eviction
+	LocalStorage bool `json:"localStorage"`
+	// NoController is set for bare pods, which are not recreated once evicted
+	NoController bool `json:"noController"`
+	// BlockingPodDisruptionBudget is the PodDisruptionBudget that would currently block the eviction of the pod
+	BlockingPodDisruptionBudget string `json:"blockingPodDisruptionBudget,omitempty"`
+}
+
+// DryRun reports what draining the nodegroup would do with the pods of each of its nodes,
+// without cordoning the nodes or evicting any pod
+func (n *NodeGroupDrainer) DryRun() ([]PodDrainReport, error) {
+	nodes, err := n.clientSet.CoreV1().Nodes().List(context.TODO(), n.ng.ListOptions())
+	if err != nil {
+		return nil, err
+	}
+	nodeNames := make([]string, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		nodeNames = append(nodeNames, node.Name)
+	}
+	sort.Strings(nodeNames)
+
+	reports := []PodDrainReport{}
+	pdbs := n.newPodDisruptionBudgets()
+	for _, node := range nodeNames {
+		list, errs := n.evictor.GetPodsForEviction(node)
+		if list == nil {
+			// pods that cannot be drained are reported along with the others, only
+			// errors that prevent listing the pods are returned
+			return nil, errors.Errorf("listing pods on node %s: %v", node, errs)
+		}
+		for _, item := range list.Items {
+			report, err := n.podDrainReport(node, item, pdbs)
+			if err != nil {
+				return nil, err
+			}
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
+
+func (n *NodeGroupDrainer) podDrainReport(node string, item evictor.PodDelete, pdbs *podDisruptionBudgets) (PodDrainReport, error) {
+	pod := item.Pod
+	report := PodDrainReport{
+		NodeGroup:    n.ng.NameString(),
+		Node:         node,
+		Namespace:    pod.Namespace,
+		Pod:          pod.Name,
+		LocalStorage: evictor.HasLocalStorage(pod),
+		NoController: metav1.GetControllerOf(&pod) == nil,
+	}
+
+	switch {
+	case item.Status.Delete:
+		report.Action = PodActionEvict
+		if n.disableEviction && !n.evictor.NeverDelete(pod) {
+			break
+		}
+		budget, err := pdbs.forPod(pod)
+		if err != nil {
+			return PodDrainReport{}, errors.Wrapf(err, "finding the PodDisruptionBudget of pod %s", podKey(pod))
+		}
+		if budget != nil && budget.Status.DisruptionsAllowed < 1 {
+			report.BlockingPodDisruptionBudget = fmt.Sprintf("%s/%s", budget.Namespace, budget.Name)
+		}
+	case item.Status.IsError():
+		report.Action = PodActionFail
+		report.Reason = item.Status.Message
+	default:
+		report.Action = PodActionSkip
+		report.Reason = n.skipReason(item)
+	}
+	return report, nil
+}
+
+func (n *NodeGroupDrainer) skipReason(item evictor.PodDelete) string {
+	pod := item.Pod
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return "mirror pod"
+	}
+	if controllerRef := metav1.GetControllerOf(&pod); controllerRef != nil && controllerRef.Kind == appsv1.SchemeGroupVersion.WithKind("DaemonSet").Kind {
+		for i, ignoreDaemonSet := range n.ignoreDaemonSets {
+			if controllerRef.Name == ignoreDaemonSet.Name && (ignoreDaemonSet.Namespace == pod.Namespace || ignoreDaemonSet.Namespace == metav1.NamespaceAll) {
+				if i < len(defaultIgnoreDaemonSets) {
+					return fmt.Sprintf("DaemonSet %s is always ignored", controllerRef.Name)
+				}
+				return fmt.Sprintf("DaemonSet %s is ignored by the drain policy", controllerRef.Name)
+			}
+		}
+		return fmt.Sprintf("managed by DaemonSet %s", controllerRef.Name)
+	}
+	return item.Status.Message
+}
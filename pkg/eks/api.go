@@ -1,6 +1,7 @@
 package eks
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -33,6 +34,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/aws/aws-sdk-go/service/sts"
@@ -69,7 +72,7 @@ type KubeProvider interface {
 	LoadClusterIntoSpecFromStack(spec *api.ClusterConfig, stackManager manager.StackManager) error
 	SupportsManagedNodes(clusterConfig *api.ClusterConfig) (bool, error)
 	ValidateClusterForCompatibility(cfg *api.ClusterConfig, stackManager manager.StackManager) error
-	UpdateAuthConfigMap(nodeGroups []*api.NodeGroup, clientSet kubernetes.Interface) error
+	UpdateAuthConfigMap(ctx context.Context, nodeGroups []*api.NodeGroup, clientSet kubernetes.Interface) error
 	WaitForNodes(clientSet kubernetes.Interface, ng KubeNodeGroup) error
 }
 
@@ -85,6 +88,7 @@ type ProviderServices struct {
 	sts   stsiface.STSAPI
 	ssm   ssmiface.SSMAPI
 	iam   iamiface.IAMAPI
+	s3    s3iface.S3API
 
 	cloudtrail     cloudtrailiface.CloudTrailAPI
 	cloudwatchlogs cloudwatchlogsiface.CloudWatchLogsAPI
@@ -103,6 +107,22 @@ func (p ProviderServices) CloudFormationDisableRollback() bool {
 	return p.spec.CloudFormationDisableRollback
 }
 
+// CloudFormationTemplateBucket returns the S3 bucket that large templates are uploaded to, if set
+func (p ProviderServices) CloudFormationTemplateBucket() string {
+	return p.spec.CloudFormationTemplateBucket
+}
+
+// StreamStackEvents returns whether stack events should be printed while waiting for stacks
+func (p ProviderServices) StreamStackEvents() bool {
+	return !p.spec.Quiet
+}
+
+// MaxParallelStacks returns the maximum number of stacks that are created or deleted at the same time,
+// there is no limit when it is 0
+func (p ProviderServices) MaxParallelStacks() int {
+	return p.spec.MaxParallelStacks
+}
+
 // ASG returns a representation of the AutoScaling API
 func (p ProviderServices) ASG() autoscalingiface.AutoScalingAPI { return p.asg }
 
@@ -127,6 +147,9 @@ func (p ProviderServices) SSM() ssmiface.SSMAPI { return p.ssm }
 // IAM returns a representation of the IAM API
 func (p ProviderServices) IAM() iamiface.IAMAPI { return p.iam }
 
+// S3 returns a representation of the S3 API
+func (p ProviderServices) S3() s3iface.S3API { return p.s3 }
+
 // CloudTrail returns a representation of the CloudTrail API
 func (p ProviderServices) CloudTrail() cloudtrailiface.CloudTrailAPI { return p.cloudtrail }
 
@@ -198,6 +221,7 @@ func New(spec *api.ProviderConfig, clusterSpec *api.ClusterConfig) (*ClusterProv
 	)
 	provider.ssm = ssm.New(s)
 	provider.iam = iam.New(s)
+	provider.s3 = s3.New(s)
 	provider.cloudtrail = cloudtrail.New(s)
 	provider.cloudwatchlogs = cloudwatchlogs.New(s)
 
@@ -295,12 +319,7 @@ func readConfig(configFile string) ([]byte, error) {
 
 // IsSupportedRegion check if given region is supported
 func (c *ClusterProvider) IsSupportedRegion() bool {
-	for _, supportedRegion := range api.SupportedRegions() {
-		if c.Provider.Region() == supportedRegion {
-			return true
-		}
-	}
-	return false
+	return api.IsSupportedRegion(c.Provider.Region())
 }
 
 // GetCredentialsEnv returns the AWS credentials for env usage
@@ -441,6 +460,10 @@ func (c *ClusterProvider) newSession(spec *api.ProviderConfig) *session.Session
 		Fn: request.MakeAddToUserAgentHandler(
 			"eksctl", version.String()),
 	})
+	s.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "eksctlProgressEvents",
+		Fn:   emitEKSUpdate,
+	})
 
 	if spec.Region == "" {
 		if api.IsSetAndNonEmptyString(s.Config.Region) {
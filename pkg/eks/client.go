@@ -25,6 +25,7 @@ import (
 	"github.com/weaveworks/eksctl/pkg/authconfigmap"
 	kubewrapper "github.com/weaveworks/eksctl/pkg/kubernetes"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
 )
 
 // Client stores information about the client config
@@ -130,10 +131,13 @@ func (c *ClusterProvider) ServerVersion(rawClient *kubewrapper.RawClient) (strin
 }
 
 // UpdateAuthConfigMap creates or adds a nodegroup IAM role in the auth ConfigMap for the given nodegroup.
-func (c *ClusterProvider) UpdateAuthConfigMap(nodeGroups []*api.NodeGroup, clientSet kubernetes.Interface) error {
+func (c *ClusterProvider) UpdateAuthConfigMap(ctx context.Context, nodeGroups []*api.NodeGroup, clientSet kubernetes.Interface) error {
 	for _, ng := range nodeGroups {
-		// authorise nodes to join
-		if err := authconfigmap.AddNodeGroup(clientSet, ng); err != nil {
+		// authorise nodes to join, retrying as the API server may not be ready right after creation
+		err := tasks.DoWithRetries(ctx, "updating auth ConfigMap", func() error {
+			return authconfigmap.AddNodeGroup(clientSet, ng)
+		})
+		if err != nil {
 			return err
 		}
 
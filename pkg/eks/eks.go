@@ -275,8 +275,12 @@ func (c *ClusterProvider) NewOpenIDConnectManager(spec *api.ClusterConfig) (*iam
 		return nil, fmt.Errorf("unknown EKS ARN: %q", spec.Status.ARN)
 	}
 
+	tags := sharedTags(c.Status.ClusterInfo.Cluster)
+	if p := spec.Metadata.Protection; p != nil && api.IsEnabled(p.RetainOIDCProvider) {
+		tags[api.RetainTag] = "true"
+	}
 	return iamoidc.NewOpenIDConnectManager(c.Provider.IAM(), parsedARN.AccountID,
-		*c.Status.ClusterInfo.Cluster.Identity.Oidc.Issuer, parsedARN.Partition, sharedTags(c.Status.ClusterInfo.Cluster))
+		*c.Status.ClusterInfo.Cluster.Identity.Oidc.Issuer, parsedARN.Partition, tags)
 }
 
 func sharedTags(cluster *awseks.Cluster) map[string]string {
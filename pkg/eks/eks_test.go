@@ -97,6 +97,11 @@ var _ = Describe("EKS API wrapper", func() {
 						"UPDATE_ROLLBACK_COMPLETE_CLEANUP_IN_PROGRESS",
 						"UPDATE_ROLLBACK_COMPLETE",
 						"REVIEW_IN_PROGRESS",
+						"IMPORT_IN_PROGRESS",
+						"IMPORT_COMPLETE",
+						"IMPORT_ROLLBACK_IN_PROGRESS",
+						"IMPORT_ROLLBACK_FAILED",
+						"IMPORT_ROLLBACK_COMPLETE",
 					}
 
 					logger.Level = 4
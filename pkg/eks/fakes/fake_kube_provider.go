@@ -2,13 +2,13 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
-	kubernetesa "k8s.io/client-go/kubernetes"
 )
 
 type FakeKubeProvider struct {
@@ -63,11 +63,12 @@ type FakeKubeProvider struct {
 		result1 bool
 		result2 error
 	}
-	UpdateAuthConfigMapStub        func([]*v1alpha5.NodeGroup, kubernetesa.Interface) error
+	UpdateAuthConfigMapStub        func(context.Context, []*v1alpha5.NodeGroup, kubernetes.Interface) error
 	updateAuthConfigMapMutex       sync.RWMutex
 	updateAuthConfigMapArgsForCall []struct {
-		arg1 []*v1alpha5.NodeGroup
-		arg2 kubernetesa.Interface
+		arg1 context.Context
+		arg2 []*v1alpha5.NodeGroup
+		arg3 kubernetes.Interface
 	}
 	updateAuthConfigMapReturns struct {
 		result1 error
@@ -87,10 +88,10 @@ type FakeKubeProvider struct {
 	validateClusterForCompatibilityReturnsOnCall map[int]struct {
 		result1 error
 	}
-	WaitForNodesStub        func(kubernetesa.Interface, eks.KubeNodeGroup) error
+	WaitForNodesStub        func(kubernetes.Interface, eks.KubeNodeGroup) error
 	waitForNodesMutex       sync.RWMutex
 	waitForNodesArgsForCall []struct {
-		arg1 kubernetesa.Interface
+		arg1 kubernetes.Interface
 		arg2 eks.KubeNodeGroup
 	}
 	waitForNodesReturns struct {
@@ -357,24 +358,25 @@ func (fake *FakeKubeProvider) SupportsManagedNodesReturnsOnCall(i int, result1 b
 	}{result1, result2}
 }
 
-func (fake *FakeKubeProvider) UpdateAuthConfigMap(arg1 []*v1alpha5.NodeGroup, arg2 kubernetesa.Interface) error {
-	var arg1Copy []*v1alpha5.NodeGroup
-	if arg1 != nil {
-		arg1Copy = make([]*v1alpha5.NodeGroup, len(arg1))
-		copy(arg1Copy, arg1)
+func (fake *FakeKubeProvider) UpdateAuthConfigMap(arg1 context.Context, arg2 []*v1alpha5.NodeGroup, arg3 kubernetes.Interface) error {
+	var arg2Copy []*v1alpha5.NodeGroup
+	if arg2 != nil {
+		arg2Copy = make([]*v1alpha5.NodeGroup, len(arg2))
+		copy(arg2Copy, arg2)
 	}
 	fake.updateAuthConfigMapMutex.Lock()
 	ret, specificReturn := fake.updateAuthConfigMapReturnsOnCall[len(fake.updateAuthConfigMapArgsForCall)]
 	fake.updateAuthConfigMapArgsForCall = append(fake.updateAuthConfigMapArgsForCall, struct {
-		arg1 []*v1alpha5.NodeGroup
-		arg2 kubernetesa.Interface
-	}{arg1Copy, arg2})
+		arg1 context.Context
+		arg2 []*v1alpha5.NodeGroup
+		arg3 kubernetes.Interface
+	}{arg1, arg2Copy, arg3})
 	stub := fake.UpdateAuthConfigMapStub
 	fakeReturns := fake.updateAuthConfigMapReturns
-	fake.recordInvocation("UpdateAuthConfigMap", []interface{}{arg1Copy, arg2})
+	fake.recordInvocation("UpdateAuthConfigMap", []interface{}{arg1, arg2Copy, arg3})
 	fake.updateAuthConfigMapMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -388,17 +390,17 @@ func (fake *FakeKubeProvider) UpdateAuthConfigMapCallCount() int {
 	return len(fake.updateAuthConfigMapArgsForCall)
 }
 
-func (fake *FakeKubeProvider) UpdateAuthConfigMapCalls(stub func([]*v1alpha5.NodeGroup, kubernetesa.Interface) error) {
+func (fake *FakeKubeProvider) UpdateAuthConfigMapCalls(stub func(context.Context, []*v1alpha5.NodeGroup, kubernetes.Interface) error) {
 	fake.updateAuthConfigMapMutex.Lock()
 	defer fake.updateAuthConfigMapMutex.Unlock()
 	fake.UpdateAuthConfigMapStub = stub
 }
 
-func (fake *FakeKubeProvider) UpdateAuthConfigMapArgsForCall(i int) ([]*v1alpha5.NodeGroup, kubernetesa.Interface) {
+func (fake *FakeKubeProvider) UpdateAuthConfigMapArgsForCall(i int) (context.Context, []*v1alpha5.NodeGroup, kubernetes.Interface) {
 	fake.updateAuthConfigMapMutex.RLock()
 	defer fake.updateAuthConfigMapMutex.RUnlock()
 	argsForCall := fake.updateAuthConfigMapArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeKubeProvider) UpdateAuthConfigMapReturns(result1 error) {
@@ -486,11 +488,11 @@ func (fake *FakeKubeProvider) ValidateClusterForCompatibilityReturnsOnCall(i int
 	}{result1}
 }
 
-func (fake *FakeKubeProvider) WaitForNodes(arg1 kubernetesa.Interface, arg2 eks.KubeNodeGroup) error {
+func (fake *FakeKubeProvider) WaitForNodes(arg1 kubernetes.Interface, arg2 eks.KubeNodeGroup) error {
 	fake.waitForNodesMutex.Lock()
 	ret, specificReturn := fake.waitForNodesReturnsOnCall[len(fake.waitForNodesArgsForCall)]
 	fake.waitForNodesArgsForCall = append(fake.waitForNodesArgsForCall, struct {
-		arg1 kubernetesa.Interface
+		arg1 kubernetes.Interface
 		arg2 eks.KubeNodeGroup
 	}{arg1, arg2})
 	stub := fake.WaitForNodesStub
@@ -512,13 +514,13 @@ func (fake *FakeKubeProvider) WaitForNodesCallCount() int {
 	return len(fake.waitForNodesArgsForCall)
 }
 
-func (fake *FakeKubeProvider) WaitForNodesCalls(stub func(kubernetesa.Interface, eks.KubeNodeGroup) error) {
+func (fake *FakeKubeProvider) WaitForNodesCalls(stub func(kubernetes.Interface, eks.KubeNodeGroup) error) {
 	fake.waitForNodesMutex.Lock()
 	defer fake.waitForNodesMutex.Unlock()
 	fake.WaitForNodesStub = stub
 }
 
-func (fake *FakeKubeProvider) WaitForNodesArgsForCall(i int) (kubernetesa.Interface, eks.KubeNodeGroup) {
+func (fake *FakeKubeProvider) WaitForNodesArgsForCall(i int) (kubernetes.Interface, eks.KubeNodeGroup) {
 	fake.waitForNodesMutex.RLock()
 	defer fake.waitForNodesMutex.RUnlock()
 	argsForCall := fake.waitForNodesArgsForCall[i]
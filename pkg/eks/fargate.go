@@ -1,6 +1,7 @@
 package eks
 
 import (
+	"context"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/eks"
@@ -31,7 +32,7 @@ type fargateProfilesTask struct {
 
 func (fpt *fargateProfilesTask) Describe() string { return fpt.info }
 
-func (fpt *fargateProfilesTask) Do(errCh chan error) error {
+func (fpt *fargateProfilesTask) Do(_ context.Context, errCh chan error) error {
 	defer close(errCh)
 	if err := DoCreateFargateProfiles(fpt.spec, fpt.manager); err != nil {
 		return err
@@ -27,6 +27,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/request"
+
+	"github.com/weaveworks/eksctl/pkg/utils/progress"
 )
 
 const (
@@ -101,6 +103,13 @@ func (l LoggingRetryer) RetryRules(r *request.Request) time.Duration {
 	}
 
 	logger.Warning("retryable error (%s) from %s - will retry after delay of %v", errorDescription, methodDescription, duration)
+	progress.Emit(progress.Event{
+		Type:            progress.Retry,
+		Operation:       methodDescription,
+		Attempt:         r.RetryCount + 1,
+		DurationSeconds: duration.Seconds(),
+		Error:           errorDescription,
+	})
 
 	return duration
 }
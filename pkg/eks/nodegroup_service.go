@@ -35,7 +35,7 @@ type InstanceSelector interface {
 //counterfeiter:generate -o fakes/fake_nodegroup_initialiser.go . NodeGroupInitialiser
 // NodeGroupInitialiser is an interface that provides helpers for nodegroup creation.
 type NodeGroupInitialiser interface {
-	Normalize(nodePools []api.NodePool, clusterMeta *api.ClusterMeta) error
+	Normalize(nodePools []api.NodePool, clusterConfig *api.ClusterConfig) error
 	ExpandInstanceSelectorOptions(nodePools []api.NodePool, clusterAZs []string) error
 	NewAWSSelectorSession(provider api.ClusterProvider)
 	ValidateLegacySubnetsForNodeGroups(spec *api.ClusterConfig, provider api.ClusterProvider) error
@@ -58,15 +58,26 @@ func NewNodeGroupService(provider api.ClusterProvider, instanceSelector Instance
 	}
 }
 
-const defaultCPUArch = "x86_64"
+const (
+	defaultCPUArch = "x86_64"
+
+	zoneLabel = "topology.kubernetes.io/zone"
+)
 
 // NewAWSSelectorSession returns a new instance of Selector provided an aws session
 func (m *NodeGroupService) NewAWSSelectorSession(provider api.ClusterProvider) {
 	m.instanceSelector = selector.New(provider.Session())
 }
 
-// Normalize normalizes nodegroups
-func (m *NodeGroupService) Normalize(nodePools []api.NodePool, clusterMeta *api.ClusterMeta) error {
+// Normalize normalizes nodegroups, expanding nodegroups with perAvailabilityZone enabled
+// into one nodegroup per availability zone
+func (m *NodeGroupService) Normalize(nodePools []api.NodePool, clusterConfig *api.ClusterConfig) error {
+	nodePools, err := m.expandPerAvailabilityZone(nodePools, clusterConfig)
+	if err != nil {
+		return err
+	}
+
+	clusterMeta := clusterConfig.Metadata
 	for _, np := range nodePools {
 		switch ng := np.(type) {
 		case *api.ManagedNodeGroup:
@@ -115,6 +126,109 @@ func (m *NodeGroupService) Normalize(nodePools []api.NodePool, clusterMeta *api.
 	return nil
 }
 
+// expandPerAvailabilityZone replaces each nodegroup with perAvailabilityZone enabled by a copy of it
+// in each of its availability zones, both in the ClusterConfig and in the returned node pools
+func (m *NodeGroupService) expandPerAvailabilityZone(nodePools []api.NodePool, clusterConfig *api.ClusterConfig) ([]api.NodePool, error) {
+	var expandedNodePools []api.NodePool
+	for _, np := range nodePools {
+		ng := np.BaseNodeGroup()
+		if !api.IsEnabled(ng.PerAvailabilityZone) {
+			expandedNodePools = append(expandedNodePools, np)
+			continue
+		}
+
+		azs := ng.AvailabilityZones
+		if len(azs) == 0 {
+			azs = clusterConfig.AvailabilityZones
+		}
+		if len(azs) == 0 {
+			return nil, errors.Errorf("cannot expand nodegroup %q with perAvailabilityZone enabled as no availability zones are set", ng.Name)
+		}
+
+		subnets := clusterConfig.VPC.Subnets.Public
+		if ng.PrivateNetworking {
+			subnets = clusterConfig.VPC.Subnets.Private
+		}
+
+		var azNodePools []api.NodePool
+		for _, az := range azs {
+			if _, err := vpc.SelectNodeGroupSubnets([]string{az}, nil, subnets, m.Provider.EC2(), clusterConfig.VPC.ID); err != nil {
+				return nil, errors.Wrapf(err, "selecting subnets for nodegroup %q in availability zone %s", ng.Name, az)
+			}
+			azNodePools = append(azNodePools, newAvailabilityZoneNodePool(np, az))
+		}
+		logger.Info("expanded nodegroup %q into %d nodegroups, one per availability zone", ng.Name, len(azNodePools))
+
+		replaceNodePool(clusterConfig, np, azNodePools)
+		expandedNodePools = append(expandedNodePools, azNodePools...)
+	}
+	return expandedNodePools, nil
+}
+
+// newAvailabilityZoneNodePool returns a copy of a nodegroup with perAvailabilityZone enabled, limited to one availability zone
+func newAvailabilityZoneNodePool(np api.NodePool, az string) api.NodePool {
+	var azNodePool api.NodePool
+	switch ng := np.(type) {
+	case *api.NodeGroup:
+		azNodePool = ng.DeepCopy()
+	case *api.ManagedNodeGroup:
+		azNodePool = ng.DeepCopy()
+	}
+
+	ng := azNodePool.BaseNodeGroup()
+	groupName := ng.Name
+	ng.Name = fmt.Sprintf("%s-%s", groupName, az)
+	ng.AvailabilityZones = []string{az}
+	ng.PerAvailabilityZone = nil
+
+	if ng.Labels == nil {
+		ng.Labels = map[string]string{}
+	}
+	if _, ok := ng.Labels[api.NodeGroupNameLabel]; ok {
+		ng.Labels[api.NodeGroupNameLabel] = ng.Name
+	}
+	ng.Labels[zoneLabel] = az
+
+	if ng.Tags == nil {
+		ng.Tags = map[string]string{}
+	}
+	if _, ok := ng.Tags[api.NodeGroupNameTag]; ok {
+		ng.Tags[api.NodeGroupNameTag] = ng.Name
+	}
+	ng.Tags[api.NodeGroupPerAZGroupTag] = groupName
+	return azNodePool
+}
+
+// replaceNodePool replaces a nodegroup of the ClusterConfig by the given nodegroups, keeping their order
+func replaceNodePool(clusterConfig *api.ClusterConfig, np api.NodePool, replacements []api.NodePool) {
+	switch np.(type) {
+	case *api.NodeGroup:
+		var nodeGroups []*api.NodeGroup
+		for _, ng := range clusterConfig.NodeGroups {
+			if ng != np {
+				nodeGroups = append(nodeGroups, ng)
+				continue
+			}
+			for _, r := range replacements {
+				nodeGroups = append(nodeGroups, r.(*api.NodeGroup))
+			}
+		}
+		clusterConfig.NodeGroups = nodeGroups
+	case *api.ManagedNodeGroup:
+		var nodeGroups []*api.ManagedNodeGroup
+		for _, ng := range clusterConfig.ManagedNodeGroups {
+			if ng != np {
+				nodeGroups = append(nodeGroups, ng)
+				continue
+			}
+			for _, r := range replacements {
+				nodeGroups = append(nodeGroups, r.(*api.ManagedNodeGroup))
+			}
+		}
+		clusterConfig.ManagedNodeGroups = nodeGroups
+	}
+}
+
 // ExpandInstanceSelectorOptions sets instance types to instances matched by the instance selector criteria
 func (m *NodeGroupService) ExpandInstanceSelectorOptions(nodePools []api.NodePool, clusterAZs []string) error {
 	instanceTypesMatch := func(a, b []string) bool {
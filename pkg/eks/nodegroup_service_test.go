@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/eks/fakes"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 )
@@ -225,3 +226,74 @@ func tooManyTypes() []string {
 	}
 	return instances
 }
+
+var _ = Describe("Normalize", func() {
+	var (
+		clusterConfig    *api.ClusterConfig
+		nodeGroupService *eks.NodeGroupService
+	)
+
+	newManagedNodeGroup := func(name string, perAvailabilityZone bool) *api.ManagedNodeGroup {
+		ng := api.NewManagedNodeGroup()
+		ng.Name = name
+		ng.PerAvailabilityZone = aws.Bool(perAvailabilityZone)
+		api.SetManagedNodeGroupDefaults(ng, clusterConfig.Metadata)
+		return ng
+	}
+
+	BeforeEach(func() {
+		clusterConfig = api.NewClusterConfig()
+		clusterConfig.Metadata.Name = "test"
+		clusterConfig.Metadata.Version = "1.21"
+		clusterConfig.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+		clusterConfig.VPC.Subnets = &api.ClusterSubnets{
+			Public: api.AZSubnetMappingFromMap(map[string]api.AZSubnetSpec{
+				"us-west-2a": {ID: "subnet-a"},
+				"us-west-2b": {ID: "subnet-b"},
+			}),
+		}
+		nodeGroupService = eks.NewNodeGroupService(mockprovider.NewMockProvider(), nil)
+	})
+
+	It("expands nodegroups with perAvailabilityZone enabled into one nodegroup per availability zone", func() {
+		clusterConfig.ManagedNodeGroups = []*api.ManagedNodeGroup{
+			newManagedNodeGroup("ng", true),
+			newManagedNodeGroup("other", false),
+		}
+
+		Expect(nodeGroupService.Normalize([]api.NodePool{clusterConfig.ManagedNodeGroups[0], clusterConfig.ManagedNodeGroups[1]}, clusterConfig)).To(Succeed())
+
+		Expect(clusterConfig.ManagedNodeGroups).To(HaveLen(3))
+		for i, az := range []string{"us-west-2a", "us-west-2b"} {
+			ng := clusterConfig.ManagedNodeGroups[i]
+			Expect(ng.Name).To(Equal("ng-" + az))
+			Expect(ng.AvailabilityZones).To(Equal([]string{az}))
+			Expect(ng.PerAvailabilityZone).To(BeNil())
+			Expect(ng.Labels).To(HaveKeyWithValue("topology.kubernetes.io/zone", az))
+			Expect(ng.Labels).To(HaveKeyWithValue(api.NodeGroupNameLabel, "ng-"+az))
+			Expect(ng.Tags).To(HaveKeyWithValue(api.NodeGroupNameTag, "ng-"+az))
+			Expect(ng.Tags).To(HaveKeyWithValue(api.NodeGroupPerAZGroupTag, "ng"))
+		}
+		Expect(clusterConfig.ManagedNodeGroups[2].Name).To(Equal("other"))
+	})
+
+	It("uses the availability zones of the nodegroup when set", func() {
+		ng := newManagedNodeGroup("ng", true)
+		ng.AvailabilityZones = []string{"us-west-2b"}
+		clusterConfig.ManagedNodeGroups = []*api.ManagedNodeGroup{ng}
+
+		Expect(nodeGroupService.Normalize([]api.NodePool{ng}, clusterConfig)).To(Succeed())
+
+		Expect(clusterConfig.ManagedNodeGroups).To(HaveLen(1))
+		Expect(clusterConfig.ManagedNodeGroups[0].Name).To(Equal("ng-us-west-2b"))
+	})
+
+	It("fails when an availability zone has no subnet", func() {
+		ng := newManagedNodeGroup("ng", true)
+		ng.AvailabilityZones = []string{"us-west-2c"}
+		clusterConfig.ManagedNodeGroups = []*api.ManagedNodeGroup{ng}
+
+		err := nodeGroupService.Normalize([]api.NodePool{ng}, clusterConfig)
+		Expect(err).To(MatchError(ContainSubstring(`selecting subnets for nodegroup "ng" in availability zone us-west-2c`)))
+	})
+})
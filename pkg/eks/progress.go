@@ -0,0 +1,45 @@
+package eks
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/eks"
+
+	"github.com/weaveworks/eksctl/pkg/utils/progress"
+)
+
+// emitEKSUpdate reports the EKS update returned by requests starting or describing updates,
+// e.g. UpdateClusterConfig or DescribeUpdate
+func emitEKSUpdate(r *request.Request) {
+	if r.Error != nil || r.ClientInfo.ServiceName != eks.ServiceName || !progress.Enabled() {
+		return
+	}
+	values, err := awsutil.ValuesAtPath(r.Data, "Update")
+	if err != nil || len(values) == 0 {
+		return
+	}
+	update, ok := values[0].(*eks.Update)
+	if !ok || update == nil {
+		return
+	}
+
+	e := progress.Event{
+		Type:       progress.EKSUpdate,
+		UpdateID:   aws.StringValue(update.Id),
+		UpdateType: aws.StringValue(update.Type),
+		Status:     aws.StringValue(update.Status),
+	}
+	for _, path := range []string{"ClusterName", "Name"} {
+		if values, err := awsutil.ValuesAtPath(r.Params, path); err == nil && len(values) > 0 {
+			if name, ok := values[0].(*string); ok {
+				e.Cluster = aws.StringValue(name)
+				break
+			}
+		}
+	}
+	if len(update.Errors) > 0 {
+		e.Reason = aws.StringValue(update.Errors[0].ErrorMessage)
+	}
+	progress.Emit(e)
+}
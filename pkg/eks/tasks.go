@@ -31,14 +31,17 @@ import (
 )
 
 type clusterConfigTask struct {
-	info string
-	spec *api.ClusterConfig
-	call func(*api.ClusterConfig) error
+	info          string
+	spec          *api.ClusterConfig
+	call          func(*api.ClusterConfig) error
+	rerunOnResume bool
 }
 
 func (t *clusterConfigTask) Describe() string { return t.info }
 
-func (t *clusterConfigTask) Do(errs chan error) error {
+func (t *clusterConfigTask) RerunOnResume() bool { return t.rerunOnResume }
+
+func (t *clusterConfigTask) Do(_ context.Context, errs chan error) error {
 	err := t.call(t.spec)
 	close(errs)
 	return err
@@ -51,7 +54,7 @@ type WindowsIPAMTask struct {
 }
 
 // Do implements Task.
-func (w *WindowsIPAMTask) Do(errCh chan error) error {
+func (w *WindowsIPAMTask) Do(_ context.Context, errCh chan error) error {
 	defer close(errCh)
 
 	clientset, err := w.ClientsetFunc()
@@ -81,7 +84,7 @@ type VPCControllerTask struct {
 func (v *VPCControllerTask) Describe() string { return v.Info }
 
 // Do implements Task
-func (v *VPCControllerTask) Do(errCh chan error) error {
+func (v *VPCControllerTask) Do(_ context.Context, errCh chan error) error {
 	defer close(errCh)
 	rawClient, err := v.ClusterProvider.NewRawClient(v.ClusterConfig)
 	if err != nil {
@@ -110,6 +113,7 @@ func (v *VPCControllerTask) Do(errCh chan error) error {
 }
 
 type devicePluginTask struct {
+	tasks.TransientErrorRetries
 	kind            string
 	clusterProvider *ClusterProvider
 	spec            *api.ClusterConfig
@@ -119,7 +123,7 @@ type devicePluginTask struct {
 
 func (n *devicePluginTask) Describe() string { return fmt.Sprintf("install %s device plugin", n.kind) }
 
-func (n *devicePluginTask) Do(errCh chan error) error {
+func (n *devicePluginTask) Do(_ context.Context, errCh chan error) error {
 	defer close(errCh)
 	rawClient, err := n.clusterProvider.NewRawClient(n.spec)
 	if err != nil {
@@ -180,6 +184,7 @@ func newEFADevicePluginTask(
 }
 
 type restartDaemonsetTask struct {
+	tasks.TransientErrorRetries
 	name            string
 	namespace       string
 	clusterProvider *ClusterProvider
@@ -190,7 +195,7 @@ func (t *restartDaemonsetTask) Describe() string {
 	return fmt.Sprintf(`restart daemonset "%s/%s"`, t.namespace, t.name)
 }
 
-func (t *restartDaemonsetTask) Do(errCh chan error) error {
+func (t *restartDaemonsetTask) Do(_ context.Context, errCh chan error) error {
 	defer close(errCh)
 	clientSet, err := t.clusterProvider.NewStdClientSet(t.spec)
 	if err != nil {
@@ -216,16 +221,21 @@ func (t *restartDaemonsetTask) Do(errCh chan error) error {
 	return nil
 }
 
-// CreateExtraClusterConfigTasks returns all tasks for updating cluster configuration not depending on the control plane availability
-func (c *ClusterProvider) CreateExtraClusterConfigTasks(cfg *api.ClusterConfig) *tasks.TaskTree {
+// CreateExtraClusterConfigTasks returns all tasks for updating cluster configuration not depending on the control plane availability;
+// the updates of the cluster config are run in order, as EKS only allows one update at a time, and the other tasks are
+// run in parallel once the control plane is ready, e.g. the iamserviceaccounts only wait for the OIDC provider
+func (c *ClusterProvider) CreateExtraClusterConfigTasks(cfg *api.ClusterConfig) (*tasks.TaskTree, error) {
 	newTasks := &tasks.TaskTree{
-		Parallel:  false,
+		Parallel:  true,
 		IsSubTask: true,
 	}
 
-	newTasks.Append(&tasks.GenericTask{
-		Description: "wait for control plane to become ready",
-		Doer: func() error {
+	waitForControlPlane := &clusterConfigTask{
+		info: "wait for control plane to become ready",
+		spec: cfg,
+		// the cluster status is needed by the tasks using the Kubernetes API
+		rerunOnResume: true,
+		call: func(cfg *api.ClusterConfig) error {
 			clientSet, err := c.NewStdClientSet(cfg)
 			if err != nil {
 				return errors.Wrap(err, "error creating Clientset")
@@ -235,10 +245,16 @@ func (c *ClusterProvider) CreateExtraClusterConfigTasks(cfg *api.ClusterConfig)
 			}
 			return c.RefreshClusterStatus(cfg)
 		},
-	})
+	}
+	newTasks.Append(waitForControlPlane)
+
+	clusterUpdates := &tasks.TaskTree{
+		Parallel:  false,
+		IsSubTask: true,
+	}
 
 	if len(cfg.Metadata.Tags) > 0 {
-		newTasks.Append(&clusterConfigTask{
+		clusterUpdates.Append(&clusterConfigTask{
 			info: "tag cluster",
 			spec: cfg,
 			call: c.UpdateClusterTags,
@@ -249,14 +265,14 @@ func (c *ClusterProvider) CreateExtraClusterConfigTasks(cfg *api.ClusterConfig)
 		logger.Info("you can enable it with 'eksctl utils update-cluster-logging --enable-types={SPECIFY-YOUR-LOG-TYPES-HERE (e.g. all)} --region=%s --cluster=%s'", cfg.Metadata.Region, cfg.Metadata.Name)
 
 	} else {
-		newTasks.Append(&clusterConfigTask{
+		clusterUpdates.Append(&clusterConfigTask{
 			info: "update CloudWatch logging configuration",
 			spec: cfg,
 			call: c.UpdateClusterConfigForLogging,
 		})
 
 		if logRetentionDays := cfg.CloudWatch.ClusterLogging.LogRetentionInDays; logRetentionDays != 0 {
-			newTasks.Append(&clusterConfigTask{
+			clusterUpdates.Append(&clusterConfigTask{
 				info: "update CloudWatch log retention",
 				spec: cfg,
 				call: func(clusterConfig *api.ClusterConfig) error {
@@ -275,19 +291,24 @@ func (c *ClusterProvider) CreateExtraClusterConfigTasks(cfg *api.ClusterConfig)
 		}
 	}
 
-	c.maybeAppendTasksForEndpointAccessUpdates(cfg, newTasks)
+	c.maybeAppendTasksForEndpointAccessUpdates(cfg, clusterUpdates)
 
 	if len(cfg.VPC.PublicAccessCIDRs) > 0 {
-		newTasks.Append(&clusterConfigTask{
+		clusterUpdates.Append(&clusterConfigTask{
 			info: "update public access CIDRs",
 			spec: cfg,
 			call: c.UpdatePublicAccessCIDRs,
 		})
 	}
 
+	if len(cfg.IdentityProviders) > 0 {
+		clusterUpdates.Append(identityproviders.NewAssociateProvidersTask(*cfg.Metadata, cfg.IdentityProviders, c.Provider.EKS()))
+	}
+
+	// fargate profiles can't be created while the cluster is being updated
 	if cfg.IsFargateEnabled() {
 		manager := fargate.NewFromProvider(cfg.Metadata.Name, c.Provider, c.NewStackManager(cfg))
-		newTasks.Append(&fargateProfilesTask{
+		clusterUpdates.Append(&fargateProfilesTask{
 			info:            "create fargate profiles",
 			spec:            cfg,
 			clusterProvider: c,
@@ -295,31 +316,38 @@ func (c *ClusterProvider) CreateExtraClusterConfigTasks(cfg *api.ClusterConfig)
 		})
 	}
 
-	if api.IsEnabled(cfg.IAM.WithOIDC) {
-		c.appendCreateTasksForIAMServiceAccounts(cfg, newTasks)
+	if clusterUpdates.Len() > 0 {
+		if err := newTasks.AppendWithDependencies(clusterUpdates, waitForControlPlane); err != nil {
+			return nil, err
+		}
 	}
 
-	if len(cfg.IdentityProviders) > 0 {
-		newTasks.Append(identityproviders.NewAssociateProvidersTask(*cfg.Metadata, cfg.IdentityProviders, c.Provider.EKS()))
+	if api.IsEnabled(cfg.IAM.WithOIDC) {
+		if err := c.appendCreateTasksForIAMServiceAccounts(cfg, newTasks, waitForControlPlane); err != nil {
+			return nil, err
+		}
 	}
 
 	if cfg.HasWindowsNodeGroup() {
-		newTasks.Append(&WindowsIPAMTask{
+		if err := newTasks.AppendWithDependencies(&WindowsIPAMTask{
 			Info: "enable Windows IP address management",
 			ClientsetFunc: func() (kubernetes.Interface, error) {
 				return c.NewStdClientSet(cfg)
 			},
-		})
+		}, waitForControlPlane); err != nil {
+			return nil, err
+		}
 	}
 
-	return newTasks
+	return newTasks, nil
 }
 
 // ClusterTasksForNodeGroups returns all tasks dependent on node groups
 func (c *ClusterProvider) ClusterTasksForNodeGroups(cfg *api.ClusterConfig, installNeuronDevicePluginParam, installNvidiaDevicePluginParam bool) *tasks.TaskTree {
 	tasks := &tasks.TaskTree{
-		Parallel:  true,
-		IsSubTask: false,
+		Parallel:    true,
+		IsSubTask:   false,
+		MaxParallel: c.Provider.MaxParallelStacks(),
 	}
 	var needsNvidiaButNotNeuron = func(t string) bool {
 		return instanceutils.IsGPUInstanceType(t) && !instanceutils.IsInferentiaInstanceType(t)
@@ -372,24 +400,34 @@ func (c *ClusterProvider) ClusterTasksForNodeGroups(cfg *api.ClusterConfig, inst
 	return tasks
 }
 
-func (c *ClusterProvider) appendCreateTasksForIAMServiceAccounts(cfg *api.ClusterConfig, tasks *tasks.TaskTree) {
+// appendCreateTasksForIAMServiceAccounts appends the tasks associating the OIDC provider once dependsOn have completed,
+// then creating the iamserviceaccounts and restarting aws-node to pick up its iamserviceaccount
+func (c *ClusterProvider) appendCreateTasksForIAMServiceAccounts(cfg *api.ClusterConfig, tasks *tasks.TaskTree, dependsOn ...tasks.Task) error {
 	// we don't have all the information to construct full iamoidc.OpenIDConnectManager now,
 	// instead we just create a reference that gets updated when first task runs, and gets
 	// used by this would be more elegant if it was all done via CloudFormation and we didn't
 	// have to put wires across all the things like this; this whole function is needed because
 	// we cannot manage certain EKS features with CloudFormation
 	oidcPlaceholder := &iamoidc.OpenIDConnectManager{}
-	tasks.Append(&clusterConfigTask{
+	associateOIDCProvider := &clusterConfigTask{
 		info: "associate IAM OIDC provider",
 		spec: cfg,
+		// the provider is needed by the iamserviceaccount tasks
+		rerunOnResume: true,
 		call: func(cfg *api.ClusterConfig) error {
 			oidc, err := c.NewOpenIDConnectManager(cfg)
 			if err != nil {
 				return err
 			}
-			if err := oidc.CreateProvider(); err != nil {
+			exists, err := oidc.CheckProviderExists()
+			if err != nil {
 				return err
 			}
+			if !exists {
+				if err := oidc.CreateProvider(); err != nil {
+					return err
+				}
+			}
 			*oidcPlaceholder = *oidc
 			// Make sure control plane is reachable
 			clientSet, err := c.NewStdClientSet(cfg)
@@ -401,7 +439,10 @@ func (c *ClusterProvider) appendCreateTasksForIAMServiceAccounts(cfg *api.Cluste
 			}
 			return nil
 		},
-	})
+	}
+	if err := tasks.AppendWithDependencies(associateOIDCProvider, dependsOn...); err != nil {
+		return err
+	}
 
 	clientSet := &kubernetes.CallbackClientSet{
 		Callback: func() (kubernetes.Interface, error) {
@@ -418,13 +459,15 @@ func (c *ClusterProvider) appendCreateTasksForIAMServiceAccounts(cfg *api.Cluste
 		clientSet,
 	)
 	newTasks.IsSubTask = true
-	tasks.Append(newTasks)
-	tasks.Append(&restartDaemonsetTask{
+	if err := tasks.AppendWithDependencies(newTasks, associateOIDCProvider); err != nil {
+		return err
+	}
+	return tasks.AppendWithDependencies(&restartDaemonsetTask{
 		namespace:       "kube-system",
 		name:            "aws-node",
 		clusterProvider: c,
 		spec:            cfg,
-	})
+	}, newTasks)
 }
 
 func (c *ClusterProvider) maybeAppendTasksForEndpointAccessUpdates(cfg *api.ClusterConfig, tasks *tasks.TaskTree) {
@@ -0,0 +1,58 @@
+package eks_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
+)
+
+var _ = Describe("CreateExtraClusterConfigTasks", func() {
+	var (
+		c   *ClusterProvider
+		cfg *api.ClusterConfig
+	)
+
+	BeforeEach(func() {
+		c = &ClusterProvider{
+			Provider: mockprovider.NewMockProvider(),
+		}
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		cfg.Metadata.Region = "us-west-2"
+		cfg.Metadata.Tags = map[string]string{"team": "eks"}
+		api.SetClusterEndpointAccessDefaults(cfg.VPC)
+		cfg.IAM.WithOIDC = api.Enabled()
+	})
+
+	findTask := func(taskTree *tasks.TaskTree, desc string) tasks.Task {
+		for _, task := range taskTree.Tasks {
+			if task.Describe() == desc {
+				return task
+			}
+		}
+		Fail("task not found: " + desc)
+		return nil
+	}
+
+	It("only makes the iamserviceaccounts wait for the OIDC provider", func() {
+		taskTree, err := c.CreateExtraClusterConfigTasks(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(taskTree.Parallel).To(BeTrue())
+
+		waitForControlPlane := findTask(taskTree, "wait for control plane to become ready")
+		associateOIDCProvider := findTask(taskTree, "associate IAM OIDC provider")
+		Expect(taskTree.Dependencies(associateOIDCProvider)).To(ConsistOf(waitForControlPlane))
+
+		serviceAccounts := taskTree.Tasks[3]
+		Expect(serviceAccounts).To(BeAssignableToTypeOf(&tasks.TaskTree{}))
+		Expect(taskTree.Dependencies(serviceAccounts)).To(ConsistOf(associateOIDCProvider))
+
+		clusterUpdates := taskTree.Tasks[1]
+		Expect(clusterUpdates.Describe()).To(ContainSubstring("tag cluster"))
+		Expect(taskTree.Dependencies(clusterUpdates)).To(ConsistOf(waitForControlPlane))
+	})
+})
@@ -349,7 +349,7 @@ func (c *ClusterProvider) waitForUpdateToSucceed(clusterName string, update *eks
 
 	msg := fmt.Sprintf("waiting for requested %q in cluster %q to succeed", *update.Type, clusterName)
 
-	return waiters.Wait(clusterName, msg, acceptors, newRequest, c.Provider.WaitTimeout(), nil)
+	return waiters.Wait(context.TODO(), clusterName, msg, acceptors, newRequest, c.Provider.WaitTimeout(), nil)
 }
 
 func controlPlaneIsVersion(clientSet *kubeclient.Clientset, version string) (bool, error) {
@@ -13,6 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/pkg/errors"
 
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	cft "github.com/weaveworks/eksctl/pkg/cfn/template"
 )
 
@@ -28,6 +29,7 @@ type OpenIDConnectManager struct {
 	issuerURL          *url.URL
 	insecureSkipVerify bool
 	issuerCAThumbprint string
+	retained           bool
 
 	ProviderARN string
 
@@ -69,7 +71,7 @@ func (m *OpenIDConnectManager) CheckProviderExists() (bool, error) {
 			fmt.Sprintf("arn:%s:iam::%s:oidc-provider/%s", m.partition, m.accountID, m.hostnameAndPath()),
 		),
 	}
-	_, err := m.iam.GetOpenIDConnectProvider(input)
+	output, err := m.iam.GetOpenIDConnectProvider(input)
 	if err != nil {
 		awsError := err.(awserr.Error)
 		if awsError.Code() == awsiam.ErrCodeNoSuchEntityException {
@@ -78,9 +80,20 @@ func (m *OpenIDConnectManager) CheckProviderExists() (bool, error) {
 		return false, err
 	}
 	m.ProviderARN = *input.OpenIDConnectProviderArn
+	for _, tag := range output.Tags {
+		if aws.StringValue(tag.Key) == api.RetainTag && aws.StringValue(tag.Value) == "true" {
+			m.retained = true
+		}
+	}
 	return true, nil
 }
 
+// IsRetained returns true when the provider is tagged to be kept on cluster deletion,
+// it is only known after calling CheckProviderExists
+func (m *OpenIDConnectManager) IsRetained() bool {
+	return m.retained
+}
+
 // CreateProvider will retrieve CA root certificate and compute its thumbprint for the
 // by connecting to it and create the provider using IAM API
 func (m *OpenIDConnectManager) CreateProvider() error {
@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// JournalConfigMapName is the name of the ConfigMap holding the journal of an operation
+	JournalConfigMapName = "eksctl-journal"
+	journalConfigMapKey  = "journal.json"
+)
+
+// ConfigMapJournalStore stores a task journal in a ConfigMap of the cluster, so that
+// an operation can be resumed from another machine; the cluster may not be reachable
+// until some of the tasks of the operation have completed
+type ConfigMapJournalStore struct {
+	ClientSetGetter ClientSetGetter
+}
+
+func (s *ConfigMapJournalStore) configMaps() (typedcorev1.ConfigMapInterface, error) {
+	clientSet, err := s.ClientSetGetter.ClientSet()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting clientset to access journal")
+	}
+	return clientSet.CoreV1().ConfigMaps(metav1.NamespaceSystem), nil
+}
+
+// Load implements tasks.JournalStore
+func (s *ConfigMapJournalStore) Load() ([]byte, error) {
+	configMaps, err := s.configMaps()
+	if err != nil {
+		return nil, err
+	}
+	cm, err := configMaps.Get(context.TODO(), JournalConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting ConfigMap %q", JournalConfigMapName)
+	}
+	return []byte(cm.Data[journalConfigMapKey]), nil
+}
+
+// Save implements tasks.JournalStore
+func (s *ConfigMapJournalStore) Save(data []byte) error {
+	configMaps, err := s.configMaps()
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      JournalConfigMapName,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Data: map[string]string{journalConfigMapKey: string(data)},
+	}
+	_, err = configMaps.Update(context.TODO(), cm, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(context.TODO(), cm, metav1.CreateOptions{})
+	}
+	return errors.Wrapf(err, "saving ConfigMap %q", JournalConfigMapName)
+}
+
+// Delete implements tasks.JournalStore
+func (s *ConfigMapJournalStore) Delete() error {
+	configMaps, err := s.configMaps()
+	if err != nil {
+		return err
+	}
+	err = configMaps.Delete(context.TODO(), JournalConfigMapName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "deleting ConfigMap %q", JournalConfigMapName)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package kubernetes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/client-go/kubernetes/fake"
+
+	. "github.com/weaveworks/eksctl/pkg/kubernetes"
+)
+
+var _ = Describe("ConfigMap journal store", func() {
+	It("saves, loads and deletes the journal", func() {
+		store := &ConfigMapJournalStore{
+			ClientSetGetter: &CachedClientSet{CachedClientSet: fake.NewSimpleClientset()},
+		}
+
+		data, err := store.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(BeNil())
+
+		Expect(store.Save([]byte(`{"tasks": {}}`))).To(Succeed())
+		Expect(store.Save([]byte(`{"tasks": {"t1": {"state": "completed"}}}`))).To(Succeed())
+		data, err = store.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"tasks": {"t1": {"state": "completed"}}}`))
+
+		Expect(store.Delete()).To(Succeed())
+		Expect(store.Delete()).To(Succeed())
+		data, err = store.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(BeNil())
+	})
+})
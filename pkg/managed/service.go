@@ -1,8 +1,10 @@
 package managed
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 
@@ -64,6 +66,23 @@ type UpgradeOptions struct {
 	ReleaseVersion string
 	// Wait for the upgrade to finish
 	Wait bool
+	// Plan only previews the changes to the nodegroup stack
+	Plan bool
+
+	// The following options only apply to self-managed nodegroups
+
+	// AMI is the AMI to upgrade to, by default the EKS-optimized AMI for
+	// the Kubernetes version is used
+	AMI string
+	// UpdateConfig bounds the number of nodes that are replaced at the same time
+	UpdateConfig *api.NodeGroupUpdateConfig
+	// InstanceRefresh replaces the instances with an ASG instance refresh
+	// instead of draining and terminating them in batches
+	InstanceRefresh bool
+	// MaxGracePeriod is the maximum termination grace period of pods when draining nodes
+	MaxGracePeriod time.Duration
+	// DisableEviction deletes pods instead of evicting them when draining nodes
+	DisableEviction bool
 }
 
 // TODO use goformation types
@@ -115,7 +134,7 @@ func (m *Service) GetHealth(nodeGroupName string) ([]HealthIssue, error) {
 }
 
 // UpdateLabels adds or removes labels for a nodegroup
-func (m *Service) UpdateLabels(nodeGroupName string, labelsToAdd map[string]string, labelsToRemove []string) error {
+func (m *Service) UpdateLabels(ctx context.Context, nodeGroupName string, labelsToAdd map[string]string, labelsToRemove []string) error {
 	template, err := m.stackCollection.GetManagedNodeGroupTemplate(nodeGroupName)
 	if err != nil {
 		return err
@@ -139,7 +158,7 @@ func (m *Service) UpdateLabels(nodeGroupName string, labelsToAdd map[string]stri
 		return err
 	}
 
-	return m.stackCollection.UpdateNodeGroupStack(nodeGroupName, template, true)
+	return m.stackCollection.UpdateNodeGroupStack(ctx, nodeGroupName, template, true, false)
 }
 
 // GetLabels fetches the labels for a nodegroup
@@ -154,7 +173,7 @@ func (m *Service) GetLabels(nodeGroupName string) (map[string]string, error) {
 // UpgradeNodeGroup upgrades nodegroup to the latest AMI release for the specified Kubernetes version, or
 // the current Kubernetes version if the version isn't specified
 // If options.LaunchTemplateVersion is set, it also upgrades the nodegroup to the specified launch template version
-func (m *Service) UpgradeNodeGroup(options UpgradeOptions) error {
+func (m *Service) UpgradeNodeGroup(ctx context.Context, options UpgradeOptions) error {
 	output, err := m.eksAPI.DescribeNodegroup(&eks.DescribeNodegroupInput{
 		ClusterName:   &m.clusterName,
 		NodegroupName: &options.NodegroupName,
@@ -194,7 +213,7 @@ func (m *Service) UpgradeNodeGroup(options UpgradeOptions) error {
 		if err != nil {
 			return err
 		}
-		if err := m.stackCollection.UpdateNodeGroupStack(options.NodegroupName, string(bytes), true); err != nil {
+		if err := m.stackCollection.UpdateNodeGroupStack(ctx, options.NodegroupName, string(bytes), true, options.Plan); err != nil {
 			return errors.Wrap(err, "error updating nodegroup stack")
 		}
 		return nil
@@ -204,7 +223,8 @@ func (m *Service) UpgradeNodeGroup(options UpgradeOptions) error {
 	if err != nil {
 		return err
 	}
-	if requiresUpdate {
+	// in plan mode the format update is previewed as part of the version upgrade below
+	if requiresUpdate && !options.Plan {
 		logger.Info("updating nodegroup stack to a newer format before upgrading nodegroup version")
 		// always wait for the main stack update
 		if err := updateStack(stack, true); err != nil {
@@ -272,6 +292,11 @@ func (m *Service) UpgradeNodeGroup(options UpgradeOptions) error {
 
 	ngResource.ForceUpdateEnabled = gfnt.NewBoolean(options.ForceUpgrade)
 
+	if options.Plan {
+		logger.Info("(plan) previewing changes to upgrade nodegroup %q", options.NodegroupName)
+		return updateStack(stack, options.Wait)
+	}
+
 	logger.Info("upgrading nodegroup version")
 	if err := updateStack(stack, options.Wait); err != nil {
 		return err
@@ -0,0 +1,174 @@
+package nodebootstrap
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap/utils"
+)
+
+const (
+	nodeConfigAPIVersion  = "node.eks.aws/v1alpha1"
+	nodeConfigKind        = "NodeConfig"
+	nodeConfigContentType = "application/node.eks.aws"
+)
+
+// nodeConfig is the configuration read by nodeadm, which bootstraps the nodes of
+// the Amazon Linux 2022 EKS AMI in place of /etc/eks/bootstrap.sh
+type nodeConfig struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Spec       nodeConfigSpec `json:"spec"`
+}
+
+type nodeConfigSpec struct {
+	Cluster nodeConfigCluster `json:"cluster"`
+	Kubelet nodeConfigKubelet `json:"kubelet,omitempty"`
+}
+
+type nodeConfigCluster struct {
+	Name                 string `json:"name"`
+	APIServerEndpoint    string `json:"apiServerEndpoint"`
+	CertificateAuthority []byte `json:"certificateAuthority"`
+	// CIDR is the service IPv4 CIDR of the cluster
+	CIDR string `json:"cidr,omitempty"`
+}
+
+type nodeConfigKubelet struct {
+	// Config is merged into the kubelet config file
+	Config map[string]interface{} `json:"config,omitempty"`
+	Flags  []string               `json:"flags,omitempty"`
+}
+
+// AmazonLinux2022 is a bootstrapper for both unmanaged and managed nodegroups
+// using the Amazon Linux 2022 EKS AMI
+type AmazonLinux2022 struct {
+	clusterConfig *api.ClusterConfig
+	np            api.NodePool
+
+	UserDataMimeBoundary string
+}
+
+// NewAL2022Bootstrapper returns a new bootstrapper for Amazon Linux 2022
+func NewAL2022Bootstrapper(clusterConfig *api.ClusterConfig, np api.NodePool) *AmazonLinux2022 {
+	return &AmazonLinux2022{
+		clusterConfig: clusterConfig,
+		np:            np,
+	}
+}
+
+// UserData returns a MIME document with the NodeConfig of the node, preceded by the preBootstrapCommands;
+// the NodeConfig is replaced with overrideBootstrapCommand if set
+func (b *AmazonLinux2022) UserData() (string, error) {
+	ng := b.np.BaseNodeGroup()
+	if err := api.ValidateAMIFamilyVersion(api.NodeImageFamilyAmazonLinux2022, b.clusterConfig.Metadata.Version); err != nil {
+		return "", err
+	}
+
+	var (
+		buf        bytes.Buffer
+		scripts    []string
+		nodeConfig string
+	)
+
+	if len(ng.PreBootstrapCommands) > 0 {
+		scripts = append(scripts, ng.PreBootstrapCommands...)
+	}
+
+	if ng.OverrideBootstrapCommand != nil {
+		scripts = append(scripts, *ng.OverrideBootstrapCommand)
+	} else {
+		config, err := b.makeNodeConfig()
+		if err != nil {
+			return "", err
+		}
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return "", errors.Wrap(err, "encoding NodeConfig")
+		}
+		nodeConfig = string(data)
+	}
+
+	if err := createMimeMessage(&buf, scripts, nil, nodeConfig, b.UserDataMimeBoundary); err != nil {
+		return "", errors.Wrap(err, "encoding user data")
+	}
+
+	logger.Debug("user-data = %s", buf.String())
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func (b *AmazonLinux2022) makeNodeConfig() (*nodeConfig, error) {
+	ng := b.np.BaseNodeGroup()
+	status := b.clusterConfig.Status
+
+	config := &nodeConfig{
+		APIVersion: nodeConfigAPIVersion,
+		Kind:       nodeConfigKind,
+		Spec: nodeConfigSpec{
+			Cluster: nodeConfigCluster{
+				Name:                 b.clusterConfig.Metadata.Name,
+				APIServerEndpoint:    status.Endpoint,
+				CertificateAuthority: status.CertificateAuthorityData,
+			},
+		},
+	}
+	if status.KubernetesNetworkConfig != nil {
+		config.Spec.Cluster.CIDR = status.KubernetesNetworkConfig.ServiceIPv4CIDR
+	}
+
+	kubeletConfig, err := b.makeKubeletConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Spec.Kubelet.Config = kubeletConfig
+
+	flags := []string{"--node-labels=" + formatLabels(ng.Labels)}
+	if taints := utils.FormatTaints(b.np.NGTaints()); taints != "" {
+		flags = append(flags, "--register-with-taints="+taints)
+	}
+	config.Spec.Kubelet.Flags = flags
+	return config, nil
+}
+
+// makeKubeletConfig combines kubeletExtraConfig with the max pods and cluster DNS of the nodegroup
+func (b *AmazonLinux2022) makeKubeletConfig() (map[string]interface{}, error) {
+	ng := b.np.BaseNodeGroup()
+	kubeletConfig := map[string]interface{}{}
+
+	clusterDNS := ""
+	if unmanaged, ok := b.np.(*api.NodeGroup); ok {
+		clusterDNS = unmanaged.ClusterDNS
+		if unmanaged.KubeletExtraConfig != nil {
+			// makeKubeletExtraConf checks that the extra config is a valid KubeletConfiguration
+			extraConf, err := makeKubeletExtraConf(unmanaged.KubeletExtraConfig)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal([]byte(extraConf.Content), &kubeletConfig); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		var err error
+		if clusterDNS, err = GetClusterDNS(b.clusterConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	if ng.MaxPodsPerNode > 0 {
+		kubeletConfig["maxPods"] = ng.MaxPodsPerNode
+	}
+	if clusterDNS != "" {
+		kubeletConfig["clusterDNS"] = []string{clusterDNS}
+	}
+	if len(kubeletConfig) == 0 {
+		return nil, nil
+	}
+	return kubeletConfig, nil
+}
@@ -0,0 +1,169 @@
+package nodebootstrap_test
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"mime/multipart"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
+)
+
+type userDataPart struct {
+	contentType string
+	body        string
+}
+
+var _ = Describe("AmazonLinux2022", func() {
+	var clusterConfig *api.ClusterConfig
+
+	BeforeEach(func() {
+		clusterConfig = api.NewClusterConfig()
+		clusterConfig.Metadata.Name = "al2022-cluster"
+		clusterConfig.Metadata.Version = api.Version1_22
+		clusterConfig.Status = &api.ClusterStatus{
+			Endpoint:                 "https://test.example.com",
+			CertificateAuthorityData: []byte("CertificateAuthorityData"),
+			KubernetesNetworkConfig: &api.KubernetesNetworkConfig{
+				ServiceIPv4CIDR: "10.100.0.0/16",
+			},
+		}
+	})
+
+	renderParts := func(np api.NodePool) []userDataPart {
+		bootstrapper := nodebootstrap.NewAL2022Bootstrapper(clusterConfig, np)
+		bootstrapper.UserDataMimeBoundary = "//"
+		userData, err := bootstrapper.UserData()
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := base64.StdEncoding.DecodeString(userData)
+		Expect(err).NotTo(HaveOccurred())
+
+		// skip the MIME-Version and Content-Type headers of the message
+		body := string(decoded)[strings.Index(string(decoded), "\r\n\r\n")+4:]
+		reader := multipart.NewReader(strings.NewReader(body), "//")
+		var parts []userDataPart
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			content, err := ioutil.ReadAll(part)
+			Expect(err).NotTo(HaveOccurred())
+			parts = append(parts, userDataPart{contentType: part.Header.Get("Content-Type"), body: string(content)})
+		}
+		return parts
+	}
+
+	parseNodeConfig := func(part userDataPart) map[string]interface{} {
+		Expect(part.contentType).To(Equal("application/node.eks.aws"))
+		var config map[string]interface{}
+		Expect(yaml.Unmarshal([]byte(part.body), &config)).To(Succeed())
+		Expect(config["apiVersion"]).To(Equal("node.eks.aws/v1alpha1"))
+		Expect(config["kind"]).To(Equal("NodeConfig"))
+		return config["spec"].(map[string]interface{})
+	}
+
+	Context("unmanaged nodegroup", func() {
+		var ng *api.NodeGroup
+
+		BeforeEach(func() {
+			ng = api.NewNodeGroup()
+			ng.Name = "al2022"
+			ng.AMIFamily = api.NodeImageFamilyAmazonLinux2022
+			ng.Labels = map[string]string{"role": "worker"}
+			ng.Taints = []api.NodeGroupTaint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}}
+			ng.MaxPodsPerNode = 20
+			ng.KubeletExtraConfig = &api.InlineDocument{"kubeReserved": map[string]interface{}{"cpu": "300m"}}
+			// set by NewBootstrapper from the service IPv4 CIDR
+			ng.ClusterDNS = "10.100.0.10"
+		})
+
+		It("writes the cluster and kubelet settings to a NodeConfig", func() {
+			parts := renderParts(ng)
+			Expect(parts).To(HaveLen(1))
+
+			spec := parseNodeConfig(parts[0])
+			Expect(spec["cluster"]).To(Equal(map[string]interface{}{
+				"name":                 "al2022-cluster",
+				"apiServerEndpoint":    "https://test.example.com",
+				"certificateAuthority": base64.StdEncoding.EncodeToString([]byte("CertificateAuthorityData")),
+				"cidr":                 "10.100.0.0/16",
+			}))
+			Expect(spec["kubelet"]).To(Equal(map[string]interface{}{
+				"config": map[string]interface{}{
+					"kubeReserved": map[string]interface{}{"cpu": "300m"},
+					"maxPods":      float64(20),
+					"clusterDNS":   []interface{}{"10.100.0.10"},
+				},
+				"flags": []interface{}{
+					"--node-labels=role=worker",
+					"--register-with-taints=dedicated=gpu:NoSchedule",
+				},
+			}))
+		})
+
+		It("is created by NewBootstrapper, which sets the cluster DNS", func() {
+			ng.ClusterDNS = ""
+			bootstrapper, err := nodebootstrap.NewBootstrapper(clusterConfig, ng)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bootstrapper).To(BeAssignableToTypeOf(&nodebootstrap.AmazonLinux2022{}))
+			Expect(ng.ClusterDNS).To(Equal("10.100.0.10"))
+		})
+
+		It("rejects kubeletExtraConfig that is not a valid KubeletConfiguration", func() {
+			ng.KubeletExtraConfig = &api.InlineDocument{"maxPods": "not-a-number"}
+			_, err := nodebootstrap.NewAL2022Bootstrapper(clusterConfig, ng).UserData()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("runs preBootstrapCommands before the NodeConfig is applied", func() {
+			ng.PreBootstrapCommands = []string{"echo pre"}
+			parts := renderParts(ng)
+			Expect(parts).To(HaveLen(2))
+			Expect(parts[0]).To(Equal(userDataPart{contentType: "text/x-shellscript", body: "echo pre"}))
+			Expect(parts[1].contentType).To(Equal("application/node.eks.aws"))
+		})
+
+		It("replaces the NodeConfig with overrideBootstrapCommand", func() {
+			ng.OverrideBootstrapCommand = aws.String("echo override")
+			parts := renderParts(ng)
+			Expect(parts).To(Equal([]userDataPart{{contentType: "text/x-shellscript", body: "echo override"}}))
+		})
+
+		It("fails for Kubernetes versions the AMI is not published for", func() {
+			clusterConfig.Metadata.Version = api.Version1_21
+			_, err := nodebootstrap.NewAL2022Bootstrapper(clusterConfig, ng).UserData()
+			Expect(err).To(MatchError("AmazonLinux2022 requires EKS version 1.22 and above"))
+		})
+	})
+
+	Context("managed nodegroup with a custom AMI", func() {
+		It("derives the cluster DNS and sets the labels, taints and max pods", func() {
+			ng := api.NewManagedNodeGroup()
+			ng.Name = "al2022"
+			ng.AMIFamily = api.NodeImageFamilyAmazonLinux2022
+			ng.AMI = "ami-custom"
+			ng.Labels = map[string]string{"role": "worker"}
+			ng.Taints = []api.NodeGroupTaint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}}
+			ng.MaxPodsPerNode = 30
+
+			spec := parseNodeConfig(renderParts(ng)[0])
+			Expect(spec["kubelet"]).To(Equal(map[string]interface{}{
+				"config": map[string]interface{}{
+					"maxPods":    float64(30),
+					"clusterDNS": []interface{}{"10.100.0.10"},
+				},
+				"flags": []interface{}{
+					"--node-labels=role=worker",
+					"--register-with-taints=dedicated=gpu:NoSchedule",
+				},
+			}))
+		})
+	})
+})
@@ -67,6 +67,38 @@ var _ = Describe("AmazonLinux2 User Data", func() {
 		})
 	})
 
+	When("the nodegroup has a warm pool", func() {
+		BeforeEach(func() {
+			ng.PreBootstrapCommands = []string{"echo pre"}
+			ng.WarmPool = &api.WarmPool{}
+			bootstrapper = newBootstrapper(clusterConfig, ng)
+		})
+
+		It("bootstraps the node with a systemd unit once the instance leaves the warm pool", func() {
+			userData, err := bootstrapper.UserData()
+			Expect(err).NotTo(HaveOccurred())
+
+			cloudCfg := decode(userData)
+			files := map[string]string{}
+			for _, f := range cloudCfg.WriteFiles {
+				files[f.Path] = f.Content
+			}
+			Expect(files).To(HaveKey("/var/lib/cloud/scripts/eksctl/bootstrap.warmpool.sh"))
+			Expect(files["/var/lib/cloud/scripts/eksctl/bootstrap.warmpool.sh"]).To(ContainSubstring("target-lifecycle-state"))
+			Expect(files).To(HaveKey("/var/lib/cloud/scripts/eksctl/bootstrap.al2.sh"))
+			Expect(files["/etc/systemd/system/eksctl-bootstrap.service"]).To(ContainSubstring(
+				"ExecStart=/var/lib/cloud/scripts/eksctl/bootstrap.warmpool.sh /var/lib/cloud/scripts/eksctl/bootstrap.helper.sh /var/lib/cloud/scripts/eksctl/bootstrap.al2.sh\n",
+			))
+
+			// the pre-bootstrap commands still run when the instance is launched, the bootstrap scripts only run from the unit
+			Expect(cloudCfg.Commands).To(Equal([]interface{}{
+				[]interface{}{"/bin/bash", "-c", "echo pre"},
+				[]interface{}{"systemctl", "daemon-reload"},
+				[]interface{}{"systemctl", "enable", "--now", "--no-block", "eksctl-bootstrap.service"},
+			}))
+		})
+	})
+
 	type bootScriptEntry struct {
 		clusterConfig    *api.ClusterConfig
 		ng               *api.NodeGroup
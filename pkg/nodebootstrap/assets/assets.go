@@ -13,6 +13,10 @@ var EksctlAl2Conf string
 //go:embed scripts/bootstrap.al2.sh
 var BootstrapAl2Sh string
 
+//BootstrapFlatcarSh holds the bootstrap.flatcar.sh contents
+//go:embed scripts/bootstrap.flatcar.sh
+var BootstrapFlatcarSh string
+
 //BootstrapHelperSh holds the bootstrap.helper.sh contents
 //go:embed scripts/bootstrap.helper.sh
 var BootstrapHelperSh string
@@ -21,6 +25,10 @@ var BootstrapHelperSh string
 //go:embed scripts/bootstrap.ubuntu.sh
 var BootstrapUbuntuSh string
 
+//BootstrapWarmpoolSh holds the bootstrap.warmpool.sh contents
+//go:embed scripts/bootstrap.warmpool.sh
+var BootstrapWarmpoolSh string
+
 //EfaAl2Sh holds the efa.al2.sh contents
 //go:embed scripts/efa.al2.sh
 var EfaAl2Sh string
@@ -33,6 +41,10 @@ var EfaManagedBoothook string
 //go:embed scripts/install-ssm.al2.sh
 var InstallSsmAl2Sh string
 
+//KubeletFlatcarService holds the kubelet.flatcar.service contents
+//go:embed scripts/kubelet.flatcar.service
+var KubeletFlatcarService string
+
 //KubeletYaml holds the kubelet.yaml contents
 //go:embed scripts/kubelet.yaml
 var KubeletYaml string
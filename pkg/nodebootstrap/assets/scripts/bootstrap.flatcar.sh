@@ -0,0 +1,123 @@
+#!/bin/bash
+
+set -o errexit
+set -o pipefail
+set -o nounset
+
+source /opt/eksctl/bootstrap.helper.sh
+
+# Flatcar does not come with the EKS bootstrap script, so the kubelet and aws-iam-authenticator are installed
+# to /opt/bin, as /usr is read-only, and configured here; the node needs egress to dl.k8s.io and github.com
+# to download them, and each download is checked against the SHA-256 checksum pinned by eksctl in kubelet.env
+BIN_DIR='/opt/bin'
+AWS_IAM_AUTHENTICATOR_RELEASE_URL="https://github.com/kubernetes-sigs/aws-iam-authenticator/releases/download/v${AWS_IAM_AUTHENTICATOR_VERSION}"
+KUBELET_RELEASE_URL="https://dl.k8s.io/release/${KUBELET_VERSION}"
+CA_FILE='/etc/kubernetes/pki/ca.crt'
+KUBECONFIG_FILE='/var/lib/kubelet/kubeconfig'
+KUBELET_ARGS_ENV='/etc/eksctl/kubelet-args.env'
+
+case "$(uname -m)" in
+  aarch64) ARCH='arm64' ;;
+  *) ARCH='amd64' ;;
+esac
+KUBELET_SHA256_VAR="KUBELET_SHA256_${ARCH^^}"
+AWS_IAM_AUTHENTICATOR_SHA256_VAR="AWS_IAM_AUTHENTICATOR_SHA256_${ARCH^^}"
+
+function fetch() {
+  curl --silent --show-error --fail --location --retry 5 "$@"
+}
+
+# download <file> <url> <sha256> installs the executable at url to file if its checksum matches
+function download() {
+  fetch --output "$1.download" "$2"
+  if ! echo "$3  $1.download" | sha256sum --check --status; then
+    rm -f "$1.download"
+    echo "eksctl: the SHA-256 checksum of $2 does not match the pinned checksum $3" >&2
+    exit 1
+  fi
+  chmod +x "$1.download"
+  mv "$1.download" "$1"
+}
+
+mkdir -p "${BIN_DIR}" "$(dirname "${CA_FILE}")" "$(dirname "${KUBECONFIG_FILE}")" "$(dirname "${KUBELET_CONFIG}")"
+
+echo "eksctl: downloading kubelet ${KUBELET_VERSION}"
+KUBELET_URL="${KUBELET_RELEASE_URL}/bin/linux/${ARCH}/kubelet"
+download "${BIN_DIR}/kubelet" "${KUBELET_URL}" "${!KUBELET_SHA256_VAR}"
+
+echo "eksctl: downloading aws-iam-authenticator ${AWS_IAM_AUTHENTICATOR_VERSION}"
+AWS_IAM_AUTHENTICATOR_FILE="aws-iam-authenticator_${AWS_IAM_AUTHENTICATOR_VERSION}_linux_${ARCH}"
+download "${BIN_DIR}/aws-iam-authenticator" "${AWS_IAM_AUTHENTICATOR_RELEASE_URL}/${AWS_IAM_AUTHENTICATOR_FILE}" "${!AWS_IAM_AUTHENTICATOR_SHA256_VAR}"
+
+if [[ -z "${CLUSTER_DNS}" ]]; then
+  # same as the EKS bootstrap script, the service CIDR is 172.20.0.0/16 if the VPC uses 10.0.0.0/8
+  MAC="$(get_metadata mac)"
+  if get_metadata "network/interfaces/macs/${MAC}/vpc-ipv4-cidr-blocks" | grep --quiet '^10\.'; then
+    CLUSTER_DNS='172.20.0.10'
+  else
+    CLUSTER_DNS='10.100.0.10'
+  fi
+fi
+
+echo "eksctl: writing the kubelet configuration"
+base64 --decode <<< "${B64_CLUSTER_CA}" > "${CA_FILE}"
+
+cat > "${KUBECONFIG_FILE}" <<EOF
+apiVersion: v1
+kind: Config
+clusters:
+- name: kubernetes
+  cluster:
+    certificate-authority: ${CA_FILE}
+    server: ${API_SERVER_URL}
+contexts:
+- name: kubelet
+  context:
+    cluster: kubernetes
+    user: kubelet
+current-context: kubelet
+users:
+- name: kubelet
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: ${BIN_DIR}/aws-iam-authenticator
+      args: ["token", "-i", "${CLUSTER_NAME}", "--region", "${AWS_REGION}"]
+EOF
+
+jq --null-input --arg clusterDNS "${CLUSTER_DNS}" --arg caFile "${CA_FILE}" '{
+  kind: "KubeletConfiguration",
+  apiVersion: "kubelet.config.k8s.io/v1beta1",
+  address: "0.0.0.0",
+  authentication: {
+    anonymous: {enabled: false},
+    webhook: {cacheTTL: "2m0s", enabled: true},
+    x509: {clientCAFile: $caFile}
+  },
+  authorization: {
+    mode: "Webhook",
+    webhook: {cacheAuthorizedTTL: "5m0s", cacheUnauthorizedTTL: "30s"}
+  },
+  cgroupDriver: "systemd",
+  clusterDomain: "cluster.local",
+  clusterDNS: [$clusterDNS],
+  hairpinMode: "hairpin-veth",
+  readOnlyPort: 0,
+  resolvConf: "/run/systemd/resolve/resolv.conf",
+  serializeImagePulls: false,
+  serverTLSBootstrap: true,
+  featureGates: {RotateKubeletServerCertificate: true}
+}' > "${KUBELET_CONFIG}"
+
+echo "eksctl: merging user options into kubelet-config.json"
+trap 'rm -f ${TMP_KUBE_CONF}' EXIT
+jq -s '.[0] * .[1]' "${KUBELET_CONFIG}" "${KUBELET_EXTRA_CONFIG}" > "${TMP_KUBE_CONF}"
+mv "${TMP_KUBE_CONF}" "${KUBELET_CONFIG}"
+
+echo "KUBELET_EXTRA_ARGS=\"${KUBELET_EXTRA_ARGS}\"" > "${KUBELET_ARGS_ENV}"
+
+systemctl daemon-reload
+echo "eksctl: starting kubelet"
+systemctl enable kubelet
+systemctl restart kubelet
+echo "eksctl: done"
@@ -0,0 +1,33 @@
+#!/bin/bash
+
+# Runs the bootstrap scripts passed as arguments once the instance leaves the warm pool of its nodegroup.
+# Instances launched into the warm pool are stopped or hibernated there, so they must not join the cluster
+# before they are put in service. The script is started by eksctl-bootstrap.service at every boot until the
+# node has been bootstrapped.
+
+set -o errexit
+set -o pipefail
+set -o nounset
+
+BOOTSTRAPPED='/var/lib/eksctl/bootstrapped'
+[[ -f "${BOOTSTRAPPED}" ]] && exit 0
+
+# Use IMDSv2 to get metadata
+function get_target_lifecycle_state() {
+  local token
+  token="$(curl --silent -X PUT -H "X-aws-ec2-metadata-token-ttl-seconds: 600" http://169.254.169.254/latest/api/token)"
+  curl --silent --fail -H "X-aws-ec2-metadata-token: ${token}" http://169.254.169.254/latest/meta-data/autoscaling/target-lifecycle-state
+}
+
+until [[ "$(get_target_lifecycle_state || true)" == "InService" ]]; do
+  echo "eksctl: waiting for the instance to leave the warm pool"
+  sleep 10
+done
+
+for script in "$@"; do
+  "${script}"
+done
+
+mkdir -p "$(dirname "${BOOTSTRAPPED}")"
+touch "${BOOTSTRAPPED}"
+echo "eksctl: node bootstrapped"
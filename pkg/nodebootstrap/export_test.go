@@ -0,0 +1,10 @@
+package nodebootstrap
+
+// SetFlatcarChecksums replaces the pinned checksums of the Flatcar binaries, and returns a func restoring them
+func SetFlatcarChecksums(kubeletSHA256 map[string]map[string]string, authenticatorSHA256 map[string]string) func() {
+	oldKubeletSHA256, oldAuthenticatorSHA256 := flatcarKubeletSHA256, flatcarAWSIAMAuthenticatorSHA256
+	flatcarKubeletSHA256, flatcarAWSIAMAuthenticatorSHA256 = kubeletSHA256, authenticatorSHA256
+	return func() {
+		flatcarKubeletSHA256, flatcarAWSIAMAuthenticatorSHA256 = oldKubeletSHA256, oldAuthenticatorSHA256
+	}
+}
@@ -0,0 +1,267 @@
+package nodebootstrap
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cloudconfig"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap/assets"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap/legacy"
+)
+
+const (
+	flatcarScriptDir      = "/opt/eksctl/"
+	flatcarBootScript     = "bootstrap.flatcar.sh"
+	flatcarPreBootScript  = "pre-bootstrap.sh"
+	flatcarOverrideScript = "bootstrap.override.sh"
+	flatcarBootUnit       = "eksctl-bootstrap.service"
+	flatcarKubeletUnit    = "kubelet.service"
+	// flatcarBootstrapped is created once the node has been bootstrapped, so that it is only bootstrapped once
+	flatcarBootstrapped = configDir + "bootstrapped"
+
+	ignitionVersion = "3.3.0"
+)
+
+// flatcarKubeletVersions are the kubelet releases the bootstrap script installs for each Kubernetes version,
+// pinned so that all nodes of a cluster run the same kubelet, whenever they are launched
+var flatcarKubeletVersions = map[string]string{
+	api.Version1_18: "v1.18.20",
+	api.Version1_19: "v1.19.16",
+	api.Version1_20: "v1.20.15",
+	api.Version1_21: "v1.21.14",
+}
+
+// flatcarArchitectures are the architectures of the binaries the bootstrap script installs, as named in their releases
+var flatcarArchitectures = []string{"amd64", "arm64"}
+
+// flatcarKubeletSHA256 are the SHA-256 checksums of the kubelet binaries of each release in flatcarKubeletVersions
+// by architecture, as published in https://dl.k8s.io/release/<release>/bin/linux/<arch>/kubelet.sha256; they are
+// rendered into the bootstrap environment rather than fetched by the nodes, so that a download is only installed
+// when it matches the binary eksctl was released with
+var flatcarKubeletSHA256 = map[string]map[string]string{}
+
+// flatcarAWSIAMAuthenticatorVersion is the aws-iam-authenticator release the bootstrap script installs
+const flatcarAWSIAMAuthenticatorVersion = "0.5.9"
+
+// flatcarAWSIAMAuthenticatorSHA256 are the SHA-256 checksums of the aws-iam-authenticator binaries by architecture,
+// as published in authenticator_<version>_checksums.txt of the release
+var flatcarAWSIAMAuthenticatorSHA256 = map[string]string{}
+
+// ignitionConfig is the subset of the Ignition config spec used to bootstrap Flatcar nodes
+type ignitionConfig struct {
+	Ignition ignitionMetadata `json:"ignition"`
+	Storage  ignitionStorage  `json:"storage"`
+	Systemd  ignitionSystemd  `json:"systemd"`
+}
+
+type ignitionMetadata struct {
+	Version string `json:"version"`
+}
+
+type ignitionStorage struct {
+	Files []ignitionFile `json:"files"`
+}
+
+type ignitionFile struct {
+	Path      string               `json:"path"`
+	Mode      int                  `json:"mode"`
+	Overwrite bool                 `json:"overwrite"`
+	Contents  ignitionFileContents `json:"contents"`
+}
+
+type ignitionFileContents struct {
+	// Source is a data URL holding the contents of the file
+	Source string `json:"source"`
+}
+
+type ignitionSystemd struct {
+	Units []ignitionUnit `json:"units"`
+}
+
+type ignitionUnit struct {
+	Name     string `json:"name"`
+	Enabled  bool   `json:"enabled"`
+	Contents string `json:"contents"`
+}
+
+// Flatcar is a bootstrapper for both unmanaged and managed nodegroups using Flatcar Container Linux,
+// whose nodes are configured with Ignition rather than cloud-init
+type Flatcar struct {
+	clusterConfig *api.ClusterConfig
+	np            api.NodePool
+}
+
+// NewFlatcarBootstrapper returns a new bootstrapper for Flatcar Container Linux
+func NewFlatcarBootstrapper(clusterConfig *api.ClusterConfig, np api.NodePool) *Flatcar {
+	return &Flatcar{
+		clusterConfig: clusterConfig,
+		np:            np,
+	}
+}
+
+// UserData returns an Ignition config that writes the same kubelet.env and kubelet-extra.json as for AmazonLinux2,
+// and a systemd unit that runs the Flatcar bootstrap script, or overrideBootstrapCommand if set. As Flatcar does
+// not come with the EKS bootstrap script, the bootstrap script installs the kubelet and configures it itself
+func (b *Flatcar) UserData() (string, error) {
+	ng := b.np.BaseNodeGroup()
+
+	var (
+		files     []ignitionFile
+		units     []ignitionUnit
+		execStart string
+	)
+	addFile := func(path, contents string, mode int) {
+		files = append(files, ignitionFile{
+			Path:      path,
+			Mode:      mode,
+			Overwrite: true,
+			Contents: ignitionFileContents{
+				Source: "data:;base64," + base64.StdEncoding.EncodeToString([]byte(contents)),
+			},
+		})
+	}
+
+	if ng.OverrideBootstrapCommand != nil {
+		addFile(flatcarScriptDir+flatcarOverrideScript, makeFlatcarScript(*ng.OverrideBootstrapCommand), 0755)
+		execStart = flatcarScriptDir + flatcarOverrideScript
+	} else {
+		var kubeletExtraConf *api.InlineDocument
+		if unmanaged, ok := b.np.(*api.NodeGroup); ok {
+			kubeletExtraConf = unmanaged.KubeletExtraConfig
+		}
+		kubeletConf, err := makeKubeletExtraConf(kubeletExtraConf)
+		if err != nil {
+			return "", err
+		}
+		envFile, err := b.makeBootstrapEnv()
+		if err != nil {
+			return "", err
+		}
+		for _, f := range []cloudconfig.File{kubeletConf, envFile} {
+			addFile(f.Path, f.Content, 0644)
+		}
+		addFile(flatcarScriptDir+commonLinuxBootScript, assets.BootstrapHelperSh, 0755)
+		addFile(flatcarScriptDir+flatcarBootScript, assets.BootstrapFlatcarSh, 0755)
+		execStart = flatcarScriptDir + flatcarBootScript
+		// the bootstrap script enables the kubelet once it has been configured
+		units = append(units, ignitionUnit{Name: flatcarKubeletUnit, Contents: assets.KubeletFlatcarService})
+	}
+
+	var execStartPre string
+	if len(ng.PreBootstrapCommands) > 0 {
+		addFile(flatcarScriptDir+flatcarPreBootScript, makeFlatcarScript(ng.PreBootstrapCommands...), 0755)
+		execStartPre = flatcarScriptDir + flatcarPreBootScript
+	}
+
+	config := ignitionConfig{
+		Ignition: ignitionMetadata{Version: ignitionVersion},
+		Storage:  ignitionStorage{Files: files},
+		Systemd: ignitionSystemd{
+			Units: append([]ignitionUnit{{
+				Name:     flatcarBootUnit,
+				Enabled:  true,
+				Contents: makeFlatcarBootUnit(execStartPre, execStart),
+			}}, units...),
+		},
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", errors.Wrap(err, "encoding user data")
+	}
+
+	logger.Debug("user-data = %s", data)
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// makeBootstrapEnv returns kubelet.env, along with the kubelet version and region the bootstrap script needs
+// to install the kubelet; the cluster DNS of managed nodegroups is derived from the cluster as they cannot set it
+func (b *Flatcar) makeBootstrapEnv() (cloudconfig.File, error) {
+	kubeletVersion, ok := flatcarKubeletVersions[b.clusterConfig.Metadata.Version]
+	if !ok {
+		return cloudconfig.File{}, fmt.Errorf("no kubelet release is known for Kubernetes version %q on Flatcar", b.clusterConfig.Metadata.Version)
+	}
+	envFile := makeBootstrapEnv(b.clusterConfig, b.np)
+	envFile.Content += fmt.Sprintf("\nKUBELET_VERSION=%s\nAWS_IAM_AUTHENTICATOR_VERSION=%s\nAWS_REGION=%s", kubeletVersion, flatcarAWSIAMAuthenticatorVersion, b.clusterConfig.Metadata.Region)
+	for _, arch := range flatcarArchitectures {
+		kubeletSHA256, ok := flatcarKubeletSHA256[kubeletVersion][arch]
+		if !ok {
+			return cloudconfig.File{}, fmt.Errorf("no SHA-256 checksum of kubelet %s for %s is pinned for Flatcar", kubeletVersion, arch)
+		}
+		authenticatorSHA256, ok := flatcarAWSIAMAuthenticatorSHA256[arch]
+		if !ok {
+			return cloudconfig.File{}, fmt.Errorf("no SHA-256 checksum of aws-iam-authenticator %s for %s is pinned for Flatcar", flatcarAWSIAMAuthenticatorVersion, arch)
+		}
+		envFile.Content += fmt.Sprintf("\nKUBELET_SHA256_%[1]s=%[2]s\nAWS_IAM_AUTHENTICATOR_SHA256_%[1]s=%[3]s", strings.ToUpper(arch), kubeletSHA256, authenticatorSHA256)
+	}
+	if maxPods, ok := flatcarMaxPods(b.np); ok {
+		envFile.Content += fmt.Sprintf("\nMAX_PODS=%d", maxPods)
+	}
+	if _, ok := b.np.(*api.ManagedNodeGroup); ok {
+		clusterDNS, err := GetClusterDNS(b.clusterConfig)
+		if err != nil {
+			return cloudconfig.File{}, err
+		}
+		if clusterDNS != "" {
+			envFile.Content += "\nCLUSTER_DNS=" + clusterDNS
+		}
+	}
+	return envFile, nil
+}
+
+// flatcarMaxPods returns the maximum number of pods of the nodegroup's nodes when maxPodsPerNode is not set,
+// which is the smallest maximum of its instance types, as Flatcar does not know the maximum of each instance type
+func flatcarMaxPods(np api.NodePool) (int, bool) {
+	if np.BaseNodeGroup().MaxPodsPerNode > 0 {
+		return 0, false
+	}
+	withInstanceTypes, ok := np.(interface{ InstanceTypeList() []string })
+	if !ok {
+		return 0, false
+	}
+	minMaxPods := 0
+	for _, instanceType := range withInstanceTypes.InstanceTypeList() {
+		if instanceType == "" {
+			return 0, false
+		}
+		maxPods, ok := legacy.MaxPodsPerNodeType(instanceType)
+		if !ok {
+			logger.Warning("the maximum number of pods of instance type %q is not known, set maxPodsPerNode for Flatcar nodegroup %q", instanceType, np.BaseNodeGroup().Name)
+			return 0, false
+		}
+		if minMaxPods == 0 || maxPods < minMaxPods {
+			minMaxPods = maxPods
+		}
+	}
+	return minMaxPods, minMaxPods > 0
+}
+
+func makeFlatcarScript(commands ...string) string {
+	return fmt.Sprintf("#!/bin/bash\n\nset -o errexit\nset -o pipefail\n\n%s\n", strings.Join(commands, "\n"))
+}
+
+func makeFlatcarBootUnit(execStartPre, execStart string) string {
+	if execStartPre != "" {
+		execStartPre = fmt.Sprintf("ExecStartPre=%s\n", execStartPre)
+	}
+	return fmt.Sprintf(`[Unit]
+Description=Bootstrap the node into the EKS cluster
+Wants=network-online.target
+After=network-online.target
+ConditionPathExists=!%[1]s
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+%[2]sExecStart=%[3]s
+ExecStartPost=/usr/bin/touch %[1]s
+
+[Install]
+WantedBy=multi-user.target
+`, flatcarBootstrapped, execStartPre, execStart)
+}
@@ -0,0 +1,226 @@
+package nodebootstrap_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap/assets"
+)
+
+type ignitionConfig struct {
+	Ignition struct {
+		Version string `json:"version"`
+	} `json:"ignition"`
+	Storage struct {
+		Files []struct {
+			Path     string `json:"path"`
+			Mode     int    `json:"mode"`
+			Contents struct {
+				Source string `json:"source"`
+			} `json:"contents"`
+		} `json:"files"`
+	} `json:"storage"`
+	Systemd struct {
+		Units []struct {
+			Name     string `json:"name"`
+			Enabled  bool   `json:"enabled"`
+			Contents string `json:"contents"`
+		} `json:"units"`
+	} `json:"systemd"`
+}
+
+var _ = Describe("Flatcar", func() {
+	var (
+		clusterConfig    *api.ClusterConfig
+		restoreChecksums func()
+	)
+
+	BeforeEach(func() {
+		restoreChecksums = nodebootstrap.SetFlatcarChecksums(map[string]map[string]string{
+			"v1.21.14": {"amd64": "kubelet-amd64-sha256", "arm64": "kubelet-arm64-sha256"},
+		}, map[string]string{"amd64": "authenticator-amd64-sha256", "arm64": "authenticator-arm64-sha256"})
+		clusterConfig = api.NewClusterConfig()
+		clusterConfig.Metadata.Name = "flatcar-cluster"
+		clusterConfig.Metadata.Region = "us-west-2"
+		clusterConfig.Metadata.Version = "1.21"
+		clusterConfig.Status = &api.ClusterStatus{
+			Endpoint:                 "https://test.example.com",
+			CertificateAuthorityData: []byte("CertificateAuthorityData"),
+			KubernetesNetworkConfig: &api.KubernetesNetworkConfig{
+				ServiceIPv4CIDR: "10.100.0.0/16",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		restoreChecksums()
+	})
+
+	// render returns the Ignition config of the nodegroup and the decoded contents of its files
+	render := func(np api.NodePool) (ignitionConfig, map[string]string) {
+		userData, err := nodebootstrap.NewFlatcarBootstrapper(clusterConfig, np).UserData()
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := base64.StdEncoding.DecodeString(userData)
+		Expect(err).NotTo(HaveOccurred())
+
+		var config ignitionConfig
+		Expect(json.Unmarshal(decoded, &config)).To(Succeed())
+		Expect(config.Ignition.Version).To(Equal("3.3.0"))
+
+		files := map[string]string{}
+		for _, f := range config.Storage.Files {
+			Expect(f.Contents.Source).To(HavePrefix("data:;base64,"))
+			content, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(f.Contents.Source, "data:;base64,"))
+			Expect(err).NotTo(HaveOccurred())
+			files[f.Path] = string(content)
+		}
+		return config, files
+	}
+
+	Context("unmanaged nodegroup", func() {
+		var ng *api.NodeGroup
+
+		BeforeEach(func() {
+			ng = api.NewNodeGroup()
+			ng.Name = "flatcar"
+			ng.AMIFamily = api.NodeImageFamilyFlatcar
+			ng.Labels = map[string]string{"role": "worker"}
+			ng.Taints = []api.NodeGroupTaint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}}
+			ng.MaxPodsPerNode = 20
+			ng.ClusterDNS = "10.100.0.10"
+			ng.KubeletExtraConfig = &api.InlineDocument{"kubeReserved": map[string]interface{}{"cpu": "300m"}}
+		})
+
+		It("writes the bootstrap files and enables a unit that runs the bootstrap script once", func() {
+			config, files := render(ng)
+
+			Expect(files).To(HaveKeyWithValue("/etc/eksctl/kubelet-extra.json", `{"kubeReserved":{"cpu":"300m"}}`))
+			Expect(files).To(HaveKeyWithValue("/opt/eksctl/bootstrap.helper.sh", assets.BootstrapHelperSh))
+			Expect(files).To(HaveKeyWithValue("/opt/eksctl/bootstrap.flatcar.sh", assets.BootstrapFlatcarSh))
+			Expect(strings.Split(files["/etc/eksctl/kubelet.env"], "\n")).To(ContainElements(
+				"CLUSTER_NAME=flatcar-cluster",
+				"API_SERVER_URL=https://test.example.com",
+				"NODE_LABELS=role=worker",
+				"NODE_TAINTS=dedicated=gpu:NoSchedule",
+				"MAX_PODS=20",
+				"CLUSTER_DNS=10.100.0.10",
+				"KUBELET_VERSION=v1.21.14",
+				"AWS_IAM_AUTHENTICATOR_VERSION=0.5.9",
+				"AWS_REGION=us-west-2",
+				"KUBELET_SHA256_AMD64=kubelet-amd64-sha256",
+				"KUBELET_SHA256_ARM64=kubelet-arm64-sha256",
+				"AWS_IAM_AUTHENTICATOR_SHA256_AMD64=authenticator-amd64-sha256",
+				"AWS_IAM_AUTHENTICATOR_SHA256_ARM64=authenticator-arm64-sha256",
+			))
+
+			Expect(config.Systemd.Units).To(HaveLen(2))
+			unit := config.Systemd.Units[0]
+			Expect(unit.Name).To(Equal("eksctl-bootstrap.service"))
+			Expect(unit.Enabled).To(BeTrue())
+			Expect(unit.Contents).To(ContainSubstring("ExecStart=/opt/eksctl/bootstrap.flatcar.sh\n"))
+			Expect(unit.Contents).To(ContainSubstring("ConditionPathExists=!/etc/eksctl/bootstrapped\n"))
+			Expect(unit.Contents).NotTo(ContainSubstring("ExecStartPre"))
+
+			kubeletUnit := config.Systemd.Units[1]
+			Expect(kubeletUnit.Name).To(Equal("kubelet.service"))
+			Expect(kubeletUnit.Enabled).To(BeFalse())
+			Expect(kubeletUnit.Contents).To(Equal(assets.KubeletFlatcarService))
+		})
+
+		It("does not depend on the EKS bootstrap files of Amazon Linux", func() {
+			config, files := render(ng)
+
+			for path, content := range files {
+				for _, amazonLinuxPath := range []string{"/usr/share/amazon/", "/etc/eks/"} {
+					Expect(content).NotTo(ContainSubstring(amazonLinuxPath), "file %s", path)
+				}
+			}
+			for _, unit := range config.Systemd.Units {
+				for _, amazonLinuxPath := range []string{"/usr/share/amazon/", "/etc/eks/"} {
+					Expect(unit.Contents).NotTo(ContainSubstring(amazonLinuxPath), "unit %s", unit.Name)
+				}
+			}
+			Expect(files["/opt/eksctl/bootstrap.flatcar.sh"]).To(ContainSubstring("https://dl.k8s.io/release/"))
+			Expect(files["/opt/eksctl/bootstrap.flatcar.sh"]).To(ContainSubstring("sha256sum --check"))
+			Expect(files["/opt/eksctl/bootstrap.flatcar.sh"]).NotTo(ContainSubstring(".sha256"))
+			Expect(files["/opt/eksctl/bootstrap.flatcar.sh"]).NotTo(ContainSubstring("checksums.txt"))
+			Expect(files["/opt/eksctl/bootstrap.flatcar.sh"]).NotTo(ContainSubstring("stable-"))
+			Expect(config.Systemd.Units[1].Contents).To(ContainSubstring("ExecStart=/opt/bin/kubelet"))
+		})
+
+		It("fails when no kubelet release is pinned for the Kubernetes version", func() {
+			clusterConfig.Metadata.Version = "1.10"
+			_, err := nodebootstrap.NewFlatcarBootstrapper(clusterConfig, ng).UserData()
+			Expect(err).To(MatchError(`no kubelet release is known for Kubernetes version "1.10" on Flatcar`))
+		})
+
+		It("fails when the checksums of the binaries are not pinned for all architectures", func() {
+			nodebootstrap.SetFlatcarChecksums(map[string]map[string]string{
+				"v1.21.14": {"amd64": "kubelet-amd64-sha256"},
+			}, map[string]string{"amd64": "authenticator-amd64-sha256", "arm64": "authenticator-arm64-sha256"})
+			_, err := nodebootstrap.NewFlatcarBootstrapper(clusterConfig, ng).UserData()
+			Expect(err).To(MatchError("no SHA-256 checksum of kubelet v1.21.14 for arm64 is pinned for Flatcar"))
+
+			nodebootstrap.SetFlatcarChecksums(map[string]map[string]string{
+				"v1.21.14": {"amd64": "kubelet-amd64-sha256", "arm64": "kubelet-arm64-sha256"},
+			}, map[string]string{"amd64": "authenticator-amd64-sha256"})
+			_, err = nodebootstrap.NewFlatcarBootstrapper(clusterConfig, ng).UserData()
+			Expect(err).To(MatchError("no SHA-256 checksum of aws-iam-authenticator 0.5.9 for arm64 is pinned for Flatcar"))
+		})
+
+		It("sets max pods to the smallest maximum of the instance types when maxPodsPerNode is not set", func() {
+			ng.MaxPodsPerNode = 0
+			ng.InstanceType = "mixed"
+			ng.InstancesDistribution = &api.NodeGroupInstancesDistribution{InstanceTypes: []string{"m5.xlarge", "m5.large"}}
+
+			_, files := render(ng)
+			Expect(strings.Split(files["/etc/eksctl/kubelet.env"], "\n")).To(ContainElement("MAX_PODS=29"))
+		})
+
+		It("runs preBootstrapCommands before the bootstrap script", func() {
+			ng.PreBootstrapCommands = []string{"echo one", "echo two"}
+			config, files := render(ng)
+
+			Expect(files).To(HaveKeyWithValue("/opt/eksctl/pre-bootstrap.sh", "#!/bin/bash\n\nset -o errexit\nset -o pipefail\n\necho one\necho two\n"))
+			Expect(config.Systemd.Units[0].Contents).To(ContainSubstring("ExecStartPre=/opt/eksctl/pre-bootstrap.sh\nExecStart=/opt/eksctl/bootstrap.flatcar.sh\n"))
+		})
+
+		It("runs overrideBootstrapCommand in place of the bootstrap script", func() {
+			ng.OverrideBootstrapCommand = aws.String("echo override")
+			config, files := render(ng)
+
+			Expect(files).To(HaveLen(1))
+			Expect(files).To(HaveKeyWithValue("/opt/eksctl/bootstrap.override.sh", "#!/bin/bash\n\nset -o errexit\nset -o pipefail\n\necho override\n"))
+			Expect(config.Systemd.Units).To(HaveLen(1))
+			Expect(config.Systemd.Units[0].Contents).To(ContainSubstring("ExecStart=/opt/eksctl/bootstrap.override.sh\n"))
+		})
+	})
+
+	Context("managed nodegroup with a custom AMI", func() {
+		It("derives the cluster DNS and sets the labels, taints and max pods", func() {
+			ng := api.NewManagedNodeGroup()
+			ng.Name = "flatcar"
+			ng.AMIFamily = api.NodeImageFamilyFlatcar
+			ng.AMI = "ami-custom"
+			ng.Labels = map[string]string{"role": "worker"}
+			ng.Taints = []api.NodeGroupTaint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}}
+			ng.MaxPodsPerNode = 30
+
+			_, files := render(ng)
+			Expect(files).To(HaveKeyWithValue("/etc/eksctl/kubelet-extra.json", "{}"))
+			Expect(strings.Split(files["/etc/eksctl/kubelet.env"], "\n")).To(ContainElements(
+				"NODE_LABELS=role=worker",
+				"NODE_TAINTS=dedicated=gpu:NoSchedule",
+				"MAX_PODS=30",
+				"CLUSTER_DNS=10.100.0.10",
+			))
+		})
+	})
+})
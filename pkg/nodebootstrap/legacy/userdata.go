@@ -173,3 +173,9 @@ func makeMaxPodsMapping() string {
 	}
 	return text.String()
 }
+
+// MaxPodsPerNodeType returns the maximum number of pods of an instance type, as published for the EKS AMIs
+func MaxPodsPerNodeType(instanceType string) (int, bool) {
+	maxPods, ok := maxPodsPerNodeType[instanceType]
+	return maxPods, ok
+}
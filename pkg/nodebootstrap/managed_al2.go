@@ -60,7 +60,7 @@ func (m *ManagedAL2) UserData() (string, error) {
 		return "", nil
 	}
 
-	if err := createMimeMessage(&buf, scripts, cloudboot, m.UserDataMimeBoundary); err != nil {
+	if err := createMimeMessage(&buf, scripts, cloudboot, "", m.UserDataMimeBoundary); err != nil {
 		return "", err
 	}
 
@@ -85,7 +85,7 @@ func makeCustomAMIUserData(ng *api.NodeGroupBase, mimeBoundary string) (string,
 		return "", nil
 	}
 
-	if err := createMimeMessage(&buf, scripts, nil, mimeBoundary); err != nil {
+	if err := createMimeMessage(&buf, scripts, nil, "", mimeBoundary); err != nil {
 		return "", err
 	}
 
@@ -102,7 +102,9 @@ KUBELET_CONFIG=/etc/kubernetes/kubelet/kubelet-config.json
 	return script
 }
 
-func createMimeMessage(writer io.Writer, scripts, cloudboots []string, mimeBoundary string) error {
+// createMimeMessage writes a multi-part MIME document with the given shell scripts, cloud-boothooks
+// and nodeadm NodeConfig, which is left out if empty
+func createMimeMessage(writer io.Writer, scripts, cloudboots []string, nodeConfig string, mimeBoundary string) error {
 	mw := multipart.NewWriter(writer)
 	if mimeBoundary != "" {
 		if err := mw.SetBoundary(mimeBoundary); err != nil {
@@ -138,5 +140,18 @@ func createMimeMessage(writer io.Writer, scripts, cloudboots []string, mimeBound
 			return err
 		}
 	}
+	if nodeConfig != "" {
+		part, err := mw.CreatePart(map[string][]string{
+			"Content-Type": {nodeConfigContentType},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if _, err = part.Write([]byte(nodeConfig)); err != nil {
+			return err
+		}
+	}
 	return mw.Close()
 }
@@ -0,0 +1,127 @@
+package nodebootstrap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// DerivedSetting is a node setting that goes into the userdata of a nodegroup, along with where it came from
+type DerivedSetting struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// DerivedSettings returns the kubelet extra config, labels, taints, max pods and cluster DNS settings
+// that go into the userdata of a nodegroup; it must be called before the nodegroup's bootstrapper
+// is created, as NewBootstrapper sets the cluster DNS of the nodegroup
+func DerivedSettings(clusterConfig *api.ClusterConfig, np api.NodePool) ([]DerivedSetting, error) {
+	ng := np.BaseNodeGroup()
+	unmanaged, isUnmanaged := np.(*api.NodeGroup)
+	// managed nodegroups using an AMI type supported by EKS are bootstrapped by EKS, the rest by eksctl
+	bootstrappedByEKS := !isUnmanaged && (ng.AMIFamily == api.NodeImageFamilyAmazonLinux2 || ng.AMIFamily == api.NodeImageFamilyBottlerocket)
+
+	var settings []DerivedSetting
+	add := func(name, value, source string) {
+		settings = append(settings, DerivedSetting{Name: name, Value: value, Source: source})
+	}
+
+	switch {
+	case isUnmanaged && unmanaged.ClusterDNS != "":
+		add("clusterDNS", unmanaged.ClusterDNS, "clusterDNS of the nodegroup")
+	case !bootstrappedByEKS:
+		clusterDNS, err := GetClusterDNS(clusterConfig)
+		if err != nil {
+			return nil, err
+		}
+		if clusterDNS != "" {
+			add("clusterDNS", clusterDNS, fmt.Sprintf("10th address of the service IPv4 CIDR %s", clusterConfig.Status.KubernetesNetworkConfig.ServiceIPv4CIDR))
+		} else {
+			add("clusterDNS", "", "not set, chosen on the node based on the VPC CIDR")
+		}
+	default:
+		add("clusterDNS", "", "not set, chosen on the node based on the VPC CIDR")
+	}
+
+	maxPods, isFlatcarMaxPods := 0, false
+	if ng.MaxPodsPerNode == 0 && ng.AMIFamily == api.NodeImageFamilyFlatcar {
+		maxPods, isFlatcarMaxPods = flatcarMaxPods(np)
+	}
+	switch {
+	case ng.MaxPodsPerNode > 0:
+		add("maxPods", strconv.Itoa(ng.MaxPodsPerNode), "maxPodsPerNode of the nodegroup")
+	case isFlatcarMaxPods:
+		add("maxPods", strconv.Itoa(maxPods), "smallest maximum of the instance types of the nodegroup")
+	default:
+		add("maxPods", "", "not set, the maximum for the instance type is used")
+	}
+
+	if isUnmanaged && unmanaged.KubeletExtraConfig != nil {
+		data, err := json.Marshal(unmanaged.KubeletExtraConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "encoding kubeletExtraConfig")
+		}
+		add("kubeletExtraConfig", string(data), "kubeletExtraConfig of the nodegroup")
+	}
+
+	if isUnmanaged && ng.AMIFamily == api.NodeImageFamilyAmazonLinux2 {
+		add("containerRuntime", unmanaged.GetContainerRuntime(), "containerRuntime of the nodegroup")
+	}
+
+	// the labels and taints of nodegroups bootstrapped by EKS are set on the EKS nodegroup rather than in the userdata
+	managedSuffix := ""
+	if bootstrappedByEKS {
+		managedSuffix = ", applied by EKS"
+	}
+
+	labelKeys := make([]string, 0, len(ng.Labels))
+	for k := range ng.Labels {
+		labelKeys = append(labelKeys, k)
+	}
+	sort.Strings(labelKeys)
+	for _, k := range labelKeys {
+		source := "labels of the nodegroup"
+		if k == api.ClusterNameLabel || k == api.NodeGroupNameLabel {
+			source = "added by eksctl"
+		}
+		add("label", fmt.Sprintf("%s=%s", k, ng.Labels[k]), source+managedSuffix)
+	}
+
+	for _, t := range np.NGTaints() {
+		add("taint", fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect), "taints of the nodegroup"+managedSuffix)
+	}
+	return settings, nil
+}
+
+// DecodeUserData decodes the userdata returned by a bootstrapper into what the node sees;
+// all userdata is base64 encoded, and cloud-config userdata is also gzipped
+func DecodeUserData(userData string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(userData)
+	if err != nil {
+		return "", errors.Wrap(err, "decoding base64 userdata")
+	}
+	if !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		return string(data), nil
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", errors.Wrap(err, "decompressing userdata")
+	}
+	defer gr.Close()
+	data, err = io.ReadAll(gr)
+	if err != nil {
+		return "", errors.Wrap(err, "decompressing userdata")
+	}
+	return string(data), nil
+}
@@ -0,0 +1,101 @@
+package nodebootstrap_test
+
+import (
+	"encoding/base64"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
+)
+
+var _ = Describe("Rendering userdata", func() {
+	var clusterConfig *api.ClusterConfig
+
+	BeforeEach(func() {
+		clusterConfig = api.NewClusterConfig()
+		clusterConfig.Metadata.Name = "something-awesome"
+		clusterConfig.Status = &api.ClusterStatus{
+			KubernetesNetworkConfig: &api.KubernetesNetworkConfig{
+				ServiceIPv4CIDR: "10.100.0.0/16",
+			},
+		}
+	})
+
+	It("decodes gzipped cloud-config userdata", func() {
+		ng := api.NewNodeGroup()
+		ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+		userData, err := nodebootstrap.NewAL2Bootstrapper(clusterConfig, ng).UserData()
+		Expect(err).NotTo(HaveOccurred())
+
+		decoded, err := nodebootstrap.DecodeUserData(userData)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(HavePrefix("#cloud-config"))
+	})
+
+	It("decodes userdata that is only base64 encoded", func() {
+		decoded, err := nodebootstrap.DecodeUserData(base64.StdEncoding.EncodeToString([]byte("<powershell></powershell>")))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(Equal("<powershell></powershell>"))
+
+		decoded, err = nodebootstrap.DecodeUserData("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(BeEmpty())
+	})
+
+	It("reports the settings derived for an unmanaged nodegroup and where they came from", func() {
+		ng := api.NewNodeGroup()
+		ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+		ng.ContainerRuntime = aws.String(api.ContainerRuntimeDockerD)
+		ng.MaxPodsPerNode = 20
+		ng.Labels = map[string]string{
+			"role":                 "worker",
+			api.NodeGroupNameLabel: "ng-1",
+		}
+		ng.Taints = []api.NodeGroupTaint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}}
+		ng.KubeletExtraConfig = &api.InlineDocument{"kubeReserved": map[string]string{"cpu": "300m"}}
+
+		settings, err := nodebootstrap.DerivedSettings(clusterConfig, ng)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings).To(Equal([]nodebootstrap.DerivedSetting{
+			{Name: "clusterDNS", Value: "10.100.0.10", Source: "10th address of the service IPv4 CIDR 10.100.0.0/16"},
+			{Name: "maxPods", Value: "20", Source: "maxPodsPerNode of the nodegroup"},
+			{Name: "kubeletExtraConfig", Value: `{"kubeReserved":{"cpu":"300m"}}`, Source: "kubeletExtraConfig of the nodegroup"},
+			{Name: "containerRuntime", Value: "dockerd", Source: "containerRuntime of the nodegroup"},
+			{Name: "label", Value: "alpha.eksctl.io/nodegroup-name=ng-1", Source: "added by eksctl"},
+			{Name: "label", Value: "role=worker", Source: "labels of the nodegroup"},
+			{Name: "taint", Value: "dedicated=gpu:NoSchedule", Source: "taints of the nodegroup"},
+		}))
+	})
+
+	It("reports the settings of a managed nodegroup that are left to the node or to EKS", func() {
+		ng := api.NewManagedNodeGroup()
+		ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+		ng.Labels = map[string]string{"role": "worker"}
+
+		settings, err := nodebootstrap.DerivedSettings(clusterConfig, ng)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings).To(Equal([]nodebootstrap.DerivedSetting{
+			{Name: "clusterDNS", Source: "not set, chosen on the node based on the VPC CIDR"},
+			{Name: "maxPods", Source: "not set, the maximum for the instance type is used"},
+			{Name: "label", Value: "role=worker", Source: "labels of the nodegroup, applied by EKS"},
+		}))
+	})
+
+	It("reports the settings of a managed nodegroup that is bootstrapped by eksctl", func() {
+		ng := api.NewManagedNodeGroup()
+		ng.AMIFamily = api.NodeImageFamilyFlatcar
+		ng.AMI = "ami-custom"
+		ng.InstanceType = "m5.large"
+		ng.Labels = map[string]string{"role": "worker"}
+
+		settings, err := nodebootstrap.DerivedSettings(clusterConfig, ng)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings).To(Equal([]nodebootstrap.DerivedSetting{
+			{Name: "clusterDNS", Value: "10.100.0.10", Source: "10th address of the service IPv4 CIDR 10.100.0.0/16"},
+			{Name: "maxPods", Value: "29", Source: "smallest maximum of the instance types of the nodegroup"},
+			{Name: "label", Value: "role=worker", Source: "labels of the nodegroup"},
+		}))
+	})
+})
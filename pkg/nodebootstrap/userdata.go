@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"path"
 	"strconv"
 	"strings"
 
@@ -26,6 +27,8 @@ const (
 	envFile               = "kubelet.env"
 	extraKubeConfFile     = "kubelet-extra.json"
 	commonLinuxBootScript = "bootstrap.helper.sh"
+	warmPoolBootScript    = "bootstrap.warmpool.sh"
+	warmPoolBootService   = "/etc/systemd/system/eksctl-bootstrap.service"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -65,6 +68,10 @@ func NewBootstrapper(clusterConfig *api.ClusterConfig, ng *api.NodeGroup) (Boots
 			return legacy.NewAL2Bootstrapper(clusterConfig, ng), nil
 		}
 		return NewAL2Bootstrapper(clusterConfig, ng), nil
+	case api.NodeImageFamilyAmazonLinux2022:
+		return NewAL2022Bootstrapper(clusterConfig, ng), nil
+	case api.NodeImageFamilyFlatcar:
+		return NewFlatcarBootstrapper(clusterConfig, ng), nil
 	default:
 		return nil, errors.Errorf("unrecognized AMI family %q for creating bootstrapper", ng.AMIFamily)
 
@@ -80,6 +87,10 @@ func NewManagedBootstrapper(clusterConfig *api.ClusterConfig, ng *api.ManagedNod
 		return NewManagedBottlerocketBootstrapper(clusterConfig, ng)
 	case api.NodeImageFamilyUbuntu1804, api.NodeImageFamilyUbuntu2004:
 		return NewUbuntuBootstrapper(clusterConfig, ng)
+	case api.NodeImageFamilyAmazonLinux2022:
+		return NewAL2022Bootstrapper(clusterConfig, ng)
+	case api.NodeImageFamilyFlatcar:
+		return NewFlatcarBootstrapper(clusterConfig, ng)
 	}
 	return nil
 }
@@ -130,7 +141,9 @@ func linuxConfig(clusterConfig *api.ClusterConfig, bootScriptName, bootScriptCon
 		files = append(files, envFile)
 	}
 
-	if err := addFilesAndScripts(config, files, scripts); err != nil {
+	if unmanaged, ok := np.(*api.NodeGroup); ok && unmanaged.WarmPool != nil && ng.OverrideBootstrapCommand == nil {
+		addWarmPoolBootstrap(config, files, scripts)
+	} else if err := addFilesAndScripts(config, files, scripts); err != nil {
 		return "", err
 	}
 
@@ -208,6 +221,55 @@ type script struct {
 	contents string
 }
 
+// addWarmPoolBootstrap adds the files and scripts, and a systemd unit that runs the scripts at boot once the
+// instance leaves the warm pool of the nodegroup, as the instances launched into it must not join the cluster
+func addWarmPoolBootstrap(config *cloudconfig.CloudConfig, files []cloudconfig.File, scripts []script) {
+	for _, file := range files {
+		config.AddFile(file)
+	}
+
+	execStart := []string{cloudconfig.ScriptPath(warmPoolBootScript)}
+	config.AddScript(execStart[0], assets.BootstrapWarmpoolSh)
+	for _, s := range scripts {
+		p := cloudconfig.ScriptPath(s.name)
+		config.AddScript(p, s.contents)
+		execStart = append(execStart, p)
+	}
+
+	config.AddFile(cloudconfig.File{
+		Path: warmPoolBootService,
+		Content: fmt.Sprintf(`[Unit]
+Description=Bootstrap the node once the instance leaves the warm pool
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=simple
+ExecStart=%s
+
+[Install]
+WantedBy=multi-user.target
+`, strings.Join(execStart, " ")),
+	})
+	config.AddCommand("systemctl", "daemon-reload")
+	config.AddCommand("systemctl", "enable", "--now", "--no-block", path.Base(warmPoolBootService))
+}
+
+// BootstrapsAfterWarmPool reports whether nodes launched with the userdata are only bootstrapped
+// once they leave the warm pool of their nodegroup
+func BootstrapsAfterWarmPool(userData string) bool {
+	config, err := cloudconfig.DecodeCloudConfig(userData)
+	if err != nil {
+		return false
+	}
+	for _, file := range config.WriteFiles {
+		if file.Path == warmPoolBootService {
+			return true
+		}
+	}
+	return false
+}
+
 func addFilesAndScripts(config *cloudconfig.CloudConfig, files []cloudconfig.File, scripts []script) error {
 	for _, file := range files {
 		config.AddFile(file)
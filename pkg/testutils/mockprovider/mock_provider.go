@@ -14,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
 	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 
@@ -62,6 +63,7 @@ type MockProvider struct {
 	sts            *mocks.STSAPI
 	ssm            *mocks.SSMAPI
 	iam            *mocks.IAMAPI
+	s3             *mocks.S3API
 	cloudtrail     *mocks.CloudTrailAPI
 	cloudwatchlogs *mocks.CloudWatchLogsAPI
 	configProvider *mocks.ConfigProvider
@@ -81,6 +83,7 @@ func NewMockProvider() *MockProvider {
 		sts:            &mocks.STSAPI{},
 		ssm:            &mocks.SSMAPI{},
 		iam:            &mocks.IAMAPI{},
+		s3:             &mocks.S3API{},
 		cloudtrail:     &mocks.CloudTrailAPI{},
 		cloudwatchlogs: &mocks.CloudWatchLogsAPI{},
 		configProvider: &mocks.ConfigProvider{},
@@ -98,6 +101,21 @@ func (m MockProvider) CloudFormationDisableRollback() bool {
 	return false
 }
 
+// CloudFormationTemplateBucket returns the S3 bucket that large templates are uploaded to, if set
+func (m MockProvider) CloudFormationTemplateBucket() string {
+	return ""
+}
+
+// StreamStackEvents returns whether stack events should be printed while waiting for stacks
+func (m MockProvider) StreamStackEvents() bool {
+	return false
+}
+
+// MaxParallelStacks returns the maximum number of stacks that are created or deleted at the same time
+func (m MockProvider) MaxParallelStacks() int {
+	return 0
+}
+
 // MockCloudFormation returns a mocked CloudFormation API
 func (m MockProvider) MockCloudFormation() *mocks.CloudFormationAPI {
 	return m.CloudFormation().(*mocks.CloudFormationAPI)
@@ -145,6 +163,12 @@ func (m MockProvider) IAM() iamiface.IAMAPI { return m.iam }
 // MockIAM returns a mocked IAM API
 func (m MockProvider) MockIAM() *mocks.IAMAPI { return m.IAM().(*mocks.IAMAPI) }
 
+// S3 returns a representation of the S3 API
+func (m MockProvider) S3() s3iface.S3API { return m.s3 }
+
+// MockS3 returns a mocked S3 API
+func (m MockProvider) MockS3() *mocks.S3API { return m.S3().(*mocks.S3API) }
+
 // CloudTrail returns a representation of the CloudTrail API
 func (m MockProvider) CloudTrail() cloudtrailiface.CloudTrailAPI { return m.cloudtrail }
 
@@ -0,0 +1,146 @@
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// EventType is the type of an Event
+type EventType string
+
+// Values for `EventType`
+const (
+	TaskStarted    EventType = "task_started"
+	TaskFinished   EventType = "task_finished"
+	TaskSkipped    EventType = "task_skipped"
+	StackStatus    EventType = "stack_status"
+	WaiterPoll     EventType = "waiter_poll"
+	WaiterFinished EventType = "waiter_finished"
+	EKSUpdate      EventType = "eks_update"
+	Retry          EventType = "retry"
+	Result         EventType = "result"
+)
+
+// Event is a machine-readable progress event, which is written as a single line of JSON
+type Event struct {
+	Timestamp   time.Time `json:"timestamp"`
+	OperationID string    `json:"operationID"`
+	Type        EventType `json:"type"`
+
+	// Task is the description of the task, for task events
+	Task string `json:"task,omitempty"`
+	// Stack, Resource and ResourceType identify the stack or stack resource whose status changed
+	Stack        string `json:"stack,omitempty"`
+	Resource     string `json:"resource,omitempty"`
+	ResourceType string `json:"resourceType,omitempty"`
+	// Waiter is the name of the waiter, for waiter events
+	Waiter string `json:"waiter,omitempty"`
+	// Cluster, UpdateID and UpdateType identify an EKS update
+	Cluster    string `json:"cluster,omitempty"`
+	UpdateID   string `json:"updateID,omitempty"`
+	UpdateType string `json:"updateType,omitempty"`
+	// Operation is the AWS API operation that is retried, e.g. CloudFormation/DescribeStacks
+	Operation string `json:"operation,omitempty"`
+
+	Status          string  `json:"status,omitempty"`
+	Reason          string  `json:"reason,omitempty"`
+	Attempt         int     `json:"attempt,omitempty"`
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+	Error           string  `json:"error,omitempty"`
+	// Cause is the root cause of Error
+	Cause string `json:"cause,omitempty"`
+}
+
+// stream writes the events of the current operation
+type stream struct {
+	encoder     *json.Encoder
+	closer      io.Closer
+	operationID string
+}
+
+var (
+	mutex   sync.Mutex
+	current *stream
+)
+
+// Open starts writing events to path, or to stdout when path is "-"; events
+// are only written once the stream has been opened
+func Open(path string) error {
+	if path == "-" {
+		Start(os.Stdout, nil)
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "opening events file")
+	}
+	Start(f, f)
+	return nil
+}
+
+// Start starts writing events to w with a new operation ID, closer is closed by Close when set
+func Start(w io.Writer, closer io.Closer) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = &stream{
+		encoder:     json.NewEncoder(w),
+		closer:      closer,
+		operationID: uuid.New().String(),
+	}
+}
+
+// Close stops writing events
+func Close() error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	s := current
+	current = nil
+	if s == nil || s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// Enabled returns true when events are written, so that callers can skip building events otherwise
+func Enabled() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return current != nil
+}
+
+// Emit writes the event, setting its timestamp and operation ID
+func Emit(e Event) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if current == nil {
+		return
+	}
+	e.Timestamp = time.Now().UTC()
+	e.OperationID = current.operationID
+	// events must never fail the operation
+	_ = current.encoder.Encode(e)
+}
+
+// WithError sets the error of the event, along with its root cause
+func (e Event) WithError(err error) Event {
+	if err != nil {
+		e.Error = err.Error()
+		e.Cause = errors.Cause(err).Error()
+	}
+	return e
+}
+
+// EmitResult writes the final result of the operation
+func EmitResult(err error) {
+	status := "succeeded"
+	if err != nil {
+		status = "failed"
+	}
+	Emit(Event{Type: Result, Status: status}.WithError(err))
+}
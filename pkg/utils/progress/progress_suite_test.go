@@ -0,0 +1,11 @@
+package progress
+
+import (
+	"testing"
+
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+func TestProgress(t *testing.T) {
+	testutils.RegisterAndRun(t)
+}
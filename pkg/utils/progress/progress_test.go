@@ -0,0 +1,68 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Progress events", func() {
+	var buf *bytes.Buffer
+
+	decode := func() []Event {
+		var events []Event
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var e Event
+			Expect(json.Unmarshal([]byte(line), &e)).To(Succeed())
+			events = append(events, e)
+		}
+		return events
+	}
+
+	BeforeEach(func() {
+		buf = new(bytes.Buffer)
+	})
+
+	AfterEach(func() {
+		Expect(Close()).To(Succeed())
+	})
+
+	It("does not write events until the stream is started", func() {
+		Expect(Enabled()).To(BeFalse())
+		Emit(Event{Type: TaskStarted, Task: "t1"})
+		Expect(buf.Len()).To(Equal(0))
+	})
+
+	It("writes events with a timestamp and an operation ID as JSON lines", func() {
+		Start(buf, nil)
+		Expect(Enabled()).To(BeTrue())
+
+		Emit(Event{Type: TaskStarted, Task: "create nodegroup \"ng-1\""})
+		Emit(Event{Type: StackStatus, Stack: "eksctl-test-nodegroup-ng-1", Status: "CREATE_IN_PROGRESS"})
+		EmitResult(errors.Wrap(errors.New("stack failed"), "creating nodegroup"))
+
+		events := decode()
+		Expect(events).To(HaveLen(3))
+		Expect(events[0].OperationID).NotTo(BeEmpty())
+		for _, e := range events {
+			Expect(e.OperationID).To(Equal(events[0].OperationID))
+			Expect(e.Timestamp.IsZero()).To(BeFalse())
+		}
+		Expect(events[1].Stack).To(Equal("eksctl-test-nodegroup-ng-1"))
+		Expect(events[2].Type).To(Equal(Result))
+		Expect(events[2].Status).To(Equal("failed"))
+		Expect(events[2].Error).To(Equal("creating nodegroup: stack failed"))
+		Expect(events[2].Cause).To(Equal("stack failed"))
+	})
+
+	It("omits unset fields", func() {
+		Start(buf, nil)
+		EmitResult(nil)
+		Expect(buf.String()).NotTo(ContainSubstring("error"))
+		Expect(buf.String()).To(ContainSubstring(`"type":"result","status":"succeeded"`))
+	})
+})
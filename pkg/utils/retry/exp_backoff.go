@@ -39,12 +39,15 @@ func (b ExponentialBackoff) Clone() Policy {
 }
 
 // TimingOutExponentialBackoff defines a retry policy in which we exponentially
-// retry up to the provided maximum duration (Timeout).
+// retry up to the provided maximum duration (Timeout). The timeout is measured in
+// wall time, including the time spent in the calls that are retried, from the
+// first call to Done or Duration, or from Clone.
 type TimingOutExponentialBackoff struct {
-	retry          int
-	totalTimeSoFar time.Duration
-	Timeout        time.Duration
-	TimeUnit       time.Duration
+	retry    int
+	start    time.Time
+	now      func() time.Time
+	Timeout  time.Duration
+	TimeUnit time.Duration
 }
 
 // NewTimingOutExponentialBackoff creates a new TimingOutExponentialBackoff
@@ -57,8 +60,8 @@ func NewTimingOutExponentialBackoff(timeout time.Duration) TimingOutExponentialB
 }
 
 // Done implements retry.Policy#Done() bool.
-func (b TimingOutExponentialBackoff) Done() bool {
-	return b.totalTimeSoFar == b.Timeout
+func (b *TimingOutExponentialBackoff) Done() bool {
+	return b.elapsed() >= b.Timeout
 }
 
 // Duration implements retry.Policy#Duration() time.Duration.
@@ -66,25 +69,47 @@ func (b *TimingOutExponentialBackoff) Duration() time.Duration {
 	duration := time.Duration(pow(2, b.retry)) * b.TimeUnit
 	b.retry++
 	// Cap duration so that the configured timeout is never exceeded:
-	if b.totalTimeSoFar+duration > b.Timeout {
-		duration = b.Timeout - b.totalTimeSoFar
+	if remaining := b.Timeout - b.elapsed(); duration > remaining {
+		duration = remaining
+	}
+	if duration < 0 {
+		duration = 0
 	}
-	b.totalTimeSoFar += duration
 	return duration
 }
 
 // Reset implements retry.Policy#Reset().
 func (b *TimingOutExponentialBackoff) Reset() {
 	b.retry = 0
-	b.totalTimeSoFar = 0
+	b.start = time.Time{}
 }
 
-// Clone implements retry.Policy#Clone() retry.Policy.
+// Clone implements retry.Policy#Clone() retry.Policy, the timeout of the
+// clone is measured from now.
 func (b TimingOutExponentialBackoff) Clone() Policy {
-	return &TimingOutExponentialBackoff{
+	clone := &TimingOutExponentialBackoff{
+		now:      b.now,
 		Timeout:  b.Timeout,
 		TimeUnit: b.TimeUnit,
 	}
+	clone.start = clone.currentTime()
+	return clone
+}
+
+// elapsed returns the wall time since the policy was first used
+func (b *TimingOutExponentialBackoff) elapsed() time.Duration {
+	now := b.currentTime()
+	if b.start.IsZero() {
+		b.start = now
+	}
+	return now.Sub(b.start)
+}
+
+func (b *TimingOutExponentialBackoff) currentTime() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
 }
 
 func pow(x, y int) int32 {
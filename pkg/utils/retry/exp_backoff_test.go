@@ -69,49 +69,81 @@ var _ = Describe("retry", func() {
 	})
 
 	Describe("TimingOutExponentialBackoff", func() {
+		var clock time.Time
+		now := func() time.Time { return clock }
+		// sleep advances the clock as if the caller slept for the given duration
+		sleep := func(d time.Duration) time.Duration {
+			clock = clock.Add(d)
+			return d
+		}
+
+		BeforeEach(func() {
+			clock = time.Date(2021, time.October, 1, 0, 0, 0, 0, time.UTC)
+		})
+
 		It("generates a sequence of exponentially increasing durations, capped by the provided timeout", func() {
 			policy := retry.TimingOutExponentialBackoff{
 				Timeout:  10 * time.Minute,
 				TimeUnit: time.Second,
 			}
+			policy.SetNow(now)
 			Expect(policy.Done()).To(BeFalse())
-			Expect(policy.Duration()).To(Equal(1 * time.Second))
+			Expect(sleep(policy.Duration())).To(Equal(1 * time.Second))
 			Expect(policy.Done()).To(BeFalse())
-			Expect(policy.Duration()).To(Equal(2 * time.Second))
+			Expect(sleep(policy.Duration())).To(Equal(2 * time.Second))
 			Expect(policy.Done()).To(BeFalse())
-			Expect(policy.Duration()).To(Equal(4 * time.Second))
+			Expect(sleep(policy.Duration())).To(Equal(4 * time.Second))
 			Expect(policy.Done()).To(BeFalse())
-			Expect(policy.Duration()).To(Equal(8 * time.Second))
+			Expect(sleep(policy.Duration())).To(Equal(8 * time.Second))
 			Expect(policy.Done()).To(BeFalse())
-			Expect(policy.Duration()).To(Equal(16 * time.Second))
+			Expect(sleep(policy.Duration())).To(Equal(16 * time.Second))
 			Expect(policy.Done()).To(BeFalse())
-			Expect(policy.Duration()).To(Equal(32 * time.Second))
+			Expect(sleep(policy.Duration())).To(Equal(32 * time.Second))
 			Expect(policy.Done()).To(BeFalse())
-			Expect(policy.Duration()).To(Equal(64 * time.Second))
+			Expect(sleep(policy.Duration())).To(Equal(64 * time.Second))
 			Expect(policy.Done()).To(BeFalse())
-			Expect(policy.Duration()).To(Equal(128 * time.Second))
+			Expect(sleep(policy.Duration())).To(Equal(128 * time.Second))
 			Expect(policy.Done()).To(BeFalse())
-			Expect(policy.Duration()).To(Equal(256 * time.Second))
+			Expect(sleep(policy.Duration())).To(Equal(256 * time.Second))
 			Expect(policy.Done()).To(BeFalse())
 			// Instead of being 512s in a normal exponential backoff, given the
 			// timeout is 10m (600s), and 511s have passed now (2**0 + 2**1 +
 			// ... 2**8 = 511), the next duration should be 600 - 511 = 89s:
-			Expect(policy.Duration()).To(Equal(89 * time.Second))
+			Expect(sleep(policy.Duration())).To(Equal(89 * time.Second))
 			Expect(policy.Done()).To(BeTrue())
 		})
 
+		It("counts the time spent between the durations towards the timeout", func() {
+			policy := retry.TimingOutExponentialBackoff{
+				Timeout:  time.Minute,
+				TimeUnit: time.Second,
+			}
+			policy.SetNow(now)
+			Expect(policy.Done()).To(BeFalse())
+			// a call that is retried takes 50s before failing
+			clock = clock.Add(50 * time.Second)
+			Expect(policy.Done()).To(BeFalse())
+			Expect(sleep(policy.Duration())).To(Equal(1 * time.Second))
+			Expect(policy.Done()).To(BeFalse())
+			clock = clock.Add(8 * time.Second)
+			Expect(sleep(policy.Duration())).To(Equal(1 * time.Second))
+			Expect(policy.Done()).To(BeTrue())
+			Expect(policy.Duration()).To(BeZero())
+		})
+
 		Describe("Reset", func() {
 			It("resets the current policy so it can be re-used", func() {
 				policy := retry.TimingOutExponentialBackoff{
 					Timeout:  1 * time.Second,
 					TimeUnit: time.Second,
 				}
+				policy.SetNow(now)
 				Expect(policy.Done()).To(BeFalse())
-				Expect(policy.Duration()).To(Equal(1 * time.Second))
+				Expect(sleep(policy.Duration())).To(Equal(1 * time.Second))
 				Expect(policy.Done()).To(BeTrue())
 				policy.Reset()
 				Expect(policy.Done()).To(BeFalse())
-				Expect(policy.Duration()).To(Equal(1 * time.Second))
+				Expect(sleep(policy.Duration())).To(Equal(1 * time.Second))
 				Expect(policy.Done()).To(BeTrue())
 			})
 		})
@@ -122,8 +154,9 @@ var _ = Describe("retry", func() {
 					Timeout:  1 * time.Second,
 					TimeUnit: time.Second,
 				}
+				policy.SetNow(now)
 				Expect(policy.Done()).To(BeFalse())
-				Expect(policy.Duration()).To(Equal(1 * time.Second))
+				Expect(sleep(policy.Duration())).To(Equal(1 * time.Second))
 				Expect(policy.Done()).To(BeTrue())
 				clone := policy.Clone()
 				addrPolicy := fmt.Sprintf("%p", &policy)
@@ -133,7 +166,7 @@ var _ = Describe("retry", func() {
 				Expect(policy.Done()).To(BeTrue())
 				// The cloned policy can be used:
 				Expect(clone.Done()).To(BeFalse())
-				Expect(clone.Duration()).To(Equal(1 * time.Second))
+				Expect(sleep(clone.Duration())).To(Equal(1 * time.Second))
 				Expect(clone.Done()).To(BeTrue())
 			})
 		})
@@ -0,0 +1,8 @@
+package retry
+
+import "time"
+
+// SetNow sets the clock the policy measures the elapsed time with
+func (b *TimingOutExponentialBackoff) SetNow(now func() time.Time) {
+	b.now = now
+}
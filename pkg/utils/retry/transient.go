@@ -0,0 +1,69 @@
+package retry
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// IsTransient returns true for errors that are likely to go away when the
+// operation is retried, such as API server errors right after the control
+// plane has been created, throttling, or IAM eventual consistency errors
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	err = errors.Cause(err)
+
+	switch {
+	case apierrors.IsInternalError(err),
+		apierrors.IsServerTimeout(err),
+		apierrors.IsTimeout(err),
+		apierrors.IsTooManyRequests(err),
+		apierrors.IsServiceUnavailable(err),
+		apierrors.IsUnexpectedServerError(err),
+		apierrors.IsConflict(err):
+		return true
+	case utilnet.IsConnectionRefused(err),
+		utilnet.IsConnectionReset(err),
+		utilnet.IsProbableEOF(err),
+		utilnet.IsTimeout(err):
+		return true
+	}
+
+	// the AWS SDK considers any error that is not an AWS error retryable
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+	if request.IsErrorRetryable(awsErr) || request.IsErrorThrottle(awsErr) {
+		return true
+	}
+	return isIAMEventualConsistencyError(awsErr)
+}
+
+// iamPropagationMessages are returned by other services while IAM roles and instance
+// profiles that were just created have not propagated to them yet; IAM's own NoSuchEntity
+// is not among them, as it is returned just the same for entities that don't exist
+var iamPropagationMessages = []string{
+	"cannot be assumed",
+	"could not be assumed",
+	"invalid iam instance profile",
+	"invalid iaminstanceprofile",
+}
+
+// isIAMEventualConsistencyError returns true for errors caused by IAM resources
+// that were just created not being visible to other services yet
+func isIAMEventualConsistencyError(awsErr awserr.Error) bool {
+	message := strings.ToLower(awsErr.Message())
+	for _, m := range iamPropagationMessages {
+		if strings.Contains(message, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package retry_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+)
+
+var _ = Describe("IsTransient", func() {
+	configMaps := schema.GroupResource{Resource: "configmaps"}
+
+	table.DescribeTable("classifies errors",
+		func(err error, transient bool) {
+			Expect(retry.IsTransient(err)).To(Equal(transient))
+		},
+		table.Entry("no error", nil, false),
+		table.Entry("generic error", fmt.Errorf("invalid configuration"), false),
+		table.Entry("API server unavailable", apierrors.NewServiceUnavailable("starting"), true),
+		table.Entry("API server internal error", apierrors.NewInternalError(fmt.Errorf("etcd")), true),
+		table.Entry("wrapped API server error", errors.Wrap(apierrors.NewGenericServerResponse(http.StatusBadGateway, "get", configMaps, "aws-auth", "", 0, true), "getting aws-auth"), true),
+		table.Entry("conflict", apierrors.NewConflict(configMaps, "aws-auth", fmt.Errorf("modified")), true),
+		table.Entry("not found", apierrors.NewNotFound(configMaps, "aws-auth"), false),
+		table.Entry("forbidden", apierrors.NewForbidden(configMaps, "aws-auth", fmt.Errorf("denied")), false),
+		table.Entry("AWS throttling", awserr.New("Throttling", "Rate exceeded", nil), true),
+		table.Entry("IAM eventual consistency", awserr.New("InvalidParameterException", "Role arn:aws:iam::123:role/r cannot be assumed", nil), true),
+		table.Entry("instance profile not propagated to EC2", awserr.New("InvalidParameterValue", "Value (eksctl-ng-profile) for parameter iamInstanceProfile.name is invalid. Invalid IAM Instance Profile name", nil), true),
+		table.Entry("IAM entity that does not exist", awserr.New("NoSuchEntity", "The role with name eksctl-test-role cannot be found.", nil), false),
+		table.Entry("AWS validation error", awserr.New("InvalidParameterException", "invalid version", nil), false),
+	)
+})
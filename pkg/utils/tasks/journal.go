@@ -0,0 +1,284 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+)
+
+// TaskState is the state of a task recorded in a Journal
+type TaskState string
+
+// Values for `TaskState`
+const (
+	TaskStateRunning   TaskState = "running"
+	TaskStateCompleted TaskState = "completed"
+	TaskStateFailed    TaskState = "failed"
+)
+
+// JournalEntry is the last recorded state of a task
+type JournalEntry struct {
+	// Task is the description of the task
+	Task      string    `json:"task"`
+	State     TaskState `json:"state"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// JournalStore persists a Journal
+type JournalStore interface {
+	// Load returns the persisted journal, or nil when there is none
+	Load() ([]byte, error)
+	Save(data []byte) error
+	Delete() error
+}
+
+// Rerunnable is implemented by tasks that are run again when resuming even if they
+// completed before, because later tasks depend on state they set up in memory
+type Rerunnable interface {
+	RerunOnResume() bool
+}
+
+// Journal records the state of each task of a TaskTree as it runs, so that an
+// interrupted operation can be resumed; tasks are identified by their position in
+// the tree, e.g. `1/0` for the first task of the second sub-tree, which is stable
+// as long as the tree is built from the same spec. Operations running several trees
+// give each of them a section with WithJournalSection, e.g. `nodegroups/1/0`.
+// The journal is written to every store, and loaded from the first store holding one
+type Journal struct {
+	// Spec is the spec the tasks were built from, so that resuming builds the same tasks
+	Spec  json.RawMessage          `json:"spec,omitempty"`
+	Tasks map[string]*JournalEntry `json:"tasks"`
+	// Stacks maps the names of the CloudFormation stacks created by the operation to their IDs
+	Stacks map[string]string `json:"stacks,omitempty"`
+
+	mutex    sync.Mutex
+	stores   []JournalStore
+	resuming bool
+}
+
+// NewJournal returns an empty journal of the given spec
+func NewJournal(spec interface{}, stores ...JournalStore) (*Journal, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding journal spec")
+	}
+	j := &Journal{
+		Spec:   data,
+		Tasks:  map[string]*JournalEntry{},
+		stores: stores,
+	}
+	return j, j.save()
+}
+
+// LoadJournal loads the journal of an operation to resume, it returns nil
+// when none of the stores holds a journal
+func LoadJournal(stores ...JournalStore) (*Journal, error) {
+	for _, store := range stores {
+		data, err := store.Load()
+		if err != nil {
+			return nil, errors.Wrap(err, "loading journal")
+		}
+		if data == nil {
+			continue
+		}
+		j := &Journal{}
+		if err := json.Unmarshal(data, j); err != nil {
+			return nil, errors.Wrap(err, "decoding journal")
+		}
+		if j.Tasks == nil {
+			j.Tasks = map[string]*JournalEntry{}
+		}
+		j.stores = stores
+		j.resuming = true
+		return j, nil
+	}
+	return nil, nil
+}
+
+// Resuming returns true when the journal was loaded to resume an operation
+func (j *Journal) Resuming() bool {
+	return j != nil && j.resuming
+}
+
+// DecodeSpec decodes the spec of the journal into spec
+func (j *Journal) DecodeSpec(spec interface{}) error {
+	return errors.Wrap(json.Unmarshal(j.Spec, spec), "decoding journal spec")
+}
+
+// State returns the last recorded state of the task with the given ID, or an empty
+// state when the task has not been started
+func (j *Journal) State(taskID string) TaskState {
+	if j == nil {
+		return ""
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if entry, ok := j.Tasks[taskID]; ok {
+		return entry.State
+	}
+	return ""
+}
+
+// RecordStack records that the operation created the stack named name, with the given ID
+func (j *Journal) RecordStack(name, id string) {
+	if j == nil {
+		return
+	}
+	j.mutex.Lock()
+	if j.Stacks == nil {
+		j.Stacks = map[string]string{}
+	}
+	j.Stacks[name] = id
+	j.mutex.Unlock()
+	if err := j.save(); err != nil {
+		logger.Warning("unable to record the creation of stack %q: %v", name, err)
+	}
+}
+
+// StackID returns the ID of the stack named name recorded by RecordStack, or an empty
+// string when the operation did not record the creation of such a stack
+func (j *Journal) StackID(name string) string {
+	if j == nil {
+		return ""
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.Stacks[name]
+}
+
+// Delete deletes the journal from all stores, once the operation has completed
+func (j *Journal) Delete() error {
+	if j == nil {
+		return nil
+	}
+	for _, store := range j.stores {
+		if err := store.Delete(); err != nil {
+			return errors.Wrap(err, "deleting journal")
+		}
+	}
+	return nil
+}
+
+// skip returns true when the task completed in the run being resumed
+func (j *Journal) skip(taskID string, task Task) bool {
+	if !j.Resuming() || j.State(taskID) != TaskStateCompleted {
+		return false
+	}
+	if r, ok := task.(Rerunnable); ok && r.RerunOnResume() {
+		return false
+	}
+	return true
+}
+
+func (j *Journal) record(taskID string, task string, state TaskState, err error) {
+	if j == nil {
+		return
+	}
+	entry := &JournalEntry{Task: task, State: state, UpdatedAt: time.Now().UTC()}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	j.mutex.Lock()
+	j.Tasks[taskID] = entry
+	j.mutex.Unlock()
+	if err := j.save(); err != nil {
+		logger.Warning("unable to record the state of task %q: %v", task, err)
+	}
+}
+
+// save writes the journal to all stores; only failures of the first store are returned,
+// as the other stores may not be available until some of the tasks have completed
+func (j *Journal) save() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	for i, store := range j.stores {
+		if err := store.Save(data); err != nil {
+			if i == 0 {
+				return err
+			}
+			logger.Debug("unable to save journal: %v", err)
+		}
+	}
+	return nil
+}
+
+type journalKey struct{}
+
+type taskIDKey struct{}
+
+// withTaskIndex returns a context identifying the task at index i of the tree run with ctx
+func withTaskIndex(ctx context.Context, i int) context.Context {
+	id := strconv.Itoa(i)
+	if parent := taskIDFromContext(ctx); parent != "" {
+		id = parent + "/" + id
+	}
+	return context.WithValue(ctx, taskIDKey{}, id)
+}
+
+// WithJournalSection returns a context recording the tasks of the tree run with it under
+// section, so that the tasks of different trees recorded in the same journal have distinct IDs
+func WithJournalSection(ctx context.Context, section string) context.Context {
+	return context.WithValue(ctx, taskIDKey{}, section)
+}
+
+func taskIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(taskIDKey{}).(string)
+	return id
+}
+
+// WithJournal returns a context recording the state of the tasks run with it in journal
+func WithJournal(ctx context.Context, journal *Journal) context.Context {
+	return context.WithValue(ctx, journalKey{}, journal)
+}
+
+// JournalFromContext returns the journal of the context, or nil
+func JournalFromContext(ctx context.Context) *Journal {
+	j, _ := ctx.Value(journalKey{}).(*Journal)
+	return j
+}
+
+// FileJournalStore stores a journal in a local file
+type FileJournalStore struct {
+	Path string
+}
+
+// Load implements JournalStore
+func (s *FileJournalStore) Load() ([]byte, error) {
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Save implements JournalStore
+func (s *FileJournalStore) Save(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return err
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+// Delete implements JournalStore
+func (s *FileJournalStore) Delete() error {
+	if err := os.Remove(s.Path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
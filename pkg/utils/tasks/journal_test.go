@@ -0,0 +1,166 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type rerunnableTask struct {
+	waitingTask
+}
+
+func (t *rerunnableTask) RerunOnResume() bool { return true }
+
+var _ = Describe("Journal", func() {
+	var (
+		dir     string
+		store   *FileJournalStore
+		started []string
+	)
+
+	newTasks := func(failing string) *TaskTree {
+		started = nil
+		newTask := func(name string) waitingTask {
+			return waitingTask{
+				info: name,
+				call: func(_ context.Context) error {
+					started = append(started, name)
+					if name == failing {
+						return fmt.Errorf("%s failed", name)
+					}
+					return nil
+				},
+			}
+		}
+		t1, t2, t3 := newTask("t1"), newTask("t2"), newTask("t3")
+		taskTree := &TaskTree{Parallel: false}
+		taskTree.Append(&t1, &rerunnableTask{t2}, &t3)
+		return taskTree
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "journal")
+		Expect(err).NotTo(HaveOccurred())
+		store = &FileJournalStore{Path: filepath.Join(dir, "journals", "test.json")}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("records the state of tasks and skips completed tasks when resuming", func() {
+		journal, err := NewJournal(map[string]string{"name": "test"}, store)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(journal.Resuming()).To(BeFalse())
+
+		errs := newTasks("t3").DoAllSyncWithContext(WithJournal(context.Background(), journal))
+		Expect(errs).To(HaveLen(1))
+		Expect(started).To(Equal([]string{"t1", "t2", "t3"}))
+
+		journal, err = LoadJournal(store)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(journal.Resuming()).To(BeTrue())
+		Expect(journal.State("0")).To(Equal(TaskStateCompleted))
+		Expect(journal.State("2")).To(Equal(TaskStateFailed))
+		Expect(journal.Tasks["2"].Task).To(Equal("t3"))
+		Expect(journal.Tasks["2"].Error).To(Equal("t3 failed"))
+		spec := map[string]string{}
+		Expect(journal.DecodeSpec(&spec)).To(Succeed())
+		Expect(spec).To(Equal(map[string]string{"name": "test"}))
+
+		errs = newTasks("").DoAllSyncWithContext(WithJournal(context.Background(), journal))
+		Expect(errs).To(BeEmpty())
+		Expect(started).To(Equal([]string{"t2", "t3"}))
+		Expect(journal.State("2")).To(Equal(TaskStateCompleted))
+
+		Expect(journal.Delete()).To(Succeed())
+		journal, err = LoadJournal(store)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(journal).To(BeNil())
+	})
+
+	It("tells apart tasks with the same description", func() {
+		journal, err := NewJournal(map[string]string{"name": "test"}, store)
+		Expect(err).NotTo(HaveOccurred())
+
+		var resuming bool
+		newTree := func() *TaskTree {
+			started = nil
+			newTask := func(fail bool) *waitingTask {
+				return &waitingTask{
+					info: "create addon",
+					call: func(_ context.Context) error {
+						started = append(started, "create addon")
+						if fail && !resuming {
+							return fmt.Errorf("create addon failed")
+						}
+						return nil
+					},
+				}
+			}
+			taskTree := &TaskTree{Parallel: false}
+			taskTree.Append(newTask(false), &TaskTree{
+				Tasks:     []Task{newTask(true)},
+				IsSubTask: true,
+			})
+			return taskTree
+		}
+
+		Expect(newTree().DoAllSyncWithContext(WithJournal(context.Background(), journal))).To(HaveLen(1))
+		Expect(journal.State("0")).To(Equal(TaskStateCompleted))
+		Expect(journal.State("1/0")).To(Equal(TaskStateFailed))
+
+		resuming = true
+		journal, err = LoadJournal(store)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(newTree().DoAllSyncWithContext(WithJournal(context.Background(), journal))).To(BeEmpty())
+		Expect(started).To(HaveLen(1))
+		Expect(journal.State("1/0")).To(Equal(TaskStateCompleted))
+	})
+
+	It("tells apart the tasks of trees run in different sections", func() {
+		journal, err := NewJournal(map[string]string{"name": "test"}, store)
+		Expect(err).NotTo(HaveOccurred())
+
+		var resuming bool
+		run := func() []error {
+			started = nil
+			newTree := func(name string, fail bool) *TaskTree {
+				taskTree := &TaskTree{Parallel: false}
+				taskTree.Append(&waitingTask{
+					info: name,
+					call: func(_ context.Context) error {
+						started = append(started, name)
+						if fail && !resuming {
+							return fmt.Errorf("%s failed", name)
+						}
+						return nil
+					},
+				})
+				return taskTree
+			}
+			ctx := WithJournal(context.Background(), journal)
+			if errs := newTree("cluster", false).DoAllSyncWithContext(WithJournalSection(ctx, "cluster")); len(errs) > 0 {
+				return errs
+			}
+			return newTree("device plugin", true).DoAllSyncWithContext(WithJournalSection(ctx, "nodegroups"))
+		}
+
+		Expect(run()).To(HaveLen(1))
+		Expect(journal.State("cluster/0")).To(Equal(TaskStateCompleted))
+		Expect(journal.State("nodegroups/0")).To(Equal(TaskStateFailed))
+
+		resuming = true
+		journal, err = LoadJournal(store)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(run()).To(BeEmpty())
+		Expect(started).To(Equal([]string{"device plugin"}))
+		Expect(journal.State("nodegroups/0")).To(Equal(TaskStateCompleted))
+	})
+})
@@ -0,0 +1,80 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"github.com/kris-nova/logger"
+
+	"github.com/weaveworks/eksctl/pkg/utils/progress"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+)
+
+// DefaultRetryTimeout is the default time spent retrying a task that failed with a retryable error
+const DefaultRetryTimeout = 2 * time.Minute
+
+// Retryable is implemented by tasks that can be retried when they fail with a transient error
+type Retryable interface {
+	// RetryPolicy returns the policy used to retry the task, or nil to use the default policy
+	RetryPolicy() retry.Policy
+	// IsRetryable returns true when the error returned by the task is worth retrying
+	IsRetryable(error) bool
+}
+
+var defaultRetryPolicy retry.Policy = &retry.TimingOutExponentialBackoff{
+	Timeout:  DefaultRetryTimeout,
+	TimeUnit: time.Second,
+}
+
+// SetDefaultRetryPolicy sets the policy used for retryable tasks that do not declare their own
+func SetDefaultRetryPolicy(policy retry.Policy) {
+	defaultRetryPolicy = policy
+}
+
+// TransientErrorRetries can be embedded in a task to retry it on transient AWS and Kubernetes
+// errors, Policy is optional and the default policy is used when it is not set
+type TransientErrorRetries struct {
+	Policy retry.Policy
+}
+
+// RetryPolicy implements Retryable
+func (r TransientErrorRetries) RetryPolicy() retry.Policy { return r.Policy }
+
+// IsRetryable implements Retryable
+func (r TransientErrorRetries) IsRetryable(err error) bool { return retry.IsTransient(err) }
+
+// DoWithRetries calls fn until it succeeds, it fails with an error that is not transient,
+// the default retry policy gives up or ctx is cancelled; it is meant for operations that
+// are not run as tasks
+func DoWithRetries(ctx context.Context, desc string, fn func() error) error {
+	return doWithRetries(ctx, desc, TransientErrorRetries{}, fn)
+}
+
+func doWithRetries(ctx context.Context, desc string, r Retryable, fn func() error) error {
+	policy := r.RetryPolicy()
+	if policy == nil {
+		policy = defaultRetryPolicy
+	}
+	policy = policy.Clone()
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !r.IsRetryable(err) || policy.Done() {
+			return err
+		}
+		delay := policy.Duration()
+		logger.Warning("%s failed with a transient error (attempt %d), retrying in %s: %v", desc, attempt, delay, err)
+		progress.Emit(progress.Event{
+			Type:            progress.Retry,
+			Task:            desc,
+			Attempt:         attempt,
+			DurationSeconds: delay.Seconds(),
+		}.WithError(err))
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
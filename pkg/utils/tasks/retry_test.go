@@ -0,0 +1,87 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+)
+
+type retryingTask struct {
+	waitingTask
+	TransientErrorRetries
+}
+
+var _ = Describe("Retrying tasks", func() {
+	var (
+		attempts int
+		policy   retry.Policy
+	)
+
+	newTask := func(errs ...error) *retryingTask {
+		attempts = 0
+		return &retryingTask{
+			waitingTask: waitingTask{
+				info: "t1",
+				call: func(_ context.Context) error {
+					attempts++
+					if attempts <= len(errs) {
+						return errs[attempts-1]
+					}
+					return nil
+				},
+			},
+			TransientErrorRetries: TransientErrorRetries{Policy: policy},
+		}
+	}
+
+	serverError := apierrors.NewGenericServerResponse(http.StatusServiceUnavailable, "get", schema.GroupResource{Resource: "configmaps"}, "aws-auth", "", 0, true)
+
+	BeforeEach(func() {
+		policy = &retry.ConstantBackoff{MaxRetries: 2}
+	})
+
+	It("retries tasks failing with transient errors", func() {
+		tasks := &TaskTree{Parallel: false}
+		tasks.Append(newTask(serverError, serverError))
+		Expect(tasks.DoAllSync()).To(BeEmpty())
+		Expect(attempts).To(Equal(3))
+	})
+
+	It("gives up once the retry policy is done", func() {
+		tasks := &TaskTree{Parallel: false}
+		tasks.Append(newTask(serverError, serverError, serverError))
+		errs := tasks.DoAllSync()
+		Expect(errs).To(HaveLen(1))
+		Expect(apierrors.IsServiceUnavailable(errs[0])).To(BeTrue())
+		Expect(attempts).To(Equal(3))
+	})
+
+	It("does not retry errors that are not transient", func() {
+		tasks := &TaskTree{Parallel: false}
+		tasks.Append(newTask(fmt.Errorf("invalid configuration")))
+		errs := tasks.DoAllSync()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0]).To(MatchError("invalid configuration"))
+		Expect(attempts).To(Equal(1))
+	})
+
+	It("uses the default retry policy when the task does not declare one", func() {
+		defer SetDefaultRetryPolicy(defaultRetryPolicy)
+		SetDefaultRetryPolicy(&retry.ConstantBackoff{MaxRetries: 1})
+		policy = nil
+
+		task := newTask(serverError, serverError)
+		err := DoWithRetries(context.Background(), "t1", func() error {
+			return task.call(context.Background())
+		})
+		Expect(apierrors.IsServiceUnavailable(err)).To(BeTrue())
+		Expect(attempts).To(Equal(2))
+	})
+})
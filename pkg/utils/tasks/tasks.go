@@ -1,17 +1,22 @@
 package tasks
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/kris-nova/logger"
+
+	"github.com/weaveworks/eksctl/pkg/utils/progress"
 )
 
-// Task is a common interface for the stack manager tasks
+// Task is a common interface for the stack manager tasks, the context passed
+// to Do is cancelled when the tasks are interrupted
 type Task interface {
 	Describe() string
-	Do(chan error) error
+	Do(context.Context, chan error) error
 }
 
 type GenericTask struct {
@@ -22,7 +27,7 @@ type GenericTask struct {
 func (t *GenericTask) Describe() string {
 	return t.Description
 }
-func (t *GenericTask) Do(errCh chan error) error {
+func (t *GenericTask) Do(_ context.Context, errCh chan error) error {
 	close(errCh)
 	return t.Doer()
 }
@@ -36,17 +41,22 @@ type SynchronousTask struct {
 	SynchronousTaskIface
 }
 
-func (t SynchronousTask) Do(errCh chan error) error {
+func (t SynchronousTask) Do(_ context.Context, errCh chan error) error {
 	defer close(errCh)
 	return t.SynchronousTaskIface.Do()
 }
 
-// TaskTree wraps a set of tasks
+// TaskTree wraps a set of tasks; tasks of a parallel tree may depend on other
+// tasks of the tree, which makes the tree a graph of tasks
 type TaskTree struct {
 	Tasks     []Task
 	Parallel  bool
 	PlanMode  bool
 	IsSubTask bool
+	// MaxParallel limits the number of tasks of a parallel tree running at the same time, when set
+	MaxParallel int
+
+	dependencies map[Task][]Task
 }
 
 // Append new tasks to the set
@@ -54,6 +64,40 @@ func (t *TaskTree) Append(newTasks ...Task) {
 	t.Tasks = append(t.Tasks, newTasks...)
 }
 
+// AppendWithDependencies appends a task that is only started once all of the tasks
+// it depends on have completed successfully; dependencies must have been appended
+// to the same tree before, and are only taken into account when the tree is parallel
+func (t *TaskTree) AppendWithDependencies(task Task, dependsOn ...Task) error {
+	for _, dependency := range dependsOn {
+		if !t.contains(dependency) {
+			return fmt.Errorf("task %q depends on task %q, which is not part of the task tree", task.Describe(), dependency.Describe())
+		}
+	}
+	t.Append(task)
+	if len(dependsOn) == 0 {
+		return nil
+	}
+	if t.dependencies == nil {
+		t.dependencies = map[Task][]Task{}
+	}
+	t.dependencies[task] = append(t.dependencies[task], dependsOn...)
+	return nil
+}
+
+func (t *TaskTree) contains(task Task) bool {
+	for _, existing := range t.Tasks {
+		if existing == task {
+			return true
+		}
+	}
+	return false
+}
+
+// Dependencies returns the tasks that task depends on
+func (t *TaskTree) Dependencies(task Task) []Task {
+	return t.dependencies[task]
+}
+
 // Len returns number of tasks in the set
 func (t *TaskTree) Len() int {
 	if t == nil {
@@ -91,6 +135,12 @@ func (t *TaskTree) Describe() string {
 		mode = "parallel"
 	}
 	noun += "s"
+	if t.Parallel && t.MaxParallel > 0 && t.MaxParallel < count {
+		noun += fmt.Sprintf(" (at most %d at a time)", t.MaxParallel)
+	}
+	if t.Parallel && len(t.dependencies) > 0 {
+		descriptions = t.describeDependencies(descriptions)
+	}
 	head := fmt.Sprintf("\n%d %s %s: { ", count, mode, noun)
 	var tail string
 	if t.IsSubTask {
@@ -131,10 +181,34 @@ func (t *TaskTree) Describe() string {
 	return msg + "\n"
 }
 
+// describeDependencies numbers the descriptions of the tasks, and adds the numbers
+// of the tasks that each task depends on
+func (t *TaskTree) describeDependencies(descriptions []string) []string {
+	numbers := map[Task]int{}
+	for i, task := range t.Tasks {
+		numbers[task] = i + 1
+	}
+	var labelled []string
+	for i, d := range descriptions {
+		label := fmt.Sprintf("#%d", i+1)
+		var after []string
+		for _, dependency := range t.dependencies[t.Tasks[i]] {
+			after = append(after, fmt.Sprintf("#%d", numbers[dependency]))
+		}
+		if len(after) > 0 {
+			label += fmt.Sprintf(" (after %s)", strings.Join(after, ", "))
+		}
+		// keep the leading line break and indentation of sub-task descriptions
+		trimmed := strings.TrimLeft(d, "\n ")
+		labelled = append(labelled, d[:len(d)-len(trimmed)]+label+": "+trimmed)
+	}
+	return labelled
+}
+
 // Do will run through the set in the background, it may return an error immediately,
 // or eventually write to the errs channel; it will close the channel once all tasks
 // are completed
-func (t *TaskTree) Do(allErrs chan error) error {
+func (t *TaskTree) Do(ctx context.Context, allErrs chan error) error {
 	if t.Len() == 0 || t.PlanMode {
 		logger.Debug("no actual tasks")
 		close(allErrs)
@@ -144,9 +218,9 @@ func (t *TaskTree) Do(allErrs chan error) error {
 	errs := make(chan error)
 
 	if t.Parallel {
-		go doParallelTasks(errs, t.Tasks)
+		go t.doParallelTasks(ctx, errs)
 	} else {
-		go doSequentialTasks(errs, t.Tasks)
+		go doSequentialTasks(ctx, errs, t.Tasks)
 	}
 
 	go func() {
@@ -162,6 +236,13 @@ func (t *TaskTree) Do(allErrs chan error) error {
 // DoAllSync will run through the set in the foregounds and return all the errors
 // in a slice
 func (t *TaskTree) DoAllSync() []error {
+	return t.DoAllSyncWithContext(context.Background())
+}
+
+// DoAllSyncWithContext is like DoAllSync, but stops starting new tasks once ctx is
+// cancelled; tasks which are running when ctx is cancelled receive the cancellation
+// through their context
+func (t *TaskTree) DoAllSyncWithContext(ctx context.Context) []error {
 	if t.Len() == 0 || t.PlanMode {
 		logger.Debug("no actual tasks")
 		return nil
@@ -170,9 +251,9 @@ func (t *TaskTree) DoAllSync() []error {
 	errs := make(chan error)
 
 	if t.Parallel {
-		go doParallelTasks(errs, t.Tasks)
+		go t.doParallelTasks(ctx, errs)
 	} else {
-		go doSequentialTasks(errs, t.Tasks)
+		go doSequentialTasks(ctx, errs, t.Tasks)
 	}
 
 	allErrs := []error{}
@@ -182,15 +263,56 @@ func (t *TaskTree) DoAllSync() []error {
 	return allErrs
 }
 
-func doSingleTask(allErrs chan error, task Task) bool {
+func doSingleTask(ctx context.Context, allErrs chan error, task Task) bool {
 	desc := task.Describe()
-	logger.Debug("started task: %s", desc)
-	errs := make(chan error)
-	if err := task.Do(errs); err != nil {
-		allErrs <- err
+	if ctx.Err() != nil {
+		allErrs <- &InterruptedError{Task: desc, Err: ctx.Err()}
 		return false
 	}
-	if err := <-errs; err != nil {
+	// sub-trees record the state of each of their tasks
+	journal := JournalFromContext(ctx)
+	if _, ok := task.(*TaskTree); ok {
+		journal = nil
+	}
+	taskID := taskIDFromContext(ctx)
+	if journal.skip(taskID, task) {
+		logger.Info("skipping task: %s (completed before)", desc)
+		progress.Emit(progress.Event{Type: progress.TaskSkipped, Task: desc})
+		return true
+	}
+	logger.Debug("started task: %s", desc)
+	journal.record(taskID, desc, TaskStateRunning, nil)
+	progress.Emit(progress.Event{Type: progress.TaskStarted, Task: desc})
+	startTime := time.Now()
+	finished := func(err error) {
+		state := TaskStateCompleted
+		if err != nil {
+			state = TaskStateFailed
+		}
+		journal.record(taskID, desc, state, err)
+		progress.Emit(progress.Event{
+			Type:            progress.TaskFinished,
+			Task:            desc,
+			Status:          string(state),
+			DurationSeconds: time.Since(startTime).Seconds(),
+		}.WithError(err))
+	}
+
+	do := func() error {
+		errs := make(chan error)
+		if err := task.Do(ctx, errs); err != nil {
+			return err
+		}
+		return <-errs
+	}
+	var err error
+	if r, ok := task.(Retryable); ok {
+		err = doWithRetries(ctx, desc, r, do)
+	} else {
+		err = do()
+	}
+	finished(err)
+	if err != nil {
 		allErrs <- err
 		return false
 	}
@@ -198,25 +320,68 @@ func doSingleTask(allErrs chan error, task Task) bool {
 	return true
 }
 
-func doParallelTasks(allErrs chan error, tasks []Task) {
+// doParallelTasks starts every task once the tasks it depends on have completed
+// successfully, running at most MaxParallel tasks at the same time when it is set;
+// tasks depending on a failed task are not started
+func (t *TaskTree) doParallelTasks(ctx context.Context, allErrs chan error) {
+	type result struct {
+		done chan struct{}
+		ok   bool
+	}
+	results := make([]*result, len(t.Tasks))
+	indices := map[Task]int{}
+	for i := len(t.Tasks) - 1; i >= 0; i-- {
+		results[i] = &result{done: make(chan struct{})}
+		indices[t.Tasks[i]] = i
+	}
+
+	var slots chan struct{}
+	if t.MaxParallel > 0 {
+		slots = make(chan struct{}, t.MaxParallel)
+	}
+
 	wg := &sync.WaitGroup{}
-	wg.Add(len(tasks))
-	for t := range tasks {
-		go func(t int) {
+	wg.Add(len(t.Tasks))
+	for i, task := range t.Tasks {
+		go func(i int, task Task) {
 			defer wg.Done()
-			if ok := doSingleTask(allErrs, tasks[t]); !ok {
-				logger.Debug("failed task: %s (will continue until other parallel tasks are completed)", tasks[t].Describe())
+			r := results[i]
+			defer close(r.done)
+
+			for _, dependency := range t.dependencies[task] {
+				j, ok := indices[dependency]
+				if !ok || j >= i {
+					continue
+				}
+				d := results[j]
+				<-d.done
+				if !d.ok {
+					logger.Debug("not starting task: %s (task %s has failed)", task.Describe(), dependency.Describe())
+					return
+				}
+			}
+
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-ctx.Done():
+				}
+			}
+
+			if r.ok = doSingleTask(withTaskIndex(ctx, i), allErrs, task); !r.ok {
+				logger.Debug("failed task: %s (will continue until other parallel tasks are completed)", task.Describe())
 			}
-		}(t)
+		}(i, task)
 	}
-	logger.Debug("waiting for %d parallel tasks to complete", len(tasks))
+	logger.Debug("waiting for %d parallel tasks to complete", len(t.Tasks))
 	wg.Wait()
 	close(allErrs)
 }
 
-func doSequentialTasks(allErrs chan error, tasks []Task) {
+func doSequentialTasks(ctx context.Context, allErrs chan error, tasks []Task) {
 	for t := range tasks {
-		if ok := doSingleTask(allErrs, tasks[t]); !ok {
+		if ok := doSingleTask(withTaskIndex(ctx, t), allErrs, tasks[t]); !ok {
 			logger.Debug("failed task: %s (will not run other sequential tasks)", tasks[t].Describe())
 			break
 		}
@@ -229,8 +394,8 @@ type TaskWithoutParams struct {
 	Call func(chan error) error
 }
 
-func (t *TaskWithoutParams) Describe() string         { return t.Info }
-func (t *TaskWithoutParams) Do(errs chan error) error { return t.Call(errs) }
+func (t *TaskWithoutParams) Describe() string                            { return t.Info }
+func (t *TaskWithoutParams) Do(_ context.Context, errs chan error) error { return t.Call(errs) }
 
 type TaskWithNameParam struct {
 	Info string
@@ -238,5 +403,22 @@ type TaskWithNameParam struct {
 	Call func(chan error, string) error
 }
 
-func (t *TaskWithNameParam) Describe() string         { return t.Info }
-func (t *TaskWithNameParam) Do(errs chan error) error { return t.Call(errs, t.Name) }
+func (t *TaskWithNameParam) Describe() string { return t.Info }
+func (t *TaskWithNameParam) Do(_ context.Context, errs chan error) error {
+	return t.Call(errs, t.Name)
+}
+
+// InterruptedError is returned for a task which was not started because
+// the context of the tasks was cancelled
+type InterruptedError struct {
+	Task string
+	Err  error
+}
+
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("task %q was not started: %v", e.Task, e.Err)
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return e.Err
+}
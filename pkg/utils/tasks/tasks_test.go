@@ -1,13 +1,20 @@
 package tasks
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/utils/progress"
 )
 
 var _ = Describe("TaskTree", func() {
@@ -621,5 +628,191 @@ var _ = Describe("TaskTree", func() {
 				Expect(errs[0].Error()).To(Equal("t1.3 always fails"))
 			}
 		})
+
+		It("should stop starting tasks once the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var started []string
+			tasks := &TaskTree{Parallel: false}
+			tasks.Append(&waitingTask{
+				info: "t1",
+				call: func(ctx context.Context) error {
+					started = append(started, "t1")
+					cancel()
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			})
+			tasks.Append(&waitingTask{
+				info: "t2",
+				call: func(ctx context.Context) error {
+					started = append(started, "t2")
+					return nil
+				},
+			})
+
+			errs := tasks.DoAllSyncWithContext(ctx)
+			Expect(started).To(Equal([]string{"t1"}))
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0]).To(MatchError(context.Canceled))
+
+			errs = tasks.DoAllSyncWithContext(ctx)
+			Expect(started).To(Equal([]string{"t1"}))
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0]).To(MatchError(`task "t1" was not started: context canceled`))
+			Expect(errors.Is(errs[0], context.Canceled)).To(BeTrue())
+		})
+
+		It("should start tasks once their dependencies have completed", func() {
+			var (
+				mutex    sync.Mutex
+				finished []string
+			)
+			record := func(name string, delay time.Duration) *waitingTask {
+				return &waitingTask{
+					info: name,
+					call: func(_ context.Context) error {
+						time.Sleep(delay)
+						mutex.Lock()
+						defer mutex.Unlock()
+						finished = append(finished, name)
+						return nil
+					},
+				}
+			}
+
+			t1 := record("t1", 100*time.Millisecond)
+			t2 := record("t2", 0)
+			t3 := record("t3", 0)
+			t4 := record("t4", 0)
+			tasks := &TaskTree{Parallel: true}
+			tasks.Append(t1)
+			Expect(tasks.AppendWithDependencies(t2, t1)).To(Succeed())
+			tasks.Append(t3)
+			Expect(tasks.AppendWithDependencies(t4, t2, t3)).To(Succeed())
+
+			Expect(tasks.Dependencies(t4)).To(Equal([]Task{t2, t3}))
+			Expect(tasks.Describe()).To(Equal(`
+4 parallel tasks: { #1: t1, #2 (after #1): t2, #3: t3, #4 (after #2, #3): t4 
+}
+`))
+
+			Expect(tasks.DoAllSync()).To(BeEmpty())
+			Expect(finished).To(Equal([]string{"t3", "t1", "t2", "t4"}))
+		})
+
+		It("should not start tasks depending on a failed task", func() {
+			var started []string
+			t1 := &waitingTask{
+				info: "t1",
+				call: func(_ context.Context) error {
+					return fmt.Errorf("t1 always fails")
+				},
+			}
+			t2 := &waitingTask{
+				info: "t2",
+				call: func(_ context.Context) error {
+					started = append(started, "t2")
+					return nil
+				},
+			}
+			tasks := &TaskTree{Parallel: true}
+			tasks.Append(t1)
+			Expect(tasks.AppendWithDependencies(t2, t1)).To(Succeed())
+
+			errs := tasks.DoAllSync()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0]).To(MatchError("t1 always fails"))
+			Expect(started).To(BeEmpty())
+		})
+
+		It("should reject dependencies that are not part of the tree", func() {
+			t1 := &waitingTask{info: "t1", call: func(_ context.Context) error { return nil }}
+			t2 := &waitingTask{info: "t2", call: func(_ context.Context) error { return nil }}
+			tasks := &TaskTree{Parallel: true}
+
+			Expect(tasks.AppendWithDependencies(t2, t1)).To(MatchError(`task "t2" depends on task "t1", which is not part of the task tree`))
+			Expect(tasks.Len()).To(Equal(0))
+
+			tasks.Append(t1)
+			Expect(tasks.AppendWithDependencies(t2, t1)).To(Succeed())
+			Expect(tasks.Dependencies(t2)).To(Equal([]Task{t1}))
+		})
+
+		It("should report the progress of tasks", func() {
+			buf := new(bytes.Buffer)
+			progress.Start(buf, nil)
+			defer func() {
+				Expect(progress.Close()).To(Succeed())
+			}()
+
+			tasks := &TaskTree{Parallel: false}
+			tasks.Append(&waitingTask{
+				info: "t1",
+				call: func(_ context.Context) error { return nil },
+			})
+			tasks.Append(&waitingTask{
+				info: "t2",
+				call: func(_ context.Context) error { return fmt.Errorf("t2 always fails") },
+			})
+			Expect(tasks.DoAllSync()).To(HaveLen(1))
+
+			var events []progress.Event
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				var e progress.Event
+				Expect(json.Unmarshal([]byte(line), &e)).To(Succeed())
+				events = append(events, e)
+			}
+			Expect(events).To(HaveLen(4))
+			Expect(events[0].Type).To(Equal(progress.TaskStarted))
+			Expect(events[0].Task).To(Equal("t1"))
+			Expect(events[1].Type).To(Equal(progress.TaskFinished))
+			Expect(events[1].Status).To(Equal("completed"))
+			Expect(events[3].Task).To(Equal("t2"))
+			Expect(events[3].Status).To(Equal("failed"))
+			Expect(events[3].Error).To(Equal("t2 always fails"))
+		})
+
+		It("should run at most MaxParallel tasks at the same time", func() {
+			var running, maxRunning int32
+			tasks := &TaskTree{Parallel: true, MaxParallel: 2}
+			for i := 0; i < 6; i++ {
+				tasks.Append(&waitingTask{
+					info: fmt.Sprintf("t%d", i),
+					call: func(_ context.Context) error {
+						n := atomic.AddInt32(&running, 1)
+						defer atomic.AddInt32(&running, -1)
+						for {
+							m := atomic.LoadInt32(&maxRunning)
+							if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+								break
+							}
+						}
+						time.Sleep(20 * time.Millisecond)
+						return nil
+					},
+				})
+			}
+
+			Expect(tasks.Describe()).To(HavePrefix("\n6 parallel tasks (at most 2 at a time): {"))
+			Expect(tasks.DoAllSync()).To(BeEmpty())
+			Expect(maxRunning).To(Equal(int32(2)))
+		})
 	})
 })
+
+type waitingTask struct {
+	info string
+	call func(context.Context) error
+}
+
+func (t *waitingTask) Describe() string { return t.info }
+
+func (t *waitingTask) Do(ctx context.Context, errs chan error) error {
+	go func() {
+		defer close(errs)
+		errs <- t.call(ctx)
+	}()
+	return nil
+}
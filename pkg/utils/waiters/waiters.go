@@ -10,33 +10,52 @@ import (
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/utils/progress"
 )
 
 // Wait for something with a name to reach status that is expressed by acceptors using newRequest
-// until we hit waitTimeout, on unexpected status troubleshoot will be called with the desired
-// status as an argument, so that it can find what might have gone wrong
-func Wait(name, msg string, acceptors []request.WaiterAcceptor, newRequest func() *request.Request, waitTimeout time.Duration, troubleshoot func(string) error) error {
+// until we hit waitTimeout or ctx is cancelled, on unexpected status troubleshoot will be called
+// with the desired status as an argument, so that it can find what might have gone wrong
+func Wait(ctx context.Context, name, msg string, acceptors []request.WaiterAcceptor, newRequest func() *request.Request, waitTimeout time.Duration, troubleshoot func(string) error) error {
 	desiredStatus := fmt.Sprintf("%v", acceptors[0].Expected)
 	name = strings.Join([]string{"wait", name, desiredStatus}, "_")
 
-	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout)
 	defer cancel()
 	startTime := time.Now()
 	w := makeWaiter(ctx, name, msg, acceptors, newRequest)
 	logger.Debug("start %s", msg)
 	if waitErr := w.WaitWithContext(ctx); waitErr != nil {
+		err := errors.Wrap(waitErr, msg)
 		if troubleshoot != nil {
 			if wrappedErr := troubleshoot(desiredStatus); wrappedErr != nil {
-				return wrappedErr
+				err = wrappedErr
 			}
 		}
-		return errors.Wrap(waitErr, msg)
+		emitFinished(name, startTime, err)
+		return err
 	}
 	logger.Debug("done after %s of %s", time.Since(startTime), msg)
+	emitFinished(name, startTime, nil)
 	return nil
 }
 
+func emitFinished(name string, startTime time.Time, err error) {
+	status := "succeeded"
+	if err != nil {
+		status = "failed"
+	}
+	progress.Emit(progress.Event{
+		Type:            progress.WaiterFinished,
+		Waiter:          name,
+		Status:          status,
+		DurationSeconds: time.Since(startTime).Seconds(),
+	}.WithError(err))
+}
+
 func makeWaiter(ctx context.Context, name, msg string, acceptors []request.WaiterAcceptor, newRequest func() *request.Request) request.Waiter {
+	attempt := 0
 	return request.Waiter{
 		Name:        name,
 		MaxAttempts: 1024, // we use context deadline instead
@@ -44,6 +63,8 @@ func makeWaiter(ctx context.Context, name, msg string, acceptors []request.Waite
 		Acceptors:   acceptors,
 		NewRequest: func(_ []request.Option) (*request.Request, error) {
 			logger.Info(msg)
+			attempt++
+			progress.Emit(progress.Event{Type: progress.WaiterPoll, Waiter: name, Attempt: attempt})
 			req := newRequest()
 			req.SetContext(ctx)
 			return req, nil